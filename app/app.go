@@ -0,0 +1,301 @@
+// Package app composes servers and background workers into a single Runner with
+// ordered startup, OS signal handling, coordinated graceful shutdown with a deadline
+// and aggregated error reporting, replacing the select-on-Notify boilerplate of services.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/signals"
+)
+
+const _defaultShutdownTimeout = 30 * time.Second
+
+// ErrAlreadyRunning is returned by Run when the Runner was already run.
+var ErrAlreadyRunning = errors.New("app - runner already run")
+
+// Component is a unit of the service lifecycle, such as httpserver.Server,
+// grpcserver.Server, the kafka and rabbitmq servers, scheduler.Scheduler or workerpool.Pool.
+type Component interface {
+	Start()
+	Shutdown() error
+}
+
+// Notifier is implemented by components reporting runtime errors, e.g. a server
+// failing to listen. Nil values and a closed channel are ignored, and errors with a
+// Temporary method reporting true, such as the reconnection events of the rabbitmq
+// client and server, are logged as warnings.
+type Notifier interface {
+	Notify() <-chan error
+}
+
+type component struct {
+	name        string
+	c           Component
+	nonCritical bool
+}
+
+// ComponentOption configures a single component.
+type ComponentOption func(*component)
+
+// NonCritical logs the component's Notify errors instead of shutting the service down,
+// e.g. for scheduler job failures.
+func NonCritical() ComponentOption {
+	return func(c *component) {
+		c.nonCritical = true
+	}
+}
+
+// Runner runs components until a signal, context cancellation or critical component error,
+// then shuts them down in reverse order.
+type Runner struct {
+	components []*component
+
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+	logger          logger.LoggerI
+
+	mu      sync.Mutex
+	started bool
+}
+
+// New creates a new Runner.
+// Default configuration: SIGINT and SIGTERM trigger shutdown, 30 seconds shutdown timeout.
+//
+// Example:
+//
+//	r := app.New(l, app.ShutdownTimeout(20*time.Second))
+//	r.Add("http", httpServer)
+//	r.Add("grpc", grpcServer)
+//	r.Add("scheduler", s, app.NonCritical())
+//	if err := r.Run(context.Background()); err != nil {
+//	    l.Fatal(err)
+//	}
+func New(l logger.LoggerI, opts ...Option) *Runner {
+	r := &Runner{
+		shutdownTimeout: _defaultShutdownTimeout,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+		logger:          l,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Add registers a component. Components start in the order they are added
+// and shut down in reverse order.
+func (r *Runner) Add(name string, c Component, opts ...ComponentOption) {
+	comp := &component{name: name, c: c}
+
+	for _, opt := range opts {
+		opt(comp)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components = append(r.components, comp)
+}
+
+// AddFunc registers a background function as a component. The function runs in its
+// own goroutine with a context cancelled at shutdown; a non-nil error it returns
+// before shutdown is handled like a Notify error.
+//
+// Example:
+//
+//	r.AddFunc("outbox", func(ctx context.Context) error {
+//	    return relay.Run(ctx)
+//	})
+func (r *Runner) AddFunc(name string, fn func(ctx context.Context) error, opts ...ComponentOption) {
+	r.Add(name, &funcComponent{fn: fn, notify: make(chan error, 1)}, opts...)
+}
+
+// Run starts all components and blocks until ctx is done, a configured signal is
+// received or a critical component reports an error. It then shuts components down
+// in reverse order within the shutdown timeout. A second signal received during the
+// shutdown exits the process at once.
+// The returned error joins the component error that stopped the service, if any,
+// with shutdown errors; a signal or cancelled ctx alone returns nil.
+func (r *Runner) Run(ctx context.Context) error {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+
+		return ErrAlreadyRunning
+	}
+
+	r.started = true
+	components := r.components
+	r.mu.Unlock()
+
+	var stop context.CancelFunc
+	if len(r.signals) > 0 {
+		ctx, stop = signals.NotifyContext(ctx, signals.Signals(r.signals...), signals.WithLogger(r.logger))
+	} else {
+		ctx, stop = context.WithCancel(ctx)
+	}
+	defer stop()
+
+	failures := make(chan error, len(components))
+	done := make(chan struct{})
+
+	var watchers sync.WaitGroup
+
+	for _, comp := range components {
+		r.logger.Info("app - starting %s", comp.name)
+		comp.c.Start()
+
+		if n, ok := comp.c.(Notifier); ok {
+			watchers.Add(1)
+
+			go func() {
+				defer watchers.Done()
+
+				r.watch(comp, n.Notify(), failures, done)
+			}()
+		}
+	}
+
+	var cause error
+
+	select {
+	case <-ctx.Done():
+		r.logger.Info("app - shutting down")
+	case cause = <-failures:
+		r.logger.Error(fmt.Errorf("app - shutting down: %w", cause))
+	}
+
+	close(done)
+
+	errs := []error{cause}
+	errs = append(errs, r.shutdown(components)...)
+
+	watchers.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (r *Runner) watch(comp *component, notify <-chan error, failures chan<- error, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case err, ok := <-notify:
+			if !ok {
+				return
+			}
+
+			if err == nil {
+				continue
+			}
+
+			if isTemporary(err) {
+				r.logger.Warn("app - %s: %v", comp.name, err)
+
+				continue
+			}
+
+			err = fmt.Errorf("app - %s: %w", comp.name, err)
+
+			if comp.nonCritical {
+				r.logger.Error(err)
+
+				continue
+			}
+
+			select {
+			case failures <- err:
+			default:
+			}
+
+			return
+		}
+	}
+}
+
+func isTemporary(err error) bool {
+	var t interface{ Temporary() bool }
+
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// shutdown stops components in reverse order. Components that do not finish before
+// the shutdown deadline are abandoned and reported as errors.
+func (r *Runner) shutdown(components []*component) []error {
+	deadline := time.After(r.shutdownTimeout)
+
+	var errs []error
+
+	for i := len(components) - 1; i >= 0; i-- {
+		comp := components[i]
+
+		r.logger.Info("app - stopping %s", comp.name)
+
+		result := make(chan error, 1)
+
+		go func() {
+			result <- comp.c.Shutdown()
+		}()
+
+		select {
+		case err := <-result:
+			if err != nil {
+				errs = append(errs, fmt.Errorf("app - %s - Shutdown: %w", comp.name, err))
+			}
+		case <-deadline:
+			errs = append(errs, fmt.Errorf("app - %s - Shutdown: timed out after %s", comp.name, r.shutdownTimeout))
+
+			for j := i - 1; j >= 0; j-- {
+				errs = append(errs, fmt.Errorf("app - %s - Shutdown: skipped, deadline exceeded", components[j].name))
+			}
+
+			return errs
+		}
+	}
+
+	return errs
+}
+
+// funcComponent adapts a background function to Component.
+type funcComponent struct {
+	fn     func(ctx context.Context) error
+	notify chan error
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (f *funcComponent) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	f.cancel = cancel
+	f.done = make(chan struct{})
+
+	go func() {
+		defer close(f.done)
+
+		if err := f.fn(ctx); err != nil && ctx.Err() == nil {
+			f.notify <- err
+		}
+	}()
+}
+
+func (f *funcComponent) Notify() <-chan error {
+	return f.notify
+}
+
+func (f *funcComponent) Shutdown() error {
+	f.cancel()
+	<-f.done
+
+	return nil
+}
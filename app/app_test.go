@@ -0,0 +1,199 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/app"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+type fakeComponent struct {
+	name     string
+	rec      *recorder
+	notify   chan error
+	shutdown func() error
+}
+
+func newFake(name string, rec *recorder) *fakeComponent {
+	return &fakeComponent{name: name, rec: rec, notify: make(chan error, 1)}
+}
+
+func (f *fakeComponent) Start() { f.rec.add("start " + f.name) }
+
+func (f *fakeComponent) Notify() <-chan error { return f.notify }
+
+func (f *fakeComponent) Shutdown() error {
+	f.rec.add("stop " + f.name)
+
+	if f.shutdown != nil {
+		return f.shutdown()
+	}
+
+	return nil
+}
+
+func TestRunContextCancel(t *testing.T) {
+	rec := &recorder{}
+
+	r := app.New(logger.New("error"))
+	r.Add("db", newFake("db", rec))
+	r.Add("http", newFake("http", rec))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := r.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	want := []string{"start db", "start http", "stop http", "stop db"}
+	if got := rec.list(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	if err := r.Run(ctx); !errors.Is(err, app.ErrAlreadyRunning) {
+		t.Errorf("second Run() error = %v, want ErrAlreadyRunning", err)
+	}
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "reconnecting" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestRunComponentError(t *testing.T) {
+	errListen := errors.New("listen failed")
+	errShutdown := errors.New("shutdown failed")
+
+	tests := []struct {
+		name        string
+		nonCritical bool
+		notify      error
+		wantErrs    []error
+	}{
+		{name: "critical", notify: errListen, wantErrs: []error{errListen, errShutdown}},
+		{name: "non critical", nonCritical: true, notify: errListen},
+		{name: "temporary", notify: temporaryError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+
+			failing := newFake("grpc", rec)
+			failing.shutdown = func() error { return errShutdown }
+			failing.notify <- tt.notify
+
+			r := app.New(logger.New("error"))
+
+			if tt.nonCritical {
+				r.Add("grpc", failing, app.NonCritical())
+			} else {
+				r.Add("grpc", failing)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			err := r.Run(ctx)
+
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("Run() error = %v, want %v", err, want)
+				}
+			}
+
+			if tt.wantErrs == nil && !errors.Is(err, errShutdown) {
+				t.Errorf("Run() error = %v, want shutdown error only", err)
+			}
+
+			if tt.wantErrs == nil && errors.Is(err, tt.notify) {
+				t.Errorf("Run() error = %v, non critical error must not be returned", err)
+			}
+		})
+	}
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	rec := &recorder{}
+
+	slow := newFake("slow", rec)
+	slow.shutdown = func() error {
+		time.Sleep(time.Second)
+
+		return nil
+	}
+
+	r := app.New(logger.New("error"), app.ShutdownTimeout(50*time.Millisecond))
+	r.Add("first", newFake("first", rec))
+	r.Add("slow", slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+
+	if err := r.Run(ctx); err == nil {
+		t.Fatal("Run() error = nil, want shutdown timeout error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run() took %s, want shutdown deadline to be enforced", elapsed)
+	}
+
+	if slices.Contains(rec.list(), "stop first") {
+		t.Error("components after the deadline must be skipped")
+	}
+}
+
+func TestAddFunc(t *testing.T) {
+	errWorker := errors.New("worker failed")
+
+	r := app.New(logger.New("error"))
+
+	stopped := make(chan struct{})
+
+	r.AddFunc("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+
+		return nil
+	})
+	r.AddFunc("failing", func(context.Context) error {
+		return errWorker
+	})
+
+	if err := r.Run(context.Background()); !errors.Is(err, errWorker) {
+		t.Errorf("Run() error = %v, want %v", err, errWorker)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("background function context was not cancelled on shutdown")
+	}
+}
@@ -0,0 +1,35 @@
+package app_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/app"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+func ExampleRunner_Run() {
+	l := logger.New("info")
+
+	r := app.New(l, app.ShutdownTimeout(20*time.Second))
+	r.Add("http", httpserver.New(httpserver.Port("8080")))
+	r.AddFunc("ticker", func(ctx context.Context) error {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				l.Info("tick")
+			}
+		}
+	})
+
+	// Blocks until SIGINT/SIGTERM or a component failure.
+	if err := r.Run(context.Background()); err != nil {
+		l.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"os"
+	"time"
+)
+
+// Option is a function that configures a Runner.
+type Option func(*Runner)
+
+// ShutdownTimeout sets the deadline for shutting down all components.
+// Default is 30 seconds.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		r.shutdownTimeout = timeout
+	}
+}
+
+// Signals sets the OS signals that trigger shutdown, and force it when received again.
+// Default is SIGINT and SIGTERM.
+func Signals(signals ...os.Signal) Option {
+	return func(r *Runner) {
+		r.signals = signals
+	}
+}
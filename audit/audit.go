@@ -0,0 +1,132 @@
+// Package audit records who did what to which resource, for compliance-driven
+// services. Events are recorded without blocking request handling and written in
+// batches by a background Auditor to a Sink: a Postgres table or a Kafka topic.
+// Fiber middleware (httpserver/middleware.Audit) and gRPC interceptors
+// (grpcserver.UnaryAudit) capture mutations automatically; handlers enrich the
+// captured event with FromContext.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Outcome tells whether an audited action succeeded.
+type Outcome string
+
+// Outcomes.
+const (
+	Success Outcome = "success"
+	Failure Outcome = "failure"
+)
+
+// Event is an audit log entry.
+type Event struct {
+	// ID uniquely identifies the event; Record generates one when empty.
+	ID string `json:"id"`
+	// Time is when the action happened; Record sets it when zero.
+	Time time.Time `json:"time"`
+	// Actor identifies who performed the action, e.g. a user or service ID.
+	Actor string `json:"actor"`
+	// Action is what was done, e.g. "order.cancel" or "POST /orders".
+	Action string `json:"action"`
+	// Resource is the type of the affected resource, e.g. "order".
+	Resource string `json:"resource"`
+	// ResourceID identifies the affected resource.
+	ResourceID string `json:"resource_id,omitempty"`
+	// Before is the JSON state of the resource before the action, if known.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the JSON state of the resource after the action, if known.
+	After json.RawMessage `json:"after,omitempty"`
+	// IP is the client IP address.
+	IP string `json:"ip,omitempty"`
+	// UserAgent is the client user agent.
+	UserAgent string `json:"user_agent,omitempty"`
+	// Outcome tells whether the action succeeded.
+	Outcome Outcome `json:"outcome"`
+	// Metadata carries additional details, e.g. a request ID or a status code.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	skipped bool
+}
+
+// SetChange stores the JSON encoding of the resource states before and after the
+// action. A nil state is left empty, e.g. before a creation.
+func (e *Event) SetChange(before, after any) error {
+	var err error
+
+	if before != nil {
+		if e.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("audit - Event - SetChange - json.Marshal: %w", err)
+		}
+	}
+
+	if after != nil {
+		if e.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("audit - Event - SetChange - json.Marshal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetMetadata sets a metadata entry.
+func (e *Event) SetMetadata(key, value string) {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+
+	e.Metadata[key] = value
+}
+
+// Skipped reports whether Skip was called for the event.
+func (e *Event) Skipped() bool {
+	return e.skipped
+}
+
+// Sink stores audit events. Implementations must be safe for concurrent use.
+type Sink interface {
+	// Write stores a batch of events.
+	Write(ctx context.Context, events []*Event) error
+}
+
+// SinkFunc adapts a function to Sink.
+type SinkFunc func(ctx context.Context, events []*Event) error
+
+// Write calls f.
+func (f SinkFunc) Write(ctx context.Context, events []*Event) error {
+	return f(ctx, events)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying e, the event captured for the current
+// request. The audit middleware calls it before running handlers.
+func NewContext(ctx context.Context, e *Event) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the event captured for the current request, or nil outside
+// of the audit middleware. Handlers enrich it before returning.
+//
+// Example:
+//
+//	if e := audit.FromContext(c.UserContext()); e != nil {
+//	    e.Resource, e.ResourceID = "order", order.ID
+//	    _ = e.SetChange(old, order)
+//	}
+func FromContext(ctx context.Context) *Event {
+	e, _ := ctx.Value(contextKey{}).(*Event)
+
+	return e
+}
+
+// Skip prevents the event captured for the current request from being recorded,
+// e.g. for a mutation that changed nothing. It is a no-op outside of the audit middleware.
+func Skip(ctx context.Context) {
+	if e := FromContext(ctx); e != nil {
+		e.skipped = true
+	}
+}
@@ -0,0 +1,198 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]*audit.Event
+	err     error
+}
+
+func (s *memorySink) Write(_ context.Context, events []*audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.batches = append(s.batches, events)
+
+	return nil
+}
+
+func (s *memorySink) events() []*audit.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []*audit.Event
+	for _, b := range s.batches {
+		events = append(events, b...)
+	}
+
+	return events
+}
+
+func TestAuditor(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []audit.Option
+		events      int
+		wantBatches int
+	}{
+		{name: "single batch", events: 3, wantBatches: 1},
+		{name: "full batches", opts: []audit.Option{audit.BatchSize(2)}, events: 5, wantBatches: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memorySink{}
+			a := audit.New(sink, tt.opts...)
+			a.Start()
+
+			for range tt.events {
+				a.Record(context.Background(), &audit.Event{Actor: "u1", Action: "order.cancel"})
+			}
+
+			if err := a.Shutdown(); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+
+			events := sink.events()
+			if len(events) != tt.events {
+				t.Fatalf("written events = %d, want %d", len(events), tt.events)
+			}
+
+			if len(sink.batches) != tt.wantBatches {
+				t.Errorf("batches = %d, want %d", len(sink.batches), tt.wantBatches)
+			}
+
+			for _, e := range events {
+				if e.ID == "" || e.Time.IsZero() {
+					t.Errorf("event ID = %q, Time = %v, want both set", e.ID, e.Time)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditor_FlushInterval(t *testing.T) {
+	sink := &memorySink{}
+	a := audit.New(sink, audit.FlushInterval(10*time.Millisecond))
+	a.Start()
+	defer a.Shutdown()
+
+	a.Record(context.Background(), &audit.Event{Action: "order.create"})
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.events()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("event not written after the flush interval")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAuditor_Dropped(t *testing.T) {
+	a := audit.New(&memorySink{}, audit.BufferSize(1))
+
+	a.Record(context.Background(), &audit.Event{Action: "a"})
+	a.Record(context.Background(), &audit.Event{Action: "b"})
+
+	if got := a.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+
+	_ = a.Shutdown()
+
+	a.Record(context.Background(), &audit.Event{Action: "c"})
+
+	if got := a.Dropped(); got != 2 {
+		t.Errorf("Dropped() after Shutdown = %d, want 2", got)
+	}
+}
+
+func TestAuditor_WriteError(t *testing.T) {
+	sink := &memorySink{err: errors.New("database unavailable")}
+	a := audit.New(sink, audit.WriteRetry(retry.Attempts(2), retry.ConstantBackoff(0)))
+	a.Start()
+
+	a.Record(context.Background(), &audit.Event{Action: "order.cancel"})
+
+	_ = a.Shutdown()
+
+	select {
+	case err := <-a.Notify():
+		if !strings.Contains(err.Error(), "database unavailable") {
+			t.Errorf("Notify() error = %v, want the sink error", err)
+		}
+	default:
+		t.Error("Notify() received no error")
+	}
+}
+
+func TestEvent_SetChange(t *testing.T) {
+	e := &audit.Event{}
+
+	if err := e.SetChange(nil, map[string]string{"status": "paid"}); err != nil {
+		t.Fatalf("SetChange() error = %v", err)
+	}
+
+	if e.Before != nil {
+		t.Errorf("Before = %s, want nil", e.Before)
+	}
+
+	if got := string(e.After); got != `{"status":"paid"}` {
+		t.Errorf("After = %s, want %s", got, `{"status":"paid"}`)
+	}
+
+	if err := e.SetChange(func() {}, nil); err == nil {
+		t.Error("SetChange() of a func error = nil, want error")
+	}
+}
+
+func TestContext(t *testing.T) {
+	if e := audit.FromContext(context.Background()); e != nil {
+		t.Errorf("FromContext() = %v, want nil", e)
+	}
+
+	audit.Skip(context.Background())
+
+	e := &audit.Event{}
+	ctx := audit.NewContext(context.Background(), e)
+
+	if got := audit.FromContext(ctx); got != e {
+		t.Errorf("FromContext() = %p, want %p", got, e)
+	}
+
+	audit.Skip(ctx)
+
+	if !e.Skipped() {
+		t.Error("Skipped() = false after Skip, want true")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := audit.Schema("compliance.audit_events")
+
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "compliance"."audit_events"`,
+		`"audit_events_resource_idx" ON "compliance"."audit_events" (resource, resource_id, occurred_at)`,
+		`"audit_events_actor_idx" ON "compliance"."audit_events" (actor, occurred_at)`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("Schema() does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// Auditor records events asynchronously: Record queues them in a buffer and a
+// background goroutine writes them to the sink in batches, retrying failed writes.
+// When the buffer is full, events are dropped rather than slowing requests down;
+// Dropped counts them.
+type Auditor struct {
+	sink Sink
+	cfg  config
+
+	events  chan *Event
+	dropped atomic.Uint64
+
+	notify chan error
+	stop   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	once   sync.Once
+}
+
+// New creates a new Auditor writing events to sink.
+// Default configuration: buffer of 10000 events, batches of 100 events flushed at
+// least every second, 3 write attempts with exponential backoff, 10 seconds write timeout.
+//
+// Example:
+//
+//	a := audit.New(audit.NewPostgresSink(pg, ""), audit.WithLogger(l))
+//	a.Start()
+//	defer a.Shutdown()
+//
+//	app.Use(middleware.Audit(a, nil))
+func New(sink Sink, opts ...Option) *Auditor {
+	cfg := newConfig(opts)
+
+	return &Auditor{
+		sink:   sink,
+		cfg:    cfg,
+		events: make(chan *Event, cfg.bufferSize),
+		notify: make(chan error, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Record queues e for writing without blocking. It sets the event ID and time when
+// empty. Events recorded after Shutdown are dropped.
+func (a *Auditor) Record(_ context.Context, e *Event) {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	select {
+	case <-a.stop:
+		a.drop(e)
+
+		return
+	default:
+	}
+
+	select {
+	case a.events <- e:
+	default:
+		a.drop(e)
+	}
+}
+
+func (a *Auditor) drop(e *Event) {
+	a.dropped.Add(1)
+	a.logError("audit - Auditor - event %s dropped: %s %s by %s", e.ID, e.Action, e.ResourceID, e.Actor)
+}
+
+// Dropped returns the number of events dropped because the buffer was full or the
+// auditor was shut down.
+func (a *Auditor) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Start begins writing events in a separate goroutine.
+// Use Notify() to receive write errors.
+func (a *Auditor) Start() {
+	a.start.Do(func() {
+		go a.run()
+	})
+}
+
+// Notify returns a channel that receives write errors, after retries, of batches
+// that were discarded. Errors are dropped while a previous one has not been received.
+func (a *Auditor) Notify() <-chan error {
+	return a.notify
+}
+
+// Shutdown stops accepting events and writes the buffered ones.
+func (a *Auditor) Shutdown() error {
+	a.Start()
+
+	a.once.Do(func() {
+		close(a.stop)
+	})
+
+	<-a.done
+
+	return nil
+}
+
+func (a *Auditor) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, a.cfg.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		a.write(batch)
+		batch = make([]*Event, 0, a.cfg.batchSize)
+	}
+
+	for {
+		select {
+		case e := <-a.events:
+			batch = append(batch, e)
+			if len(batch) == a.cfg.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-a.stop:
+			for {
+				select {
+				case e := <-a.events:
+					batch = append(batch, e)
+					if len(batch) == a.cfg.batchSize {
+						flush()
+					}
+				default:
+					flush()
+
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *Auditor) write(batch []*Event) {
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, a.cfg.writeTimeout)
+		defer cancel()
+
+		return a.sink.Write(ctx, batch)
+	}, a.cfg.retry...)
+	if err == nil {
+		return
+	}
+
+	err = fmt.Errorf("audit - Auditor - a.sink.Write: %d events lost: %w", len(batch), err)
+	a.logError(err.Error())
+
+	select {
+	case a.notify <- err:
+	default:
+	}
+}
+
+func (a *Auditor) logError(message string, args ...interface{}) {
+	if a.cfg.logger != nil {
+		a.cfg.logger.Error(message, args...)
+	}
+}
@@ -0,0 +1,29 @@
+package audit_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/audit"
+)
+
+func ExampleAuditor() {
+	a := audit.New(audit.SinkFunc(func(_ context.Context, events []*audit.Event) error {
+		for _, e := range events {
+			fmt.Println(e.Actor, e.Action, e.Resource, e.ResourceID, e.Outcome, string(e.After))
+		}
+
+		return nil
+	}))
+	a.Start()
+
+	e := &audit.Event{Actor: "user-1", Action: "order.pay", Resource: "order", ResourceID: "42", Outcome: audit.Success}
+	_ = e.SetChange(map[string]string{"status": "pending"}, map[string]string{"status": "paid"})
+
+	a.Record(context.Background(), e)
+
+	_ = a.Shutdown()
+
+	// Output:
+	// user-1 order.pay order 42 success {"status":"paid"}
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// DefaultTopic is the topic used by KafkaSink when no topic is given.
+const DefaultTopic = "audit.events"
+
+// KafkaSink is a Sink producing events as JSON records to a Kafka topic. Records
+// are keyed by resource and resource ID, so the events of a resource keep their order.
+type KafkaSink struct {
+	conn  *kafka.Connection
+	topic string
+}
+
+var _ Sink = (*KafkaSink)(nil)
+
+// NewKafkaSink creates a new KafkaSink producing to topic, or DefaultTopic when empty,
+// through a connected conn.
+//
+// Example:
+//
+//	conn := kafka.NewConnection(kafka.Config{Brokers: []string{"localhost:9092"}})
+//	err := conn.Connect(ctx)
+//
+//	a := audit.New(audit.NewKafkaSink(conn, ""))
+func NewKafkaSink(conn *kafka.Connection, topic string) *KafkaSink {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+
+	return &KafkaSink{conn: conn, topic: topic}
+}
+
+// Write implements Sink. It waits for the broker acknowledgement of every record.
+func (s *KafkaSink) Write(ctx context.Context, events []*Event) error {
+	records := make([]*kgo.Record, 0, len(events))
+
+	for _, e := range events {
+		record, err := s.record(e)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, record)
+	}
+
+	if err := s.conn.Client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("audit - KafkaSink - Write - ProduceSync: %w", err)
+	}
+
+	return nil
+}
+
+func (s *KafkaSink) record(e *Event) (*kgo.Record, error) {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("audit - KafkaSink - Write - json.Marshal: %w", err)
+	}
+
+	return &kgo.Record{
+		Topic:     s.topic,
+		Key:       []byte(e.Resource + "/" + e.ResourceID),
+		Value:     value,
+		Timestamp: e.Time,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_defaultBufferSize    = 10000
+	_defaultBatchSize     = 100
+	_defaultFlushInterval = time.Second
+	_defaultWriteTimeout  = 10 * time.Second
+)
+
+// Option is a function that configures an Auditor.
+type Option func(*config)
+
+type config struct {
+	bufferSize    int
+	batchSize     int
+	flushInterval time.Duration
+	writeTimeout  time.Duration
+	retry         []retry.Option
+	logger        logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		bufferSize:    _defaultBufferSize,
+		batchSize:     _defaultBatchSize,
+		flushInterval: _defaultFlushInterval,
+		writeTimeout:  _defaultWriteTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.batchSize < 1 {
+		cfg.batchSize = 1
+	}
+
+	if cfg.flushInterval <= 0 {
+		cfg.flushInterval = _defaultFlushInterval
+	}
+
+	return cfg
+}
+
+// BufferSize sets how many events may wait to be written before Record drops new
+// ones. Default is 10000.
+func BufferSize(n int) Option {
+	return func(c *config) {
+		c.bufferSize = n
+	}
+}
+
+// BatchSize sets the maximum number of events written to the sink at once.
+// Default is 100.
+func BatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// FlushInterval sets how long events may wait for a batch to fill before being
+// written. Default is 1 second.
+func FlushInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.flushInterval = interval
+	}
+}
+
+// WriteTimeout sets the timeout of a single write attempt. Default is 10 seconds.
+func WriteTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.writeTimeout = timeout
+	}
+}
+
+// WriteRetry sets the retry policy of failed writes. Errors wrapped with
+// retry.Permanent are not retried. Default is the retry package default: 3 attempts
+// with exponential backoff.
+func WriteRetry(opts ...retry.Option) Option {
+	return func(c *config) {
+		c.retry = opts
+	}
+}
+
+// WithLogger sets a logger for write errors and dropped events.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// DefaultTable is the table used by PostgresSink when no table name is given.
+const DefaultTable = "audit_events"
+
+// _maxInsertRows keeps a multi-row INSERT below the Postgres limit of 65535 parameters.
+const _maxInsertRows = 5000
+
+// Schema returns the DDL creating the audit table and its indexes by resource and
+// by actor, for use in migrations (e.g. with the goose package).
+func Schema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    id          TEXT PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    actor       TEXT NOT NULL,
+    action      TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT NOT NULL DEFAULT '',
+    before      JSONB,
+    after       JSONB,
+    ip          TEXT NOT NULL DEFAULT '',
+    user_agent  TEXT NOT NULL DEFAULT '',
+    outcome     TEXT NOT NULL,
+    metadata    JSONB
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (resource, resource_id, occurred_at);
+CREATE INDEX IF NOT EXISTS %[3]s ON %[1]s (actor, occurred_at);`,
+		postgres.TableIdentifier(table).Sanitize(),
+		postgres.IndexIdentifier(table, "resource").Sanitize(),
+		postgres.IndexIdentifier(table, "actor").Sanitize())
+}
+
+// PostgresSink is a Sink writing events to a Postgres table. Rewriting an event
+// after a retried write is a no-op.
+type PostgresSink struct {
+	pool  *pgxpool.Pool
+	name  string
+	table string
+}
+
+var _ Sink = (*PostgresSink)(nil)
+
+// NewPostgresSink creates a new PostgresSink using table, or DefaultTable when empty.
+//
+// Example:
+//
+//	sink := audit.NewPostgresSink(pg, "")
+//	err := sink.CreateTable(ctx)
+func NewPostgresSink(pg *postgres.Postgres, table string) *PostgresSink {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	return &PostgresSink{pool: pg.Pool, name: table, table: postgres.TableIdentifier(table).Sanitize()}
+}
+
+// CreateTable creates the audit table if it does not exist.
+func (s *PostgresSink) CreateTable(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, Schema(s.name)); err != nil {
+		return fmt.Errorf("audit - PostgresSink - CreateTable - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Write implements Sink.
+func (s *PostgresSink) Write(ctx context.Context, events []*Event) error {
+	for len(events) > 0 {
+		n := min(len(events), _maxInsertRows)
+
+		query, args, err := s.insert(events[:n])
+		if err != nil {
+			return err
+		}
+
+		if _, err = s.pool.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("audit - PostgresSink - Write - s.pool.Exec: %w", err)
+		}
+
+		events = events[n:]
+	}
+
+	return nil
+}
+
+func (s *PostgresSink) insert(events []*Event) (string, []any, error) {
+	const columns = 12
+
+	var b strings.Builder
+
+	b.WriteString("INSERT INTO ")
+	b.WriteString(s.table)
+	b.WriteString(" (id, occurred_at, actor, action, resource, resource_id, before, after, ip, user_agent, outcome, metadata) VALUES ")
+
+	args := make([]any, 0, len(events)*columns)
+
+	for i, e := range events {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString("(")
+
+		for j := range columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(&b, "$%d", i*columns+j+1)
+		}
+
+		b.WriteString(")")
+
+		var metadata []byte
+
+		if len(e.Metadata) > 0 {
+			var err error
+			if metadata, err = json.Marshal(e.Metadata); err != nil {
+				return "", nil, fmt.Errorf("audit - PostgresSink - Write - json.Marshal: %w", err)
+			}
+		}
+
+		args = append(args, e.ID, e.Time, e.Actor, e.Action, e.Resource, e.ResourceID,
+			nullJSON(e.Before), nullJSON(e.After), e.IP, e.UserAgent, string(e.Outcome), nullJSON(metadata))
+	}
+
+	b.WriteString(" ON CONFLICT (id) DO NOTHING")
+
+	return b.String(), args, nil
+}
+
+// nullJSON maps empty JSON to SQL NULL.
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return string(data)
+}
@@ -0,0 +1,172 @@
+// Package authz checks what subjects (users, services) may do. Subjects are granted
+// roles, roles carry permissions, and permissions allow actions on resources, with
+// "*" wildcards. An Authorizer evaluates Can(ctx, subject, action, resource) against
+// the roles of a Store (in memory, Postgres, optionally cached in Redis) and optional
+// policies, e.g. ownership rules. Fiber middleware (httpserver/middleware.Authorize)
+// and gRPC interceptors (grpcserver.UnaryAuthorize) enforce permissions per route or
+// method.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Wildcard matches any action or resource. A trailing wildcard matches any suffix,
+// e.g. "invoices/*" matches "invoices/42".
+const Wildcard = "*"
+
+var (
+	// ErrForbidden is returned by Authorize when the subject lacks the permission.
+	ErrForbidden = errors.New("authz - forbidden")
+	// ErrRoleNotFound is returned when assigning an undefined role.
+	ErrRoleNotFound = errors.New("authz - role not found")
+)
+
+// Permission allows an action on a resource, e.g. {Action: "delete", Resource: "orders"}.
+type Permission struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+}
+
+// Allows reports whether p allows action on resource.
+func (p Permission) Allows(action, resource string) bool {
+	return match(p.Action, action) && match(p.Resource, resource)
+}
+
+// String returns the permission as "action:resource".
+func (p Permission) String() string {
+	return p.Action + ":" + p.Resource
+}
+
+func match(pattern, value string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, Wildcard); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+
+	return pattern == value
+}
+
+// Role is a named set of permissions.
+type Role struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Allows reports whether a permission of r allows action on resource.
+func (r Role) Allows(action, resource string) bool {
+	for _, p := range r.Permissions {
+		if p.Allows(action, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Store provides the roles granted to subjects. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Roles returns the roles granted to subject, with their permissions.
+	Roles(ctx context.Context, subject string) ([]Role, error)
+}
+
+// Decision is the result of a Policy.
+type Decision int
+
+// Decisions.
+const (
+	// Abstain leaves the decision to other policies and to roles.
+	Abstain Decision = iota
+	// Allow grants the request, unless another policy denies it.
+	Allow
+	// Deny rejects the request, whatever the roles and other policies.
+	Deny
+)
+
+// Policy decides on a request beyond roles, e.g. allowing owners to edit their own
+// resources or denying writes during maintenance.
+type Policy func(ctx context.Context, subject, action, resource string) (Decision, error)
+
+// Authorizer checks permissions. It is safe for concurrent use.
+type Authorizer struct {
+	store    Store
+	policies []Policy
+}
+
+// New creates a new Authorizer checking the roles of store, and the policies given
+// with WithPolicy.
+//
+// Example:
+//
+//	store := authz.NewPostgresStore(pg, "")
+//	az := authz.New(authz.NewCachedStore(store, r, time.Minute),
+//	    authz.WithPolicy(func(ctx context.Context, subject, action, resource string) (authz.Decision, error) {
+//	        if resource == "users/"+subject {
+//	            return authz.Allow, nil
+//	        }
+//	        return authz.Abstain, nil
+//	    }),
+//	)
+//
+//	ok, err := az.Can(ctx, userID, "delete", "orders")
+func New(store Store, opts ...Option) *Authorizer {
+	cfg := newConfig(opts)
+
+	return &Authorizer{store: store, policies: cfg.policies}
+}
+
+// Can reports whether subject may perform action on resource. A request is denied
+// when a policy denies it, allowed when a policy allows it or a role of the subject
+// has a matching permission, and denied otherwise.
+func (a *Authorizer) Can(ctx context.Context, subject, action, resource string) (bool, error) {
+	allowed := false
+
+	for _, p := range a.policies {
+		d, err := p(ctx, subject, action, resource)
+		if err != nil {
+			return false, fmt.Errorf("authz - Authorizer - Can - policy: %w", err)
+		}
+
+		switch d {
+		case Deny:
+			return false, nil
+		case Allow:
+			allowed = true
+		case Abstain:
+		}
+	}
+
+	if allowed {
+		return true, nil
+	}
+
+	roles, err := a.store.Roles(ctx, subject)
+	if err != nil {
+		return false, fmt.Errorf("authz - Authorizer - Can - a.store.Roles: %w", err)
+	}
+
+	for _, r := range roles {
+		if r.Allows(action, resource) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Authorize is like Can but returns ErrForbidden when the request is denied.
+func (a *Authorizer) Authorize(ctx context.Context, subject, action, resource string) error {
+	ok, err := a.Can(ctx, subject, action, resource)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s may not %s %s", ErrForbidden, subject, action, resource)
+	}
+
+	return nil
+}
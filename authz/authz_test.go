@@ -0,0 +1,145 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/authz"
+)
+
+func TestPermission_Allows(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission authz.Permission
+		action     string
+		resource   string
+		want       bool
+	}{
+		{name: "exact", permission: authz.Permission{Action: "read", Resource: "orders"}, action: "read", resource: "orders", want: true},
+		{name: "other action", permission: authz.Permission{Action: "read", Resource: "orders"}, action: "delete", resource: "orders"},
+		{name: "other resource", permission: authz.Permission{Action: "read", Resource: "orders"}, action: "read", resource: "users"},
+		{name: "wildcard", permission: authz.Permission{Action: "*", Resource: "*"}, action: "delete", resource: "users", want: true},
+		{name: "prefix wildcard", permission: authz.Permission{Action: "read", Resource: "invoices/*"}, action: "read", resource: "invoices/42", want: true},
+		{name: "prefix mismatch", permission: authz.Permission{Action: "read", Resource: "invoices/*"}, action: "read", resource: "invoices", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.permission.Allows(tt.action, tt.resource); got != tt.want {
+				t.Errorf("Allows(%q, %q) = %v, want %v", tt.action, tt.resource, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Can(t *testing.T) {
+	ctx := context.Background()
+
+	store := authz.NewMemoryStore(
+		authz.Role{Name: "viewer", Permissions: []authz.Permission{{Action: "read", Resource: "*"}}},
+		authz.Role{Name: "billing", Permissions: []authz.Permission{{Action: "*", Resource: "invoices"}}},
+	)
+	_ = store.Assign(ctx, "alice", "viewer")
+	_ = store.Assign(ctx, "alice", "billing")
+	_ = store.Assign(ctx, "bob", "viewer")
+
+	az := authz.New(store,
+		authz.WithPolicy(func(_ context.Context, _, action, _ string) (authz.Decision, error) {
+			if action == "purge" {
+				return authz.Deny, nil
+			}
+
+			return authz.Abstain, nil
+		}),
+		authz.WithPolicy(func(_ context.Context, subject, _, resource string) (authz.Decision, error) {
+			if resource == "users/"+subject {
+				return authz.Allow, nil
+			}
+
+			return authz.Abstain, nil
+		}),
+	)
+
+	tests := []struct {
+		name     string
+		subject  string
+		action   string
+		resource string
+		want     bool
+	}{
+		{name: "role permission", subject: "bob", action: "read", resource: "orders", want: true},
+		{name: "missing permission", subject: "bob", action: "delete", resource: "invoices"},
+		{name: "second role", subject: "alice", action: "delete", resource: "invoices", want: true},
+		{name: "allow policy", subject: "bob", action: "update", resource: "users/bob", want: true},
+		{name: "deny policy wins", subject: "alice", action: "purge", resource: "invoices"},
+		{name: "unknown subject", subject: "eve", action: "read", resource: "orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := az.Can(ctx, tt.subject, tt.action, tt.resource)
+			if err != nil {
+				t.Fatalf("Can() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+
+			err = az.Authorize(ctx, tt.subject, tt.action, tt.resource)
+			if tt.want != (err == nil) || (err != nil && !errors.Is(err, authz.ErrForbidden)) {
+				t.Errorf("Authorize() error = %v, want allowed %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_PolicyError(t *testing.T) {
+	errPolicy := errors.New("policy unavailable")
+	az := authz.New(authz.NewMemoryStore(), authz.WithPolicy(func(context.Context, string, string, string) (authz.Decision, error) {
+		return authz.Abstain, errPolicy
+	}))
+
+	if _, err := az.Can(context.Background(), "alice", "read", "orders"); !errors.Is(err, errPolicy) {
+		t.Errorf("Can() error = %v, want %v", err, errPolicy)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := authz.NewMemoryStore()
+
+	if err := store.Assign(ctx, "alice", "admin"); !errors.Is(err, authz.ErrRoleNotFound) {
+		t.Errorf("Assign() of an undefined role error = %v, want %v", err, authz.ErrRoleNotFound)
+	}
+
+	_ = store.DefineRole(ctx, authz.Role{Name: "admin", Permissions: []authz.Permission{{Action: "*", Resource: "*"}}})
+	_ = store.Assign(ctx, "alice", "admin")
+	_ = store.Assign(ctx, "alice", "admin")
+
+	if roles, _ := store.Roles(ctx, "alice"); len(roles) != 1 || roles[0].Name != "admin" {
+		t.Errorf("Roles() = %v, want [admin]", roles)
+	}
+
+	_ = store.Unassign(ctx, "alice", "admin")
+
+	if roles, _ := store.Roles(ctx, "alice"); len(roles) != 0 {
+		t.Errorf("Roles() after Unassign = %v, want none", roles)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := authz.Schema("iam.authz")
+
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "iam"."authz_roles"`,
+		`CREATE TABLE IF NOT EXISTS "iam"."authz_role_permissions"`,
+		`CREATE TABLE IF NOT EXISTS "iam"."authz_subject_roles"`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("Schema() does not contain %q", want)
+		}
+	}
+}
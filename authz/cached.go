@@ -0,0 +1,54 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/cache"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// CachedStore is a Store caching the roles of subjects in Redis, so permission
+// checks do not query the underlying store on every request.
+type CachedStore struct {
+	store Store
+	cache *cache.Cache[[]Role]
+}
+
+var _ Store = (*CachedStore)(nil)
+
+// NewCachedStore creates a new CachedStore caching the roles returned by store in r
+// for ttl. Role changes are visible after ttl, or at once for subjects passed to
+// Invalidate.
+//
+// Example:
+//
+//	store := authz.NewCachedStore(authz.NewPostgresStore(pg, ""), r, time.Minute)
+func NewCachedStore(store Store, r *redis.Redis, ttl time.Duration) *CachedStore {
+	return &CachedStore{
+		store: store,
+		cache: cache.New[[]Role](cache.NewRedis(r), cache.TTL(ttl), cache.Prefix("authz:roles:")),
+	}
+}
+
+// Roles implements Store.
+func (s *CachedStore) Roles(ctx context.Context, subject string) ([]Role, error) {
+	roles, err := s.cache.GetOrSet(ctx, subject, func(ctx context.Context) ([]Role, error) {
+		return s.store.Roles(ctx, subject)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("authz - CachedStore - Roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Invalidate removes the cached roles of subject, e.g. after assigning it a role.
+func (s *CachedStore) Invalidate(ctx context.Context, subject string) error {
+	if err := s.cache.Delete(ctx, subject); err != nil {
+		return fmt.Errorf("authz - CachedStore - Invalidate: %w", err)
+	}
+
+	return nil
+}
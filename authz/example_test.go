@@ -0,0 +1,25 @@
+package authz_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/authz"
+)
+
+func ExampleAuthorizer_Can() {
+	ctx := context.Background()
+
+	store := authz.NewMemoryStore(
+		authz.Role{Name: "editor", Permissions: []authz.Permission{{Action: "*", Resource: "articles"}}},
+	)
+	_ = store.Assign(ctx, "alice", "editor")
+
+	az := authz.New(store)
+
+	canEdit, _ := az.Can(ctx, "alice", "update", "articles")
+	canBill, _ := az.Can(ctx, "alice", "update", "invoices")
+	fmt.Println(canEdit, canBill)
+
+	// Output: true false
+}
@@ -0,0 +1,89 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MemoryStore is a Store keeping roles in memory, for tests and static
+// configurations.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	roles  map[string]Role
+	grants map[string][]string
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new MemoryStore defining roles.
+//
+// Example:
+//
+//	store := authz.NewMemoryStore(
+//	    authz.Role{Name: "admin", Permissions: []authz.Permission{{Action: "*", Resource: "*"}}},
+//	    authz.Role{Name: "viewer", Permissions: []authz.Permission{{Action: "read", Resource: "*"}}},
+//	)
+//	err := store.Assign(ctx, userID, "viewer")
+func NewMemoryStore(roles ...Role) *MemoryStore {
+	s := &MemoryStore{roles: make(map[string]Role), grants: make(map[string][]string)}
+
+	for _, r := range roles {
+		s.roles[r.Name] = r
+	}
+
+	return s
+}
+
+// DefineRole creates or replaces a role.
+func (s *MemoryStore) DefineRole(_ context.Context, r Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.roles[r.Name] = Role{Name: r.Name, Permissions: slices.Clone(r.Permissions)}
+
+	return nil
+}
+
+// Assign grants role to subject. It returns ErrRoleNotFound when the role is undefined.
+func (s *MemoryStore) Assign(_ context.Context, subject, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.roles[role]; !ok {
+		return fmt.Errorf("authz - MemoryStore - Assign - %q: %w", role, ErrRoleNotFound)
+	}
+
+	if !slices.Contains(s.grants[subject], role) {
+		s.grants[subject] = append(s.grants[subject], role)
+	}
+
+	return nil
+}
+
+// Unassign revokes role from subject.
+func (s *MemoryStore) Unassign(_ context.Context, subject, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grants[subject] = slices.DeleteFunc(s.grants[subject], func(r string) bool { return r == role })
+
+	return nil
+}
+
+// Roles implements Store.
+func (s *MemoryStore) Roles(_ context.Context, subject string) ([]Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]Role, 0, len(s.grants[subject]))
+
+	for _, name := range s.grants[subject] {
+		if r, ok := s.roles[name]; ok {
+			roles = append(roles, r)
+		}
+	}
+
+	return roles, nil
+}
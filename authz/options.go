@@ -0,0 +1,26 @@
+package authz
+
+// Option is a function that configures an Authorizer.
+type Option func(*config)
+
+type config struct {
+	policies []Policy
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithPolicy adds a policy evaluated before roles. Policies are evaluated in the
+// order they are added. Default is no policies.
+func WithPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policies = append(c.policies, p)
+	}
+}
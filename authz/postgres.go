@@ -0,0 +1,206 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// DefaultPrefix is the table name prefix used by PostgresStore when none is given.
+const DefaultPrefix = "authz"
+
+// _foreignKeyViolation is the SQLSTATE of foreign key violations.
+const _foreignKeyViolation = "23503"
+
+// Schema returns the DDL creating the roles, role permissions and subject roles
+// tables, named after prefix (e.g. "authz_roles"), optionally schema qualified
+// ("schema.prefix"), for use in migrations (e.g. with the goose package).
+func Schema(prefix string) string {
+	t := newTables(prefix)
+
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    name       TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS %[2]s (
+    role     TEXT NOT NULL REFERENCES %[1]s (name) ON DELETE CASCADE,
+    action   TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    PRIMARY KEY (role, action, resource)
+);
+CREATE TABLE IF NOT EXISTS %[3]s (
+    subject    TEXT NOT NULL,
+    role       TEXT NOT NULL REFERENCES %[1]s (name) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subject, role)
+);`, t.roles, t.permissions, t.subjects)
+}
+
+type tables struct {
+	roles       string
+	permissions string
+	subjects    string
+}
+
+func newTables(prefix string) tables {
+	return tables{
+		roles:       postgres.TableIdentifier(prefix + "_roles").Sanitize(),
+		permissions: postgres.TableIdentifier(prefix + "_role_permissions").Sanitize(),
+		subjects:    postgres.TableIdentifier(prefix + "_subject_roles").Sanitize(),
+	}
+}
+
+// PostgresStore is a Store backed by Postgres tables.
+type PostgresStore struct {
+	pool   *pgxpool.Pool
+	prefix string
+	tables tables
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a new PostgresStore using tables named after prefix, or
+// DefaultPrefix when empty.
+//
+// Example:
+//
+//	store := authz.NewPostgresStore(pg, "")
+//	err := store.CreateTables(ctx)
+//	err = store.DefineRole(ctx, authz.Role{Name: "editor", Permissions: []authz.Permission{
+//	    {Action: "*", Resource: "articles"},
+//	}})
+//	err = store.Assign(ctx, userID, "editor")
+func NewPostgresStore(pg *postgres.Postgres, prefix string) *PostgresStore {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	return &PostgresStore{pool: pg.Pool, prefix: prefix, tables: newTables(prefix)}
+}
+
+// CreateTables creates the authz tables if they do not exist.
+func (s *PostgresStore) CreateTables(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, Schema(s.prefix)); err != nil {
+		return fmt.Errorf("authz - PostgresStore - CreateTables - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// DefineRole creates a role or replaces its permissions.
+func (s *PostgresStore) DefineRole(ctx context.Context, r Role) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("authz - PostgresStore - DefineRole - s.pool.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	query := fmt.Sprintf("INSERT INTO %s (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", s.tables.roles)
+	if _, err = tx.Exec(ctx, query, r.Name); err != nil {
+		return fmt.Errorf("authz - PostgresStore - DefineRole - tx.Exec: %w", err)
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE role = $1", s.tables.permissions)
+	if _, err = tx.Exec(ctx, query, r.Name); err != nil {
+		return fmt.Errorf("authz - PostgresStore - DefineRole - tx.Exec: %w", err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (role, action, resource) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		s.tables.permissions)
+
+	for _, p := range r.Permissions {
+		if _, err = tx.Exec(ctx, query, r.Name, p.Action, p.Resource); err != nil {
+			return fmt.Errorf("authz - PostgresStore - DefineRole - tx.Exec: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("authz - PostgresStore - DefineRole - tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRole deletes a role and revokes it from all subjects.
+func (s *PostgresStore) DeleteRole(ctx context.Context, name string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE name = $1", s.tables.roles)
+	if _, err := s.pool.Exec(ctx, query, name); err != nil {
+		return fmt.Errorf("authz - PostgresStore - DeleteRole - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Assign grants role to subject. It returns ErrRoleNotFound when the role is undefined.
+func (s *PostgresStore) Assign(ctx context.Context, subject, role string) error {
+	query := fmt.Sprintf("INSERT INTO %s (subject, role) VALUES ($1, $2) ON CONFLICT DO NOTHING", s.tables.subjects)
+
+	_, err := s.pool.Exec(ctx, query, subject, role)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolation {
+		return fmt.Errorf("authz - PostgresStore - Assign - %q: %w", role, ErrRoleNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("authz - PostgresStore - Assign - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Unassign revokes role from subject.
+func (s *PostgresStore) Unassign(ctx context.Context, subject, role string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE subject = $1 AND role = $2", s.tables.subjects)
+	if _, err := s.pool.Exec(ctx, query, subject, role); err != nil {
+		return fmt.Errorf("authz - PostgresStore - Unassign - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Roles implements Store.
+func (s *PostgresStore) Roles(ctx context.Context, subject string) ([]Role, error) {
+	query := fmt.Sprintf(`SELECT s.role, p.action, p.resource
+FROM %s s LEFT JOIN %s p ON p.role = s.role
+WHERE s.subject = $1
+ORDER BY s.role`, s.tables.subjects, s.tables.permissions)
+
+	rows, err := s.pool.Query(ctx, query, subject)
+	if err != nil {
+		return nil, fmt.Errorf("authz - PostgresStore - Roles - s.pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []Role
+
+	for rows.Next() {
+		var (
+			name             string
+			action, resource *string
+		)
+
+		if err = rows.Scan(&name, &action, &resource); err != nil {
+			return nil, fmt.Errorf("authz - PostgresStore - Roles - rows.Scan: %w", err)
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].Name != name {
+			roles = append(roles, Role{Name: name})
+		}
+
+		if action != nil && resource != nil {
+			r := &roles[len(roles)-1]
+			r.Permissions = append(r.Permissions, Permission{Action: *action, Resource: *resource})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("authz - PostgresStore - Roles - rows.Err: %w", err)
+	}
+
+	return roles, nil
+}
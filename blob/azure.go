@@ -0,0 +1,226 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+// Azure is a Bucket on an Azure Blob Storage container.
+type Azure struct {
+	client    *container.Client
+	blockSize int64
+}
+
+var _ Bucket = (*Azure)(nil)
+
+// NewAzure creates a Bucket for an Azure Blob Storage container, authenticated
+// with a storage account shared key, which also signs URLs.
+//
+// Default configuration: endpoint "https://<account>.blob.core.windows.net", 16 MiB blocks.
+//
+// Example:
+//
+//	bucket, err := blob.NewAzure(os.Getenv("AZURE_STORAGE_ACCOUNT"), os.Getenv("AZURE_STORAGE_KEY"), "uploads")
+func NewAzure(account, key, containerName string, opts ...Option) (*Azure, error) {
+	cfg := newConfig(opts)
+
+	endpoint := cfg.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", account)
+	}
+
+	cred, err := container.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return nil, fmt.Errorf("blob - NewAzure - container.NewSharedKeyCredential: %w", err)
+	}
+
+	client, err := container.NewClientWithSharedKeyCredential(
+		strings.TrimSuffix(endpoint, "/")+"/"+containerName, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("blob - NewAzure - container.NewClientWithSharedKeyCredential: %w", err)
+	}
+
+	return &Azure{client: client, blockSize: int64(cfg.partSize)}, nil // #nosec G115 -- part size is a small positive value
+}
+
+// Client returns the underlying container client for operations not covered by Bucket.
+func (a *Azure) Client() *container.Client {
+	return a.client
+}
+
+// NewReader implements Bucket.
+func (a *Azure) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return a.NewRangeReader(ctx, key, 0, -1)
+}
+
+// NewRangeReader implements Bucket.
+func (a *Azure) NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		// A zero count means the whole blob to Azure; only check existence.
+		if _, err := a.Attributes(ctx, key); err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	if length < 0 {
+		length = 0
+	}
+
+	resp, err := a.client.NewBlobClient(key).DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: offset, Count: length},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blob - Azure - NewRangeReader - DownloadStream: %w", azureError(err))
+	}
+
+	return resp.Body, nil
+}
+
+// NewWriter implements Bucket.
+func (a *Azure) NewWriter(ctx context.Context, key string, opts ...WriterOption) (io.WriteCloser, error) {
+	cfg := newWriterConfig(opts)
+
+	metadata := make(map[string]*string, len(cfg.metadata))
+	for k, v := range cfg.metadata {
+		metadata[k] = to.Ptr(v)
+	}
+
+	return newPipeWriter(ctx, func(ctx context.Context, r io.Reader) error {
+		_, err := a.client.NewBlockBlobClient(key).UploadStream(ctx, r, &blockblob.UploadStreamOptions{
+			BlockSize:   a.blockSize,
+			HTTPHeaders: &blob.HTTPHeaders{BlobContentType: to.Ptr(cfg.contentType)},
+			Metadata:    metadata,
+		})
+		if err != nil {
+			return fmt.Errorf("blob - Azure - NewWriter - UploadStream: %w", err)
+		}
+
+		return nil
+	}), nil
+}
+
+// Attributes implements Bucket.
+func (a *Azure) Attributes(ctx context.Context, key string) (Attributes, error) {
+	props, err := a.client.NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		return Attributes{}, fmt.Errorf("blob - Azure - Attributes - GetProperties: %w", azureError(err))
+	}
+
+	attrs := Attributes{
+		Key:         key,
+		Size:        deref(props.ContentLength),
+		ContentType: deref(props.ContentType),
+		ModTime:     deref(props.LastModified),
+		Metadata:    make(map[string]string, len(props.Metadata)),
+	}
+
+	if props.ETag != nil {
+		attrs.ETag = string(*props.ETag)
+	}
+
+	for k, v := range props.Metadata {
+		attrs.Metadata[strings.ToLower(k)] = deref(v)
+	}
+
+	return attrs, nil
+}
+
+// List implements Bucket.
+func (a *Azure) List(ctx context.Context, prefix string) ([]Attributes, error) {
+	var list []Attributes
+
+	pager := a.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: to.Ptr(prefix)})
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("blob - Azure - List - pager.NextPage: %w", err)
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			attrs := Attributes{Key: deref(item.Name)}
+
+			if p := item.Properties; p != nil {
+				attrs.Size = deref(p.ContentLength)
+				attrs.ContentType = deref(p.ContentType)
+				attrs.ModTime = deref(p.LastModified)
+
+				if p.ETag != nil {
+					attrs.ETag = string(*p.ETag)
+				}
+			}
+
+			list = append(list, attrs)
+		}
+	}
+
+	return list, nil
+}
+
+// Delete implements Bucket.
+func (a *Azure) Delete(ctx context.Context, key string) error {
+	_, err := a.client.NewBlobClient(key).Delete(ctx, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return fmt.Errorf("blob - Azure - Delete - Delete: %w", err)
+	}
+
+	return nil
+}
+
+// SignedURL implements Bucket.
+func (a *Azure) SignedURL(_ context.Context, key, method string, expiry time.Duration) (string, error) {
+	var perms sas.BlobPermissions
+
+	switch method {
+	case http.MethodGet:
+		perms.Read = true
+	case http.MethodPut:
+		perms.Create = true
+		perms.Write = true
+	default:
+		return "", fmt.Errorf("blob - Azure - SignedURL - method %s: %w", method, ErrNotSupported)
+	}
+
+	u, err := a.client.NewBlobClient(key).GetSASURL(perms, time.Now().Add(expiry), nil)
+	if err != nil {
+		return "", fmt.Errorf("blob - Azure - SignedURL - GetSASURL: %w", err)
+	}
+
+	return u, nil
+}
+
+// Close implements Bucket.
+func (a *Azure) Close() error {
+	return nil
+}
+
+// azureError maps missing blob errors to ErrNotFound.
+func azureError(err error) error {
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	return err
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+
+	return *p
+}
@@ -0,0 +1,180 @@
+// Package blob provides a provider-agnostic blob storage abstraction. A Bucket
+// streams objects through readers and writers, exposes their attributes and
+// issues signed URLs; drivers exist for S3 (and S3-compatible stores such as
+// MinIO), Google Cloud Storage, Azure Blob Storage and the local filesystem,
+// and Open picks one from a URL so the provider can change per environment
+// through configuration only.
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the requested object does not exist.
+	ErrNotFound = errors.New("blob - object not found")
+	// ErrNotSupported is returned for operations a driver cannot perform, such as
+	// signing URLs for a file bucket without a signing key.
+	ErrNotSupported = errors.New("blob - operation not supported")
+)
+
+// Attributes describe a stored object.
+type Attributes struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+	// Metadata holds user-defined key-value pairs. List does not return it.
+	Metadata map[string]string
+}
+
+// Bucket is a container of objects addressed by slash-separated keys.
+type Bucket interface {
+	// NewReader returns a reader of the object content. The caller must close it.
+	NewReader(ctx context.Context, key string) (io.ReadCloser, error)
+	// NewRangeReader returns a reader of length bytes of the object starting at
+	// offset; a negative length reads to the end. The caller must close it.
+	NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// NewWriter returns a writer storing the object. The object becomes visible,
+	// replacing any previous version, only once Close returns nil; cancelling
+	// ctx before Close aborts the write.
+	NewWriter(ctx context.Context, key string, opts ...WriterOption) (io.WriteCloser, error)
+	// Attributes returns the attributes of the object.
+	Attributes(ctx context.Context, key string) (Attributes, error)
+	// List returns the attributes of every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Attributes, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL granting anonymous access to the object with the
+	// given HTTP method, GET or PUT, until expiry.
+	SignedURL(ctx context.Context, key, method string, expiry time.Duration) (string, error)
+	// Close releases the resources of the bucket.
+	Close() error
+}
+
+// WriterOption configures an object written with NewWriter.
+type WriterOption func(*writerConfig)
+
+type writerConfig struct {
+	contentType string
+	metadata    map[string]string
+}
+
+func newWriterConfig(opts []WriterOption) writerConfig {
+	cfg := writerConfig{contentType: "application/octet-stream"}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// ContentType sets the object content type.
+// Default is "application/octet-stream".
+func ContentType(contentType string) WriterOption {
+	return func(c *writerConfig) {
+		c.contentType = contentType
+	}
+}
+
+// Metadata sets user-defined metadata stored with the object. Keys should be
+// lowercase ASCII as some providers normalize them.
+// Default is none.
+func Metadata(metadata map[string]string) WriterOption {
+	return func(c *writerConfig) {
+		c.metadata = metadata
+	}
+}
+
+// ReadAll returns the content of the object.
+func ReadAll(ctx context.Context, b Bucket, key string) ([]byte, error) {
+	r, err := b.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("blob - ReadAll - io.ReadAll: %w", err)
+	}
+
+	return data, nil
+}
+
+// WriteAll stores data as the object content.
+//
+// Example:
+//
+//	err := blob.WriteAll(ctx, bucket, "avatars/42.png", png, blob.ContentType("image/png"))
+func WriteAll(ctx context.Context, b Bucket, key string, data []byte, opts ...WriterOption) error {
+	w, err := b.NewWriter(ctx, key, opts...)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(w, bytes.NewReader(data)); err != nil {
+		_ = w.Close()
+
+		return fmt.Errorf("blob - WriteAll - io.Copy: %w", err)
+	}
+
+	return w.Close()
+}
+
+// Exists reports whether the object exists.
+func Exists(ctx context.Context, b Bucket, key string) (bool, error) {
+	_, err := b.Attributes(ctx, key)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// pipeWriter adapts upload APIs consuming a reader to io.WriteCloser: writes
+// feed upload, running in the background, and Close waits for its result.
+type pipeWriter struct {
+	pw     *io.PipeWriter
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func newPipeWriter(ctx context.Context, upload func(ctx context.Context, r io.Reader) error) *pipeWriter {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+
+	w := &pipeWriter{pw: pw, cancel: cancel, done: make(chan error, 1)}
+
+	go func() {
+		err := upload(ctx, pr)
+		// Unblocks writers if the upload stopped reading early.
+		_ = pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	return w
+}
+
+func (w *pipeWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *pipeWriter) Close() error {
+	defer w.cancel()
+
+	_ = w.pw.Close()
+
+	return <-w.done
+}
@@ -0,0 +1,296 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileBucket(t *testing.T, opts ...blob.Option) *blob.File {
+	t.Helper()
+
+	b, err := blob.NewFile(t.TempDir(), opts...)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestFile_ReadWrite(t *testing.T) {
+	ctx := context.Background()
+	b := newFileBucket(t)
+
+	err := blob.WriteAll(ctx, b, "docs/readme.txt", []byte("hello blob"),
+		blob.ContentType("text/plain"),
+		blob.Metadata(map[string]string{"owner": "alice"}),
+	)
+	require.NoError(t, err)
+
+	data, err := blob.ReadAll(ctx, b, "docs/readme.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello blob", string(data))
+
+	attrs, err := b.Attributes(ctx, "docs/readme.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "docs/readme.txt", attrs.Key)
+	assert.Equal(t, int64(10), attrs.Size)
+	assert.Equal(t, "text/plain", attrs.ContentType)
+	assert.Equal(t, map[string]string{"owner": "alice"}, attrs.Metadata)
+	assert.NotEmpty(t, attrs.ETag)
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{name: "whole", offset: 0, length: -1, want: "hello blob"},
+		{name: "prefix", offset: 0, length: 5, want: "hello"},
+		{name: "middle", offset: 6, length: 2, want: "bl"},
+		{name: "suffix", offset: 6, length: -1, want: "blob"},
+		{name: "empty", offset: 3, length: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := b.NewRangeReader(ctx, "docs/readme.txt", tt.offset, tt.length)
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestFile_ListDelete(t *testing.T) {
+	ctx := context.Background()
+	b := newFileBucket(t)
+
+	for _, key := range []string{"a/1", "a/2", "b/1"} {
+		require.NoError(t, blob.WriteAll(ctx, b, key, []byte(key)))
+	}
+
+	list, err := b.List(ctx, "a/")
+	require.NoError(t, err)
+
+	var keys []string
+	for _, attrs := range list {
+		keys = append(keys, attrs.Key)
+	}
+
+	assert.Equal(t, []string{"a/1", "a/2"}, keys)
+
+	require.NoError(t, b.Delete(ctx, "a/1"))
+	require.NoError(t, b.Delete(ctx, "a/1"), "deleting a missing object")
+
+	ok, err := blob.Exists(ctx, b, "a/1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = b.NewReader(ctx, "a/1")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	_, err = b.Attributes(ctx, "a/1")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+}
+
+func TestFile_Writer(t *testing.T) {
+	ctx := context.Background()
+	b := newFileBucket(t)
+
+	require.NoError(t, blob.WriteAll(ctx, b, "k", []byte("old")))
+
+	cctx, cancel := context.WithCancel(ctx)
+
+	w, err := b.NewWriter(cctx, "k")
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("new"))
+	require.NoError(t, err)
+
+	// The object is only replaced by a successful Close.
+	data, err := blob.ReadAll(ctx, b, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+
+	cancel()
+	assert.Error(t, w.Close())
+
+	data, err = blob.ReadAll(ctx, b, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+
+	list, err := b.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, list, 1, "temporary files must not be listed")
+}
+
+func TestFile_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	b := newFileBucket(t)
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "x.attrs"} {
+		assert.Error(t, blob.WriteAll(ctx, b, key, []byte("x")), key)
+	}
+}
+
+func TestFile_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	b := newFileBucket(t, blob.SignURLs("http://localhost/blobs", []byte("secret")))
+
+	app := fiber.New()
+	app.All("/blobs/*", b.Handler())
+
+	do := func(method, rawURL, body string) *http.Response {
+		t.Helper()
+
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(method, u.RequestURI(), strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, "text/plain")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	putURL, err := b.SignedURL(ctx, "files/report 1.txt", http.MethodPut, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, putURL, "report").StatusCode)
+
+	getURL, err := b.SignedURL(ctx, "files/report 1.txt", http.MethodGet, time.Minute)
+	require.NoError(t, err)
+
+	resp := do(http.MethodGet, getURL, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain", resp.Header.Get(fiber.HeaderContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "report", string(body))
+
+	expiredURL, err := b.SignedURL(ctx, "files/report 1.txt", http.MethodGet, -time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+	}{
+		{name: "expired", method: http.MethodGet, url: expiredURL},
+		{name: "wrong method", method: http.MethodPut, url: getURL},
+		{name: "other key", method: http.MethodGet, url: strings.Replace(getURL, "report%201", "other", 1)},
+		{name: "unsigned", method: http.MethodGet, url: "http://localhost/blobs/files/report%201.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusForbidden, do(tt.method, tt.url, "").StatusCode)
+		})
+	}
+
+	_, err = newFileBucket(t).SignedURL(ctx, "k", http.MethodGet, time.Minute)
+	assert.ErrorIs(t, err, blob.ErrNotSupported)
+
+	_, err = b.SignedURL(ctx, "k", http.MethodDelete, time.Minute)
+	assert.ErrorIs(t, err, blob.ErrNotSupported)
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "devstoreaccount1")
+	t.Setenv("AZURE_STORAGE_KEY", "a2V5")
+
+	tests := []struct {
+		name    string
+		url     string
+		want    any
+		wantErr bool
+	}{
+		{name: "s3", url: "s3://uploads?endpoint=localhost:9000&secure=false", want: &blob.S3{}},
+		{name: "gcs", url: "gs://uploads", want: &blob.S3{}},
+		{name: "azure", url: "azblob://uploads?endpoint=http://127.0.0.1:10000/devstoreaccount1", want: &blob.Azure{}},
+		{name: "file", url: "file://" + t.TempDir(), want: &blob.File{}},
+		{name: "unsupported scheme", url: "ftp://uploads", wantErr: true},
+		{name: "invalid secure", url: "s3://uploads?secure=maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := blob.Open(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, b)
+			assert.NoError(t, b.Close())
+		})
+	}
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+
+	b, err := blob.NewS3("127.0.0.1:65432", "key", "secret", "uploads", blob.Secure(false), blob.Region("us-east-1"))
+	require.NoError(t, err)
+
+	// Signing is local and needs no connection.
+	u, err := b.SignedURL(ctx, "a/b.txt", http.MethodGet, time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, u, "http://127.0.0.1:65432/uploads/a/b.txt?")
+	assert.Contains(t, u, "X-Amz-Signature=")
+
+	_, err = b.SignedURL(ctx, "a/b.txt", http.MethodDelete, time.Minute)
+	assert.ErrorIs(t, err, blob.ErrNotSupported)
+
+	cctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if _, err := b.Attributes(cctx, "a/b.txt"); err == nil {
+		t.Skip("unexpected successful connection to S3")
+	} else {
+		assert.False(t, errors.Is(err, blob.ErrNotFound), "connection errors must not look like missing objects")
+	}
+
+	assert.Error(t, blob.WriteAll(cctx, b, "a/b.txt", bytes.Repeat([]byte("x"), 10)))
+}
+
+func TestAzure_SignedURL(t *testing.T) {
+	b, err := blob.NewAzure("devstoreaccount1", "a2V5", "uploads", blob.Endpoint("http://127.0.0.1:10000/devstoreaccount1/"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		method   string
+		wantPerm string
+	}{
+		{method: http.MethodGet, wantPerm: "sp=r"},
+		{method: http.MethodPut, wantPerm: "sp=cw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			u, err := b.SignedURL(context.Background(), "a/b.txt", tt.method, time.Minute)
+			require.NoError(t, err)
+			assert.Contains(t, u, "http://127.0.0.1:10000/devstoreaccount1/uploads/a%2Fb.txt?")
+			assert.Contains(t, u, tt.wantPerm)
+			assert.Contains(t, u, "sig=")
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package blob_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rdashevsky/go-pkgs/blob"
+)
+
+func ExampleOpen() {
+	dir, err := os.MkdirTemp("", "blob-example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// In production the URL comes from configuration, e.g. "s3://uploads".
+	bucket, err := blob.Open("file://" + dir)
+	if err != nil {
+		panic(err)
+	}
+	defer bucket.Close()
+
+	ctx := context.Background()
+
+	if err := blob.WriteAll(ctx, bucket, "greetings/hello.txt", []byte("hello"), blob.ContentType("text/plain")); err != nil {
+		panic(err)
+	}
+
+	data, _ := blob.ReadAll(ctx, bucket, "greetings/hello.txt")
+	attrs, _ := bucket.Attributes(ctx, "greetings/hello.txt")
+
+	fmt.Println(string(data), attrs.Size, attrs.ContentType)
+
+	// Output:
+	// hello 5 text/plain
+}
@@ -0,0 +1,418 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5" // #nosec G501 -- MD5 only computes ETags, matching S3
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	_attrsSuffix = ".attrs"
+	_tempPrefix  = ".blob-tmp-"
+)
+
+// File is a Bucket storing objects as files below a directory, for local
+// development and tests. Content type and metadata are kept in a ".attrs"
+// sidecar file next to each object.
+type File struct {
+	dir        string
+	baseURL    string
+	signingKey []byte
+}
+
+var _ Bucket = (*File)(nil)
+
+// fileAttrs is the sidecar file content.
+type fileAttrs struct {
+	ContentType string            `json:"content_type"`
+	ETag        string            `json:"etag"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// NewFile creates a Bucket storing objects below dir, creating it if needed.
+//
+// Example:
+//
+//	bucket, err := blob.NewFile("./data/uploads",
+//	    blob.SignURLs("http://localhost:8080/blobs", []byte(os.Getenv("BLOB_SIGNING_KEY"))),
+//	)
+//	server.App.All("/blobs/*", bucket.Handler())
+func NewFile(dir string, opts ...Option) (*File, error) {
+	cfg := newConfig(opts)
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("blob - NewFile - filepath.Abs: %w", err)
+	}
+
+	if err = os.MkdirAll(abs, 0o750); err != nil {
+		return nil, fmt.Errorf("blob - NewFile - os.MkdirAll: %w", err)
+	}
+
+	return &File{
+		dir:        abs,
+		baseURL:    strings.TrimSuffix(cfg.baseURL, "/"),
+		signingKey: cfg.signingKey,
+	}, nil
+}
+
+// NewReader implements Bucket.
+func (f *File) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return f.NewRangeReader(ctx, key, 0, -1)
+}
+
+// NewRangeReader implements Bucket.
+func (f *File) NewRangeReader(_ context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path) // #nosec G304 -- path is confined to the bucket directory
+	if err != nil {
+		return nil, fmt.Errorf("blob - File - NewRangeReader - os.Open: %w", fileError(err))
+	}
+
+	if offset > 0 {
+		if _, err = file.Seek(offset, io.SeekStart); err != nil {
+			_ = file.Close()
+
+			return nil, fmt.Errorf("blob - File - NewRangeReader - file.Seek: %w", err)
+		}
+	}
+
+	if length < 0 {
+		return file, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, length), file}, nil
+}
+
+// NewWriter implements Bucket. Content goes to a temporary file that is
+// renamed over the object on Close.
+func (f *File) NewWriter(ctx context.Context, key string, opts ...WriterOption) (io.WriteCloser, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("blob - File - NewWriter - os.MkdirAll: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), _tempPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("blob - File - NewWriter - os.CreateTemp: %w", err)
+	}
+
+	return &fileWriter{
+		ctx:  ctx,
+		tmp:  tmp,
+		path: path,
+		cfg:  newWriterConfig(opts),
+		md5:  md5.New(), // #nosec G401 -- not used for security
+	}, nil
+}
+
+// Attributes implements Bucket.
+func (f *File) Attributes(_ context.Context, key string) (Attributes, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return Attributes{}, err
+	}
+
+	attrs, err := f.attributes(key, path)
+	if err != nil {
+		return Attributes{}, fmt.Errorf("blob - File - Attributes: %w", err)
+	}
+
+	return attrs, nil
+}
+
+// List implements Bucket.
+func (f *File) List(_ context.Context, prefix string) ([]Attributes, error) {
+	var list []Attributes
+
+	err := filepath.WalkDir(f.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := d.Name()
+		if d.IsDir() || strings.HasSuffix(name, _attrsSuffix) || strings.HasPrefix(name, _tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.dir, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		attrs, err := f.attributes(key, path)
+		if err != nil {
+			return err
+		}
+
+		attrs.Metadata = nil
+		list = append(list, attrs)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blob - File - List - filepath.WalkDir: %w", err)
+	}
+
+	return list, nil
+}
+
+// Delete implements Bucket.
+func (f *File) Delete(_ context.Context, key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{path, path + _attrsSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("blob - File - Delete - os.Remove: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SignedURL implements Bucket. URLs are served by Handler and require the
+// bucket to be created with SignURLs.
+func (f *File) SignedURL(_ context.Context, key, method string, expiry time.Duration) (string, error) {
+	if f.signingKey == nil {
+		return "", fmt.Errorf("blob - File - SignedURL - no signing key: %w", ErrNotSupported)
+	}
+
+	if method != http.MethodGet && method != http.MethodPut {
+		return "", fmt.Errorf("blob - File - SignedURL - method %s: %w", method, ErrNotSupported)
+	}
+
+	if _, err := f.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("method", method)
+	q.Set("signature", f.sign(method, key, expires))
+
+	return f.baseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + q.Encode(), nil
+}
+
+// Close implements Bucket.
+func (f *File) Close() error {
+	return nil
+}
+
+// Handler returns a Fiber handler serving the signed URLs of the bucket: GET
+// downloads and PUT uploads the object named by the "*" route parameter. It
+// must be mounted on the path of the base URL given to SignURLs.
+//
+// Example:
+//
+//	server.App.All("/blobs/*", bucket.Handler())
+func (f *File) Handler() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key, err := url.PathUnescape(ctx.Params("*"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+
+		method := ctx.Method()
+		expires := ctx.Query("expires")
+		signature := ctx.Query("signature")
+
+		if f.signingKey == nil || ctx.Query("method") != method ||
+			!hmac.Equal([]byte(signature), []byte(f.sign(method, key, expires))) {
+			return fiber.ErrForbidden
+		}
+
+		if unix, err := strconv.ParseInt(expires, 10, 64); err != nil || time.Now().Unix() > unix {
+			return fiber.ErrForbidden
+		}
+
+		switch method {
+		case http.MethodGet:
+			attrs, err := f.Attributes(ctx.UserContext(), key)
+			if errors.Is(err, ErrNotFound) {
+				return fiber.ErrNotFound
+			}
+
+			if err != nil {
+				return err
+			}
+
+			r, err := f.NewReader(ctx.UserContext(), key)
+			if err != nil {
+				return err
+			}
+
+			ctx.Set(fiber.HeaderContentType, attrs.ContentType)
+			ctx.Set(fiber.HeaderETag, attrs.ETag)
+
+			// The stream is closed once sent.
+			return ctx.SendStream(r, int(attrs.Size))
+		case http.MethodPut:
+			contentType := ctx.Get(fiber.HeaderContentType, "application/octet-stream")
+
+			if err := WriteAll(ctx.UserContext(), f, key, ctx.Body(), ContentType(contentType)); err != nil {
+				return err
+			}
+
+			return ctx.SendStatus(fiber.StatusOK)
+		default:
+			return fiber.ErrMethodNotAllowed
+		}
+	}
+}
+
+func (f *File) sign(method, key, expires string) string {
+	mac := hmac.New(sha256.New, f.signingKey)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path returns the file path of key, rejecting keys escaping the directory.
+func (f *File) path(key string) (string, error) {
+	if key == "" || strings.HasSuffix(key, _attrsSuffix) {
+		return "", fmt.Errorf("blob - File - invalid key %q", key)
+	}
+
+	path := filepath.Join(f.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, f.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("blob - File - invalid key %q", key)
+	}
+
+	return path, nil
+}
+
+func (f *File) attributes(key, path string) (Attributes, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Attributes{}, fileError(err)
+	}
+
+	attrs := Attributes{
+		Key:         key,
+		Size:        info.Size(),
+		ContentType: "application/octet-stream",
+		ModTime:     info.ModTime(),
+	}
+
+	data, err := os.ReadFile(path + _attrsSuffix) // #nosec G304 -- path is confined to the bucket directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return attrs, nil
+	}
+
+	if err != nil {
+		return Attributes{}, err
+	}
+
+	var sidecar fileAttrs
+	if err = json.Unmarshal(data, &sidecar); err != nil {
+		return Attributes{}, err
+	}
+
+	attrs.ContentType = sidecar.ContentType
+	attrs.ETag = sidecar.ETag
+	attrs.Metadata = sidecar.Metadata
+
+	return attrs, nil
+}
+
+func fileError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	return err
+}
+
+type fileWriter struct {
+	ctx  context.Context
+	tmp  *os.File
+	path string
+	cfg  writerConfig
+	md5  hash.Hash
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	w.md5.Write(p)
+
+	return w.tmp.Write(p)
+}
+
+func (w *fileWriter) Close() error {
+	err := w.commit()
+	if err != nil {
+		_ = os.Remove(w.tmp.Name())
+	}
+
+	return err
+}
+
+func (w *fileWriter) commit() error {
+	if err := w.tmp.Close(); err != nil {
+		return fmt.Errorf("blob - File - Writer - Close - tmp.Close: %w", err)
+	}
+
+	if err := w.ctx.Err(); err != nil {
+		return fmt.Errorf("blob - File - Writer - Close: %w", err)
+	}
+
+	sidecar, err := json.Marshal(fileAttrs{
+		ContentType: w.cfg.contentType,
+		ETag:        `"` + hex.EncodeToString(w.md5.Sum(nil)) + `"`,
+		Metadata:    w.cfg.metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("blob - File - Writer - Close - json.Marshal: %w", err)
+	}
+
+	if err = os.WriteFile(w.path+_attrsSuffix, sidecar, 0o600); err != nil {
+		return fmt.Errorf("blob - File - Writer - Close - os.WriteFile: %w", err)
+	}
+
+	if err = os.Rename(w.tmp.Name(), w.path); err != nil {
+		return fmt.Errorf("blob - File - Writer - Close - os.Rename: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package blob
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// Open creates a Bucket from a URL, so that the provider is picked by
+// configuration. Credentials are read from the environment:
+//
+//   - s3://bucket?endpoint=localhost:9000&region=eu-west-1&secure=false uses
+//     AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY; the endpoint defaults to
+//     "s3.amazonaws.com".
+//   - gs://bucket uses the HMAC key in GCS_ACCESS_KEY_ID and GCS_SECRET_ACCESS_KEY.
+//   - azblob://container?endpoint=http://127.0.0.1:10000/devstoreaccount1 uses
+//     AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY.
+//   - file:///var/data/uploads stores objects below the path.
+//
+// Query parameters take precedence over the matching opts.
+//
+// Example:
+//
+//	bucket, err := blob.Open(os.Getenv("BLOB_URL"))
+func Open(rawURL string, opts ...Option) (Bucket, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("blob - Open - url.Parse: %w", err)
+	}
+
+	q := u.Query()
+
+	if v := q.Get("region"); v != "" {
+		opts = append(opts, Region(v))
+	}
+
+	if v := q.Get("secure"); v != "" {
+		secure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("blob - Open - invalid secure parameter %q", v)
+		}
+
+		opts = append(opts, Secure(secure))
+	}
+
+	switch u.Scheme {
+	case "s3":
+		endpoint := q.Get("endpoint")
+		if endpoint == "" {
+			endpoint = "s3.amazonaws.com"
+		}
+
+		return NewS3(endpoint, os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), u.Host, opts...)
+	case "gs":
+		return NewGCS(os.Getenv("GCS_ACCESS_KEY_ID"), os.Getenv("GCS_SECRET_ACCESS_KEY"), u.Host, opts...)
+	case "azblob":
+		if v := q.Get("endpoint"); v != "" {
+			opts = append(opts, Endpoint(v))
+		}
+
+		return NewAzure(os.Getenv("AZURE_STORAGE_ACCOUNT"), os.Getenv("AZURE_STORAGE_KEY"), u.Host, opts...)
+	case "file":
+		path := u.Path
+		if u.Host != "" {
+			// file://relative/dir
+			path = u.Host + u.Path
+		}
+
+		return NewFile(path, opts...)
+	default:
+		return nil, fmt.Errorf("blob - Open - unsupported scheme %q", u.Scheme)
+	}
+}
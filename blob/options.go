@@ -0,0 +1,75 @@
+package blob
+
+const (
+	_defaultPartSize = 16 << 20 // 16 MiB
+)
+
+// Option configures a Bucket driver. Options a driver does not use are ignored.
+type Option func(*config)
+
+type config struct {
+	secure     bool
+	region     string
+	partSize   uint64
+	endpoint   string
+	baseURL    string
+	signingKey []byte
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		secure:   true,
+		partSize: _defaultPartSize,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Secure enables or disables TLS for S3 and GCS connections.
+// Default is true.
+func Secure(secure bool) Option {
+	return func(c *config) {
+		c.secure = secure
+	}
+}
+
+// Region sets the S3 bucket region. Setting it avoids a region lookup request
+// before the first operation, including signing URLs.
+// Default is empty, which discovers the region automatically.
+func Region(region string) Option {
+	return func(c *config) {
+		c.region = region
+	}
+}
+
+// PartSize sets the part size of S3 multipart uploads and the block size of
+// Azure uploads. Writers buffer one part in memory.
+// Default is 16 MiB.
+func PartSize(size uint64) Option {
+	return func(c *config) {
+		c.partSize = size
+	}
+}
+
+// Endpoint sets the Azure Blob Storage service URL, e.g. the Azurite emulator
+// "http://127.0.0.1:10000/devstoreaccount1".
+// Default is "https://<account>.blob.core.windows.net".
+func Endpoint(url string) Option {
+	return func(c *config) {
+		c.endpoint = url
+	}
+}
+
+// SignURLs enables SignedURL on file buckets: URLs point below baseURL, where
+// the bucket Handler must be mounted, and are signed with key.
+// Default is disabled, SignedURL returns ErrNotSupported.
+func SignURLs(baseURL string, key []byte) Option {
+	return func(c *config) {
+		c.baseURL = baseURL
+		c.signingKey = key
+	}
+}
@@ -0,0 +1,384 @@
+// Package breaker provides a circuit breaker tripping on a failure rate over a
+// rolling window, with half-open probes, state change callbacks and metrics hooks.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultName                 = "default"
+	_defaultFailureRateThreshold = 0.5
+	_defaultMinRequests          = 10
+	_defaultWindow               = time.Minute
+	_defaultWindowBuckets        = 10
+	_defaultOpenDuration         = 30 * time.Second
+	_defaultHalfOpenRequests     = 1
+)
+
+var (
+	// ErrOpen is returned when the breaker is open and rejects calls.
+	ErrOpen = errors.New("breaker - circuit breaker is open")
+	// ErrTooManyRequests is returned when all half-open probe slots are in use.
+	ErrTooManyRequests = errors.New("breaker - too many requests in half-open state")
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets all calls through while recording their outcome.
+	StateClosed State = iota
+	// StateHalfOpen lets a limited number of probe calls through after the open duration.
+	StateHalfOpen
+	// StateOpen rejects all calls until the open duration elapses.
+	StateOpen
+)
+
+// String returns the state name.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("unknown state %d", int(s))
+	}
+}
+
+// Counts holds the call outcomes recorded in the current rolling window.
+type Counts struct {
+	Requests  int
+	Successes int
+	Failures  int
+}
+
+// FailureRate returns the ratio of failures to requests, or 0 without requests.
+func (c Counts) FailureRate() float64 {
+	if c.Requests == 0 {
+		return 0
+	}
+
+	return float64(c.Failures) / float64(c.Requests)
+}
+
+// Metrics receives breaker measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveResult is called for every call let through with its outcome.
+	ObserveResult(name string, success bool)
+	// ObserveRejection is called for every call rejected by an open or saturated breaker.
+	ObserveRejection(name string)
+	// ObserveStateChange is called on every state transition.
+	ObserveStateChange(name string, from, to State)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveResult(string, bool)              {}
+func (noopMetrics) ObserveRejection(string)                 {}
+func (noopMetrics) ObserveStateChange(string, State, State) {}
+
+type bucket struct {
+	epoch     int64
+	successes int
+	failures  int
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	name                 string
+	failureRateThreshold float64
+	minRequests          int
+	window               time.Duration
+	windowBuckets        int
+	openDuration         time.Duration
+	halfOpenRequests     int
+	isFailure            func(error) bool
+	onStateChange        func(name string, from, to State)
+	metrics              Metrics
+	now                  func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	buckets    []bucket
+	openedAt   time.Time
+	// inFlight and probeSuccesses track half-open probes.
+	inFlight       int
+	probeSuccesses int
+}
+
+// New creates a new closed Breaker.
+// Default configuration: trips when at least 50% of at least 10 calls failed over a
+// 1 minute rolling window, stays open for 30 seconds, then closes again after 1
+// successful half-open probe. Every non-nil error except context.Canceled is a failure.
+//
+// Example:
+//
+//	b := breaker.New(
+//	    breaker.Name("payments"),
+//	    breaker.FailureRateThreshold(0.3),
+//	    breaker.OpenDuration(10*time.Second),
+//	)
+//	err := b.Execute(func() error { return callPayments(ctx) })
+func New(opts ...Option) *Breaker {
+	b := &Breaker{
+		name:                 _defaultName,
+		failureRateThreshold: _defaultFailureRateThreshold,
+		minRequests:          _defaultMinRequests,
+		window:               _defaultWindow,
+		windowBuckets:        _defaultWindowBuckets,
+		openDuration:         _defaultOpenDuration,
+		halfOpenRequests:     _defaultHalfOpenRequests,
+		isFailure:            defaultIsFailure,
+		metrics:              noopMetrics{},
+		now:                  time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.minRequests < 1 {
+		b.minRequests = 1
+	}
+
+	if b.windowBuckets < 1 {
+		b.windowBuckets = 1
+	}
+
+	if b.window < time.Duration(b.windowBuckets) {
+		b.window = time.Duration(b.windowBuckets)
+	}
+
+	if b.halfOpenRequests < 1 {
+		b.halfOpenRequests = 1
+	}
+
+	b.buckets = make([]bucket, b.windowBuckets)
+
+	return b
+}
+
+func defaultIsFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// Name returns the breaker name.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Execute runs fn if the breaker allows it and records its outcome.
+// It returns ErrOpen or ErrTooManyRequests without calling fn when the call is rejected,
+// and the error of fn otherwise.
+func (b *Breaker) Execute(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			done(fmt.Errorf("breaker - panic: %v", r))
+			panic(r)
+		}
+	}()
+
+	err = fn()
+	done(err)
+
+	return err
+}
+
+// Allow checks whether a call may proceed. On success the caller must perform the call
+// and report its outcome exactly once through done; the error is classified with IsFailure.
+// It returns ErrOpen or ErrTooManyRequests when the call is rejected.
+//
+// Example:
+//
+//	done, err := b.Allow()
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := client.Do(req)
+//	done(err)
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refresh(now)
+
+	switch b.state {
+	case StateOpen:
+		b.metrics.ObserveRejection(b.name)
+
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.inFlight >= b.halfOpenRequests {
+			b.metrics.ObserveRejection(b.name)
+
+			return nil, ErrTooManyRequests
+		}
+
+		b.inFlight++
+	case StateClosed:
+	}
+
+	generation := b.generation
+
+	var once sync.Once
+
+	return func(err error) {
+		once.Do(func() {
+			b.record(generation, !b.isFailure(err))
+		})
+	}, nil
+}
+
+// State returns the current state, moving an expired open breaker to half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+
+	return b.state
+}
+
+// Counts returns the outcomes recorded in the current rolling window.
+// Counts are reset on every state change.
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.counts(b.now())
+}
+
+// Reset forces the breaker back to the closed state and clears its counts.
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.setState(StateClosed, b.now())
+}
+
+func (b *Breaker) record(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.metrics.ObserveResult(b.name, success)
+
+	// Outcomes of calls started before the last state change are ignored.
+	if generation != b.generation {
+		return
+	}
+
+	now := b.now()
+
+	switch b.state {
+	case StateClosed:
+		bk := b.bucket(now)
+		if success {
+			bk.successes++
+
+			return
+		}
+
+		bk.failures++
+
+		if c := b.counts(now); c.Requests >= b.minRequests && c.FailureRate() >= b.failureRateThreshold {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+
+		if !success {
+			b.setState(StateOpen, now)
+
+			return
+		}
+
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.halfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+	case StateOpen:
+	}
+}
+
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && !now.Before(b.openedAt.Add(b.openDuration)) {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	from := b.state
+
+	b.state = state
+	b.generation++
+	b.inFlight = 0
+	b.probeSuccesses = 0
+
+	for i := range b.buckets {
+		b.buckets[i] = bucket{}
+	}
+
+	if state == StateOpen {
+		b.openedAt = now
+	}
+
+	if from == state {
+		return
+	}
+
+	b.metrics.ObserveStateChange(b.name, from, state)
+
+	if b.onStateChange != nil {
+		b.onStateChange(b.name, from, state)
+	}
+}
+
+func (b *Breaker) epoch(now time.Time) int64 {
+	width := b.window.Nanoseconds() / int64(b.windowBuckets)
+
+	return now.UnixNano() / width
+}
+
+func (b *Breaker) bucket(now time.Time) *bucket {
+	epoch := b.epoch(now)
+	bk := &b.buckets[epoch%int64(b.windowBuckets)]
+
+	if bk.epoch != epoch {
+		*bk = bucket{epoch: epoch}
+	}
+
+	return bk
+}
+
+func (b *Breaker) counts(now time.Time) Counts {
+	epoch := b.epoch(now)
+
+	var c Counts
+
+	for _, bk := range b.buckets {
+		if bk.epoch > epoch-int64(b.windowBuckets) && bk.epoch <= epoch {
+			c.Successes += bk.successes
+			c.Failures += bk.failures
+		}
+	}
+
+	c.Requests = c.Successes + c.Failures
+
+	return c
+}
@@ -0,0 +1,265 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+)
+
+var errBoom = errors.New("boom")
+
+type transition struct {
+	from, to breaker.State
+}
+
+type recordingMetrics struct {
+	mu          sync.Mutex
+	successes   int
+	failures    int
+	rejections  int
+	transitions []transition
+}
+
+func (m *recordingMetrics) ObserveResult(_ string, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if success {
+		m.successes++
+	} else {
+		m.failures++
+	}
+}
+
+func (m *recordingMetrics) ObserveRejection(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rejections++
+}
+
+func (m *recordingMetrics) ObserveStateChange(_ string, from, to breaker.State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.transitions = append(m.transitions, transition{from, to})
+}
+
+func fail() error    { return errBoom }
+func succeed() error { return nil }
+
+func TestBreaker_Trips(t *testing.T) {
+	testCases := []struct {
+		name      string
+		calls     []func() error
+		wantState breaker.State
+	}{
+		{"all successes", []func() error{succeed, succeed, succeed, succeed}, breaker.StateClosed},
+		{"below min requests", []func() error{fail, fail, fail}, breaker.StateClosed},
+		{"below threshold", []func() error{fail, succeed, succeed, succeed}, breaker.StateClosed},
+		{"at threshold", []func() error{succeed, fail, succeed, fail}, breaker.StateOpen},
+		{"all failures", []func() error{fail, fail, fail, fail}, breaker.StateOpen},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := breaker.New(breaker.MinRequests(4), breaker.FailureRateThreshold(0.5))
+
+			for _, call := range tc.calls {
+				_ = b.Execute(call)
+			}
+
+			if b.State() != tc.wantState {
+				t.Errorf("expected %s, got %s", tc.wantState, b.State())
+			}
+		})
+	}
+}
+
+func TestBreaker_OpenRejects(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	b := breaker.New(breaker.MinRequests(1), breaker.OpenDuration(time.Hour), breaker.WithMetrics(metrics))
+
+	if err := b.Execute(fail); !errors.Is(err, errBoom) {
+		t.Fatalf("expected call error, got: %v", err)
+	}
+
+	called := false
+
+	err := b.Execute(func() error {
+		called = true
+
+		return nil
+	})
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("expected ErrOpen, got: %v", err)
+	}
+
+	if called {
+		t.Error("expected fn not to be called while open")
+	}
+
+	if metrics.failures != 1 || metrics.rejections != 1 {
+		t.Errorf("expected 1 failure and 1 rejection, got %d and %d", metrics.failures, metrics.rejections)
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	t.Run("closes after successful probes", func(t *testing.T) {
+		var transitions []transition
+
+		b := breaker.New(
+			breaker.MinRequests(1),
+			breaker.OpenDuration(10*time.Millisecond),
+			breaker.HalfOpenRequests(2),
+			breaker.OnStateChange(func(_ string, from, to breaker.State) {
+				transitions = append(transitions, transition{from, to})
+			}),
+		)
+
+		_ = b.Execute(fail)
+		time.Sleep(20 * time.Millisecond)
+
+		if b.State() != breaker.StateHalfOpen {
+			t.Fatalf("expected half-open, got %s", b.State())
+		}
+
+		done1, err := b.Allow()
+		if err != nil {
+			t.Fatalf("expected first probe to be allowed, got: %v", err)
+		}
+
+		done2, err := b.Allow()
+		if err != nil {
+			t.Fatalf("expected second probe to be allowed, got: %v", err)
+		}
+
+		if _, err := b.Allow(); !errors.Is(err, breaker.ErrTooManyRequests) {
+			t.Errorf("expected ErrTooManyRequests, got: %v", err)
+		}
+
+		done1(nil)
+		done1(errBoom) // reporting twice has no effect
+		done2(nil)
+
+		if b.State() != breaker.StateClosed {
+			t.Errorf("expected closed, got %s", b.State())
+		}
+
+		want := []transition{
+			{breaker.StateClosed, breaker.StateOpen},
+			{breaker.StateOpen, breaker.StateHalfOpen},
+			{breaker.StateHalfOpen, breaker.StateClosed},
+		}
+		if len(transitions) != len(want) {
+			t.Fatalf("expected %v, got %v", want, transitions)
+		}
+
+		for i := range want {
+			if transitions[i] != want[i] {
+				t.Errorf("transition %d: expected %v, got %v", i, want[i], transitions[i])
+			}
+		}
+	})
+
+	t.Run("reopens on failed probe", func(t *testing.T) {
+		b := breaker.New(breaker.MinRequests(1), breaker.OpenDuration(10*time.Millisecond))
+
+		_ = b.Execute(fail)
+		time.Sleep(20 * time.Millisecond)
+
+		_ = b.Execute(fail)
+
+		if b.State() != breaker.StateOpen {
+			t.Errorf("expected open, got %s", b.State())
+		}
+	})
+}
+
+func TestBreaker_Window(t *testing.T) {
+	b := breaker.New(breaker.MinRequests(2), breaker.Window(40*time.Millisecond, 4))
+
+	_ = b.Execute(fail)
+
+	if c := b.Counts(); c.Requests != 1 || c.Failures != 1 {
+		t.Errorf("unexpected counts: %+v", c)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if c := b.Counts(); c.Requests != 0 {
+		t.Errorf("expected outcomes to expire, got %+v", c)
+	}
+
+	_ = b.Execute(fail)
+
+	if b.State() != breaker.StateClosed {
+		t.Error("expected expired failure not to count towards the threshold")
+	}
+}
+
+func TestBreaker_IsFailure(t *testing.T) {
+	b := breaker.New(breaker.MinRequests(1))
+
+	_ = b.Execute(func() error { return context.Canceled })
+
+	if b.State() != breaker.StateClosed {
+		t.Error("expected context.Canceled not to count as a failure by default")
+	}
+
+	b = breaker.New(breaker.MinRequests(1), breaker.IsFailure(func(err error) bool {
+		return errors.Is(err, errBoom)
+	}))
+
+	_ = b.Execute(func() error { return errors.New("client error") })
+
+	if b.State() != breaker.StateClosed {
+		t.Error("expected custom IsFailure to be honoured")
+	}
+
+	_ = b.Execute(fail)
+
+	if b.State() != breaker.StateOpen {
+		t.Errorf("expected open, got %s", b.State())
+	}
+
+	b.Reset()
+
+	if b.State() != breaker.StateClosed || b.Counts().Requests != 0 {
+		t.Error("expected Reset to close the breaker and clear counts")
+	}
+}
+
+func TestBreaker_ExecutePanic(t *testing.T) {
+	b := breaker.New(breaker.MinRequests(1))
+
+	func() {
+		defer func() { _ = recover() }()
+
+		_ = b.Execute(func() error { panic("boom") })
+	}()
+
+	if b.State() != breaker.StateOpen {
+		t.Error("expected panic to be recorded as a failure")
+	}
+}
+
+func TestState_String(t *testing.T) {
+	testCases := map[breaker.State]string{
+		breaker.StateClosed:   "closed",
+		breaker.StateHalfOpen: "half-open",
+		breaker.StateOpen:     "open",
+		breaker.State(42):     "unknown state 42",
+	}
+
+	for state, want := range testCases {
+		if state.String() != want {
+			t.Errorf("expected %q, got %q", want, state.String())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package breaker_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+)
+
+// Example demonstrates a breaker opening after repeated failures
+func Example() {
+	b := breaker.New(
+		breaker.Name("payments"),
+		breaker.MinRequests(3),
+		breaker.OpenDuration(10*time.Second),
+		breaker.OnStateChange(func(name string, from, to breaker.State) {
+			fmt.Printf("%s: %s -> %s\n", name, from, to)
+		}),
+	)
+
+	for i := 0; i < 4; i++ {
+		err := b.Execute(func() error {
+			return errors.New("payments unavailable")
+		})
+		fmt.Println(err)
+	}
+
+	// Output:
+	// payments unavailable
+	// payments unavailable
+	// payments: closed -> open
+	// payments unavailable
+	// breaker - circuit breaker is open
+}
@@ -0,0 +1,81 @@
+package breaker
+
+import "time"
+
+// Option configures a Breaker.
+type Option func(*Breaker)
+
+// Name sets the breaker name reported to callbacks and metrics.
+// Default is "default".
+func Name(name string) Option {
+	return func(b *Breaker) {
+		b.name = name
+	}
+}
+
+// FailureRateThreshold sets the failure ratio, between 0 and 1, at which the breaker opens.
+// Default is 0.5.
+func FailureRateThreshold(threshold float64) Option {
+	return func(b *Breaker) {
+		b.failureRateThreshold = threshold
+	}
+}
+
+// MinRequests sets the minimum number of calls in the window before the failure rate is evaluated.
+// Default is 10.
+func MinRequests(n int) Option {
+	return func(b *Breaker) {
+		b.minRequests = n
+	}
+}
+
+// Window sets the rolling window over which outcomes are counted and the number of buckets
+// it is divided into; outcomes expire one bucket at a time.
+// Default is 1 minute in 10 buckets.
+func Window(window time.Duration, buckets int) Option {
+	return func(b *Breaker) {
+		b.window = window
+		b.windowBuckets = buckets
+	}
+}
+
+// OpenDuration sets how long the breaker stays open before allowing half-open probes.
+// Default is 30 seconds.
+func OpenDuration(d time.Duration) Option {
+	return func(b *Breaker) {
+		b.openDuration = d
+	}
+}
+
+// HalfOpenRequests sets the number of concurrent probes allowed in the half-open state,
+// all of which must succeed for the breaker to close.
+// Default is 1.
+func HalfOpenRequests(n int) Option {
+	return func(b *Breaker) {
+		b.halfOpenRequests = n
+	}
+}
+
+// IsFailure sets the predicate classifying call errors as failures.
+// Default treats every non-nil error except context.Canceled as a failure.
+func IsFailure(fn func(error) bool) Option {
+	return func(b *Breaker) {
+		b.isFailure = fn
+	}
+}
+
+// OnStateChange registers a callback invoked on every state transition.
+// It is called with the breaker lock held and must not call back into the breaker.
+func OnStateChange(fn func(name string, from, to State)) Option {
+	return func(b *Breaker) {
+		b.onStateChange = fn
+	}
+}
+
+// WithMetrics sets the metrics receiver.
+// Default discards all measurements.
+func WithMetrics(m Metrics) Option {
+	return func(b *Breaker) {
+		b.metrics = m
+	}
+}
@@ -0,0 +1,254 @@
+// Package cache provides a typed Cache[T] over pluggable byte backends (in-memory
+// LRU, Redis, Memcached and layered combinations), with stampede protection and
+// stale-while-revalidate semantics, decoupling caching policy from the store.
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/singleflight"
+)
+
+const (
+	_defaultTTL            = 5 * time.Minute
+	_defaultRefreshTimeout = 10 * time.Second
+)
+
+// _headerSize is the size of the envelope header holding the fresh and stale deadlines.
+const _headerSize = 16
+
+// Backend stores raw cache entries. Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the value stored under key, or nil and nil error if the key doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Loader computes the value of a missing or stale key.
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// Cache stores values of type T, JSON encoded, in a Backend.
+// It is safe for concurrent use.
+type Cache[T any] struct {
+	backend        Backend
+	ttl            time.Duration
+	staleTTL       time.Duration
+	prefix         string
+	refreshTimeout time.Duration
+	logger         logger.LoggerI
+	clock          clock.Clock
+
+	group *singleflight.Group[T]
+}
+
+// New creates a new Cache storing values in backend.
+// Default configuration: 5 minutes TTL, no stale-while-revalidate, no key prefix.
+//
+// Example:
+//
+//	users := cache.New[User](cache.NewLayered(cache.NewMemory(10_000), cache.NewRedis(r)),
+//	    cache.TTL(time.Minute),
+//	    cache.StaleTTL(10*time.Minute),
+//	    cache.Prefix("users:"),
+//	)
+//	user, err := users.GetOrSet(ctx, id, func(ctx context.Context) (User, error) {
+//	    return repo.User(ctx, id)
+//	})
+func New[T any](backend Backend, opts ...Option) *Cache[T] {
+	cfg := newConfig(opts)
+
+	return &Cache[T]{
+		backend:        backend,
+		ttl:            cfg.ttl,
+		staleTTL:       cfg.staleTTL,
+		prefix:         cfg.prefix,
+		refreshTimeout: cfg.refreshTimeout,
+		logger:         cfg.logger,
+		clock:          cfg.clock,
+		group:          singleflight.New[T](),
+	}
+}
+
+// Get returns the fresh value stored under key.
+// Returns the zero value and false if the key doesn't exist or is stale.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	e, err := c.lookup(ctx, key)
+	if err != nil {
+		return zero, false, fmt.Errorf("cache - Get - %w", err)
+	}
+
+	if e == nil || !e.fresh(c.clock.Now()) {
+		return zero, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+// Set stores value under key with the default TTL.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	return c.SetWithTTL(ctx, key, value, c.ttl)
+}
+
+// SetWithTTL stores value under key, fresh for ttl and then stale for the configured StaleTTL.
+func (c *Cache[T]) SetWithTTL(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache - SetWithTTL - json.Marshal: %w", err)
+	}
+
+	now := c.clock.Now()
+
+	if err := c.backend.Set(ctx, c.prefix+key, encode(now.Add(ttl), now.Add(ttl+c.staleTTL), data), ttl+c.staleTTL); err != nil {
+		return fmt.Errorf("cache - SetWithTTL - c.backend.Set: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the key.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	if err := c.backend.Delete(ctx, c.prefix+key); err != nil {
+		return fmt.Errorf("cache - Delete - c.backend.Delete: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrSet returns the value stored under key, calling load and storing its result
+// when the key is missing. Concurrent calls for the same key share a single load.
+// Stale values are returned immediately while a single background load refreshes
+// them. Backend errors are logged and treated as misses; load errors are returned
+// and not cached. A caller whose ctx is done stops waiting without cancelling the
+// shared load, which completes and fills the cache for the others.
+func (c *Cache[T]) GetOrSet(ctx context.Context, key string, load Loader[T]) (T, error) {
+	e, err := c.lookup(ctx, key)
+	if err != nil {
+		c.logError(err, "cache - GetOrSet - lookup")
+	}
+
+	if e != nil {
+		if e.fresh(c.clock.Now()) {
+			return e.value, nil
+		}
+
+		go c.refresh(key, load)
+
+		return e.value, nil
+	}
+
+	value, _, err := c.group.Do(ctx, key, func(ctx context.Context) (T, error) {
+		return c.load(ctx, key, load)
+	})
+	if err != nil {
+		var zero T
+
+		return zero, fmt.Errorf("cache - GetOrSet - %w", err)
+	}
+
+	return value, nil
+}
+
+func (c *Cache[T]) refresh(key string, load Loader[T]) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.refreshTimeout)
+	defer cancel()
+
+	// Background refreshes use their own key so they never block callers loading a miss.
+	_, _, err := c.group.Do(ctx, "refresh:"+key, func(ctx context.Context) (T, error) {
+		return c.load(ctx, key, load)
+	})
+	if err != nil {
+		c.logError(err, "cache - GetOrSet - refresh")
+	}
+}
+
+func (c *Cache[T]) load(ctx context.Context, key string, load Loader[T]) (T, error) {
+	v, err := load(ctx)
+	if err != nil {
+		return v, fmt.Errorf("load: %w", err)
+	}
+
+	if err := c.Set(ctx, key, v); err != nil {
+		c.logError(err, "cache - GetOrSet - store")
+	}
+
+	return v, nil
+}
+
+type entry[T any] struct {
+	value      T
+	freshUntil time.Time
+}
+
+func (e *entry[T]) fresh(now time.Time) bool {
+	return now.Before(e.freshUntil)
+}
+
+// lookup returns the fresh or stale entry stored under key, or nil.
+func (c *Cache[T]) lookup(ctx context.Context, key string) (*entry[T], error) {
+	data, err := c.backend.Get(ctx, c.prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("c.backend.Get: %w", err)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	freshUntil, staleUntil, payload, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.clock.Now().Before(staleUntil) {
+		return nil, nil
+	}
+
+	e := &entry[T]{freshUntil: freshUntil}
+
+	if err := json.Unmarshal(payload, &e.value); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return e, nil
+}
+
+func (c *Cache[T]) logError(err error, msg string) {
+	if c.logger != nil {
+		c.logger.Error(err, msg)
+	}
+}
+
+var errCorrupted = errors.New("cache - corrupted entry")
+
+// encode prepends the fresh and stale deadlines to the payload.
+func encode(freshUntil, staleUntil time.Time, payload []byte) []byte {
+	data := make([]byte, _headerSize+len(payload))
+	binary.BigEndian.PutUint64(data[0:8], uint64(freshUntil.UnixNano()))
+	binary.BigEndian.PutUint64(data[8:16], uint64(staleUntil.UnixNano()))
+	copy(data[_headerSize:], payload)
+
+	return data
+}
+
+func decode(data []byte) (freshUntil, staleUntil time.Time, payload []byte, err error) {
+	if len(data) < _headerSize {
+		return time.Time{}, time.Time{}, nil, errCorrupted
+	}
+
+	freshUntil = time.Unix(0, int64(binary.BigEndian.Uint64(data[0:8])))
+	staleUntil = time.Unix(0, int64(binary.BigEndian.Uint64(data[8:16])))
+
+	return freshUntil, staleUntil, data[_headerSize:], nil
+}
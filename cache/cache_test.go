@@ -0,0 +1,252 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/cache"
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/memcache"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCache_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	c := cache.New[user](cache.NewMemory(10), cache.Prefix("users:"))
+
+	if _, ok, err := c.Get(ctx, "1"); ok || err != nil {
+		t.Fatalf("Get() on empty cache = %v, %v; want miss", ok, err)
+	}
+
+	if err := c.Set(ctx, "1", user{ID: 1, Name: "Ann"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, ok, err := c.Get(ctx, "1")
+	if err != nil || !ok || got != (user{ID: 1, Name: "Ann"}) {
+		t.Fatalf("Get() = %+v, %v, %v; want Ann", got, ok, err)
+	}
+
+	if err := c.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, ok, _ := c.Get(ctx, "1"); ok {
+		t.Error("Get() after Delete() hit")
+	}
+}
+
+func TestCache_Expiration(t *testing.T) {
+	ctx := context.Background()
+	c := cache.New[string](cache.NewMemory(10), cache.TTL(20*time.Millisecond))
+
+	_ = c.Set(ctx, "k", "v")
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("Get() returned an expired value")
+	}
+}
+
+func TestCache_WithClock(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := cache.New[string](cache.NewMemory(10),
+		cache.TTL(time.Minute), cache.StaleTTL(time.Hour), cache.WithClock(clk))
+
+	_ = c.Set(ctx, "k", "v1")
+
+	clk.Advance(59 * time.Second)
+
+	if v, ok, _ := c.Get(ctx, "k"); !ok || v != "v1" {
+		t.Fatalf("Get() before TTL = %q, %v; want v1", v, ok)
+	}
+
+	clk.Advance(time.Second)
+
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Fatal("Get() returned a stale value")
+	}
+
+	clk.Advance(time.Hour)
+
+	v, err := c.GetOrSet(ctx, "k", func(context.Context) (string, error) { return "v2", nil })
+	if err != nil || v != "v2" {
+		t.Errorf("GetOrSet() after stale TTL = %q, %v; want a synchronous reload", v, err)
+	}
+}
+
+func TestCache_GetOrSet_Stampede(t *testing.T) {
+	c := cache.New[int](cache.NewMemory(10))
+
+	var (
+		calls atomic.Int32
+		wg    sync.WaitGroup
+	)
+
+	load := func(context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+
+		return 42, nil
+	}
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err := c.GetOrSet(context.Background(), "answer", load)
+			if err != nil || v != 42 {
+				t.Errorf("GetOrSet() = %d, %v; want 42", v, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("load called %d times, want 1", n)
+	}
+}
+
+func TestCache_GetOrSet_LoadError(t *testing.T) {
+	ctx := context.Background()
+	c := cache.New[int](cache.NewMemory(10))
+	errLoad := errors.New("db down")
+
+	if _, err := c.GetOrSet(ctx, "k", func(context.Context) (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+		t.Fatalf("GetOrSet() error = %v, want %v", err, errLoad)
+	}
+
+	v, err := c.GetOrSet(ctx, "k", func(context.Context) (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Errorf("GetOrSet() after error = %d, %v; want 7", v, err)
+	}
+}
+
+func TestCache_StaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	c := cache.New[int](cache.NewMemory(10), cache.TTL(20*time.Millisecond), cache.StaleTTL(time.Minute))
+
+	_ = c.Set(ctx, "k", 1)
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Fatal("Get() returned a stale value")
+	}
+
+	refreshed := make(chan struct{})
+
+	v, err := c.GetOrSet(ctx, "k", func(context.Context) (int, error) {
+		defer close(refreshed)
+
+		return 2, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("GetOrSet() = %d, %v; want stale value 1", v, err)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale value was not refreshed")
+	}
+
+	// The refreshed value is stored right after load returns.
+	time.Sleep(10 * time.Millisecond)
+
+	if v, ok, _ := c.Get(ctx, "k"); !ok || v != 2 {
+		t.Errorf("Get() after refresh = %d, %v; want 2", v, ok)
+	}
+}
+
+func TestMemory_Eviction(t *testing.T) {
+	ctx := context.Background()
+	m := cache.NewMemory(2)
+
+	_ = m.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = m.Set(ctx, "b", []byte("2"), time.Minute)
+	_, _ = m.Get(ctx, "a")
+	_ = m.Set(ctx, "c", []byte("3"), time.Minute)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		v, _ := m.Get(ctx, key)
+		if got := v != nil; got != want {
+			t.Errorf("key %q present = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMemoryCache_Stats(t *testing.T) {
+	ctx := context.Background()
+	local := memcache.New[string, []byte](memcache.CleanupInterval(0))
+	c := cache.New[string](cache.NewMemoryCache(local))
+
+	_ = c.Set(ctx, "k", "v")
+	_, _, _ = c.Get(ctx, "k")
+	_, _, _ = c.Get(ctx, "missing")
+
+	if stats := local.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit and 1 miss", stats)
+	}
+}
+
+func TestLayered(t *testing.T) {
+	ctx := context.Background()
+	l1, l2 := cache.NewMemory(10), cache.NewMemory(10)
+
+	// Populate the lower layer only, as another instance would.
+	_ = cache.New[string](l2).Set(ctx, "k", "v")
+
+	c := cache.New[string](cache.NewLayered(l1, l2))
+
+	if v, ok, err := c.Get(ctx, "k"); err != nil || !ok || v != "v" {
+		t.Fatalf("Get() = %q, %v, %v; want v", v, ok, err)
+	}
+
+	if v, _ := l1.Get(ctx, "k"); v == nil {
+		t.Error("upper layer was not populated")
+	}
+
+	_ = c.Delete(ctx, "k")
+
+	for i, layer := range []cache.Backend{l1, l2} {
+		if v, _ := layer.Get(ctx, "k"); v != nil {
+			t.Errorf("layer %d still holds the key after Delete()", i)
+		}
+	}
+}
+
+func TestRedis_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("redis.New() error = %v", err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	c := cache.New[string](cache.NewRedis(r))
+
+	if _, _, err := c.Get(ctx, "k"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	// GetOrSet falls back to the loader when the backend is unavailable.
+	v, err := c.GetOrSet(ctx, "k", func(context.Context) (string, error) { return "v", nil })
+	if err != nil || v != "v" {
+		t.Errorf("GetOrSet() = %q, %v; want v", v, err)
+	}
+}
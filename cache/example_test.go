@@ -0,0 +1,41 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/cache"
+)
+
+func ExampleCache_GetOrSet() {
+	ctx := context.Background()
+	prices := cache.New[float64](cache.NewMemory(1000), cache.TTL(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		price, _ := prices.GetOrSet(ctx, "BTC", func(context.Context) (float64, error) {
+			fmt.Println("loading")
+
+			return 64000.5, nil
+		})
+		fmt.Println(price)
+	}
+	// Output:
+	// loading
+	// 64000.5
+	// 64000.5
+}
+
+func ExampleNewLayered() {
+	ctx := context.Background()
+	local := cache.NewMemory(1000)
+	// In production the lower layer is shared, e.g. cache.NewRedis(r).
+	shared := cache.NewMemory(100_000)
+
+	sessions := cache.New[string](cache.NewLayered(local, shared), cache.Prefix("sessions:"))
+
+	_ = sessions.Set(ctx, "abc", "user-1")
+	userID, ok, _ := sessions.Get(ctx, "abc")
+	fmt.Println(userID, ok)
+	// Output: user-1 true
+}
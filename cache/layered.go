@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type layered struct {
+	layers []Backend
+}
+
+// NewLayered returns a Backend reading from layers in order, typically a small
+// in-memory cache in front of a shared Redis or Memcached one. Entries found in a
+// lower layer are copied to the layers above it until they expire. Set and Delete
+// apply to every layer.
+//
+// Example:
+//
+//	backend := cache.NewLayered(cache.NewMemory(1000), cache.NewRedis(r))
+func NewLayered(layers ...Backend) Backend {
+	return &layered{layers: layers}
+}
+
+func (l *layered) Get(ctx context.Context, key string) ([]byte, error) {
+	var errs []error
+
+	for i, layer := range l.layers {
+		value, err := layer.Get(ctx, key)
+		if err != nil {
+			// A failing layer is skipped so a Redis outage falls back to the lower layers.
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if _, staleUntil, _, err := decode(value); err == nil {
+			if ttl := time.Until(staleUntil); ttl > 0 {
+				for _, upper := range l.layers[:i] {
+					_ = upper.Set(ctx, key, value, ttl)
+				}
+			}
+		}
+
+		return value, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (l *layered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var errs []error
+
+	for _, layer := range l.layers {
+		if err := layer.Set(ctx, key, value, ttl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l *layered) Delete(ctx context.Context, key string) error {
+	var errs []error
+
+	for _, layer := range l.layers {
+		if err := layer.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
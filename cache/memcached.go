@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcached"
+)
+
+type memcachedBackend struct {
+	m *memcached.Memcached
+}
+
+// NewMemcached returns a Backend storing entries in Memcached.
+// Keys must be valid Memcached keys: at most 250 bytes without spaces or control characters.
+func NewMemcached(m *memcached.Memcached) Backend {
+	return &memcachedBackend{m: m}
+}
+
+func (b *memcachedBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := b.m.Get(ctx, key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+func (b *memcachedBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.m.SetWithTTL(ctx, key, string(value), ttl)
+}
+
+func (b *memcachedBackend) Delete(ctx context.Context, key string) error {
+	return b.m.Delete(ctx, key)
+}
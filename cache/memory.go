@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcache"
+)
+
+const _defaultMemorySize = 10_000
+
+type memory struct {
+	c *memcache.Cache[string, []byte]
+}
+
+// NewMemory returns an in-process Backend keeping at most size entries, evicting the
+// least recently used ones. A size below 1 uses the default of 10000 entries.
+// Expired entries are removed when looked up or evicted.
+func NewMemory(size int) Backend {
+	if size < 1 {
+		size = _defaultMemorySize
+	}
+
+	return NewMemoryCache(memcache.New[string, []byte](
+		memcache.MaxEntries(size),
+		memcache.CleanupInterval(0),
+	))
+}
+
+// NewMemoryCache returns a Backend storing entries in c, e.g. to share it or to
+// export its statistics. Closing c is left to the caller.
+//
+// Example:
+//
+//	local := memcache.New[string, []byte](memcache.MaxEntries(1000), memcache.WithMetrics(m))
+//	defer local.Close()
+//
+//	backend := cache.NewLayered(cache.NewMemoryCache(local), cache.NewRedis(r))
+func NewMemoryCache(c *memcache.Cache[string, []byte]) Backend {
+	return &memory{c: c}
+}
+
+func (m *memory) Get(_ context.Context, key string) ([]byte, error) {
+	value, _ := m.c.Get(key)
+
+	return value, nil
+}
+
+func (m *memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	// memcache keeps entries without a TTL forever, while a backend entry without
+	// a TTL is already expired.
+	if ttl <= 0 {
+		m.c.Delete(key)
+
+		return nil
+	}
+
+	m.c.SetWithTTL(key, value, ttl)
+
+	return nil
+}
+
+func (m *memory) Delete(_ context.Context, key string) error {
+	m.c.Delete(key)
+
+	return nil
+}
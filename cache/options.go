@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option configures a Cache.
+type Option func(*config)
+
+type config struct {
+	ttl            time.Duration
+	staleTTL       time.Duration
+	prefix         string
+	refreshTimeout time.Duration
+	logger         logger.LoggerI
+	clock          clock.Clock
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		ttl:            _defaultTTL,
+		refreshTimeout: _defaultRefreshTimeout,
+		clock:          clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// TTL sets how long values stay fresh.
+// Default is 5 minutes.
+func TTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// StaleTTL keeps values for staleTTL after they expire. GetOrSet returns stale values
+// immediately and refreshes them in the background (stale-while-revalidate).
+// Default is 0, expired values are reloaded synchronously.
+func StaleTTL(staleTTL time.Duration) Option {
+	return func(c *config) {
+		c.staleTTL = staleTTL
+	}
+}
+
+// Prefix sets the prefix added to every key, to share a backend between caches.
+// Default is no prefix.
+func Prefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// RefreshTimeout bounds background refreshes of stale values.
+// Default is 10 seconds.
+func RefreshTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.refreshTimeout = timeout
+	}
+}
+
+// WithLogger logs backend and background refresh errors, which GetOrSet otherwise ignores.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithClock sets the clock deciding when values become stale and expire,
+// e.g. a *clock.Fake to test TTLs without sleeping.
+// Default is clock.Real().
+func WithClock(c clock.Clock) Option {
+	return func(cfg *config) {
+		if c != nil {
+			cfg.clock = c
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+type redisBackend struct {
+	r *redis.Redis
+}
+
+// NewRedis returns a Backend storing entries in Redis.
+func NewRedis(r *redis.Redis) Backend {
+	return &redisBackend{r: r}
+}
+
+func (b *redisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := b.r.Get(ctx, key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+func (b *redisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.r.SetWithTTL(ctx, key, string(value), ttl)
+}
+
+func (b *redisBackend) Delete(ctx context.Context, key string) error {
+	_, err := b.r.Del(ctx, key)
+
+	return err
+}
@@ -0,0 +1,73 @@
+// Package clock provides a time abstraction (Now, Sleep, timers and tickers behind
+// the Clock interface) with a controllable Fake implementation, so that TTL, retry
+// and scheduling logic can be unit-tested deterministically without sleeps.
+package clock
+
+import "time"
+
+// Clock tells the time and creates timers. Implementations must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// Sleep pauses the current goroutine for at least the duration d.
+	Sleep(d time.Duration)
+	// NewTimer creates a Timer that sends the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a Ticker sending the current time on its channel every period d.
+	// It panics if d <= 0.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the Clock counterpart of *time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+	// Reset changes the timer to expire after duration d. It returns true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the Clock counterpart of *time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker. No more ticks will be sent after Stop returns.
+	Stop()
+	// Reset stops the ticker and resets its period to d. It panics if d <= 0.
+	Reset(d time.Duration)
+}
+
+// Real returns the Clock backed by the time package.
+//
+// Example:
+//
+//	retry.Do(ctx, fn, retry.WithClock(clock.Real()))
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
@@ -0,0 +1,203 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake_NowAndAdvance(t *testing.T) {
+	c := clock.NewFake(epoch)
+
+	c.Advance(time.Minute)
+
+	if got := c.Now(); !got.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("Now() = %v, want %v", got, epoch.Add(time.Minute))
+	}
+
+	if got := c.Since(epoch); got != time.Minute {
+		t.Errorf("Since() = %v, want 1m", got)
+	}
+
+	c.Advance(-time.Hour)
+	c.Set(epoch)
+
+	if got := c.Now(); !got.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("Now() after moving backwards = %v, want unchanged", got)
+	}
+}
+
+func TestFake_Timer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		delay   time.Duration
+		advance time.Duration
+		fired   bool
+	}{
+		{"before deadline", time.Second, 999 * time.Millisecond, false},
+		{"at deadline", time.Second, time.Second, true},
+		{"after deadline", time.Second, time.Hour, true},
+		{"zero delay", 0, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clock.NewFake(epoch)
+			timer := c.NewTimer(tc.delay)
+
+			c.Advance(tc.advance)
+
+			got, ok := received(timer.C())
+			if ok != tc.fired {
+				t.Fatalf("fired = %v, want %v", ok, tc.fired)
+			}
+
+			if ok && !got.Equal(epoch.Add(tc.delay)) {
+				t.Errorf("fired at %v, want %v", got, epoch.Add(tc.delay))
+			}
+		})
+	}
+}
+
+func TestFake_TimerStopReset(t *testing.T) {
+	c := clock.NewFake(epoch)
+	timer := c.NewTimer(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Stop() on active timer = false")
+	}
+
+	c.Advance(time.Second)
+
+	if _, ok := received(timer.C()); ok {
+		t.Error("stopped timer fired")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Reset() on stopped timer = true")
+	}
+
+	c.Advance(time.Second)
+
+	if _, ok := received(timer.C()); !ok {
+		t.Error("reset timer did not fire")
+	}
+
+	if timer.Stop() {
+		t.Error("Stop() on fired timer = true")
+	}
+}
+
+func TestFake_Ticker(t *testing.T) {
+	c := clock.NewFake(epoch)
+	ticker := c.NewTicker(time.Second)
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Second)
+
+		got, ok := received(ticker.C())
+		if !ok || !got.Equal(epoch.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("tick %d = %v, %v", i, got, ok)
+		}
+	}
+
+	// Like time.Ticker, ticks are dropped for a slow receiver.
+	c.Advance(5 * time.Second)
+
+	if _, ok := received(ticker.C()); !ok {
+		t.Fatal("expected a tick")
+	}
+
+	if _, ok := received(ticker.C()); ok {
+		t.Error("expected missed ticks to be dropped")
+	}
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Second)
+
+	if _, ok := received(ticker.C()); ok {
+		t.Error("ticker fired before its new period")
+	}
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+
+	if _, ok := received(ticker.C()); ok {
+		t.Error("stopped ticker fired")
+	}
+}
+
+func TestFake_SleepAndBlockUntil(t *testing.T) {
+	c := clock.NewFake(epoch)
+	done := make(chan struct{})
+
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+
+	c.BlockUntil(1)
+
+	if n := c.Waiters(); n != 1 {
+		t.Fatalf("Waiters() = %d, want 1", n)
+	}
+
+	c.Advance(time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sleep did not return after Advance")
+	}
+
+	if n := c.Waiters(); n != 0 {
+		t.Errorf("Waiters() = %d, want 0", n)
+	}
+}
+
+func TestFake_FiresInDeadlineOrder(t *testing.T) {
+	c := clock.NewFake(epoch)
+	late := c.NewTimer(2 * time.Second)
+	early := c.NewTimer(time.Second)
+
+	c.Advance(3 * time.Second)
+
+	e, _ := received(early.C())
+	l, _ := received(late.C())
+
+	if !e.Before(l) {
+		t.Errorf("early fired at %v, late at %v", e, l)
+	}
+}
+
+func TestReal(t *testing.T) {
+	c := clock.Real()
+
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+
+	if c.Since(start) < time.Millisecond {
+		t.Error("Sleep returned early")
+	}
+
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	<-c.After(time.Millisecond)
+}
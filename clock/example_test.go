@@ -0,0 +1,23 @@
+package clock_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+func ExampleNewFake() {
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	timer := c.NewTimer(time.Hour)
+
+	c.Advance(30 * time.Minute)
+	fmt.Println("after 30m:", len(timer.C()))
+
+	c.Advance(30 * time.Minute)
+	fmt.Println("fired at:", (<-timer.C()).Format(time.TimeOnly))
+
+	// Output:
+	// after 30m: 0
+	// fired at: 01:00:00
+}
@@ -0,0 +1,250 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance or Set is called.
+// Timers, tickers, After and Sleep fire synchronously from Advance once their deadline
+// is reached, in deadline order. It is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// NewFake creates a Fake clock set to now.
+//
+// Example:
+//
+//	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	go func() { _ = retry.Do(ctx, fn, retry.WithClock(c)) }()
+//	c.BlockUntil(1) // wait for the backoff timer
+//	c.Advance(100 * time.Millisecond)
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel receiving the fake time once it has advanced by d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// Sleep blocks until the fake time has advanced by d.
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// NewTimer creates a Timer firing once the fake time has advanced by d.
+// A timer with d <= 0 fires immediately.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	w := &fakeWaiter{fake: f, ch: make(chan time.Time, 1)}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.schedule(w, d)
+
+	return w
+}
+
+// NewTicker creates a Ticker firing every time the fake time advances by d.
+// It panics if d <= 0.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock - Fake - NewTicker - non-positive interval")
+	}
+
+	w := &fakeWaiter{fake: f, ch: make(chan time.Time, 1), period: d}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.schedule(w, d)
+
+	return fakeTicker{w}
+}
+
+// Advance moves the fake time forward by d, firing every timer and ticker whose
+// deadline is reached along the way. Negative durations are ignored.
+func (f *Fake) Advance(d time.Duration) {
+	if d < 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+
+	for len(f.waiters) > 0 && !f.waiters[0].at.After(end) {
+		w := f.waiters[0]
+		f.waiters = f.waiters[1:]
+
+		f.now = w.at
+		w.fire(f.now)
+
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+			f.insert(w)
+		}
+	}
+
+	f.now = end
+}
+
+// Set moves the fake time to t, firing due timers and tickers.
+// Times before the current fake time are ignored.
+func (f *Fake) Set(t time.Time) {
+	f.Advance(t.Sub(f.Now()))
+}
+
+// Waiters returns the number of active timers, tickers and pending After and Sleep calls.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until at least n timers, tickers, After or Sleep calls are active.
+// It lets tests wait for the code under test to start waiting before calling Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// schedule activates w to fire after d. The caller must hold f.mu.
+func (f *Fake) schedule(w *fakeWaiter, d time.Duration) {
+	w.at = f.now.Add(d)
+
+	if d <= 0 && w.period == 0 {
+		w.fire(f.now)
+
+		return
+	}
+
+	f.insert(w)
+	f.cond.Broadcast()
+}
+
+// insert adds w keeping waiters sorted by deadline, in creation order for equal deadlines.
+// The caller must hold f.mu.
+func (f *Fake) insert(w *fakeWaiter) {
+	i := sort.Search(len(f.waiters), func(i int) bool {
+		return f.waiters[i].at.After(w.at)
+	})
+
+	f.waiters = append(f.waiters, nil)
+	copy(f.waiters[i+1:], f.waiters[i:])
+	f.waiters[i] = w
+}
+
+// remove deactivates w, reporting whether it was active. The caller must hold f.mu.
+func (f *Fake) remove(w *fakeWaiter) bool {
+	for i, v := range f.waiters {
+		if v == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// fakeWaiter implements both Timer and Ticker; period is zero for timers.
+type fakeWaiter struct {
+	fake   *Fake
+	ch     chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+// fire delivers t without blocking, dropping it if the previous value was not received,
+// like a time.Ticker does for slow receivers.
+func (w *fakeWaiter) fire(t time.Time) {
+	select {
+	case w.ch <- t:
+	default:
+	}
+}
+
+// drain discards an undelivered value, so that no stale time is received after Stop
+// or Reset, matching the time package since Go 1.23.
+func (w *fakeWaiter) drain() {
+	select {
+	case <-w.ch:
+	default:
+	}
+}
+
+// Stop implements Timer.Stop.
+func (w *fakeWaiter) Stop() bool {
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+
+	w.drain()
+
+	return w.fake.remove(w)
+}
+
+// Reset implements Timer.Reset.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+
+	w.drain()
+	active := w.fake.remove(w)
+	w.fake.schedule(w, d)
+
+	return active
+}
+
+// fakeTicker adapts fakeWaiter to the Ticker interface, whose Stop and Reset return nothing.
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t fakeTicker) Stop() { t.fakeWaiter.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock - Fake - Ticker.Reset - non-positive interval")
+	}
+
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	t.drain()
+	t.fake.remove(t.fakeWaiter)
+	t.period = d
+	t.fake.schedule(t.fakeWaiter, d)
+}
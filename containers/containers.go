@@ -0,0 +1,227 @@
+// Package containers starts Postgres, Redis, RabbitMQ and Kafka in Docker with
+// testcontainers for integration tests and returns ready-wired go-pkgs clients.
+// Containers are terminated when the test ends. Tests are skipped when Docker is
+// not available or when running with -short.
+package containers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/kafka"
+	"github.com/rdashevsky/go-pkgs/postgres"
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/testcontainers/testcontainers-go"
+	tckafka "github.com/testcontainers/testcontainers-go/modules/kafka"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcrabbitmq "github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+const (
+	_defaultPostgresImage  = "postgres:16-alpine"
+	_defaultRedisImage     = "redis:7-alpine"
+	_defaultRabbitMQImage  = "rabbitmq:3.13-management-alpine"
+	_defaultKafkaImage     = "confluentinc/confluent-local:7.5.0"
+	_defaultStartupTimeout = 2 * time.Minute
+
+	_postgresDatabase = "test"
+	_postgresUser     = "test"
+	_postgresPassword = "test"
+)
+
+// Postgres starts a Postgres container and returns a connected client,
+// closed when the test ends.
+//
+// Example:
+//
+//	func TestRepository(t *testing.T) {
+//	    pg := containers.Postgres(t)
+//	    repo := NewRepository(pg)
+//	    ...
+//	}
+func Postgres(tb testing.TB, opts ...Option) *postgres.Postgres {
+	tb.Helper()
+
+	pg, err := postgres.New(PostgresURL(tb, opts...))
+	if err != nil {
+		tb.Fatalf("containers - Postgres - postgres.New: %v", err)
+	}
+
+	tb.Cleanup(pg.Close)
+
+	return pg
+}
+
+// PostgresURL starts a Postgres container and returns its connection URL,
+// e.g. to run migrations with the goose package.
+func PostgresURL(tb testing.TB, opts ...Option) string {
+	tb.Helper()
+	RequireDocker(tb)
+
+	cfg := newConfig(_defaultPostgresImage, opts)
+	ctx, cancel := cfg.context()
+	defer cancel()
+
+	c, err := tcpostgres.Run(ctx, cfg.image, append([]testcontainers.ContainerCustomizer{
+		tcpostgres.WithDatabase(_postgresDatabase),
+		tcpostgres.WithUsername(_postgresUser),
+		tcpostgres.WithPassword(_postgresPassword),
+		tcpostgres.BasicWaitStrategies(),
+	}, cfg.customizers...)...)
+	testcontainers.CleanupContainer(tb, c)
+
+	if err != nil {
+		tb.Fatalf("containers - PostgresURL - tcpostgres.Run: %v", err)
+	}
+
+	url, err := c.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		tb.Fatalf("containers - PostgresURL - c.ConnectionString: %v", err)
+	}
+
+	return url
+}
+
+// Redis starts a Redis container and returns a connected client,
+// closed when the test ends.
+func Redis(tb testing.TB, opts ...Option) *redis.Redis {
+	tb.Helper()
+
+	r, err := redis.New(RedisAddr(tb, opts...), "", "")
+	if err != nil {
+		tb.Fatalf("containers - Redis - redis.New: %v", err)
+	}
+
+	tb.Cleanup(r.Close)
+
+	return r
+}
+
+// RedisAddr starts a Redis container and returns its "host:port" address.
+func RedisAddr(tb testing.TB, opts ...Option) string {
+	tb.Helper()
+	RequireDocker(tb)
+
+	cfg := newConfig(_defaultRedisImage, opts)
+	ctx, cancel := cfg.context()
+	defer cancel()
+
+	c, err := tcredis.Run(ctx, cfg.image, cfg.customizers...)
+	testcontainers.CleanupContainer(tb, c)
+
+	if err != nil {
+		tb.Fatalf("containers - RedisAddr - tcredis.Run: %v", err)
+	}
+
+	addr, err := c.Endpoint(ctx, "")
+	if err != nil {
+		tb.Fatalf("containers - RedisAddr - c.Endpoint: %v", err)
+	}
+
+	return addr
+}
+
+// RabbitMQ starts a RabbitMQ container and returns a connection Config for the
+// rabbitmq, rabbitmq/server, rabbitmq/client and eventbus packages.
+//
+// Example:
+//
+//	cfg := containers.RabbitMQ(t)
+//	bus, err := eventbus.NewRabbitMQ(cfg, "events")
+func RabbitMQ(tb testing.TB, opts ...Option) rabbitmq.Config {
+	tb.Helper()
+	RequireDocker(tb)
+
+	cfg := newConfig(_defaultRabbitMQImage, opts)
+	ctx, cancel := cfg.context()
+	defer cancel()
+
+	c, err := tcrabbitmq.Run(ctx, cfg.image, cfg.customizers...)
+	testcontainers.CleanupContainer(tb, c)
+
+	if err != nil {
+		tb.Fatalf("containers - RabbitMQ - tcrabbitmq.Run: %v", err)
+	}
+
+	url, err := c.AmqpURL(ctx)
+	if err != nil {
+		tb.Fatalf("containers - RabbitMQ - c.AmqpURL: %v", err)
+	}
+
+	return rabbitmq.Config{
+		URL:      url,
+		WaitTime: time.Second,
+		Attempts: 5,
+	}
+}
+
+// Kafka starts a single node Kafka container in KRaft mode and returns a connection
+// Config for the kafka, kafka/server, kafka/client and eventbus packages.
+func Kafka(tb testing.TB, opts ...Option) kafka.Config {
+	tb.Helper()
+	RequireDocker(tb)
+
+	cfg := newConfig(_defaultKafkaImage, opts)
+	ctx, cancel := cfg.context()
+	defer cancel()
+
+	c, err := tckafka.Run(ctx, cfg.image, append([]testcontainers.ContainerCustomizer{
+		tckafka.WithClusterID("go-pkgs"),
+	}, cfg.customizers...)...)
+	testcontainers.CleanupContainer(tb, c)
+
+	if err != nil {
+		tb.Fatalf("containers - Kafka - tckafka.Run: %v", err)
+	}
+
+	brokers, err := c.Brokers(ctx)
+	if err != nil {
+		tb.Fatalf("containers - Kafka - c.Brokers: %v", err)
+	}
+
+	return kafka.Config{
+		Brokers:    brokers,
+		Timeout:    10 * time.Second,
+		RetryDelay: time.Second,
+		MaxRetries: 5,
+		ClientID:   "go-pkgs-test",
+	}
+}
+
+// RequireDocker skips the test when running with -short or when Docker is not available.
+// It is called by every container helper.
+func RequireDocker(tb testing.TB) {
+	tb.Helper()
+
+	if testing.Short() {
+		tb.Skip("containers - skipping integration test in short mode")
+	}
+
+	if err := dockerHealth(); err != nil {
+		tb.Skipf("containers - Docker is not available: %v", err)
+	}
+}
+
+func dockerHealth() (err error) {
+	// The Docker client panics on some invalid environments instead of returning an error.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return provider.Health(ctx)
+}
@@ -0,0 +1,53 @@
+package containers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/containers"
+	"github.com/rdashevsky/go-pkgs/eventbus"
+)
+
+func TestPostgres(t *testing.T) {
+	pg := containers.Postgres(t)
+
+	var one int
+	if err := pg.Pool.QueryRow(context.Background(), "SELECT 1").Scan(&one); err != nil || one != 1 {
+		t.Errorf("SELECT 1 = %d, %v", one, err)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	r := containers.Redis(t)
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if value, err := r.Get(ctx, "key"); err != nil || value != "value" {
+		t.Errorf("Get() = %q, %v, want value", value, err)
+	}
+}
+
+func TestRabbitMQ(t *testing.T) {
+	bus, err := eventbus.NewRabbitMQ(containers.RabbitMQ(t), "events")
+	if err != nil {
+		t.Fatalf("eventbus.NewRabbitMQ() error = %v", err)
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestKafka(t *testing.T) {
+	bus, err := eventbus.NewKafka(containers.Kafka(t))
+	if err != nil {
+		t.Fatalf("eventbus.NewKafka() error = %v", err)
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package containers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/containers"
+)
+
+func ExamplePostgres() {
+	testUsers := func(t *testing.T) {
+		pg := containers.Postgres(t)
+
+		if _, err := pg.Pool.Exec(context.Background(), "CREATE TABLE users (id BIGSERIAL PRIMARY KEY, name TEXT)"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_ = testUsers
+}
@@ -0,0 +1,60 @@
+package containers
+
+import (
+	"context"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Option configures a container.
+type Option func(*config)
+
+type config struct {
+	image          string
+	startupTimeout time.Duration
+	customizers    []testcontainers.ContainerCustomizer
+}
+
+func newConfig(image string, opts []Option) config {
+	cfg := config{
+		image:          image,
+		startupTimeout: _defaultStartupTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+func (c config) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.startupTimeout)
+}
+
+// Image sets the Docker image of the container.
+// Defaults are postgres:16-alpine, redis:7-alpine, rabbitmq:3.13-management-alpine
+// and confluentinc/confluent-local:7.5.0.
+func Image(image string) Option {
+	return func(c *config) {
+		c.image = image
+	}
+}
+
+// StartupTimeout bounds the time to pull, start and wait for the container.
+// Default is 2 minutes.
+func StartupTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.startupTimeout = timeout
+	}
+}
+
+// Customize passes testcontainers customizers to the container request,
+// e.g. tcpostgres.WithInitScripts.
+// Default is no customization.
+func Customize(customizers ...testcontainers.ContainerCustomizer) Option {
+	return func(c *config) {
+		c.customizers = append(c.customizers, customizers...)
+	}
+}
@@ -0,0 +1,57 @@
+// Package cryptoutil provides encryption and hashing helpers behind a stable API:
+// AES-256-GCM envelope encryption with versioned keys and rotation (Keyring), HMAC
+// signing, constant-time comparison, random tokens and password hashing with
+// argon2id or bcrypt (PasswordHasher).
+package cryptoutil
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidCiphertext is returned when decrypting malformed or tampered data,
+	// or data encrypted with other associated data.
+	ErrInvalidCiphertext = errors.New("cryptoutil - invalid ciphertext")
+	// ErrUnknownKey is returned when decrypting data encrypted with a key missing
+	// from the keyring.
+	ErrUnknownKey = errors.New("cryptoutil - unknown key")
+	// ErrInvalidHash is returned when verifying a password against a malformed or
+	// unsupported hash.
+	ErrInvalidHash = errors.New("cryptoutil - invalid password hash")
+)
+
+// RandomBytes returns n cryptographically secure random bytes.
+func RandomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("cryptoutil - RandomBytes - rand.Read: %w", err)
+	}
+
+	return b, nil
+}
+
+// RandomToken returns a URL-safe token encoding n random bytes, e.g. for API keys,
+// reset links or session IDs. 32 bytes give 256 bits of entropy.
+func RandomToken(n int) (string, error) {
+	b, err := RandomBytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Equal reports whether a and b are equal in constant time, so comparing secrets
+// such as tokens or signatures does not leak their content through timing.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString is Equal for strings.
+func EqualString(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,266 @@
+package cryptoutil_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/cryptoutil"
+)
+
+func newKeyring(t *testing.T, primary string, ids ...string) (*cryptoutil.Keyring, map[string][]byte) {
+	t.Helper()
+
+	keys := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		key, err := cryptoutil.GenerateKey()
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+
+		keys[id] = key
+	}
+
+	kr, err := cryptoutil.NewKeyring(primary, keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	return kr, keys
+}
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("base64.StdEncoding.DecodeString() error = %v", err)
+	}
+
+	return b
+}
+
+func TestNewKeyring(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, cryptoutil.KeySize)
+
+	tests := []struct {
+		name    string
+		primary string
+		keys    map[string][]byte
+		wantErr bool
+	}{
+		{name: "valid", primary: "v1", keys: map[string][]byte{"v1": key}},
+		{name: "missing primary", primary: "v2", keys: map[string][]byte{"v1": key}, wantErr: true},
+		{name: "short key", primary: "v1", keys: map[string][]byte{"v1": key[:16]}, wantErr: true},
+		{name: "empty key ID", primary: "v1", keys: map[string][]byte{"v1": key, "": key}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cryptoutil.NewKeyring(tt.primary, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	kr, _ := newKeyring(t, "v1", "v1")
+
+	ciphertext, err := kr.Encrypt([]byte("123-45-6789"), []byte("user-1"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+		ad         string
+		wantErr    error
+	}{
+		{name: "valid", ciphertext: ciphertext, ad: "user-1"},
+		{name: "other associated data", ciphertext: ciphertext, ad: "user-2", wantErr: cryptoutil.ErrInvalidCiphertext},
+		{name: "tampered", ciphertext: tampered, ad: "user-1", wantErr: cryptoutil.ErrInvalidCiphertext},
+		{name: "truncated", ciphertext: ciphertext[:10], ad: "user-1", wantErr: cryptoutil.ErrInvalidCiphertext},
+		{name: "empty", ad: "user-1", wantErr: cryptoutil.ErrInvalidCiphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := kr.Decrypt(tt.ciphertext, []byte(tt.ad))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decrypt() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && string(plaintext) != "123-45-6789" {
+				t.Errorf("Decrypt() = %q, want %q", plaintext, "123-45-6789")
+			}
+		})
+	}
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	old, keys := newKeyring(t, "v1", "v1")
+
+	ciphertext, err := old.EncryptString("secret", "row-1")
+	if err != nil {
+		t.Fatalf("EncryptString() error = %v", err)
+	}
+
+	keys["v2"], _ = cryptoutil.GenerateKey()
+
+	kr, err := cryptoutil.NewKeyring("v2", keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	raw, err := kr.Decrypt(mustDecodeBase64(t, ciphertext), []byte("row-1"))
+	if err != nil || string(raw) != "secret" {
+		t.Fatalf("Decrypt() with an older key = %q, %v, want %q", raw, err, "secret")
+	}
+
+	if !kr.NeedsRotation(mustDecodeBase64(t, ciphertext)) {
+		t.Error("NeedsRotation() = false for a ciphertext of v1, want true")
+	}
+
+	rotated, err := kr.Rotate(mustDecodeBase64(t, ciphertext))
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	if id, _ := kr.KeyID(rotated); id != "v2" {
+		t.Errorf("KeyID() after Rotate = %q, want %q", id, "v2")
+	}
+
+	if kr.NeedsRotation(rotated) {
+		t.Error("NeedsRotation() = true after Rotate, want false")
+	}
+
+	delete(keys, "v1")
+
+	current, err := cryptoutil.NewKeyring("v2", keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	if plaintext, err := current.Decrypt(rotated, []byte("row-1")); err != nil || string(plaintext) != "secret" {
+		t.Errorf("Decrypt() after removing v1 = %q, %v, want %q", plaintext, err, "secret")
+	}
+
+	if _, err := current.Decrypt(mustDecodeBase64(t, ciphertext), []byte("row-1")); !errors.Is(err, cryptoutil.ErrUnknownKey) {
+		t.Errorf("Decrypt() of a v1 ciphertext error = %v, want %v", err, cryptoutil.ErrUnknownKey)
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	key := []byte("webhook-secret")
+	signature := cryptoutil.SignString(key, "payload")
+
+	tests := []struct {
+		name      string
+		key       []byte
+		data      string
+		signature string
+		want      bool
+	}{
+		{name: "valid", key: key, data: "payload", signature: signature, want: true},
+		{name: "other data", key: key, data: "payload2", signature: signature},
+		{name: "other key", key: []byte("other"), data: "payload", signature: signature},
+		{name: "malformed signature", key: key, data: "payload", signature: "%%%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cryptoutil.VerifyString(tt.key, tt.data, tt.signature); got != tt.want {
+				t.Errorf("VerifyString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomToken(t *testing.T) {
+	a, err := cryptoutil.RandomToken(32)
+	if err != nil {
+		t.Fatalf("RandomToken() error = %v", err)
+	}
+
+	b, _ := cryptoutil.RandomToken(32)
+
+	if len(a) != 43 || a == b || strings.ContainsAny(a, "+/=") {
+		t.Errorf("RandomToken() = %q, %q, want distinct 43-character URL-safe tokens", a, b)
+	}
+
+	if !cryptoutil.EqualString(a, a) || cryptoutil.EqualString(a, b) {
+		t.Error("EqualString() does not compare tokens")
+	}
+}
+
+func TestPasswordHasher(t *testing.T) {
+	fastArgon2 := cryptoutil.Argon2Params(1024, 1, 1)
+
+	tests := []struct {
+		name string
+		opts []cryptoutil.Option
+	}{
+		{name: "argon2id", opts: []cryptoutil.Option{fastArgon2}},
+		{name: "bcrypt", opts: []cryptoutil.Option{cryptoutil.WithAlgorithm(cryptoutil.Bcrypt), cryptoutil.BcryptCost(4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := cryptoutil.NewPasswordHasher(tt.opts...)
+
+			hash, err := h.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			if ok, err := h.Verify("correct horse", hash); !ok || err != nil {
+				t.Errorf("Verify() of the password = %v, %v, want true", ok, err)
+			}
+
+			if ok, err := h.Verify("wrong horse", hash); ok || err != nil {
+				t.Errorf("Verify() of another password = %v, %v, want false", ok, err)
+			}
+
+			if h.NeedsRehash(hash) {
+				t.Error("NeedsRehash() = true for a fresh hash, want false")
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_Upgrade(t *testing.T) {
+	legacy := cryptoutil.NewPasswordHasher(cryptoutil.WithAlgorithm(cryptoutil.Bcrypt), cryptoutil.BcryptCost(4))
+	h := cryptoutil.NewPasswordHasher(cryptoutil.Argon2Params(1024, 1, 1))
+
+	hash, err := legacy.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if ok, err := h.Verify("secret", hash); !ok || err != nil {
+		t.Errorf("Verify() of a bcrypt hash = %v, %v, want true", ok, err)
+	}
+
+	if !h.NeedsRehash(hash) {
+		t.Error("NeedsRehash() of a bcrypt hash = false, want true")
+	}
+
+	argonHash, _ := h.Hash("secret")
+	if !cryptoutil.NewPasswordHasher(cryptoutil.Argon2Params(2048, 1, 1)).NeedsRehash(argonHash) {
+		t.Error("NeedsRehash() with other argon2 parameters = false, want true")
+	}
+
+	for _, malformed := range []string{"", "plain", "$argon2id$v=19$m=1024$salt$key", "$2a$04$short"} {
+		if _, err := h.Verify("secret", malformed); !errors.Is(err, cryptoutil.ErrInvalidHash) {
+			t.Errorf("Verify(%q) error = %v, want %v", malformed, err, cryptoutil.ErrInvalidHash)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cryptoutil_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/cryptoutil"
+)
+
+func ExampleKeyring() {
+	v1 := bytes.Repeat([]byte{1}, cryptoutil.KeySize)
+	v2 := bytes.Repeat([]byte{2}, cryptoutil.KeySize)
+
+	old, _ := cryptoutil.NewKeyring("v1", map[string][]byte{"v1": v1})
+	ciphertext, _ := old.Encrypt([]byte("123-45-6789"), []byte("user-1"))
+
+	// v2 becomes the primary key; v1 stays until existing data is rotated.
+	kr, _ := cryptoutil.NewKeyring("v2", map[string][]byte{"v1": v1, "v2": v2})
+
+	if kr.NeedsRotation(ciphertext) {
+		ciphertext, _ = kr.Rotate(ciphertext)
+	}
+
+	id, _ := kr.KeyID(ciphertext)
+	plaintext, _ := kr.Decrypt(ciphertext, []byte("user-1"))
+	fmt.Println(id, string(plaintext))
+
+	// Output: v2 123-45-6789
+}
+
+func ExampleSignString() {
+	signature := cryptoutil.SignString([]byte("secret"), "payload")
+
+	fmt.Println(cryptoutil.VerifyString([]byte("secret"), "payload", signature))
+
+	// Output: true
+}
@@ -0,0 +1,37 @@
+package cryptoutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Sign returns the HMAC-SHA256 of data with key.
+func Sign(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// Verify reports whether signature is the HMAC-SHA256 of data with key, in constant time.
+func Verify(key, data, signature []byte) bool {
+	return hmac.Equal(Sign(key, data), signature)
+}
+
+// SignString returns the URL-safe base64 HMAC-SHA256 of data with key, e.g. for
+// signed URLs or webhook signature headers.
+func SignString(key []byte, data string) string {
+	return base64.RawURLEncoding.EncodeToString(Sign(key, []byte(data)))
+}
+
+// VerifyString reports whether signature is the SignString of data with key, in
+// constant time.
+func VerifyString(key []byte, data, signature string) bool {
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return Verify(key, []byte(data), mac)
+}
@@ -0,0 +1,284 @@
+package cryptoutil
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+const (
+	// KeySize is the size of keyring keys: AES-256.
+	KeySize = 32
+
+	_formatVersion = 1
+	_nonceSize     = 12
+	_tagSize       = 16
+	_wrappedSize   = _nonceSize + KeySize + _tagSize
+)
+
+// Keyring encrypts data with envelope encryption: every message is encrypted with
+// a fresh random data key using AES-256-GCM, and the data key is encrypted ("wrapped")
+// with a versioned key-encryption key of the keyring. Ciphertexts record the ID of
+// the key that wrapped them, so keys can be rotated: new data is encrypted with the
+// primary key while older keys remain available for decryption, and Rotate re-wraps
+// existing ciphertexts with the primary key without re-encrypting their payload.
+//
+// A Keyring is safe for concurrent use.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a new Keyring encrypting with the primary key of keys, a map of
+// key IDs (up to 255 bytes, e.g. "v2" or "2024-06") to 32-byte keys.
+//
+// Example:
+//
+//	kr, err := cryptoutil.NewKeyring("v2", map[string][]byte{
+//	    "v1": oldKey,
+//	    "v2": newKey,
+//	})
+//
+//	ciphertext, err := kr.Encrypt([]byte(user.SSN), []byte(user.ID))
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("cryptoutil - NewKeyring - primary key %q: %w", primary, ErrUnknownKey)
+	}
+
+	kr := &Keyring{primary: primary, keys: make(map[string]cipher.AEAD, len(keys))}
+
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("cryptoutil - NewKeyring - key ID %q must be 1 to 255 bytes long", id)
+		}
+
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("cryptoutil - NewKeyring - key %q is %d bytes long, want %d", id, len(key), KeySize)
+		}
+
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf("cryptoutil - NewKeyring - key %q: %w", id, err)
+		}
+
+		kr.keys[id] = aead
+	}
+
+	return kr, nil
+}
+
+// GenerateKey returns a new random 32-byte key for a Keyring.
+func GenerateKey() ([]byte, error) {
+	return RandomBytes(KeySize)
+}
+
+// Primary returns the ID of the key encrypting new data.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// KeyIDs returns the sorted IDs of the keyring keys.
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Encrypt encrypts plaintext with a new data key wrapped by the primary key.
+// associatedData, which may be nil, is authenticated but not encrypted: the same
+// value must be given to Decrypt, which binds a ciphertext to its context (e.g. the
+// ID of the row holding it) so it cannot be copied elsewhere.
+func (k *Keyring) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	dataKey, err := GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Encrypt - GenerateKey: %w", err)
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Encrypt - newGCM: %w", err)
+	}
+
+	out, err := k.wrap(header(k.primary), dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Encrypt - k.wrap: %w", err)
+	}
+
+	nonce, err := RandomBytes(_nonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Encrypt - RandomBytes: %w", err)
+	}
+
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt with the same associated data.
+// It returns ErrUnknownKey when the wrapping key is not in the keyring and
+// ErrInvalidCiphertext when the ciphertext is malformed or was tampered with.
+func (k *Keyring) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	_, dataKey, payload, err := k.unwrap(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Decrypt: %w", err)
+	}
+
+	if len(payload) < _nonceSize+_tagSize {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Decrypt: %w", ErrInvalidCiphertext)
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Decrypt - newGCM: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, payload[:_nonceSize], payload[_nonceSize:], associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Decrypt: %w", ErrInvalidCiphertext)
+	}
+
+	return plaintext, nil
+}
+
+// KeyID returns the ID of the key that wrapped ciphertext.
+func (k *Keyring) KeyID(ciphertext []byte) (string, error) {
+	id, _, err := parseHeader(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("cryptoutil - Keyring - KeyID: %w", err)
+	}
+
+	return id, nil
+}
+
+// NeedsRotation reports whether ciphertext was wrapped by a key other than the
+// primary key. Malformed ciphertexts do not need rotation.
+func (k *Keyring) NeedsRotation(ciphertext []byte) bool {
+	id, _, err := parseHeader(ciphertext)
+
+	return err == nil && id != k.primary
+}
+
+// Rotate re-wraps the data key of ciphertext with the primary key. The payload is
+// not re-encrypted, so rotating does not need the associated data. Ciphertexts
+// already wrapped by the primary key are returned unchanged.
+func (k *Keyring) Rotate(ciphertext []byte) ([]byte, error) {
+	id, dataKey, payload, err := k.unwrap(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Rotate: %w", err)
+	}
+
+	if id == k.primary {
+		return ciphertext, nil
+	}
+
+	out, err := k.wrap(header(k.primary), dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoutil - Keyring - Rotate - k.wrap: %w", err)
+	}
+
+	return append(out, payload...), nil
+}
+
+// EncryptString encrypts plaintext like Encrypt and returns the ciphertext in
+// standard base64, e.g. for a text column.
+func (k *Keyring) EncryptString(plaintext, associatedData string) (string, error) {
+	ciphertext, err := k.Encrypt([]byte(plaintext), []byte(associatedData))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString decrypts a ciphertext produced by EncryptString.
+func (k *Keyring) DecryptString(ciphertext, associatedData string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("cryptoutil - Keyring - DecryptString: %w", ErrInvalidCiphertext)
+	}
+
+	plaintext, err := k.Decrypt(data, []byte(associatedData))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// wrap appends the data key encrypted with the primary key to hdr. The header is
+// authenticated, so the key ID of a ciphertext cannot be altered.
+func (k *Keyring) wrap(hdr, dataKey []byte) ([]byte, error) {
+	nonce, err := RandomBytes(_nonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	out := append(hdr, nonce...)
+
+	return k.keys[k.primary].Seal(out, nonce, dataKey, hdr), nil
+}
+
+// unwrap returns the key ID, the data key and the encrypted payload of ciphertext.
+func (k *Keyring) unwrap(ciphertext []byte) (string, []byte, []byte, error) {
+	id, n, err := parseHeader(ciphertext)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("key %q: %w", id, ErrUnknownKey)
+	}
+
+	if len(ciphertext) < n+_wrappedSize {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	wrapped := ciphertext[n : n+_wrappedSize]
+
+	dataKey, err := aead.Open(nil, wrapped[:_nonceSize], wrapped[_nonceSize:], ciphertext[:n])
+	if err != nil {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	return id, dataKey, ciphertext[n+_wrappedSize:], nil
+}
+
+// header returns the ciphertext header: the format version, the key ID length and
+// the key ID.
+func header(id string) []byte {
+	hdr := make([]byte, 0, 2+len(id)+_wrappedSize+_nonceSize)
+	hdr = append(hdr, _formatVersion, byte(len(id)))
+
+	return append(hdr, id...)
+}
+
+// parseHeader returns the key ID and the header length of ciphertext.
+func parseHeader(ciphertext []byte) (string, int, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != _formatVersion {
+		return "", 0, ErrInvalidCiphertext
+	}
+
+	n := 2 + int(ciphertext[1])
+	if ciphertext[1] == 0 || len(ciphertext) < n {
+		return "", 0, ErrInvalidCiphertext
+	}
+
+	return string(ciphertext[2:n]), n, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,71 @@
+package cryptoutil
+
+const (
+	_defaultArgon2Memory      = 64 * 1024
+	_defaultArgon2Iterations  = 3
+	_defaultArgon2Parallelism = 2
+	_defaultBcryptCost        = 12
+	_argon2SaltLength         = 16
+	_argon2KeyLength          = 32
+)
+
+// Algorithm is a password hashing algorithm.
+type Algorithm string
+
+// Password hashing algorithms.
+const (
+	Argon2id Algorithm = "argon2id"
+	Bcrypt   Algorithm = "bcrypt"
+)
+
+// Option is a function that configures a PasswordHasher.
+type Option func(*config)
+
+type config struct {
+	algorithm         Algorithm
+	argon2Memory      uint32
+	argon2Iterations  uint32
+	argon2Parallelism uint8
+	bcryptCost        int
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		algorithm:         Argon2id,
+		argon2Memory:      _defaultArgon2Memory,
+		argon2Iterations:  _defaultArgon2Iterations,
+		argon2Parallelism: _defaultArgon2Parallelism,
+		bcryptCost:        _defaultBcryptCost,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithAlgorithm sets the algorithm of new hashes. Hashes of both algorithms are
+// verified regardless. Default is Argon2id.
+func WithAlgorithm(a Algorithm) Option {
+	return func(c *config) {
+		c.algorithm = a
+	}
+}
+
+// Argon2Params sets the argon2id memory in KiB, number of iterations and degree of
+// parallelism. Default is 64 MiB, 3 iterations and a parallelism of 2.
+func Argon2Params(memoryKiB, iterations uint32, parallelism uint8) Option {
+	return func(c *config) {
+		c.argon2Memory = memoryKiB
+		c.argon2Iterations = iterations
+		c.argon2Parallelism = parallelism
+	}
+}
+
+// BcryptCost sets the bcrypt cost, between 4 and 31. Default is 12.
+func BcryptCost(cost int) Option {
+	return func(c *config) {
+		c.bcryptCost = cost
+	}
+}
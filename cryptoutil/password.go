@@ -0,0 +1,180 @@
+package cryptoutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes and verifies passwords. Hashes are self-describing strings:
+// argon2id hashes use the PHC format ("$argon2id$v=19$m=65536,t=3,p=2$salt$hash")
+// and bcrypt hashes the modular crypt format ("$2a$12$..."), so a hasher verifies
+// hashes of any algorithm and parameters, and NeedsRehash tells when a hash should
+// be upgraded after a successful login.
+type PasswordHasher struct {
+	cfg config
+}
+
+// NewPasswordHasher creates a new PasswordHasher.
+// Default configuration: argon2id with 64 MiB of memory, 3 iterations and a
+// parallelism of 2.
+//
+// Example:
+//
+//	h := cryptoutil.NewPasswordHasher()
+//	hash, err := h.Hash(password)
+//
+//	ok, err := h.Verify(password, user.PasswordHash)
+//	if ok && h.NeedsRehash(user.PasswordHash) {
+//	    user.PasswordHash, err = h.Hash(password)
+//	}
+func NewPasswordHasher(opts ...Option) *PasswordHasher {
+	return &PasswordHasher{cfg: newConfig(opts)}
+}
+
+// Hash returns the hash of password with the configured algorithm and a random salt.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	switch h.cfg.algorithm {
+	case Argon2id:
+		salt, err := RandomBytes(_argon2SaltLength)
+		if err != nil {
+			return "", fmt.Errorf("cryptoutil - PasswordHasher - Hash - RandomBytes: %w", err)
+		}
+
+		p := argon2Params{
+			memory:      h.cfg.argon2Memory,
+			iterations:  h.cfg.argon2Iterations,
+			parallelism: h.cfg.argon2Parallelism,
+			salt:        salt,
+		}
+		p.key = p.derive(password, _argon2KeyLength)
+
+		return p.String(), nil
+	case Bcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.bcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("cryptoutil - PasswordHasher - Hash - bcrypt.GenerateFromPassword: %w", err)
+		}
+
+		return string(hash), nil
+	default:
+		return "", fmt.Errorf("cryptoutil - PasswordHasher - Hash - unsupported algorithm %q", h.cfg.algorithm)
+	}
+}
+
+// Verify reports whether password matches hash, comparing in constant time.
+// It returns ErrInvalidHash when hash is malformed or of an unsupported algorithm.
+func (h *PasswordHasher) Verify(password, hash string) (bool, error) {
+	switch algorithmOf(hash) {
+	case Argon2id:
+		p, err := parseArgon2(hash)
+		if err != nil {
+			return false, fmt.Errorf("cryptoutil - PasswordHasher - Verify: %w", err)
+		}
+
+		return Equal(p.derive(password, uint32(len(p.key))), p.key), nil
+	case Bcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("cryptoutil - PasswordHasher - Verify: %w: %w", ErrInvalidHash, err)
+		}
+
+		return true, nil
+	default:
+		return false, fmt.Errorf("cryptoutil - PasswordHasher - Verify: %w", ErrInvalidHash)
+	}
+}
+
+// NeedsRehash reports whether hash was produced with another algorithm or other
+// parameters than the configured ones. Malformed hashes need rehashing.
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if algorithmOf(hash) != h.cfg.algorithm {
+		return true
+	}
+
+	switch h.cfg.algorithm {
+	case Argon2id:
+		p, err := parseArgon2(hash)
+
+		return err != nil || p.memory != h.cfg.argon2Memory || p.iterations != h.cfg.argon2Iterations ||
+			p.parallelism != h.cfg.argon2Parallelism
+	case Bcrypt:
+		cost, err := bcrypt.Cost([]byte(hash))
+
+		return err != nil || cost != h.cfg.bcryptCost
+	default:
+		return true
+	}
+}
+
+func algorithmOf(hash string) Algorithm {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return Argon2id
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return Bcrypt
+	default:
+		return ""
+	}
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (p argon2Params) derive(password string, keyLength uint32) []byte {
+	return argon2.IDKey([]byte(password), p.salt, p.iterations, p.memory, p.parallelism, keyLength)
+}
+
+// String returns the PHC string of p.
+func (p argon2Params) String() string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(p.salt), base64.RawStdEncoding.EncodeToString(p.key))
+}
+
+func parseArgon2(hash string) (argon2Params, error) {
+	var p argon2Params
+
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, ErrInvalidHash
+	}
+
+	var err error
+
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, ErrInvalidHash
+	}
+
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return p, ErrInvalidHash
+	}
+
+	if p.iterations == 0 || p.parallelism == 0 {
+		return p, ErrInvalidHash
+	}
+
+	return p, nil
+}
@@ -0,0 +1,235 @@
+// Package dlqtool lists, inspects and replays dead-lettered messages from Kafka DLQ
+// topics and RabbitMQ dead letter queues back to the topic or queue they came from,
+// with filtering and rate limiting. The dlqtool command in cmd/dlqtool wraps it.
+package dlqtool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+)
+
+// ErrNotFound is returned by Find when no message has the requested ID.
+var ErrNotFound = errors.New("dlqtool - message not found")
+
+// Message is a dead-lettered message.
+type Message struct {
+	// ID identifies the message within its source, e.g. "partition-offset" in Kafka.
+	ID string
+	// Origin is the topic or queue the message was dead-lettered from, empty when unknown.
+	Origin string
+	// Error is the reason the message was dead-lettered, empty when unknown.
+	Error   string
+	Key     []byte
+	Body    []byte
+	Headers map[string]string
+	// Time is when the message was dead-lettered, or published if unknown.
+	Time time.Time
+
+	receipt any
+}
+
+// Source reads dead-lettered messages.
+type Source interface {
+	// Next returns the next message, or nil once the source is drained.
+	Next(ctx context.Context) (*Message, error)
+	// Settle removes m from the source when replayed is true and leaves it otherwise.
+	Settle(ctx context.Context, m *Message, replayed bool) error
+	Close() error
+}
+
+// Target republishes replayed messages.
+type Target interface {
+	// Publish sends m to the origin topic or queue.
+	Publish(ctx context.Context, origin string, m *Message) error
+}
+
+// Filter reports whether a message is selected.
+type Filter func(m *Message) bool
+
+// HeaderEquals selects messages whose header key equals value.
+func HeaderEquals(key, value string) Filter {
+	return func(m *Message) bool {
+		v, ok := m.Headers[key]
+
+		return ok && v == value
+	}
+}
+
+// BodyContains selects messages whose body contains s.
+func BodyContains(s string) Filter {
+	return func(m *Message) bool {
+		return bytes.Contains(m.Body, []byte(s))
+	}
+}
+
+// OriginEquals selects messages dead-lettered from origin.
+func OriginEquals(origin string) Filter {
+	return func(m *Message) bool {
+		return m.Origin == origin
+	}
+}
+
+// Since selects messages dead-lettered at or after t.
+func Since(t time.Time) Filter {
+	return func(m *Message) bool {
+		return !m.Time.Before(t)
+	}
+}
+
+// Result counts the messages handled by Replay.
+type Result struct {
+	// Replayed messages were published to their origin, or would have been in a dry run.
+	Replayed int
+	// Skipped messages were rejected by the filters or have no known origin.
+	Skipped int
+}
+
+// List returns the messages of src selected by the Filter options, up to the Limit
+// option. Messages are left in the source.
+//
+// Example:
+//
+//	msgs, err := dlqtool.List(ctx, src, dlqtool.Limit(20), dlqtool.WithFilter(dlqtool.BodyContains("order-42")))
+func List(ctx context.Context, src Source, opts ...Option) ([]*Message, error) {
+	cfg := newConfig(opts)
+
+	var msgs []*Message
+
+	for cfg.limit == 0 || len(msgs) < cfg.limit {
+		m, err := src.Next(ctx)
+		if err != nil {
+			return msgs, fmt.Errorf("dlqtool - List - src.Next: %w", err)
+		}
+
+		if m == nil {
+			break
+		}
+
+		if err := src.Settle(ctx, m, false); err != nil {
+			return msgs, fmt.Errorf("dlqtool - List - src.Settle: %w", err)
+		}
+
+		if cfg.matches(m) {
+			msgs = append(msgs, m)
+		}
+	}
+
+	return msgs, nil
+}
+
+// Find returns the message of src with the given ID, or ErrNotFound.
+// The message is left in the source.
+func Find(ctx context.Context, src Source, id string) (*Message, error) {
+	for {
+		m, err := src.Next(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dlqtool - Find - src.Next: %w", err)
+		}
+
+		if m == nil {
+			return nil, ErrNotFound
+		}
+
+		if err := src.Settle(ctx, m, false); err != nil {
+			return nil, fmt.Errorf("dlqtool - Find - src.Settle: %w", err)
+		}
+
+		if m.ID == id {
+			return m, nil
+		}
+	}
+}
+
+// Replay publishes the messages of src selected by the Filter options to their origin,
+// or to the To option, through dst and removes them from src. It stops when src is
+// drained, the Limit option is reached, or publishing fails; the returned Result is
+// valid in every case. Messages without an origin are skipped and left in the source.
+//
+// Example:
+//
+//	res, err := dlqtool.Replay(ctx, src, src,
+//	    dlqtool.WithFilter(dlqtool.HeaderEquals("tenant", "acme")),
+//	    dlqtool.Rate(50),
+//	)
+func Replay(ctx context.Context, src Source, dst Target, opts ...Option) (Result, error) {
+	cfg := newConfig(opts)
+
+	var (
+		res     Result
+		limiter ratelimit.Limiter
+	)
+
+	if cfg.rate > 0 {
+		limiter = ratelimit.NewLeakyBucket(ratelimit.PerSecond(cfg.rate))
+	}
+
+	for cfg.limit == 0 || res.Replayed < cfg.limit {
+		m, err := src.Next(ctx)
+		if err != nil {
+			return res, fmt.Errorf("dlqtool - Replay - src.Next: %w", err)
+		}
+
+		if m == nil {
+			break
+		}
+
+		origin := m.Origin
+		if cfg.to != "" {
+			origin = cfg.to
+		}
+
+		selected := cfg.matches(m)
+		if selected && origin == "" {
+			if cfg.logger != nil {
+				cfg.logger.Warn("dlqtool - message %s has no origin, skipped", m.ID)
+			}
+
+			selected = false
+		}
+
+		if !selected || cfg.dryRun {
+			if err := src.Settle(ctx, m, false); err != nil {
+				return res, fmt.Errorf("dlqtool - Replay - src.Settle: %w", err)
+			}
+
+			if selected {
+				res.Replayed++
+			} else {
+				res.Skipped++
+			}
+
+			continue
+		}
+
+		if limiter != nil {
+			if err := ratelimit.Wait(ctx, limiter, "replay"); err != nil {
+				_ = src.Settle(ctx, m, false)
+
+				return res, fmt.Errorf("dlqtool - Replay - ratelimit.Wait: %w", err)
+			}
+		}
+
+		if err := dst.Publish(ctx, origin, m); err != nil {
+			_ = src.Settle(ctx, m, false)
+
+			return res, fmt.Errorf("dlqtool - Replay - dst.Publish: %w", err)
+		}
+
+		if err := src.Settle(ctx, m, true); err != nil {
+			return res, fmt.Errorf("dlqtool - Replay - src.Settle: %w", err)
+		}
+
+		res.Replayed++
+
+		if cfg.logger != nil {
+			cfg.logger.Info("dlqtool - message %s replayed to %s", m.ID, origin)
+		}
+	}
+
+	return res, nil
+}
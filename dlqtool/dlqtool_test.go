@@ -0,0 +1,197 @@
+package dlqtool_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/dlqtool"
+)
+
+// memory is an in-memory Source and Target.
+type memory struct {
+	msgs      []*dlqtool.Message
+	next      int
+	removed   map[string]bool
+	published []string
+	err       error
+}
+
+func newMemory(msgs ...*dlqtool.Message) *memory {
+	return &memory{msgs: msgs, removed: map[string]bool{}}
+}
+
+func (m *memory) Next(context.Context) (*dlqtool.Message, error) {
+	if m.next == len(m.msgs) {
+		return nil, nil
+	}
+
+	m.next++
+
+	return m.msgs[m.next-1], nil
+}
+
+func (m *memory) Settle(_ context.Context, msg *dlqtool.Message, replayed bool) error {
+	if replayed {
+		m.removed[msg.ID] = true
+	}
+
+	return nil
+}
+
+func (m *memory) Publish(_ context.Context, origin string, msg *dlqtool.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.published = append(m.published, origin+":"+msg.ID)
+
+	return nil
+}
+
+func (m *memory) Close() error {
+	return nil
+}
+
+func messages() []*dlqtool.Message {
+	return []*dlqtool.Message{
+		{ID: "1", Origin: "orders", Body: []byte(`{"order":1}`), Headers: map[string]string{"tenant": "acme"}},
+		{ID: "2", Origin: "orders", Body: []byte(`{"order":2}`), Headers: map[string]string{"tenant": "globex"}},
+		{ID: "3", Origin: "", Body: []byte(`{"order":3}`), Headers: map[string]string{"tenant": "acme"}},
+		{ID: "4", Origin: "payments", Body: []byte(`{"order":4}`), Headers: map[string]string{"tenant": "acme"}},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []dlqtool.Option
+		want      dlqtool.Result
+		published []string
+	}{
+		{
+			name:      "all",
+			want:      dlqtool.Result{Replayed: 3, Skipped: 1},
+			published: []string{"orders:1", "orders:2", "payments:4"},
+		},
+		{
+			name:      "filter",
+			opts:      []dlqtool.Option{dlqtool.WithFilter(dlqtool.HeaderEquals("tenant", "acme"))},
+			want:      dlqtool.Result{Replayed: 2, Skipped: 2},
+			published: []string{"orders:1", "payments:4"},
+		},
+		{
+			name:      "limit",
+			opts:      []dlqtool.Option{dlqtool.Limit(1)},
+			want:      dlqtool.Result{Replayed: 1},
+			published: []string{"orders:1"},
+		},
+		{
+			name:      "to",
+			opts:      []dlqtool.Option{dlqtool.To("retry"), dlqtool.WithFilter(dlqtool.BodyContains(`"order":3`))},
+			want:      dlqtool.Result{Replayed: 1, Skipped: 3},
+			published: []string{"retry:3"},
+		},
+		{
+			name: "dry run",
+			opts: []dlqtool.Option{dlqtool.DryRun(true), dlqtool.WithFilter(dlqtool.OriginEquals("orders"))},
+			want: dlqtool.Result{Replayed: 2, Skipped: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMemory(messages()...)
+
+			res, err := dlqtool.Replay(context.Background(), m, m, tt.opts...)
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+
+			if res != tt.want {
+				t.Errorf("Replay() = %+v, want %+v", res, tt.want)
+			}
+
+			if len(m.published) != len(tt.published) {
+				t.Fatalf("published %v, want %v", m.published, tt.published)
+			}
+
+			for i := range tt.published {
+				if m.published[i] != tt.published[i] {
+					t.Errorf("published %v, want %v", m.published, tt.published)
+				}
+			}
+
+			if len(m.removed) != len(tt.published) {
+				t.Errorf("removed %d messages, want %d", len(m.removed), len(tt.published))
+			}
+		})
+	}
+}
+
+func TestReplayPublishError(t *testing.T) {
+	m := newMemory(messages()...)
+	m.err = errors.New("broker down")
+
+	res, err := dlqtool.Replay(context.Background(), m, m)
+	if !errors.Is(err, m.err) {
+		t.Fatalf("Replay() error = %v, want %v", err, m.err)
+	}
+
+	if res.Replayed != 0 || len(m.removed) != 0 {
+		t.Errorf("Replay() = %+v and removed %d messages, want nothing replayed", res, len(m.removed))
+	}
+}
+
+func TestReplayRate(t *testing.T) {
+	m := newMemory(messages()...)
+
+	start := time.Now()
+
+	if _, err := dlqtool.Replay(context.Background(), m, m, dlqtool.Rate(20)); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	// Three messages at 20 per second are spaced 50ms apart.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Replay() took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestList(t *testing.T) {
+	m := newMemory(messages()...)
+
+	msgs, err := dlqtool.List(context.Background(), m, dlqtool.WithFilter(dlqtool.HeaderEquals("tenant", "acme")), dlqtool.Limit(2))
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(msgs) != 2 || msgs[0].ID != "1" || msgs[1].ID != "3" {
+		t.Errorf("List() = %v, want messages 1 and 3", msgs)
+	}
+
+	if len(m.removed) != 0 {
+		t.Error("List() removed messages")
+	}
+}
+
+func TestFind(t *testing.T) {
+	m, err := dlqtool.Find(context.Background(), newMemory(messages()...), "2")
+	if err != nil || m.ID != "2" {
+		t.Errorf("Find() = %v, %v, want message 2", m, err)
+	}
+
+	if _, err := dlqtool.Find(context.Background(), newMemory(messages()...), "5"); !errors.Is(err, dlqtool.ErrNotFound) {
+		t.Errorf("Find() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSince(t *testing.T) {
+	now := time.Now()
+	f := dlqtool.Since(now)
+
+	if f(&dlqtool.Message{Time: now.Add(-time.Second)}) || !f(&dlqtool.Message{Time: now}) {
+		t.Error("Since() selects messages before t or rejects messages at t")
+	}
+}
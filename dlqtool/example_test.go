@@ -0,0 +1,28 @@
+package dlqtool_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/dlqtool"
+)
+
+func ExampleReplay() {
+	// In production the source is dlqtool.NewKafka or dlqtool.NewRabbitMQ.
+	src := newMemory(messages()...)
+
+	res, err := dlqtool.Replay(context.Background(), src, src,
+		dlqtool.WithFilter(dlqtool.HeaderEquals("tenant", "acme")),
+		dlqtool.Rate(100),
+	)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Printf("replayed %d, skipped %d\n", res.Replayed, res.Skipped)
+
+	// Output:
+	// replayed 2, skipped 2
+}
@@ -0,0 +1,213 @@
+package dlqtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Headers describing a dead-lettered Kafka record. Producers dead-lettering records
+// should set them; they are removed from replayed records.
+const (
+	// HeaderOrigin is the topic the record was dead-lettered from.
+	HeaderOrigin = "dlq-origin"
+	// HeaderError is the reason the record was dead-lettered.
+	HeaderError = "dlq-error"
+)
+
+const _defaultIdleTimeout = 5 * time.Second
+
+// dlqSuffixes are stripped from a DLQ topic name to find the origin of records without
+// an origin header.
+var dlqSuffixes = []string{".dlq", "-dlq", "_dlq"}
+
+// Kafka is a Source reading a DLQ topic and a Target producing to origin topics.
+// It reads without a consumer group, so Settle does nothing: records stay in the topic
+// until its retention removes them, and consecutive runs read the same records.
+type Kafka struct {
+	client *kgo.Client
+	topic  string
+	idle   time.Duration
+	buf    []*kgo.Record
+}
+
+var (
+	_ Source = (*Kafka)(nil)
+	_ Target = (*Kafka)(nil)
+)
+
+// KafkaOption configures a Kafka source.
+type KafkaOption func(*kafkaConfig)
+
+type kafkaConfig struct {
+	from time.Time
+	idle time.Duration
+}
+
+// FromTime starts reading at the first record produced at or after t.
+// Default is the beginning of the topic.
+func FromTime(t time.Time) KafkaOption {
+	return func(c *kafkaConfig) {
+		c.from = t
+	}
+}
+
+// IdleTimeout is how long Next waits for a record before reporting the topic drained.
+// Default is 5 seconds.
+func IdleTimeout(d time.Duration) KafkaOption {
+	return func(c *kafkaConfig) {
+		if d > 0 {
+			c.idle = d
+		}
+	}
+}
+
+// NewKafka creates a Kafka source reading topic from the brokers of cfg.
+// Only Brokers, ClientID and Hooks of cfg are used.
+//
+// Example:
+//
+//	src, err := dlqtool.NewKafka(kafka.Config{Brokers: []string{"localhost:9092"}}, "orders.dlq")
+//	if err != nil {
+//	    return err
+//	}
+//	defer src.Close()
+//
+//	res, err := dlqtool.Replay(ctx, src, src, dlqtool.Rate(100))
+func NewKafka(cfg kafka.Config, topic string, opts ...KafkaOption) (*Kafka, error) {
+	kcfg := kafkaConfig{idle: _defaultIdleTimeout}
+	for _, opt := range opts {
+		opt(&kcfg)
+	}
+
+	offset := kgo.NewOffset().AtStart()
+	if !kcfg.from.IsZero() {
+		offset = kgo.NewOffset().AfterMilli(kcfg.from.UnixMilli())
+	}
+
+	kopts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(offset),
+	}
+
+	if cfg.ClientID != "" {
+		kopts = append(kopts, kgo.ClientID(cfg.ClientID))
+	}
+
+	if len(cfg.Hooks) > 0 {
+		kopts = append(kopts, kgo.WithHooks(cfg.Hooks...))
+	}
+
+	client, err := kgo.NewClient(kopts...)
+	if err != nil {
+		return nil, fmt.Errorf("dlqtool - NewKafka - kgo.NewClient: %w", err)
+	}
+
+	return &Kafka{
+		client: client,
+		topic:  topic,
+		idle:   kcfg.idle,
+	}, nil
+}
+
+// Next returns the next record, or nil when none arrives within the idle timeout.
+func (k *Kafka) Next(ctx context.Context) (*Message, error) {
+	if len(k.buf) == 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, k.idle)
+		fetches := k.client.PollFetches(pollCtx)
+
+		cancel()
+
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("dlqtool - Kafka - Next: %w", err)
+		}
+
+		if err := fetches.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("dlqtool - Kafka - Next - k.client.PollFetches: %w", err)
+		}
+
+		k.buf = fetches.Records()
+	}
+
+	if len(k.buf) == 0 {
+		return nil, nil
+	}
+
+	r := k.buf[0]
+	k.buf = k.buf[1:]
+
+	return k.message(r), nil
+}
+
+func (k *Kafka) message(r *kgo.Record) *Message {
+	m := &Message{
+		ID:      fmt.Sprintf("%d-%d", r.Partition, r.Offset),
+		Key:     r.Key,
+		Body:    r.Value,
+		Headers: make(map[string]string, len(r.Headers)),
+		Time:    r.Timestamp,
+	}
+
+	for _, h := range r.Headers {
+		m.Headers[h.Key] = string(h.Value)
+	}
+
+	m.Origin = m.Headers[HeaderOrigin]
+	m.Error = m.Headers[HeaderError]
+
+	if m.Origin == "" {
+		for _, suffix := range dlqSuffixes {
+			if len(k.topic) > len(suffix) && strings.EqualFold(k.topic[len(k.topic)-len(suffix):], suffix) {
+				m.Origin = k.topic[:len(k.topic)-len(suffix)]
+
+				break
+			}
+		}
+	}
+
+	return m
+}
+
+// Settle does nothing: records cannot be removed from a Kafka topic.
+func (k *Kafka) Settle(context.Context, *Message, bool) error {
+	return nil
+}
+
+// Publish produces m to the origin topic with its key and headers, except the DLQ
+// headers, and waits for the broker to acknowledge it.
+func (k *Kafka) Publish(ctx context.Context, origin string, m *Message) error {
+	r := &kgo.Record{
+		Topic: origin,
+		Key:   m.Key,
+		Value: m.Body,
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(m.Headers)) {
+		if key == HeaderOrigin || key == HeaderError {
+			continue
+		}
+
+		r.Headers = append(r.Headers, kgo.RecordHeader{Key: key, Value: []byte(m.Headers[key])})
+	}
+
+	if err := k.client.ProduceSync(ctx, r).FirstErr(); err != nil {
+		return fmt.Errorf("dlqtool - Kafka - Publish - k.client.ProduceSync: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the client.
+func (k *Kafka) Close() error {
+	k.client.Close()
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package dlqtool
+
+import (
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option configures List and Replay.
+type Option func(*config)
+
+type config struct {
+	limit   int
+	filters []Filter
+	rate    int
+	to      string
+	dryRun  bool
+	logger  logger.LoggerI
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+func (c *config) matches(m *Message) bool {
+	for _, f := range c.filters {
+		if !f(m) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Limit stops after n listed or replayed messages; zero means no limit.
+// Default is no limit.
+func Limit(n int) Option {
+	return func(c *config) {
+		if n >= 0 {
+			c.limit = n
+		}
+	}
+}
+
+// WithFilter selects the messages accepted by every filter. Nil filters are ignored.
+// Default is every message.
+func WithFilter(filters ...Filter) Option {
+	return func(c *config) {
+		for _, f := range filters {
+			if f != nil {
+				c.filters = append(c.filters, f)
+			}
+		}
+	}
+}
+
+// Rate limits Replay to perSecond messages per second, evenly spaced.
+// Default is no limit.
+func Rate(perSecond int) Option {
+	return func(c *config) {
+		if perSecond > 0 {
+			c.rate = perSecond
+		}
+	}
+}
+
+// To replays every message to the topic or queue to instead of its origin.
+// Default is the origin of each message.
+func To(to string) Option {
+	return func(c *config) {
+		c.to = to
+	}
+}
+
+// DryRun makes Replay count the messages it would replay without publishing them
+// or removing them from the source.
+// Default is false.
+func DryRun(dryRun bool) Option {
+	return func(c *config) {
+		c.dryRun = dryRun
+	}
+}
+
+// WithLogger sets the logger reporting replayed messages and messages skipped for
+// lack of an origin.
+// Default is nil, which disables logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
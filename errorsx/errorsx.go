@@ -0,0 +1,204 @@
+// Package errorsx provides coded errors carrying a transport independent Code, a
+// client safe message and metadata, wrapping their cause. Codes map to HTTP status
+// codes for the httpserver error handler and to gRPC status codes for the grpcserver
+// interceptors, so error semantics survive across layers and services.
+package errorsx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Code classifies an error independently of the transport.
+type Code string
+
+// Error codes.
+const (
+	CodeInternal           Code = "internal"
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeNotFound           Code = "not_found"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeConflict           Code = "conflict"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeResourceExhausted  Code = "resource_exhausted"
+	CodeCanceled           Code = "canceled"
+	CodeDeadlineExceeded   Code = "deadline_exceeded"
+	CodeUnimplemented      Code = "unimplemented"
+	CodeUnavailable        Code = "unavailable"
+)
+
+// Error is a coded error. Message is meant for clients; the wrapped cause is not
+// exposed by the transport mappings.
+type Error struct {
+	Code     Code
+	Message  string
+	Metadata map[string]string
+	Err      error
+}
+
+// New returns an error with the given code and message.
+func New(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an error with the given code and message wrapping err.
+// It returns nil if err is nil.
+//
+// Example:
+//
+//	if errors.Is(err, pgx.ErrNoRows) {
+//	    return errorsx.Wrap(err, errorsx.CodeNotFound, "user %s not found", id)
+//	}
+func Wrap(err error, code Code, format string, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// InvalidArgument returns a CodeInvalidArgument error.
+func InvalidArgument(format string, args ...interface{}) *Error {
+	return New(CodeInvalidArgument, format, args...)
+}
+
+// Unauthenticated returns a CodeUnauthenticated error.
+func Unauthenticated(format string, args ...interface{}) *Error {
+	return New(CodeUnauthenticated, format, args...)
+}
+
+// PermissionDenied returns a CodePermissionDenied error.
+func PermissionDenied(format string, args ...interface{}) *Error {
+	return New(CodePermissionDenied, format, args...)
+}
+
+// NotFound returns a CodeNotFound error.
+func NotFound(format string, args ...interface{}) *Error {
+	return New(CodeNotFound, format, args...)
+}
+
+// AlreadyExists returns a CodeAlreadyExists error.
+func AlreadyExists(format string, args ...interface{}) *Error {
+	return New(CodeAlreadyExists, format, args...)
+}
+
+// Conflict returns a CodeConflict error.
+func Conflict(format string, args ...interface{}) *Error {
+	return New(CodeConflict, format, args...)
+}
+
+// FailedPrecondition returns a CodeFailedPrecondition error.
+func FailedPrecondition(format string, args ...interface{}) *Error {
+	return New(CodeFailedPrecondition, format, args...)
+}
+
+// ResourceExhausted returns a CodeResourceExhausted error.
+func ResourceExhausted(format string, args ...interface{}) *Error {
+	return New(CodeResourceExhausted, format, args...)
+}
+
+// Unavailable returns a CodeUnavailable error.
+func Unavailable(format string, args ...interface{}) *Error {
+	return New(CodeUnavailable, format, args...)
+}
+
+// Internal returns a CodeInternal error.
+func Internal(format string, args ...interface{}) *Error {
+	return New(CodeInternal, format, args...)
+}
+
+// Error returns the message followed by the cause.
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Code)
+	}
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the wrapped cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithMeta returns a copy of e with the metadata key set to value.
+func (e *Error) WithMeta(key, value string) *Error {
+	c := *e
+	c.Metadata = maps.Clone(e.Metadata)
+
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string, 1)
+	}
+
+	c.Metadata[key] = value
+
+	return &c
+}
+
+// CodeOf returns the code of the first *Error in err's chain. Context cancellation
+// and deadline errors map to CodeCanceled and CodeDeadlineExceeded; other errors,
+// and nil, to CodeInternal.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	default:
+		return CodeInternal
+	}
+}
+
+// HasCode reports whether err has the given code.
+func HasCode(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// MessageOf returns the client safe message of the first *Error in err's chain,
+// or an empty string.
+func MessageOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Message
+	}
+
+	return ""
+}
+
+// MetadataOf returns the metadata of every *Error in err's chain, outer errors
+// taking precedence.
+func MetadataOf(err error) map[string]string {
+	var md map[string]string
+
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.Metadata {
+				if md == nil {
+					md = make(map[string]string)
+				}
+
+				if _, ok := md[k]; !ok {
+					md[k] = v
+				}
+			}
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return md
+}
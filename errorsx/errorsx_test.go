@@ -0,0 +1,146 @@
+package errorsx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCodeOf(t *testing.T) {
+	cause := errors.New("no rows")
+
+	tests := []struct {
+		name string
+		err  error
+		want errorsx.Code
+	}{
+		{name: "coded", err: errorsx.NotFound("user not found"), want: errorsx.CodeNotFound},
+		{name: "wrapped", err: fmt.Errorf("repo: %w", errorsx.Wrap(cause, errorsx.CodeNotFound, "user")), want: errorsx.CodeNotFound},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), want: errorsx.CodeCanceled},
+		{name: "deadline", err: context.DeadlineExceeded, want: errorsx.CodeDeadlineExceeded},
+		{name: "plain", err: cause, want: errorsx.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorsx.CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("no rows")
+	err := errorsx.Wrap(cause, errorsx.CodeNotFound, "user %d not found", 7)
+
+	if !errors.Is(err, cause) {
+		t.Error("Wrap() does not wrap its cause")
+	}
+
+	if err.Error() != "user 7 not found: no rows" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	if errorsx.Wrap(nil, errorsx.CodeInternal, "x") != nil {
+		t.Error("Wrap(nil) is not nil")
+	}
+
+	if !errorsx.HasCode(fmt.Errorf("service: %w", err), errorsx.CodeNotFound) {
+		t.Error("HasCode() = false, want true")
+	}
+}
+
+func TestMetadataOf(t *testing.T) {
+	inner := errorsx.InvalidArgument("invalid email").WithMeta("field", "email").WithMeta("rule", "format")
+	outer := errorsx.Wrap(inner, errorsx.CodeInvalidArgument, "invalid request").WithMeta("field", "user.email")
+
+	md := errorsx.MetadataOf(fmt.Errorf("handler: %w", outer))
+	if md["field"] != "user.email" || md["rule"] != "format" {
+		t.Errorf("MetadataOf() = %v", md)
+	}
+
+	if len(inner.Metadata) != 2 {
+		t.Errorf("WithMeta() modified its receiver: %v", inner.Metadata)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errorsx.InvalidArgument("x"), want: http.StatusBadRequest},
+		{err: errorsx.NotFound("x"), want: http.StatusNotFound},
+		{err: errorsx.Conflict("x"), want: http.StatusConflict},
+		{err: errorsx.ResourceExhausted("x"), want: http.StatusTooManyRequests},
+		{err: errorsx.Unavailable("x"), want: http.StatusServiceUnavailable},
+		{err: errors.New("x"), want: http.StatusInternalServerError},
+		{err: errorsx.New("custom", "x"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := errorsx.HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("HTTPStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+
+	for code := range map[errorsx.Code]bool{errorsx.CodeNotFound: true, errorsx.CodeUnauthenticated: true, errorsx.CodeUnavailable: true} {
+		if got := errorsx.FromHTTPStatus(code.HTTPStatus()); got != code {
+			t.Errorf("FromHTTPStatus(%d) = %s, want %s", code.HTTPStatus(), got, code)
+		}
+	}
+}
+
+func TestGRPC_RoundTrip(t *testing.T) {
+	err := fmt.Errorf("service: %w", errorsx.Conflict("version mismatch").WithMeta("version", "3"))
+
+	grpcErr := errorsx.ToGRPC(err)
+
+	st, _ := status.FromError(grpcErr)
+	if st.Code() != codes.Aborted || st.Message() != "version mismatch" {
+		t.Fatalf("ToGRPC() = %v, %q", st.Code(), st.Message())
+	}
+
+	got := errorsx.FromGRPC(grpcErr)
+	if got.Code != errorsx.CodeConflict || got.Message != "version mismatch" || got.Metadata["version"] != "3" {
+		t.Errorf("FromGRPC() = %+v", got)
+	}
+}
+
+func TestToGRPC(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "plain error hides its message", err: errors.New("pq: password leaked"), wantCode: codes.Internal, wantMsg: "internal"},
+		{name: "context error", err: context.DeadlineExceeded, wantCode: codes.DeadlineExceeded, wantMsg: "deadline_exceeded"},
+		{name: "status error is kept", err: status.Error(codes.NotFound, "gone"), wantCode: codes.NotFound, wantMsg: "gone"},
+		{name: "direct conversion", err: errorsx.PermissionDenied("admins only"), wantCode: codes.PermissionDenied, wantMsg: "admins only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(errorsx.ToGRPC(tt.err))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("ToGRPC() = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+
+	if errorsx.ToGRPC(nil) != nil {
+		t.Error("ToGRPC(nil) is not nil")
+	}
+
+	if got := errorsx.FromGRPC(status.Error(codes.Unavailable, "down")); got.Code != errorsx.CodeUnavailable {
+		t.Errorf("FromGRPC() of a foreign status = %s, want %s", got.Code, errorsx.CodeUnavailable)
+	}
+}
@@ -0,0 +1,25 @@
+package errorsx_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/errorsx"
+)
+
+func ExampleWrap() {
+	errNoRows := errors.New("no rows in result set")
+
+	err := fmt.Errorf("usecase - GetUser: %w",
+		errorsx.Wrap(errNoRows, errorsx.CodeNotFound, "user %d not found", 42).WithMeta("user_id", "42"))
+
+	fmt.Println(errorsx.CodeOf(err))
+	fmt.Println(errorsx.HTTPStatus(err))
+	fmt.Println(errorsx.CodeOf(err).GRPCCode())
+	fmt.Println(errorsx.MessageOf(err))
+	// Output:
+	// not_found
+	// 404
+	// NotFound
+	// user 42 not found
+}
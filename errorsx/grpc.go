@@ -0,0 +1,114 @@
+package errorsx
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorInfoDomain is the domain of the ErrorInfo detail attached to gRPC statuses,
+// carrying the error code and metadata.
+const ErrorInfoDomain = "errorsx"
+
+var grpcCodes = map[Code]codes.Code{
+	CodeInternal:           codes.Internal,
+	CodeInvalidArgument:    codes.InvalidArgument,
+	CodeUnauthenticated:    codes.Unauthenticated,
+	CodePermissionDenied:   codes.PermissionDenied,
+	CodeNotFound:           codes.NotFound,
+	CodeAlreadyExists:      codes.AlreadyExists,
+	CodeConflict:           codes.Aborted,
+	CodeFailedPrecondition: codes.FailedPrecondition,
+	CodeResourceExhausted:  codes.ResourceExhausted,
+	CodeCanceled:           codes.Canceled,
+	CodeDeadlineExceeded:   codes.DeadlineExceeded,
+	CodeUnimplemented:      codes.Unimplemented,
+	CodeUnavailable:        codes.Unavailable,
+}
+
+// GRPCCode returns the gRPC status code of code. Unknown codes map to codes.Internal.
+func (c Code) GRPCCode() codes.Code {
+	if code, ok := grpcCodes[c]; ok {
+		return code
+	}
+
+	return codes.Internal
+}
+
+// GRPCStatus returns the gRPC status of the error, carrying its code and metadata in
+// an ErrorInfo detail. It lets grpc-go convert errors returned by handlers directly.
+func (e *Error) GRPCStatus() *status.Status {
+	return grpcStatus(e.Code, e.Message, e.Metadata)
+}
+
+// ToGRPC converts err to a gRPC status error. gRPC status errors are returned as is.
+// Internal errors not carrying an *Error only expose their code, not their message.
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return grpcStatus(e.Code, e.Message, MetadataOf(err)).Err()
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := CodeOf(err)
+
+	return grpcStatus(code, string(code), nil).Err()
+}
+
+// FromGRPC converts a gRPC status error, e.g. returned by a client call, to an *Error.
+// The code and metadata of errors produced by ToGRPC are restored.
+func FromGRPC(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return &Error{Code: CodeOf(err), Err: err}
+	}
+
+	e := &Error{Code: fromGRPCCode(st.Code()), Message: st.Message(), Err: err}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorInfoDomain {
+			e.Code = Code(info.GetReason())
+			e.Metadata = info.GetMetadata()
+		}
+	}
+
+	return e
+}
+
+func grpcStatus(code Code, message string, metadata map[string]string) *status.Status {
+	st := status.New(code.GRPCCode(), message)
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(code),
+		Domain:   ErrorInfoDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+func fromGRPCCode(code codes.Code) Code {
+	for c, gc := range grpcCodes {
+		if gc == code {
+			return c
+		}
+	}
+
+	return CodeInternal
+}
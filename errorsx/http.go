@@ -0,0 +1,67 @@
+package errorsx
+
+import "net/http"
+
+// StatusClientClosedRequest is the non standard status of requests canceled by the client.
+const StatusClientClosedRequest = 499
+
+var httpStatuses = map[Code]int{
+	CodeInternal:           http.StatusInternalServerError,
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodeConflict:           http.StatusConflict,
+	CodeFailedPrecondition: http.StatusPreconditionFailed,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeCanceled:           StatusClientClosedRequest,
+	CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+}
+
+// HTTPStatus returns the HTTP status code of code. Unknown codes map to 500.
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatuses[c]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// HTTPStatus returns the HTTP status code of err.
+func HTTPStatus(err error) int {
+	return CodeOf(err).HTTPStatus()
+}
+
+// FromHTTPStatus returns the code of an HTTP status, e.g. of a response from another service.
+// 2xx and 3xx statuses, which are not errors, map to CodeInternal.
+func FromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodeFailedPrecondition
+	case http.StatusTooManyRequests:
+		return CodeResourceExhausted
+	case StatusClientClosedRequest:
+		return CodeCanceled
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CodeDeadlineExceeded
+	case http.StatusNotImplemented:
+		return CodeUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return CodeUnavailable
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,213 @@
+// Package etcd provides an etcd coordination client wrapping clientv3, with KV helpers,
+// watch channels, lease-based TTL keys and sessions. It backs the etcd implementations
+// of the lock package.
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/retry"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+const (
+	defaultDialTimeout  = 5 * time.Second
+	defaultConnAttempts = 10
+	defaultConnTimeout  = time.Second
+)
+
+// Etcd represents an etcd client.
+type Etcd struct {
+	client *clientv3.Client
+
+	dialTimeout  time.Duration
+	tls          *tls.Config
+	username     string
+	password     string
+	connAttempts int
+	connTimeout  time.Duration
+}
+
+// New creates a new etcd client for the given endpoints and waits until the cluster answers.
+// Default configuration: 5 seconds dial timeout, 10 connection attempts with 1 second between them,
+// no TLS and no authentication.
+//
+// Example:
+//
+//	e, err := etcd.New([]string{"etcd-1:2379", "etcd-2:2379", "etcd-3:2379"},
+//	    etcd.Auth("app", "secret"),
+//	)
+func New(endpoints []string, opts ...Option) (*Etcd, error) {
+	e := &Etcd{
+		dialTimeout:  defaultDialTimeout,
+		connAttempts: defaultConnAttempts,
+		connTimeout:  defaultConnTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: e.dialTimeout,
+		TLS:         e.tls,
+		Username:    e.username,
+		Password:    e.password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("etcd - New - clientv3.New: %w", err)
+	}
+
+	e.client = client
+
+	// No attempts skips the connectivity check.
+	if e.connAttempts <= 0 {
+		return e, nil
+	}
+
+	err = retry.Do(context.Background(), func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, e.dialTimeout)
+		defer cancel()
+
+		_, err := client.Status(ctx, endpoints[0])
+
+		return err
+	},
+		retry.Attempts(e.connAttempts),
+		retry.ConstantBackoff(e.connTimeout),
+		retry.OnRetry(func(attempt int, _ error, _ time.Duration) {
+			log.Printf("Etcd is trying to connect, attempts left: %d", e.connAttempts-attempt)
+		}),
+	)
+	if err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("etcd - New - client.Status: %w", err)
+	}
+
+	return e, nil
+}
+
+// Put stores a key-value pair without expiration.
+func (e *Etcd) Put(ctx context.Context, key, value string) error {
+	if _, err := e.client.Put(ctx, key, value); err != nil {
+		return fmt.Errorf("etcd - Put - e.client.Put: %w", err)
+	}
+
+	return nil
+}
+
+// PutWithTTL stores a key-value pair attached to a new lease of ttl,
+// so the key is deleted when the lease expires. TTLs are rounded up to seconds.
+// It returns the lease ID, which can be renewed with KeepAlive.
+func (e *Etcd) PutWithTTL(ctx context.Context, key, value string, ttl time.Duration) (clientv3.LeaseID, error) {
+	lease, err := e.client.Grant(ctx, seconds(ttl))
+	if err != nil {
+		return 0, fmt.Errorf("etcd - PutWithTTL - e.client.Grant: %w", err)
+	}
+
+	if _, err := e.client.Put(ctx, key, value, clientv3.WithLease(lease.ID)); err != nil {
+		return 0, fmt.Errorf("etcd - PutWithTTL - e.client.Put: %w", err)
+	}
+
+	return lease.ID, nil
+}
+
+// KeepAlive renews lease until ctx is done or the lease is revoked,
+// keeping its keys alive.
+func (e *Etcd) KeepAlive(ctx context.Context, lease clientv3.LeaseID) error {
+	ch, err := e.client.KeepAlive(ctx, lease)
+	if err != nil {
+		return fmt.Errorf("etcd - KeepAlive - e.client.KeepAlive: %w", err)
+	}
+
+	go func() {
+		// Responses must be drained for the keep alive to continue.
+		for range ch {
+		}
+	}()
+
+	return nil
+}
+
+// Get retrieves the value for the given key.
+// Returns empty string and nil error if key doesn't exist.
+func (e *Etcd) Get(ctx context.Context, key string) (string, error) {
+	resp, err := e.client.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("etcd - Get - e.client.Get: %w", err)
+	}
+
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+
+	return string(resp.Kvs[0].Value), nil
+}
+
+// GetPrefix returns all key-value pairs whose keys start with prefix.
+func (e *Etcd) GetPrefix(ctx context.Context, prefix string) (map[string]string, error) {
+	resp, err := e.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("etcd - GetPrefix - e.client.Get: %w", err)
+	}
+
+	kvs := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = string(kv.Value)
+	}
+
+	return kvs, nil
+}
+
+// Delete removes the key and reports whether it existed.
+func (e *Etcd) Delete(ctx context.Context, key string) (bool, error) {
+	resp, err := e.client.Delete(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("etcd - Delete - e.client.Delete: %w", err)
+	}
+
+	return resp.Deleted > 0, nil
+}
+
+// NewSession creates a concurrency session whose lease of ttl is kept alive until the
+// session is closed or the client is lost. Sessions back mutexes and elections.
+func (e *Etcd) NewSession(ctx context.Context, ttl time.Duration) (*concurrency.Session, error) {
+	session, err := concurrency.NewSession(e.client, concurrency.WithTTL(int(seconds(ttl))), concurrency.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("etcd - NewSession - concurrency.NewSession: %w", err)
+	}
+
+	return session, nil
+}
+
+// Close gracefully closes the etcd client connection.
+func (e *Etcd) Close() {
+	if e.client != nil {
+		err := e.client.Close()
+		if err != nil {
+			log.Printf("Error closing etcd client: %s", err)
+		}
+	}
+}
+
+// Client returns the underlying clientv3 client for operations not covered by Etcd.
+func (e *Etcd) Client() *clientv3.Client {
+	return e.client
+}
+
+// seconds converts ttl to etcd lease seconds, rounding up to at least one second.
+func seconds(ttl time.Duration) int64 {
+	s := int64((ttl + time.Second - 1) / time.Second)
+	if s < 1 {
+		return 1
+	}
+
+	return s
+}
@@ -0,0 +1,76 @@
+package etcd_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/etcd"
+)
+
+func TestNew_NoConnection(t *testing.T) {
+	e, err := etcd.New([]string{"127.0.0.1:65432"},
+		etcd.ConnAttempts(1),
+		etcd.DialTimeout(100*time.Millisecond),
+	)
+	if err == nil {
+		e.Close()
+		t.Skip("unexpected successful connection to etcd")
+	}
+}
+
+func TestNew_WithoutConnectivityCheck(t *testing.T) {
+	e, err := etcd.New([]string{"127.0.0.1:65432"}, etcd.ConnAttempts(0))
+	if err != nil {
+		t.Fatalf("expected no error from New(), got: %v", err)
+	}
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := e.Put(ctx, "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to etcd")
+	}
+}
+
+func TestIntegration(t *testing.T) {
+	e, err := etcd.New([]string{"localhost:2379"}, etcd.ConnAttempts(1), etcd.DialTimeout(time.Second))
+	if err != nil {
+		t.Skip("etcd server not available for integration test")
+	}
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events := e.WatchPrefix(ctx, "etcd-test/")
+
+	if err := e.Put(ctx, "etcd-test/a", "1"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if value, err := e.Get(ctx, "etcd-test/a"); err != nil || value != "1" {
+		t.Errorf("Get() = %q, %v, want 1", value, err)
+	}
+
+	if ev := <-events; ev.Err != nil || ev.Type != etcd.EventPut || ev.Key != "etcd-test/a" || ev.Value != "1" {
+		t.Errorf("watch event = %+v", ev)
+	}
+
+	if _, err := e.PutWithTTL(ctx, "etcd-test/ttl", "x", time.Second); err != nil {
+		t.Fatalf("PutWithTTL() error = %v", err)
+	}
+
+	if kvs, err := e.GetPrefix(ctx, "etcd-test/"); err != nil || len(kvs) != 2 {
+		t.Errorf("GetPrefix() = %v, %v, want 2 keys", kvs, err)
+	}
+
+	if deleted, err := e.Delete(ctx, "etcd-test/a"); err != nil || !deleted {
+		t.Errorf("Delete() = %v, %v, want true", deleted, err)
+	}
+
+	if value, _ := e.Get(ctx, "etcd-test/missing"); value != "" {
+		t.Errorf("Get() of a missing key = %q, want empty", value)
+	}
+}
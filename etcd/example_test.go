@@ -0,0 +1,28 @@
+package etcd_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/etcd"
+)
+
+func ExampleEtcd_WatchPrefix() {
+	e, err := etcd.New([]string{"localhost:2379"})
+	if err != nil {
+		return
+	}
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for ev := range e.WatchPrefix(ctx, "config/") {
+		if ev.Err != nil {
+			return
+		}
+
+		fmt.Println(ev.Key, ev.Value)
+	}
+}
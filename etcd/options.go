@@ -0,0 +1,50 @@
+package etcd
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// Option defines a function type for configuring Etcd instances.
+type Option func(*Etcd)
+
+// DialTimeout sets the timeout for establishing a connection and for the initial
+// connectivity check. Default is 5 seconds.
+func DialTimeout(timeout time.Duration) Option {
+	return func(e *Etcd) {
+		e.dialTimeout = timeout
+	}
+}
+
+// TLS sets the TLS configuration used to connect to the cluster.
+// Default is no TLS.
+func TLS(cfg *tls.Config) Option {
+	return func(e *Etcd) {
+		e.tls = cfg
+	}
+}
+
+// Auth sets the user credentials of the etcd authentication.
+// Default is no authentication.
+func Auth(username, password string) Option {
+	return func(e *Etcd) {
+		e.username = username
+		e.password = password
+	}
+}
+
+// ConnAttempts sets the number of connectivity checks made by New.
+// Zero skips the check. Default is 10.
+func ConnAttempts(attempts int) Option {
+	return func(e *Etcd) {
+		e.connAttempts = attempts
+	}
+}
+
+// ConnTimeout sets the wait between connectivity checks.
+// Default is 1 second.
+func ConnTimeout(timeout time.Duration) Option {
+	return func(e *Etcd) {
+		e.connTimeout = timeout
+	}
+}
@@ -0,0 +1,92 @@
+package etcd
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EventType is the kind of a watched change.
+type EventType int
+
+// Event types.
+const (
+	EventPut EventType = iota
+	EventDelete
+)
+
+// Event is a change of a watched key.
+type Event struct {
+	Type     EventType
+	Key      string
+	Value    string
+	Revision int64
+	// Err is set when the watch failed, e.g. because the requested revision was compacted.
+	// The channel is closed after an error event.
+	Err error
+}
+
+// Watch returns a channel receiving changes of key until ctx is done.
+func (e *Etcd) Watch(ctx context.Context, key string) <-chan Event {
+	return e.watch(ctx, key)
+}
+
+// WatchPrefix returns a channel receiving changes of every key starting with prefix
+// until ctx is done.
+//
+// Example:
+//
+//	for ev := range e.WatchPrefix(ctx, "config/") {
+//	    if ev.Err != nil {
+//	        return ev.Err
+//	    }
+//	    apply(ev.Key, ev.Value)
+//	}
+func (e *Etcd) WatchPrefix(ctx context.Context, prefix string) <-chan Event {
+	return e.watch(ctx, prefix, clientv3.WithPrefix())
+}
+
+func (e *Etcd) watch(ctx context.Context, key string, opts ...clientv3.OpOption) <-chan Event {
+	events := make(chan Event)
+
+	// The watch must be cancelled to release its resources, so it gets its own context.
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	watch := e.client.Watch(ctx, key, opts...)
+
+	go func() {
+		defer close(events)
+		defer cancel()
+
+		for resp := range watch {
+			if err := resp.Err(); err != nil {
+				select {
+				case events <- Event{Err: err}:
+				case <-ctx.Done():
+				}
+
+				return
+			}
+
+			for _, ev := range resp.Events {
+				event := Event{
+					Type:     EventPut,
+					Key:      string(ev.Kv.Key),
+					Value:    string(ev.Kv.Value),
+					Revision: ev.Kv.ModRevision,
+				}
+
+				if ev.Type == clientv3.EventTypeDelete {
+					event.Type = EventDelete
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
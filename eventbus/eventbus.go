@@ -0,0 +1,132 @@
+// Package eventbus provides a broker-agnostic publish/subscribe abstraction with typed
+// events and handler middleware. Implementations are backed by the kafka and rabbitmq
+// packages, plus an in-memory bus for tests, so application code does not depend on
+// broker-specific types.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+// Header names used to carry event metadata over brokers.
+const (
+	HeaderID   = "event-id"
+	HeaderTime = "event-time"
+)
+
+// ErrClosed is returned when publishing or subscribing on a closed bus.
+var ErrClosed = errors.New("eventbus - bus is closed")
+
+// Event is a message published on a topic.
+type Event struct {
+	// ID uniquely identifies the event; Publish generates one when empty.
+	ID string
+	// Topic is the destination: a Kafka topic or a RabbitMQ routing key.
+	Topic string
+	// Key is used by partitioned brokers to keep related events ordered.
+	Key string
+	// Headers carry metadata such as trace context.
+	Headers map[string]string
+	// Payload is the encoded event body.
+	Payload []byte
+	// Time is the event creation time; Publish sets it when zero.
+	Time time.Time
+}
+
+// Handler processes an event. A returned error marks the delivery as failed.
+type Handler func(ctx context.Context, e *Event) error
+
+// Publisher publishes events.
+type Publisher interface {
+	Publish(ctx context.Context, e *Event) error
+}
+
+// Subscriber delivers events of a topic to a handler until the bus is closed.
+// Subscribe registers the handler and returns; delivery happens in the background.
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic string, h Handler) error
+}
+
+// Bus publishes and subscribes.
+type Bus interface {
+	Publisher
+	Subscriber
+	Close() error
+}
+
+// PublishJSON publishes v encoded as JSON on topic.
+//
+// Example:
+//
+//	err := eventbus.PublishJSON(ctx, bus, "orders.created", OrderCreated{ID: id}, eventbus.WithKey(id))
+func PublishJSON[T any](ctx context.Context, p Publisher, topic string, v T, opts ...EventOption) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("eventbus - PublishJSON - json.Marshal: %w", err)
+	}
+
+	e := &Event{Topic: topic, Payload: payload}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return p.Publish(ctx, e)
+}
+
+// JSONHandler adapts a typed handler to Handler, decoding the payload as JSON.
+// Undecodable payloads fail the delivery without calling fn.
+//
+// Example:
+//
+//	err := bus.Subscribe(ctx, "orders.created", eventbus.JSONHandler(func(ctx context.Context, o OrderCreated, _ *eventbus.Event) error {
+//	    return svc.Handle(ctx, o)
+//	}))
+func JSONHandler[T any](fn func(ctx context.Context, v T, e *Event) error) Handler {
+	return func(ctx context.Context, e *Event) error {
+		var v T
+		if err := json.Unmarshal(e.Payload, &v); err != nil {
+			return fmt.Errorf("eventbus - JSONHandler - json.Unmarshal: %w", err)
+		}
+
+		return fn(ctx, v, e)
+	}
+}
+
+// EventOption configures an event published with PublishJSON.
+type EventOption func(*Event)
+
+// WithKey sets the event key.
+func WithKey(key string) EventOption {
+	return func(e *Event) {
+		e.Key = key
+	}
+}
+
+// WithHeader sets an event header.
+func WithHeader(key, value string) EventOption {
+	return func(e *Event) {
+		if e.Headers == nil {
+			e.Headers = make(map[string]string)
+		}
+
+		e.Headers[key] = value
+	}
+}
+
+// prepare fills the generated event fields.
+func prepare(e *Event) {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+}
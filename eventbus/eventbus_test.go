@@ -0,0 +1,203 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/containers"
+	"github.com/rdashevsky/go-pkgs/eventbus"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+type orderCreated struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func TestMemory(t *testing.T) {
+	bus := eventbus.NewMemory()
+
+	var got []orderCreated
+
+	err := bus.Subscribe(context.Background(), "orders.created", eventbus.JSONHandler(
+		func(_ context.Context, o orderCreated, e *eventbus.Event) error {
+			if e.ID == "" || e.Time.IsZero() || e.Key != "o-1" || e.Headers["source"] != "test" {
+				t.Errorf("event metadata = %+v", e)
+			}
+
+			got = append(got, o)
+
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	err = eventbus.PublishJSON(context.Background(), bus, "orders.created", orderCreated{ID: "o-1", Total: 10},
+		eventbus.WithKey("o-1"), eventbus.WithHeader("source", "test"))
+	if err != nil {
+		t.Fatalf("PublishJSON() error = %v", err)
+	}
+
+	if err := bus.Publish(context.Background(), &eventbus.Event{Topic: "other"}); err != nil {
+		t.Fatalf("Publish() to topic without subscribers error = %v", err)
+	}
+
+	if len(got) != 1 || got[0].Total != 10 {
+		t.Errorf("handled = %+v", got)
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := bus.Publish(context.Background(), &eventbus.Event{Topic: "orders.created"}); !errors.Is(err, eventbus.ErrClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var order []string
+
+	trace := func(name string) eventbus.Middleware {
+		return func(next eventbus.Handler) eventbus.Handler {
+			return func(ctx context.Context, e *eventbus.Event) error {
+				order = append(order, name)
+
+				return next(ctx, e)
+			}
+		}
+	}
+
+	attempts := 0
+
+	bus := eventbus.NewMemory(eventbus.Use(
+		trace("outer"),
+		eventbus.Recover(logger.New("error")),
+		eventbus.Retry(retry.Attempts(3), retry.ConstantBackoff(time.Millisecond)),
+		trace("inner"),
+	))
+
+	tests := []struct {
+		name    string
+		handler eventbus.Handler
+		wantErr bool
+	}{
+		{
+			name: "retried until success",
+			handler: func(context.Context, *eventbus.Event) error {
+				attempts++
+				if attempts < 3 {
+					return errors.New("temporary")
+				}
+
+				return nil
+			},
+		},
+		{
+			name:    "panic recovered",
+			handler: func(context.Context, *eventbus.Event) error { panic("boom") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := bus.Subscribe(context.Background(), tt.name, tt.handler); err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+
+			err := bus.Publish(context.Background(), &eventbus.Event{Topic: tt.name})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The panic escapes Retry and is recovered by the outer Recover, so it is not retried.
+	want := []string{"outer", "inner", "inner", "inner", "outer", "inner"}
+	if !slices.Equal(order, want) {
+		t.Errorf("middleware order = %v, want %v", order, want)
+	}
+}
+
+// collect subscribes to topic and returns a channel receiving handled events.
+func collect(t *testing.T, bus eventbus.Bus, topic string) <-chan *eventbus.Event {
+	t.Helper()
+
+	events := make(chan *eventbus.Event, 1)
+
+	var once sync.Once
+
+	err := bus.Subscribe(context.Background(), topic, func(_ context.Context, e *eventbus.Event) error {
+		once.Do(func() { events <- e })
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	return events
+}
+
+func TestRabbitMQ(t *testing.T) {
+	bus, err := eventbus.NewRabbitMQ(containers.RabbitMQ(t), "eventbus-test")
+	if err != nil {
+		t.Fatalf("NewRabbitMQ() error = %v", err)
+	}
+	defer bus.Close()
+
+	events := collect(t, bus, "orders.*")
+
+	err = bus.Publish(context.Background(), &eventbus.Event{Topic: "orders.created", Key: "o-1", Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case e := <-events:
+		if e.Topic != "orders.created" || e.Key != "o-1" || e.ID == "" {
+			t.Errorf("event = %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+func TestKafka(t *testing.T) {
+	cfg := containers.Kafka(t)
+	cfg.GroupID = "eventbus-test"
+	cfg.StartOffset = -2
+
+	bus, err := eventbus.NewKafka(cfg)
+	if err != nil {
+		t.Fatalf("NewKafka() error = %v", err)
+	}
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	topic := "eventbus-test-" + time.Now().Format("150405.000000")
+
+	if err := bus.Publish(ctx, &eventbus.Event{Topic: topic, Key: "o-1", Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	events := collect(t, bus, topic)
+
+	select {
+	case e := <-events:
+		if e.Topic != topic || e.Key != "o-1" || e.ID == "" {
+			t.Errorf("event = %+v", e)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("event not delivered")
+	}
+}
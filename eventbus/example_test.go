@@ -0,0 +1,28 @@
+package eventbus_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/eventbus"
+)
+
+func ExampleJSONHandler() {
+	type OrderCreated struct {
+		ID string `json:"id"`
+	}
+
+	// The in-memory bus stands in for NewKafka or NewRabbitMQ in tests.
+	var bus eventbus.Bus = eventbus.NewMemory()
+	defer bus.Close()
+
+	_ = bus.Subscribe(context.Background(), "orders.created", eventbus.JSONHandler(
+		func(_ context.Context, o OrderCreated, _ *eventbus.Event) error {
+			fmt.Println("order created:", o.ID)
+
+			return nil
+		}))
+
+	_ = eventbus.PublishJSON(context.Background(), bus, "orders.created", OrderCreated{ID: "o-1"})
+	// Output: order created: o-1
+}
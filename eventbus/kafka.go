@@ -0,0 +1,211 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Kafka is a Bus backed by Kafka. Events are consumed within the consumer group of
+// kafka.Config.GroupID; failed deliveries are logged and skipped, so handlers needing
+// redelivery should use the Retry middleware. Offsets are committed after each
+// polled batch when AutoCommit is disabled.
+type Kafka struct {
+	conn *kafka.Connection
+	opts options
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	started  bool
+	closed   bool
+	done     chan struct{}
+}
+
+var _ Bus = (*Kafka)(nil)
+
+// NewKafka creates a new Kafka bus and connects to the brokers.
+// cfg.GroupID is required to subscribe.
+//
+// Example:
+//
+//	bus, err := eventbus.NewKafka(kafka.Config{
+//	    Brokers: []string{"localhost:9092"},
+//	    GroupID: "billing",
+//	}, eventbus.WithLogger(l))
+func NewKafka(cfg kafka.Config, opts ...Option) (*Kafka, error) {
+	k := &Kafka{
+		conn:     kafka.NewConnection(cfg),
+		opts:     newOptions(opts),
+		handlers: make(map[string]Handler),
+		done:     make(chan struct{}),
+	}
+
+	if err := k.conn.Connect(context.Background()); err != nil {
+		return nil, fmt.Errorf("eventbus - NewKafka - k.conn.Connect: %w", err)
+	}
+
+	return k, nil
+}
+
+// Publish produces e to its topic and waits for the broker acknowledgement.
+func (k *Kafka) Publish(ctx context.Context, e *Event) error {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.closed {
+		return ErrClosed
+	}
+
+	prepare(e)
+
+	record := &kgo.Record{
+		Topic: e.Topic,
+		Value: e.Payload,
+		Headers: []kgo.RecordHeader{
+			{Key: HeaderID, Value: []byte(e.ID)},
+			{Key: HeaderTime, Value: []byte(e.Time.UTC().Format(time.RFC3339Nano))},
+		},
+		Timestamp: e.Time,
+	}
+
+	if e.Key != "" {
+		record.Key = []byte(e.Key)
+	}
+
+	for key, value := range e.Headers {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+
+	if err := k.conn.Client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("eventbus - Kafka - Publish - ProduceSync: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe adds topic to the consumed topics and starts consuming on first use.
+// Only one handler per topic is supported.
+func (k *Kafka) Subscribe(_ context.Context, topic string, h Handler) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.closed {
+		return ErrClosed
+	}
+
+	if k.conn.GroupID == "" {
+		return errors.New("eventbus - Kafka - Subscribe - kafka.Config.GroupID is required")
+	}
+
+	if _, ok := k.handlers[topic]; ok {
+		return fmt.Errorf("eventbus - Kafka - Subscribe - topic %q already has a handler", topic)
+	}
+
+	k.handlers[topic] = Chain(h, k.opts.middleware...)
+	k.conn.Client.AddConsumeTopics(topic)
+
+	if !k.started {
+		k.started = true
+
+		go k.consume()
+	}
+
+	return nil
+}
+
+func (k *Kafka) consume() {
+	defer close(k.done)
+
+	ctx := k.conn.Context()
+
+	for ctx.Err() == nil {
+		fetches := k.conn.Client.PollFetches(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		fetches.EachError(func(topic string, partition int32, err error) {
+			k.logError("eventbus - Kafka - consume - %s/%d: %v", topic, partition, err)
+		})
+
+		fetches.EachRecord(func(record *kgo.Record) {
+			k.mu.RLock()
+			h := k.handlers[record.Topic]
+			k.mu.RUnlock()
+
+			if h == nil {
+				return
+			}
+
+			e := eventFromRecord(record)
+
+			if err := h(ctx, e); err != nil {
+				k.logError("eventbus - Kafka - handler %s %s: %v", e.Topic, e.ID, err)
+			}
+		})
+
+		if !k.conn.AutoCommit {
+			if err := k.conn.Client.CommitUncommittedOffsets(ctx); err != nil && ctx.Err() == nil {
+				k.logError("eventbus - Kafka - consume - CommitUncommittedOffsets: %v", err)
+			}
+		}
+	}
+}
+
+func (k *Kafka) logError(format string, args ...interface{}) {
+	if k.opts.logger != nil {
+		k.opts.logger.Error(format, args...)
+	}
+}
+
+// Close stops consuming and closes the Kafka client.
+func (k *Kafka) Close() error {
+	k.mu.Lock()
+	if k.closed {
+		k.mu.Unlock()
+
+		return nil
+	}
+
+	k.closed = true
+	started := k.started
+	k.mu.Unlock()
+
+	k.conn.Close()
+
+	if started {
+		<-k.done
+	}
+
+	return nil
+}
+
+func eventFromRecord(record *kgo.Record) *Event {
+	e := &Event{
+		Topic:   record.Topic,
+		Key:     string(record.Key),
+		Payload: record.Value,
+		Time:    record.Timestamp,
+		Headers: make(map[string]string, len(record.Headers)),
+	}
+
+	for _, h := range record.Headers {
+		switch h.Key {
+		case HeaderID:
+			e.ID = string(h.Value)
+		case HeaderTime:
+			if t, err := time.Parse(time.RFC3339Nano, string(h.Value)); err == nil {
+				e.Time = t
+			}
+		default:
+			e.Headers[h.Key] = string(h.Value)
+		}
+	}
+
+	return e
+}
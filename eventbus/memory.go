@@ -0,0 +1,82 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// Memory is an in-memory Bus for tests. Publish delivers events synchronously to
+// every handler subscribed to the topic and returns their joined errors.
+type Memory struct {
+	opts options
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	closed   bool
+}
+
+var _ Bus = (*Memory)(nil)
+
+// NewMemory creates a new in-memory bus.
+func NewMemory(opts ...Option) *Memory {
+	return &Memory{
+		opts:     newOptions(opts),
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Publish delivers e to the topic handlers.
+func (m *Memory) Publish(ctx context.Context, e *Event) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+
+		return ErrClosed
+	}
+
+	handlers := m.handlers[e.Topic]
+	m.mu.RUnlock()
+
+	prepare(e)
+
+	var errs []error
+
+	for _, h := range handlers {
+		delivered := *e
+		delivered.Headers = maps.Clone(e.Headers)
+
+		if err := h(ctx, &delivered); err != nil {
+			errs = append(errs, fmt.Errorf("eventbus - Memory - Publish - %s: %w", e.Topic, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Subscribe registers h for topic.
+func (m *Memory) Subscribe(_ context.Context, topic string, h Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	m.handlers[topic] = append(m.handlers[topic], Chain(h, m.opts.middleware...))
+
+	return nil
+}
+
+// Close removes all subscriptions.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	m.handlers = nil
+
+	return nil
+}
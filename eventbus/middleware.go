@@ -0,0 +1,76 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// Middleware wraps a Handler.
+type Middleware func(Handler) Handler
+
+// Chain wraps h with middleware; the first middleware is the outermost.
+func Chain(h Handler, mw ...Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+
+	return h
+}
+
+// Recover turns handler panics into errors and logs their stack trace.
+func Recover(l logger.LoggerI) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("eventbus - handler panicked: %v", r)
+
+					l.Error("eventbus - handler for %s panicked: %v\n%s", e.Topic, r, debug.Stack())
+				}
+			}()
+
+			return next(ctx, e)
+		}
+	}
+}
+
+// Logger logs every handled event with its duration, and failures at error level.
+func Logger(l logger.LoggerI) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			start := time.Now()
+
+			err := next(ctx, e)
+			if err != nil {
+				l.Error("eventbus - %s %s - %s: %v", e.Topic, e.ID, time.Since(start), err)
+
+				return err
+			}
+
+			l.Debug("eventbus - %s %s - %s", e.Topic, e.ID, time.Since(start))
+
+			return nil
+		}
+	}
+}
+
+// Retry retries failed handlers in process with the retry package, before the
+// delivery is reported as failed to the broker.
+//
+// Example:
+//
+//	bus := eventbus.NewMemory(eventbus.Use(eventbus.Retry(retry.Attempts(5))))
+func Retry(opts ...retry.Option) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			return retry.Do(ctx, func(ctx context.Context) error {
+				return next(ctx, e)
+			}, opts...)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package eventbus
+
+import "github.com/rdashevsky/go-pkgs/logger"
+
+// Option is a function that configures a bus.
+type Option func(*options)
+
+type options struct {
+	group      string
+	middleware []Middleware
+	logger     logger.LoggerI
+}
+
+func newOptions(opts []Option) options {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Group sets the consumer group of RabbitMQ subscriptions: instances sharing a group
+// share a durable queue per topic, so each event is handled once per group.
+// Without a group every subscriber gets its own exclusive queue.
+// Kafka consumer groups are set with kafka.Config.GroupID.
+func Group(name string) Option {
+	return func(o *options) {
+		o.group = name
+	}
+}
+
+// Use adds middleware applied to every subscribed handler.
+func Use(mw ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// WithLogger sets the logger reporting failed deliveries and consumer errors.
+func WithLogger(l logger.LoggerI) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
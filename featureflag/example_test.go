@@ -0,0 +1,31 @@
+package featureflag_test
+
+import (
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/featureflag"
+)
+
+// Example demonstrates evaluating attribute based flags
+func Example() {
+	ff, err := featureflag.New(featureflag.StaticProvider(featureflag.Flag{
+		Name:    "beta-ui",
+		Enabled: true,
+		Rules: []featureflag.Rule{
+			{Attribute: "plan", Operator: featureflag.OpIn, Values: []string{"pro", "enterprise"}},
+		},
+	}))
+	if err != nil {
+		return
+	}
+	defer ff.Close()
+
+	for _, plan := range []string{"free", "pro"} {
+		ec := featureflag.EvalContext{Key: "user-1", Attributes: map[string]string{"plan": plan}}
+		fmt.Println(plan, ff.Enabled("beta-ui", ec))
+	}
+
+	// Output:
+	// free false
+	// pro true
+}
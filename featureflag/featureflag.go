@@ -0,0 +1,188 @@
+// Package featureflag provides feature flag evaluation with boolean, percentage rollout
+// and attribute based rules, file, environment and Redis providers with live updates,
+// and a Fiber middleware injecting evaluated flags into the request context.
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const _defaultWatchRetry = 5 * time.Second
+
+// Provider loads flag definitions.
+type Provider interface {
+	Load(ctx context.Context) ([]Flag, error)
+}
+
+// Watcher is implemented by providers able to push updates.
+// Watch blocks until ctx is done, calling notify whenever flags may have changed.
+type Watcher interface {
+	Watch(ctx context.Context, notify func()) error
+}
+
+// Client evaluates flags against the latest definitions loaded from a Provider.
+// It is safe for concurrent use.
+type Client struct {
+	provider        Provider
+	refreshInterval time.Duration
+	logger          logger.LoggerI
+
+	flags atomic.Pointer[map[string]Flag]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new Client and loads the initial flag definitions from provider.
+// If the provider implements Watcher, definitions are reloaded on every notification.
+// Default configuration: no periodic refresh, reload errors are not logged.
+//
+// Example:
+//
+//	ff, err := featureflag.New(featureflag.NewRedisProvider(r, "flags"),
+//	    featureflag.WithLogger(l),
+//	)
+//	defer ff.Close()
+//
+//	if ff.Enabled("new-checkout", featureflag.EvalContext{Key: userID}) {
+//	    // ...
+//	}
+func New(provider Provider, opts ...Option) (*Client, error) {
+	c := &Client{provider: provider}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.Reload(context.Background()); err != nil {
+		return nil, fmt.Errorf("featureflag - New - %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	if w, ok := provider.(Watcher); ok {
+		c.wg.Add(1)
+
+		go c.watch(ctx, w)
+	}
+
+	if c.refreshInterval > 0 {
+		c.wg.Add(1)
+
+		go c.refresh(ctx)
+	}
+
+	return c, nil
+}
+
+// Reload loads the flag definitions from the provider and replaces the current ones.
+func (c *Client) Reload(ctx context.Context) error {
+	list, err := c.provider.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("c.provider.Load: %w", err)
+	}
+
+	flags := make(map[string]Flag, len(list))
+	for _, f := range list {
+		flags[f.Name] = f
+	}
+
+	c.flags.Store(&flags)
+
+	return nil
+}
+
+// Enabled reports whether the named flag is on for ec. Unknown flags are off.
+func (c *Client) Enabled(name string, ec EvalContext) bool {
+	f, ok := (*c.flags.Load())[name]
+	if !ok {
+		return false
+	}
+
+	return f.Evaluate(ec)
+}
+
+// Evaluate evaluates every flag for ec.
+func (c *Client) Evaluate(ec EvalContext) Flags {
+	flags := *c.flags.Load()
+	result := make(Flags, len(flags))
+
+	for name, f := range flags {
+		result[name] = f.Evaluate(ec)
+	}
+
+	return result
+}
+
+// Flag returns the current definition of the named flag.
+func (c *Client) Flag(name string) (Flag, bool) {
+	f, ok := (*c.flags.Load())[name]
+
+	return f, ok
+}
+
+// Close stops watching and refreshing the provider.
+func (c *Client) Close() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *Client) watch(ctx context.Context, w Watcher) {
+	defer c.wg.Done()
+
+	notify := func() {
+		if err := c.Reload(ctx); err != nil {
+			c.logError(err)
+		}
+	}
+
+	for {
+		err := w.Watch(ctx, notify)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			c.logError(err)
+		}
+
+		// Updates may have been missed while not watching.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(_defaultWatchRetry):
+			notify()
+		}
+	}
+}
+
+func (c *Client) refresh(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx); err != nil {
+				c.logError(err)
+			}
+		}
+	}
+}
+
+func (c *Client) logError(err error) {
+	if c.logger != nil {
+		c.logger.Error(err, "featureflag - Client - reload")
+	}
+}
@@ -0,0 +1,283 @@
+package featureflag_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/containers"
+	"github.com/rdashevsky/go-pkgs/featureflag"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+func pct(n int) *int { return &n }
+
+func TestFlag_Evaluate(t *testing.T) {
+	pro := featureflag.EvalContext{Key: "u1", Attributes: map[string]string{"plan": "pro", "email": "a@corp.com"}}
+	free := featureflag.EvalContext{Key: "u2", Attributes: map[string]string{"plan": "free"}}
+
+	planRule := featureflag.Rule{Attribute: "plan", Operator: featureflag.OpIn, Values: []string{"pro", "enterprise"}}
+
+	testCases := []struct {
+		name string
+		flag featureflag.Flag
+		ec   featureflag.EvalContext
+		want bool
+	}{
+		{"disabled", featureflag.Flag{Enabled: false}, pro, false},
+		{"enabled", featureflag.Flag{Enabled: true}, pro, true},
+		{"zero percentage", featureflag.Flag{Enabled: true, Percentage: pct(0)}, pro, false},
+		{"full percentage", featureflag.Flag{Enabled: true, Percentage: pct(100)}, pro, true},
+		{"rule matches", featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{planRule}}, pro, true},
+		{"rule does not match", featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{planRule}}, free, false},
+		{
+			"rule miss falls back to percentage",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{planRule}, Percentage: pct(100)}, free, true,
+		},
+		{
+			"rule with zero rollout",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{{
+				Attribute: "plan", Operator: featureflag.OpEquals, Values: []string{"pro"}, Percentage: pct(0),
+			}}}, pro, false,
+		},
+		{
+			"suffix operator",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{{
+				Attribute: "email", Operator: featureflag.OpSuffix, Values: []string{"@corp.com"},
+			}}}, pro, true,
+		},
+		{
+			"not_in matches missing attribute",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{{
+				Attribute: "country", Operator: featureflag.OpNotIn, Values: []string{"US"},
+			}}}, pro, true,
+		},
+		{
+			"key attribute",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{{
+				Attribute: "key", Operator: featureflag.OpEquals, Values: []string{"u2"},
+			}}}, free, true,
+		},
+		{
+			"unknown operator",
+			featureflag.Flag{Enabled: true, Rules: []featureflag.Rule{{
+				Attribute: "plan", Operator: "regex", Values: []string{"pro"},
+			}}}, pro, false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.flag.Evaluate(tc.ec); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFlag_PercentageRollout(t *testing.T) {
+	f := featureflag.Flag{Name: "rollout", Enabled: true, Percentage: pct(30)}
+
+	on := 0
+
+	for i := 0; i < 10000; i++ {
+		ec := featureflag.EvalContext{Key: "user-" + strconv.Itoa(i)}
+
+		result := f.Evaluate(ec)
+		if result != f.Evaluate(ec) {
+			t.Fatal("expected sticky evaluation for the same key")
+		}
+
+		if result {
+			on++
+		}
+	}
+
+	if on < 2500 || on > 3500 {
+		t.Errorf("expected about 30%% of keys, got %d of 10000", on)
+	}
+}
+
+func TestClient(t *testing.T) {
+	ff, err := featureflag.New(featureflag.StaticProvider(
+		featureflag.Flag{Name: "on", Enabled: true},
+		featureflag.Flag{Name: "off"},
+	))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer ff.Close()
+
+	ec := featureflag.EvalContext{Key: "u1"}
+
+	if !ff.Enabled("on", ec) || ff.Enabled("off", ec) || ff.Enabled("unknown", ec) {
+		t.Error("unexpected flag evaluation")
+	}
+
+	flags := ff.Evaluate(ec)
+	if len(flags) != 2 || !flags.Enabled("on") || flags.Enabled("off") {
+		t.Errorf("unexpected evaluated flags: %v", flags)
+	}
+
+	if f, ok := ff.Flag("on"); !ok || !f.Enabled {
+		t.Error("expected flag definition to be returned")
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write flags: %v", err)
+		}
+	}
+
+	write(`[{"name": "beta", "enabled": false}]`)
+
+	ff, err := featureflag.New(featureflag.FileProvider(path), featureflag.RefreshInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer ff.Close()
+
+	if ff.Enabled("beta", featureflag.EvalContext{}) {
+		t.Fatal("expected beta to be off")
+	}
+
+	write(`[{"name": "beta", "enabled": true}]`)
+
+	deadline := time.Now().Add(time.Second)
+	for !ff.Enabled("beta", featureflag.EvalContext{}) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected refreshed flag to be on")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := featureflag.New(featureflag.FileProvider(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("FFTEST_NEW_CHECKOUT", "true")
+	t.Setenv("FFTEST_ROLLOUT", "0%")
+
+	flags, err := featureflag.EnvProvider("FFTEST_").Load(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	byName := map[string]featureflag.Flag{}
+	for _, f := range flags {
+		byName[f.Name] = f
+	}
+
+	if f := byName["new-checkout"]; !f.Enabled || f.Percentage != nil {
+		t.Errorf("unexpected new-checkout flag: %+v", f)
+	}
+
+	if f := byName["rollout"]; !f.Enabled || f.Percentage == nil || *f.Percentage != 0 {
+		t.Errorf("unexpected rollout flag: %+v", f)
+	}
+
+	t.Setenv("FFTEST_BROKEN", "maybe")
+
+	if _, err := featureflag.EnvProvider("FFTEST_").Load(context.Background()); err == nil {
+		t.Error("expected error for invalid value")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	ff, _ := featureflag.New(featureflag.StaticProvider(featureflag.Flag{
+		Name:    "beta",
+		Enabled: true,
+		Rules:   []featureflag.Rule{{Attribute: "key", Operator: featureflag.OpEquals, Values: []string{"tester"}}},
+	}))
+	defer ff.Close()
+
+	app := fiber.New()
+	app.Use(featureflag.Middleware(ff, func(c *fiber.Ctx) featureflag.EvalContext {
+		return featureflag.EvalContext{Key: c.Get("X-User-ID")}
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		if featureflag.FromContext(c.UserContext()).Enabled("beta") {
+			return c.SendString("beta")
+		}
+
+		return c.SendString("stable")
+	})
+
+	testCases := map[string]string{"tester": "beta", "someone": "stable"}
+
+	for user, want := range testCases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-User-ID", user)
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if string(body) != want {
+			t.Errorf("user %s: expected %q, got %q", user, want, body)
+		}
+	}
+
+	if featureflag.FromContext(context.Background()).Enabled("beta") {
+		t.Error("expected no flags in a bare context")
+	}
+}
+
+func TestRedisProvider_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer r.Close()
+
+	_, err = featureflag.New(featureflag.NewRedisProvider(r, "flags"))
+	if err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+}
+
+func TestRedisProvider_Integration(t *testing.T) {
+	r := containers.Redis(t)
+
+	ctx := context.Background()
+	p := featureflag.NewRedisProvider(r, "featureflag-test")
+
+	ff, err := featureflag.New(p)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer ff.Close()
+
+	// Give the watcher time to subscribe before publishing.
+	time.Sleep(100 * time.Millisecond)
+
+	if err := p.Set(ctx, featureflag.Flag{Name: "live", Enabled: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !ff.Enabled("live", featureflag.EvalContext{}) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected live update to enable the flag")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,112 @@
+package featureflag
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// Rule operators.
+const (
+	OpEquals    = "eq"
+	OpNotEquals = "neq"
+	OpIn        = "in"
+	OpNotIn     = "not_in"
+	OpPrefix    = "prefix"
+	OpSuffix    = "suffix"
+	OpContains  = "contains"
+)
+
+// Flag is a feature flag definition.
+//
+// A disabled flag always evaluates to false. Otherwise the first rule matching the
+// evaluation context decides; when no rule matches, Percentage rolls the flag out to
+// that share of keys, and without Percentage the flag is on only if it has no rules.
+type Flag struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Rules   []Rule `json:"rules,omitempty"`
+	// Percentage is the rollout percentage (0-100) applied when no rule matches.
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+// Rule targets a segment of evaluation contexts by attribute.
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+	// Percentage is the rollout percentage (0-100) within the matched segment.
+	// Nil means the whole segment.
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+// EvalContext describes the subject a flag is evaluated for.
+type EvalContext struct {
+	// Key identifies the subject (e.g. a user ID) and makes percentage rollouts sticky.
+	Key string
+	// Attributes are matched by rules. The "key" attribute resolves to Key when absent.
+	Attributes map[string]string
+}
+
+// Evaluate reports whether the flag is on for ec.
+func (f Flag) Evaluate(ec EvalContext) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	for _, r := range f.Rules {
+		if r.matches(ec) {
+			return f.inRollout(r.Percentage, ec.Key)
+		}
+	}
+
+	if f.Percentage != nil {
+		return f.inRollout(f.Percentage, ec.Key)
+	}
+
+	return len(f.Rules) == 0
+}
+
+func (f Flag) inRollout(percentage *int, key string) bool {
+	if percentage == nil {
+		return true
+	}
+
+	return bucket(f.Name, key) < *percentage
+}
+
+// bucket deterministically maps a flag and key to [0, 100).
+func bucket(flag, key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % 100)
+}
+
+func (r Rule) matches(ec EvalContext) bool {
+	value, ok := ec.Attributes[r.Attribute]
+	if !ok && r.Attribute == "key" {
+		value, ok = ec.Key, true
+	}
+
+	if !ok {
+		return r.Operator == OpNotEquals || r.Operator == OpNotIn
+	}
+
+	switch r.Operator {
+	case OpEquals, OpIn:
+		return slices.Contains(r.Values, value)
+	case OpNotEquals, OpNotIn:
+		return !slices.Contains(r.Values, value)
+	case OpPrefix:
+		return slices.ContainsFunc(r.Values, func(v string) bool { return strings.HasPrefix(value, v) })
+	case OpSuffix:
+		return slices.ContainsFunc(r.Values, func(v string) bool { return strings.HasSuffix(value, v) })
+	case OpContains:
+		return slices.ContainsFunc(r.Values, func(v string) bool { return strings.Contains(value, v) })
+	default:
+		return false
+	}
+}
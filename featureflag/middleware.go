@@ -0,0 +1,55 @@
+package featureflag
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type contextKey struct{}
+
+// Flags holds evaluated flag values by name.
+type Flags map[string]bool
+
+// Enabled reports whether the named flag is on. Unknown flags are off.
+func (f Flags) Enabled(name string) bool {
+	return f[name]
+}
+
+// NewContext returns a copy of ctx carrying flags.
+func NewContext(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, contextKey{}, flags)
+}
+
+// FromContext returns the flags stored in ctx, or nil if there are none.
+// A nil Flags reports every flag as off.
+func FromContext(ctx context.Context) Flags {
+	flags, _ := ctx.Value(contextKey{}).(Flags)
+
+	return flags
+}
+
+// Middleware returns a Fiber middleware evaluating all flags for every request and storing
+// them in the request user context, where handlers and services read them with FromContext.
+// evalContext builds the evaluation context from the request, e.g. from an authenticated user.
+//
+// Example:
+//
+//	app.Use(featureflag.Middleware(ff, func(c *fiber.Ctx) featureflag.EvalContext {
+//	    return featureflag.EvalContext{Key: c.Get("X-User-ID")}
+//	}))
+//
+//	app.Get("/checkout", func(c *fiber.Ctx) error {
+//	    if featureflag.FromContext(c.UserContext()).Enabled("new-checkout") {
+//	        // ...
+//	    }
+//	})
+func Middleware(c *Client, evalContext func(ctx *fiber.Ctx) EvalContext) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		flags := c.Evaluate(evalContext(ctx))
+
+		ctx.SetUserContext(NewContext(ctx.UserContext(), flags))
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package featureflag
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// RefreshInterval periodically reloads the flag definitions from the provider,
+// e.g. to pick up changes to a flags file.
+// Default is 0, no periodic refresh.
+func RefreshInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.refreshInterval = interval
+	}
+}
+
+// WithLogger logs background reload errors.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
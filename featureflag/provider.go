@@ -0,0 +1,107 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+type staticProvider []Flag
+
+// StaticProvider returns a Provider serving fixed flag definitions.
+func StaticProvider(flags ...Flag) Provider {
+	return staticProvider(flags)
+}
+
+func (p staticProvider) Load(context.Context) ([]Flag, error) {
+	return p, nil
+}
+
+type fileProvider struct {
+	path string
+}
+
+// FileProvider returns a Provider reading a JSON array of flags from path.
+// Combine it with the RefreshInterval option to pick up file changes.
+//
+// Example file:
+//
+//	[
+//	  {"name": "new-checkout", "enabled": true, "percentage": 25},
+//	  {"name": "beta-ui", "enabled": true, "rules": [
+//	    {"attribute": "plan", "operator": "in", "values": ["pro", "enterprise"]}
+//	  ]}
+//	]
+func FileProvider(path string) Provider {
+	return fileProvider{path: path}
+}
+
+func (p fileProvider) Load(context.Context) ([]Flag, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("featureflag - FileProvider - os.ReadFile: %w", err)
+	}
+
+	var flags []Flag
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("featureflag - FileProvider - json.Unmarshal: %w", err)
+	}
+
+	return flags, nil
+}
+
+type envProvider struct {
+	prefix string
+}
+
+// EnvProvider returns a Provider reading flags from environment variables starting with prefix.
+// The rest of the variable name, lower-cased with underscores replaced by dashes, is the flag name.
+// Values are booleans ("true", "false", "1", "0") or rollout percentages ("25%").
+//
+// Example:
+//
+//	// FF_NEW_CHECKOUT=25% defines the "new-checkout" flag rolled out to 25% of keys.
+//	p := featureflag.EnvProvider("FF_")
+func EnvProvider(prefix string) Provider {
+	return envProvider{prefix: prefix}
+}
+
+func (p envProvider) Load(context.Context) ([]Flag, error) {
+	var flags []Flag
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+
+		name, ok := strings.CutPrefix(key, p.prefix)
+		if !ok || name == "" {
+			continue
+		}
+
+		f := Flag{Name: strings.ReplaceAll(strings.ToLower(name), "_", "-")}
+
+		if pct, ok := strings.CutSuffix(value, "%"); ok {
+			n, err := strconv.Atoi(pct)
+			if err != nil || n < 0 || n > 100 {
+				return nil, fmt.Errorf("featureflag - EnvProvider - %s: invalid percentage %q", key, value)
+			}
+
+			f.Enabled = true
+			f.Percentage = &n
+		} else {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("featureflag - EnvProvider - %s: %w", key, err)
+			}
+
+			f.Enabled = enabled
+		}
+
+		flags = append(flags, f)
+	}
+
+	return flags, nil
+}
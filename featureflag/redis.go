@@ -0,0 +1,119 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// RedisProvider stores flag definitions as JSON in a Redis hash keyed by flag name,
+// and publishes change notifications on the "<key>:updates" channel for live updates.
+type RedisProvider struct {
+	r   *redis.Redis
+	key string
+}
+
+var (
+	_ Provider = (*RedisProvider)(nil)
+	_ Watcher  = (*RedisProvider)(nil)
+)
+
+// NewRedisProvider returns a Provider reading flags from the Redis hash at key.
+//
+// Example:
+//
+//	p := featureflag.NewRedisProvider(r, "flags")
+//	err := p.Set(ctx, featureflag.Flag{Name: "new-checkout", Enabled: true})
+func NewRedisProvider(r *redis.Redis, key string) *RedisProvider {
+	return &RedisProvider{r: r, key: key}
+}
+
+// Load reads all flags from the hash.
+func (p *RedisProvider) Load(ctx context.Context) ([]Flag, error) {
+	values, err := p.r.Client().HGetAll(ctx, p.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("featureflag - RedisProvider - Load - HGetAll: %w", err)
+	}
+
+	flags := make([]Flag, 0, len(values))
+
+	for name, value := range values {
+		var f Flag
+		if err := json.Unmarshal([]byte(value), &f); err != nil {
+			return nil, fmt.Errorf("featureflag - RedisProvider - Load - flag %q: %w", name, err)
+		}
+
+		f.Name = name
+		flags = append(flags, f)
+	}
+
+	return flags, nil
+}
+
+// Watch subscribes to change notifications until ctx is done.
+func (p *RedisProvider) Watch(ctx context.Context, notify func()) error {
+	sub := p.r.Client().Subscribe(ctx, p.channel())
+	defer sub.Close()
+
+	// Wait for the subscription to be confirmed so no update is missed after it.
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("featureflag - RedisProvider - Watch - Subscribe: %w", err)
+	}
+
+	// Catch up on changes made before the subscription.
+	notify()
+
+	ch := sub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("featureflag - RedisProvider - Watch - subscription closed")
+			}
+
+			notify()
+		}
+	}
+}
+
+// Set creates or replaces a flag and notifies watchers.
+func (p *RedisProvider) Set(ctx context.Context, f Flag) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("featureflag - RedisProvider - Set - json.Marshal: %w", err)
+	}
+
+	if err := p.r.Client().HSet(ctx, p.key, f.Name, data).Err(); err != nil {
+		return fmt.Errorf("featureflag - RedisProvider - Set - HSet: %w", err)
+	}
+
+	return p.publish(ctx)
+}
+
+// Delete removes a flag and notifies watchers.
+func (p *RedisProvider) Delete(ctx context.Context, name string) error {
+	if err := p.r.Client().HDel(ctx, p.key, name).Err(); err != nil {
+		return fmt.Errorf("featureflag - RedisProvider - Delete - HDel: %w", err)
+	}
+
+	return p.publish(ctx)
+}
+
+func (p *RedisProvider) publish(ctx context.Context) error {
+	if err := p.r.Client().Publish(ctx, p.channel(), "changed").Err(); err != nil {
+		return fmt.Errorf("featureflag - RedisProvider - Publish: %w", err)
+	}
+
+	return nil
+}
+
+// channel returns the updates channel, prefixed like the key with the KeyPrefix of the
+// client as channels are not keys.
+func (p *RedisProvider) channel() string {
+	return p.r.Key(p.key + ":updates")
+}
@@ -0,0 +1,36 @@
+package graphqlserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+const _apqPrefix = "graphql:apq:"
+
+type redisCache struct {
+	r   *redis.Redis
+	ttl time.Duration
+}
+
+// RedisCache returns a persisted query cache stored in Redis for ttl, shared by every
+// instance of the service.
+func RedisCache(r *redis.Redis, ttl time.Duration) graphql.Cache[string] {
+	return &redisCache{r: r, ttl: ttl}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) (string, bool) {
+	value, err := c.r.Get(ctx, _apqPrefix+key)
+	if err != nil || value == "" {
+		return "", false
+	}
+
+	return value, true
+}
+
+func (c *redisCache) Add(ctx context.Context, key string, value string) {
+	// A failed write only costs the client a retry with the full query.
+	_ = c.r.SetWithTTL(ctx, _apqPrefix+key, value, c.ttl)
+}
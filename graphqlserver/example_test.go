@@ -0,0 +1,39 @@
+package graphqlserver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/rdashevsky/go-pkgs/graphqlserver"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+)
+
+func ExampleNew() {
+	rest := httpserver.New(httpserver.Port("8080"))
+
+	// The schema is usually generated.NewExecutableSchema(generated.Config{Resolvers: ...}).
+	graphqlserver.New(newSchema(),
+		graphqlserver.MountOn(rest),
+		graphqlserver.ComplexityLimit(200),
+		graphqlserver.PersistedQueries(nil),
+		graphqlserver.Dataloaders(func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, ctxKey("loader"), "loaders")
+		}),
+	)
+
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"{ greeting }"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rest.App.Test(req)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+	defer resp.Body.Close()
+
+	fmt.Println(resp.StatusCode)
+	// Output: 200
+}
@@ -0,0 +1,167 @@
+// Package graphqlserver serves gqlgen executable schemas on an httpserver.Server,
+// with an optional playground, query complexity limits, automatic persisted queries
+// and per request dataloader wiring, following the Start/Notify/Shutdown lifecycle.
+package graphqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	_defaultPath           = "/graphql"
+	_defaultQueryCacheSize = 1000
+	_defaultAPQCacheSize   = 1000
+)
+
+// Server serves a GraphQL schema over HTTP.
+type Server struct {
+	// Handler is the underlying gqlgen server, to add extensions and middleware.
+	Handler *handler.Server
+	// HTTP is the server the GraphQL endpoint is mounted on.
+	HTTP *httpserver.Server
+
+	cfg config
+}
+
+// New creates a new Server for schema, usually built with the gqlgen generated
+// NewExecutableSchema. Queries are accepted as GET, POST and multipart POST requests;
+// subscriptions over WebSocket are not supported by the Fiber based httpserver.
+// Errors wrapping an *errorsx.Error are presented with its message and a "code" extension.
+// Default configuration: endpoint /graphql on a new httpserver.Server, introspection
+// enabled, no playground, no complexity limit, no persisted queries.
+//
+// Example:
+//
+//	srv := graphqlserver.New(generated.NewExecutableSchema(generated.Config{Resolvers: r}),
+//	    graphqlserver.HTTPOptions(httpserver.Port("8080")),
+//	    graphqlserver.Playground("/"),
+//	    graphqlserver.ComplexityLimit(200),
+//	    graphqlserver.PersistedQueries(graphqlserver.RedisCache(rdb, 24*time.Hour)),
+//	    graphqlserver.Dataloaders(loaders.NewContext),
+//	)
+//	srv.Start()
+func New(schema graphql.ExecutableSchema, opts ...Option) *Server {
+	cfg := newConfig(opts)
+
+	h := handler.New(schema)
+	h.AddTransport(transport.Options{})
+	h.AddTransport(transport.GET{})
+	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{})
+	h.SetQueryCache(lru.New[*ast.QueryDocument](cfg.queryCacheSize))
+	h.SetErrorPresenter(presentError)
+	h.SetRecoverFunc(cfg.recover)
+
+	if cfg.introspection {
+		h.Use(extension.Introspection{})
+	}
+
+	if cfg.complexityLimit > 0 {
+		h.Use(extension.FixedComplexityLimit(cfg.complexityLimit))
+	}
+
+	if cfg.persistedQueries {
+		apqCache := cfg.apqCache
+		if apqCache == nil {
+			apqCache = lru.New[string](_defaultAPQCacheSize)
+		}
+
+		h.Use(extension.AutomaticPersistedQuery{Cache: apqCache})
+	}
+
+	s := &Server{Handler: h, HTTP: cfg.http, cfg: cfg}
+	if s.HTTP == nil {
+		s.HTTP = httpserver.New(cfg.httpOptions...)
+	}
+
+	s.HTTP.App.All(cfg.path, s.handler())
+
+	if cfg.playgroundPath != "" {
+		s.HTTP.App.Get(cfg.playgroundPath, adaptor.HTTPHandler(playground.Handler(cfg.playgroundTitle, cfg.path)))
+	}
+
+	return s
+}
+
+// handler serves GraphQL requests with the Fiber user context, so values set by
+// middleware such as JWT claims reach resolvers.
+func (s *Server) handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		if s.cfg.dataloaders != nil {
+			ctx = s.cfg.dataloaders(ctx)
+		}
+
+		return adaptor.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.Handler.ServeHTTP(w, r.WithContext(ctx))
+		}))(c)
+	}
+}
+
+// Start begins serving requests. When the server is mounted on a shared
+// httpserver.Server with MountOn, start either of them, not both.
+func (s *Server) Start() {
+	s.HTTP.Start()
+}
+
+// Notify returns a channel receiving the HTTP server lifecycle errors.
+func (s *Server) Notify() <-chan error {
+	return s.HTTP.Notify()
+}
+
+// Shutdown gracefully shuts down the HTTP server.
+func (s *Server) Shutdown() error {
+	if err := s.HTTP.Shutdown(); err != nil {
+		return fmt.Errorf("graphqlserver - Shutdown - s.HTTP.Shutdown: %w", err)
+	}
+
+	return nil
+}
+
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var e *errorsx.Error
+	if errors.As(err, &e) {
+		if e.Message != "" {
+			gqlErr.Message = e.Message
+		}
+
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{}, 1+len(e.Metadata))
+		}
+
+		gqlErr.Extensions["code"] = string(errorsx.CodeOf(err))
+
+		for k, v := range errorsx.MetadataOf(err) {
+			gqlErr.Extensions[k] = v
+		}
+	}
+
+	return gqlErr
+}
+
+func (c config) recover(_ context.Context, err interface{}) error {
+	if c.logger != nil {
+		c.logger.Error("graphqlserver - resolver panicked: %v\n%s", err, debug.Stack())
+	}
+
+	return errorsx.Internal("internal server error")
+}
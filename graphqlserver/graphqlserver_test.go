@@ -0,0 +1,164 @@
+package graphqlserver_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	"github.com/rdashevsky/go-pkgs/graphqlserver"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type ctxKey string
+
+// newSchema returns a schema resolving {greeting} from the context values set by
+// middleware and dataloaders, and failing {fail} with a coded error.
+func newSchema() graphql.ExecutableSchema {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query {
+			greeting: String!
+			fail: String!
+		}
+	`})
+
+	return &graphql.ExecutableSchemaMock{
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			op := graphql.GetOperationContext(ctx)
+
+			if strings.Contains(op.RawQuery, "fail") {
+				graphql.AddError(ctx, errorsx.NotFound("order not found").WithMeta("order_id", "7"))
+
+				return graphql.OneShot(&graphql.Response{Errors: graphql.GetErrors(ctx)})
+			}
+
+			user, _ := ctx.Value(ctxKey("user")).(string)
+			loader, _ := ctx.Value(ctxKey("loader")).(string)
+			data, _ := json.Marshal(map[string]string{"greeting": user + "/" + loader})
+
+			return graphql.OneShot(&graphql.Response{Data: data})
+		},
+		SchemaFunc: func() *ast.Schema {
+			return schema
+		},
+		ComplexityFunc: func(context.Context, string, string, int, map[string]interface{}) (int, bool) {
+			return 10, true
+		},
+	}
+}
+
+type gqlResponse struct {
+	Data   map[string]string `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, app *fiber.App, body string) gqlResponse {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out gqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	return out
+}
+
+func TestServer_Query(t *testing.T) {
+	rest := httpserver.New()
+	rest.App.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), ctxKey("user"), "ann"))
+
+		return c.Next()
+	})
+
+	graphqlserver.New(newSchema(),
+		graphqlserver.MountOn(rest),
+		graphqlserver.Dataloaders(func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, ctxKey("loader"), "loaders")
+		}),
+	)
+
+	out := post(t, rest.App, `{"query":"{ greeting }"}`)
+	if len(out.Errors) > 0 || out.Data["greeting"] != "ann/loaders" {
+		t.Errorf("response = %+v, want greeting ann/loaders", out)
+	}
+}
+
+func TestServer_ErrorPresenter(t *testing.T) {
+	srv := graphqlserver.New(newSchema())
+
+	out := post(t, srv.HTTP.App, `{"query":"{ fail }"}`)
+	if len(out.Errors) != 1 {
+		t.Fatalf("errors = %+v, want 1 error", out.Errors)
+	}
+
+	e := out.Errors[0]
+	if e.Message != "order not found" || e.Extensions["code"] != "not_found" || e.Extensions["order_id"] != "7" {
+		t.Errorf("error = %+v", e)
+	}
+}
+
+func TestServer_ComplexityLimit(t *testing.T) {
+	srv := graphqlserver.New(newSchema(), graphqlserver.ComplexityLimit(5))
+
+	out := post(t, srv.HTTP.App, `{"query":"{ greeting }"}`)
+	if len(out.Errors) == 0 || !strings.Contains(out.Errors[0].Message, "complexity") {
+		t.Errorf("response = %+v, want complexity error", out)
+	}
+}
+
+func TestServer_PersistedQueries(t *testing.T) {
+	srv := graphqlserver.New(newSchema(), graphqlserver.PersistedQueries(nil))
+
+	query := "{ greeting }"
+	sum := sha256.Sum256([]byte(query))
+	ext := `"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hex.EncodeToString(sum[:]) + `"}}`
+
+	if out := post(t, srv.HTTP.App, `{`+ext+`}`); len(out.Errors) == 0 || out.Errors[0].Message != "PersistedQueryNotFound" {
+		t.Fatalf("unknown hash: response = %+v, want PersistedQueryNotFound", out)
+	}
+
+	if out := post(t, srv.HTTP.App, `{"query":"`+query+`",`+ext+`}`); len(out.Errors) > 0 {
+		t.Fatalf("registration: errors = %+v", out.Errors)
+	}
+
+	if out := post(t, srv.HTTP.App, `{`+ext+`}`); len(out.Errors) > 0 {
+		t.Errorf("persisted hash: errors = %+v", out.Errors)
+	}
+}
+
+func TestServer_Playground(t *testing.T) {
+	srv := graphqlserver.New(newSchema(), graphqlserver.Playground("/playground"))
+
+	resp, err := srv.HTTP.App.Test(httptest.NewRequest("GET", "/playground", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(body), "/graphql") {
+		t.Errorf("playground: status %d, body does not reference the endpoint", resp.StatusCode)
+	}
+}
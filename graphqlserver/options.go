@@ -0,0 +1,135 @@
+package graphqlserver
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option configures a Server.
+type Option func(*config)
+
+type config struct {
+	http        *httpserver.Server
+	httpOptions []httpserver.Option
+
+	path            string
+	playgroundPath  string
+	playgroundTitle string
+	introspection   bool
+	complexityLimit int
+	queryCacheSize  int
+
+	persistedQueries bool
+	apqCache         graphql.Cache[string]
+
+	dataloaders func(ctx context.Context) context.Context
+	logger      logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		path:            _defaultPath,
+		playgroundTitle: "GraphQL playground",
+		introspection:   true,
+		queryCacheSize:  _defaultQueryCacheSize,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// MountOn mounts the GraphQL endpoint on an existing HTTP server, next to its REST routes.
+// Default is a new httpserver.Server configured with HTTPOptions.
+func MountOn(s *httpserver.Server) Option {
+	return func(c *config) {
+		c.http = s
+	}
+}
+
+// HTTPOptions configures the HTTP server created when MountOn is not used.
+func HTTPOptions(opts ...httpserver.Option) Option {
+	return func(c *config) {
+		c.httpOptions = append(c.httpOptions, opts...)
+	}
+}
+
+// Path sets the path of the GraphQL endpoint.
+// Default is "/graphql".
+func Path(path string) Option {
+	return func(c *config) {
+		c.path = path
+	}
+}
+
+// Playground serves the GraphiQL playground at path.
+// Default is no playground.
+func Playground(path string) Option {
+	return func(c *config) {
+		c.playgroundPath = path
+	}
+}
+
+// DisableIntrospection rejects introspection queries, hiding the schema from clients.
+// Default is introspection enabled.
+func DisableIntrospection() Option {
+	return func(c *config) {
+		c.introspection = false
+	}
+}
+
+// ComplexityLimit rejects queries whose complexity exceeds limit. Field complexity
+// defaults to 1 and is customized with the gqlgen generated Complexity functions.
+// Default is no limit.
+func ComplexityLimit(limit int) Option {
+	return func(c *config) {
+		c.complexityLimit = limit
+	}
+}
+
+// QueryCacheSize sets the number of parsed queries cached.
+// Default is 1000.
+func QueryCacheSize(size int) Option {
+	return func(c *config) {
+		c.queryCacheSize = size
+	}
+}
+
+// PersistedQueries enables automatic persisted queries: clients send the SHA-256 hash
+// of a query instead of its text once it has been registered in cache. A nil cache
+// uses an in-memory LRU of 1000 queries; use RedisCache to share queries between instances.
+// Default is disabled.
+func PersistedQueries(cache graphql.Cache[string]) Option {
+	return func(c *config) {
+		c.persistedQueries = true
+		c.apqCache = cache
+	}
+}
+
+// Dataloaders calls newContext for every request to attach fresh dataloaders to the
+// context passed to resolvers, so batching and caching never leak between requests.
+// Default is no dataloaders.
+//
+// Example:
+//
+//	graphqlserver.Dataloaders(func(ctx context.Context) context.Context {
+//	    return context.WithValue(ctx, loadersKey{}, NewLoaders(repo))
+//	})
+func Dataloaders(newContext func(ctx context.Context) context.Context) Option {
+	return func(c *config) {
+		c.dataloaders = newContext
+	}
+}
+
+// WithLogger logs resolver panics, which are returned to clients as internal errors.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,134 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/jwt"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// _readMethodPrefixes are the method name prefixes of calls not audited.
+var _readMethodPrefixes = []string{"Get", "List", "Search", "Find", "Watch", "Check", "Describe", "Count"}
+
+// UnaryAudit returns a unary interceptor recording an audit event with a for every
+// mutating call, i.e. every method whose name does not start with Get, List, Search,
+// Find, Watch, Check, Describe or Count. The event action is the full method name
+// and its outcome is a failure when the handler returns an error, whose code is
+// stored in the "code" metadata. The actor is resolved by actor once the handler
+// ran; when nil, it is the subject of the claims stored by the JWT interceptors.
+// Handlers enrich the event with audit.FromContext, or skip it with audit.Skip.
+func UnaryAudit(a *audit.Auditor, actor func(ctx context.Context) string) pbgrpc.UnaryServerInterceptor {
+	au := newAuditor(a, actor)
+
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		if !isMutation(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, e := au.begin(ctx)
+
+		resp, err := handler(ctx, req)
+
+		au.end(ctx, e, info.FullMethod, err)
+
+		return resp, err
+	}
+}
+
+// StreamAudit is the streaming counterpart of UnaryAudit. One event is recorded
+// per stream, when the handler returns.
+func StreamAudit(a *audit.Auditor, actor func(ctx context.Context) string) pbgrpc.StreamServerInterceptor {
+	au := newAuditor(a, actor)
+
+	return func(srv interface{}, ss pbgrpc.ServerStream, info *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler) error {
+		if !isMutation(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, e := au.begin(ss.Context())
+
+		err := handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+
+		au.end(ctx, e, info.FullMethod, err)
+
+		return err
+	}
+}
+
+type auditor struct {
+	auditor *audit.Auditor
+	actor   func(ctx context.Context) string
+}
+
+func newAuditor(a *audit.Auditor, actor func(ctx context.Context) string) *auditor {
+	if actor == nil {
+		actor = jwtSubject
+	}
+
+	return &auditor{auditor: a, actor: actor}
+}
+
+func (a *auditor) begin(ctx context.Context) (context.Context, *audit.Event) {
+	e := &audit.Event{IP: peerAddress(ctx, "")}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			e.UserAgent = ua[0]
+		}
+	}
+
+	return audit.NewContext(ctx, e), e
+}
+
+func (a *auditor) end(ctx context.Context, e *audit.Event, method string, err error) {
+	if e.Skipped() {
+		return
+	}
+
+	if e.Action == "" {
+		e.Action = method
+	}
+
+	e.Outcome = audit.Success
+	if err != nil {
+		e.Outcome = audit.Failure
+	}
+
+	e.SetMetadata("code", status.Code(err).String())
+
+	if e.Actor == "" {
+		e.Actor = a.actor(ctx)
+	}
+
+	a.auditor.Record(ctx, e)
+}
+
+// isMutation reports whether the method of fullMethod ("/package.Service/Method")
+// may change state.
+func isMutation(fullMethod string) bool {
+	method := fullMethod[strings.LastIndexByte(fullMethod, '/')+1:]
+
+	for _, prefix := range _readMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jwtSubject returns the subject of the JWT claims stored in ctx, if any.
+func jwtSubject(ctx context.Context) string {
+	claims, ok := jwt.FromContext[jwt.Claims](ctx)
+	if !ok {
+		return ""
+	}
+
+	sub, _ := claims.GetSubject()
+
+	return sub
+}
@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAudit(t *testing.T) {
+	var events []*audit.Event
+
+	a := audit.New(audit.SinkFunc(func(_ context.Context, batch []*audit.Event) error {
+		events = append(events, batch...)
+
+		return nil
+	}))
+	a.Start()
+
+	unary := UnaryAudit(a, nil)
+	ctx := jwt.NewContext(context.Background(), &jwt.RegisteredClaims{Subject: "user-1"})
+
+	tests := []struct {
+		method string
+		err    error
+	}{
+		{method: "/orders.v1.OrderService/GetOrder"},
+		{method: "/orders.v1.OrderService/CancelOrder"},
+		{method: "/orders.v1.OrderService/DeleteOrder", err: status.Error(codes.NotFound, "not found")},
+	}
+
+	for _, tt := range tests {
+		_, _ = unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+			func(ctx context.Context, _ interface{}) (interface{}, error) {
+				if e := audit.FromContext(ctx); e != nil {
+					e.Resource = "order"
+				}
+
+				return nil, tt.err
+			})
+	}
+
+	_ = a.Shutdown()
+
+	want := []struct {
+		action  string
+		outcome audit.Outcome
+		code    string
+	}{
+		{action: "/orders.v1.OrderService/CancelOrder", outcome: audit.Success, code: "OK"},
+		{action: "/orders.v1.OrderService/DeleteOrder", outcome: audit.Failure, code: "NotFound"},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("recorded events = %d, want %d", len(events), len(want))
+	}
+
+	for i, w := range want {
+		e := events[i]
+		if e.Action != w.action || e.Outcome != w.outcome || e.Metadata["code"] != w.code || e.Actor != "user-1" || e.Resource != "order" {
+			t.Errorf("event %d = %+v, want action %s, outcome %s, code %s by user-1 on order", i, e, w.action, w.outcome, w.code)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryJWTAuth returns a unary interceptor authenticating calls with a bearer token
+// from the "authorization" metadata, verified by j. newClaims returns a pointer to the
+// claims type tokens are decoded into; when nil, *jwt.RegisteredClaims is used.
+// Verified claims are stored in the handler context and read with jwt.FromContext.
+// Calls to publicMethods (full method names, e.g. "/grpc.health.v1.Health/Check")
+// are not authenticated. Other calls without a valid token fail with codes.Unauthenticated.
+func UnaryJWTAuth(j *jwt.JWT, newClaims func() jwt.Claims, publicMethods ...string) pbgrpc.UnaryServerInterceptor {
+	a := newAuthenticator(j, newClaims, publicMethods)
+
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamJWTAuth is the streaming counterpart of UnaryJWTAuth.
+func StreamJWTAuth(j *jwt.JWT, newClaims func() jwt.Claims, publicMethods ...string) pbgrpc.StreamServerInterceptor {
+	a := newAuthenticator(j, newClaims, publicMethods)
+
+	return func(srv interface{}, ss pbgrpc.ServerStream, info *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authenticator struct {
+	jwt           *jwt.JWT
+	newClaims     func() jwt.Claims
+	publicMethods []string
+}
+
+func newAuthenticator(j *jwt.JWT, newClaims func() jwt.Claims, publicMethods []string) *authenticator {
+	if newClaims == nil {
+		newClaims = func() jwt.Claims { return &jwt.RegisteredClaims{} }
+	}
+
+	return &authenticator{jwt: j, newClaims: newClaims, publicMethods: publicMethods}
+}
+
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if slices.Contains(a.publicMethods, method) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization header")
+	}
+
+	claims := a.newClaims()
+
+	if err := a.jwt.Verify(ctx, strings.TrimSpace(token), claims); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return jwt.NewContext(ctx, claims), nil
+}
+
+// authStream overrides the context of a server stream.
+type authStream struct {
+	pbgrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,121 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type authTestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authTestStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestJWTAuth(t *testing.T) {
+	j, err := jwt.New(jwt.HS256([]byte("secret")))
+	if err != nil {
+		t.Fatalf("jwt.New() error = %v", err)
+	}
+
+	token, err := j.Sign(j.NewRegisteredClaims("user-1"))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	const publicMethod = "/grpc.health.v1.Health/Check"
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantCode codes.Code
+		wantSub  string
+	}{
+		{
+			name:     "valid token",
+			method:   "/svc/Call",
+			md:       metadata.Pairs("authorization", "Bearer "+token),
+			wantCode: codes.OK,
+			wantSub:  "user-1",
+		},
+		{
+			name:     "missing token",
+			method:   "/svc/Call",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong scheme",
+			method:   "/svc/Call",
+			md:       metadata.Pairs("authorization", "Basic "+token),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			method:   "/svc/Call",
+			md:       metadata.Pairs("authorization", "Bearer invalid"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "public method",
+			method:   publicMethod,
+			wantCode: codes.OK,
+		},
+	}
+
+	unary := UnaryJWTAuth(j, nil, publicMethod)
+	stream := StreamJWTAuth(j, nil, publicMethod)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var unarySub string
+
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					if claims, ok := jwt.FromContext[*jwt.RegisteredClaims](ctx); ok {
+						unarySub = claims.Subject
+					}
+
+					return nil, nil
+				})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("unary code = %v, want %v", code, tt.wantCode)
+			}
+
+			if unarySub != tt.wantSub {
+				t.Errorf("unary subject = %q, want %q", unarySub, tt.wantSub)
+			}
+
+			var streamSub string
+
+			err = stream(nil, &authTestStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(_ interface{}, ss grpc.ServerStream) error {
+					if claims, ok := jwt.FromContext[*jwt.RegisteredClaims](ss.Context()); ok {
+						streamSub = claims.Subject
+					}
+
+					return nil
+				})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("stream code = %v, want %v", code, tt.wantCode)
+			}
+
+			if streamSub != tt.wantSub {
+				t.Errorf("stream subject = %q, want %q", streamSub, tt.wantSub)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/authz"
+	"github.com/rdashevsky/go-pkgs/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuthorize(t *testing.T) {
+	store := authz.NewMemoryStore(authz.Role{Name: "support", Permissions: []authz.Permission{{Action: "cancel", Resource: "orders"}}})
+	_ = store.Assign(context.Background(), "alice", "support")
+
+	unary := UnaryAuthorize(authz.New(store), map[string]authz.Permission{
+		"/orders.v1.OrderService/CancelOrder": {Action: "cancel", Resource: "orders"},
+		"/orders.v1.OrderService/DeleteOrder": {Action: "delete", Resource: "orders"},
+	}, nil)
+
+	tests := []struct {
+		name     string
+		subject  string
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "allowed", subject: "alice", method: "/orders.v1.OrderService/CancelOrder"},
+		{name: "denied", subject: "alice", method: "/orders.v1.OrderService/DeleteOrder", wantCode: codes.PermissionDenied},
+		{name: "anonymous", method: "/orders.v1.OrderService/CancelOrder", wantCode: codes.PermissionDenied},
+		{name: "unchecked method", method: "/orders.v1.OrderService/GetOrder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.subject != "" {
+				ctx = jwt.NewContext(ctx, &jwt.RegisteredClaims{Subject: tt.subject})
+			}
+
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+		})
+	}
+}
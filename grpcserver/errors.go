@@ -0,0 +1,26 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	pbgrpc "google.golang.org/grpc"
+)
+
+// UnaryErrorMapping returns a unary interceptor converting handler errors to gRPC
+// statuses with errorsx.ToGRPC: errorsx codes map to gRPC codes, their metadata is
+// sent as an ErrorInfo detail and messages of other errors are not exposed.
+func UnaryErrorMapping() pbgrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		return resp, errorsx.ToGRPC(err)
+	}
+}
+
+// StreamErrorMapping is the streaming counterpart of UnaryErrorMapping.
+func StreamErrorMapping() pbgrpc.StreamServerInterceptor {
+	return func(srv interface{}, ss pbgrpc.ServerStream, _ *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler) error {
+		return errorsx.ToGRPC(handler(srv, ss))
+	}
+}
@@ -0,0 +1,47 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "no error", wantCode: codes.OK},
+		{name: "coded error", err: errorsx.NotFound("order not found"), wantCode: codes.NotFound},
+		{name: "plain error", err: errors.New("boom"), wantCode: codes.Internal},
+	}
+
+	unary := UnaryErrorMapping()
+	stream := StreamErrorMapping()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Call"},
+				func(context.Context, interface{}) (interface{}, error) {
+					return nil, tt.err
+				})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("unary code = %v, want %v", code, tt.wantCode)
+			}
+
+			err = stream(nil, &authTestStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/svc/Stream"},
+				func(interface{}, grpc.ServerStream) error {
+					return tt.err
+				})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("stream code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
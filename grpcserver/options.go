@@ -1,7 +1,14 @@
 package grpcserver
 
 import (
+	"context"
 	"net"
+
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/authz"
+	"github.com/rdashevsky/go-pkgs/jwt"
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+	pbgrpc "google.golang.org/grpc"
 )
 
 // Option is a function that configures a Server.
@@ -19,3 +26,123 @@ func Port(port string) Option {
 		s.address = net.JoinHostPort("", port)
 	}
 }
+
+// ServerOptions adds options passed to grpc.NewServer, e.g. credentials or message size limits.
+//
+// Example:
+//
+//	server := grpcserver.New(grpcserver.ServerOptions(grpc.MaxRecvMsgSize(16 << 20)))
+func ServerOptions(opts ...pbgrpc.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// UnaryInterceptors adds unary interceptors, chained in the order they are added.
+func UnaryInterceptors(interceptors ...pbgrpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, interceptors...)
+	}
+}
+
+// StreamInterceptors adds stream interceptors, chained in the order they are added.
+func StreamInterceptors(interceptors ...pbgrpc.StreamServerInterceptor) Option {
+	return func(s *Server) {
+		s.streamInterceptors = append(s.streamInterceptors, interceptors...)
+	}
+}
+
+// JWTAuth authenticates unary and stream calls with bearer tokens verified by j.
+// See UnaryJWTAuth for the meaning of newClaims and publicMethods.
+//
+// Example:
+//
+//	server := grpcserver.New(
+//	    grpcserver.Port("9090"),
+//	    grpcserver.JWTAuth(j, nil, "/grpc.health.v1.Health/Check"),
+//	)
+func JWTAuth(j *jwt.JWT, newClaims func() jwt.Claims, publicMethods ...string) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryJWTAuth(j, newClaims, publicMethods...))
+		s.streamInterceptors = append(s.streamInterceptors, StreamJWTAuth(j, newClaims, publicMethods...))
+	}
+}
+
+// RateLimit limits unary and stream calls with l.
+// See UnaryRateLimit for the meaning of key.
+//
+// Example:
+//
+//	server := grpcserver.New(
+//	    grpcserver.Port("9090"),
+//	    grpcserver.RateLimit(ratelimit.NewTokenBucket(ratelimit.PerSecond(100)), nil),
+//	)
+func RateLimit(l ratelimit.Limiter, key func(ctx context.Context, fullMethod string) string) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryRateLimit(l, key))
+		s.streamInterceptors = append(s.streamInterceptors, StreamRateLimit(l, key))
+	}
+}
+
+// MapErrors converts errors returned by unary and stream handlers with errorsx.ToGRPC.
+// Add it first so errors of the interceptors added after it are mapped as well.
+//
+// Example:
+//
+//	server := grpcserver.New(grpcserver.MapErrors(), grpcserver.JWTAuth(j, nil))
+func MapErrors() Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryErrorMapping())
+		s.streamInterceptors = append(s.streamInterceptors, StreamErrorMapping())
+	}
+}
+
+// Audit records an audit event with a for every mutating unary and stream call.
+// See UnaryAudit for the meaning of actor. Add it after JWTAuth so the caller is known.
+//
+// Example:
+//
+//	server := grpcserver.New(
+//	    grpcserver.JWTAuth(j, nil),
+//	    grpcserver.Audit(a, nil),
+//	)
+func Audit(a *audit.Auditor, actor func(ctx context.Context) string) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryAudit(a, actor))
+		s.streamInterceptors = append(s.streamInterceptors, StreamAudit(a, actor))
+	}
+}
+
+// Tenant resolves the tenant of unary and stream calls.
+// See UnaryTenant for the meaning of resolve and publicMethods.
+//
+// Example:
+//
+//	server := grpcserver.New(
+//	    grpcserver.JWTAuth(j, newClaims),
+//	    grpcserver.Tenant(tenant.FromJWT(func(c *UserClaims) string { return c.TenantID })),
+//	)
+func Tenant(resolve func(ctx context.Context) string, publicMethods ...string) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryTenant(resolve, publicMethods...))
+		s.streamInterceptors = append(s.streamInterceptors, StreamTenant(resolve, publicMethods...))
+	}
+}
+
+// Authorize checks the permissions of unary and stream calls with a.
+// See UnaryAuthorize for the meaning of permissions and subject.
+//
+// Example:
+//
+//	server := grpcserver.New(
+//	    grpcserver.JWTAuth(j, nil),
+//	    grpcserver.Authorize(az, map[string]authz.Permission{
+//	        "/orders.v1.OrderService/CancelOrder": {Action: "cancel", Resource: "orders"},
+//	    }, nil),
+//	)
+func Authorize(a *authz.Authorizer, permissions map[string]authz.Permission, subject func(ctx context.Context) string) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, UnaryAuthorize(a, permissions, subject))
+		s.streamInterceptors = append(s.streamInterceptors, StreamAuthorize(a, permissions, subject))
+	}
+}
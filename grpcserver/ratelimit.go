@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRateLimit returns a unary interceptor limiting calls with l, keyed by key;
+// when key is nil, calls are limited per peer address. Rejected calls fail with
+// codes.ResourceExhausted and a "retry-after" trailer in seconds. Calls delayed by a
+// leaky bucket wait before proceeding. Limiter errors let calls through.
+func UnaryRateLimit(l ratelimit.Limiter, key func(ctx context.Context, fullMethod string) string) pbgrpc.UnaryServerInterceptor {
+	rl := newRateLimiter(l, key)
+
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		if err := rl.allow(ctx, info.FullMethod, func(md metadata.MD) { _ = pbgrpc.SetTrailer(ctx, md) }); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit is the streaming counterpart of UnaryRateLimit. Only stream
+// creation is limited, not the messages sent on the stream.
+func StreamRateLimit(l ratelimit.Limiter, key func(ctx context.Context, fullMethod string) string) pbgrpc.StreamServerInterceptor {
+	rl := newRateLimiter(l, key)
+
+	return func(srv interface{}, ss pbgrpc.ServerStream, info *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler) error {
+		if err := rl.allow(ss.Context(), info.FullMethod, ss.SetTrailer); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+type rateLimiter struct {
+	limiter ratelimit.Limiter
+	key     func(ctx context.Context, fullMethod string) string
+}
+
+func newRateLimiter(l ratelimit.Limiter, key func(ctx context.Context, fullMethod string) string) *rateLimiter {
+	if key == nil {
+		key = peerAddress
+	}
+
+	return &rateLimiter{limiter: l, key: key}
+}
+
+func (r *rateLimiter) allow(ctx context.Context, method string, setTrailer func(metadata.MD)) error {
+	res, err := r.limiter.Allow(ctx, r.key(ctx, method))
+	if err != nil {
+		// Fail open: an unavailable limiter must not take the service down.
+		return nil
+	}
+
+	if !res.Allowed {
+		setTrailer(metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))))
+
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	if res.Delay > 0 {
+		timer := time.NewTimer(res.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil
+}
+
+func peerAddress(ctx context.Context, _ string) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+
+	return p.Addr.String()
+}
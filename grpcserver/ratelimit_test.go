@@ -0,0 +1,77 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type rateLimitTestStream struct {
+	grpc.ServerStream
+	trailer metadata.MD
+}
+
+func (s *rateLimitTestStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *rateLimitTestStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.NewTokenBucket(ratelimit.PerHour(1))
+	unary := UnaryRateLimit(limiter, nil)
+	stream := StreamRateLimit(limiter, func(_ context.Context, method string) string { return method })
+
+	peerCtx := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}})
+	}
+
+	tests := []struct {
+		name     string
+		ip       string
+		wantCode codes.Code
+	}{
+		{name: "first call", ip: "10.0.0.1", wantCode: codes.OK},
+		{name: "over the limit", ip: "10.0.0.1", wantCode: codes.ResourceExhausted},
+		{name: "other peer", ip: "10.0.0.2", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unary(peerCtx(tt.ip), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Call"},
+				func(context.Context, interface{}) (interface{}, error) {
+					return nil, nil
+				})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("unary code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+
+	handler := func(interface{}, grpc.ServerStream) error { return nil }
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+
+	if err := stream(nil, &rateLimitTestStream{}, info, handler); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+
+	ss := &rateLimitTestStream{}
+
+	err := stream(nil, ss, info, handler)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("stream code = %v, want %v", code, codes.ResourceExhausted)
+	}
+
+	if got := ss.trailer.Get("retry-after"); len(got) != 1 || got[0] != "3600" {
+		t.Errorf("retry-after trailer = %v, want [3600]", got)
+	}
+}
@@ -19,6 +19,10 @@ type Server struct {
 	App     *pbgrpc.Server
 	notify  chan error
 	address string
+
+	serverOptions      []pbgrpc.ServerOption
+	unaryInterceptors  []pbgrpc.UnaryServerInterceptor
+	streamInterceptors []pbgrpc.StreamServerInterceptor
 }
 
 // New creates a new gRPC server instance with the specified options.
@@ -31,7 +35,6 @@ type Server struct {
 //	server.Start()
 func New(opts ...Option) *Server {
 	s := &Server{
-		App:     pbgrpc.NewServer(),
 		notify:  make(chan error, 1),
 		address: _defaultAddr,
 	}
@@ -41,6 +44,17 @@ func New(opts ...Option) *Server {
 		opt(s)
 	}
 
+	serverOptions := s.serverOptions
+	if len(s.unaryInterceptors) > 0 {
+		serverOptions = append(serverOptions, pbgrpc.ChainUnaryInterceptor(s.unaryInterceptors...))
+	}
+
+	if len(s.streamInterceptors) > 0 {
+		serverOptions = append(serverOptions, pbgrpc.ChainStreamInterceptor(s.streamInterceptors...))
+	}
+
+	s.App = pbgrpc.NewServer(serverOptions...)
+
 	return s
 }
 
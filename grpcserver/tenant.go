@@ -0,0 +1,84 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+
+	"github.com/rdashevsky/go-pkgs/tenant"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryTenant returns a unary interceptor resolving the tenant of calls with resolve
+// and storing it in the handler context, read with tenant.FromContext. When resolve
+// is nil, the tenant is read from the "x-tenant-id" metadata; use tenant.FromJWT to
+// read it from verified claims instead. Calls to publicMethods (full method names)
+// need no tenant. Other calls without a tenant fail with codes.InvalidArgument.
+func UnaryTenant(resolve func(ctx context.Context) string, publicMethods ...string) pbgrpc.UnaryServerInterceptor {
+	r := newTenantResolver(resolve, publicMethods)
+
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		ctx, err := r.resolve(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamTenant is the streaming counterpart of UnaryTenant.
+func StreamTenant(resolve func(ctx context.Context) string, publicMethods ...string) pbgrpc.StreamServerInterceptor {
+	r := newTenantResolver(resolve, publicMethods)
+
+	return func(srv interface{}, ss pbgrpc.ServerStream, info *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler) error {
+		ctx, err := r.resolve(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type tenantResolver struct {
+	tenant        func(ctx context.Context) string
+	publicMethods []string
+}
+
+func newTenantResolver(resolve func(ctx context.Context) string, publicMethods []string) *tenantResolver {
+	if resolve == nil {
+		resolve = tenantFromMetadata
+	}
+
+	return &tenantResolver{tenant: resolve, publicMethods: publicMethods}
+}
+
+func (r *tenantResolver) resolve(ctx context.Context, method string) (context.Context, error) {
+	if slices.Contains(r.publicMethods, method) {
+		return ctx, nil
+	}
+
+	id := r.tenant(ctx)
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing tenant")
+	}
+
+	return tenant.NewContext(ctx, id), nil
+}
+
+func tenantFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(tenant.MetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
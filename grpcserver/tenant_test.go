@@ -0,0 +1,50 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryTenant(t *testing.T) {
+	unary := UnaryTenant(nil, "/grpc.health.v1.Health/Check")
+
+	tests := []struct {
+		name       string
+		method     string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{name: "tenant metadata", method: "/svc/Call", md: metadata.Pairs(tenant.MetadataKey, "acme"), wantTenant: "acme"},
+		{name: "missing tenant", method: "/svc/Call", wantCode: codes.InvalidArgument},
+		{name: "public method", method: "/grpc.health.v1.Health/Check"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var got string
+
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					got, _ = tenant.FromContext(ctx)
+
+					return nil, nil
+				})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+
+			if got != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package httpclient
+
+import "sync"
+
+// budget limits retries to a fraction of requests with a token bucket:
+// every request deposits ratio tokens and every retry withdraws one.
+// The bucket starts full so short bursts of failures can still be retried.
+type budget struct {
+	mu        sync.Mutex
+	ratio     float64
+	tokens    float64
+	maxTokens float64
+}
+
+func newBudget(ratio float64, maxTokens int) *budget {
+	return &budget{
+		ratio:     ratio,
+		tokens:    float64(maxTokens),
+		maxTokens: float64(maxTokens),
+	}
+}
+
+func (b *budget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+func (b *budget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,30 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/httpclient"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+func ExampleClient_DoJSON() {
+	c := httpclient.New(
+		httpclient.BaseURL("https://api.example.com"),
+		httpclient.Timeout(5*time.Second),
+		httpclient.RetryBudget(0.1, 10),
+		httpclient.CircuitBreaker(breaker.New(breaker.Name("example-api"))),
+		httpclient.WithLogger(logger.New("info")),
+	)
+
+	var user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	if err := c.DoJSON(context.Background(), http.MethodGet, "/users/42", nil, &user); err != nil {
+		return
+	}
+}
@@ -0,0 +1,241 @@
+// Package httpclient provides a resilient outbound HTTP client, the counterpart of
+// httpserver: timeouts, retries with exponential backoff and a retry budget, circuit
+// breaking, connection pooling, request logging, metrics and trace propagation.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultTimeout             = 30 * time.Second
+	_defaultAttempts            = 3
+	_defaultInitialBackoff      = 100 * time.Millisecond
+	_defaultMaxBackoff          = 2 * time.Second
+	_defaultMaxIdleConns        = 100
+	_defaultMaxIdleConnsPerHost = 10
+	_defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// Metrics receives a measurement for every request, after retries.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called with the response status code, 0 when no response was received.
+	ObserveRequest(method, host string, status int, duration time.Duration, err error)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, string, int, time.Duration, error) {}
+
+// Propagator injects trace context from ctx into outgoing request headers.
+// An OpenTelemetry propagator is adapted with
+// p.Inject(ctx, propagation.HeaderCarrier(header)).
+type Propagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// StatusError is returned by the JSON helpers for non-2xx responses.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("httpclient - unexpected status %d: %s", e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+// Client sends HTTP requests through a resilient transport. It is safe for concurrent use.
+type Client struct {
+	client *http.Client
+
+	baseURL string
+	headers http.Header
+
+	timeout             time.Duration
+	attempts            int
+	initialBackoff      time.Duration
+	maxBackoff          time.Duration
+	budget              *budget
+	breaker             *breaker.Breaker
+	base                http.RoundTripper
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	metrics             Metrics
+	propagator          Propagator
+	logger              logger.LoggerI
+}
+
+// New creates a new Client.
+// Default configuration: 30 seconds timeout per request including retries,
+// 3 attempts with exponential backoff from 100ms up to 2 seconds, no retry budget,
+// no circuit breaker, 100 idle connections (10 per host) kept for 90 seconds.
+//
+// Only idempotent requests (GET, HEAD, OPTIONS, TRACE, PUT, DELETE, or any request
+// carrying an Idempotency-Key header) are retried, on transport errors and on
+// 429, 502, 503 and 504 responses.
+//
+// Example:
+//
+//	c := httpclient.New(
+//	    httpclient.BaseURL("https://api.example.com"),
+//	    httpclient.Timeout(5*time.Second),
+//	    httpclient.CircuitBreaker(breaker.New(breaker.Name("example-api"))),
+//	    httpclient.WithLogger(l),
+//	)
+func New(opts ...Option) *Client {
+	c := &Client{
+		headers:             make(http.Header),
+		timeout:             _defaultTimeout,
+		attempts:            _defaultAttempts,
+		initialBackoff:      _defaultInitialBackoff,
+		maxBackoff:          _defaultMaxBackoff,
+		maxIdleConns:        _defaultMaxIdleConns,
+		maxIdleConnsPerHost: _defaultMaxIdleConnsPerHost,
+		idleConnTimeout:     _defaultIdleConnTimeout,
+		metrics:             noopMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	base := c.base
+	if base == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConns = c.maxIdleConns
+		t.MaxIdleConnsPerHost = c.maxIdleConnsPerHost
+		t.IdleConnTimeout = c.idleConnTimeout
+		base = t
+	}
+
+	c.client = &http.Client{
+		Timeout:   c.timeout,
+		Transport: &transport{client: c, base: base},
+	}
+
+	return c
+}
+
+// HTTPClient returns a standard *http.Client using the resilient transport,
+// for SDKs accepting a custom client. Base URL and default headers are not applied.
+func (c *Client) HTTPClient() *http.Client {
+	return c.client
+}
+
+// Do sends the request. Relative request URLs are resolved against BaseURL
+// and default headers are added when not already set.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.baseURL != "" && req.URL.Host == "" {
+		u, err := neturl.Parse(c.baseURL + "/" + strings.TrimPrefix(req.URL.String(), "/"))
+		if err != nil {
+			return nil, fmt.Errorf("httpclient - Client - Do - url.Parse: %w", err)
+		}
+
+		req.URL = u
+		req.Host = u.Host
+	}
+
+	for key, values := range c.headers {
+		if req.Header.Get(key) == "" {
+			req.Header[key] = values
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient - Client - Do - c.client.Do: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Get sends a GET request.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient - Client - Get - http.NewRequest: %w", err)
+	}
+
+	return c.Do(req)
+}
+
+// Post sends a POST request with the given body.
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient - Client - Post - http.NewRequest: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	return c.Do(req)
+}
+
+// DoJSON sends a request with in encoded as JSON body (skipped when nil) and decodes
+// a 2xx JSON response into out (skipped when nil). Non-2xx responses return *StatusError.
+//
+// Example:
+//
+//	var user User
+//	err := c.DoJSON(ctx, http.MethodGet, "/users/42", nil, &user)
+func (c *Client) DoJSON(ctx context.Context, method, url string, in, out interface{}) error {
+	body := io.Reader(http.NoBody)
+
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("httpclient - Client - DoJSON - json.Marshal: %w", err)
+		}
+
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("httpclient - Client - DoJSON - http.NewRequest: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+		return &StatusError{StatusCode: resp.StatusCode, Body: b}
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("httpclient - Client - DoJSON - json.Decode: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/httpclient"
+)
+
+// flakyServer fails the first failures requests with status, then answers 200 with the request body.
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func fastRetries(attempts int) httpclient.Option {
+	return httpclient.Retries(attempts, time.Millisecond, 5*time.Millisecond)
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		failures   int32
+		status     int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{name: "GET retried", method: http.MethodGet, failures: 2, status: 503, wantStatus: 200, wantCalls: 3},
+		{name: "GET attempts exhausted", method: http.MethodGet, failures: 5, status: 502, wantStatus: 502, wantCalls: 3},
+		{name: "GET not retried on 500", method: http.MethodGet, failures: 1, status: 500, wantStatus: 500, wantCalls: 1},
+		{name: "POST not retried", method: http.MethodPost, failures: 1, status: 503, wantStatus: 503, wantCalls: 1},
+		{
+			name: "POST with idempotency key retried", method: http.MethodPost, header: "key-1",
+			failures: 1, status: 503, wantStatus: 200, wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.failures, tt.status)
+
+			c := httpclient.New(httpclient.BaseURL(srv.URL), fastRetries(3))
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, "/items", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+
+			if resp.StatusCode == http.StatusOK {
+				if body, _ := io.ReadAll(resp.Body); string(body) != "payload" {
+					t.Errorf("body = %q, want replayed payload", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusServiceUnavailable)
+
+	c := httpclient.New(fastRetries(3), httpclient.RetryBudget(0, 1))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// One retry is allowed by the budget: 2 requests + 1 retry.
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusInternalServerError)
+
+	b := breaker.New(breaker.MinRequests(2), breaker.OpenDuration(time.Minute))
+	c := httpclient.New(httpclient.CircuitBreaker(b), fastRetries(1))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if _, err := c.Get(context.Background(), srv.URL); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("Get() error = %v, want breaker.ErrOpen", err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	c := httpclient.New(fastRetries(2))
+
+	if _, err := c.Get(context.Background(), url); err == nil {
+		t.Error("Get() error = nil, want connection error")
+	}
+}
+
+type headerPropagator struct{}
+
+func (headerPropagator) Inject(_ context.Context, header http.Header) {
+	header.Set("Traceparent", "00-trace-span-01")
+}
+
+type metricsRecorder struct {
+	mu       sync.Mutex
+	statuses []int
+}
+
+func (m *metricsRecorder) ObserveRequest(_, _ string, status int, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.statuses = append(m.statuses, status)
+}
+
+func TestDoJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Traceparent") == "" || r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	m := &metricsRecorder{}
+
+	c := httpclient.New(
+		httpclient.BaseURL(srv.URL+"/"),
+		httpclient.Header("X-Api-Key", "secret"),
+		httpclient.WithPropagator(headerPropagator{}),
+		httpclient.WithMetrics(m),
+	)
+
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	var out item
+	if err := c.DoJSON(context.Background(), http.MethodPost, "/echo", item{Name: "book"}, &out); err != nil {
+		t.Fatalf("DoJSON() error = %v", err)
+	}
+
+	if out.Name != "book" {
+		t.Errorf("DoJSON() out = %+v", out)
+	}
+
+	var statusErr *httpclient.StatusError
+
+	err := c.DoJSON(context.Background(), http.MethodGet, "missing", nil, &out)
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("DoJSON() error = %v, want 404 StatusError", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.statuses) != 2 || m.statuses[0] != http.StatusOK || m.statuses[1] != http.StatusNotFound {
+		t.Errorf("observed statuses = %v", m.statuses)
+	}
+}
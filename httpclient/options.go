@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option is a function that configures a Client.
+type Option func(*Client)
+
+// BaseURL sets the URL relative request paths are resolved against.
+func BaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// Header sets a header added to every request sent with Do, unless the request sets it.
+func Header(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(key, value)
+	}
+}
+
+// Timeout sets the overall timeout of a request, including retries and reading the body.
+// Default is 30 seconds; zero disables it.
+func Timeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// Retries sets the number of attempts of idempotent requests and the exponential backoff
+// between them. Default is 3 attempts from 100ms up to 2 seconds; 1 disables retries.
+func Retries(attempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.attempts = attempts
+		c.initialBackoff = initialBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// RetryBudget limits retries to ratio of requests (e.g. 0.1 for 10%), allowing bursts
+// of up to maxTokens retries, so retries cannot amplify load on a failing service.
+// By default retries are not budgeted.
+func RetryBudget(ratio float64, maxTokens int) Option {
+	return func(c *Client) {
+		c.budget = newBudget(ratio, maxTokens)
+	}
+}
+
+// CircuitBreaker guards every attempt with b. Transport errors and 5xx responses count
+// as failures; rejected requests fail with breaker.ErrOpen or breaker.ErrTooManyRequests.
+func CircuitBreaker(b *breaker.Breaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+// MaxIdleConns sets the connection pool size across all hosts and per host.
+// Default is 100 and 10.
+func MaxIdleConns(total, perHost int) Option {
+	return func(c *Client) {
+		c.maxIdleConns = total
+		c.maxIdleConnsPerHost = perHost
+	}
+}
+
+// IdleConnTimeout sets how long idle pooled connections are kept.
+// Default is 90 seconds.
+func IdleConnTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.idleConnTimeout = timeout
+	}
+}
+
+// Transport replaces the underlying transport, e.g. for custom TLS settings.
+// Connection pool options are ignored when it is set.
+func Transport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.base = rt
+	}
+}
+
+// WithMetrics sets the metrics collector.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// WithPropagator injects trace context into every request.
+func WithPropagator(p Propagator) Option {
+	return func(c *Client) {
+		c.propagator = p
+	}
+}
+
+// WithLogger logs completed requests at debug level and failed ones at warn level.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
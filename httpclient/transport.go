@@ -0,0 +1,172 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// transport applies retries, circuit breaking, trace propagation, logging and metrics.
+type transport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+// errRetryableStatus reports a response whose status code may be retried.
+var errRetryableStatus = errors.New("httpclient - retryable status")
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := t.client
+	ctx := req.Context()
+	start := time.Now()
+
+	if c.propagator != nil {
+		req = req.Clone(ctx)
+		c.propagator.Inject(ctx, req.Header)
+	}
+
+	if c.budget != nil {
+		c.budget.deposit()
+	}
+
+	retryable := idempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	attempts := 1
+	if retryable {
+		attempts = c.attempts
+	}
+
+	var (
+		resp    *http.Response
+		attempt int
+	)
+
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		attempt++
+
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return retry.Permanent(err)
+			}
+
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		r, err := t.roundTrip(attemptReq)
+
+		// canRetry consumes a budget token, so it is only checked for failed attempts.
+		canRetry := func() bool {
+			return attempt < attempts && (c.budget == nil || c.budget.withdraw())
+		}
+
+		if err != nil {
+			if !canRetry() {
+				return retry.Permanent(err)
+			}
+
+			return err
+		}
+
+		if retryableStatus(r.StatusCode) && canRetry() {
+			drain(r)
+
+			return errRetryableStatus
+		}
+
+		resp = r
+
+		return nil
+	},
+		retry.Attempts(attempts),
+		retry.ExponentialBackoff(c.initialBackoff, c.maxBackoff),
+		retry.Jitter(0.2),
+		retry.RetryIf(func(err error) bool {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+		}),
+		retry.OnRetry(func(attempt int, err error, delay time.Duration) {
+			if c.logger != nil {
+				c.logger.Debug("httpclient - %s %s - attempt %d failed: %v, retrying in %s",
+					req.Method, req.URL.Redacted(), attempt, err, delay)
+			}
+		}),
+	)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+
+	c.metrics.ObserveRequest(req.Method, req.URL.Host, status, time.Since(start), err)
+
+	if c.logger != nil {
+		if err != nil {
+			c.logger.Warn("httpclient - %s %s - failed after %s: %v", req.Method, req.URL.Redacted(), time.Since(start), err)
+		} else {
+			c.logger.Debug("httpclient - %s %s - %d - %s", req.Method, req.URL.Redacted(), status, time.Since(start))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// roundTrip sends a single attempt through the circuit breaker.
+func (t *transport) roundTrip(req *http.Request) (*http.Response, error) {
+	b := t.client.breaker
+	if b == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	done, err := b.Allow()
+	if err != nil {
+		return nil, retry.Permanent(fmt.Errorf("httpclient - %s: %w", b.Name(), err))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	switch {
+	case err != nil:
+		done(err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		done(fmt.Errorf("httpclient - status %d", resp.StatusCode))
+	default:
+		done(nil)
+	}
+
+	return resp, err
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// drain discards a response body so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
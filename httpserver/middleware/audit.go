@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+// Audit returns a Fiber middleware recording an audit event with a for every unsafe
+// request (POST, PUT, PATCH, DELETE, ...). The event action is the method and route
+// path, e.g. "DELETE /orders/:id", and its outcome is a failure when the handler
+// returns an error or a 4xx/5xx status. The actor is resolved by actor once the
+// handler ran; when nil, it is the subject of the claims stored by the JWT middleware.
+// Handlers enrich the event with audit.FromContext, or skip it with audit.Skip.
+//
+// Example:
+//
+//	app.Use(middleware.JWT(j, nil), middleware.Audit(a, nil))
+//
+//	app.Delete("/orders/:id", func(c *fiber.Ctx) error {
+//	    e := audit.FromContext(c.UserContext())
+//	    e.Resource, e.ResourceID = "order", c.Params("id")
+//	    ...
+//	})
+func Audit(a *audit.Auditor, actor func(c *fiber.Ctx) string) func(c *fiber.Ctx) error {
+	if actor == nil {
+		actor = func(c *fiber.Ctx) string { return jwtSubject(c.UserContext()) }
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if isSafeMethod(ctx.Method()) {
+			return ctx.Next()
+		}
+
+		e := &audit.Event{
+			IP:        ctx.IP(),
+			UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		}
+
+		ctx.SetUserContext(audit.NewContext(ctx.UserContext(), e))
+
+		err := ctx.Next()
+
+		if e.Skipped() {
+			return err
+		}
+
+		if e.Action == "" {
+			e.Action = ctx.Method() + " " + ctx.Route().Path
+		}
+
+		status := ctx.Response().StatusCode()
+
+		var fiberErr *fiber.Error
+
+		switch {
+		case errors.As(err, &fiberErr):
+			status = fiberErr.Code
+		case err != nil:
+			status = fiber.StatusInternalServerError
+		}
+
+		e.Outcome = audit.Success
+		if status >= fiber.StatusBadRequest {
+			e.Outcome = audit.Failure
+		}
+
+		e.SetMetadata("status", strconv.Itoa(status))
+
+		if e.Actor == "" {
+			e.Actor = actor(ctx)
+		}
+
+		detachStrings(e)
+		a.Record(ctx.UserContext(), e)
+
+		return err
+	}
+}
+
+// detachStrings copies the string fields of e, which may point into Fiber buffers
+// reused once the request completes (e.g. values of c.Params or c.Get).
+func detachStrings(e *audit.Event) {
+	for _, s := range []*string{&e.Actor, &e.Action, &e.Resource, &e.ResourceID, &e.IP, &e.UserAgent} {
+		*s = strings.Clone(*s)
+	}
+
+	metadata := make(map[string]string, len(e.Metadata))
+	for k, v := range e.Metadata {
+		metadata[strings.Clone(k)] = strings.Clone(v)
+	}
+
+	e.Metadata = metadata
+}
+
+// jwtSubject returns the subject of the JWT claims stored in ctx, if any.
+func jwtSubject(ctx context.Context) string {
+	claims, ok := jwt.FromContext[jwt.Claims](ctx)
+	if !ok {
+		return ""
+	}
+
+	sub, _ := claims.GetSubject()
+
+	return sub
+}
@@ -0,0 +1,98 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/audit"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+func TestAudit(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []*audit.Event
+	)
+
+	a := audit.New(audit.SinkFunc(func(_ context.Context, batch []*audit.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, batch...)
+
+		return nil
+	}))
+	a.Start()
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		claims := &jwt.RegisteredClaims{Subject: "user-1"}
+		c.SetUserContext(jwt.NewContext(c.UserContext(), claims))
+
+		return c.Next()
+	})
+	app.Use(middleware.Audit(a, nil))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		return c.SendString("order")
+	})
+	app.Delete("/orders/:id", func(c *fiber.Ctx) error {
+		e := audit.FromContext(c.UserContext())
+		e.Resource, e.ResourceID = "order", c.Params("id")
+
+		if c.Params("id") == "missing" {
+			return fiber.ErrNotFound
+		}
+
+		if c.Params("id") == "noop" {
+			audit.Skip(c.UserContext())
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, req := range []struct{ method, path string }{
+		{method: "GET", path: "/orders/1"},
+		{method: "DELETE", path: "/orders/1"},
+		{method: "DELETE", path: "/orders/missing"},
+		{method: "DELETE", path: "/orders/noop"},
+	} {
+		resp, err := app.Test(httptest.NewRequest(req.method, req.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+
+		resp.Body.Close()
+	}
+
+	_ = a.Shutdown()
+
+	want := []struct {
+		resourceID string
+		outcome    audit.Outcome
+		status     string
+	}{
+		{resourceID: "1", outcome: audit.Success, status: "204"},
+		{resourceID: "missing", outcome: audit.Failure, status: "404"},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("recorded events = %d, want %d", len(events), len(want))
+	}
+
+	for i, w := range want {
+		e := events[i]
+
+		if e.Action != "DELETE /orders/:id" || e.Actor != "user-1" || e.Resource != "order" {
+			t.Errorf("event %d = %s by %q on %q, want DELETE /orders/:id by user-1 on order", i, e.Action, e.Actor, e.Resource)
+		}
+
+		if e.ResourceID != w.resourceID || e.Outcome != w.outcome || e.Metadata["status"] != w.status {
+			t.Errorf("event %d = %s %s status %s, want %s %s status %s",
+				i, e.ResourceID, e.Outcome, e.Metadata["status"], w.resourceID, w.outcome, w.status)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/authz"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+)
+
+// Authorize returns a Fiber middleware allowing requests only when the subject may
+// perform action on resource according to a. The subject is resolved by subject;
+// when nil, it is the subject of the claims stored by the JWT middleware. Denied
+// requests get a 403 response and requests whose permissions cannot be checked a 500.
+//
+// Example:
+//
+//	app.Use(middleware.JWT(j, nil))
+//	app.Delete("/orders/:id", middleware.Authorize(az, "delete", "orders", nil), deleteOrder)
+func Authorize(a *authz.Authorizer, action, resource string, subject func(c *fiber.Ctx) string) func(c *fiber.Ctx) error {
+	if subject == nil {
+		subject = func(c *fiber.Ctx) string { return jwtSubject(c.UserContext()) }
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		sub := subject(ctx)
+		if sub == "" {
+			return response.Error(ctx, fiber.StatusForbidden)
+		}
+
+		ok, err := a.Can(ctx.UserContext(), sub, action, resource)
+		if err != nil {
+			return response.Error(ctx, fiber.StatusInternalServerError)
+		}
+
+		if !ok {
+			return response.Error(ctx, fiber.StatusForbidden)
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/authz"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+)
+
+func TestAuthorize(t *testing.T) {
+	store := authz.NewMemoryStore(authz.Role{Name: "admin", Permissions: []authz.Permission{{Action: "delete", Resource: "orders"}}})
+	_ = store.Assign(context.Background(), "alice", "admin")
+
+	app := fiber.New()
+	app.Delete("/orders/:id", middleware.Authorize(authz.New(store), "delete", "orders", func(c *fiber.Ctx) string {
+		return c.Get("X-User")
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		user   string
+		status int
+	}{
+		{name: "allowed", user: "alice", status: fiber.StatusNoContent},
+		{name: "denied", user: "bob", status: fiber.StatusForbidden},
+		{name: "anonymous", status: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/orders/1", nil)
+			req.Header.Set("X-User", tt.user)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
+
+// Concurrency returns a Fiber middleware bounding the number of requests handled
+// concurrently to the capacity of sem, each request holding a weight of 1. Requests
+// wait up to maxWait for a slot, or until the request context is done when maxWait
+// is zero, and are rejected with 503 otherwise. The semaphore may be shared between
+// routes to bound them together, e.g. all handlers hitting the database.
+//
+// Example:
+//
+//	db := semaphore.New(20)
+//	app.Get("/reports", middleware.Concurrency(db, 2*time.Second), reportsHandler)
+func Concurrency(sem *semaphore.Semaphore, maxWait time.Duration) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		waitCtx := ctx.UserContext()
+
+		if maxWait > 0 {
+			var cancel context.CancelFunc
+
+			waitCtx, cancel = context.WithTimeout(waitCtx, maxWait)
+			defer cancel()
+		}
+
+		if err := sem.Acquire(waitCtx, 1); err != nil {
+			return response.Error(ctx, fiber.StatusServiceUnavailable)
+		}
+		defer sem.Release(1)
+
+		return ctx.Next()
+	}
+}
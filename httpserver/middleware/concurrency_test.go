@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
+
+func TestConcurrency(t *testing.T) {
+	sem := semaphore.New(1)
+
+	app := fiber.New()
+	app.Use(middleware.Concurrency(sem, 10*time.Millisecond))
+	app.Get("/", func(c *fiber.Ctx) error {
+		if sem.InUse() != 1 {
+			t.Errorf("InUse() in handler = %d, want 1", sem.InUse())
+		}
+
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		held     bool
+		wantCode int
+	}{
+		{name: "slot available", wantCode: fiber.StatusOK},
+		{name: "slot held elsewhere", held: true, wantCode: fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.held {
+				sem.TryAcquire(1)
+				defer sem.Release(1)
+			}
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+
+	if sem.InUse() != 0 {
+		t.Errorf("InUse() after requests = %d, want 0", sem.InUse())
+	}
+}
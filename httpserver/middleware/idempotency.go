@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+	"github.com/rdashevsky/go-pkgs/idempotency"
+)
+
+// HeaderIdempotencyKey is the request header carrying the idempotency key.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// HeaderIdempotentReplayed is set to "true" on responses replayed from the idempotency store.
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+// errServerError releases the idempotency key of requests answered with a 5xx status,
+// so they can be retried.
+var errServerError = errors.New("middleware - idempotency - server error")
+
+// Idempotency returns a Fiber middleware executing unsafe requests (POST, PATCH, ...)
+// carrying an Idempotency-Key header at most once. Retries with the same key and
+// request replay the stored status, content type and body with an
+// "Idempotent-Replayed: true" header. A key reused for a different method, path or
+// body gets a 422 response and a key whose request is still running gets a 409.
+// Responses with a 5xx status are not stored.
+//
+// Example:
+//
+//	idem := idempotency.New(idempotency.NewRedisStore(r))
+//	app.Post("/payments", middleware.Idempotency(idem), createPayment)
+func Idempotency(i *idempotency.Idempotency) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		key := ctx.Get(HeaderIdempotencyKey)
+		if key == "" || isSafeMethod(ctx.Method()) {
+			return ctx.Next()
+		}
+
+		fingerprint := idempotency.Fingerprint([]byte(ctx.Method()), []byte(ctx.OriginalURL()), ctx.Body())
+
+		var handlerErr error
+
+		resp, replayed, err := i.Do(ctx.UserContext(), key, fingerprint, func(context.Context) (idempotency.Response, error) {
+			if handlerErr = ctx.Next(); handlerErr != nil {
+				return idempotency.Response{}, handlerErr
+			}
+
+			status := ctx.Response().StatusCode()
+			if status >= fiber.StatusInternalServerError {
+				return idempotency.Response{}, errServerError
+			}
+
+			return idempotency.Response{
+				StatusCode: status,
+				Header: map[string]string{
+					fiber.HeaderContentType: string(ctx.Response().Header.ContentType()),
+				},
+				Body: append([]byte(nil), ctx.Response().Body()...),
+			}, nil
+		})
+
+		switch {
+		case handlerErr != nil:
+			return handlerErr
+		case errors.Is(err, errServerError):
+			return nil
+		case errors.Is(err, idempotency.ErrInProgress):
+			return response.Error(ctx, fiber.StatusConflict)
+		case errors.Is(err, idempotency.ErrMismatch):
+			return response.Error(ctx, fiber.StatusUnprocessableEntity)
+		case err != nil && resp.StatusCode == 0:
+			// The store failed before the handler ran.
+			return err
+		case replayed:
+			for k, v := range resp.Header {
+				ctx.Set(k, v)
+			}
+
+			ctx.Set(HeaderIdempotentReplayed, strconv.FormatBool(true))
+
+			return ctx.Status(resp.StatusCode).Send(resp.Body)
+		default:
+			// The handler response has been written; a failure to store it only affects retries.
+			return nil
+		}
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
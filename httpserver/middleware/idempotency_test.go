@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/idempotency"
+)
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+
+	app := fiber.New()
+	app.Use(middleware.Idempotency(idempotency.New(idempotency.NewMemoryStore())))
+	app.Post("/payments", func(c *fiber.Ctx) error {
+		calls++
+
+		return c.Status(fiber.StatusCreated).SendString("payment")
+	})
+	app.Post("/fail", func(c *fiber.Ctx) error {
+		calls++
+
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		key      string
+		body     string
+		status   int
+		replayed bool
+		calls    int
+	}{
+		{name: "first request", path: "/payments", key: "k-1", body: "a", status: fiber.StatusCreated, calls: 1},
+		{name: "retry is replayed", path: "/payments", key: "k-1", body: "a", status: fiber.StatusCreated, replayed: true, calls: 1},
+		{name: "key reused with another body", path: "/payments", key: "k-1", body: "b", status: fiber.StatusUnprocessableEntity, calls: 1},
+		{name: "no key", path: "/payments", body: "a", status: fiber.StatusCreated, calls: 2},
+		{name: "server error", path: "/fail", key: "k-2", status: fiber.StatusServiceUnavailable, calls: 3},
+		{name: "server error is not stored", path: "/fail", key: "k-2", status: fiber.StatusServiceUnavailable, calls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(middleware.HeaderIdempotencyKey, tt.key)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			if replayed := resp.Header.Get(middleware.HeaderIdempotentReplayed) == "true"; replayed != tt.replayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.replayed)
+			}
+
+			if tt.replayed {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != "payment" {
+					t.Errorf("body = %q, want %q", body, "payment")
+				}
+			}
+
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+// JWT returns a Fiber middleware authenticating requests with a bearer token verified by j.
+// newClaims returns a pointer to the claims type tokens are decoded into; when nil,
+// *jwt.RegisteredClaims is used. Verified claims are stored in the request user context
+// and read with jwt.FromContext. Requests without a valid token get a 401 response.
+//
+// Example:
+//
+//	app.Use(middleware.JWT(j, func() jwt.Claims { return &UserClaims{} }))
+//
+//	app.Get("/me", func(c *fiber.Ctx) error {
+//	    claims, _ := jwt.FromContext[*UserClaims](c.UserContext())
+//	    return c.JSON(claims)
+//	})
+func JWT(j *jwt.JWT, newClaims func() jwt.Claims) func(c *fiber.Ctx) error {
+	if newClaims == nil {
+		newClaims = func() jwt.Claims { return &jwt.RegisteredClaims{} }
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		token, ok := bearerToken(ctx.Get(fiber.HeaderAuthorization))
+		if !ok {
+			return response.Error(ctx, fiber.StatusUnauthorized)
+		}
+
+		claims := newClaims()
+
+		if err := j.Verify(ctx.UserContext(), token, claims); err != nil {
+			return response.Error(ctx, fiber.StatusUnauthorized)
+		}
+
+		ctx.SetUserContext(jwt.NewContext(ctx.UserContext(), claims))
+
+		return ctx.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+func TestJWT(t *testing.T) {
+	j, err := jwt.New(jwt.HS256([]byte("secret")))
+	if err != nil {
+		t.Fatalf("jwt.New() error = %v", err)
+	}
+
+	token, err := j.Sign(j.NewRegisteredClaims("user-1"))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(middleware.JWT(j, nil))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		claims, ok := jwt.FromContext[*jwt.RegisteredClaims](c.UserContext())
+		if !ok {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		return c.SendString(claims.Subject)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{name: "valid token", authorization: "Bearer " + token, expected: fiber.StatusOK},
+		{name: "lowercase scheme", authorization: "bearer " + token, expected: fiber.StatusOK},
+		{name: "missing header", expected: fiber.StatusUnauthorized},
+		{name: "wrong scheme", authorization: "Basic " + token, expected: fiber.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer invalid", expected: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+)
+
+// Rate limit response headers.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+)
+
+// RateLimit returns a Fiber middleware limiting requests with l, keyed by key;
+// when key is nil, requests are limited per client IP. Responses carry the
+// X-RateLimit-Limit and X-RateLimit-Remaining headers and rejected requests get a
+// 429 response with a Retry-After header. Requests delayed by a leaky bucket wait
+// before proceeding. Limiter errors let requests through.
+//
+// Example:
+//
+//	limiter := ratelimit.NewTokenBucket(ratelimit.PerMinute(100), ratelimit.Redis(r))
+//	app.Use(middleware.RateLimit(limiter, func(c *fiber.Ctx) string {
+//	    return c.Get("X-API-Key")
+//	}))
+func RateLimit(l ratelimit.Limiter, key func(c *fiber.Ctx) string) func(c *fiber.Ctx) error {
+	if key == nil {
+		key = func(c *fiber.Ctx) string { return c.IP() }
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		res, err := l.Allow(ctx.UserContext(), key(ctx))
+		if err != nil {
+			// Fail open: an unavailable limiter must not take the service down.
+			return ctx.Next()
+		}
+
+		ctx.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+		ctx.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+
+			return response.Error(ctx, fiber.StatusTooManyRequests)
+		}
+
+		if res.Delay > 0 {
+			timer := time.NewTimer(res.Delay)
+
+			select {
+			case <-ctx.UserContext().Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+
+		return ctx.Next()
+	}
+}
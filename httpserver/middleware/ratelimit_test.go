@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RateLimit(ratelimit.NewTokenBucket(ratelimit.PerHour(2)), func(c *fiber.Ctx) string {
+		return c.Get("X-API-Key")
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{name: "first request", key: "a", status: fiber.StatusOK, remaining: "1"},
+		{name: "second request", key: "a", status: fiber.StatusOK, remaining: "0"},
+		{name: "over the limit", key: "a", status: fiber.StatusTooManyRequests, remaining: "0", retryAfter: "1800"},
+		{name: "other key", key: "b", status: fiber.StatusOK, remaining: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-API-Key", tt.key)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			if got := resp.Header.Get(middleware.HeaderRateLimitLimit); got != "2" {
+				t.Errorf("%s = %q, want %q", middleware.HeaderRateLimitLimit, got, "2")
+			}
+
+			if got := resp.Header.Get(middleware.HeaderRateLimitRemaining); got != tt.remaining {
+				t.Errorf("%s = %q, want %q", middleware.HeaderRateLimitRemaining, got, tt.remaining)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderRetryAfter); got != tt.retryAfter {
+				t.Errorf("%s = %q, want %q", fiber.HeaderRetryAfter, got, tt.retryAfter)
+			}
+		})
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("unavailable")
+}
+
+func TestRateLimit_FailOpen(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RateLimit(failingLimiter{}, nil))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+	"github.com/rdashevsky/go-pkgs/tenant"
+)
+
+// Tenant returns a Fiber middleware resolving the tenant of requests with resolve
+// and storing it in the request user context, read with tenant.FromContext. When
+// resolve is nil, the tenant is read from the X-Tenant-ID header; use tenant.FromJWT
+// to read it from verified claims instead. Requests without a tenant get a 400
+// response.
+//
+// Example:
+//
+//	app.Use(middleware.Tenant(nil))
+//
+//	app.Get("/orders", func(c *fiber.Ctx) error {
+//	    id, _ := tenant.FromContext(c.UserContext())
+//	    ...
+//	})
+func Tenant(resolve func(c *fiber.Ctx) string) func(c *fiber.Ctx) error {
+	if resolve == nil {
+		resolve = func(c *fiber.Ctx) string { return c.Get(tenant.Header) }
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		id := resolve(ctx)
+		if id == "" {
+			message := "missing tenant"
+
+			return response.Error(ctx, fiber.StatusBadRequest, response.ErrorMessage(&message))
+		}
+
+		// Header values point into buffers reused once the request completes.
+		ctx.SetUserContext(tenant.NewContext(ctx.UserContext(), strings.Clone(id)))
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver/middleware"
+	"github.com/rdashevsky/go-pkgs/tenant"
+)
+
+func TestTenant(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.Tenant(nil))
+	app.Get("/", func(c *fiber.Ctx) error {
+		id, _ := tenant.FromContext(c.UserContext())
+
+		return c.SendString(id)
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{name: "tenant header", header: "acme", wantCode: fiber.StatusOK, wantBody: "acme"},
+		{name: "missing tenant", wantCode: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+
+			if body, _ := io.ReadAll(resp.Body); tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package httpserver
 import (
 	"net"
 	"time"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 // Option defines a function type for configuring Server instances.
@@ -44,3 +46,25 @@ func ShutdownTimeout(timeout time.Duration) Option {
 		s.shutdownTimeout = timeout
 	}
 }
+
+// ErrorHandler sets the handler of errors returned by request handlers,
+// e.g. response.ErrorHandler mapping errorsx codes to status codes.
+// Default is the Fiber default error handler.
+func ErrorHandler(handler fiber.ErrorHandler) Option {
+	return func(s *Server) {
+		s.errorHandler = handler
+	}
+}
+
+// Middleware registers handlers on the App with App.Use right after it is created,
+// before any route, e.g. telemetry or recovery middleware. Handlers of several calls
+// run in order.
+//
+// Example:
+//
+//	server := httpserver.New(httpserver.Middleware(middleware.Recovery(l), middleware.Logger(l)))
+func Middleware(handlers ...fiber.Handler) Option {
+	return func(s *Server) {
+		s.middleware = append(s.middleware, handlers...)
+	}
+}
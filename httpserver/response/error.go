@@ -25,6 +25,20 @@ func ErrorTitle(title string) Option {
 	}
 }
 
+// ErrorCode sets the machine readable error code.
+func ErrorCode(code string) Option {
+	return func(e *ErrorResponse) {
+		e.Code = code
+	}
+}
+
+// ErrorDetails sets additional error details.
+func ErrorDetails(details map[string]string) Option {
+	return func(e *ErrorResponse) {
+		e.Details = details
+	}
+}
+
 // Error sends a standardized JSON error response with the given HTTP status code.
 // It automatically maps common status codes to appropriate error titles and allows
 // customization through optional parameters.
@@ -47,8 +61,10 @@ func Error(ctx *fiber.Ctx, code int, opts ...Option) error {
 
 // ErrorResponse represents a standardized JSON error response structure.
 type ErrorResponse struct {
-	Error   string  `json:"error" example:"Not found"`
-	Message *string `json:"message,omitempty" example:"Some error details"`
+	Error   string            `json:"error" example:"Not found"`
+	Message *string           `json:"message,omitempty" example:"Some error details"`
+	Code    string            `json:"code,omitempty" example:"not_found"`
+	Details map[string]string `json:"details,omitempty"`
 }
 
 var statusCodeErrorTitle = map[int]string{
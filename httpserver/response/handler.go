@@ -0,0 +1,39 @@
+package response
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/errorsx"
+)
+
+// ErrorHandler is a Fiber error handler sending errors returned by handlers as
+// standardized JSON error responses. The status of *errorsx.Error values is mapped
+// from their code, and their code, message and metadata are included for 4xx statuses.
+// *fiber.Error values keep their status and message. Other errors get a 500 response
+// without details.
+//
+// Example:
+//
+//	server := httpserver.New(httpserver.ErrorHandler(response.ErrorHandler))
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return Error(ctx, fiberErr.Code, ErrorMessage(&fiberErr.Message))
+	}
+
+	code := errorsx.CodeOf(err)
+	status := code.HTTPStatus()
+
+	if status >= fiber.StatusInternalServerError {
+		return Error(ctx, status, ErrorCode(string(code)))
+	}
+
+	opts := []Option{ErrorCode(string(code)), ErrorDetails(errorsx.MetadataOf(err))}
+
+	if message := errorsx.MessageOf(err); message != "" {
+		opts = append(opts, ErrorMessage(&message))
+	}
+
+	return Error(ctx, status, opts...)
+}
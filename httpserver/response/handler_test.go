@@ -0,0 +1,76 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/errorsx"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   response.ErrorResponse
+	}{
+		{
+			name:   "coded error",
+			err:    errorsx.InvalidArgument("invalid email").WithMeta("field", "email"),
+			status: fiber.StatusBadRequest,
+			want:   response.ErrorResponse{Error: "Bad Request", Code: "invalid_argument", Details: map[string]string{"field": "email"}},
+		},
+		{
+			name:   "internal error hides details",
+			err:    errorsx.Wrap(errors.New("pq: syntax error"), errorsx.CodeInternal, "query failed"),
+			status: fiber.StatusInternalServerError,
+			want:   response.ErrorResponse{Error: "Internal Server Error", Code: "internal"},
+		},
+		{
+			name:   "plain error",
+			err:    errors.New("boom"),
+			status: fiber.StatusInternalServerError,
+			want:   response.ErrorResponse{Error: "Internal Server Error", Code: "internal"},
+		},
+		{
+			name:   "fiber error",
+			err:    fiber.ErrMethodNotAllowed,
+			status: fiber.StatusMethodNotAllowed,
+			want:   response.ErrorResponse{Error: "Method Not Allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: response.ErrorHandler})
+			app.Get("/", func(*fiber.Ctx) error { return tt.err })
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			var got response.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if got.Error != tt.want.Error || got.Code != tt.want.Code || len(got.Details) != len(tt.want.Details) {
+				t.Errorf("body = %+v, want %+v", got, tt.want)
+			}
+
+			if tt.name == "coded error" && (got.Message == nil || *got.Message != "invalid email") {
+				t.Errorf("message = %v, want %q", got.Message, "invalid email")
+			}
+		})
+	}
+}
@@ -28,6 +28,8 @@ type Server struct {
 	readTimeout     time.Duration
 	writeTimeout    time.Duration
 	shutdownTimeout time.Duration
+	errorHandler    fiber.ErrorHandler
+	middleware      []fiber.Handler
 }
 
 // New creates a new HTTP server with the given options.
@@ -60,8 +62,13 @@ func New(opts ...Option) *Server {
 		WriteTimeout: s.writeTimeout,
 		JSONDecoder:  json.Unmarshal,
 		JSONEncoder:  json.Marshal,
+		ErrorHandler: s.errorHandler,
 	})
 
+	for _, h := range s.middleware {
+		app.Use(h)
+	}
+
 	s.App = app
 
 	return s
@@ -2,6 +2,7 @@ package httpserver_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -163,6 +164,25 @@ func TestOptions(t *testing.T) {
 		}
 		// We can't directly test the shutdown timeout without actually shutting down
 	})
+
+	t.Run("Middleware option", func(t *testing.T) {
+		server := httpserver.New(httpserver.Middleware(func(c *fiber.Ctx) error {
+			c.Set("X-Middleware", "called")
+
+			return c.Next()
+		}))
+		server.App.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+		resp, err := server.App.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.Header.Get("X-Middleware") != "called" {
+			t.Error("expected middleware registered with the Middleware option to run")
+		}
+	})
 }
 
 func TestServer_MultipleOptions(t *testing.T) {
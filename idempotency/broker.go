@@ -0,0 +1,101 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	amqp "github.com/rabbitmq/amqp091-go"
+	kafkaserver "github.com/rdashevsky/go-pkgs/kafka/server"
+	rmqserver "github.com/rdashevsky/go-pkgs/rabbitmq/server"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// HeaderKey is the message header carrying the idempotency key.
+const HeaderKey = "idempotency-key"
+
+// KafkaHandler wraps a kafka server handler so that records carrying an
+// "idempotency-key" header are handled once: redelivered records reply with the
+// stored response. Records without the header are passed through.
+//
+// Example:
+//
+//	router := map[string]kafkaserver.CallHandler{
+//	    "charge": idempotency.KafkaHandler(idem, charge),
+//	}
+func KafkaHandler(i *Idempotency, h kafkaserver.CallHandler) kafkaserver.CallHandler {
+	return func(r *kgo.Record) (interface{}, error) {
+		var key string
+
+		for _, header := range r.Headers {
+			if header.Key == HeaderKey {
+				key = string(header.Value)
+
+				break
+			}
+		}
+
+		if key == "" {
+			return h(r)
+		}
+
+		ctx := r.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return handle(ctx, i, key, Fingerprint([]byte(r.Topic), r.Key, r.Value), func() (interface{}, error) {
+			return h(r)
+		})
+	}
+}
+
+// RabbitMQHandler wraps a rabbitmq server handler so that deliveries are handled once
+// per key: redelivered messages reply with the stored response. The key is taken from
+// the "idempotency-key" header, falling back to the message ID; deliveries without
+// either are passed through.
+//
+// Example:
+//
+//	router := map[string]rmqserver.CallHandler{
+//	    "charge": idempotency.RabbitMQHandler(idem, charge),
+//	}
+func RabbitMQHandler(i *Idempotency, h rmqserver.CallHandler) rmqserver.CallHandler {
+	return func(d *amqp.Delivery) (interface{}, error) {
+		key, _ := d.Headers[HeaderKey].(string)
+		if key == "" {
+			key = d.MessageId
+		}
+
+		if key == "" {
+			return h(d)
+		}
+
+		return handle(context.Background(), i, key, Fingerprint([]byte(d.Type), d.Body), func() (interface{}, error) {
+			return h(d)
+		})
+	}
+}
+
+// handle runs fn through Do, storing its JSON encoded result.
+// Replays return the stored result as json.RawMessage, which the servers send unchanged.
+func handle(ctx context.Context, i *Idempotency, key, fingerprint string, fn func() (interface{}, error)) (interface{}, error) {
+	resp, _, err := i.Do(ctx, key, fingerprint, func(context.Context) (Response, error) {
+		result, err := fn()
+		if err != nil {
+			return Response{}, err
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			return Response{}, fmt.Errorf("idempotency - handle - json.Marshal: %w", err)
+		}
+
+		return Response{Body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(resp.Body), nil
+}
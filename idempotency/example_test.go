@@ -0,0 +1,29 @@
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/idempotency"
+)
+
+func ExampleIdempotency_Do() {
+	idem := idempotency.New(idempotency.NewMemoryStore())
+	body := []byte(`{"amount":10}`)
+
+	for range 2 {
+		resp, replayed, err := idem.Do(context.Background(), "payment-1", idempotency.Fingerprint(body),
+			func(context.Context) (idempotency.Response, error) {
+				return idempotency.Response{StatusCode: 201, Body: []byte("charged")}, nil
+			})
+		if err != nil {
+			return
+		}
+
+		fmt.Println(resp.StatusCode, string(resp.Body), replayed)
+	}
+
+	// Output:
+	// 201 charged false
+	// 201 charged true
+}
@@ -0,0 +1,156 @@
+// Package idempotency records request fingerprints and results under idempotency keys,
+// so retried requests replay the original result instead of repeating side effects.
+// Stores are backed by Redis, Postgres or memory; the package powers the httpserver
+// Idempotency middleware and the kafka and rabbitmq handler wrappers.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	_defaultTTL     = 24 * time.Hour
+	_defaultLockTTL = time.Minute
+)
+
+var (
+	// ErrInProgress is returned when a request with the same key is still being processed.
+	ErrInProgress = errors.New("idempotency - request in progress")
+	// ErrMismatch is returned when a key is reused with a different request fingerprint.
+	ErrMismatch = errors.New("idempotency - key reused with a different request")
+	// ErrLockLost is returned when the reservation of a key expired and another request
+	// took it over before the response was stored.
+	ErrLockLost = errors.New("idempotency - key reservation lost")
+)
+
+// Response is a stored result.
+type Response struct {
+	StatusCode int               `json:"status_code,omitempty"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       []byte            `json:"body,omitempty"`
+}
+
+// Record is the state of an idempotency key.
+type Record struct {
+	Fingerprint string   `json:"fingerprint"`
+	Completed   bool     `json:"completed"`
+	Response    Response `json:"response"`
+}
+
+// Store persists idempotency records. Implementations must be safe for concurrent use.
+// The token passed to Begin identifies the request owning the reservation: Complete and
+// Release only apply while the key is not owned by another token.
+type Store interface {
+	// Begin atomically reserves key with fingerprint for lockTTL on behalf of token. When
+	// the key is already recorded, acquired is false and the existing record is returned.
+	Begin(ctx context.Context, key, fingerprint, token string, lockTTL time.Duration) (rec *Record, acquired bool, err error)
+	// Complete stores the response of a key reserved by token for ttl. It returns
+	// ErrLockLost when the key was reserved or completed by another token meanwhile.
+	Complete(ctx context.Context, key, token string, rec *Record, ttl time.Duration) error
+	// Release removes a key reserved by token so the request can be retried, leaving
+	// the key untouched when another token owns it.
+	Release(ctx context.Context, key, token string) error
+}
+
+// Idempotency executes operations at most once per key.
+type Idempotency struct {
+	store   Store
+	ttl     time.Duration
+	lockTTL time.Duration
+	prefix  string
+}
+
+// New creates a new Idempotency on top of store.
+// Default configuration: results kept for 24 hours, keys of in-flight requests
+// reserved for 1 minute.
+//
+// Example:
+//
+//	idem := idempotency.New(idempotency.NewRedisStore(r), idempotency.TTL(12*time.Hour))
+func New(store Store, opts ...Option) *Idempotency {
+	i := &Idempotency{
+		store:   store,
+		ttl:     _defaultTTL,
+		lockTTL: _defaultLockTTL,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Do runs fn once for key. A completed key with the same fingerprint replays its stored
+// response with replayed true; a key still in progress returns ErrInProgress and a key
+// recorded with another fingerprint returns ErrMismatch. When fn fails, the key is
+// released and the error returned, so the request can be retried. When fn outlives the
+// lock TTL and another request takes the key over, its response is returned with
+// ErrLockLost and not stored.
+//
+// Example:
+//
+//	resp, replayed, err := idem.Do(ctx, key, idempotency.Fingerprint(body), func(ctx context.Context) (idempotency.Response, error) {
+//	    id, err := payments.Charge(ctx, req)
+//	    return idempotency.Response{Body: []byte(id)}, err
+//	})
+func (i *Idempotency) Do(
+	ctx context.Context,
+	key, fingerprint string,
+	fn func(ctx context.Context) (Response, error),
+) (resp Response, replayed bool, err error) {
+	key = i.prefix + key
+	token := uuid.NewString()
+
+	rec, acquired, err := i.store.Begin(ctx, key, fingerprint, token, i.lockTTL)
+	if err != nil {
+		return Response{}, false, fmt.Errorf("idempotency - Do - i.store.Begin: %w", err)
+	}
+
+	if !acquired {
+		switch {
+		case rec.Fingerprint != fingerprint:
+			return Response{}, false, ErrMismatch
+		case !rec.Completed:
+			return Response{}, false, ErrInProgress
+		default:
+			return rec.Response, true, nil
+		}
+	}
+
+	resp, err = fn(ctx)
+	if err != nil {
+		if releaseErr := i.store.Release(context.WithoutCancel(ctx), key, token); releaseErr != nil {
+			return Response{}, false, errors.Join(err, fmt.Errorf("idempotency - Do - i.store.Release: %w", releaseErr))
+		}
+
+		return Response{}, false, err
+	}
+
+	rec = &Record{Fingerprint: fingerprint, Completed: true, Response: resp}
+
+	if err := i.store.Complete(context.WithoutCancel(ctx), key, token, rec, i.ttl); err != nil {
+		return resp, false, fmt.Errorf("idempotency - Do - i.store.Complete: %w", err)
+	}
+
+	return resp, false, nil
+}
+
+// Fingerprint returns a hex SHA-256 digest of parts, identifying a request payload.
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+
+	for _, p := range parts {
+		h.Write(p)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
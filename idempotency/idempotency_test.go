@@ -0,0 +1,255 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/idempotency"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	idem := idempotency.New(idempotency.NewMemoryStore())
+
+	calls := 0
+	fn := func(context.Context) (idempotency.Response, error) {
+		calls++
+
+		return idempotency.Response{StatusCode: 201, Body: []byte("created")}, nil
+	}
+
+	resp, replayed, err := idem.Do(ctx, "key-1", "fp", fn)
+	if err != nil || replayed || string(resp.Body) != "created" {
+		t.Fatalf("first Do() = %v, %v, %v", resp, replayed, err)
+	}
+
+	resp, replayed, err = idem.Do(ctx, "key-1", "fp", fn)
+	if err != nil || !replayed || resp.StatusCode != 201 || string(resp.Body) != "created" {
+		t.Fatalf("second Do() = %v, %v, %v", resp, replayed, err)
+	}
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	if _, _, err := idem.Do(ctx, "key-1", "other", fn); !errors.Is(err, idempotency.ErrMismatch) {
+		t.Errorf("Do() with another fingerprint error = %v, want ErrMismatch", err)
+	}
+}
+
+func TestDoInProgress(t *testing.T) {
+	ctx := context.Background()
+	idem := idempotency.New(idempotency.NewMemoryStore())
+
+	_, _, err := idem.Do(ctx, "key", "fp", func(ctx context.Context) (idempotency.Response, error) {
+		_, _, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+			t.Error("concurrent request must not run")
+
+			return idempotency.Response{}, nil
+		})
+
+		return idempotency.Response{}, err
+	})
+	if !errors.Is(err, idempotency.ErrInProgress) {
+		t.Errorf("Do() error = %v, want ErrInProgress", err)
+	}
+}
+
+func TestDoReleasesOnError(t *testing.T) {
+	ctx := context.Background()
+	idem := idempotency.New(idempotency.NewMemoryStore())
+	errFailed := errors.New("failed")
+
+	_, _, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+		return idempotency.Response{}, errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Do() error = %v, want %v", err, errFailed)
+	}
+
+	_, replayed, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+		return idempotency.Response{Body: []byte("ok")}, nil
+	})
+	if err != nil || replayed {
+		t.Errorf("retry Do() = %v, %v, want a fresh run", replayed, err)
+	}
+}
+
+func TestDoExpiredLock(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewMemoryStore()
+	idem := idempotency.New(store, idempotency.LockTTL(time.Millisecond))
+
+	// Simulates a crashed request that reserved the key and never completed it.
+	if _, acquired, err := store.Begin(ctx, "key", "fp", "crashed", time.Millisecond); err != nil || !acquired {
+		t.Fatalf("Begin() = %v, %v", acquired, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, replayed, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+		return idempotency.Response{}, nil
+	}); err != nil || replayed {
+		t.Errorf("Do() after lock expiry = %v, %v, want a fresh run", replayed, err)
+	}
+}
+
+func TestDoLockLost(t *testing.T) {
+	r, srv := redistest.New(t)
+
+	// expire makes the reservation of the running request expire.
+	stores := map[string]struct {
+		store  idempotency.Store
+		expire func()
+	}{
+		"memory": {idempotency.NewMemoryStore(), func() { time.Sleep(50 * time.Millisecond) }},
+		"redis":  {idempotency.NewRedisStore(r), func() { srv.Miniredis().FastForward(time.Second) }},
+	}
+
+	for name, tc := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			idem := idempotency.New(tc.store, idempotency.LockTTL(10*time.Millisecond))
+
+			respond := func(body string) func(context.Context) (idempotency.Response, error) {
+				return func(context.Context) (idempotency.Response, error) {
+					return idempotency.Response{Body: []byte(body)}, nil
+				}
+			}
+
+			resp, _, err := idem.Do(ctx, "key", "fp", func(ctx context.Context) (idempotency.Response, error) {
+				tc.expire()
+
+				// The reservation expired: another request takes the key over and completes it.
+				if _, _, err := idem.Do(ctx, "key", "fp", respond("second")); err != nil {
+					t.Errorf("takeover Do() error = %v", err)
+				}
+
+				return idempotency.Response{Body: []byte("first")}, nil
+			})
+			if !errors.Is(err, idempotency.ErrLockLost) || string(resp.Body) != "first" {
+				t.Errorf("Do() = %s, %v, want first, ErrLockLost", resp.Body, err)
+			}
+
+			resp, replayed, err := idem.Do(ctx, "key", "fp", respond("third"))
+			if err != nil || !replayed || string(resp.Body) != "second" {
+				t.Errorf("replayed Do() = %s, %v, %v, want the response of the takeover", resp.Body, replayed, err)
+			}
+		})
+	}
+}
+
+func TestDoReleaseKeepsTakenOverKey(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewMemoryStore()
+	idem := idempotency.New(store, idempotency.LockTTL(10*time.Millisecond))
+
+	_, _, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+
+		// Another request takes the expired reservation over while this one fails.
+		if _, acquired, err := store.Begin(ctx, "key", "fp", "other", time.Minute); err != nil || !acquired {
+			t.Errorf("takeover Begin() = %v, %v", acquired, err)
+		}
+
+		return idempotency.Response{}, errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("Do() error = nil, want the error of fn")
+	}
+
+	if _, _, err := idem.Do(ctx, "key", "fp", func(context.Context) (idempotency.Response, error) {
+		t.Error("request must not run while the key is reserved by another request")
+
+		return idempotency.Response{}, nil
+	}); !errors.Is(err, idempotency.ErrInProgress) {
+		t.Errorf("Do() error = %v, want ErrInProgress", err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if idempotency.Fingerprint([]byte("a"), []byte("bc")) == idempotency.Fingerprint([]byte("ab"), []byte("c")) {
+		t.Error("Fingerprint() must distinguish part boundaries")
+	}
+
+	if idempotency.Fingerprint([]byte("a")) != idempotency.Fingerprint([]byte("a")) {
+		t.Error("Fingerprint() must be deterministic")
+	}
+}
+
+func TestKafkaHandler(t *testing.T) {
+	idem := idempotency.New(idempotency.NewMemoryStore())
+
+	calls := 0
+	h := idempotency.KafkaHandler(idem, func(*kgo.Record) (interface{}, error) {
+		calls++
+
+		return map[string]int{"calls": calls}, nil
+	})
+
+	record := &kgo.Record{
+		Topic:   "payments",
+		Value:   []byte(`{"amount":10}`),
+		Headers: []kgo.RecordHeader{{Key: idempotency.HeaderKey, Value: []byte("key-1")}},
+	}
+
+	for range 2 {
+		resp, err := h(record)
+		if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+
+		body, _ := json.Marshal(resp)
+		if string(body) != `{"calls":1}` {
+			t.Errorf("response = %s, want {\"calls\":1}", body)
+		}
+	}
+
+	if _, err := h(&kgo.Record{Topic: "payments"}); err != nil || calls != 2 {
+		t.Errorf("record without key: calls = %d, err = %v, want pass-through", calls, err)
+	}
+}
+
+func TestRabbitMQHandler(t *testing.T) {
+	idem := idempotency.New(idempotency.NewMemoryStore())
+
+	calls := 0
+	h := idempotency.RabbitMQHandler(idem, func(*amqp.Delivery) (interface{}, error) {
+		calls++
+
+		return "ok", nil
+	})
+
+	tests := []struct {
+		name     string
+		delivery *amqp.Delivery
+		calls    int
+	}{
+		{name: "message id", delivery: &amqp.Delivery{MessageId: "m-1", Body: []byte("a")}, calls: 1},
+		{name: "redelivered", delivery: &amqp.Delivery{MessageId: "m-1", Body: []byte("a")}, calls: 1},
+		{name: "header takes precedence", delivery: &amqp.Delivery{
+			MessageId: "m-1",
+			Headers:   amqp.Table{idempotency.HeaderKey: "k-1"},
+			Body:      []byte("a"),
+		}, calls: 2},
+		{name: "no key", delivery: &amqp.Delivery{Body: []byte("a")}, calls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h(tt.delivery); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store for tests and single instance services.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+	now     func() time.Time
+}
+
+type memoryRecord struct {
+	rec       Record
+	token     string
+	expiresAt time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]memoryRecord),
+		now:     time.Now,
+	}
+}
+
+// Begin implements Store.
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if existing, ok := s.records[key]; ok && now.Before(existing.expiresAt) {
+		rec := existing.rec
+
+		return &rec, false, nil
+	}
+
+	s.records[key] = memoryRecord{
+		rec:       Record{Fingerprint: fingerprint},
+		token:     token,
+		expiresAt: now.Add(lockTTL),
+	}
+
+	return nil, true, nil
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(_ context.Context, key, token string, rec *Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok && existing.token != token {
+		return ErrLockLost
+	}
+
+	s.records[key] = memoryRecord{rec: *rec, token: token, expiresAt: s.now().Add(ttl)}
+
+	return nil
+}
+
+// Release implements Store.
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok && existing.token == token {
+		delete(s.records, key)
+	}
+
+	return nil
+}
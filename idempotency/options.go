@@ -0,0 +1,30 @@
+package idempotency
+
+import "time"
+
+// Option is a function that configures an Idempotency.
+type Option func(*Idempotency)
+
+// TTL sets how long completed results are kept and replayed.
+// Default is 24 hours.
+func TTL(ttl time.Duration) Option {
+	return func(i *Idempotency) {
+		i.ttl = ttl
+	}
+}
+
+// LockTTL sets how long the key of an in-flight request stays reserved, bounding how
+// long a crashed request blocks retries. It should exceed the longest request duration.
+// Default is 1 minute.
+func LockTTL(ttl time.Duration) Option {
+	return func(i *Idempotency) {
+		i.lockTTL = ttl
+	}
+}
+
+// KeyPrefix prefixes every key, e.g. with the service name. Default is no prefix.
+func KeyPrefix(prefix string) Option {
+	return func(i *Idempotency) {
+		i.prefix = prefix
+	}
+}
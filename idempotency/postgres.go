@@ -0,0 +1,155 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// DefaultTable is the table used by PostgresStore when no table name is given.
+const DefaultTable = "idempotency_keys"
+
+// Schema returns the DDL creating the idempotency table,
+// for use in migrations (e.g. with the goose package).
+func Schema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    key         TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    completed   BOOLEAN NOT NULL DEFAULT false,
+    response    JSONB,
+    token       TEXT NOT NULL DEFAULT '',
+    expires_at  TIMESTAMPTZ NOT NULL
+);`, postgres.TableIdentifier(table).Sanitize())
+}
+
+// PostgresStore is a Store backed by a Postgres table. Expired rows are taken over
+// by new requests; they can be purged periodically with DeleteExpired.
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	name  string
+	table string
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a new PostgresStore using table, or DefaultTable when empty.
+//
+// Example:
+//
+//	store := idempotency.NewPostgresStore(pg, "")
+//	err := store.CreateTable(ctx)
+func NewPostgresStore(pg *postgres.Postgres, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	return &PostgresStore{pool: pg.Pool, name: table, table: postgres.TableIdentifier(table).Sanitize()}
+}
+
+// CreateTable creates the idempotency table if it does not exist.
+func (s *PostgresStore) CreateTable(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, Schema(s.name)); err != nil {
+		return fmt.Errorf("idempotency - PostgresStore - CreateTable - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Begin implements Store.
+func (s *PostgresStore) Begin(ctx context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, bool, error) {
+	insert := fmt.Sprintf(`INSERT INTO %[1]s AS t (key, fingerprint, token, expires_at)
+VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+ON CONFLICT (key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint, completed = false, response = NULL, token = EXCLUDED.token,
+expires_at = EXCLUDED.expires_at
+WHERE t.expires_at <= now()`, s.table)
+
+	selectExisting := fmt.Sprintf(`SELECT fingerprint, completed, response FROM %s
+WHERE key = $1 AND expires_at > now()`, s.table)
+
+	// The existing row may expire between the insert and the select, in which case the reservation is retried.
+	for attempt := 0; attempt < 2; attempt++ {
+		tag, err := s.pool.Exec(ctx, insert, key, fingerprint, token, lockTTL.Seconds())
+		if err != nil {
+			return nil, false, fmt.Errorf("idempotency - PostgresStore - Begin - insert: %w", err)
+		}
+
+		if tag.RowsAffected() == 1 {
+			return nil, true, nil
+		}
+
+		var (
+			rec      Record
+			response []byte
+		)
+
+		err = s.pool.QueryRow(ctx, selectExisting, key).Scan(&rec.Fingerprint, &rec.Completed, &response)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("idempotency - PostgresStore - Begin - select: %w", err)
+		}
+
+		if len(response) > 0 {
+			if err := json.Unmarshal(response, &rec.Response); err != nil {
+				return nil, false, fmt.Errorf("idempotency - PostgresStore - Begin - json.Unmarshal: %w", err)
+			}
+		}
+
+		return &rec, false, nil
+	}
+
+	return nil, false, errors.New("idempotency - PostgresStore - Begin - key changed concurrently")
+}
+
+// Complete implements Store.
+func (s *PostgresStore) Complete(ctx context.Context, key, token string, rec *Record, ttl time.Duration) error {
+	response, err := json.Marshal(rec.Response)
+	if err != nil {
+		return fmt.Errorf("idempotency - PostgresStore - Complete - json.Marshal: %w", err)
+	}
+
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf(`INSERT INTO %[1]s AS t (key, fingerprint, completed, response, token, expires_at)
+VALUES ($1, $2, true, $3, $4, now() + make_interval(secs => $5))
+ON CONFLICT (key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint, completed = true, response = EXCLUDED.response, expires_at = EXCLUDED.expires_at
+WHERE t.token = EXCLUDED.token`,
+		s.table), key, rec.Fingerprint, response, token, ttl.Seconds())
+	if err != nil {
+		return fmt.Errorf("idempotency - PostgresStore - Complete - s.pool.Exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Release implements Store.
+func (s *PostgresStore) Release(ctx context.Context, key, token string) error {
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE key = $1 AND token = $2`, s.table), key, token)
+	if err != nil {
+		return fmt.Errorf("idempotency - PostgresStore - Release - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes expired keys and returns the number of deleted rows.
+func (s *PostgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= now()`, s.table))
+	if err != nil {
+		return 0, fmt.Errorf("idempotency - PostgresStore - DeleteExpired - s.pool.Exec: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,114 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// completeScript stores the completed record ARGV[2] for ARGV[3] milliseconds unless
+// the key is owned by another token than ARGV[1].
+var completeScript = goredis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value and cjson.decode(value).token ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1`)
+
+// releaseScript deletes the key only if it is owned by the token ARGV[1].
+var releaseScript = goredis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value and cjson.decode(value).token == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisStore is a Store backed by Redis, keeping each record as a JSON value with a TTL.
+type RedisStore struct {
+	client goredis.UniversalClient
+}
+
+// redisRecord is the stored value, with the token of the request owning the key.
+type redisRecord struct {
+	Record
+	Token string `json:"token"`
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a new RedisStore.
+func NewRedisStore(r *redis.Redis) *RedisStore {
+	return &RedisStore{client: r.Client()}
+}
+
+// Begin implements Store.
+func (s *RedisStore) Begin(ctx context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, bool, error) {
+	value, err := json.Marshal(redisRecord{Record: Record{Fingerprint: fingerprint}, Token: token})
+	if err != nil {
+		return nil, false, fmt.Errorf("idempotency - RedisStore - Begin - json.Marshal: %w", err)
+	}
+
+	// The existing record may expire between SET NX and GET, in which case the reservation is retried.
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := s.client.SetNX(ctx, key, value, lockTTL).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("idempotency - RedisStore - Begin - SetNX: %w", err)
+		}
+
+		if acquired {
+			return nil, true, nil
+		}
+
+		existing, err := s.client.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("idempotency - RedisStore - Begin - Get: %w", err)
+		}
+
+		var rec redisRecord
+		if err := json.Unmarshal(existing, &rec); err != nil {
+			return nil, false, fmt.Errorf("idempotency - RedisStore - Begin - json.Unmarshal: %w", err)
+		}
+
+		return &rec.Record, false, nil
+	}
+
+	return nil, false, errors.New("idempotency - RedisStore - Begin - key changed concurrently")
+}
+
+// Complete implements Store.
+func (s *RedisStore) Complete(ctx context.Context, key, token string, rec *Record, ttl time.Duration) error {
+	value, err := json.Marshal(redisRecord{Record: *rec, Token: token})
+	if err != nil {
+		return fmt.Errorf("idempotency - RedisStore - Complete - json.Marshal: %w", err)
+	}
+
+	stored, err := completeScript.Run(ctx, s.client, []string{key}, token, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("idempotency - RedisStore - Complete - completeScript.Run: %w", err)
+	}
+
+	if stored == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Release implements Store.
+func (s *RedisStore) Release(ctx context.Context, key, token string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("idempotency - RedisStore - Release - releaseScript.Run: %w", err)
+	}
+
+	return nil
+}
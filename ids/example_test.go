@@ -0,0 +1,31 @@
+package ids_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/ids"
+)
+
+func ExampleParseULID() {
+	id, _ := ids.ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	fmt.Println(id.Time().UTC().Format(time.RFC3339))
+	fmt.Println(id.UUID())
+	// Output:
+	// 2016-07-30T23:54:10Z
+	// 01563e3a-b5d3-d676-4c61-efb99302bd5b
+}
+
+func ExampleNewSnowflakeGenerator() {
+	gen, err := ids.NewSnowflakeGenerator(ids.StaticMachineID(3))
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	id := gen.Next()
+	fmt.Println(gen.MachineID(id))
+	// Output: 3
+}
@@ -0,0 +1,35 @@
+// Package ids generates time-sortable identifiers: UUIDv7, ULID and Snowflake-style
+// 64-bit IDs with a pluggable machine ID strategy. Every type implements
+// driver.Valuer and sql.Scanner for use as Postgres primary keys and has a Key
+// method returning its canonical form for Kafka message keys, so services agree on
+// the encoding of the same ID.
+//
+// Recommended Postgres column types: uuid for UUIDv7 and ULID, bigint for Snowflake.
+package ids
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// NewUUID returns a new UUIDv7, ordered by creation time at millisecond precision.
+// It panics if the random source fails, like uuid.New.
+func NewUUID() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// ParseUUID parses a UUID in its canonical form.
+func ParseUUID(s string) (uuid.UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ids - ParseUUID - %w", err)
+	}
+
+	return u, nil
+}
+
+// UUIDKey returns the canonical form of u as a message key.
+func UUIDKey(u uuid.UUID) []byte {
+	return []byte(u.String())
+}
@@ -0,0 +1,198 @@
+package ids_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/ids"
+)
+
+func TestNewUUID(t *testing.T) {
+	a, b := ids.NewUUID(), ids.NewUUID()
+
+	if a.Version() != 7 {
+		t.Errorf("Version() = %d, want 7", a.Version())
+	}
+
+	if a.String() >= b.String() {
+		t.Errorf("UUIDs are not ordered: %s >= %s", a, b)
+	}
+
+	parsed, err := ids.ParseUUID(a.String())
+	if err != nil || parsed != a {
+		t.Errorf("ParseUUID() = %s, %v; want %s", parsed, err, a)
+	}
+
+	if string(ids.UUIDKey(a)) != a.String() {
+		t.Errorf("UUIDKey() = %s, want %s", ids.UUIDKey(a), a)
+	}
+}
+
+func TestULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+
+	generated := make([]string, 1000)
+	for i := range generated {
+		generated[i] = ids.NewULID().String()
+	}
+
+	if !sort.StringsAreSorted(generated) {
+		t.Error("ULIDs are not strictly ordered")
+	}
+
+	id, err := ids.ParseULID(generated[0])
+	if err != nil {
+		t.Fatalf("ParseULID() error = %v", err)
+	}
+
+	if id.String() != generated[0] {
+		t.Errorf("round trip = %s, want %s", id, generated[0])
+	}
+
+	if ts := id.Time(); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("Time() = %v, want around %v", ts, before)
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid", input: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "lower case", input: "01arz3ndektsv4rrffq69g5fav"},
+		{name: "max", input: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{name: "overflow", input: "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", wantErr: true},
+		{name: "too short", input: "01ARZ3NDEK", wantErr: true},
+		{name: "invalid character", input: "01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ids.ParseULID(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseULID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ids.ErrInvalidULID) {
+				t.Errorf("error = %v, want ErrInvalidULID", err)
+			}
+		})
+	}
+}
+
+func TestULID_SQL(t *testing.T) {
+	id := ids.NewULID()
+
+	v, err := id.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	if v != id.UUID().String() {
+		t.Errorf("Value() = %v, want uuid form", v)
+	}
+
+	for _, src := range []interface{}{v, id.String(), id[:], [16]byte(uuid.UUID(id))} {
+		var scanned ids.ULID
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("Scan(%T) = %s, %v; want %s", src, scanned, err, id)
+		}
+	}
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	gen, err := ids.NewSnowflakeGenerator(ids.StaticMachineID(42))
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+
+	const workers, perWorker = 8, 2000
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[ids.Snowflake]bool, workers*perWorker)
+		wg   sync.WaitGroup
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			prev := ids.Snowflake(-1)
+
+			for i := 0; i < perWorker; i++ {
+				id := gen.Next()
+				if id <= prev {
+					t.Errorf("IDs are not increasing: %d <= %d", id, prev)
+				}
+
+				prev = id
+
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(seen) != workers*perWorker {
+		t.Errorf("got %d unique IDs, want %d", len(seen), workers*perWorker)
+	}
+
+	id := gen.Next()
+
+	if m := gen.MachineID(id); m != 42 {
+		t.Errorf("MachineID() = %d, want 42", m)
+	}
+
+	if d := time.Since(gen.Time(id)); d < 0 || d > time.Second {
+		t.Errorf("Time() is %s away from now", d)
+	}
+}
+
+func TestNewSnowflakeGenerator_InvalidMachineID(t *testing.T) {
+	for _, id := range []int64{-1, ids.MaxMachineID + 1} {
+		if _, err := ids.NewSnowflakeGenerator(ids.StaticMachineID(id)); !errors.Is(err, ids.ErrInvalidMachineID) {
+			t.Errorf("machine ID %d: error = %v, want ErrInvalidMachineID", id, err)
+		}
+	}
+}
+
+func TestSnowflake_JSON(t *testing.T) {
+	id := ids.Snowflake(1234567890123456789)
+
+	data, err := json.Marshal(id)
+	if err != nil || string(data) != `"1234567890123456789"` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	for _, input := range []string{`"1234567890123456789"`, `1234567890123456789`} {
+		var decoded ids.Snowflake
+		if err := json.Unmarshal([]byte(input), &decoded); err != nil || decoded != id {
+			t.Errorf("Unmarshal(%s) = %d, %v; want %d", input, decoded, err, id)
+		}
+	}
+}
+
+func TestEnvMachineID(t *testing.T) {
+	t.Setenv("IDS_MACHINE_ID", "7")
+
+	if id, err := ids.EnvMachineID("IDS_MACHINE_ID")(); err != nil || id != 7 {
+		t.Errorf("EnvMachineID() = %d, %v; want 7", id, err)
+	}
+
+	if _, err := ids.EnvMachineID("IDS_MACHINE_ID_UNSET")(); err == nil {
+		t.Error("expected error for unset variable")
+	}
+}
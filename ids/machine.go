@@ -0,0 +1,88 @@
+package ids
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MachineID resolves the machine ID of a Snowflake generator, in [0, MaxMachineID].
+type MachineID func() (int64, error)
+
+// StaticMachineID returns a fixed machine ID.
+func StaticMachineID(id int64) MachineID {
+	return func() (int64, error) {
+		return id, nil
+	}
+}
+
+// EnvMachineID reads the machine ID from the named environment variable.
+func EnvMachineID(name string) MachineID {
+	return func() (int64, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return 0, fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("environment variable %s: %w", name, err)
+		}
+
+		return id, nil
+	}
+}
+
+// HostnameMachineID derives the machine ID from the hostname. Hostnames ending with
+// an ordinal, like Kubernetes StatefulSet pods ("orders-3"), use the ordinal, which
+// is unique; other hostnames are hashed, which may collide between instances.
+func HostnameMachineID() MachineID {
+	return func() (int64, error) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return 0, fmt.Errorf("os.Hostname: %w", err)
+		}
+
+		return hostnameID(hostname), nil
+	}
+}
+
+func hostnameID(hostname string) int64 {
+	if i := strings.LastIndexByte(hostname, '-'); i >= 0 {
+		if ordinal, err := strconv.ParseInt(hostname[i+1:], 10, 64); err == nil && ordinal <= MaxMachineID {
+			return ordinal
+		}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostname))
+
+	return int64(h.Sum32() % (MaxMachineID + 1))
+}
+
+// PrivateIPMachineID derives the machine ID from the low 10 bits of the first private
+// IPv4 address, unique for instances within a /22 network.
+func PrivateIPMachineID() MachineID {
+	return func() (int64, error) {
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return 0, fmt.Errorf("net.InterfaceAddrs: %w", err)
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			if ip := ipNet.IP.To4(); ip != nil && ip.IsPrivate() {
+				return int64(ip[2])<<8&0x300 | int64(ip[3]), nil
+			}
+		}
+
+		return 0, fmt.Errorf("no private IPv4 address found")
+	}
+}
@@ -0,0 +1,26 @@
+package ids
+
+import "testing"
+
+func TestHostnameID(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     int64
+	}{
+		{hostname: "orders-0", want: 0},
+		{hostname: "orders-3", want: 3},
+		{hostname: "orders-api-1023", want: 1023},
+	}
+
+	for _, tt := range tests {
+		if got := hostnameID(tt.hostname); got != tt.want {
+			t.Errorf("hostnameID(%q) = %d, want %d", tt.hostname, got, tt.want)
+		}
+	}
+
+	for _, hostname := range []string{"orders-7f9c4b-xk2lp", "orders-5000", "localhost"} {
+		if got := hostnameID(hostname); got < 0 || got > MaxMachineID {
+			t.Errorf("hostnameID(%q) = %d, out of range", hostname, got)
+		}
+	}
+}
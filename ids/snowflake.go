@@ -0,0 +1,186 @@
+package ids
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Snowflake layout: 41 bits of milliseconds since the epoch, 10 bits of machine ID
+// and 12 bits of sequence, leaving the sign bit unset.
+const (
+	_machineBits  = 10
+	_sequenceBits = 12
+
+	// MaxMachineID is the largest machine ID of a Snowflake generator.
+	MaxMachineID = 1<<_machineBits - 1
+
+	_maxSequence = 1<<_sequenceBits - 1
+)
+
+// DefaultEpoch is the default Snowflake epoch, 2024-01-01 UTC, giving IDs for 69 years.
+var DefaultEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrInvalidMachineID is returned when a machine ID is outside [0, MaxMachineID].
+var ErrInvalidMachineID = errors.New("ids - invalid machine ID")
+
+// Snowflake is a 64-bit time-sortable ID. It is stored in bigint columns and
+// encoded as a JSON string, since JavaScript numbers cannot hold 64-bit integers.
+type Snowflake int64
+
+// ParseSnowflake parses the decimal form of a Snowflake.
+func ParseSnowflake(s string) (Snowflake, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ids - ParseSnowflake - %w", err)
+	}
+
+	return Snowflake(v), nil
+}
+
+// String returns the decimal form of the Snowflake.
+func (id Snowflake) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// Key returns the decimal form of the Snowflake as a message key.
+func (id Snowflake) Key() []byte {
+	return []byte(id.String())
+}
+
+// MarshalJSON encodes the Snowflake as a JSON string.
+func (id Snowflake) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + id.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a Snowflake from a JSON string or number.
+func (id *Snowflake) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	parsed, err := ParseSnowflake(s)
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (id Snowflake) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan implements sql.Scanner.
+func (id *Snowflake) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = Snowflake(v)
+	case string:
+		return id.UnmarshalJSON([]byte(v))
+	case []byte:
+		return id.UnmarshalJSON(v)
+	default:
+		return fmt.Errorf("ids - Snowflake - Scan - unsupported type %T", src)
+	}
+
+	return nil
+}
+
+// SnowflakeGenerator generates Snowflake IDs unique to its machine ID.
+// It is safe for concurrent use.
+type SnowflakeGenerator struct {
+	epoch     time.Time
+	machineID int64
+
+	mu       sync.Mutex
+	lastMs   int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator creates a generator whose machine ID is resolved by machineID.
+// Every running instance must use a distinct machine ID.
+// Default configuration: DefaultEpoch.
+//
+// Example:
+//
+//	gen, err := ids.NewSnowflakeGenerator(ids.HostnameMachineID())
+//	id := gen.Next()
+func NewSnowflakeGenerator(machineID MachineID, opts ...SnowflakeOption) (*SnowflakeGenerator, error) {
+	id, err := machineID()
+	if err != nil {
+		return nil, fmt.Errorf("ids - NewSnowflakeGenerator - machineID: %w", err)
+	}
+
+	if id < 0 || id > MaxMachineID {
+		return nil, fmt.Errorf("ids - NewSnowflakeGenerator - %w: %d", ErrInvalidMachineID, id)
+	}
+
+	g := &SnowflakeGenerator{epoch: DefaultEpoch, machineID: id}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g, nil
+}
+
+// Next returns a new Snowflake. Up to 4096 IDs are generated per millisecond; beyond
+// that, or when the clock moves backwards, Next waits for the clock to catch up.
+func (g *SnowflakeGenerator) Next() Snowflake {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.millis()
+
+	if ms <= g.lastMs {
+		g.sequence = (g.sequence + 1) & _maxSequence
+		if g.sequence == 0 {
+			for ms <= g.lastMs {
+				time.Sleep(time.Duration(g.lastMs-ms+1) * time.Millisecond)
+				ms = g.millis()
+			}
+		} else {
+			ms = g.lastMs
+		}
+	} else {
+		g.sequence = 0
+	}
+
+	g.lastMs = ms
+
+	return Snowflake(ms<<(_machineBits+_sequenceBits) | g.machineID<<_sequenceBits | g.sequence)
+}
+
+// Time returns the creation time of id at millisecond precision.
+func (g *SnowflakeGenerator) Time(id Snowflake) time.Time {
+	return g.epoch.Add(time.Duration(int64(id)>>(_machineBits+_sequenceBits)) * time.Millisecond)
+}
+
+// MachineID returns the machine ID that generated id.
+func (g *SnowflakeGenerator) MachineID(id Snowflake) int64 {
+	return int64(id) >> _sequenceBits & MaxMachineID
+}
+
+func (g *SnowflakeGenerator) millis() int64 {
+	return time.Since(g.epoch).Milliseconds()
+}
+
+// SnowflakeOption configures a SnowflakeGenerator.
+type SnowflakeOption func(*SnowflakeGenerator)
+
+// Epoch sets the time Snowflake timestamps are counted from. Services sharing IDs
+// must use the same epoch. Default is DefaultEpoch.
+func Epoch(epoch time.Time) SnowflakeOption {
+	return func(g *SnowflakeGenerator) {
+		g.epoch = epoch
+	}
+}
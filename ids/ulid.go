@@ -0,0 +1,216 @@
+package ids
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// _crockford is the Crockford base32 alphabet used by ULIDs.
+const _crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const _ulidLen = 26
+
+// ErrInvalidULID is returned when parsing a malformed ULID.
+var ErrInvalidULID = errors.New("ids - invalid ULID")
+
+// ULID is a 128-bit identifier made of a 48-bit millisecond timestamp and 80 random bits,
+// encoded as 26 Crockford base32 characters. Its byte and string orders follow creation time.
+// In Postgres it is stored in uuid columns, preserving its order.
+type ULID [16]byte
+
+var _crockfordIndex = func() [256]byte {
+	var idx [256]byte
+	for i := range idx {
+		idx[i] = 0xFF
+	}
+
+	for i := 0; i < len(_crockford); i++ {
+		c := _crockford[i]
+		idx[c] = byte(i)
+
+		if c >= 'A' {
+			idx[c+'a'-'A'] = byte(i)
+		}
+	}
+
+	return idx
+}()
+
+var ulidGen struct {
+	mu   sync.Mutex
+	last ULID
+	ms   int64
+}
+
+// NewULID returns a new ULID. ULIDs created within the same millisecond by the
+// process increment the random part, so they stay strictly ordered.
+// It panics if the random source fails.
+func NewULID() ULID {
+	ulidGen.mu.Lock()
+	defer ulidGen.mu.Unlock()
+
+	ms := time.Now().UnixMilli()
+
+	var id ULID
+
+	if ms <= ulidGen.ms {
+		// Same millisecond, or a clock moving backwards: increment the previous ULID.
+		id = ulidGen.last
+		for i := len(id) - 1; i >= 6; i-- {
+			id[i]++
+			if id[i] != 0 {
+				break
+			}
+		}
+	} else {
+		putMillis(&id, ms)
+
+		if _, err := rand.Read(id[6:]); err != nil {
+			panic(fmt.Sprintf("ids - NewULID - rand.Read: %v", err))
+		}
+
+		ulidGen.ms = ms
+	}
+
+	ulidGen.last = id
+
+	return id
+}
+
+func putMillis(id *ULID, ms int64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(ms))
+	copy(id[:6], buf[2:])
+}
+
+// ParseULID parses a ULID in its 26 character form. Parsing is case insensitive.
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+
+	if len(s) != _ulidLen || _crockfordIndex[s[0]] > 7 {
+		return id, fmt.Errorf("%w: %q", ErrInvalidULID, s)
+	}
+
+	// Decode the 130 bit string into 128 bits, most significant first.
+	var hi, lo uint64
+
+	for i := 0; i < _ulidLen; i++ {
+		v := _crockfordIndex[s[i]]
+		if v == 0xFF {
+			return ULID{}, fmt.Errorf("%w: %q", ErrInvalidULID, s)
+		}
+
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+
+	return id, nil
+}
+
+// String returns the 26 character form of the ULID.
+func (id ULID) String() string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	var buf [_ulidLen]byte
+
+	for i := _ulidLen - 1; i >= 0; i-- {
+		buf[i] = _crockford[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
+
+// Time returns the creation time of the ULID at millisecond precision.
+func (id ULID) Time() time.Time {
+	var buf [8]byte
+	copy(buf[2:], id[:6])
+
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(buf[:])))
+}
+
+// IsZero reports whether id is the zero ULID.
+func (id ULID) IsZero() bool {
+	return id == ULID{}
+}
+
+// UUID returns the ULID bytes as a UUID.
+func (id ULID) UUID() uuid.UUID {
+	return uuid.UUID(id)
+}
+
+// Key returns the canonical form of the ULID as a message key.
+func (id ULID) Key() []byte {
+	return []byte(id.String())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ULID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// Value implements driver.Valuer, storing the ULID in uuid columns.
+func (id ULID) Value() (driver.Value, error) {
+	return id.UUID().String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts the uuid and ULID string forms and 16 raw bytes.
+func (id *ULID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return id.scanString(v)
+	case []byte:
+		if len(v) == len(id) {
+			copy(id[:], v)
+
+			return nil
+		}
+
+		return id.scanString(string(v))
+	case [16]byte:
+		*id = v
+
+		return nil
+	default:
+		return fmt.Errorf("ids - ULID - Scan - unsupported type %T", src)
+	}
+}
+
+func (id *ULID) scanString(s string) error {
+	if len(s) == _ulidLen {
+		return id.UnmarshalText([]byte(s))
+	}
+
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return fmt.Errorf("ids - ULID - Scan - %w", err)
+	}
+
+	*id = ULID(u)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package jwt_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+func ExampleJWT_Sign() {
+	type UserClaims struct {
+		jwt.RegisteredClaims
+		Role string `json:"role"`
+	}
+
+	j, err := jwt.New(
+		jwt.HS256([]byte("secret")),
+		jwt.Issuer("auth-service"),
+		jwt.TTL(time.Hour),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	token, err := j.Sign(UserClaims{RegisteredClaims: j.NewRegisteredClaims("user-1"), Role: "admin"})
+	if err != nil {
+		panic(err)
+	}
+
+	claims, err := jwt.Parse[UserClaims](context.Background(), j, token)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(claims.Subject, claims.Role)
+	// Output: user-1 admin
+}
@@ -0,0 +1,240 @@
+// Package jwt provides JSON Web Token issuing and verification with HS256, RS256 and ES256,
+// JWKS key fetching with caching, clock skew tolerance and typed claims.
+// It backs the httpserver JWT middleware and the grpcserver auth interceptors.
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	_defaultTTL    = 15 * time.Minute
+	_defaultLeeway = 30 * time.Second
+)
+
+var (
+	// ErrInvalidToken is wrapped by every verification failure.
+	ErrInvalidToken = errors.New("jwt - invalid token")
+	// ErrExpiredToken is returned, along with ErrInvalidToken, for expired tokens.
+	ErrExpiredToken = errors.New("jwt - token is expired")
+	// ErrNoSigningKey is returned by Sign when no signing key is configured.
+	ErrNoSigningKey = errors.New("jwt - no signing key configured")
+	// ErrUnknownKey is returned, along with ErrInvalidToken, when no key matches the token.
+	ErrUnknownKey = errors.New("jwt - no key found for token")
+)
+
+// Claims is implemented by claim types. Custom claims embed RegisteredClaims.
+type Claims = gojwt.Claims
+
+// RegisteredClaims are the standard claims defined by RFC 7519.
+type RegisteredClaims = gojwt.RegisteredClaims
+
+// NumericDate is a JSON numeric date as used by the exp, iat and nbf claims.
+type NumericDate = gojwt.NumericDate
+
+// ClaimStrings is a claim holding one or more strings, such as aud.
+type ClaimStrings = gojwt.ClaimStrings
+
+// JWT signs and verifies tokens. It is safe for concurrent use.
+type JWT struct {
+	signingMethod gojwt.SigningMethod
+	signingKey    interface{}
+	keyID         string
+
+	// keys holds static verification keys by key ID; "" is used for tokens without kid.
+	keys map[string]interface{}
+	jwks *jwks
+
+	algorithms []string
+	issuer     string
+	audience   []string
+	ttl        time.Duration
+	leeway     time.Duration
+	now        func() time.Time
+}
+
+// New creates a new JWT signer and verifier.
+// At least one signing key (HS256, RS256, ES256), verification key (VerificationKey) or
+// key set (JWKS) must be configured. A signing key is also used to verify tokens.
+// Default configuration: 15 minutes token lifetime, 30 seconds clock skew tolerance.
+//
+// Example:
+//
+//	j, err := jwt.New(
+//	    jwt.HS256([]byte(secret)),
+//	    jwt.Issuer("auth-service"),
+//	    jwt.TTL(time.Hour),
+//	)
+func New(opts ...Option) (*JWT, error) {
+	j := &JWT{
+		keys:   make(map[string]interface{}),
+		ttl:    _defaultTTL,
+		leeway: _defaultLeeway,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	if j.signingMethod != nil {
+		verifyKey := j.signingKey
+
+		switch k := j.signingKey.(type) {
+		case *rsa.PrivateKey:
+			verifyKey = &k.PublicKey
+		case *ecdsa.PrivateKey:
+			verifyKey = &k.PublicKey
+		}
+
+		j.keys[j.keyID] = verifyKey
+	}
+
+	if len(j.keys) == 0 && j.jwks == nil {
+		return nil, errors.New("jwt - New - no signing key, verification key or JWKS configured")
+	}
+
+	if len(j.algorithms) == 0 {
+		j.algorithms = []string{"HS256", "RS256", "ES256"}
+	}
+
+	return j, nil
+}
+
+// NewRegisteredClaims returns registered claims for subject with the configured issuer,
+// audience and lifetime, the current issue time and a random token ID.
+//
+// Example:
+//
+//	token, err := j.Sign(UserClaims{
+//	    RegisteredClaims: j.NewRegisteredClaims(user.ID),
+//	    Role:             user.Role,
+//	})
+func (j *JWT) NewRegisteredClaims(subject string) RegisteredClaims {
+	now := j.now()
+
+	claims := RegisteredClaims{
+		Issuer:    j.issuer,
+		Subject:   subject,
+		IssuedAt:  gojwt.NewNumericDate(now),
+		NotBefore: gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(now.Add(j.ttl)),
+		ID:        uuid.NewString(),
+	}
+
+	if len(j.audience) > 0 {
+		claims.Audience = j.audience
+	}
+
+	return claims
+}
+
+// Sign returns the signed compact serialization of claims.
+func (j *JWT) Sign(claims Claims) (string, error) {
+	if j.signingMethod == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := gojwt.NewWithClaims(j.signingMethod, claims)
+	if j.keyID != "" {
+		token.Header["kid"] = j.keyID
+	}
+
+	signed, err := token.SignedString(j.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("jwt - Sign - token.SignedString: %w", err)
+	}
+
+	return signed, nil
+}
+
+// Verify checks the token signature, algorithm, expiry, not-before, issuer and audience,
+// and decodes its claims into claims, which must be a pointer.
+// All failures wrap ErrInvalidToken; expired tokens also wrap ErrExpiredToken.
+func (j *JWT) Verify(ctx context.Context, token string, claims Claims) error {
+	parserOpts := []gojwt.ParserOption{
+		gojwt.WithValidMethods(j.algorithms),
+		gojwt.WithLeeway(j.leeway),
+		gojwt.WithTimeFunc(j.now),
+		gojwt.WithExpirationRequired(),
+	}
+
+	if j.issuer != "" {
+		parserOpts = append(parserOpts, gojwt.WithIssuer(j.issuer))
+	}
+
+	if len(j.audience) > 0 {
+		parserOpts = append(parserOpts, gojwt.WithAudience(j.audience...))
+	}
+
+	_, err := gojwt.ParseWithClaims(token, claims, func(t *gojwt.Token) (interface{}, error) {
+		return j.key(ctx, t)
+	}, parserOpts...)
+	if err != nil {
+		if errors.Is(err, gojwt.ErrTokenExpired) {
+			return fmt.Errorf("%w: %w", ErrInvalidToken, ErrExpiredToken)
+		}
+
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return nil
+}
+
+// Parse verifies token and returns its claims decoded into a new T.
+//
+// Example:
+//
+//	claims, err := jwt.Parse[UserClaims](ctx, j, token)
+func Parse[T any, PT interface {
+	*T
+	Claims
+}](ctx context.Context, j *JWT, token string) (*T, error) {
+	claims := PT(new(T))
+
+	if err := j.Verify(ctx, token, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (j *JWT) key(ctx context.Context, t *gojwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+
+	if j.jwks != nil {
+		return j.jwks.key(ctx, kid)
+	}
+
+	return nil, ErrUnknownKey
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying verified claims.
+func NewContext(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext returns the claims stored in ctx by NewContext, if they are of type T.
+//
+// Example:
+//
+//	claims, ok := jwt.FromContext[*UserClaims](ctx)
+func FromContext[T Claims](ctx context.Context) (T, bool) {
+	claims, ok := ctx.Value(contextKey{}).(T)
+
+	return claims, ok
+}
@@ -0,0 +1,311 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+type userClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+func mustNew(t *testing.T, opts ...jwt.Option) *jwt.JWT {
+	t.Helper()
+
+	j, err := jwt.New(opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return j
+}
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opt  jwt.Option
+	}{
+		{name: "HS256", opt: jwt.HS256([]byte("secret"))},
+		{name: "RS256", opt: jwt.RS256(rsaKey)},
+		{name: "ES256", opt: jwt.ES256(ecKey)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := mustNew(t, tt.opt, jwt.Issuer("auth"), jwt.Audience("api"))
+
+			token, err := j.Sign(userClaims{RegisteredClaims: j.NewRegisteredClaims("user-1"), Role: "admin"})
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			var claims userClaims
+			if err := j.Verify(context.Background(), token, &claims); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if claims.Subject != "user-1" || claims.Role != "admin" || claims.Issuer != "auth" {
+				t.Errorf("Verify() claims = %+v", claims)
+			}
+
+			if claims.ID == "" {
+				t.Error("Verify() claims have no token ID")
+			}
+		})
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	j := mustNew(t, jwt.HS256([]byte("secret")), jwt.Issuer("auth"), jwt.Audience("api"), jwt.Leeway(time.Minute))
+
+	sign := func(t *testing.T, signer *jwt.JWT, mutate func(*jwt.RegisteredClaims)) string {
+		t.Helper()
+
+		claims := signer.NewRegisteredClaims("user-1")
+		mutate(&claims)
+
+		token, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+
+		return token
+	}
+
+	past := func(d time.Duration) *jwt.NumericDate {
+		return &jwt.NumericDate{Time: time.Now().Add(-d)}
+	}
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{
+			name: "expired within leeway",
+			token: func(t *testing.T) string {
+				return sign(t, j, func(c *jwt.RegisteredClaims) { c.ExpiresAt = past(30 * time.Second) })
+			},
+		},
+		{
+			name: "expired beyond leeway",
+			token: func(t *testing.T) string {
+				return sign(t, j, func(c *jwt.RegisteredClaims) { c.ExpiresAt = past(2 * time.Minute) })
+			},
+			wantErr: jwt.ErrExpiredToken,
+		},
+		{
+			name: "missing expiry",
+			token: func(t *testing.T) string {
+				return sign(t, j, func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil })
+			},
+			wantErr: jwt.ErrInvalidToken,
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				return sign(t, j, func(c *jwt.RegisteredClaims) { c.Issuer = "other" })
+			},
+			wantErr: jwt.ErrInvalidToken,
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				return sign(t, j, func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"other"} })
+			},
+			wantErr: jwt.ErrInvalidToken,
+		},
+		{
+			name: "wrong secret",
+			token: func(t *testing.T) string {
+				other := mustNew(t, jwt.HS256([]byte("other")), jwt.Issuer("auth"), jwt.Audience("api"))
+
+				return sign(t, other, func(*jwt.RegisteredClaims) {})
+			},
+			wantErr: jwt.ErrInvalidToken,
+		},
+		{
+			name: "unknown key ID",
+			token: func(t *testing.T) string {
+				other := mustNew(t, jwt.HS256([]byte("secret")), jwt.KeyID("k2"), jwt.Issuer("auth"), jwt.Audience("api"))
+
+				return sign(t, other, func(*jwt.RegisteredClaims) {})
+			},
+			wantErr: jwt.ErrUnknownKey,
+		},
+		{
+			name:    "malformed",
+			token:   func(*testing.T) string { return "not.a.token" },
+			wantErr: jwt.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := j.Verify(context.Background(), tt.token(t), &jwt.RegisteredClaims{})
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !errors.Is(err, jwt.ErrInvalidToken) {
+				t.Errorf("Verify() error = %v does not wrap ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	signer := mustNew(t, jwt.HS256([]byte("secret")))
+
+	token, err := signer.Sign(signer.NewRegisteredClaims("user-1"))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	verifier := mustNew(t, jwt.VerificationKey("", []byte("secret")), jwt.Algorithms("RS256"))
+
+	if err := verifier.Verify(context.Background(), token, &jwt.RegisteredClaims{}); !errors.Is(err, jwt.ErrInvalidToken) {
+		t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := jwt.New(); err == nil {
+		t.Error("New() without keys error = nil, want error")
+	}
+
+	j := mustNew(t, jwt.VerificationKey("", []byte("secret")))
+
+	if _, err := j.Sign(j.NewRegisteredClaims("user-1")); !errors.Is(err, jwt.ErrNoSigningKey) {
+		t.Errorf("Sign() error = %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+	keys := []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa-1", "use": "sig",
+			"n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec-1", "crv": "P-256",
+			"x": encode(ecKey.X.Bytes()), "y": encode(ecKey.Y.Bytes()),
+		},
+		{"kty": "oct", "kid": "ignored"},
+	}
+
+	var fetches atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer srv.Close()
+
+	verifier := mustNew(t, jwt.JWKS(srv.URL, time.Hour))
+
+	tests := []struct {
+		name    string
+		signer  *jwt.JWT
+		wantErr bool
+	}{
+		{name: "RSA key", signer: mustNew(t, jwt.RS256(rsaKey), jwt.KeyID("rsa-1"))},
+		{name: "EC key", signer: mustNew(t, jwt.ES256(ecKey), jwt.KeyID("ec-1"))},
+		{name: "unknown kid", signer: mustNew(t, jwt.RS256(rsaKey), jwt.KeyID("rsa-2")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.Sign(tt.signer.NewRegisteredClaims("user-1"))
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			err = verifier.Verify(context.Background(), token, &jwt.RegisteredClaims{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The unknown kid must not trigger a refetch within the minimum refresh interval.
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", got)
+	}
+}
+
+func TestParseAndContext(t *testing.T) {
+	j := mustNew(t, jwt.HS256([]byte("secret")))
+
+	token, err := j.Sign(userClaims{RegisteredClaims: j.NewRegisteredClaims("user-1"), Role: "admin"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	claims, err := jwt.Parse[userClaims](context.Background(), j, token)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if claims.Role != "admin" {
+		t.Errorf("Parse() role = %q, want admin", claims.Role)
+	}
+
+	ctx := jwt.NewContext(context.Background(), claims)
+
+	got, ok := jwt.FromContext[*userClaims](ctx)
+	if !ok || got.Subject != "user-1" {
+		t.Errorf("FromContext() = %+v, %v", got, ok)
+	}
+
+	if _, ok := jwt.FromContext[*jwt.RegisteredClaims](ctx); ok {
+		t.Error("FromContext() with another claims type ok = true, want false")
+	}
+}
@@ -0,0 +1,108 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"net/http"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Option configures a JWT.
+type Option func(*JWT)
+
+// HS256 signs and verifies tokens with HMAC SHA-256 using secret.
+func HS256(secret []byte) Option {
+	return func(j *JWT) {
+		j.signingMethod = gojwt.SigningMethodHS256
+		j.signingKey = secret
+	}
+}
+
+// RS256 signs tokens with RSA SHA-256 using key, and verifies them with its public key.
+func RS256(key *rsa.PrivateKey) Option {
+	return func(j *JWT) {
+		j.signingMethod = gojwt.SigningMethodRS256
+		j.signingKey = key
+	}
+}
+
+// ES256 signs tokens with ECDSA P-256 SHA-256 using key, and verifies them with its public key.
+func ES256(key *ecdsa.PrivateKey) Option {
+	return func(j *JWT) {
+		j.signingMethod = gojwt.SigningMethodES256
+		j.signingKey = key
+	}
+}
+
+// KeyID sets the kid header of signed tokens, and the key ID the signing key verifies.
+func KeyID(kid string) Option {
+	return func(j *JWT) {
+		j.keyID = kid
+	}
+}
+
+// VerificationKey adds a key verifying tokens whose kid header equals kid
+// ("" for tokens without kid): an HMAC secret ([]byte), *rsa.PublicKey or *ecdsa.PublicKey.
+func VerificationKey(kid string, key interface{}) Option {
+	return func(j *JWT) {
+		j.keys[kid] = key
+	}
+}
+
+// JWKS verifies tokens with keys fetched from a JSON Web Key Set URL.
+// The set is cached for refresh (default 1 hour when zero) and refetched early,
+// at most once a minute, when a token references an unknown key ID.
+func JWKS(url string, refresh time.Duration) Option {
+	return func(j *JWT) {
+		if refresh <= 0 {
+			refresh = _defaultJWKSRefresh
+		}
+
+		j.jwks = &jwks{
+			url:        url,
+			client:     &http.Client{Timeout: _defaultJWKSTimeout},
+			refresh:    refresh,
+			minRefresh: _defaultJWKSMinRefresh,
+		}
+	}
+}
+
+// Algorithms restricts the accepted signing algorithms.
+// Default is HS256, RS256 and ES256.
+func Algorithms(algs ...string) Option {
+	return func(j *JWT) {
+		j.algorithms = algs
+	}
+}
+
+// Issuer sets the iss claim of issued tokens and requires it on verified tokens.
+func Issuer(issuer string) Option {
+	return func(j *JWT) {
+		j.issuer = issuer
+	}
+}
+
+// Audience sets the aud claim of issued tokens and requires at least one of them on verified tokens.
+func Audience(audience ...string) Option {
+	return func(j *JWT) {
+		j.audience = audience
+	}
+}
+
+// TTL sets the lifetime of tokens created with NewRegisteredClaims.
+// Default is 15 minutes.
+func TTL(ttl time.Duration) Option {
+	return func(j *JWT) {
+		j.ttl = ttl
+	}
+}
+
+// Leeway sets the tolerated clock skew when validating exp, nbf and iat.
+// Default is 30 seconds.
+func Leeway(leeway time.Duration) Option {
+	return func(j *JWT) {
+		j.leeway = leeway
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/breaker"
 	kafka "github.com/rdashevsky/go-pkgs/kafka"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
@@ -58,6 +59,7 @@ type Client struct {
 	calls map[string]*pendingCall
 
 	callTimeout time.Duration
+	breaker     *breaker.Breaker
 }
 
 // New creates a new Kafka RPC client with the specified configuration.
@@ -106,19 +108,7 @@ func New(cfg kafka.Config, requestTopic, replyTopic string, opts ...Option) (*Cl
 	return c, nil
 }
 
-func (c *Client) publish(ctx context.Context, corrID, handler string, request interface{}) error {
-	var (
-		requestBody []byte
-		err         error
-	)
-
-	if request != nil {
-		requestBody, err = json.Marshal(request)
-		if err != nil {
-			return err
-		}
-	}
-
+func (c *Client) publish(ctx context.Context, corrID, handler string, requestBody []byte) error {
 	headers := []kgo.RecordHeader{
 		{Key: "handler", Value: []byte(handler)},
 		{Key: "correlation_id", Value: []byte(corrID)},
@@ -151,17 +141,44 @@ func (c *Client) publish(ctx context.Context, corrID, handler string, request in
 //
 // Returns an error if the call times out, the connection is closed,
 // or the remote handler returns an error.
-func (c *Client) RemoteCall(ctx context.Context, handler string, request, response interface{}) error {
+// With a CircuitBreaker configured, calls are rejected with breaker.ErrOpen while it is open.
+func (c *Client) RemoteCall(ctx context.Context, handler string, request, response interface{}) error { //nolint:cyclop,funlen // complex func
+	var (
+		requestBody []byte
+		err         error
+	)
+
+	if request != nil {
+		requestBody, err = json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("kafka_rpc client - Client - RemoteCall - json.Marshal: %w", err)
+		}
+	}
+
+	done, err := c.allow()
+	if err != nil {
+		return fmt.Errorf("kafka_rpc client - Client - RemoteCall - c.breaker.Allow: %w", err)
+	}
+
+	// Only failures of the connection or the server are reported to the breaker,
+	// errors caused by the request itself count as successful calls.
+	var failure error
+	defer func() { done(failure) }()
+
 	select {
 	case <-c.stop:
+		failure = ErrConnectionClosed
+
 		return ErrConnectionClosed
 	default:
 	}
 
 	corrID := uuid.New().String()
 
-	err := c.publish(ctx, corrID, handler, request)
+	err = c.publish(ctx, corrID, handler, requestBody)
 	if err != nil {
+		failure = err
+
 		return fmt.Errorf("kafka_rpc client - Client - RemoteCall - c.publish: %w", err)
 	}
 
@@ -176,6 +193,8 @@ func (c *Client) RemoteCall(ctx context.Context, handler string, request, respon
 	select {
 	case <-timeoutCtx.Done():
 		if timeoutCtx.Err() == context.DeadlineExceeded {
+			failure = kafka.ErrTimeout
+
 			return kafka.ErrTimeout
 		}
 		return timeoutCtx.Err()
@@ -199,12 +218,23 @@ func (c *Client) RemoteCall(ctx context.Context, handler string, request, respon
 	}
 
 	if call.status == kafka.ErrInternalServer.Error() {
+		failure = kafka.ErrInternalServer
+
 		return kafka.ErrInternalServer
 	}
 
 	return nil
 }
 
+// allow checks the circuit breaker, if any, returning the function reporting the call outcome.
+func (c *Client) allow() (func(error), error) {
+	if c.breaker == nil {
+		return func(error) {}, nil
+	}
+
+	return c.breaker.Allow()
+}
+
 func (c *Client) consumer() {
 	for {
 		select {
@@ -1,25 +1,25 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
-	kafka "github.com/rdashevsky/go-pkgs/kafka"
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/containers"
 )
 
 func TestCallTimeout(t *testing.T) {
 	timeout := 15 * time.Second
-	cfg := kafka.Config{
-		Brokers:    []string{"localhost:9092"},
-		ClientID:   "test-client",
-		GroupID:    "test-group",
-		AutoCommit: true,
-	}
+	cfg := containers.Kafka(t)
+	cfg.ClientID = "test-client"
+	cfg.GroupID = "test-group"
+	cfg.AutoCommit = true
 
 	client, err := New(cfg, "test-requests", "test-replies", CallTimeout(timeout))
 	if err != nil {
-		// Skip test if Kafka is not available
-		t.Skipf("Skipping test - Kafka not available: %v", err)
+		t.Fatalf("failed to connect to Kafka: %v", err)
 	}
 	defer func() {
 		if client != nil {
@@ -31,3 +31,32 @@ func TestCallTimeout(t *testing.T) {
 		t.Errorf("Expected call timeout %v, got %v", timeout, client.callTimeout)
 	}
 }
+
+func TestCircuitBreaker(t *testing.T) {
+	b := breaker.New(breaker.MinRequests(2), breaker.OpenDuration(time.Hour))
+
+	c := &Client{stop: make(chan struct{})}
+	CircuitBreaker(b)(c)
+	close(c.stop)
+
+	for i := 0; i < 2; i++ {
+		err := c.RemoteCall(context.Background(), "handler", nil, nil)
+		if !errors.Is(err, ErrConnectionClosed) {
+			t.Fatalf("expected ErrConnectionClosed, got: %v", err)
+		}
+	}
+
+	if b.State() != breaker.StateOpen {
+		t.Fatalf("expected closed connection failures to open the breaker, got %s", b.State())
+	}
+
+	err := c.RemoteCall(context.Background(), "handler", nil, nil)
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("expected breaker.ErrOpen, got: %v", err)
+	}
+
+	err = c.RemoteCall(context.Background(), "handler", make(chan int), nil)
+	if err == nil || errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("expected marshal error before the breaker check, got: %v", err)
+	}
+}
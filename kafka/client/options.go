@@ -1,6 +1,10 @@
 package client
 
-import "time"
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/breaker"
+)
 
 // Option is a function that configures a Client.
 type Option func(*Client)
@@ -11,3 +15,12 @@ func CallTimeout(timeout time.Duration) Option {
 		c.callTimeout = timeout
 	}
 }
+
+// CircuitBreaker guards remote calls with the given circuit breaker.
+// Timeouts, produce failures, closed connections and internal server errors count as
+// failures; while the breaker is open RemoteCall fails fast with breaker.ErrOpen.
+func CircuitBreaker(b *breaker.Breaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/rdashevsky/go-pkgs/retry"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 // Config holds the configuration for a Kafka connection.
 // It specifies the broker URLs, retry parameters, and timing.
+// Hooks are passed to the franz-go client, e.g. for metrics.
 type Config struct {
 	Brokers     []string
 	Timeout     time.Duration
@@ -20,6 +22,7 @@ type Config struct {
 	GroupID     string
 	AutoCommit  bool
 	StartOffset int64
+	Hooks       []kgo.Hook
 }
 
 // Connection represents a Kafka connection with a client.
@@ -88,6 +91,10 @@ func (c *Connection) Connect(ctx context.Context) error {
 		kgo.RequestTimeoutOverhead(c.Timeout),
 	}
 
+	if len(c.Hooks) > 0 {
+		opts = append(opts, kgo.WithHooks(c.Hooks...))
+	}
+
 	if c.GroupID != "" {
 		opts = append(opts, kgo.ConsumerGroup(c.GroupID))
 		switch c.StartOffset {
@@ -106,24 +113,17 @@ func (c *Connection) Connect(ctx context.Context) error {
 		}
 	}
 
-	var err error
-	for i := 0; i <= c.MaxRetries; i++ {
-		c.Client, err = kgo.NewClient(opts...)
-		if err == nil {
-			// Just return on successful client creation for now
-			// In practice, the client will handle connection issues
-			return nil
-		}
+	// Client creation does not dial the brokers, the client handles connection issues itself.
+	err := retry.Do(ctx, func(context.Context) error {
+		var err error
 
-		if i < c.MaxRetries {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(c.RetryDelay):
-			}
-		}
-	}
+		c.Client, err = kgo.NewClient(opts...)
 
+		return err
+	},
+		retry.Attempts(c.MaxRetries+1),
+		retry.ConstantBackoff(c.RetryDelay),
+	)
 	if err != nil {
 		return fmt.Errorf("kafka - Connect - failed after %d attempts: %w", c.MaxRetries+1, err)
 	}
@@ -1,4 +1,36 @@
 package server
 
+import "github.com/rdashevsky/go-pkgs/semaphore"
+
 // Option is a function that configures a Server.
 type Option func(*Server)
+
+// Workers sets the number of requests processed concurrently.
+// With a single worker records are handled sequentially in the consumer goroutine;
+// with more, they are dispatched to a bounded worker pool and Shutdown drains
+// in-flight requests before closing the connection.
+// Default is 1.
+//
+// Example:
+//
+//	server.New(cfg, "rpc-requests", router, logger, server.Workers(8))
+func Workers(n int) Option {
+	return func(s *Server) {
+		s.workers = n
+	}
+}
+
+// Semaphore bounds handler executions with sem, each request holding a weight of 1
+// while its handler runs. Sharing sem between servers, consumers or HTTP handlers
+// bounds their combined concurrency, e.g. against a shared database.
+// Default is no semaphore.
+//
+// Example:
+//
+//	db := semaphore.New(20)
+//	server.New(cfg, "rpc-requests", router, logger, server.Workers(8), server.Semaphore(db))
+func Semaphore(sem *semaphore.Semaphore) Option {
+	return func(s *Server) {
+		s.sem = sem
+	}
+}
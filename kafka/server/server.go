@@ -9,9 +9,13 @@ import (
 	"github.com/goccy/go-json"
 	kafka "github.com/rdashevsky/go-pkgs/kafka"
 	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/semaphore"
+	"github.com/rdashevsky/go-pkgs/workerpool"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+const _defaultWorkers = 1
+
 // CallHandler is a function that processes an incoming RPC request.
 // It receives the Kafka record containing the request and returns a response and/or error.
 // The response will be JSON marshaled before sending back to the client.
@@ -27,6 +31,10 @@ type Server struct {
 	stop         chan struct{}
 	router       map[string]CallHandler
 
+	workers int
+	pool    *workerpool.Pool[*kgo.Record, struct{}]
+	sem     *semaphore.Semaphore
+
 	logger logger.LoggerI
 }
 
@@ -55,6 +63,7 @@ func New(cfg kafka.Config, requestTopic string, router map[string]CallHandler, l
 		error:        make(chan error, 1),
 		stop:         make(chan struct{}),
 		router:       router,
+		workers:      _defaultWorkers,
 		logger:       l,
 	}
 
@@ -63,6 +72,19 @@ func New(cfg kafka.Config, requestTopic string, router map[string]CallHandler, l
 		opt(s)
 	}
 
+	if s.workers > 1 {
+		s.pool = workerpool.New(func(_ context.Context, record *kgo.Record) (struct{}, error) {
+			s.serveCall(record)
+
+			return struct{}{}, nil
+		},
+			workerpool.Workers(s.workers),
+			workerpool.QueueSize(s.workers),
+			workerpool.DiscardResults(),
+			workerpool.WithLogger(l),
+		)
+	}
+
 	err := s.conn.Connect(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("kafka_rpc server - NewServer - s.conn.Connect: %w", err)
@@ -78,6 +100,10 @@ func New(cfg kafka.Config, requestTopic string, router map[string]CallHandler, l
 // The server processes incoming requests in a separate goroutine.
 // Use Notify() to receive server lifecycle errors.
 func (s *Server) Start() {
+	if s.pool != nil {
+		s.pool.Start()
+	}
+
 	go s.consumer()
 }
 
@@ -101,7 +127,16 @@ func (s *Server) consumer() {
 		}
 
 		fetches.EachRecord(func(record *kgo.Record) {
-			s.serveCall(record)
+			if s.pool == nil {
+				s.serveCall(record)
+
+				return
+			}
+
+			// Blocks while all workers are busy, applying backpressure to the consumer.
+			if err := s.pool.Submit(context.Background(), record); err != nil {
+				s.logger.Error(err, "kafka_rpc server - Server - consumer - s.pool.Submit")
+			}
 		})
 	}
 }
@@ -132,6 +167,15 @@ func (s *Server) serveCall(record *kgo.Record) {
 		return
 	}
 
+	if s.sem != nil {
+		if err := s.sem.Acquire(context.Background(), 1); err != nil {
+			s.publish(replyTopic, corrID, nil, kafka.ErrInternalServer.Error())
+			s.logger.Error(err, "kafka_rpc server - Server - serveCall - s.sem.Acquire")
+			return
+		}
+		defer s.sem.Release(1)
+	}
+
 	response, err := callHandler(record)
 	if err != nil {
 		s.publish(replyTopic, corrID, nil, kafka.ErrInternalServer.Error())
@@ -178,7 +222,8 @@ func (s *Server) Notify() <-chan error {
 }
 
 // Shutdown gracefully stops the Kafka server.
-// It stops consuming messages and closes the underlying connection.
+// It stops consuming messages, drains in-flight requests when Workers is set,
+// and closes the underlying connection.
 // Returns an error if the connection close fails.
 func (s *Server) Shutdown() error {
 	select {
@@ -188,6 +233,13 @@ func (s *Server) Shutdown() error {
 	}
 
 	close(s.stop)
+
+	if s.pool != nil {
+		if err := s.pool.Shutdown(); err != nil {
+			s.logger.Warn("kafka_rpc server - Server - Shutdown - s.pool.Shutdown: %v", err)
+		}
+	}
+
 	s.conn.Close()
 
 	return nil
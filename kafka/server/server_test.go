@@ -32,3 +32,25 @@ func TestNew_MissingGroupID(t *testing.T) {
 		t.Errorf("Unexpected error message: %v", err)
 	}
 }
+
+func TestWorkers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		workers int
+	}{
+		{"default sequential", 1},
+		{"zero workers", 0},
+		{"worker pool", 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{workers: _defaultWorkers}
+			Workers(tc.workers)(s)
+
+			if s.workers != tc.workers {
+				t.Errorf("expected %d workers, got %d", tc.workers, s.workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package leader_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/leader"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/scheduler"
+)
+
+// Example demonstrates running scheduled jobs on the elected instance only
+func Example() {
+	r, err := redis.New("localhost:6379", "", "")
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	l := logger.New("info")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := leader.NewRedis(r, "reports", leader.TTL(10*time.Second), leader.WithLogger(l))
+
+	roles, err := e.Campaign(ctx)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		for role := range roles {
+			l.Info("reports instance is now %s", role)
+		}
+	}()
+
+	s := scheduler.New(l, scheduler.WithLeader(e))
+	_ = s.Add("daily-report", scheduler.MustCron("0 6 * * *"), func(context.Context) error {
+		return nil
+	}, scheduler.LeaderOnly())
+
+	s.Start()
+	defer s.Shutdown() //nolint:errcheck // example
+}
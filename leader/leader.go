@@ -0,0 +1,253 @@
+// Package leader elects a single leader among the instances of a service, on top of
+// the distributed locks of the lock package: the instance holding the election lock
+// leads until it resigns or loses its lease, and followers keep campaigning to take
+// over. Backends are PostgreSQL advisory locks, Redis and etcd.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/etcd"
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/postgres"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// ErrCampaigning is returned by Campaign while a previous campaign is still running.
+var ErrCampaigning = errors.New("leader - campaign already running")
+
+// Role is the role of an instance in an election.
+type Role int
+
+// Roles.
+const (
+	Follower Role = iota
+	Leader
+)
+
+// String returns the role name.
+func (r Role) String() string {
+	if r == Leader {
+		return "leader"
+	}
+
+	return "follower"
+}
+
+// Elector campaigns for the leadership of a named election.
+// It is safe for concurrent use.
+type Elector struct {
+	locker lock.Locker
+	name   string
+	cfg    config
+
+	leader atomic.Bool
+	token  atomic.Int64
+
+	mu          sync.Mutex
+	campaigning bool
+}
+
+// New creates a new Elector for the election name, using locker to hold the
+// leadership. The TTL and Prefix options only apply to the NewPostgres, NewRedis and
+// NewEtcd constructors; locker keeps its own configuration.
+// Default configuration: followers retry every second.
+//
+// Example:
+//
+//	e := leader.New(lock.NewRedis(r, lock.TTL(10*time.Second)), "billing")
+func New(locker lock.Locker, name string, opts ...Option) *Elector {
+	return &Elector{
+		locker: locker,
+		name:   name,
+		cfg:    newConfig(opts),
+	}
+}
+
+// NewPostgres creates a new Elector holding the leadership with a PostgreSQL advisory
+// lock, which occupies one pool connection while leading.
+// Default configuration: "leader:" prefix, 15 seconds TTL, followers retry every second.
+//
+// Example:
+//
+//	e := leader.NewPostgres(pg, "billing", leader.WithLogger(l))
+//	roles, err := e.Campaign(ctx)
+func NewPostgres(pg *postgres.Postgres, name string, opts ...Option) *Elector {
+	cfg := newConfig(opts)
+
+	return New(lock.NewPostgres(pg, cfg.lockOptions()...), name, opts...)
+}
+
+// NewRedis creates a new Elector holding the leadership with a Redis lock renewed
+// while leading. A crashed leader is replaced within TTL.
+// Default configuration: "leader:" prefix, 15 seconds TTL, followers retry every second.
+func NewRedis(r *redis.Redis, name string, opts ...Option) *Elector {
+	cfg := newConfig(opts)
+
+	return New(lock.NewRedis(r, cfg.lockOptions()...), name, opts...)
+}
+
+// NewEtcd creates a new Elector holding the leadership with an etcd key attached to
+// a lease renewed while leading. A crashed leader is replaced within TTL.
+// Default configuration: "leader:" prefix, 15 seconds TTL, followers retry every second.
+func NewEtcd(e *etcd.Etcd, name string, opts ...Option) *Elector {
+	cfg := newConfig(opts)
+
+	return New(lock.NewEtcd(e, cfg.lockOptions()...), name, opts...)
+}
+
+// Campaign runs for leadership until ctx is done, then resigns. It makes a first
+// attempt before returning, so the first role received on the channel is already
+// the outcome of the election. Role changes are then sent as they happen; a reader
+// lagging behind only receives the latest role. The channel is closed once the
+// campaign ends and the leadership, if held, is released.
+//
+// Backend errors during the campaign are logged and retried as followers; an error
+// on the first attempt is returned.
+//
+// Example:
+//
+//	roles, err := e.Campaign(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for role := range roles {
+//	    l.Info("billing is now %s", role)
+//	}
+func (e *Elector) Campaign(ctx context.Context) (<-chan Role, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.campaigning {
+		return nil, ErrCampaigning
+	}
+
+	held, err := e.locker.TryAcquire(ctx, e.name)
+	if err != nil && !errors.Is(err, lock.ErrNotAcquired) {
+		return nil, fmt.Errorf("leader - Campaign - e.locker.TryAcquire: %w", err)
+	}
+
+	e.campaigning = true
+
+	roles := make(chan Role, 1)
+
+	go e.campaign(ctx, held, roles)
+
+	return roles, nil
+}
+
+func (e *Elector) campaign(ctx context.Context, held lock.Lock, roles chan Role) {
+	defer func() {
+		e.mu.Lock()
+		e.campaigning = false
+		e.mu.Unlock()
+
+		close(roles)
+	}()
+
+	ticker := time.NewTicker(e.cfg.retryInterval)
+	defer ticker.Stop()
+
+	if held == nil {
+		e.publish(roles, Follower)
+	}
+
+	for {
+		if held != nil {
+			e.token.Store(held.Token())
+			e.leader.Store(true)
+			e.publish(roles, Leader)
+
+			e.lead(ctx, held)
+
+			e.leader.Store(false)
+			held = nil
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			e.publish(roles, Follower)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var err error
+
+		held, err = e.locker.TryAcquire(ctx, e.name)
+		if err != nil {
+			held = nil
+
+			if !errors.Is(err, lock.ErrNotAcquired) && ctx.Err() == nil {
+				e.logError("leader - %s - campaign: %v", e.name, err)
+			}
+		}
+	}
+}
+
+// lead holds the leadership until ctx is done or the lease is lost, then releases it.
+func (e *Elector) lead(ctx context.Context, held lock.Lock) {
+	select {
+	case <-ctx.Done():
+	case <-held.Lost():
+		e.logError("leader - %s - leadership lost", e.name)
+	}
+
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.cfg.retryInterval+time.Second)
+	defer cancel()
+
+	// Releasing a lost lease frees its resources, such as a PostgreSQL connection.
+	if err := held.Release(releaseCtx); err != nil && !errors.Is(err, lock.ErrNotHeld) {
+		e.logError("leader - %s - release: %v", e.name, err)
+	}
+}
+
+// publish sends role, replacing a role the reader has not received yet.
+func (e *Elector) publish(roles chan Role, role Role) {
+	for {
+		select {
+		case roles <- role:
+			return
+		default:
+		}
+
+		select {
+		case <-roles:
+		default:
+		}
+	}
+}
+
+// IsLeader reports whether the instance currently leads the election.
+// It can be passed to scheduler.WithLeader to run jobs on the leader only.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Token returns the fencing token of the current or last leadership term, which
+// increases with every term. Storage written by the leader can reject writes carrying
+// a token lower than the last one it has seen. It is 0 before the first term.
+func (e *Elector) Token() int64 {
+	return e.token.Load()
+}
+
+// Name returns the election name.
+func (e *Elector) Name() string {
+	return e.name
+}
+
+func (e *Elector) logError(message string, args ...interface{}) {
+	if e.cfg.logger != nil {
+		e.cfg.logger.Error(message, args...)
+	}
+}
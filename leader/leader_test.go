@@ -0,0 +1,264 @@
+package leader_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/leader"
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// memLocker is an in-memory lock.Locker shared by the electors of a test.
+type memLocker struct {
+	mu    sync.Mutex
+	held  map[string]*memLock
+	token int64
+	err   error
+}
+
+func newMemLocker() *memLocker {
+	return &memLocker{held: map[string]*memLock{}}
+}
+
+func (l *memLocker) TryAcquire(_ context.Context, key string) (lock.Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	if l.held[key] != nil {
+		return nil, lock.ErrNotAcquired
+	}
+
+	l.token++
+	lk := &memLock{locker: l, key: key, token: l.token, lost: make(chan struct{})}
+	l.held[key] = lk
+
+	return lk, nil
+}
+
+// expire makes the holder of key lose its lease.
+func (l *memLocker) expire(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lk := l.held[key]; lk != nil {
+		delete(l.held, key)
+		close(lk.lost)
+	}
+}
+
+func (l *memLocker) holder(key string) *memLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.held[key]
+}
+
+type memLock struct {
+	locker *memLocker
+	key    string
+	token  int64
+	lost   chan struct{}
+}
+
+func (lk *memLock) Key() string           { return lk.key }
+func (lk *memLock) Token() int64          { return lk.token }
+func (lk *memLock) Lost() <-chan struct{} { return lk.lost }
+
+func (lk *memLock) Release(context.Context) error {
+	lk.locker.mu.Lock()
+	defer lk.locker.mu.Unlock()
+
+	if lk.locker.held[lk.key] != lk {
+		return lock.ErrNotHeld
+	}
+
+	delete(lk.locker.held, lk.key)
+	close(lk.lost)
+
+	return nil
+}
+
+func next(t *testing.T, roles <-chan leader.Role) leader.Role {
+	t.Helper()
+
+	select {
+	case role, ok := <-roles:
+		if !ok {
+			t.Fatal("roles channel closed")
+		}
+
+		return role
+	case <-time.After(2 * time.Second):
+		t.Fatal("no role received")
+
+		return leader.Follower
+	}
+}
+
+func TestElector_Campaign(t *testing.T) {
+	locker := newMemLocker()
+	opts := []leader.Option{leader.RetryInterval(10 * time.Millisecond)}
+
+	a := leader.New(locker, "billing", opts...)
+	b := leader.New(locker, "billing", opts...)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+
+	rolesA, err := a.Campaign(ctxA)
+	if err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	if role := next(t, rolesA); role != leader.Leader || !a.IsLeader() || a.Token() != 1 {
+		t.Fatalf("first elector role = %v, token %d; want leader with token 1", role, a.Token())
+	}
+
+	if _, err := a.Campaign(ctxA); !errors.Is(err, leader.ErrCampaigning) {
+		t.Errorf("second Campaign() error = %v, want ErrCampaigning", err)
+	}
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	rolesB, err := b.Campaign(ctxB)
+	if err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	if role := next(t, rolesB); role != leader.Follower || b.IsLeader() {
+		t.Fatalf("second elector role = %v, want follower", role)
+	}
+
+	// The leader resigns and the follower takes over.
+	cancelA()
+
+	if _, ok := <-rolesA; ok {
+		t.Error("roles channel of a resigned elector should be closed without a new role")
+	}
+
+	if a.IsLeader() {
+		t.Error("resigned elector still reports leadership")
+	}
+
+	if role := next(t, rolesB); role != leader.Leader || b.Token() != 2 {
+		t.Errorf("second elector role = %v, token %d; want leader with token 2", role, b.Token())
+	}
+
+	cancelB()
+
+	for range rolesB {
+	}
+
+	if locker.holder("billing") != nil {
+		t.Error("leadership not released after the campaign ended")
+	}
+}
+
+func TestElector_LostLeadership(t *testing.T) {
+	locker := newMemLocker()
+	e := leader.New(locker, "billing", leader.RetryInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	roles, err := e.Campaign(ctx)
+	if err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	if role := next(t, roles); role != leader.Leader {
+		t.Fatalf("role = %v, want leader", role)
+	}
+
+	// Another instance grabs the lock as soon as the lease expires.
+	locker.expire("billing")
+
+	locker.mu.Lock()
+	locker.held["billing"] = &memLock{locker: locker, key: "billing", lost: make(chan struct{})}
+	locker.mu.Unlock()
+
+	if role := next(t, roles); role != leader.Follower || e.IsLeader() {
+		t.Fatalf("role after lost lease = %v, want follower", role)
+	}
+
+	locker.expire("billing")
+
+	if role := next(t, roles); role != leader.Leader {
+		t.Errorf("role after the other leader left = %v, want leader", role)
+	}
+}
+
+func TestElector_BackendErrors(t *testing.T) {
+	locker := newMemLocker()
+	locker.err = errors.New("backend down")
+
+	e := leader.New(locker, "billing", leader.RetryInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := e.Campaign(ctx); err == nil {
+		t.Fatal("Campaign() with failing backend: expected error")
+	}
+
+	// Later failures are retried.
+	locker.mu.Lock()
+	locker.held["billing"] = &memLock{locker: locker, key: "billing", lost: make(chan struct{})}
+	locker.err = nil
+	locker.mu.Unlock()
+
+	roles, err := e.Campaign(ctx)
+	if err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	if role := next(t, roles); role != leader.Follower {
+		t.Fatalf("role = %v, want follower", role)
+	}
+
+	locker.mu.Lock()
+	locker.err = errors.New("backend down")
+	locker.mu.Unlock()
+
+	time.Sleep(30 * time.Millisecond)
+
+	locker.mu.Lock()
+	locker.err = nil
+	locker.mu.Unlock()
+
+	locker.expire("billing")
+
+	if role := next(t, roles); role != leader.Leader {
+		t.Errorf("role after backend recovery = %v, want leader", role)
+	}
+}
+
+func TestRole_String(t *testing.T) {
+	if leader.Leader.String() != "leader" || leader.Follower.String() != "follower" {
+		t.Errorf("String() = %q, %q", leader.Leader, leader.Follower)
+	}
+}
+
+func TestNewRedis_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := leader.NewRedis(r, "billing").Campaign(ctx); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+}
@@ -0,0 +1,77 @@
+package leader
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultPrefix        = "leader:"
+	_defaultTTL           = 15 * time.Second
+	_defaultRetryInterval = time.Second
+)
+
+// Option configures an Elector.
+type Option func(*config)
+
+type config struct {
+	prefix        string
+	ttl           time.Duration
+	retryInterval time.Duration
+	logger        logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		prefix:        _defaultPrefix,
+		ttl:           _defaultTTL,
+		retryInterval: _defaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+func (c config) lockOptions() []lock.Option {
+	return []lock.Option{lock.Prefix(c.prefix), lock.TTL(c.ttl)}
+}
+
+// Prefix sets the prefix of election lock keys.
+// Default is "leader:".
+func Prefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// TTL sets the leadership lease duration: a crashed Redis or etcd leader is replaced
+// within TTL. The lease is renewed every third of it.
+// Default is 15 seconds.
+func TTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// RetryInterval sets how often followers try to take the leadership.
+// Default is 1 second.
+func RetryInterval(interval time.Duration) Option {
+	return func(c *config) {
+		if interval > 0 {
+			c.retryInterval = interval
+		}
+	}
+}
+
+// WithLogger sets the logger reporting backend errors and lost leaderships.
+// Default is nil: errors are not reported.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,84 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/etcd"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type etcdLocker struct {
+	e   *etcd.Etcd
+	cfg config
+}
+
+// NewEtcd returns a Locker backed by etcd keys attached to a lease of the configured TTL,
+// renewed while held, so the lock is freed within TTL when its owner dies.
+// Fencing tokens are the etcd revisions creating the lock keys, which increase
+// monotonically across the cluster.
+//
+// Example:
+//
+//	locker := lock.NewEtcd(e, lock.TTL(10*time.Second))
+//	l, err := locker.TryAcquire(ctx, "reports")
+func NewEtcd(e *etcd.Etcd, opts ...Option) Locker {
+	return &etcdLocker{e: e, cfg: newConfig(opts)}
+}
+
+func (l *etcdLocker) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	lockKey := l.cfg.prefix + key
+	owner := uuid.NewString()
+	client := l.e.Client()
+
+	// Lease TTLs are whole seconds; rounding up keeps the renew interval below the TTL.
+	lease, err := client.Grant(ctx, max(int64((l.cfg.ttl+time.Second-1)/time.Second), 1))
+	if err != nil {
+		return nil, fmt.Errorf("lock - Etcd - TryAcquire - client.Grant: %w", err)
+	}
+
+	resp, err := client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(lockKey), "=", 0)).
+		Then(clientv3.OpPut(lockKey, owner, clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil || !resp.Succeeded {
+		_, _ = client.Revoke(context.WithoutCancel(ctx), lease.ID)
+
+		if err != nil {
+			return nil, fmt.Errorf("lock - Etcd - TryAcquire - client.Txn: %w", err)
+		}
+
+		return nil, ErrNotAcquired
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		_, err := client.KeepAliveOnce(ctx, lease.ID)
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return false, nil
+		}
+
+		return err == nil, err
+	}
+
+	release := func(ctx context.Context) (bool, error) {
+		resp, err := client.Txn(ctx).
+			If(clientv3.Compare(clientv3.Value(lockKey), "=", owner)).
+			Then(clientv3.OpDelete(lockKey)).
+			Commit()
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := client.Revoke(ctx, lease.ID); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return resp.Succeeded, err
+		}
+
+		return resp.Succeeded, nil
+	}
+
+	return newLease(key, resp.Header.Revision, l.cfg.renewInterval, l.cfg.ttl, renew, release), nil
+}
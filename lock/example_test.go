@@ -0,0 +1,36 @@
+package lock_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// Example demonstrates guarding work with a renewed Redis lock
+func Example() {
+	r, err := redis.New("localhost:6379", "", "")
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	locker := lock.NewRedis(r, lock.TTL(10*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l, err := lock.Acquire(ctx, locker, "reports", time.Second)
+	if err != nil {
+		return
+	}
+	defer l.Release(context.Background()) //nolint:errcheck // example
+
+	select {
+	case <-l.Lost():
+		// Stop working: another owner may hold the lock now.
+	case <-time.After(time.Second):
+		// Work done while holding the lock, passing l.Token() to guarded storage.
+	}
+}
@@ -0,0 +1,93 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLease_Renewal(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	testCases := []struct {
+		name     string
+		renew    func(calls int32) (bool, error)
+		wantLost bool
+	}{
+		{"renewed", func(int32) (bool, error) { return true, nil }, false},
+		{"taken over", func(calls int32) (bool, error) { return calls < 2, nil }, true},
+		{"transient error", func(calls int32) (bool, error) {
+			if calls == 1 {
+				return false, errUnavailable
+			}
+
+			return true, nil
+		}, false},
+		{"persistent error", func(int32) (bool, error) { return false, errUnavailable }, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			l := newLease("key", 7, 5*time.Millisecond, 30*time.Millisecond,
+				func(context.Context) (bool, error) { return tc.renew(calls.Add(1)) },
+				func(context.Context) (bool, error) { return true, nil },
+			)
+
+			select {
+			case <-l.Lost():
+				if !tc.wantLost {
+					t.Error("expected lock to stay held")
+				}
+			case <-time.After(80 * time.Millisecond):
+				if tc.wantLost {
+					t.Error("expected lock to be lost")
+				}
+			}
+
+			if calls.Load() < 2 {
+				t.Errorf("expected lease to be renewed, got %d renewals", calls.Load())
+			}
+
+			_ = l.Release(context.Background())
+		})
+	}
+}
+
+func TestLease_Release(t *testing.T) {
+	released := 0
+
+	l := newLease("key", 1, time.Hour, time.Hour,
+		func(context.Context) (bool, error) { return true, nil },
+		func(context.Context) (bool, error) {
+			released++
+
+			return false, nil
+		},
+	)
+
+	if l.Key() != "key" || l.Token() != 1 {
+		t.Errorf("unexpected key %q or token %d", l.Key(), l.Token())
+	}
+
+	if err := l.Release(context.Background()); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld, got: %v", err)
+	}
+
+	if err := l.Release(context.Background()); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected repeated Release to return the same error, got: %v", err)
+	}
+
+	if released != 1 {
+		t.Errorf("expected backend release to be called once, got %d", released)
+	}
+
+	select {
+	case <-l.Lost():
+	default:
+		t.Error("expected Lost to be closed after Release")
+	}
+}
@@ -0,0 +1,183 @@
+// Package lock provides a distributed lock abstraction with Redis, etcd and PostgreSQL
+// advisory lock backends, automatic lease renewal and fencing tokens, so schedulers,
+// migrations and application code can depend on a single API regardless of backend.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultPrefix         = "lock:"
+	_defaultTTL            = 30 * time.Second
+	_defaultRetryInterval  = 100 * time.Millisecond
+	_defaultKeepAliveRatio = 3
+)
+
+var (
+	// ErrNotAcquired is returned by TryAcquire when the lock is held by another owner.
+	ErrNotAcquired = errors.New("lock - lock is held by another owner")
+	// ErrNotHeld is returned by Release when the lock was lost before being released.
+	ErrNotHeld = errors.New("lock - lock is no longer held")
+)
+
+// Locker acquires named distributed locks.
+type Locker interface {
+	// TryAcquire acquires the lock on key without blocking.
+	// It returns ErrNotAcquired when the lock is held by another owner.
+	TryAcquire(ctx context.Context, key string) (Lock, error)
+}
+
+// Lock is a held distributed lock. Its lease is renewed in the background until Release.
+type Lock interface {
+	// Key returns the locked key.
+	Key() string
+	// Token returns the fencing token of this acquisition. Tokens increase with every
+	// acquisition of the same key, so storage guarded by the lock can reject writes
+	// carrying a token lower than the last one it has seen.
+	Token() int64
+	// Lost returns a channel closed when the lock is lost, e.g. because its lease could
+	// not be renewed. Work guarded by the lock should stop when it is closed.
+	Lost() <-chan struct{}
+	// Release releases the lock and stops its renewal.
+	// It returns ErrNotHeld if the lock had already been lost.
+	Release(ctx context.Context) error
+}
+
+// Acquire blocks until the lock on key is acquired or ctx is done,
+// retrying TryAcquire every retryInterval (100ms when zero).
+//
+// Example:
+//
+//	l, err := lock.Acquire(ctx, locker, "migrations", time.Second)
+//	if err != nil {
+//	    return err
+//	}
+//	defer l.Release(context.Background())
+func Acquire(ctx context.Context, l Locker, key string, retryInterval time.Duration) (Lock, error) {
+	if retryInterval <= 0 {
+		retryInterval = _defaultRetryInterval
+	}
+
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		lk, err := l.TryAcquire(ctx, key)
+		if err == nil {
+			return lk, nil
+		}
+
+		if !errors.Is(err, ErrNotAcquired) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lock - Acquire - %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// lease implements Lock on top of backend specific renew and release functions.
+type lease struct {
+	key   string
+	token int64
+
+	// renew extends the lease. It reports held == false when the lock was taken over.
+	renew func(ctx context.Context) (held bool, err error)
+	// release gives the lock up. It reports held == false when the lock was no longer owned.
+	release func(ctx context.Context) (held bool, err error)
+	// tolerance is how long renewal errors are tolerated before the lock is considered lost.
+	tolerance time.Duration
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+
+	releaseOnce sync.Once
+	releaseErr  error
+}
+
+func newLease(key string, token int64, interval, tolerance time.Duration,
+	renew, release func(context.Context) (bool, error),
+) *lease {
+	l := &lease{
+		key:       key,
+		token:     token,
+		renew:     renew,
+		release:   release,
+		tolerance: tolerance,
+		lost:      make(chan struct{}),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go l.keepAlive(interval)
+
+	return l
+}
+
+func (l *lease) Key() string           { return l.key }
+func (l *lease) Token() int64          { return l.token }
+func (l *lease) Lost() <-chan struct{} { return l.lost }
+
+func (l *lease) Release(ctx context.Context) error {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		held, err := l.release(ctx)
+
+		switch {
+		case err != nil:
+			l.releaseErr = fmt.Errorf("lock - Release - %s: %w", l.key, err)
+		case !held:
+			l.releaseErr = ErrNotHeld
+		}
+
+		l.markLost()
+	})
+
+	return l.releaseErr
+}
+
+func (l *lease) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+func (l *lease) keepAlive(interval time.Duration) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastRenewed := time.Now()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		held, err := l.renew(ctx)
+		cancel()
+
+		switch {
+		case err == nil && held:
+			lastRenewed = time.Now()
+		case err == nil || time.Since(lastRenewed) >= l.tolerance:
+			l.markLost()
+
+			return
+		}
+	}
+}
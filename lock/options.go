@@ -0,0 +1,59 @@
+package lock
+
+import "time"
+
+// Option configures a Locker backend.
+type Option func(*config)
+
+type config struct {
+	prefix        string
+	ttl           time.Duration
+	renewInterval time.Duration
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		prefix: _defaultPrefix,
+		ttl:    _defaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.ttl <= 0 {
+		cfg.ttl = _defaultTTL
+	}
+
+	if cfg.renewInterval <= 0 {
+		cfg.renewInterval = cfg.ttl / _defaultKeepAliveRatio
+	}
+
+	return cfg
+}
+
+// Prefix sets the prefix prepended to every lock key.
+// Default is "lock:".
+func Prefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// TTL sets the lease duration. A Redis or etcd lock expires after TTL unless renewed, so it is
+// freed within TTL when its owner dies. For PostgreSQL, whose locks are freed with the
+// session, TTL only bounds how long a broken connection goes unnoticed.
+// Default is 30 seconds.
+func TTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// RenewInterval sets how often the lease is renewed; it must be shorter than TTL.
+// Default is a third of TTL.
+func RenewInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.renewInterval = interval
+	}
+}
@@ -0,0 +1,87 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+type postgresLocker struct {
+	pg  *postgres.Postgres
+	cfg config
+}
+
+// NewPostgres returns a Locker backed by PostgreSQL session advisory locks.
+// Each held lock occupies one pool connection, which is pinged every renew interval;
+// the lock is reported lost when the connection breaks, as the session and its locks are gone.
+// Fencing tokens are transaction IDs, which increase monotonically across the cluster.
+//
+// Example:
+//
+//	locker := lock.NewPostgres(pg)
+//	l, err := lock.Acquire(ctx, locker, "migrations", time.Second)
+func NewPostgres(pg *postgres.Postgres, opts ...Option) Locker {
+	return &postgresLocker{pg: pg, cfg: newConfig(opts)}
+}
+
+func (l *postgresLocker) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	lockKey := l.cfg.prefix + key
+
+	conn, err := l.pg.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lock - Postgres - TryAcquire - l.pg.Pool.Acquire: %w", err)
+	}
+
+	var (
+		acquired bool
+		token    int64
+	)
+
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockKey).Scan(&acquired)
+	if err != nil {
+		conn.Release()
+
+		return nil, fmt.Errorf("lock - Postgres - TryAcquire - pg_try_advisory_lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Release()
+
+		return nil, ErrNotAcquired
+	}
+
+	err = conn.QueryRow(ctx, "SELECT txid_current()").Scan(&token)
+	if err != nil {
+		// Closing the session releases the advisory lock.
+		_ = conn.Conn().Close(ctx)
+		conn.Release()
+
+		return nil, fmt.Errorf("lock - Postgres - TryAcquire - txid_current: %w", err)
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		if err := conn.Ping(ctx); err != nil {
+			return false, nil //nolint:nilerr // a broken session has lost its locks
+		}
+
+		return true, nil
+	}
+
+	release := func(ctx context.Context) (bool, error) {
+		defer conn.Release()
+
+		var held bool
+
+		err := conn.QueryRow(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockKey).Scan(&held)
+		if err != nil {
+			_ = conn.Conn().Close(ctx)
+
+			return false, err
+		}
+
+		return held, nil
+	}
+
+	return newLease(key, token, l.cfg.renewInterval, l.cfg.ttl, renew, release), nil
+}
@@ -0,0 +1,72 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// acquireScript sets the lock key if absent and returns the next fencing token,
+// or 0 when the lock is held.
+var acquireScript = goredis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// renewScript extends the lock key expiry only if it still holds the owner token.
+var renewScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+type redisLocker struct {
+	r   *redis.Redis
+	cfg config
+}
+
+// NewRedis returns a Locker backed by Redis keys expiring after the configured TTL
+// and renewed while held. Fencing tokens come from a counter stored next to the lock key.
+// Both keys share a hash tag, so the locker works with Redis Cluster.
+//
+// Example:
+//
+//	locker := lock.NewRedis(r, lock.TTL(10*time.Second))
+//	l, err := locker.TryAcquire(ctx, "reports")
+func NewRedis(r *redis.Redis, opts ...Option) Locker {
+	return &redisLocker{r: r, cfg: newConfig(opts)}
+}
+
+func (l *redisLocker) TryAcquire(ctx context.Context, key string) (Lock, error) {
+	lockKey := l.cfg.prefix + "{" + key + "}"
+	owner := uuid.NewString()
+	ttl := l.cfg.ttl.Milliseconds()
+	client := l.r.Client()
+
+	token, err := acquireScript.Run(ctx, client, []string{lockKey, lockKey + ":fence"}, owner, ttl).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("lock - Redis - TryAcquire - acquireScript.Run: %w", err)
+	}
+
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		n, err := renewScript.Run(ctx, client, []string{lockKey}, owner, ttl).Int()
+
+		return n == 1, err
+	}
+
+	release := func(ctx context.Context) (bool, error) {
+		return l.r.Unlock(ctx, lockKey, owner)
+	}
+
+	return newLease(key, token, l.cfg.renewInterval, l.cfg.ttl, renew, release), nil
+}
@@ -0,0 +1,74 @@
+package mailer_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/mailer"
+)
+
+// Example demonstrates sending a templated email with an attachment
+func Example() {
+	tmpl := template.Must(template.New("receipt").Parse(`<h1>Thanks, {{.Name}}!</h1>`))
+
+	m := mailer.New(
+		mailer.NewSMTP("smtp.example.com", 587, "user", "password"),
+		mailer.From("Shop <no-reply@example.com>"),
+		mailer.HTMLTemplates(tmpl),
+		mailer.Retries(3, time.Second),
+	)
+
+	msg := &mailer.Message{
+		To:      []string{"customer@example.com"},
+		Subject: "Your receipt",
+	}
+	msg.Attach("receipt.pdf", "application/pdf", []byte("%PDF-1.4"))
+
+	err := m.SendTemplate(context.Background(), msg, "receipt", map[string]string{"Name": "Ann"})
+	if err != nil {
+		fmt.Printf("SMTP server not available for example: %v\n", err)
+		return
+	}
+}
+
+// ExampleMailer_Enqueue demonstrates asynchronous delivery with error notification
+func ExampleMailer_Enqueue() {
+	m := mailer.New(
+		mailer.NewSMTP("smtp.example.com", 465, "user", "password", mailer.ImplicitTLS(true)),
+		mailer.From("no-reply@example.com"),
+		mailer.Workers(4),
+	)
+	m.Start()
+
+	go func() {
+		for err := range m.Notify() {
+			fmt.Printf("Email delivery failed: %v\n", err)
+		}
+	}()
+
+	_ = m.Enqueue(&mailer.Message{
+		To:      []string{"user@example.com"},
+		Subject: "Password reset",
+		Text:    "Use the link to reset your password.",
+	})
+
+	_ = m.Shutdown()
+}
+
+// ExampleProviderFunc demonstrates plugging an HTTP API based provider
+func ExampleProviderFunc() {
+	api := mailer.ProviderFunc(func(_ context.Context, msg *mailer.Message) error {
+		raw, err := msg.MIME()
+		if err != nil {
+			return err
+		}
+
+		_ = raw // POST the raw message to the provider API
+		return nil
+	})
+
+	m := mailer.New(api, mailer.From("no-reply@example.com"))
+	_ = m.Send(context.Background(), &mailer.Message{To: []string{"user@example.com"}, Text: "Hi"})
+}
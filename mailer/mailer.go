@@ -0,0 +1,374 @@
+// Package mailer provides transactional email sending with pluggable providers,
+// HTML templates, attachments, retry with exponential backoff and asynchronous
+// delivery reporting errors through a Notify channel.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_defaultAttempts        = 3
+	_defaultBackoff         = time.Second
+	_defaultMaxBackoff      = 30 * time.Second
+	_defaultQueueSize       = 100
+	_defaultWorkers         = 1
+	_defaultShutdownTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNoRecipients is returned when a message has no To, Cc or Bcc addresses.
+	ErrNoRecipients = errors.New("mailer - message has no recipients")
+	// ErrNoSender is returned when neither the message nor the mailer define a From address.
+	ErrNoSender = errors.New("mailer - message has no sender")
+	// ErrQueueFull is returned by Enqueue when the async queue is at capacity.
+	ErrQueueFull = errors.New("mailer - queue is full")
+	// ErrClosed is returned by Enqueue after Shutdown has been called.
+	ErrClosed = errors.New("mailer - mailer is closed")
+	// ErrNoTemplates is returned by Render and SendTemplate when no templates are configured.
+	ErrNoTemplates = errors.New("mailer - no templates configured")
+)
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+	// Inline marks the attachment as inline content referenced from HTML via "cid:<Filename>".
+	Inline bool
+}
+
+// Message represents an email message.
+// At least one of Text or HTML should be set.
+type Message struct {
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string
+	Attachments []Attachment
+}
+
+// Recipients returns all envelope recipients of the message.
+func (m *Message) Recipients() []string {
+	rcpt := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	rcpt = append(rcpt, m.To...)
+	rcpt = append(rcpt, m.Cc...)
+	rcpt = append(rcpt, m.Bcc...)
+
+	return rcpt
+}
+
+// Attach adds an attachment to the message.
+func (m *Message) Attach(filename, contentType string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	})
+}
+
+// Provider delivers a fully composed message.
+// Implementations exist for SMTP; API based providers (SendGrid, SES, Mailgun...)
+// can be plugged in by implementing this interface.
+type Provider interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(ctx context.Context, msg *Message) error
+
+// Send calls f(ctx, msg).
+func (f ProviderFunc) Send(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// PermanentError marks a provider error that must not be retried,
+// e.g. a rejected recipient or an authentication failure.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Mailer sends messages through a Provider with retries.
+// Messages may be sent synchronously with Send or queued with Enqueue,
+// in which case delivery failures are reported on Notify.
+type Mailer struct {
+	provider      Provider
+	htmlTemplates *htmltemplate.Template
+	textTemplates *texttemplate.Template
+	from          string
+
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	queue           chan *Message
+	queueSize       int
+	workers         int
+	shutdownTimeout time.Duration
+	notify          chan error
+	stop            chan struct{}
+	wg              sync.WaitGroup
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+}
+
+// New creates a new Mailer delivering messages through the given provider.
+// Default configuration: 3 attempts with exponential backoff starting at 1 second
+// (capped at 30 seconds), async queue of 100 messages processed by 1 worker.
+//
+// Example:
+//
+//	m := mailer.New(mailer.NewSMTP("smtp.example.com", 587, "user", "pass"),
+//	    mailer.From("no-reply@example.com"),
+//	    mailer.Retries(5, 2*time.Second),
+//	)
+func New(provider Provider, opts ...Option) *Mailer {
+	m := &Mailer{
+		provider:        provider,
+		attempts:        _defaultAttempts,
+		backoff:         _defaultBackoff,
+		maxBackoff:      _defaultMaxBackoff,
+		queueSize:       _defaultQueueSize,
+		workers:         _defaultWorkers,
+		shutdownTimeout: _defaultShutdownTimeout,
+		stop:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.queue = make(chan *Message, m.queueSize)
+	m.notify = make(chan error, m.queueSize)
+
+	return m
+}
+
+// Send delivers the message synchronously, retrying transient failures with
+// exponential backoff until the configured attempts are exhausted or ctx is done.
+func (m *Mailer) Send(ctx context.Context, msg *Message) error {
+	if err := m.prepare(msg); err != nil {
+		return err
+	}
+
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		return m.provider.Send(ctx, msg)
+	},
+		retry.Attempts(m.attempts),
+		retry.ExponentialBackoff(m.backoff, m.maxBackoff),
+		retry.RetryIf(func(err error) bool {
+			var permanent *PermanentError
+
+			return !errors.As(err, &permanent)
+		}),
+		retry.OnRetry(func(attempt int, _ error, _ time.Duration) {
+			log.Printf("Mailer failed to send message, attempts left: %d", m.attempts-attempt)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("mailer - Send - m.provider.Send: %w", err)
+	}
+
+	return nil
+}
+
+// Render executes the templates named name with data and stores the results in msg.
+// The HTML template output is stored in msg.HTML and the text template output in msg.Text;
+// at least one of them must define the template.
+func (m *Mailer) Render(msg *Message, name string, data interface{}) error {
+	if m.htmlTemplates == nil && m.textTemplates == nil {
+		return ErrNoTemplates
+	}
+
+	var (
+		buf   bytes.Buffer
+		found bool
+	)
+
+	if m.htmlTemplates != nil && m.htmlTemplates.Lookup(name) != nil {
+		if err := m.htmlTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("mailer - Render - html ExecuteTemplate: %w", err)
+		}
+
+		msg.HTML = buf.String()
+		found = true
+	}
+
+	if m.textTemplates != nil && m.textTemplates.Lookup(name) != nil {
+		buf.Reset()
+		if err := m.textTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("mailer - Render - text ExecuteTemplate: %w", err)
+		}
+
+		msg.Text = buf.String()
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("mailer - Render - template %q is not defined", name)
+	}
+
+	return nil
+}
+
+// SendTemplate renders the named template into msg and sends it synchronously.
+func (m *Mailer) SendTemplate(ctx context.Context, msg *Message, name string, data interface{}) error {
+	if err := m.Render(msg, name, data); err != nil {
+		return err
+	}
+
+	return m.Send(ctx, msg)
+}
+
+// Start launches the workers delivering queued messages.
+// Use Notify() to receive delivery errors.
+func (m *Mailer) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started || m.closed {
+		return
+	}
+
+	m.started = true
+
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+
+		go m.worker()
+	}
+}
+
+// Enqueue queues the message for asynchronous delivery by the workers started with Start.
+// It returns ErrQueueFull if the queue is at capacity and ErrClosed after Shutdown.
+func (m *Mailer) Enqueue(msg *Message) error {
+	if err := m.prepare(msg); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (m *Mailer) worker() {
+	defer m.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-m.stop
+		cancel()
+	}()
+
+	for msg := range m.queue {
+		if err := m.Send(ctx, msg); err != nil {
+			select {
+			case m.notify <- fmt.Errorf("mailer - worker - send to %v: %w", msg.To, err):
+			default:
+			}
+		}
+	}
+}
+
+// Notify returns a channel that receives asynchronous delivery errors.
+// The channel is closed after Shutdown completes.
+func (m *Mailer) Notify() <-chan error {
+	return m.notify
+}
+
+// Shutdown stops accepting new messages and waits up to the configured shutdown
+// timeout for queued messages to be delivered. Messages still pending after the
+// timeout are abandoned and in-flight retries are cancelled.
+func (m *Mailer) Shutdown() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+
+		return nil
+	}
+
+	m.closed = true
+	close(m.queue)
+	started := m.started
+	m.mu.Unlock()
+
+	if !started {
+		close(m.notify)
+
+		return nil
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-time.After(m.shutdownTimeout):
+		pending := len(m.queue)
+
+		close(m.stop)
+		<-done
+
+		err = fmt.Errorf("mailer - Shutdown - timed out with %d messages pending", pending)
+	}
+
+	close(m.notify)
+
+	return err
+}
+
+func (m *Mailer) prepare(msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+
+	if msg.From == "" {
+		return ErrNoSender
+	}
+
+	if len(msg.To)+len(msg.Cc)+len(msg.Bcc) == 0 {
+		return ErrNoRecipients
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package mailer_test
+
+import (
+	"context"
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/mailer"
+)
+
+type fakeProvider struct {
+	mu    sync.Mutex
+	calls int
+	sent  []*mailer.Message
+	errs  []error
+}
+
+func (p *fakeProvider) Send(_ context.Context, msg *mailer.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+
+		if err != nil {
+			return err
+		}
+	}
+
+	p.sent = append(p.sent, msg)
+
+	return nil
+}
+
+func (p *fakeProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+func TestMailer_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success on first attempt", nil, 3, 1, false},
+		{"success after transient failures", []error{errors.New("timeout"), errors.New("timeout")}, 3, 3, false},
+		{"attempts exhausted", []error{errors.New("a"), errors.New("b"), errors.New("c")}, 3, 3, true},
+		{"permanent error is not retried", []error{&mailer.PermanentError{Err: errors.New("550 no such user")}}, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fakeProvider{errs: tt.errs}
+			m := mailer.New(p,
+				mailer.From("no-reply@example.com"),
+				mailer.Retries(tt.attempts, time.Millisecond),
+			)
+
+			err := m.Send(context.Background(), &mailer.Message{To: []string{"user@example.com"}, Text: "hi"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got: %v", tt.wantErr, err)
+			}
+
+			if p.Calls() != tt.wantCalls {
+				t.Errorf("expected %d provider calls, got %d", tt.wantCalls, p.Calls())
+			}
+		})
+	}
+}
+
+func TestMailer_Send_Validation(t *testing.T) {
+	m := mailer.New(&fakeProvider{})
+
+	err := m.Send(context.Background(), &mailer.Message{To: []string{"user@example.com"}})
+	if !errors.Is(err, mailer.ErrNoSender) {
+		t.Errorf("expected ErrNoSender, got: %v", err)
+	}
+
+	err = m.Send(context.Background(), &mailer.Message{From: "a@example.com"})
+	if !errors.Is(err, mailer.ErrNoRecipients) {
+		t.Errorf("expected ErrNoRecipients, got: %v", err)
+	}
+}
+
+func TestMailer_Send_ContextCancelledDuringBackoff(t *testing.T) {
+	p := &fakeProvider{errs: []error{errors.New("a"), errors.New("b")}}
+	m := mailer.New(p, mailer.From("a@example.com"), mailer.Retries(3, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := m.Send(ctx, &mailer.Message{To: []string{"b@example.com"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestMailer_SendTemplate(t *testing.T) {
+	html := htmltemplate.Must(htmltemplate.New("welcome").Parse(`<p>Hello, {{.Name}}!</p>`))
+	text := texttemplate.Must(texttemplate.New("welcome").Parse(`Hello, {{.Name}}!`))
+
+	p := &fakeProvider{}
+	m := mailer.New(p,
+		mailer.From("no-reply@example.com"),
+		mailer.HTMLTemplates(html),
+		mailer.TextTemplates(text),
+	)
+
+	msg := &mailer.Message{To: []string{"user@example.com"}, Subject: "Welcome"}
+
+	err := m.SendTemplate(context.Background(), msg, "welcome", map[string]string{"Name": "<Ann>"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if msg.HTML != "<p>Hello, &lt;Ann&gt;!</p>" {
+		t.Errorf("unexpected HTML: %q", msg.HTML)
+	}
+
+	if msg.Text != "Hello, <Ann>!" {
+		t.Errorf("unexpected text: %q", msg.Text)
+	}
+
+	if err := m.Render(msg, "missing", nil); err == nil {
+		t.Error("expected error for undefined template")
+	}
+
+	if err := mailer.New(p).Render(msg, "welcome", nil); !errors.Is(err, mailer.ErrNoTemplates) {
+		t.Errorf("expected ErrNoTemplates, got: %v", err)
+	}
+}
+
+func TestMailer_Enqueue(t *testing.T) {
+	p := &fakeProvider{errs: []error{&mailer.PermanentError{Err: errors.New("rejected")}}}
+	m := mailer.New(p, mailer.From("a@example.com"), mailer.Workers(2))
+	m.Start()
+
+	if err := m.Enqueue(&mailer.Message{To: []string{"bad@example.com"}}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	select {
+	case err := <-m.Notify():
+		if err == nil || !strings.Contains(err.Error(), "rejected") {
+			t.Errorf("expected delivery error, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected delivery error on Notify")
+	}
+
+	if err := m.Enqueue(&mailer.Message{To: []string{"ok@example.com"}}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("expected no error on shutdown, got: %v", err)
+	}
+
+	if len(p.sent) != 1 {
+		t.Errorf("expected queued message to be delivered before shutdown, got %d", len(p.sent))
+	}
+
+	if err := m.Enqueue(&mailer.Message{To: []string{"late@example.com"}}); !errors.Is(err, mailer.ErrClosed) {
+		t.Errorf("expected ErrClosed, got: %v", err)
+	}
+
+	if _, ok := <-m.Notify(); ok {
+		t.Error("expected Notify channel to be closed after shutdown")
+	}
+}
+
+func TestMailer_Enqueue_QueueFull(t *testing.T) {
+	m := mailer.New(&fakeProvider{}, mailer.From("a@example.com"), mailer.QueueSize(1))
+
+	if err := m.Enqueue(&mailer.Message{To: []string{"a@example.com"}}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if err := m.Enqueue(&mailer.Message{To: []string{"b@example.com"}}); !errors.Is(err, mailer.ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got: %v", err)
+	}
+}
+
+func TestMailer_Shutdown_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	p := mailer.ProviderFunc(func(ctx context.Context, _ *mailer.Message) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-block:
+			return nil
+		}
+	})
+
+	m := mailer.New(p, mailer.From("a@example.com"), mailer.ShutdownTimeout(20*time.Millisecond))
+	m.Start()
+
+	for i := 0; i < 3; i++ {
+		if err := m.Enqueue(&mailer.Message{To: []string{"a@example.com"}}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	if err := m.Shutdown(); err == nil {
+		t.Error("expected shutdown timeout error")
+	}
+}
+
+func TestMessage_MIME(t *testing.T) {
+	msg := &mailer.Message{
+		From:    "Sender <sender@example.com>",
+		To:      []string{"to@example.com"},
+		Cc:      []string{"cc@example.com"},
+		Bcc:     []string{"hidden@example.com"},
+		Subject: "Invoice №42",
+		Text:    "See attached.",
+		HTML:    "<p>See attached.</p>",
+		Headers: map[string]string{"X-Campaign": "billing"},
+	}
+	msg.Attach("invoice.pdf", "application/pdf", []byte("%PDF-1.4"))
+
+	raw, err := msg.MIME()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("failed to parse composed message: %v", err)
+	}
+
+	if parsed.Header.Get("Bcc") != "" || strings.Contains(string(raw), "hidden@example.com") {
+		t.Error("expected Bcc recipients to be omitted from headers")
+	}
+
+	if parsed.Header.Get("X-Campaign") != "billing" {
+		t.Errorf("expected custom header, got %q", parsed.Header.Get("X-Campaign"))
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != "Invoice №42" {
+		t.Errorf("expected decoded subject, got %q (%v)", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("expected multipart/mixed, got %q (%v)", mediaType, err)
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	var types []string
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+
+		mt, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		types = append(types, mt)
+	}
+
+	if len(types) != 2 || types[0] != "multipart/alternative" || types[1] != "application/pdf" {
+		t.Errorf("unexpected parts: %v", types)
+	}
+}
+
+func TestMessage_MIME_TextOnly(t *testing.T) {
+	msg := &mailer.Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "plain body"}
+
+	raw, err := msg.MIME()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("failed to parse composed message: %v", err)
+	}
+
+	if !strings.HasPrefix(parsed.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("expected text/plain, got %q", parsed.Header.Get("Content-Type"))
+	}
+
+	if _, err := (&mailer.Message{From: "not an address"}).MIME(); err == nil {
+		t.Error("expected error for invalid From address")
+	}
+}
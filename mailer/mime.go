@@ -0,0 +1,245 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+const base64LineLength = 76
+
+// MIME composes the message into an RFC 5322 document suitable for SMTP DATA
+// or for API providers accepting raw messages. Bcc recipients are not included.
+func (m *Message) MIME() ([]byte, error) {
+	var buf bytes.Buffer
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("mailer - Message - MIME - invalid From: %w", err)
+	}
+
+	writeHeader(&buf, "From", from.String())
+
+	if len(m.To) > 0 {
+		writeHeader(&buf, "To", strings.Join(m.To, ", "))
+	}
+
+	if len(m.Cc) > 0 {
+		writeHeader(&buf, "Cc", strings.Join(m.Cc, ", "))
+	}
+
+	if m.ReplyTo != "" {
+		writeHeader(&buf, "Reply-To", m.ReplyTo)
+	}
+
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(from.Address))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		writeHeader(&buf, k, m.Headers[k])
+	}
+
+	if len(m.Attachments) == 0 {
+		if err := writeBody(&buf, m); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+
+	writeHeader(&buf, "Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	buf.WriteString("\r\n")
+
+	var body bytes.Buffer
+	if err := writeBody(&body, m); err != nil {
+		return nil, err
+	}
+
+	header, content, _ := strings.Cut(body.String(), "\r\n\r\n")
+
+	part, err := mixed.CreatePart(parseHeader(header))
+	if err != nil {
+		return nil, fmt.Errorf("mailer - Message - MIME - CreatePart: %w", err)
+	}
+
+	if _, err := part.Write([]byte(content)); err != nil {
+		return nil, fmt.Errorf("mailer - Message - MIME - Write: %w", err)
+	}
+
+	for _, a := range m.Attachments {
+		if err := writeAttachment(mixed, a); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, fmt.Errorf("mailer - Message - MIME - Close: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBody writes the content headers, a blank line and the text/HTML content.
+func writeBody(buf *bytes.Buffer, m *Message) error {
+	switch {
+	case m.Text != "" && m.HTML != "":
+		alt := multipart.NewWriter(buf)
+
+		writeHeader(buf, "Content-Type", "multipart/alternative; boundary="+alt.Boundary())
+		buf.WriteString("\r\n")
+
+		if err := writeTextPart(alt, "text/plain", m.Text); err != nil {
+			return err
+		}
+
+		if err := writeTextPart(alt, "text/html", m.HTML); err != nil {
+			return err
+		}
+
+		return alt.Close()
+	case m.HTML != "":
+		return writeSinglePart(buf, "text/html", m.HTML)
+	default:
+		return writeSinglePart(buf, "text/plain", m.Text)
+	}
+}
+
+func writeSinglePart(buf *bytes.Buffer, contentType, content string) error {
+	writeHeader(buf, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(buf, "Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	return writeQuotedPrintable(buf, content)
+}
+
+func writeTextPart(w *multipart.Writer, contentType, content string) error {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("mailer - writeTextPart - CreatePart: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeQuotedPrintable(&buf, content); err != nil {
+		return err
+	}
+
+	_, err = part.Write(buf.Bytes())
+
+	return err
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, content string) error {
+	qp := quotedprintable.NewWriter(buf)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return fmt.Errorf("mailer - writeQuotedPrintable - Write: %w", err)
+	}
+
+	return qp.Close()
+}
+
+func writeAttachment(w *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(fileExt(a.Filename))
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if a.Inline {
+		disposition = "inline"
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": a.Filename}))
+	h.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	h.Set("Content-Transfer-Encoding", "base64")
+
+	if a.Inline {
+		h.Set("Content-ID", "<"+a.Filename+">")
+	}
+
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("mailer - writeAttachment - CreatePart: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > base64LineLength {
+		if _, err := part.Write([]byte(encoded[:base64LineLength] + "\r\n")); err != nil {
+			return err
+		}
+
+		encoded = encoded[base64LineLength:]
+	}
+
+	_, err = part.Write([]byte(encoded + "\r\n"))
+
+	return err
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+func parseHeader(raw string) textproto.MIMEHeader {
+	h := textproto.MIMEHeader{}
+
+	for _, line := range strings.Split(raw, "\r\n") {
+		if k, v, ok := strings.Cut(line, ": "); ok {
+			h.Set(k, v)
+		}
+	}
+
+	return h
+}
+
+func fileExt(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i:]
+	}
+
+	return ""
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = d
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+// Option is a function that configures a Mailer.
+// Options are applied in the order they are passed to New.
+type Option func(*Mailer)
+
+// From sets the default sender used when a message has no From address.
+func From(address string) Option {
+	return func(m *Mailer) {
+		m.from = address
+	}
+}
+
+// HTMLTemplates sets the templates rendered into Message.HTML by Render and SendTemplate.
+//
+// Example:
+//
+//	t := template.Must(template.ParseFS(templatesFS, "emails/*.html"))
+//	m := mailer.New(provider, mailer.HTMLTemplates(t))
+func HTMLTemplates(t *htmltemplate.Template) Option {
+	return func(m *Mailer) {
+		m.htmlTemplates = t
+	}
+}
+
+// TextTemplates sets the templates rendered into Message.Text by Render and SendTemplate.
+// A text template is used when it has the same name as the requested template.
+func TextTemplates(t *texttemplate.Template) Option {
+	return func(m *Mailer) {
+		m.textTemplates = t
+	}
+}
+
+// Retries sets the maximum number of delivery attempts and the initial backoff.
+// The backoff doubles after every failed attempt, up to MaxBackoff.
+// Default is 3 attempts with 1 second initial backoff.
+func Retries(attempts int, backoff time.Duration) Option {
+	return func(m *Mailer) {
+		m.attempts = attempts
+		m.backoff = backoff
+	}
+}
+
+// MaxBackoff caps the wait time between delivery attempts.
+// Default is 30 seconds.
+func MaxBackoff(d time.Duration) Option {
+	return func(m *Mailer) {
+		m.maxBackoff = d
+	}
+}
+
+// QueueSize sets the capacity of the async delivery queue.
+// Default is 100.
+func QueueSize(size int) Option {
+	return func(m *Mailer) {
+		m.queueSize = size
+	}
+}
+
+// Workers sets the number of goroutines delivering queued messages.
+// Default is 1.
+func Workers(n int) Option {
+	return func(m *Mailer) {
+		m.workers = n
+	}
+}
+
+// ShutdownTimeout sets the maximum duration Shutdown waits for the queue to drain.
+// Default is 10 seconds.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(m *Mailer) {
+		m.shutdownTimeout = timeout
+	}
+}
@@ -0,0 +1,213 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+const _defaultDialTimeout = 10 * time.Second
+
+// SMTP is a Provider delivering messages through an SMTP server.
+// STARTTLS is used when the server advertises it, unless implicit TLS is enabled.
+type SMTP struct {
+	host     string
+	port     int
+	username string
+	password string
+
+	implicitTLS bool
+	tlsConfig   *tls.Config
+	localName   string
+	dialTimeout time.Duration
+}
+
+var _ Provider = (*SMTP)(nil)
+
+// SMTPOption is a function that configures an SMTP provider.
+type SMTPOption func(*SMTP)
+
+// ImplicitTLS enables TLS from the start of the connection (SMTPS, usually port 465)
+// instead of upgrading with STARTTLS.
+func ImplicitTLS(enabled bool) SMTPOption {
+	return func(s *SMTP) {
+		s.implicitTLS = enabled
+	}
+}
+
+// TLSConfig sets the TLS configuration used for implicit TLS and STARTTLS.
+func TLSConfig(cfg *tls.Config) SMTPOption {
+	return func(s *SMTP) {
+		s.tlsConfig = cfg
+	}
+}
+
+// LocalName sets the host name sent in the HELO/EHLO greeting.
+func LocalName(name string) SMTPOption {
+	return func(s *SMTP) {
+		s.localName = name
+	}
+}
+
+// DialTimeout sets the timeout for establishing the TCP connection.
+// Default is 10 seconds.
+func DialTimeout(timeout time.Duration) SMTPOption {
+	return func(s *SMTP) {
+		s.dialTimeout = timeout
+	}
+}
+
+// NewSMTP creates an SMTP provider. Authentication is skipped when username is empty.
+//
+// Example:
+//
+//	p := mailer.NewSMTP("smtp.example.com", 587, "user", "pass")
+//	m := mailer.New(p, mailer.From("no-reply@example.com"))
+func NewSMTP(host string, port int, username, password string, opts ...SMTPOption) *SMTP {
+	s := &SMTP{
+		host:        host,
+		port:        port,
+		username:    username,
+		password:    password,
+		dialTimeout: _defaultDialTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.tlsConfig == nil {
+		s.tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+
+	return s
+}
+
+// Send delivers the message. Server rejections (5xx replies) are returned as PermanentError.
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	body, err := msg.MIME()
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("invalid From: %w", err)}
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+
+		return classify(fmt.Errorf("smtp.NewClient: %w", err))
+	}
+	defer c.Close() //nolint:errcheck // connection is closed after Quit
+
+	if err := s.deliver(c, from.Address, msg.Recipients(), body); err != nil {
+		return classify(err)
+	}
+
+	return nil
+}
+
+func (s *SMTP) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := &net.Dialer{Timeout: s.dialTimeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if s.implicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	return conn, nil
+}
+
+func (s *SMTP) deliver(c *smtp.Client, from string, rcpt []string, body []byte) error {
+	if s.localName != "" {
+		if err := c.Hello(s.localName); err != nil {
+			return fmt.Errorf("c.Hello: %w", err)
+		}
+	}
+
+	if !s.implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(s.tlsConfig); err != nil {
+				return fmt.Errorf("c.StartTLS: %w", err)
+			}
+		}
+	}
+
+	if s.username != "" {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+				return fmt.Errorf("c.Auth: %w", err)
+			}
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("c.Mail: %w", err)
+	}
+
+	for _, addr := range rcpt {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return &PermanentError{Err: fmt.Errorf("invalid recipient %q: %w", addr, err)}
+		}
+
+		if err := c.Rcpt(parsed.Address); err != nil {
+			return fmt.Errorf("c.Rcpt: %w", err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("c.Data: %w", err)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("w.Write: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("w.Close: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// classify wraps permanent SMTP failures (5xx replies) in PermanentError.
+func classify(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return &PermanentError{Err: err}
+	}
+
+	return err
+}
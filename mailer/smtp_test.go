@@ -0,0 +1,189 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/mailer"
+)
+
+// fakeSMTPServer accepts a single session and records the envelope and data.
+type fakeSMTPServer struct {
+	ln       net.Listener
+	rejectTo string
+
+	mu   sync.Mutex
+	from string
+	rcpt []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &fakeSMTPServer{ln: ln}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			addr := strings.Trim(cmd[len("RCPT TO:"):], "<> ")
+			if addr == s.rejectTo {
+				reply("550 No such user")
+
+				continue
+			}
+
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, addr)
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+
+				data.WriteString(l)
+			}
+
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTP_Send(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	p := mailer.NewSMTP("127.0.0.1", srv.port(), "", "", mailer.LocalName("client.test"))
+
+	msg := &mailer.Message{
+		From:    "Sender <sender@example.com>",
+		To:      []string{"to@example.com"},
+		Bcc:     []string{"Hidden <hidden@example.com>"},
+		Subject: "Hello",
+		Text:    "Body text",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.Send(ctx, msg); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.from != "sender@example.com" {
+		t.Errorf("expected envelope sender, got %q", srv.from)
+	}
+
+	if len(srv.rcpt) != 2 || srv.rcpt[1] != "hidden@example.com" {
+		t.Errorf("expected To and Bcc envelope recipients, got %v", srv.rcpt)
+	}
+
+	if !strings.Contains(srv.data, "Subject: Hello") || !strings.Contains(srv.data, "Body text") {
+		t.Errorf("unexpected message data: %q", srv.data)
+	}
+}
+
+func TestSMTP_Send_RejectedRecipientIsPermanent(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	srv.rejectTo = "nobody@example.com"
+
+	p := mailer.NewSMTP("127.0.0.1", srv.port(), "", "")
+
+	err := p.Send(context.Background(), &mailer.Message{
+		From: "sender@example.com",
+		To:   []string{"nobody@example.com"},
+		Text: "hi",
+	})
+
+	var permanent *mailer.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Errorf("expected PermanentError, got: %v", err)
+	}
+}
+
+func TestSMTP_Send_NoConnection(t *testing.T) {
+	p := mailer.NewSMTP("127.0.0.1", 1, "", "", mailer.DialTimeout(100*time.Millisecond))
+
+	err := p.Send(context.Background(), &mailer.Message{
+		From: "sender@example.com",
+		To:   []string{"to@example.com"},
+		Text: "hi",
+	})
+	if err == nil {
+		t.Skip("unexpected SMTP server listening on port 1")
+	}
+
+	var permanent *mailer.PermanentError
+	if errors.As(err, &permanent) {
+		t.Error("expected connection failure to be retryable")
+	}
+}
@@ -0,0 +1,29 @@
+package memcache_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcache"
+)
+
+func ExampleNew() {
+	sessions := memcache.New[string, int](
+		memcache.MaxEntries(2),
+		memcache.TTL(time.Hour),
+	)
+	defer sessions.Close()
+
+	sessions.Set("alice", 1)
+	sessions.Set("bob", 2)
+	sessions.Get("alice")
+	sessions.Set("carol", 3) // evicts bob, the least recently used
+
+	_, ok := sessions.Get("bob")
+	fmt.Println("bob cached:", ok)
+	fmt.Printf("%+v\n", sessions.Stats())
+
+	// Output:
+	// bob cached: false
+	// {Hits:1 Misses:1 Evictions:1 Expirations:0}
+}
@@ -0,0 +1,316 @@
+// Package memcache provides a dependency-free, in-process cache with typed keys and
+// values, a maximum number of entries evicted in least recently used order, per-entry
+// TTLs, a janitor goroutine removing expired entries and hit/miss statistics.
+// It serves as the local layer of the cache package.
+package memcache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	_defaultMaxEntries      = 10_000
+	_defaultCleanupInterval = time.Minute
+)
+
+// EvictionReason tells why an entry left the cache.
+type EvictionReason int
+
+// Eviction reasons.
+const (
+	// Evicted entries were the least recently used when the cache was full.
+	Evicted EvictionReason = iota
+	// Expired entries outlived their TTL.
+	Expired
+)
+
+// String returns the reason name.
+func (r EvictionReason) String() string {
+	if r == Expired {
+		return "expired"
+	}
+
+	return "evicted"
+}
+
+// Metrics receives cache events, e.g. to export them as Prometheus counters.
+// Methods are called with the cache lock held and must be fast.
+type Metrics interface {
+	Hit()
+	Miss()
+	Eviction(reason EvictionReason)
+}
+
+// Stats are counters accumulated since the cache creation.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// HitRatio returns the share of lookups that were hits, or 0 without lookups.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+type item[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+func (it *item[K, V]) expired(now time.Time) bool {
+	return !it.expiresAt.IsZero() && !now.Before(it.expiresAt)
+}
+
+// Cache is an in-process cache of values of type V by keys of type K.
+// It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	cfg config
+
+	mu    sync.Mutex
+	items map[K]*list.Element
+	lru   *list.List
+
+	hits, misses, evictions, expirations atomic.Uint64
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// New creates a new Cache and starts its janitor. Call Close to stop the janitor.
+// Default configuration: 10000 entries, no TTL, expired entries removed every minute.
+//
+// Example:
+//
+//	users := memcache.New[string, User](
+//	    memcache.MaxEntries(50_000),
+//	    memcache.TTL(time.Minute),
+//	)
+//	defer users.Close()
+//
+//	users.Set(id, u)
+//	u, ok := users.Get(id)
+func New[K comparable, V any](opts ...Option) *Cache[K, V] {
+	c := &Cache[K, V]{
+		cfg:   newConfig(opts),
+		items: make(map[K]*list.Element),
+		lru:   list.New(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	if c.cfg.cleanupInterval > 0 {
+		go c.janitor()
+	} else {
+		close(c.done)
+	}
+
+	return c
+}
+
+// Get returns the value stored under key and marks it as recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.miss()
+
+		var zero V
+
+		return zero, false
+	}
+
+	it := el.Value.(*item[K, V])
+	if it.expired(time.Now()) {
+		c.remove(el, Expired)
+		c.miss()
+
+		var zero V
+
+		return zero, false
+	}
+
+	c.lru.MoveToFront(el)
+	c.hits.Add(1)
+
+	if c.cfg.metrics != nil {
+		c.cfg.metrics.Hit()
+	}
+
+	return it.value, true
+}
+
+// Peek returns the value stored under key without marking it as recently used or
+// counting a hit or miss.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		if it := el.Value.(*item[K, V]); !it.expired(time.Now()) {
+			return it.value, true
+		}
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// Set stores value under key with the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.cfg.ttl)
+}
+
+// SetWithTTL stores value under key for ttl. A ttl of 0 or less never expires.
+// The least recently used entries are evicted when the cache is full.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	it := &item[K, V]{key: key, value: value}
+	if ttl > 0 {
+		it.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value = it
+		c.lru.MoveToFront(el)
+
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(it)
+
+	for c.cfg.maxEntries > 0 && c.lru.Len() > c.cfg.maxEntries {
+		c.remove(c.lru.Back(), Evicted)
+	}
+}
+
+// Delete removes key and reports whether it was present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if ok {
+		c.lru.Remove(el)
+		delete(c.items, key)
+	}
+
+	return ok
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Clear removes all entries. Statistics are kept.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.items)
+	c.lru.Init()
+}
+
+// DeleteExpired removes expired entries and returns how many were removed.
+// The janitor calls it periodically.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+
+	for el := c.lru.Back(); el != nil; {
+		prev := el.Prev()
+
+		if el.Value.(*item[K, V]).expired(now) {
+			c.remove(el, Expired)
+			n++
+		}
+
+		el = prev
+	}
+
+	return n
+}
+
+// Stats returns the cache statistics.
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+	}
+}
+
+// Close stops the janitor. The cache remains usable; expired entries are then only
+// removed when looked up or evicted.
+func (c *Cache[K, V]) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+
+	<-c.done
+}
+
+func (c *Cache[K, V]) janitor() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.DeleteExpired()
+		}
+	}
+}
+
+// remove deletes el and records why. It must be called with c.mu held.
+func (c *Cache[K, V]) remove(el *list.Element, reason EvictionReason) {
+	c.lru.Remove(el)
+	delete(c.items, el.Value.(*item[K, V]).key)
+
+	if reason == Expired {
+		c.expirations.Add(1)
+	} else {
+		c.evictions.Add(1)
+	}
+
+	if c.cfg.metrics != nil {
+		c.cfg.metrics.Eviction(reason)
+	}
+}
+
+// miss records a miss. It must be called with c.mu held.
+func (c *Cache[K, V]) miss() {
+	c.misses.Add(1)
+
+	if c.cfg.metrics != nil {
+		c.cfg.metrics.Miss()
+	}
+}
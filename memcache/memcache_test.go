@@ -0,0 +1,207 @@
+package memcache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcache"
+)
+
+func TestCache_GetSetDelete(t *testing.T) {
+	c := memcache.New[string, int](memcache.CleanupInterval(0))
+	defer c.Close()
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get() on empty cache: want miss")
+	}
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Fatalf("Get() = %d, %v; want 2", v, ok)
+	}
+
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+
+	if !c.Delete("a") || c.Delete("a") {
+		t.Error("Delete() should report true then false")
+	}
+
+	c.Set("b", 1)
+	c.Clear()
+
+	if c.Len() != 0 {
+		t.Errorf("Len() after Clear() = %d, want 0", c.Len())
+	}
+}
+
+func TestCache_LRU(t *testing.T) {
+	c := memcache.New[int, string](memcache.MaxEntries(2), memcache.CleanupInterval(0))
+	defer c.Close()
+
+	c.Set(1, "a")
+	c.Set(2, "b")
+	c.Get(1)
+	// Peek does not protect 2 from eviction.
+	c.Peek(2)
+	c.Set(3, "c")
+
+	for key, want := range map[int]bool{1: true, 2: false, 3: true} {
+		if _, ok := c.Peek(key); ok != want {
+			t.Errorf("key %d present = %v, want %v", key, ok, want)
+		}
+	}
+
+	if stats := c.Stats(); stats.Evictions != 1 {
+		t.Errorf("Stats().Evictions = %d, want 1", stats.Evictions)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []memcache.Option
+		ttl     time.Duration
+		expired bool
+	}{
+		{name: "default TTL", opts: []memcache.Option{memcache.TTL(10 * time.Millisecond)}, ttl: -1, expired: true},
+		{name: "no TTL", ttl: -1},
+		{name: "entry TTL", ttl: 10 * time.Millisecond, expired: true},
+		{name: "entry TTL overrides default", opts: []memcache.Option{memcache.TTL(10 * time.Millisecond)}, ttl: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := memcache.New[string, int](append(tt.opts, memcache.CleanupInterval(0))...)
+			defer c.Close()
+
+			if tt.ttl < 0 {
+				c.Set("k", 1)
+			} else {
+				c.SetWithTTL("k", 1, tt.ttl)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			if _, ok := c.Get("k"); ok == tt.expired {
+				t.Errorf("Get() found = %v, want %v", ok, !tt.expired)
+			}
+
+			if tt.expired && c.Stats().Expirations != 1 {
+				t.Errorf("Stats().Expirations = %d, want 1", c.Stats().Expirations)
+			}
+		})
+	}
+}
+
+func TestCache_Janitor(t *testing.T) {
+	c := memcache.New[string, int](memcache.CleanupInterval(10 * time.Millisecond))
+	defer c.Close()
+
+	c.SetWithTTL("short", 1, 5*time.Millisecond)
+	c.Set("long", 2)
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Len() = %d, want the expired entry removed by the janitor", c.Len())
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	c.Close()
+	c.Close()
+}
+
+type countingMetrics struct {
+	mu                          sync.Mutex
+	hits, misses, evicted, exps int
+}
+
+func (m *countingMetrics) Hit()  { m.mu.Lock(); m.hits++; m.mu.Unlock() }
+func (m *countingMetrics) Miss() { m.mu.Lock(); m.misses++; m.mu.Unlock() }
+
+func (m *countingMetrics) Eviction(reason memcache.EvictionReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if reason == memcache.Expired {
+		m.exps++
+	} else {
+		m.evicted++
+	}
+}
+
+func TestCache_Metrics(t *testing.T) {
+	m := &countingMetrics{}
+	c := memcache.New[string, int](memcache.MaxEntries(1), memcache.WithMetrics(m), memcache.CleanupInterval(0))
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("b")
+	c.Set("b", 2)
+	c.SetWithTTL("c", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	c.Get("c")
+
+	if m.hits != 1 || m.misses != 2 || m.evicted != 2 || m.exps != 1 {
+		t.Errorf("metrics = %d hits, %d misses, %d evictions, %d expirations; want 1, 2, 2, 1",
+			m.hits, m.misses, m.evicted, m.exps)
+	}
+
+	stats := c.Stats()
+	if stats != (memcache.Stats{Hits: 1, Misses: 2, Evictions: 2, Expirations: 1}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	if ratio := stats.HitRatio(); ratio < 0.33 || ratio > 0.34 {
+		t.Errorf("HitRatio() = %v, want 1/3", ratio)
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	c := memcache.New[int, int](memcache.MaxEntries(100), memcache.CleanupInterval(time.Millisecond))
+	defer c.Close()
+
+	var wg sync.WaitGroup
+
+	for g := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 1000 {
+				c.SetWithTTL(g*1000+i, i, time.Millisecond)
+				c.Get(g*1000 + i/2)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if c.Len() > 100 {
+		t.Errorf("Len() = %d, want at most 100", c.Len())
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	c := memcache.New[int, int](memcache.CleanupInterval(0))
+	defer c.Close()
+
+	for i := range 1000 {
+		c.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Get(i % 1000)
+	}
+}
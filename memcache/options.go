@@ -0,0 +1,60 @@
+package memcache
+
+import "time"
+
+// Option configures a Cache.
+type Option func(*config)
+
+type config struct {
+	maxEntries      int
+	ttl             time.Duration
+	cleanupInterval time.Duration
+	metrics         Metrics
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		maxEntries:      _defaultMaxEntries,
+		cleanupInterval: _defaultCleanupInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// MaxEntries sets the number of entries above which the least recently used ones
+// are evicted. A value below 1 removes the limit.
+// Default is 10000.
+func MaxEntries(n int) Option {
+	return func(c *config) {
+		c.maxEntries = n
+	}
+}
+
+// TTL sets how long entries stored with Set live.
+// Default is 0: entries never expire.
+func TTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// CleanupInterval sets how often the janitor removes expired entries. A value of 0
+// or less disables the janitor.
+// Default is 1 minute.
+func CleanupInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.cleanupInterval = interval
+	}
+}
+
+// WithMetrics sets the metrics collector notified of hits, misses and evictions.
+// Default is nil: only Stats are kept.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
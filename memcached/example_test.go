@@ -0,0 +1,32 @@
+package memcached_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcached"
+)
+
+func ExampleNew() {
+	client, err := memcached.New([]string{"cache-1:11211", "cache-2:11211"},
+		memcached.TTL(5*time.Minute),
+	)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "user:1", "Alice"); err != nil {
+		return
+	}
+
+	value, err := client.Get(ctx, "user:1")
+	if err != nil {
+		return
+	}
+
+	fmt.Println(value)
+}
@@ -0,0 +1,147 @@
+// Package memcached provides Memcached client functionality with configurable TTL,
+// simplified key-value operations and consistent hashing across nodes,
+// using gomemcache.
+package memcached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+const (
+	defaultTTL = 2 * time.Minute
+	// maxRelativeTTL is the longest expiration memcached interprets as relative;
+	// longer ones must be sent as a Unix timestamp.
+	maxRelativeTTL = 30 * 24 * time.Hour
+)
+
+// Memcached represents a Memcached client with configurable default TTL.
+// Keys are distributed across servers with a consistent hash ring,
+// so adding or removing a node only remaps a small share of the keys.
+type Memcached struct {
+	client *memcache.Client
+	ttl    time.Duration
+
+	timeout      time.Duration
+	maxIdleConns int
+}
+
+// New creates a new Memcached client for the given servers ("host:port" or unix socket paths).
+// Default TTL is set to 2 minutes for all Set operations.
+//
+// Example:
+//
+//	client, err := memcached.New([]string{"cache-1:11211", "cache-2:11211"},
+//	    memcached.TTL(5 * time.Minute),
+//	)
+func New(servers []string, opts ...Options) (*Memcached, error) {
+	m := &Memcached{
+		ttl:          defaultTTL,
+		timeout:      memcache.DefaultTimeout,
+		maxIdleConns: memcache.DefaultMaxIdleConns,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	ring, err := newRing(servers...)
+	if err != nil {
+		return nil, fmt.Errorf("memcached - New - newRing: %w", err)
+	}
+
+	m.client = memcache.NewFromSelector(ring)
+	m.client.Timeout = m.timeout
+	m.client.MaxIdleConns = m.maxIdleConns
+
+	return m, nil
+}
+
+// Set stores a key-value pair with the default TTL.
+func (m *Memcached) Set(ctx context.Context, key string, value string) error {
+	return m.SetWithTTL(ctx, key, value, m.ttl)
+}
+
+// SetWithTTL stores a key-value pair with a custom TTL. A zero TTL never expires.
+// The context is not propagated: gomemcache bounds calls with the Timeout option.
+func (m *Memcached) SetWithTTL(_ context.Context, key string, value string, ttl time.Duration) error {
+	return m.client.Set(&memcache.Item{
+		Key:        key,
+		Value:      []byte(value),
+		Expiration: expiration(ttl),
+	})
+}
+
+// Get retrieves the value for the given key.
+// Returns empty string and nil error if key doesn't exist.
+func (m *Memcached) Get(_ context.Context, key string) (string, error) {
+	item, err := m.client.Get(key)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return string(item.Value), nil
+}
+
+// Delete removes the key. Deleting a missing key is not an error.
+func (m *Memcached) Delete(_ context.Context, key string) error {
+	err := m.client.Delete(key)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil
+	}
+
+	return err
+}
+
+// Touch updates the TTL of an existing key.
+// Returns false and nil error if key doesn't exist.
+func (m *Memcached) Touch(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	err := m.client.Touch(key, expiration(ttl))
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Ping checks that every server is reachable.
+func (m *Memcached) Ping(_ context.Context) error {
+	return m.client.Ping()
+}
+
+// Close gracefully closes the idle connections to the servers.
+func (m *Memcached) Close() {
+	if m.client != nil {
+		err := m.client.Close()
+		if err != nil {
+			log.Printf("Error closing memcached client: %s", err)
+		}
+	}
+}
+
+// Client returns the underlying gomemcache client for commands not covered by Memcached.
+func (m *Memcached) Client() *memcache.Client {
+	return m.client
+}
+
+// expiration converts ttl to memcached seconds, rounding sub-second TTLs up
+// and sending TTLs longer than 30 days as an absolute Unix timestamp.
+func expiration(ttl time.Duration) int32 {
+	switch {
+	case ttl <= 0:
+		return 0
+	case ttl > maxRelativeTTL:
+		return int32(time.Now().Add(ttl).Unix()) // #nosec G115 -- valid until 2038, as in the memcached protocol
+	default:
+		return int32((ttl + time.Second - 1) / time.Second)
+	}
+}
@@ -0,0 +1,62 @@
+package memcached_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/memcached"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []string
+		opts    []memcached.Options
+		wantErr bool
+	}{
+		{name: "single server", servers: []string{"localhost:11211"}},
+		{name: "multiple servers", servers: []string{"127.0.0.1:11211", "127.0.0.1:11212"}},
+		{name: "with options", servers: []string{"localhost:11211"}, opts: []memcached.Options{
+			memcached.TTL(5 * time.Minute),
+			memcached.Timeout(time.Second),
+			memcached.MaxIdleConns(10),
+		}},
+		{name: "no servers", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := memcached.New(tt.servers, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if client != nil {
+				client.Close()
+			}
+		})
+	}
+}
+
+func TestConnectionFailure(t *testing.T) {
+	client, err := memcached.New([]string{"127.0.0.1:65432"}, memcached.Timeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to Memcached")
+	}
+
+	if _, err := client.Get(ctx, "key"); err == nil {
+		t.Error("expected error from Get() without server")
+	}
+
+	if err := client.Ping(ctx); err == nil {
+		t.Error("expected error from Ping() without server")
+	}
+}
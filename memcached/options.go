@@ -0,0 +1,29 @@
+package memcached
+
+import "time"
+
+// Options defines a function type for configuring Memcached instances.
+type Options func(*Memcached)
+
+// TTL sets the default TTL (time-to-live) for Set operations.
+func TTL(ttl time.Duration) Options {
+	return func(m *Memcached) {
+		m.ttl = ttl
+	}
+}
+
+// Timeout sets the socket read/write timeout of every operation.
+// Default is 500ms.
+func Timeout(timeout time.Duration) Options {
+	return func(m *Memcached) {
+		m.timeout = timeout
+	}
+}
+
+// MaxIdleConns sets the maximum number of idle connections kept per server.
+// Default is 2.
+func MaxIdleConns(n int) Options {
+	return func(m *Memcached) {
+		m.maxIdleConns = n
+	}
+}
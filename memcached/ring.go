@@ -0,0 +1,101 @@
+package memcached
+
+import (
+	"crypto/md5" // #nosec G501 -- ketama hashing, not used for security
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// pointsPerServer is the number of ring points per server, as in libketama.
+const pointsPerServer = 160
+
+type point struct {
+	hash uint32
+	addr net.Addr
+}
+
+// ring is a ketama compatible consistent hash ring implementing memcache.ServerSelector.
+type ring struct {
+	points []point
+	addrs  []net.Addr
+}
+
+var _ memcache.ServerSelector = (*ring)(nil)
+
+func newRing(servers ...string) (*ring, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("no servers")
+	}
+
+	r := &ring{
+		points: make([]point, 0, len(servers)*pointsPerServer),
+		addrs:  make([]net.Addr, 0, len(servers)),
+	}
+
+	for _, server := range servers {
+		addr, err := resolve(server)
+		if err != nil {
+			return nil, err
+		}
+
+		r.addrs = append(r.addrs, addr)
+
+		// Each MD5 digest yields four points.
+		for i := 0; i < pointsPerServer/4; i++ {
+			digest := md5.Sum([]byte(server + "-" + strconv.Itoa(i))) // #nosec G401 -- see import
+
+			for j := 0; j < 4; j++ {
+				r.points = append(r.points, point{
+					hash: binary.LittleEndian.Uint32(digest[j*4:]),
+					addr: addr,
+				})
+			}
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i].hash < r.points[j].hash })
+
+	return r, nil
+}
+
+func resolve(server string) (net.Addr, error) {
+	if strings.Contains(server, "/") {
+		return net.ResolveUnixAddr("unix", server)
+	}
+
+	return net.ResolveTCPAddr("tcp", server)
+}
+
+// PickServer returns the server owning the first ring point at or after the key hash.
+func (r *ring) PickServer(key string) (net.Addr, error) {
+	if len(r.points) == 0 {
+		return nil, memcache.ErrNoServers
+	}
+
+	digest := md5.Sum([]byte(key)) // #nosec G401 -- see import
+	hash := binary.LittleEndian.Uint32(digest[:4])
+
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= hash })
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.points[i].addr, nil
+}
+
+// Each calls f for every server.
+func (r *ring) Each(f func(net.Addr) error) error {
+	for _, addr := range r.addrs {
+		if err := f(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
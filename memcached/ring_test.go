@@ -0,0 +1,102 @@
+package memcached
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRingDistribution(t *testing.T) {
+	servers := []string{"127.0.0.1:11211", "127.0.0.1:11212", "127.0.0.1:11213"}
+
+	r, err := newRing(servers...)
+	if err != nil {
+		t.Fatalf("newRing() error = %v", err)
+	}
+
+	counts := make(map[string]int)
+
+	for i := 0; i < 30000; i++ {
+		addr, err := r.PickServer("key-" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("PickServer() error = %v", err)
+		}
+
+		counts[addr.String()]++
+	}
+
+	for _, s := range servers {
+		if counts[s] < 7000 || counts[s] > 13000 {
+			t.Errorf("server %s got %d of 30000 keys, want a balanced share", s, counts[s])
+		}
+	}
+}
+
+func TestRingAddServerRemapsFewKeys(t *testing.T) {
+	before, err := newRing("127.0.0.1:11211", "127.0.0.1:11212", "127.0.0.1:11213")
+	if err != nil {
+		t.Fatalf("newRing() error = %v", err)
+	}
+
+	after, err := newRing("127.0.0.1:11211", "127.0.0.1:11212", "127.0.0.1:11213", "127.0.0.1:11214")
+	if err != nil {
+		t.Fatalf("newRing() error = %v", err)
+	}
+
+	moved := 0
+
+	for i := 0; i < 10000; i++ {
+		key := "key-" + strconv.Itoa(i)
+
+		a, _ := before.PickServer(key)
+		b, _ := after.PickServer(key)
+
+		if a.String() != b.String() {
+			if b.String() != "127.0.0.1:11214" {
+				t.Fatalf("key %s moved between existing servers %s -> %s", key, a, b)
+			}
+
+			moved++
+		}
+	}
+
+	// About a quarter of the keys should move to the new server.
+	if moved < 1500 || moved > 3500 {
+		t.Errorf("moved %d of 10000 keys, want about 2500", moved)
+	}
+}
+
+func TestNewRingErrors(t *testing.T) {
+	if _, err := newRing(); err == nil {
+		t.Error("newRing() without servers must fail")
+	}
+
+	if _, err := newRing("invalid:port:x"); err == nil {
+		t.Error("newRing() with an invalid address must fail")
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		expected int32
+	}{
+		{name: "no expiration", ttl: 0, expected: 0},
+		{name: "sub-second rounds up", ttl: 100 * time.Millisecond, expected: 1},
+		{name: "seconds", ttl: 2 * time.Minute, expected: 120},
+		{name: "30 days is relative", ttl: 30 * 24 * time.Hour, expected: 30 * 24 * 3600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiration(tt.ttl); got != tt.expected {
+				t.Errorf("expiration(%v) = %d, want %d", tt.ttl, got, tt.expected)
+			}
+		})
+	}
+
+	if got := expiration(60 * 24 * time.Hour); int64(got) < time.Now().Unix() {
+		t.Errorf("expiration(60 days) = %d, want a Unix timestamp", got)
+	}
+}
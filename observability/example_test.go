@@ -0,0 +1,30 @@
+package observability_test
+
+import (
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/rdashevsky/go-pkgs/observability"
+)
+
+func ExampleNew() {
+	obs, err := observability.New(observability.Config{
+		ServiceName: "orders",
+		Metrics:     observability.MetricsConfig{Enabled: true, Addr: ":9090"},
+		Tracing:     observability.TracingConfig{Enabled: false},
+	})
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	srv := httpserver.New(httpserver.Port(":8080"), obs.HTTPServer())
+	_ = srv
+
+	// With the app runner: r.Add("observability", obs)
+	fmt.Println("telemetry ready")
+
+	// Output:
+	// telemetry ready
+}
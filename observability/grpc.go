@@ -0,0 +1,118 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/grpcserver"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer returns the grpcserver option recording the duration of every unary and
+// stream call by method and status code, and tracing it in a server span continuing
+// the trace propagated in the call metadata. Add it first to measure the other interceptors.
+//
+// Example:
+//
+//	srv := grpcserver.New(grpcserver.Port("9090"), obs.GRPCServer(), grpcserver.MapErrors())
+func (o *Observability) GRPCServer() grpcserver.Option {
+	unary := grpcserver.UnaryInterceptors(o.unaryInterceptor)
+	stream := grpcserver.StreamInterceptors(o.streamInterceptor)
+
+	return func(s *grpcserver.Server) {
+		unary(s)
+		stream(s)
+	}
+}
+
+func (o *Observability) unaryInterceptor(
+	ctx context.Context, req any, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler,
+) (any, error) {
+	ctx, finish := o.startRPC(ctx, info.FullMethod)
+
+	resp, err := handler(ctx, req)
+
+	finish(err)
+
+	return resp, err
+}
+
+func (o *Observability) streamInterceptor(
+	srv any, ss pbgrpc.ServerStream, info *pbgrpc.StreamServerInfo, handler pbgrpc.StreamHandler,
+) error {
+	ctx, finish := o.startRPC(ss.Context(), info.FullMethod)
+
+	err := handler(srv, &tracedStream{ServerStream: ss, ctx: ctx})
+
+	finish(err)
+
+	return err
+}
+
+func (o *Observability) startRPC(ctx context.Context, method string) (context.Context, func(err error)) {
+	start := time.Now()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, span := o.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method)),
+	)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, code.String())
+		}
+
+		span.End()
+
+		o.metrics.grpcRequests.WithLabelValues(method, code.String()).Observe(time.Since(start).Seconds())
+	}
+}
+
+// tracedStream overrides the context of a server stream with the traced one.
+type tracedStream struct {
+	pbgrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier reads propagation headers of incoming gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	if v := metadata.MD(m).Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,92 @@
+package observability
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPServer returns the httpserver option recording the duration of every request
+// by method, route and status, and tracing it in a server span continuing the trace
+// propagated by the caller.
+//
+// Example:
+//
+//	srv := httpserver.New(httpserver.Port(":8080"), obs.HTTPServer())
+func (o *Observability) HTTPServer() httpserver.Option {
+	return httpserver.Middleware(o.HTTPMiddleware())
+}
+
+// HTTPMiddleware returns the Fiber middleware used by HTTPServer, for apps not created
+// with httpserver.New.
+func (o *Observability) HTTPMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), fiberCarrier{c})
+		ctx, span := o.tracer.Start(ctx, c.Method(), trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		code := c.Response().StatusCode()
+
+		if err != nil {
+			code = fiber.StatusInternalServerError
+
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				code = fe.Code
+			}
+		}
+
+		// The route is only known once the router matched it.
+		route := c.Route().Path
+		method := c.Method()
+
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", code),
+		)
+
+		if code >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, strconv.Itoa(code))
+		}
+
+		o.metrics.httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// fiberCarrier reads propagation headers of a Fiber request.
+type fiberCarrier struct {
+	c *fiber.Ctx
+}
+
+func (f fiberCarrier) Get(key string) string { return f.c.Get(key) }
+
+func (f fiberCarrier) Set(string, string) {}
+
+func (f fiberCarrier) Keys() []string {
+	headers := f.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+
+	for k := range headers {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
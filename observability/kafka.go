@@ -0,0 +1,30 @@
+package observability
+
+import (
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Kafka returns the franz-go hooks counting produced and consumed records by topic
+// and status, to set as kafka.Config.Hooks.
+//
+// Example:
+//
+//	cfg := kafka.Config{Brokers: brokers, GroupID: "orders", Hooks: obs.Kafka()}
+func (o *Observability) Kafka() []kgo.Hook {
+	return []kgo.Hook{kafkaHook{o}}
+}
+
+type kafkaHook struct {
+	o *Observability
+}
+
+func (h kafkaHook) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
+	h.o.metrics.kafkaRecords.WithLabelValues(r.Topic, "produced", outcome(err)).Inc()
+}
+
+func (h kafkaHook) OnFetchRecordUnbuffered(r *kgo.Record, polled bool) {
+	// Records discarded without being polled, e.g. after a rebalance, are not consumed.
+	if polled {
+		h.o.metrics.kafkaRecords.WithLabelValues(r.Topic, "consumed", statusOK).Inc()
+	}
+}
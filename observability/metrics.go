@@ -0,0 +1,63 @@
+package observability
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Status label values of the metrics.
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+type metrics struct {
+	httpRequests  *prometheus.HistogramVec
+	grpcRequests  *prometheus.HistogramVec
+	dbQueries     *prometheus.HistogramVec
+	redisCommands *prometheus.HistogramVec
+	kafkaRecords  *prometheus.CounterVec
+	rabbitmqCalls *prometheus.HistogramVec
+}
+
+func newMetrics(namespace string, r prometheus.Registerer) *metrics {
+	histogram := func(name, help string, labels ...string) *prometheus.HistogramVec {
+		h := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+			Buckets:   prometheus.DefBuckets,
+		}, labels)
+		r.MustRegister(h)
+
+		return h
+	}
+
+	kafkaRecords := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_records_total",
+		Help:      "Kafka records produced and consumed.",
+	}, []string{"topic", "direction", "status"})
+	r.MustRegister(kafkaRecords)
+
+	return &metrics{
+		httpRequests: histogram("http_server_request_duration_seconds",
+			"Duration of HTTP requests.", "method", "route", "status"),
+		grpcRequests: histogram("grpc_server_request_duration_seconds",
+			"Duration of gRPC calls.", "method", "code"),
+		dbQueries: histogram("db_query_duration_seconds",
+			"Duration of PostgreSQL queries.", "operation", "status"),
+		redisCommands: histogram("redis_command_duration_seconds",
+			"Duration of Redis commands.", "command", "status"),
+		kafkaRecords: kafkaRecords,
+		rabbitmqCalls: histogram("rabbitmq_rpc_duration_seconds",
+			"Duration of RabbitMQ RPC calls.", "side", "handler", "status"),
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return statusError
+	}
+
+	return statusOK
+}
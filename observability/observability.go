@@ -0,0 +1,310 @@
+// Package observability bootstraps the telemetry of a service from a single Config:
+// a logger, Prometheus metrics with Go runtime collectors served on /metrics, OpenTelemetry
+// tracing exported over OTLP and pprof profiling. It hands back pre-configured options
+// instrumenting httpserver, grpcserver, postgres, redis, kafka and rabbitmq consistently.
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/profiling"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	_defaultLogLevel        = "info"
+	_defaultMetricsAddr     = ":9090"
+	_defaultTracingEndpoint = "localhost:4318"
+	_defaultReadTimeout     = 5 * time.Second
+	_defaultShutdownTimeout = 5 * time.Second
+	_instrumentationName    = "github.com/rdashevsky/go-pkgs/observability"
+)
+
+// ErrNoServiceName is returned by New when Config.ServiceName is empty.
+var ErrNoServiceName = errors.New("observability - service name is required")
+
+// Config describes the telemetry of a service.
+type Config struct {
+	// ServiceName identifies the service in traces and metrics. Required.
+	ServiceName string
+	// ServiceVersion and Environment are recorded as resource attributes of traces.
+	ServiceVersion string
+	Environment    string
+	// LogLevel is the level of the returned logger. Default is "info".
+	LogLevel string
+
+	Metrics   MetricsConfig
+	Tracing   TracingConfig
+	Profiling ProfilingConfig
+}
+
+// MetricsConfig configures Prometheus metrics.
+type MetricsConfig struct {
+	// Enabled serves the metrics on Addr under /metrics.
+	Enabled bool
+	// Addr is the listen address of the metrics server. Default is ":9090".
+	Addr string
+	// Namespace prefixes every metric name. Default is no prefix.
+	Namespace string
+}
+
+// TracingConfig configures OpenTelemetry tracing.
+type TracingConfig struct {
+	// Enabled exports spans over OTLP/HTTP; when disabled the instrumentation creates no spans.
+	Enabled bool
+	// Endpoint is the host:port of the OTLP/HTTP collector. Default is "localhost:4318".
+	Endpoint string
+	// Insecure disables TLS towards the collector.
+	Insecure bool
+	// SampleRatio is the fraction of new traces sampled, between 0 and 1; sampling decisions
+	// of incoming traces are respected. Zero means 1, every trace is sampled.
+	SampleRatio float64
+}
+
+// ProfilingConfig configures the pprof server of the profiling package.
+type ProfilingConfig struct {
+	// Enabled serves pprof on Addr.
+	Enabled bool
+	// Addr is the listen address of the pprof server. Default is "127.0.0.1:6060".
+	Addr string
+	// PyroscopeURL enables continuous profiling pushed to this Pyroscope server.
+	PyroscopeURL string
+}
+
+// Observability holds the telemetry of a service. It implements the app Component
+// and Notifier interfaces, serving metrics and pprof between Start and Shutdown.
+type Observability struct {
+	// Logger is the service logger.
+	Logger logger.LoggerI
+	// Registry holds the service metrics, including the Go runtime and process collectors.
+	// Register application metrics on it.
+	Registry *prometheus.Registry
+	// TracerProvider creates the service tracers. It is also installed as the global provider.
+	TracerProvider trace.TracerProvider
+
+	cfg      Config
+	tracer   trace.Tracer
+	shutdown func(ctx context.Context) error
+	metrics  *metrics
+
+	server   *http.Server
+	profiler *profiling.Profiler
+	notify   chan error
+}
+
+// New creates the telemetry described by cfg. It installs the tracer provider and the
+// W3C trace context and baggage propagators as OpenTelemetry globals.
+// Default configuration: "info" logger, metrics, tracing and profiling disabled.
+//
+// Example:
+//
+//	obs, err := observability.New(observability.Config{
+//	    ServiceName: "orders",
+//	    Metrics:     observability.MetricsConfig{Enabled: true},
+//	    Tracing:     observability.TracingConfig{Enabled: true, Endpoint: "otel-collector:4318", Insecure: true},
+//	})
+//	pg, err := postgres.New(url, obs.Postgres())
+//	srv := httpserver.New(obs.HTTPServer())
+//	r.Add("observability", obs)
+func New(cfg Config) (*Observability, error) {
+	if cfg.ServiceName == "" {
+		return nil, ErrNoServiceName
+	}
+
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = _defaultLogLevel
+	}
+
+	if cfg.Metrics.Addr == "" {
+		cfg.Metrics.Addr = _defaultMetricsAddr
+	}
+
+	if cfg.Tracing.Endpoint == "" {
+		cfg.Tracing.Endpoint = _defaultTracingEndpoint
+	}
+
+	o := &Observability{
+		Logger:   logger.New(cfg.LogLevel),
+		Registry: prometheus.NewRegistry(),
+		cfg:      cfg,
+		shutdown: func(context.Context) error { return nil },
+		notify:   make(chan error, 1),
+	}
+
+	o.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	o.metrics = newMetrics(cfg.Metrics.Namespace, o.Registry)
+
+	if err := o.initTracing(); err != nil {
+		return nil, err
+	}
+
+	o.tracer = o.TracerProvider.Tracer(_instrumentationName)
+
+	if cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", o.MetricsHandler())
+
+		o.server = &http.Server{
+			Addr:              cfg.Metrics.Addr,
+			Handler:           mux,
+			ReadHeaderTimeout: _defaultReadTimeout,
+		}
+	}
+
+	if cfg.Profiling.Enabled {
+		opts := []profiling.Option{profiling.WithLogger(o.Logger)}
+
+		if cfg.Profiling.Addr != "" {
+			opts = append(opts, profiling.Addr(cfg.Profiling.Addr))
+		}
+
+		if cfg.Profiling.PyroscopeURL != "" {
+			opts = append(opts, profiling.Pyroscope(cfg.Profiling.PyroscopeURL, cfg.ServiceName))
+		}
+
+		o.profiler = profiling.New(opts...)
+	}
+
+	return o, nil
+}
+
+func (o *Observability) initTracing() error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !o.cfg.Tracing.Enabled {
+		o.TracerProvider = noop.NewTracerProvider()
+
+		return nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.cfg.Tracing.Endpoint)}
+	if o.cfg.Tracing.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	// The exporter connects lazily, New does not fail when the collector is down.
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("observability - New - otlptracehttp.New: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", o.cfg.ServiceName)}
+
+	if o.cfg.ServiceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", o.cfg.ServiceVersion))
+	}
+
+	if o.cfg.Environment != "" {
+		attrs = append(attrs, attribute.String("deployment.environment", o.cfg.Environment))
+	}
+
+	ratio := o.cfg.Tracing.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	o.TracerProvider = tp
+	o.shutdown = tp.Shutdown
+
+	return nil
+}
+
+// Tracer returns a tracer of the service tracer provider, for application spans.
+func (o *Observability) Tracer(name string) trace.Tracer {
+	return o.TracerProvider.Tracer(name)
+}
+
+// MetricsHandler serves the registry metrics in the Prometheus exposition format,
+// to mount on an existing server instead of enabling the metrics server.
+func (o *Observability) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(o.Registry, promhttp.HandlerOpts{})
+}
+
+// Start begins serving metrics and pprof when enabled. Use Notify() to receive server errors.
+func (o *Observability) Start() {
+	if o.server != nil {
+		go func() {
+			if err := o.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				o.report(fmt.Errorf("observability - Start - o.server.ListenAndServe: %w", err))
+			}
+		}()
+	}
+
+	if o.profiler != nil {
+		o.profiler.Start()
+
+		go func() {
+			for err := range o.profiler.Notify() {
+				o.report(err)
+			}
+		}()
+	}
+}
+
+func (o *Observability) report(err error) {
+	select {
+	case o.notify <- err:
+	default:
+	}
+}
+
+// Notify returns a channel that receives an error if the metrics or pprof server fails.
+func (o *Observability) Notify() <-chan error {
+	return o.notify
+}
+
+// Shutdown stops the metrics and pprof servers and flushes pending spans.
+func (o *Observability) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	if o.server != nil {
+		if err := o.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("observability - Shutdown - o.server.Shutdown: %w", err))
+		}
+	}
+
+	if o.profiler != nil {
+		if err := o.profiler.Shutdown(); err != nil {
+			errs = append(errs, fmt.Errorf("observability - Shutdown - o.profiler.Shutdown: %w", err))
+		}
+	}
+
+	if err := o.shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("observability - Shutdown - TracerProvider.Shutdown: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
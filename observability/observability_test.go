@@ -0,0 +1,131 @@
+package observability_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/httpserver"
+	"github.com/rdashevsky/go-pkgs/observability"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func newObservability(t *testing.T) *observability.Observability {
+	t.Helper()
+
+	obs, err := observability.New(observability.Config{ServiceName: "test", LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = obs.Shutdown() })
+
+	return obs
+}
+
+func scrape(t *testing.T, obs *observability.Observability) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	obs.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	return string(body)
+}
+
+func TestNew(t *testing.T) {
+	if _, err := observability.New(observability.Config{}); !errors.Is(err, observability.ErrNoServiceName) {
+		t.Errorf("New() without service name error = %v, want ErrNoServiceName", err)
+	}
+
+	obs := newObservability(t)
+
+	if obs.Logger == nil || obs.TracerProvider == nil {
+		t.Fatal("expected logger and tracer provider")
+	}
+
+	if metrics := scrape(t, obs); !strings.Contains(metrics, "go_goroutines") {
+		t.Error("expected Go runtime metrics")
+	}
+}
+
+func TestHTTPServer(t *testing.T) {
+	obs := newObservability(t)
+
+	srv := httpserver.New(obs.HTTPServer())
+	srv.App.Get("/users/:id", func(c *fiber.Ctx) error { return c.SendString(c.Params("id")) })
+	srv.App.Get("/fail", func(*fiber.Ctx) error { return fiber.ErrBadGateway })
+
+	for _, path := range []string{"/users/1", "/users/2", "/fail"} {
+		resp, err := srv.App.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+
+		resp.Body.Close()
+	}
+
+	metrics := scrape(t, obs)
+
+	for _, want := range []string{
+		`http_server_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2`,
+		`http_server_request_duration_seconds_count{method="GET",route="/fail",status="502"} 1`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	obs := newObservability(t)
+
+	r, err := redis.New("127.0.0.1:65432", "", "", obs.Redis())
+	if err != nil {
+		t.Fatalf("redis.New() error = %v", err)
+	}
+	defer r.Close()
+
+	if err := r.Set(context.Background(), "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	want := `redis_command_duration_seconds_count{command="set",status="error"} 1`
+	if metrics := scrape(t, obs); !strings.Contains(metrics, want) {
+		t.Errorf("metrics missing %s", want)
+	}
+}
+
+func TestKafka(t *testing.T) {
+	obs := newObservability(t)
+
+	for _, h := range obs.Kafka() {
+		if p, ok := h.(kgo.HookProduceRecordUnbuffered); ok {
+			p.OnProduceRecordUnbuffered(&kgo.Record{Topic: "orders"}, nil)
+			p.OnProduceRecordUnbuffered(&kgo.Record{Topic: "orders"}, errors.New("broker down"))
+		}
+
+		if f, ok := h.(kgo.HookFetchRecordUnbuffered); ok {
+			f.OnFetchRecordUnbuffered(&kgo.Record{Topic: "orders"}, true)
+			f.OnFetchRecordUnbuffered(&kgo.Record{Topic: "orders"}, false)
+		}
+	}
+
+	metrics := scrape(t, obs)
+
+	for _, want := range []string{
+		`kafka_records_total{direction="produced",status="ok",topic="orders"} 1`,
+		`kafka_records_total{direction="produced",status="error",topic="orders"} 1`,
+		`kafka_records_total{direction="consumed",status="ok",topic="orders"} 1`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// Postgres returns the postgres option recording the duration of every query by
+// operation (SELECT, INSERT, ...) and tracing it in a client span.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.MaxPoolSize(10), obs.Postgres())
+func (o *Observability) Postgres() postgres.Option {
+	tracing := postgres.WithTracer(o.TracerProvider)
+	metrics := postgres.QueryTracer(queryMetrics{o})
+
+	return func(p *postgres.Postgres) {
+		tracing(p)
+		metrics(p)
+	}
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	start     time.Time
+	operation string
+}
+
+type queryMetrics struct {
+	o *Observability
+}
+
+func (t queryMetrics) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{start: time.Now(), operation: postgres.Operation(data.SQL)})
+}
+
+func (t queryMetrics) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	t.o.metrics.dbQueries.WithLabelValues(q.operation, outcome(data.Err)).Observe(time.Since(q.start).Seconds())
+}
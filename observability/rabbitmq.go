@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/rabbitmq/client"
+	"github.com/rdashevsky/go-pkgs/rabbitmq/server"
+)
+
+// RabbitMQServer returns the rabbitmq server option recording the duration of every
+// handled request by handler and status and tracing it in a server span.
+//
+// Example:
+//
+//	srv, err := server.New(url, exchange, router, obs.Logger, obs.RabbitMQServer())
+func (o *Observability) RabbitMQServer() server.Option {
+	tracing := server.WithTracer(o.TracerProvider)
+	metrics := server.WithMetrics(rpcMetrics{o: o, side: "server"})
+
+	return func(s *server.Server) {
+		tracing(s)
+		metrics(s)
+	}
+}
+
+// RabbitMQClient returns the rabbitmq client option recording the duration of every
+// remote call by handler and status and tracing it in a client span.
+//
+// Example:
+//
+//	rpc, err := client.New(url, serverEx, clientEx, obs.RabbitMQClient())
+func (o *Observability) RabbitMQClient() client.Option {
+	tracing := client.WithTracer(o.TracerProvider)
+	metrics := client.WithMetrics(rpcMetrics{o: o, side: "client"})
+
+	return func(c *client.Client) {
+		tracing(c)
+		metrics(c)
+	}
+}
+
+type rpcMetrics struct {
+	o    *Observability
+	side string
+}
+
+func (m rpcMetrics) ObserveCall(handler string, duration time.Duration, err error) {
+	m.o.metrics.rabbitmqCalls.WithLabelValues(m.side, handler, outcome(err)).Observe(duration.Seconds())
+}
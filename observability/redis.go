@@ -0,0 +1,86 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redis returns the redis option recording the duration of every command by name,
+// pipelines under the "pipeline" command, and tracing them in client spans.
+// Missing keys (redis.Nil) are not errors.
+//
+// Example:
+//
+//	r, err := redis.New("localhost:6379", "", "", obs.Redis())
+func (o *Observability) Redis() redis.Options {
+	return redis.Hooks(redisHook{o})
+}
+
+type redisHook struct {
+	o *Observability
+}
+
+func (h redisHook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h redisHook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		finish := h.start(ctx, cmd.Name())
+
+		err := next(ctx, cmd)
+
+		finish(err)
+
+		return err
+	}
+}
+
+func (h redisHook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		finish := h.start(ctx, "pipeline")
+
+		err := next(ctx, cmds)
+
+		finish(err)
+
+		return err
+	}
+}
+
+func (h redisHook) start(ctx context.Context, command string) func(err error) {
+	start := time.Now()
+
+	_, span := h.o.tracer.Start(ctx, "redis "+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", command),
+		),
+	)
+
+	return func(err error) {
+		if errors.Is(err, goredis.Nil) {
+			err = nil
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+
+		h.o.metrics.redisCommands.WithLabelValues(command, outcome(err)).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const _specFile = "/openapi.json"
+
+var (
+	swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`))
+
+	redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+</head>
+<body>
+  <redoc spec-url="{{.SpecURL}}"></redoc>
+  <script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`))
+)
+
+// Register serves the documentation page on the docs path of r and the spec
+// as JSON next to it.
+//
+// Default configuration:
+//   - Docs path: "/docs" (spec on "/docs/openapi.json")
+//   - UI: SwaggerUI
+//   - Title: the spec info title
+//
+// Example:
+//
+//	openapi.Register(server.App, spec, openapi.WithUI(openapi.Redoc))
+func Register(r fiber.Router, s *Spec, opts ...Option) {
+	cfg := newConfig(opts)
+
+	title := cfg.title
+	if title == "" && s.doc.Info != nil {
+		title = s.doc.Info.Title
+	}
+
+	docsPath := strings.TrimSuffix(cfg.docsPath, "/")
+
+	tmpl := swaggerUITemplate
+	if cfg.ui == Redoc {
+		tmpl = redocTemplate
+	}
+
+	var page bytes.Buffer
+
+	// Executing into a bytes.Buffer with string fields cannot fail.
+	_ = tmpl.Execute(&page, struct {
+		Title   string
+		SpecURL string
+	}{
+		Title:   title,
+		SpecURL: docsPath + _specFile,
+	})
+
+	html := page.Bytes()
+
+	r.Get(docsPath+_specFile, func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+
+		return ctx.Send(s.json)
+	})
+
+	docsHandler := func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+		return ctx.Send(html)
+	}
+
+	if docsPath == "" {
+		r.Get("/", docsHandler)
+	} else {
+		r.Get(docsPath, docsHandler)
+	}
+}
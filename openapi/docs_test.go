@@ -0,0 +1,50 @@
+package openapi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	spec, err := openapi.Load([]byte(testSpec))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		opts     []openapi.Option
+		target   string
+		wantType string
+		wantBody string
+	}{
+		{name: "swagger ui", target: "/docs", wantType: fiber.MIMETextHTMLCharsetUTF8, wantBody: "swagger-ui"},
+		{name: "title", target: "/docs", opts: []openapi.Option{openapi.Title("Pets")}, wantType: fiber.MIMETextHTMLCharsetUTF8, wantBody: "<title>Pets</title>"},
+		{name: "default title", target: "/docs", wantType: fiber.MIMETextHTMLCharsetUTF8, wantBody: "<title>Pets API</title>"},
+		{name: "redoc", target: "/api-docs", opts: []openapi.Option{openapi.DocsPath("/api-docs"), openapi.WithUI(openapi.Redoc)}, wantType: fiber.MIMETextHTMLCharsetUTF8, wantBody: `spec-url="/api-docs/openapi.json"`},
+		{name: "spec", target: "/docs/openapi.json", wantType: fiber.MIMEApplicationJSONCharsetUTF8, wantBody: `"openapi":"3.0.3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			openapi.Register(app, spec, tt.opts...)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.target, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.wantType, resp.Header.Get(fiber.HeaderContentType))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package openapi_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/openapi"
+)
+
+func ExampleValidator() {
+	spec, err := openapi.Load([]byte(`
+openapi: 3.0.3
+info: {title: Example, version: 1.0.0}
+paths:
+  /hello:
+    get:
+      parameters:
+        - {name: name, in: query, required: true, schema: {type: string}}
+      responses:
+        "200": {description: Greeting}
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	app := fiber.New()
+	openapi.Register(app, spec)
+	app.Use(openapi.Validator(spec))
+	app.Get("/hello", func(c *fiber.Ctx) error {
+		return c.SendString("hello " + c.Query("name"))
+	})
+
+	for _, target := range []string{"/hello?name=gopher", "/hello"} {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		if err != nil {
+			panic(err)
+		}
+		resp.Body.Close()
+
+		fmt.Println(target, resp.StatusCode)
+	}
+
+	// Output:
+	// /hello?name=gopher 200
+	// /hello 400
+}
@@ -0,0 +1,145 @@
+// Package openapi keeps API documentation and behavior in sync: it serves an
+// embedded OpenAPI 3 specification through Swagger UI or Redoc and validates
+// incoming requests, and optionally outgoing responses, against it as Fiber
+// middleware.
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// Spec is a parsed and validated OpenAPI 3 document.
+type Spec struct {
+	doc       *openapi3.T
+	json      []byte
+	router    routers.Router
+	basePaths []string
+}
+
+// Load parses and validates an OpenAPI 3 document in JSON or YAML.
+//
+// Example:
+//
+//	//go:embed openapi.yaml
+//	var specYAML []byte
+//
+//	spec, err := openapi.Load(specYAML)
+func Load(data []byte) (*Spec, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("openapi - Load - loader.LoadFromData: %w", err)
+	}
+
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi - Load - doc.Validate: %w", err)
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi - Load - json.Marshal: %w", err)
+	}
+
+	basePaths, err := serverBasePaths(doc.Servers)
+	if err != nil {
+		return nil, fmt.Errorf("openapi - Load - serverBasePaths: %w", err)
+	}
+
+	// Routes are matched on the path relative to a server base path, so the
+	// host the service is reached through does not have to be listed in the spec.
+	routing := *doc
+	routing.Servers = nil
+
+	router, err := legacy.NewRouter(&routing)
+	if err != nil {
+		return nil, fmt.Errorf("openapi - Load - legacy.NewRouter: %w", err)
+	}
+
+	return &Spec{
+		doc:       doc,
+		json:      raw,
+		router:    router,
+		basePaths: basePaths,
+	}, nil
+}
+
+// LoadFS reads the document name from fsys, typically an embed.FS, and loads it.
+func LoadFS(fsys fs.FS, name string) (*Spec, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("openapi - LoadFS - fs.ReadFile: %w", err)
+	}
+
+	return Load(data)
+}
+
+// Doc returns the parsed document.
+func (s *Spec) Doc() *openapi3.T {
+	return s.doc
+}
+
+// JSON returns the document encoded as JSON.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// FindRoute returns the operation matching req along with its path parameters.
+// Server base paths declared in the spec are stripped before matching.
+func (s *Spec) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	path := req.URL.Path
+
+	for _, base := range s.basePaths {
+		if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || rest[0] == '/') {
+			if rest == "" {
+				rest = "/"
+			}
+
+			r := req.Clone(req.Context())
+			r.URL.Path = rest
+			r.URL.RawPath = ""
+
+			return s.router.FindRoute(r)
+		}
+	}
+
+	return s.router.FindRoute(req)
+}
+
+// serverBasePaths returns the distinct non-root base paths of servers,
+// longest first so that the most specific one wins.
+func serverBasePaths(servers openapi3.Servers) ([]string, error) {
+	seen := make(map[string]struct{}, len(servers))
+	paths := make([]string, 0, len(servers))
+
+	for _, server := range servers {
+		base, err := server.BasePath()
+		if err != nil {
+			return nil, err
+		}
+
+		base = strings.TrimSuffix(base, "/")
+		if base == "" {
+			continue
+		}
+
+		if _, ok := seen[base]; !ok {
+			seen[base] = struct{}{}
+			paths = append(paths, base)
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+
+	return paths, nil
+}
@@ -0,0 +1,144 @@
+package openapi_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rdashevsky/go-pkgs/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: Pets API
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "201":
+          description: Created
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Pet
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 1
+        age:
+          type: integer
+`
+
+func TestLoad(t *testing.T) {
+	spec, err := openapi.Load([]byte(testSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "Pets API", spec.Doc().Info.Title)
+	assert.Contains(t, string(spec.JSON()), `"title":"Pets API"`)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "malformed", spec: "openapi: ["},
+		{name: "missing info", spec: "openapi: 3.0.3\npaths: {}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := openapi.Load([]byte(tt.spec))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{"api/openapi.yaml": {Data: []byte(testSpec)}}
+
+	spec, err := openapi.LoadFS(fsys, "api/openapi.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", spec.Doc().Info.Version)
+
+	_, err = openapi.LoadFS(fsys, "missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestSpec_FindRoute(t *testing.T) {
+	spec, err := openapi.Load([]byte(testSpec))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		path    string
+		params  map[string]string
+		wantErr bool
+	}{
+		{name: "base path", method: "GET", target: "/v1/pets", path: "/pets", params: map[string]string{}},
+		{name: "other host", method: "GET", target: "http://localhost:8080/v1/pets/7", path: "/pets/{id}", params: map[string]string{"id": "7"}},
+		{name: "without base path", method: "POST", target: "/pets", path: "/pets", params: map[string]string{}},
+		{name: "unknown path", method: "GET", target: "/v1/owners", wantErr: true},
+		{name: "unknown method", method: "DELETE", target: "/v1/pets", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params, err := spec.FindRoute(httptest.NewRequest(tt.method, tt.target, nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.path, route.Path)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+}
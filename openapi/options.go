@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultDocsPath = "/docs"
+)
+
+// UI selects the documentation renderer served by Register.
+type UI int
+
+// Supported documentation renderers.
+const (
+	SwaggerUI UI = iota
+	Redoc
+)
+
+// Option configures Register and Validator.
+type Option func(*config)
+
+type config struct {
+	docsPath string
+	ui       UI
+	title    string
+
+	validateResponses   bool
+	strictResponses     bool
+	rejectUnknownRoutes bool
+	skip                map[string]struct{}
+
+	logger logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		docsPath: _defaultDocsPath,
+		ui:       SwaggerUI,
+		skip:     make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// DocsPath sets the path the documentation page is served on; the spec itself
+// is served on DocsPath + "/openapi.json".
+// Default is "/docs".
+func DocsPath(path string) Option {
+	return func(c *config) {
+		c.docsPath = path
+	}
+}
+
+// WithUI selects the documentation renderer.
+// Default is SwaggerUI.
+func WithUI(ui UI) Option {
+	return func(c *config) {
+		c.ui = ui
+	}
+}
+
+// Title sets the documentation page title.
+// Default is the title from the spec info object.
+func Title(title string) Option {
+	return func(c *config) {
+		c.title = title
+	}
+}
+
+// ValidateResponses enables validation of responses against the spec. Invalid
+// responses are logged and still sent unless StrictResponses is set.
+// Default is false.
+func ValidateResponses() Option {
+	return func(c *config) {
+		c.validateResponses = true
+	}
+}
+
+// StrictResponses enables response validation and replaces invalid responses
+// with a 500 response.
+// Default is false.
+func StrictResponses() Option {
+	return func(c *config) {
+		c.validateResponses = true
+		c.strictResponses = true
+	}
+}
+
+// RejectUnknownRoutes rejects requests not described by the spec with a 404
+// or 405 response instead of passing them through unvalidated.
+// Default is false.
+func RejectUnknownRoutes() Option {
+	return func(c *config) {
+		c.rejectUnknownRoutes = true
+	}
+}
+
+// Skip excludes the given request paths from validation, e.g. health and
+// metrics endpoints.
+// Default is none.
+func Skip(paths ...string) Option {
+	return func(c *config) {
+		for _, p := range paths {
+			c.skip[p] = struct{}{}
+		}
+	}
+}
+
+// WithLogger sets the logger used to report invalid responses.
+// Default is nil, which disables logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,170 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/rdashevsky/go-pkgs/httpserver/response"
+)
+
+// Validator returns a Fiber middleware validating requests against s. Invalid
+// requests are rejected with a 400 response describing the first problem found;
+// requests for routes the spec does not describe pass through unless
+// RejectUnknownRoutes is set. Security requirements are not checked, leave
+// authentication to the auth middleware.
+//
+// Default configuration:
+//   - Unknown routes: passed through
+//   - Response validation: disabled
+//
+// Example:
+//
+//	server.App.Use(openapi.Validator(spec,
+//	    openapi.Skip("/healthz", "/metrics"),
+//	    openapi.ValidateResponses(),
+//	    openapi.WithLogger(l),
+//	))
+func Validator(s *Spec, opts ...Option) fiber.Handler {
+	cfg := newConfig(opts)
+
+	filterOptions := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if _, ok := cfg.skip[ctx.Path()]; ok {
+			return ctx.Next()
+		}
+
+		req, err := adaptor.ConvertRequest(ctx, false)
+		if err != nil {
+			return fmt.Errorf("openapi - Validator - adaptor.ConvertRequest: %w", err)
+		}
+
+		req = req.WithContext(ctx.UserContext())
+
+		route, pathParams, err := s.FindRoute(req)
+		if err != nil {
+			if !cfg.rejectUnknownRoutes {
+				return ctx.Next()
+			}
+
+			var routeErr *routers.RouteError
+			if errors.As(err, &routeErr) && routeErr.Reason == routers.ErrMethodNotAllowed.Error() {
+				return response.Error(ctx, fiber.StatusMethodNotAllowed)
+			}
+
+			return response.Error(ctx, fiber.StatusNotFound)
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    filterOptions,
+		}
+
+		if err = openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+			message := describe(err)
+
+			return response.Error(ctx, fiber.StatusBadRequest, response.ErrorMessage(&message))
+		}
+
+		if err = ctx.Next(); err != nil || !cfg.validateResponses {
+			return err
+		}
+
+		return validateResponse(ctx, &cfg, input)
+	}
+}
+
+func validateResponse(ctx *fiber.Ctx, cfg *config, input *openapi3filter.RequestValidationInput) error {
+	resp := ctx.Response()
+
+	header := make(http.Header)
+	resp.Header.VisitAll(func(k, v []byte) {
+		header.Add(string(k), string(v))
+	})
+
+	out := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 resp.StatusCode(),
+		Header:                 header,
+		Options:                input.Options,
+	}
+	out.SetBodyBytes(resp.Body())
+
+	err := openapi3filter.ValidateResponse(input.Request.Context(), out)
+	if err == nil {
+		return nil
+	}
+
+	if cfg.logger != nil {
+		cfg.logger.Error("openapi - invalid response for %s %s: %s", input.Route.Method, input.Route.Path, describe(err))
+	}
+
+	if !cfg.strictResponses {
+		return nil
+	}
+
+	resp.Reset()
+
+	return response.Error(ctx, fiber.StatusInternalServerError)
+}
+
+// describe renders a validation error without the schema dumps kin-openapi
+// appends to its messages.
+func describe(err error) string {
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		reason := reqErr.Reason
+		if cause := schemaReason(reqErr.Err); cause != "" {
+			reason = cause
+		} else if reason == "" && reqErr.Err != nil {
+			reason = reqErr.Err.Error()
+		}
+
+		switch {
+		case reqErr.Parameter != nil:
+			return fmt.Sprintf("parameter %q in %s: %s", reqErr.Parameter.Name, reqErr.Parameter.In, reason)
+		case reqErr.RequestBody != nil:
+			return "request body: " + reason
+		default:
+			return reason
+		}
+	}
+
+	var respErr *openapi3filter.ResponseError
+	if errors.As(err, &respErr) {
+		reason := respErr.Reason
+		if cause := schemaReason(respErr.Err); cause != "" {
+			reason = cause
+		} else if reason == "" && respErr.Err != nil {
+			reason = respErr.Err.Error()
+		}
+
+		return "response: " + reason
+	}
+
+	return err.Error()
+}
+
+func schemaReason(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return ""
+	}
+
+	if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+		return fmt.Sprintf("%s at %q", schemaErr.Reason, "/"+strings.Join(pointer, "/"))
+	}
+
+	return schemaErr.Reason
+}
@@ -0,0 +1,111 @@
+package openapi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(t *testing.T, petsBody string, opts ...openapi.Option) *fiber.App {
+	t.Helper()
+
+	spec, err := openapi.Load([]byte(testSpec))
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(openapi.Validator(spec, opts...))
+
+	handler := func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(petsBody)
+	}
+
+	app.Get("/v1/pets", handler)
+	app.Post("/v1/pets", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Get("/v1/pets/:id", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(`{"name":"Rex"}`)
+	})
+	app.Get("/v1/owners", func(c *fiber.Ctx) error { return c.SendString("owners") })
+	app.Get("/healthz", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	return app
+}
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []openapi.Option
+		method      string
+		target      string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "valid query", method: "GET", target: "/v1/pets?limit=10", wantStatus: http.StatusOK},
+		{name: "invalid query", method: "GET", target: "/v1/pets?limit=1000", wantStatus: http.StatusBadRequest, wantMessage: `parameter \"limit\" in query`},
+		{name: "invalid path param", method: "GET", target: "/v1/pets/abc", wantStatus: http.StatusBadRequest, wantMessage: `parameter \"id\" in path`},
+		{name: "valid body", method: "POST", target: "/v1/pets", body: `{"name":"Rex","age":3}`, wantStatus: http.StatusCreated},
+		{name: "missing required property", method: "POST", target: "/v1/pets", body: `{"age":3}`, wantStatus: http.StatusBadRequest, wantMessage: "request body"},
+		{name: "wrong property type", method: "POST", target: "/v1/pets", body: `{"name":"Rex","age":"three"}`, wantStatus: http.StatusBadRequest, wantMessage: `/age`},
+		{name: "unknown route passes", method: "GET", target: "/v1/owners", wantStatus: http.StatusOK},
+		{name: "unknown route rejected", opts: []openapi.Option{openapi.RejectUnknownRoutes()}, method: "GET", target: "/v1/owners", wantStatus: http.StatusNotFound},
+		{name: "skipped path", opts: []openapi.Option{openapi.RejectUnknownRoutes(), openapi.Skip("/healthz")}, method: "GET", target: "/healthz", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, `[]`, tt.opts...)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantMessage != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Contains(t, string(body), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidator_Responses(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []openapi.Option
+		body       string
+		wantStatus int
+	}{
+		{name: "valid response", opts: []openapi.Option{openapi.StrictResponses()}, body: `[{"name":"Rex"}]`, wantStatus: http.StatusOK},
+		{name: "invalid response logged", opts: []openapi.Option{openapi.ValidateResponses()}, body: `[{"age":3}]`, wantStatus: http.StatusOK},
+		{name: "invalid response rejected", opts: []openapi.Option{openapi.StrictResponses()}, body: `[{"age":3}]`, wantStatus: http.StatusInternalServerError},
+		{name: "not validated by default", body: `[{"age":3}]`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, tt.body, tt.opts...)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/pets", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
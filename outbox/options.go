@@ -0,0 +1,119 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/eventbus"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// Option is a function that configures a Store or a Relay.
+type Option func(*config)
+
+type config struct {
+	table           string
+	batchSize       int
+	pollInterval    time.Duration
+	deleteOnPublish bool
+	maxAttempts     int
+	backoff         retry.Backoff
+	onDead          func(e *eventbus.Event, err error)
+	metrics         Metrics
+	logger          logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		table:        _defaultTable,
+		batchSize:    _defaultBatchSize,
+		pollInterval: _defaultPollInterval,
+		maxAttempts:  _defaultMaxAttempts,
+		backoff:      retry.Exponential(_defaultMinBackoff, _defaultMaxBackoff),
+		metrics:      noopMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.batchSize < 1 {
+		cfg.batchSize = 1
+	}
+
+	if cfg.maxAttempts < 1 {
+		cfg.maxAttempts = 1
+	}
+
+	return cfg
+}
+
+// Table sets the outbox table name, optionally schema qualified ("schema.table").
+// Default is "outbox".
+func Table(name string) Option {
+	return func(c *config) {
+		c.table = name
+	}
+}
+
+// BatchSize sets the maximum number of messages the relay publishes per transaction.
+// Default is 100.
+func BatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// PollInterval sets how often the relay checks for pending messages when the outbox
+// is drained. Default is 1 second.
+func PollInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.pollInterval = interval
+	}
+}
+
+// DeleteOnPublish deletes published rows instead of setting their published_at column.
+func DeleteOnPublish() Option {
+	return func(c *config) {
+		c.deleteOnPublish = true
+	}
+}
+
+// MaxAttempts sets the number of publish attempts before a message is dead-lettered.
+// Default is 10.
+func MaxAttempts(n int) Option {
+	return func(c *config) {
+		c.maxAttempts = n
+	}
+}
+
+// Backoff sets the delay before the first retry of a message, doubled on every further
+// retry up to maxDelay. Default is 1 second up to 5 minutes.
+func Backoff(initial, maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.backoff = retry.Exponential(initial, maxDelay)
+	}
+}
+
+// OnDeadLetter sets a function called with the messages that failed their last
+// attempt and the last publish error, e.g. to alert. Dead messages keep their row with
+// dead_at set. Default is logging them.
+func OnDeadLetter(fn func(e *eventbus.Event, err error)) Option {
+	return func(c *config) {
+		c.onDead = fn
+	}
+}
+
+// WithMetrics sets the relay metrics collector.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// WithLogger sets the relay logger.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,159 @@
+// Package outbox implements the transactional outbox pattern for Postgres: events are
+// stored in an outbox table in the same transaction as the business change, and a relay
+// publishes them to Kafka or RabbitMQ through an eventbus.Publisher with at-least-once
+// delivery and ordering per aggregate.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+const (
+	_defaultTable        = "outbox"
+	_defaultBatchSize    = 100
+	_defaultPollInterval = time.Second
+	_defaultMaxAttempts  = 10
+	_defaultMinBackoff   = time.Second
+	_defaultMaxBackoff   = 5 * time.Minute
+)
+
+// DBTX is implemented by pgx.Tx, *pgxpool.Pool and *pgx.Conn.
+// Enqueue is meant to be called with the transaction of the business change.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Message is an event stored in the outbox.
+type Message struct {
+	// AggregateID groups messages that must be published in order, e.g. an order ID.
+	AggregateID string
+	// Topic is the destination topic or routing key.
+	Topic string
+	// Key is the broker message key; AggregateID is used when empty.
+	Key string
+	// Headers carry metadata such as trace context.
+	Headers map[string]string
+	// Payload is the encoded event body.
+	Payload []byte
+}
+
+// JSONMessage returns a Message with v encoded as JSON payload.
+func JSONMessage(aggregateID, topic string, v interface{}) (Message, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return Message{}, fmt.Errorf("outbox - JSONMessage - json.Marshal: %w", err)
+	}
+
+	return Message{AggregateID: aggregateID, Topic: topic, Payload: payload}, nil
+}
+
+// Schema returns the DDL creating the outbox table and its pending index,
+// for use in migrations (e.g. with the goose package).
+func Schema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        UUID NOT NULL,
+    aggregate_id    TEXT NOT NULL,
+    topic           TEXT NOT NULL,
+    key             TEXT NOT NULL DEFAULT '',
+    headers         JSONB,
+    payload         BYTEA NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at    TIMESTAMPTZ,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    dead_at         TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (id) WHERE published_at IS NULL AND dead_at IS NULL;`,
+		postgres.TableIdentifier(table).Sanitize(), postgres.IndexIdentifier(table, "pending").Sanitize())
+}
+
+// Store writes messages to the outbox table.
+type Store struct {
+	table   string
+	builder squirrel.StatementBuilderType
+}
+
+// NewStore creates a new Store.
+// Default configuration: "outbox" table.
+//
+// Example:
+//
+//	store := outbox.NewStore()
+//
+//	tx, err := pg.Pool.Begin(ctx)
+//	// ... business changes in tx ...
+//	msg, err := outbox.JSONMessage(order.ID, "orders.created", order)
+//	err = store.Enqueue(ctx, tx, msg)
+//	err = tx.Commit(ctx)
+func NewStore(opts ...Option) *Store {
+	cfg := newConfig(opts)
+
+	return &Store{
+		table:   cfg.table,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// CreateTable creates the outbox table if it does not exist.
+func (s *Store) CreateTable(ctx context.Context, db DBTX) error {
+	if _, err := db.Exec(ctx, Schema(s.table)); err != nil {
+		return fmt.Errorf("outbox - Store - CreateTable - db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Enqueue inserts messages into the outbox using db, which should be the transaction
+// of the business change so that messages are stored if and only if it commits.
+func (s *Store) Enqueue(ctx context.Context, db DBTX, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	q := s.builder.Insert(postgres.TableIdentifier(s.table).Sanitize()).
+		Columns("event_id", "aggregate_id", "topic", "key", "headers", "payload")
+
+	for _, m := range msgs {
+		var headers []byte
+
+		if len(m.Headers) > 0 {
+			var err error
+
+			headers, err = json.Marshal(m.Headers)
+			if err != nil {
+				return fmt.Errorf("outbox - Store - Enqueue - json.Marshal: %w", err)
+			}
+		}
+
+		key := m.Key
+		if key == "" {
+			key = m.AggregateID
+		}
+
+		q = q.Values(uuid.NewString(), m.AggregateID, m.Topic, key, headers, m.Payload)
+	}
+
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return fmt.Errorf("outbox - Store - Enqueue - q.ToSql: %w", err)
+	}
+
+	if _, err := db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("outbox - Store - Enqueue - db.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/eventbus"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// Metrics receives relay measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObservePublish is called for every publish attempt with the time the message
+	// spent in the outbox and the publish error.
+	ObservePublish(topic string, lag time.Duration, err error)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObservePublish(string, time.Duration, error) {}
+
+// record is a pending outbox row.
+type record struct {
+	id          int64
+	eventID     string
+	aggregateID string
+	topic       string
+	key         string
+	headers     map[string]string
+	payload     []byte
+	attempts    int
+	createdAt   time.Time
+}
+
+// Relay publishes pending outbox messages. Only one relay instance works at a time,
+// coordinated with a transaction level advisory lock, so instances can run on every
+// replica. Messages are published in insertion order; when a message fails, it is
+// retried with exponential backoff and later messages of the same aggregate are held
+// back until it is published, preserving per-aggregate ordering. A message failing its
+// last attempt is dead-lettered and no longer holds back its aggregate. Delivery is at
+// least once: a message may be published again if the relay stops between publishing
+// and committing, so consumers must be idempotent.
+type Relay struct {
+	pg        *postgres.Postgres
+	publisher eventbus.Publisher
+	cfg       config
+
+	notify chan error
+	stop   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	once   sync.Once
+}
+
+// NewRelay creates a new Relay publishing messages of the outbox table through publisher.
+// Default configuration: "outbox" table, batches of 100 messages, 1 second poll interval,
+// 10 attempts with exponential backoff from 1 second up to 5 minutes.
+//
+// Example:
+//
+//	relay := outbox.NewRelay(pg, bus, outbox.WithLogger(l))
+//	relay.Start()
+//	defer relay.Shutdown()
+func NewRelay(pg *postgres.Postgres, publisher eventbus.Publisher, opts ...Option) *Relay {
+	return &Relay{
+		pg:        pg,
+		publisher: publisher,
+		cfg:       newConfig(opts),
+		notify:    make(chan error, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start begins relaying messages in a separate goroutine.
+// Use Notify() to receive relay errors.
+func (r *Relay) Start() {
+	r.start.Do(func() {
+		go r.run()
+	})
+}
+
+// Notify returns a channel that receives relay errors, such as database failures.
+// Errors are dropped while a previous one has not been received.
+func (r *Relay) Notify() <-chan error {
+	return r.notify
+}
+
+// Shutdown stops the relay and waits for it to return. The current batch is aborted:
+// its transaction rolls back, so its messages, including those already published, are
+// published again by the next relay.
+func (r *Relay) Shutdown() error {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+
+	// Start may never have been called.
+	r.start.Do(func() { close(r.done) })
+
+	<-r.done
+
+	return nil
+}
+
+func (r *Relay) run() {
+	defer close(r.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			if r.cfg.logger != nil {
+				r.cfg.logger.Error(err)
+			}
+
+			select {
+			case r.notify <- err:
+			default:
+			}
+		}
+
+		// A full batch likely means more messages are pending.
+		if n == r.cfg.batchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.pollInterval):
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due messages and returns the number of messages
+// published. It returns 0 without error when another instance holds the relay lock.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("outbox - Relay - RelayOnce - r.pg.Pool.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	var locked bool
+
+	err = tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", "outbox:"+r.cfg.table).Scan(&locked)
+	if err != nil {
+		return 0, fmt.Errorf("outbox - Relay - RelayOnce - pg_try_advisory_xact_lock: %w", err)
+	}
+
+	if !locked {
+		return 0, nil
+	}
+
+	records, err := r.pending(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	published, failed := r.publish(ctx, records)
+
+	if err := r.markPublished(ctx, tx, published); err != nil {
+		return 0, err
+	}
+
+	dead, err := r.markFailed(ctx, tx, records, failed)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("outbox - Relay - RelayOnce - tx.Commit: %w", err)
+	}
+
+	for _, rec := range dead {
+		r.deadLetter(rec, failed[rec.id])
+	}
+
+	return len(published), nil
+}
+
+func (r *Relay) pending(ctx context.Context, tx pgx.Tx) ([]record, error) {
+	table := postgres.TableIdentifier(r.cfg.table).Sanitize()
+
+	// Messages following a message of their aggregate waiting for its retry are held back.
+	rows, err := tx.Query(ctx, fmt.Sprintf(
+		`SELECT id, event_id::text, aggregate_id, topic, key, headers, payload, attempts, created_at
+		FROM %[1]s m WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now()
+		AND NOT EXISTS (
+			SELECT 1 FROM %[1]s p WHERE p.aggregate_id = m.aggregate_id AND p.id < m.id
+			AND p.published_at IS NULL AND p.dead_at IS NULL AND p.next_attempt_at > now()
+		)
+		ORDER BY id LIMIT $1`, table), r.cfg.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("outbox - Relay - pending - tx.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var records []record
+
+	for rows.Next() {
+		var (
+			rec     record
+			headers []byte
+		)
+
+		err := rows.Scan(&rec.id, &rec.eventID, &rec.aggregateID, &rec.topic, &rec.key, &headers, &rec.payload, &rec.attempts, &rec.createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("outbox - Relay - pending - rows.Scan: %w", err)
+		}
+
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &rec.headers); err != nil {
+				return nil, fmt.Errorf("outbox - Relay - pending - json.Unmarshal: %w", err)
+			}
+		}
+
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("outbox - Relay - pending - rows.Err: %w", err)
+	}
+
+	return records, nil
+}
+
+// publish publishes records in order, skipping the remaining records of an aggregate
+// after one of its records failed. It returns the published IDs and the failures by ID.
+func (r *Relay) publish(ctx context.Context, records []record) (published []int64, failed map[int64]error) {
+	failed = make(map[int64]error)
+	blocked := make(map[string]bool)
+
+	for _, rec := range records {
+		if blocked[rec.aggregateID] {
+			continue
+		}
+
+		err := r.publisher.Publish(ctx, rec.event())
+
+		r.cfg.metrics.ObservePublish(rec.topic, time.Since(rec.createdAt), err)
+
+		if err != nil {
+			blocked[rec.aggregateID] = true
+			failed[rec.id] = err
+
+			if r.cfg.logger != nil {
+				r.cfg.logger.Warn("outbox - Relay - publish %s to %s: %v", rec.eventID, rec.topic, err)
+			}
+
+			continue
+		}
+
+		published = append(published, rec.id)
+	}
+
+	return published, failed
+}
+
+func (r *Relay) markPublished(ctx context.Context, tx pgx.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	table := postgres.TableIdentifier(r.cfg.table).Sanitize()
+
+	query := fmt.Sprintf("UPDATE %s SET published_at = now() WHERE id = ANY($1)", table)
+	if r.cfg.deleteOnPublish {
+		query = fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", table)
+	}
+
+	if _, err := tx.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("outbox - Relay - markPublished - tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// markFailed records the failed attempts, scheduling the next attempt with backoff or
+// dead-lettering the records out of attempts, which it returns.
+func (r *Relay) markFailed(ctx context.Context, tx pgx.Tx, records []record, failed map[int64]error) ([]record, error) {
+	table := postgres.TableIdentifier(r.cfg.table).Sanitize()
+
+	var (
+		dead []record
+		errs []error
+	)
+
+	for _, rec := range records {
+		publishErr, ok := failed[rec.id]
+		if !ok {
+			continue
+		}
+
+		attempts := rec.attempts + 1
+
+		var err error
+
+		if attempts >= r.cfg.maxAttempts {
+			_, err = tx.Exec(ctx, fmt.Sprintf(
+				"UPDATE %s SET attempts = $2, last_error = $3, dead_at = now() WHERE id = $1",
+				table), rec.id, attempts, publishErr.Error())
+
+			rec.attempts = attempts
+			dead = append(dead, rec)
+		} else {
+			_, err = tx.Exec(ctx, fmt.Sprintf(
+				`UPDATE %s SET attempts = $2, last_error = $3, next_attempt_at = now() + $4::interval
+				WHERE id = $1`, table), rec.id, attempts, publishErr.Error(), r.cfg.backoff(attempts))
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("outbox - Relay - markFailed - tx.Exec: %w", err)
+	}
+
+	return dead, nil
+}
+
+func (r *Relay) deadLetter(rec record, err error) {
+	if r.cfg.onDead != nil {
+		r.cfg.onDead(rec.event(), err)
+
+		return
+	}
+
+	if r.cfg.logger != nil {
+		r.cfg.logger.Error("outbox - Relay - message %s to %s dead after %d attempts: %v",
+			rec.eventID, rec.topic, rec.attempts, err)
+	}
+}
+
+func (rec *record) event() *eventbus.Event {
+	return &eventbus.Event{
+		ID:      rec.eventID,
+		Topic:   rec.topic,
+		Key:     rec.key,
+		Headers: rec.headers,
+		Payload: rec.payload,
+		Time:    rec.createdAt,
+	}
+}
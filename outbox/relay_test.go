@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/eventbus"
+)
+
+type failingPublisher struct {
+	fail      map[string]bool
+	published []string
+}
+
+func (p *failingPublisher) Publish(_ context.Context, e *eventbus.Event) error {
+	if p.fail[e.ID] {
+		return errors.New("broker unavailable")
+	}
+
+	p.published = append(p.published, e.ID)
+
+	return nil
+}
+
+func TestRelayPublishOrdering(t *testing.T) {
+	records := []record{
+		{id: 1, eventID: "a1", aggregateID: "a"},
+		{id: 2, eventID: "b1", aggregateID: "b"},
+		{id: 3, eventID: "a2", aggregateID: "a"},
+		{id: 4, eventID: "b2", aggregateID: "b"},
+		{id: 5, eventID: "a3", aggregateID: "a"},
+	}
+
+	tests := []struct {
+		name          string
+		fail          map[string]bool
+		wantPublished []int64
+		wantFailed    []int64
+		wantEvents    []string
+	}{
+		{
+			name:          "all published",
+			wantPublished: []int64{1, 2, 3, 4, 5},
+			wantEvents:    []string{"a1", "b1", "a2", "b2", "a3"},
+		},
+		{
+			name:          "failed aggregate held back",
+			fail:          map[string]bool{"a2": true},
+			wantPublished: []int64{1, 2, 4},
+			wantFailed:    []int64{3},
+			wantEvents:    []string{"a1", "b1", "b2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &failingPublisher{fail: tt.fail}
+			r := NewRelay(nil, p)
+
+			published, failed := r.publish(context.Background(), records)
+
+			if !slices.Equal(published, tt.wantPublished) {
+				t.Errorf("published = %v, want %v", published, tt.wantPublished)
+			}
+
+			if len(failed) != len(tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+
+			for _, id := range tt.wantFailed {
+				if failed[id] == nil {
+					t.Errorf("failed[%d] = nil, want error", id)
+				}
+			}
+
+			if !slices.Equal(p.published, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", p.published, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestRelayShutdownWithoutStart(t *testing.T) {
+	r := NewRelay(nil, nil)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- r.Shutdown()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() without Start blocked")
+	}
+
+	// Start after Shutdown does nothing, so the relay needs no database.
+	r.Start()
+
+	if err := r.Shutdown(); err != nil {
+		t.Errorf("second Shutdown() error = %v", err)
+	}
+}
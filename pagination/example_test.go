@@ -0,0 +1,21 @@
+package pagination_test
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rdashevsky/go-pkgs/pagination"
+)
+
+func ExampleKeyset() {
+	builder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	q, err := pagination.Keyset(builder.Select("id", "name").From("users"), pagination.PageRequest{Limit: 20}, false, "id")
+	if err != nil {
+		panic(err)
+	}
+
+	sql, _, _ := q.ToSql()
+	fmt.Println(sql)
+	// Output: SELECT id, name FROM users ORDER BY id ASC LIMIT 21
+}
@@ -0,0 +1,57 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FromQuery parses the "limit", "offset" and "cursor" query parameters of a request.
+// A missing limit defaults to DefaultLimit and limits above MaxLimit are capped.
+// Malformed values return ErrInvalidLimit, ErrInvalidOffset or ErrInvalidCursor.
+//
+// Example:
+//
+//	req, err := pagination.FromQuery(c, pagination.MaxLimit(50))
+//	if err != nil {
+//	    return response.Error(c, fiber.StatusBadRequest)
+//	}
+func FromQuery(ctx *fiber.Ctx, opts ...Option) (PageRequest, error) {
+	cfg := newConfig(opts)
+
+	p := PageRequest{
+		Limit:  cfg.defaultLimit,
+		Cursor: ctx.Query("cursor"),
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return PageRequest{}, ErrInvalidLimit
+		}
+
+		p.Limit = limit
+	}
+
+	if cfg.maxLimit > 0 && p.Limit > cfg.maxLimit {
+		p.Limit = cfg.maxLimit
+	}
+
+	if v := ctx.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return PageRequest{}, ErrInvalidOffset
+		}
+
+		p.Offset = offset
+	}
+
+	if p.Cursor != "" {
+		var values interface{}
+		if err := DecodeCursor(p.Cursor, &values); err != nil {
+			return PageRequest{}, err
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,38 @@
+package pagination
+
+// Option is a function that configures query parsing.
+type Option func(*config)
+
+type config struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		defaultLimit: _defaultLimit,
+		maxLimit:     _defaultMax,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// DefaultLimit sets the limit used when the request does not specify one.
+// Default is 20.
+func DefaultLimit(limit int) Option {
+	return func(c *config) {
+		c.defaultLimit = limit
+	}
+}
+
+// MaxLimit caps the requested limit; larger values are reduced to it.
+// Default is 100.
+func MaxLimit(limit int) Option {
+	return func(c *config) {
+		c.maxLimit = limit
+	}
+}
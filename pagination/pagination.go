@@ -0,0 +1,101 @@
+// Package pagination provides shared page request/response types, opaque base64 cursors
+// and helpers to apply pagination to postgres Builder queries and to parse it from
+// HTTP query parameters, so list endpoints paginate identically across services.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	_defaultLimit = 20
+	_defaultMax   = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("pagination - invalid cursor")
+	// ErrInvalidLimit is returned when the limit is not a positive integer.
+	ErrInvalidLimit = errors.New("pagination - invalid limit")
+	// ErrInvalidOffset is returned when the offset is not a non-negative integer.
+	ErrInvalidOffset = errors.New("pagination - invalid offset")
+)
+
+// PageRequest describes the requested page.
+// Cursor based pagination uses Cursor; offset based pagination uses Offset.
+type PageRequest struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// PageResponse is a page of items. NextCursor is empty on the last page.
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// EncodeCursor returns an opaque cursor holding v, typically the sort key of the last item.
+func EncodeCursor(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("pagination - EncodeCursor - json.Marshal: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor into v, which must be a pointer.
+// Returns ErrInvalidCursor if the cursor is malformed.
+func DecodeCursor(cursor string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// NewPage builds a page from items fetched with one row more than limit,
+// as done by Offset and Keyset. The extra row is dropped and signals a next page,
+// whose cursor is built from the last returned item by cursor.
+//
+// Example:
+//
+//	page, err := pagination.NewPage(users, req.Limit, func(u User) interface{} {
+//	    return []interface{}{u.CreatedAt, u.ID}
+//	})
+func NewPage[T any](items []T, limit int, cursor func(T) interface{}) (PageResponse[T], error) {
+	page := PageResponse[T]{Items: items}
+
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if limit <= 0 || len(items) <= limit {
+		return page, nil
+	}
+
+	page.Items = items[:limit]
+	page.HasMore = true
+
+	if cursor != nil {
+		next, err := EncodeCursor(cursor(page.Items[limit-1]))
+		if err != nil {
+			return PageResponse[T]{}, err
+		}
+
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
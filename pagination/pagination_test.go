@@ -0,0 +1,225 @@
+package pagination_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/pagination"
+)
+
+type user struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+func TestCursor(t *testing.T) {
+	type key struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	cursor, err := pagination.EncodeCursor(key{ID: 42, Name: "john"})
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	var got key
+	if err := pagination.DecodeCursor(cursor, &got); err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if got.ID != 42 || got.Name != "john" {
+		t.Errorf("DecodeCursor() = %+v", got)
+	}
+
+	for _, invalid := range []string{"!!!", "bm90LWpzb24"} {
+		if err := pagination.DecodeCursor(invalid, &got); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", invalid, err)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	items := []user{{ID: 1}, {ID: 2}, {ID: 3}}
+	cursorOf := func(u user) interface{} { return []interface{}{u.ID} }
+
+	tests := []struct {
+		name       string
+		items      []user
+		limit      int
+		wantItems  int
+		wantMore   bool
+		wantCursor bool
+	}{
+		{name: "extra row", items: items, limit: 2, wantItems: 2, wantMore: true, wantCursor: true},
+		{name: "last page", items: items, limit: 3, wantItems: 3},
+		{name: "empty", items: nil, limit: 3, wantItems: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := pagination.NewPage(tt.items, tt.limit, cursorOf)
+			if err != nil {
+				t.Fatalf("NewPage() error = %v", err)
+			}
+
+			if len(page.Items) != tt.wantItems || page.HasMore != tt.wantMore || (page.NextCursor != "") != tt.wantCursor {
+				t.Errorf("NewPage() = %+v", page)
+			}
+
+			if page.Items == nil {
+				t.Error("NewPage() items = nil, want empty slice")
+			}
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	b := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).Select("id").From("users").OrderBy("id")
+
+	sql, _, err := pagination.Offset(b, pagination.PageRequest{Limit: 10, Offset: 20}).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	if want := "SELECT id FROM users ORDER BY id LIMIT 11 OFFSET 20"; sql != want {
+		t.Errorf("Offset() sql = %q, want %q", sql, want)
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	b := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).Select("id").From("users")
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	page, err := pagination.NewPage([]user{{ID: 7, CreatedAt: created}, {ID: 8}}, 1, func(u user) interface{} {
+		return []interface{}{u.CreatedAt, u.ID}
+	})
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		req      pagination.PageRequest
+		desc     bool
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:    "first page",
+			req:     pagination.PageRequest{Limit: 10},
+			wantSQL: "SELECT id FROM users ORDER BY created_at ASC, id ASC LIMIT 11",
+		},
+		{
+			name:     "next page descending",
+			req:      pagination.PageRequest{Limit: 1, Cursor: page.NextCursor},
+			desc:     true,
+			wantSQL:  "SELECT id FROM users WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT 2",
+			wantArgs: []interface{}{"2024-01-02T03:04:05Z", int64(7)},
+		},
+		{
+			name:    "column count mismatch",
+			req:     pagination.PageRequest{Limit: 1, Cursor: mustCursor(t, []interface{}{1})},
+			wantErr: pagination.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := pagination.Keyset(b, tt.req, tt.desc, "created_at", "id")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Keyset() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Keyset() error = %v", err)
+			}
+
+			sql, args, err := q.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+
+			if sql != tt.wantSQL {
+				t.Errorf("Keyset() sql = %q, want %q", sql, tt.wantSQL)
+			}
+
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Keyset() args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func mustCursor(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	c, err := pagination.EncodeCursor(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    pagination.PageRequest
+		wantErr error
+	}{
+		{name: "defaults", query: "", want: pagination.PageRequest{Limit: 20}},
+		{name: "limit and offset", query: "?limit=5&offset=10", want: pagination.PageRequest{Limit: 5, Offset: 10}},
+		{name: "capped limit", query: "?limit=1000", want: pagination.PageRequest{Limit: 50}},
+		{name: "cursor", query: "?cursor=WzFd", want: pagination.PageRequest{Limit: 20, Cursor: "WzFd"}},
+		{name: "invalid limit", query: "?limit=0", wantErr: pagination.ErrInvalidLimit},
+		{name: "invalid offset", query: "?offset=-1", wantErr: pagination.ErrInvalidOffset},
+		{name: "invalid cursor", query: "?cursor=%21", wantErr: pagination.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got pagination.PageRequest
+				err error
+			)
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got, err = pagination.FromQuery(c, pagination.MaxLimit(50))
+
+				return nil
+			})
+
+			resp, testErr := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil))
+			if testErr != nil {
+				t.Fatalf("app.Test() error = %v", testErr)
+			}
+			resp.Body.Close()
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FromQuery() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("FromQuery() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
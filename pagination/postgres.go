@@ -0,0 +1,104 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/goccy/go-json"
+)
+
+// Offset applies offset pagination to a select query, fetching one row more
+// than the limit so NewPage can detect a next page.
+//
+// Example:
+//
+//	q := pagination.Offset(pg.Builder.Select("id", "name").From("users").OrderBy("id"), req)
+func Offset(b squirrel.SelectBuilder, p PageRequest) squirrel.SelectBuilder {
+	b = b.Limit(uint64(p.Limit) + 1)
+
+	if p.Offset > 0 {
+		b = b.Offset(uint64(p.Offset))
+	}
+
+	return b
+}
+
+// Keyset applies keyset (seek) pagination to a select query ordered by columns,
+// which must identify rows uniquely, e.g. ("created_at", "id"). The request cursor
+// must hold the column values of the last row of the previous page, in order, as
+// built by NewPage. Rows are sorted ascending, or descending when desc is set, and
+// one row more than the limit is fetched so NewPage can detect a next page.
+//
+// Example:
+//
+//	q, err := pagination.Keyset(pg.Builder.Select("*").From("users"), req, false, "created_at", "id")
+func Keyset(b squirrel.SelectBuilder, p PageRequest, desc bool, columns ...string) (squirrel.SelectBuilder, error) {
+	if len(columns) == 0 {
+		return b, fmt.Errorf("pagination - Keyset - no columns")
+	}
+
+	direction, op := " ASC", ">"
+	if desc {
+		direction, op = " DESC", "<"
+	}
+
+	if p.Cursor != "" {
+		values, err := keysetValues(p.Cursor)
+		if err != nil {
+			return b, err
+		}
+
+		if len(values) != len(columns) {
+			return b, fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(columns), len(values))
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+
+		b = b.Where(squirrel.Expr(
+			fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, placeholders),
+			values...,
+		))
+	}
+
+	order := make([]string, 0, len(columns))
+	for _, c := range columns {
+		order = append(order, c+direction)
+	}
+
+	return b.OrderBy(order...).Limit(uint64(p.Limit) + 1), nil
+}
+
+// keysetValues decodes the cursor column values, keeping integers as int64
+// instead of float64 so they bind to integer columns.
+func keysetValues(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var values []interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	for i, v := range values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+
+		if iv, err := n.Int64(); err == nil {
+			values[i] = iv
+		} else if fv, err := n.Float64(); err == nil {
+			values[i] = fv
+		}
+	}
+
+	return values, nil
+}
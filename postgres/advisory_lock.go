@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// TryAdvisoryLock attempts to acquire a session-level advisory lock identified by key
+// without blocking. The key is mapped to a lock ID with hashtext, so any string can be used.
+//
+// Advisory locks belong to a database session, so a pool connection is held until unlock
+// is called; unlock must always be called when acquired is true.
+// When the lock is already held elsewhere, acquired is false and unlock is nil.
+//
+// Example:
+//
+//	unlock, acquired, err := pg.TryAdvisoryLock(ctx, "reports:nightly")
+//	if err != nil || !acquired {
+//	    return err
+//	}
+//	defer unlock(context.Background())
+func (p *Postgres) TryAdvisoryLock(ctx context.Context, key string) (unlock func(context.Context) error, acquired bool, err error) {
+	conn, err := p.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("postgres - TryAdvisoryLock - p.Pool.Acquire: %w", err)
+	}
+
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", key).Scan(&acquired)
+	if err != nil {
+		conn.Release()
+
+		return nil, false, fmt.Errorf("postgres - TryAdvisoryLock - pg_try_advisory_lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Release()
+
+		return nil, false, nil
+	}
+
+	unlock = func(ctx context.Context) error {
+		defer conn.Release()
+
+		_, err := conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtext($1))", key)
+		if err != nil {
+			// Closing the session is the only other way to release the lock.
+			_ = conn.Conn().Close(ctx)
+
+			return fmt.Errorf("postgres - TryAdvisoryLock - pg_advisory_unlock: %w", err)
+		}
+
+		return nil
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrEmptyBatch is returned by SendBatch and ExecBuilders when no statement is queued.
+var ErrEmptyBatch = errors.New("postgres - empty batch")
+
+// Batch queues statements sent to the server in one round trip by SendBatch.
+type Batch struct {
+	batch pgx.Batch
+	sql   []string
+	err   error
+}
+
+// Queue adds a statement to the batch.
+func (b *Batch) Queue(sql string, args ...any) {
+	b.batch.Queue(sql, args...)
+	b.sql = append(b.sql, sql)
+}
+
+// QueueBuilder adds a statement built with Squirrel, e.g. an InsertBuilder, with
+// dollar placeholders. An error building it fails SendBatch before anything is sent.
+func (b *Batch) QueueBuilder(s squirrel.Sqlizer) {
+	sql, args, err := s.ToSql()
+	if err == nil {
+		sql, err = squirrel.Dollar.ReplacePlaceholders(sql)
+	}
+
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("statement %d: %w", len(b.sql), err)
+		}
+
+		b.sql = append(b.sql, "")
+
+		return
+	}
+
+	b.Queue(sql, args...)
+}
+
+// Len returns the number of queued statements.
+func (b *Batch) Len() int {
+	return len(b.sql)
+}
+
+// BatchError reports the first statement of a batch that failed. The batch runs in
+// an implicit transaction, so none of its statements took effect.
+type BatchError struct {
+	// Index is the position of the statement in the batch.
+	Index int
+	SQL   string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("postgres - batch statement %d (%s): %v", e.Index, e.SQL, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// SendBatch queues statements with fn and executes them in one round trip, in an
+// implicit transaction unless they manage transactions themselves. It returns the
+// command tag of each statement, or an error wrapping a *BatchError identifying the
+// statement that failed.
+//
+// Example:
+//
+//	tags, err := pg.SendBatch(ctx, func(b *postgres.Batch) {
+//	    for _, e := range events {
+//	        b.Queue("INSERT INTO events (id, payload) VALUES ($1, $2)", e.ID, e.Payload)
+//	    }
+//	})
+//
+//	var batchErr *postgres.BatchError
+//	if errors.As(err, &batchErr) {
+//	    log.Printf("event %s rejected: %v", events[batchErr.Index].ID, batchErr.Err)
+//	}
+func (p *Postgres) SendBatch(ctx context.Context, fn func(b *Batch)) ([]pgconn.CommandTag, error) {
+	var b Batch
+
+	fn(&b)
+
+	if b.err != nil {
+		return nil, fmt.Errorf("postgres - SendBatch - QueueBuilder: %w", b.err)
+	}
+
+	if b.Len() == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+
+	results := p.Pool.SendBatch(ctx, &b.batch)
+
+	tags := make([]pgconn.CommandTag, 0, b.Len())
+
+	for i, sql := range b.sql {
+		tag, err := results.Exec()
+		if err != nil {
+			_ = results.Close()
+
+			return tags, fmt.Errorf("postgres - SendBatch - results.Exec: %w", &BatchError{Index: i, SQL: sql, Err: err})
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := results.Close(); err != nil {
+		return tags, fmt.Errorf("postgres - SendBatch - results.Close: %w", err)
+	}
+
+	return tags, nil
+}
+
+// ExecBuilders executes statements built with Squirrel in one batch, as SendBatch.
+//
+// Example:
+//
+//	tags, err := pg.ExecBuilders(ctx,
+//	    pg.Builder.Update("accounts").Set("balance", squirrel.Expr("balance - ?", amount)).Where(squirrel.Eq{"id": from}),
+//	    pg.Builder.Update("accounts").Set("balance", squirrel.Expr("balance + ?", amount)).Where(squirrel.Eq{"id": to}),
+//	)
+func (p *Postgres) ExecBuilders(ctx context.Context, builders ...squirrel.Sqlizer) ([]pgconn.CommandTag, error) {
+	return p.SendBatch(ctx, func(b *Batch) {
+		for _, s := range builders {
+			b.QueueBuilder(s)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHost = "localhost"
+	defaultPort = 5432
+)
+
+// Config describes a PostgreSQL connection without hand-building its URL.
+// Zero values keep the defaults of New and of the server.
+type Config struct {
+	// Host is the server host. Default is localhost.
+	Host string
+	// Port is the server port. Default is 5432.
+	Port     int
+	Database string
+	User     string
+	Password string
+
+	// SSLMode is the libpq sslmode: disable, allow, prefer, require, verify-ca or verify-full.
+	// Default is prefer.
+	SSLMode string
+	// SSLRootCert, SSLCert and SSLKey are paths to the CA certificate verifying the server
+	// and to the certificate and key of the client.
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+
+	// MaxPoolSize and MinPoolSize bound the number of connections in the pool.
+	MaxPoolSize int
+	MinPoolSize int
+	// MaxConnLifetime and MaxConnIdleTime close connections older or idle for longer.
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+
+	// ConnAttempts and ConnTimeout are the number of connection attempts and the wait
+	// between them, see the options of the same name.
+	ConnAttempts int
+	ConnTimeout  time.Duration
+	// ConnectTimeout bounds dialing a single connection.
+	ConnectTimeout time.Duration
+
+	// Params are extra connection parameters, e.g. application_name or search_path.
+	Params map[string]string
+}
+
+// URL returns the connection URL of the configuration, e.g. for migration tools.
+func (c Config) URL() string {
+	host := c.Host
+	if host == "" {
+		host = defaultHost
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = defaultPort
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + c.Database,
+	}
+
+	if c.User != "" {
+		if c.Password != "" {
+			u.User = url.UserPassword(c.User, c.Password)
+		} else {
+			u.User = url.User(c.User)
+		}
+	}
+
+	q := url.Values{}
+
+	for k, v := range c.Params {
+		q.Set(k, v)
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+
+	set("sslmode", c.SSLMode)
+	set("sslrootcert", c.SSLRootCert)
+	set("sslcert", c.SSLCert)
+	set("sslkey", c.SSLKey)
+
+	if c.MinPoolSize > 0 {
+		q.Set("pool_min_conns", strconv.Itoa(c.MinPoolSize))
+	}
+
+	if c.MaxConnLifetime > 0 {
+		q.Set("pool_max_conn_lifetime", c.MaxConnLifetime.String())
+	}
+
+	if c.MaxConnIdleTime > 0 {
+		q.Set("pool_max_conn_idle_time", c.MaxConnIdleTime.String())
+	}
+
+	if c.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds, rounded up as zero disables it.
+		q.Set("connect_timeout", strconv.Itoa(int((c.ConnectTimeout+time.Second-1)/time.Second)))
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func (c Config) options() []Option {
+	var opts []Option
+
+	if c.MaxPoolSize > 0 {
+		opts = append(opts, MaxPoolSize(c.MaxPoolSize))
+	}
+
+	if c.ConnAttempts > 0 {
+		opts = append(opts, ConnAttempts(c.ConnAttempts))
+	}
+
+	if c.ConnTimeout > 0 {
+		opts = append(opts, ConnTimeout(c.ConnTimeout))
+	}
+
+	return opts
+}
+
+// NewFromConfig is like New with the URL and the pool settings of cfg. Options take
+// precedence over the fields of cfg.
+//
+// Example:
+//
+//	cfg, err := postgres.ConfigFromEnv("PG")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	pg, err := postgres.NewFromConfig(cfg, postgres.WithMetrics(prometheus.DefaultRegisterer))
+func NewFromConfig(cfg Config, opts ...Option) (*Postgres, error) {
+	return New(cfg.URL(), append(cfg.options(), opts...)...)
+}
+
+// ConfigFromEnv reads a Config from environment variables named after the fields with
+// the prefix and an underscore, e.g. PG_HOST for the prefix "PG":
+//
+//	HOST, PORT, DB, USER, PASSWORD,
+//	SSLMODE, SSLROOTCERT, SSLCERT, SSLKEY,
+//	MAX_POOL_SIZE, MIN_POOL_SIZE, MAX_CONN_LIFETIME, MAX_CONN_IDLE_TIME,
+//	CONN_ATTEMPTS, CONN_TIMEOUT, CONNECT_TIMEOUT, APPLICATION_NAME
+//
+// Durations use the format of time.ParseDuration, e.g. "30s". Unset variables leave
+// fields zero; malformed numbers and durations are an error.
+func ConfigFromEnv(prefix string) (Config, error) {
+	env := envReader{prefix: prefix}
+
+	cfg := Config{
+		Host:            env.string("HOST"),
+		Port:            env.int("PORT"),
+		Database:        env.string("DB"),
+		User:            env.string("USER"),
+		Password:        env.string("PASSWORD"),
+		SSLMode:         env.string("SSLMODE"),
+		SSLRootCert:     env.string("SSLROOTCERT"),
+		SSLCert:         env.string("SSLCERT"),
+		SSLKey:          env.string("SSLKEY"),
+		MaxPoolSize:     env.int("MAX_POOL_SIZE"),
+		MinPoolSize:     env.int("MIN_POOL_SIZE"),
+		MaxConnLifetime: env.duration("MAX_CONN_LIFETIME"),
+		MaxConnIdleTime: env.duration("MAX_CONN_IDLE_TIME"),
+		ConnAttempts:    env.int("CONN_ATTEMPTS"),
+		ConnTimeout:     env.duration("CONN_TIMEOUT"),
+		ConnectTimeout:  env.duration("CONNECT_TIMEOUT"),
+	}
+
+	if name := env.string("APPLICATION_NAME"); name != "" {
+		cfg.Params = map[string]string{"application_name": name}
+	}
+
+	if env.err != nil {
+		return Config{}, fmt.Errorf("postgres - ConfigFromEnv: %w", env.err)
+	}
+
+	return cfg, nil
+}
+
+// envReader reads prefixed variables, keeping the first parse error.
+type envReader struct {
+	prefix string
+	err    error
+}
+
+func (r *envReader) key(name string) string {
+	if r.prefix == "" {
+		return name
+	}
+
+	return r.prefix + "_" + name
+}
+
+func (r *envReader) string(name string) string {
+	return os.Getenv(r.key(name))
+}
+
+func (r *envReader) int(name string) int {
+	value := r.string(name)
+	if value == "" || r.err != nil {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		r.err = fmt.Errorf("%s: %w", r.key(name), err)
+	}
+
+	return n
+}
+
+func (r *envReader) duration(name string) time.Duration {
+	value := r.string(name)
+	if value == "" || r.err != nil {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.err = fmt.Errorf("%s: %w", r.key(name), err)
+	}
+
+	return d
+}
@@ -0,0 +1,125 @@
+package postgres_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+func TestConfig_URL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  postgres.Config
+		want string
+	}{
+		{
+			name: "defaults",
+			cfg:  postgres.Config{Database: "app"},
+			want: "postgres://localhost:5432/app",
+		},
+		{
+			name: "escaped credentials",
+			cfg:  postgres.Config{Host: "db", Port: 6432, Database: "app", User: "svc", Password: "p@ss/w:rd"},
+			want: "postgres://svc:p%40ss%2Fw%3Ard@db:6432/app",
+		},
+		{
+			name: "ipv6 host",
+			cfg:  postgres.Config{Host: "::1", Database: "app"},
+			want: "postgres://[::1]:5432/app",
+		},
+		{
+			name: "parameters",
+			cfg: postgres.Config{
+				Database:        "app",
+				SSLMode:         "verify-full",
+				SSLRootCert:     "/etc/ca.pem",
+				MinPoolSize:     2,
+				MaxConnLifetime: time.Hour,
+				ConnectTimeout:  1500 * time.Millisecond,
+				Params:          map[string]string{"application_name": "orders"},
+			},
+			want: "postgres://localhost:5432/app?application_name=orders&connect_timeout=2&pool_max_conn_lifetime=1h0m0s&pool_min_conns=2&sslmode=verify-full&sslrootcert=%2Fetc%2Fca.pem",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.URL(); got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+
+			if _, err := url.Parse(tt.cfg.URL()); err != nil {
+				t.Errorf("URL() is not a valid URL: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("PG_HOST", "db.internal")
+	t.Setenv("PG_PORT", "6432")
+	t.Setenv("PG_DB", "orders")
+	t.Setenv("PG_USER", "svc")
+	t.Setenv("PG_PASSWORD", "secret")
+	t.Setenv("PG_SSLMODE", "require")
+	t.Setenv("PG_MAX_POOL_SIZE", "20")
+	t.Setenv("PG_CONN_TIMEOUT", "2s")
+	t.Setenv("PG_APPLICATION_NAME", "orders-api")
+
+	cfg, err := postgres.ConfigFromEnv("PG")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+
+	want := postgres.Config{
+		Host:        "db.internal",
+		Port:        6432,
+		Database:    "orders",
+		User:        "svc",
+		Password:    "secret",
+		SSLMode:     "require",
+		MaxPoolSize: 20,
+		ConnTimeout: 2 * time.Second,
+		Params:      map[string]string{"application_name": "orders-api"},
+	}
+
+	if cfg.URL() != want.URL() || cfg.MaxPoolSize != want.MaxPoolSize || cfg.ConnTimeout != want.ConnTimeout {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv("PG_PORT", "five")
+
+	if _, err := postgres.ConfigFromEnv("PG"); err == nil {
+		t.Error("ConfigFromEnv() expected an error for a malformed port")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	pg, err := postgres.NewFromConfig(postgres.Config{
+		Host:         "127.0.0.1",
+		Port:         65432,
+		Database:     "testdb",
+		User:         "user",
+		MaxPoolSize:  8,
+		MinPoolSize:  2,
+		ConnAttempts: 1,
+		ConnTimeout:  10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Skipf("pool creation failed: %v", err)
+	}
+	defer pg.Close()
+
+	cfg := pg.Pool.Config()
+	if cfg.MaxConns != 8 || cfg.MinConns != 2 {
+		t.Errorf("pool size = %d..%d, want 2..8", cfg.MinConns, cfg.MaxConns)
+	}
+
+	if cfg.ConnConfig.Database != "testdb" || cfg.ConnConfig.Port != 65432 {
+		t.Errorf("connection = %s:%d, want testdb:65432", cfg.ConnConfig.Database, cfg.ConnConfig.Port)
+	}
+}
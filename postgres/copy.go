@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CopyFrom bulk loads rows into the columns of table with the COPY protocol, which is
+// much faster than INSERT statements for large datasets. The table may be qualified
+// with its schema, e.g. "audit.events". It returns the number of rows copied.
+//
+// Example:
+//
+//	n, err := pg.CopyFrom(ctx, "users", []string{"name", "email"}, [][]any{
+//	    {"Alice", "alice@example.com"},
+//	    {"Bob", "bob@example.com"},
+//	})
+func (p *Postgres) CopyFrom(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+
+	n, err := p.Pool.CopyFrom(ctx, TableIdentifier(table), columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return n, fmt.Errorf("postgres - CopyFrom - p.Pool.CopyFrom: %w", err)
+	}
+
+	return n, nil
+}
+
+// CopyFromFunc is like CopyFrom for datasets too large to hold in memory: next is
+// called for each row until it returns a nil row or an error, which aborts the copy.
+// Rows must not be reused after next returns them.
+//
+// Example:
+//
+//	scanner := bufio.NewScanner(file)
+//	n, err := pg.CopyFromFunc(ctx, "events", []string{"payload"}, func() ([]any, error) {
+//	    if !scanner.Scan() {
+//	        return nil, scanner.Err()
+//	    }
+//
+//	    return []any{scanner.Text()}, nil
+//	})
+func (p *Postgres) CopyFromFunc(ctx context.Context, table string, columns []string, next func() ([]any, error)) (int64, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+
+	n, err := p.Pool.CopyFrom(ctx, TableIdentifier(table), columns, pgx.CopyFromFunc(next))
+	if err != nil {
+		return n, fmt.Errorf("postgres - CopyFromFunc - p.Pool.CopyFrom: %w", err)
+	}
+
+	return n, nil
+}
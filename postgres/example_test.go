@@ -325,6 +325,41 @@ func ExamplePostgres_Builder_upsert() {
 	// Args: [1 theme dark 1 language en]
 }
 
+// ExampleUpsert demonstrates building UPSERT queries with the Upsert builder
+func ExampleUpsert() {
+	// UPSERT with ON CONFLICT
+	query, args, err := postgres.Upsert("users").
+		Columns("email", "name").
+		Values("john@example.com", "John Doe").
+		OnConflict("email").
+		UpdateAll().
+		Set("updated_at", squirrel.Expr("NOW()")).
+		ToSql()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Query: %s\nArgs: %v\n", query, args)
+
+	// UPSERT with DO NOTHING
+	query2, args2, err := postgres.Upsert("user_preferences").
+		Columns("user_id", "preference_key", "preference_value").
+		Values(1, "theme", "dark").
+		Values(1, "language", "en").
+		OnConflict("user_id", "preference_key").
+		DoNothing().
+		ToSql()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Query: %s\nArgs: %v\n", query2, args2)
+
+	// Output:
+	// Query: INSERT INTO users (email,name) VALUES ($1,$2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW()
+	// Args: [john@example.com John Doe]
+	// Query: INSERT INTO user_preferences (user_id,preference_key,preference_value) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (user_id, preference_key) DO NOTHING
+	// Args: [1 theme dark 1 language en]
+}
+
 // ExamplePostgres_Builder_subqueries demonstrates building queries with subqueries
 func ExamplePostgres_Builder_subqueries() {
 	pg := &postgres.Postgres{}
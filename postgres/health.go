@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotConnected is returned by Ping and HealthCheck when New was told not to connect.
+var ErrNotConnected = errors.New("postgres - not connected")
+
+// Status describes the pool at the time of a health check.
+type Status struct {
+	// Latency is the duration of the ping and of the health query, if any.
+	Latency time.Duration
+	// AcquiredConns, IdleConns and TotalConns count the connections of the pool by
+	// state; MaxConns is its size.
+	AcquiredConns int32
+	IdleConns     int32
+	TotalConns    int32
+	MaxConns      int32
+}
+
+// Ping acquires a connection and checks that the server responds.
+func (p *Postgres) Ping(ctx context.Context) error {
+	if p.Pool == nil {
+		return ErrNotConnected
+	}
+
+	if err := p.Pool.Ping(ctx); err != nil {
+		return fmt.Errorf("postgres - Ping - p.Pool.Ping: %w", err)
+	}
+
+	return nil
+}
+
+// HealthCheck pings the server, runs the HealthQuery option if set, and reports the
+// pool statistics. Statistics are returned even when the check fails, for readiness
+// probes to report saturation.
+//
+// Example:
+//
+//	app.Get("/ready", func(c *fiber.Ctx) error {
+//	    status, err := pg.HealthCheck(c.UserContext())
+//	    if err != nil {
+//	        return c.Status(fiber.StatusServiceUnavailable).JSON(status)
+//	    }
+//
+//	    return c.JSON(status)
+//	})
+func (p *Postgres) HealthCheck(ctx context.Context) (Status, error) {
+	if p.Pool == nil {
+		return Status{}, ErrNotConnected
+	}
+
+	start := time.Now()
+
+	err := p.Ping(ctx)
+	if err == nil && p.healthQuery != "" {
+		if _, qErr := p.Pool.Exec(ctx, p.healthQuery); qErr != nil {
+			err = fmt.Errorf("postgres - HealthCheck - p.Pool.Exec: %w", qErr)
+		}
+	}
+
+	stat := p.Pool.Stat()
+
+	return Status{
+		Latency:       time.Since(start),
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		TotalConns:    stat.TotalConns(),
+		MaxConns:      stat.MaxConns(),
+	}, err
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TableIdentifier splits a table name optionally qualified with its schema
+// ("schema.table"). Sanitize it to quote the name in SQL.
+//
+// Example:
+//
+//	query := fmt.Sprintf("SELECT count(*) FROM %s", postgres.TableIdentifier("app.outbox").Sanitize())
+func TableIdentifier(table string) pgx.Identifier {
+	return pgx.Identifier(strings.Split(table, "."))
+}
+
+// IndexIdentifier returns the identifier of the "<table>_<suffix>_idx" index of a table
+// optionally qualified with its schema. Indexes are created in the schema of their
+// table, so the name is not qualified.
+func IndexIdentifier(table, suffix string) pgx.Identifier {
+	parts := strings.Split(table, ".")
+
+	return pgx.Identifier{parts[len(parts)-1] + "_" + suffix + "_idx"}
+}
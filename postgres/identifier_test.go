@@ -0,0 +1,30 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+func TestTableIdentifier(t *testing.T) {
+	tests := []struct {
+		table     string
+		wantTable string
+		wantIndex string
+	}{
+		{table: "outbox", wantTable: `"outbox"`, wantIndex: `"outbox_pending_idx"`},
+		{table: "app.outbox", wantTable: `"app"."outbox"`, wantIndex: `"outbox_pending_idx"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if got := postgres.TableIdentifier(tt.table).Sanitize(); got != tt.wantTable {
+				t.Errorf("TableIdentifier() = %s, want %s", got, tt.wantTable)
+			}
+
+			if got := postgres.IndexIdentifier(tt.table, "pending").Sanitize(); got != tt.wantIndex {
+				t.Errorf("IndexIdentifier() = %s, want %s", got, tt.wantIndex)
+			}
+		})
+	}
+}
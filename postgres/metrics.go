@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WithMetrics registers Prometheus metrics of the pool on r: connections by state,
+// acquire counts and wait time, and the duration of queries by operation and status.
+// Register one pool per registerer, or tell pools apart with
+// prometheus.WrapRegistererWith. A nil registerer is ignored.
+// Default is no metrics.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.WithMetrics(prometheus.DefaultRegisterer))
+func WithMetrics(r prometheus.Registerer) Option {
+	return func(c *Postgres) {
+		if r != nil {
+			c.registerer = r
+		}
+	}
+}
+
+// registerMetrics registers the query histogram and the pool collector on r and
+// returns the tracer observing queries.
+func (p *Postgres) registerMetrics(r prometheus.Registerer) (pgx.QueryTracer, error) {
+	queries := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "postgres_query_duration_seconds",
+		Help:    "Duration of PostgreSQL queries.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "status"})
+
+	if err := r.Register(queries); err != nil {
+		return nil, fmt.Errorf("postgres - registerMetrics - r.Register: %w", err)
+	}
+
+	if err := r.Register(newPoolCollector(p)); err != nil {
+		r.Unregister(queries)
+
+		return nil, fmt.Errorf("postgres - registerMetrics - r.Register: %w", err)
+	}
+
+	return queryMetrics{queries: queries}, nil
+}
+
+type poolCollector struct {
+	p *Postgres
+
+	acquired         *prometheus.Desc
+	idle             *prometheus.Desc
+	constructing     *prometheus.Desc
+	total            *prometheus.Desc
+	max              *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireWait      *prometheus.Desc
+}
+
+func newPoolCollector(p *Postgres) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("postgres_pool_"+name, help, nil, nil)
+	}
+
+	return &poolCollector{
+		p:                p,
+		acquired:         desc("acquired_connections", "Connections currently in use."),
+		idle:             desc("idle_connections", "Idle connections in the pool."),
+		constructing:     desc("constructing_connections", "Connections being established."),
+		total:            desc("total_connections", "Connections in the pool."),
+		max:              desc("max_connections", "Maximum size of the pool."),
+		acquires:         desc("acquires_total", "Connections acquired from the pool."),
+		emptyAcquires:    desc("empty_acquires_total", "Acquires that waited for a connection because the pool was empty."),
+		canceledAcquires: desc("canceled_acquires_total", "Acquires canceled by their context."),
+		acquireWait:      desc("acquire_wait_seconds_total", "Time spent acquiring connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.emptyAcquires, c.canceledAcquires, c.acquireWait,
+	} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.p.Pool == nil {
+		return
+	}
+
+	s := c.p.Stats()
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquired, float64(s.AcquiredConns))
+	gauge(c.idle, float64(s.IdleConns))
+	gauge(c.constructing, float64(s.ConstructingConns))
+	gauge(c.total, float64(s.TotalConns))
+	gauge(c.max, float64(s.MaxConns))
+	counter(c.acquires, float64(s.AcquireCount))
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount))
+	counter(c.canceledAcquires, float64(s.CanceledAcquireCount))
+	counter(c.acquireWait, s.AcquireDuration.Seconds())
+}
+
+type queryMetricsKey struct{}
+
+type queryMetricsStart struct {
+	start     time.Time
+	operation string
+}
+
+type queryMetrics struct {
+	queries *prometheus.HistogramVec
+}
+
+func (m queryMetrics) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryMetricsKey{}, queryMetricsStart{start: time.Now(), operation: Operation(data.SQL)})
+}
+
+func (m queryMetrics) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(queryMetricsKey{}).(queryMetricsStart)
+	if !ok {
+		return
+	}
+
+	status := "ok"
+	if data.Err != nil {
+		status = "error"
+	}
+
+	m.queries.WithLabelValues(q.operation, status).Observe(time.Since(q.start).Seconds())
+}
@@ -0,0 +1,212 @@
+// Package mocks provides an in-memory postgres.PoolI for unit testing repositories
+// without a database. Statements are answered by handlers registered per SQL text and
+// every call is recorded for assertions.
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// ErrUnexpected is returned for statements without a registered handler.
+var ErrUnexpected = errors.New("mocks - unexpected statement")
+
+// ErrNotSupported is returned by the transaction methods the fake does not implement,
+// such as CopyFrom, SendBatch and Prepare.
+var ErrNotSupported = errors.New("mocks - not supported")
+
+// ExecFunc answers an Exec call with the arguments of the statement.
+type ExecFunc func(args []any) (pgconn.CommandTag, error)
+
+// QueryFunc answers a Query or QueryRow call with the arguments of the statement,
+// e.g. with rows created by NewRows.
+type QueryFunc func(args []any) (pgx.Rows, error)
+
+// Call records a statement run through the pool or one of its transactions.
+type Call struct {
+	SQL  string
+	Args []any
+	// Tx is the transaction of the statement, nil outside of one.
+	Tx *Tx
+}
+
+// Pool is a fake postgres.PoolI. Statements are matched to handlers by their SQL with
+// whitespace collapsed, so that builder output and hand-written SQL compare equal.
+// It is safe for concurrent use.
+type Pool struct {
+	mu       sync.Mutex
+	execs    map[string]ExecFunc
+	queries  map[string]QueryFunc
+	beginErr error
+	calls    []Call
+	txs      []*Tx
+}
+
+var _ postgres.PoolI = (*Pool)(nil)
+
+// NewPool creates a Pool without handlers.
+//
+// Example:
+//
+//	pool := mocks.NewPool()
+//	pool.OnQuery("SELECT id, name FROM users WHERE id = $1", func(args []any) (pgx.Rows, error) {
+//	    return mocks.NewRows([]string{"id", "name"}, []any{args[0], "alice"}), nil
+//	})
+//
+//	repo := &UserRepository{db: pool}
+func NewPool() *Pool {
+	return &Pool{
+		execs:   make(map[string]ExecFunc),
+		queries: make(map[string]QueryFunc),
+	}
+}
+
+// OnExec registers the handler of Exec calls with the statement, replacing any previous one.
+func (p *Pool) OnExec(sql string, fn ExecFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.execs[normalize(sql)] = fn
+}
+
+// OnQuery registers the handler of Query and QueryRow calls with the statement,
+// replacing any previous one.
+func (p *Pool) OnQuery(sql string, fn QueryFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queries[normalize(sql)] = fn
+}
+
+// FailBegin makes Begin return err, or succeed again when err is nil.
+func (p *Pool) FailBegin(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.beginErr = err
+}
+
+// Calls returns the statements run so far, in order.
+func (p *Pool) Calls() []Call {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Call(nil), p.calls...)
+}
+
+// Txs returns the transactions begun so far, in order.
+func (p *Pool) Txs() []*Tx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]*Tx(nil), p.txs...)
+}
+
+// Exec runs the handler registered with OnExec.
+func (p *Pool) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return p.exec(nil, sql, args)
+}
+
+// Query runs the handler registered with OnQuery.
+func (p *Pool) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return p.query(nil, sql, args)
+}
+
+// QueryRow runs the handler registered with OnQuery, returning its first row.
+func (p *Pool) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	return p.queryRow(nil, sql, args)
+}
+
+// Begin starts a Tx, unless FailBegin set an error.
+func (p *Pool) Begin(_ context.Context) (pgx.Tx, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.beginErr != nil {
+		return nil, p.beginErr
+	}
+
+	tx := &Tx{pool: p}
+	p.txs = append(p.txs, tx)
+
+	return tx, nil
+}
+
+func (p *Pool) exec(tx *Tx, sql string, args []any) (pgconn.CommandTag, error) {
+	p.mu.Lock()
+	p.calls = append(p.calls, Call{SQL: sql, Args: args, Tx: tx})
+	fn, ok := p.execs[normalize(sql)]
+	p.mu.Unlock()
+
+	if !ok {
+		return pgconn.CommandTag{}, fmt.Errorf("%w: Exec %s", ErrUnexpected, sql)
+	}
+
+	return fn(args)
+}
+
+func (p *Pool) query(tx *Tx, sql string, args []any) (pgx.Rows, error) {
+	p.mu.Lock()
+	p.calls = append(p.calls, Call{SQL: sql, Args: args, Tx: tx})
+	fn, ok := p.queries[normalize(sql)]
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: Query %s", ErrUnexpected, sql)
+	}
+
+	return fn(args)
+}
+
+func (p *Pool) queryRow(tx *Tx, sql string, args []any) pgx.Row {
+	rows, err := p.query(tx, sql, args)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return firstRow{rows: rows}
+}
+
+func normalize(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// firstRow scans the first row of rows like pgx does for QueryRow.
+type firstRow struct {
+	rows pgx.Rows
+}
+
+func (r firstRow) Scan(dest ...any) error {
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+
+		return pgx.ErrNoRows
+	}
+
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	r.rows.Close()
+
+	return r.rows.Err()
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
@@ -0,0 +1,149 @@
+package mocks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/postgres/mocks"
+)
+
+func TestPool_Exec(t *testing.T) {
+	pool := mocks.NewPool()
+	pool.OnExec("UPDATE users SET name = $1 WHERE id = $2", func(args []any) (pgconn.CommandTag, error) {
+		if args[0] != "bob" {
+			t.Errorf("args[0] = %v, want bob", args[0])
+		}
+
+		return pgconn.NewCommandTag("UPDATE 1"), nil
+	})
+
+	ctx := context.Background()
+
+	tag, err := pool.Exec(ctx, "UPDATE users\n\tSET name = $1\n\tWHERE id = $2", "bob", 1)
+	if err != nil || tag.RowsAffected() != 1 {
+		t.Errorf("Exec() = %v, %v, want UPDATE 1", tag, err)
+	}
+
+	if _, err := pool.Exec(ctx, "DELETE FROM users"); !errors.Is(err, mocks.ErrUnexpected) {
+		t.Errorf("Exec() error = %v, want ErrUnexpected", err)
+	}
+
+	if calls := pool.Calls(); len(calls) != 2 || calls[0].Args[1] != 1 || calls[0].Tx != nil {
+		t.Errorf("Calls() = %+v", calls)
+	}
+}
+
+func TestPool_Query(t *testing.T) {
+	now := time.Now()
+
+	pool := mocks.NewPool()
+	pool.OnQuery("SELECT id, name, deleted_at FROM users", func([]any) (pgx.Rows, error) {
+		return mocks.NewRows([]string{"id", "name", "deleted_at"},
+			[]any{1, "alice", nil},
+			[]any{2, "bob", now},
+		), nil
+	})
+
+	rows, err := pool.Query(context.Background(), "SELECT id, name, deleted_at FROM users")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	type user struct {
+		ID        int64
+		Name      string
+		DeletedAt *time.Time
+	}
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (user, error) {
+		var u user
+		err := row.Scan(&u.ID, &u.Name, &u.DeletedAt)
+
+		return u, err
+	})
+	if err != nil {
+		t.Fatalf("CollectRows() error = %v", err)
+	}
+
+	if len(users) != 2 || users[0].ID != 1 || users[0].DeletedAt != nil || users[1].Name != "bob" || !users[1].DeletedAt.Equal(now) {
+		t.Errorf("users = %+v", users)
+	}
+}
+
+func TestPool_QueryRow(t *testing.T) {
+	pool := mocks.NewPool()
+	pool.OnQuery("SELECT name FROM users WHERE id = $1", func(args []any) (pgx.Rows, error) {
+		if args[0] == 1 {
+			return mocks.NewRows([]string{"name"}, []any{"alice"}), nil
+		}
+
+		return mocks.NewRows([]string{"name"}), nil
+	})
+
+	ctx := context.Background()
+
+	var name string
+	if err := pool.QueryRow(ctx, "SELECT name FROM users WHERE id = $1", 1).Scan(&name); err != nil || name != "alice" {
+		t.Errorf("Scan() = %q, %v, want alice", name, err)
+	}
+
+	if err := pool.QueryRow(ctx, "SELECT name FROM users WHERE id = $1", 2).Scan(&name); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Scan() error = %v, want pgx.ErrNoRows", err)
+	}
+
+	failed := errors.New("failed")
+	pool.OnQuery("SELECT 1", func([]any) (pgx.Rows, error) {
+		return mocks.NewRows([]string{"n"}, []any{1}).WithErr(failed), nil
+	})
+
+	var n int
+	if err := pool.QueryRow(ctx, "SELECT 1").Scan(&n); !errors.Is(err, failed) {
+		t.Errorf("Scan() error = %v, want %v", err, failed)
+	}
+}
+
+func TestPool_Begin(t *testing.T) {
+	pool := mocks.NewPool()
+	pool.OnExec("DELETE FROM users", func([]any) (pgconn.CommandTag, error) {
+		return pgconn.NewCommandTag("DELETE 3"), nil
+	})
+
+	ctx := context.Background()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM users"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if err := tx.Rollback(ctx); !errors.Is(err, pgx.ErrTxClosed) {
+		t.Errorf("Rollback() error = %v, want pgx.ErrTxClosed", err)
+	}
+
+	txs := pool.Txs()
+	if len(txs) != 1 || !txs[0].Committed() || txs[0].RolledBack() {
+		t.Errorf("Txs() = %+v, want one committed", txs)
+	}
+
+	if calls := pool.Calls(); len(calls) != 1 || calls[0].Tx != txs[0] {
+		t.Errorf("Calls() = %+v, want one in the transaction", calls)
+	}
+
+	failed := errors.New("failed")
+	pool.FailBegin(failed)
+
+	if _, err := pool.Begin(ctx); !errors.Is(err, failed) {
+		t.Errorf("Begin() error = %v, want %v", err, failed)
+	}
+}
@@ -0,0 +1,161 @@
+package mocks
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Rows is a fake pgx.Rows over values held in memory.
+type Rows struct {
+	columns []string
+	values  [][]any
+	err     error
+
+	current int
+	closed  bool
+}
+
+var _ pgx.Rows = (*Rows)(nil)
+
+// NewRows creates rows of the columns, each of values holding one value per column.
+// Scan assigns values to targets of the same or a convertible type, calls sql.Scanner
+// targets, and sets targets of nil values to their zero value.
+func NewRows(columns []string, values ...[]any) *Rows {
+	return &Rows{columns: columns, values: values}
+}
+
+// WithErr makes Err return err once the rows are read, as for a failure mid-result.
+func (r *Rows) WithErr(err error) *Rows {
+	r.err = err
+
+	return r
+}
+
+// Close closes the rows.
+func (r *Rows) Close() {
+	r.closed = true
+}
+
+// Err returns the error set with WithErr once the rows are closed.
+func (r *Rows) Err() error {
+	if !r.closed {
+		return nil
+	}
+
+	return r.err
+}
+
+// CommandTag returns a SELECT tag with the number of rows.
+func (r *Rows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.values)))
+}
+
+// FieldDescriptions describes the columns by name.
+func (r *Rows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fields[i].Name = c
+	}
+
+	return fields
+}
+
+// Next moves to the next row, closing the rows after the last one.
+func (r *Rows) Next() bool {
+	if r.closed {
+		return false
+	}
+
+	if r.current >= len(r.values) {
+		r.Close()
+
+		return false
+	}
+
+	r.current++
+
+	return true
+}
+
+// Scan assigns the values of the current row to dest. Nil targets are skipped.
+func (r *Rows) Scan(dest ...any) error {
+	values, err := r.Values()
+	if err != nil {
+		return err
+	}
+
+	if len(dest) != len(values) {
+		return fmt.Errorf("mocks - Rows - Scan: %d targets for %d columns", len(dest), len(values))
+	}
+
+	for i, d := range dest {
+		if err := assign(d, values[i]); err != nil {
+			return fmt.Errorf("mocks - Rows - Scan - column %d (%s): %w", i, r.columns[i], err)
+		}
+	}
+
+	return nil
+}
+
+// Values returns the values of the current row.
+func (r *Rows) Values() ([]any, error) {
+	if r.current == 0 || r.closed {
+		return nil, errors.New("mocks - Rows - no current row")
+	}
+
+	return r.values[r.current-1], nil
+}
+
+// RawValues returns nil, as values are not encoded.
+func (r *Rows) RawValues() [][]byte {
+	return nil
+}
+
+// Conn returns nil, as there is no connection.
+func (r *Rows) Conn() *pgx.Conn {
+	return nil
+}
+
+func assign(dest, value any) error {
+	if dest == nil {
+		return nil
+	}
+
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+
+	ptr := reflect.ValueOf(dest)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("target %T is not a non-nil pointer", dest)
+	}
+
+	target := ptr.Elem()
+
+	if value == nil {
+		target.SetZero()
+
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch {
+	case v.Type().AssignableTo(target.Type()):
+		target.Set(v)
+	case target.Kind() == reflect.Pointer && v.Type().AssignableTo(target.Type().Elem()):
+		target.Set(reflect.New(target.Type().Elem()))
+		target.Elem().Set(v)
+	case v.Type().ConvertibleTo(target.Type()) && v.Kind() != reflect.String && target.Kind() != reflect.String:
+		target.Set(v.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot assign %T to %s", value, target.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Tx is a fake pgx.Tx running statements with the handlers of its Pool and recording
+// how it ended. Nested transactions are the same Tx.
+type Tx struct {
+	pool *Pool
+
+	mu         sync.Mutex
+	committed  bool
+	rolledBack bool
+}
+
+var _ pgx.Tx = (*Tx)(nil)
+
+// Committed reports whether Commit was called before Rollback.
+func (t *Tx) Committed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.committed
+}
+
+// RolledBack reports whether Rollback was called before Commit.
+func (t *Tx) RolledBack() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rolledBack
+}
+
+// Begin returns t, as savepoints are not modeled.
+func (t *Tx) Begin(context.Context) (pgx.Tx, error) {
+	return t, nil
+}
+
+// Commit ends the transaction, returning pgx.ErrTxClosed if it already ended.
+func (t *Tx) Commit(context.Context) error {
+	return t.end(&t.committed)
+}
+
+// Rollback ends the transaction, returning pgx.ErrTxClosed if it already ended,
+// so that a deferred Rollback after Commit is harmless.
+func (t *Tx) Rollback(context.Context) error {
+	return t.end(&t.rolledBack)
+}
+
+func (t *Tx) end(state *bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.committed || t.rolledBack {
+		return pgx.ErrTxClosed
+	}
+
+	*state = true
+
+	return nil
+}
+
+// Exec runs the handler registered with Pool.OnExec.
+func (t *Tx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.pool.exec(t, sql, args)
+}
+
+// Query runs the handler registered with Pool.OnQuery.
+func (t *Tx) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.pool.query(t, sql, args)
+}
+
+// QueryRow runs the handler registered with Pool.OnQuery, returning its first row.
+func (t *Tx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	return t.pool.queryRow(t, sql, args)
+}
+
+// CopyFrom returns ErrNotSupported.
+func (t *Tx) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// SendBatch returns results failing with ErrNotSupported.
+func (t *Tx) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	return errBatchResults{}
+}
+
+// LargeObjects returns an unusable zero value.
+func (t *Tx) LargeObjects() pgx.LargeObjects {
+	return pgx.LargeObjects{}
+}
+
+// Prepare returns ErrNotSupported.
+func (t *Tx) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	return nil, ErrNotSupported
+}
+
+// Conn returns nil, as there is no connection.
+func (t *Tx) Conn() *pgx.Conn {
+	return nil
+}
+
+type errBatchResults struct{}
+
+func (errBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, ErrNotSupported
+}
+
+func (errBatchResults) Query() (pgx.Rows, error) {
+	return nil, ErrNotSupported
+}
+
+func (errBatchResults) QueryRow() pgx.Row {
+	return errRow{err: ErrNotSupported}
+}
+
+func (errBatchResults) Close() error {
+	return nil
+}
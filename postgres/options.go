@@ -1,6 +1,11 @@
 package postgres
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
 
 // Option defines a function type for configuring Postgres instances.
 type Option func(*Postgres)
@@ -25,3 +30,59 @@ func ConnTimeout(timeout time.Duration) Option {
 		c.connTimeout = timeout
 	}
 }
+
+// BeforeAcquire sets a hook called with the context of the caller before a pooled
+// connection is handed out, e.g. to set session settings. Returning false destroys
+// the connection and acquires another one. Hooks of several calls run in order.
+func BeforeAcquire(fn func(ctx context.Context, conn *pgx.Conn) bool) Option {
+	return func(c *Postgres) {
+		c.beforeAcquire = append(c.beforeAcquire, fn)
+	}
+}
+
+// QueryTracer adds a pgx tracer notified of the start and end of every query on pooled
+// connections, e.g. for metrics, tracing or query logging. Tracers of several calls run in order.
+func QueryTracer(t pgx.QueryTracer) Option {
+	return func(c *Postgres) {
+		c.tracers = append(c.tracers, t)
+	}
+}
+
+// HealthQuery sets a statement HealthCheck runs after pinging, e.g. a query on a table
+// the service cannot work without.
+// Default is no query.
+func HealthQuery(sql string) Option {
+	return func(c *Postgres) {
+		c.healthQuery = sql
+	}
+}
+
+// StatementCacheMode sets how queries are executed by default, from pgx.QueryExecModeCacheStatement,
+// which prepares and caches named statements, to pgx.QueryExecModeSimpleProtocol. Modes without
+// named prepared statements, such as pgx.QueryExecModeExec, work behind PgBouncer in transaction
+// pooling mode. The mode overrides default_query_exec_mode of the URL.
+// Default is the mode of the URL, pgx.QueryExecModeCacheStatement without one.
+func StatementCacheMode(mode pgx.QueryExecMode) Option {
+	return func(c *Postgres) {
+		c.queryExecMode = mode
+	}
+}
+
+// DescribeOnly caches the descriptions of statements, i.e. their argument and result types, instead
+// of preparing them, so that queries still run in a single round trip without named prepared
+// statements, e.g. behind PgBouncer in transaction pooling mode. It is shorthand for
+// StatementCacheMode(pgx.QueryExecModeCacheDescribe).
+func DescribeOnly() Option {
+	return StatementCacheMode(pgx.QueryExecModeCacheDescribe)
+}
+
+// StatementCacheSize sets the number of statements or statement descriptions cached per
+// connection. Sizes below 1 are ignored; choose a StatementCacheMode without a cache instead.
+// Default is the capacity of the URL, 512 without one.
+func StatementCacheSize(size int) Option {
+	return func(c *Postgres) {
+		if size > 0 {
+			c.statementCacheSize = size
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rdashevsky/go-pkgs/pagination"
+)
+
+// Keyset describes the sort order of a keyset paginated query.
+type Keyset[T any] struct {
+	// Columns sort the rows and must identify them uniquely, e.g. "created_at", "id".
+	Columns []string
+	// Desc sorts the rows in descending order.
+	Desc bool
+	// Key returns the values of Columns for an item, in order, to build the next cursor.
+	Key func(item T) []any
+}
+
+// Paginate runs b with keyset pagination, returning the page of req and the cursor
+// of the next one. Rows are scanned like Select and filtered with a row comparison on
+// the keyset columns, e.g. WHERE (created_at, id) > ($1, $2), which stays fast on deep
+// pages unlike OFFSET. It returns an error wrapping pagination.ErrInvalidLimit when
+// req.Limit is not positive and pagination.ErrInvalidCursor for a malformed cursor.
+//
+// Example:
+//
+//	req, err := pagination.FromQuery(c)
+//	page, err := postgres.Paginate(ctx, pg, pg.Builder.Select("id", "email", "created_at").From("users"), req,
+//	    postgres.Keyset[User]{
+//	        Columns: []string{"created_at", "id"},
+//	        Desc:    true,
+//	        Key:     func(u User) []any { return []any{u.CreatedAt, u.ID} },
+//	    },
+//	)
+func Paginate[T any](ctx context.Context, p *Postgres, b squirrel.SelectBuilder, req pagination.PageRequest, ks Keyset[T]) (pagination.PageResponse[T], error) {
+	if req.Limit <= 0 {
+		return pagination.PageResponse[T]{}, fmt.Errorf("postgres - Paginate: %w", pagination.ErrInvalidLimit)
+	}
+
+	q, err := pagination.Keyset(b, req, ks.Desc, ks.Columns...)
+	if err != nil {
+		return pagination.PageResponse[T]{}, fmt.Errorf("postgres - Paginate - pagination.Keyset: %w", err)
+	}
+
+	var items []T
+	if err := p.SelectBuilder(ctx, &items, q); err != nil {
+		return pagination.PageResponse[T]{}, fmt.Errorf("postgres - Paginate - p.SelectBuilder: %w", err)
+	}
+
+	var cursor func(T) interface{}
+	if ks.Key != nil {
+		cursor = func(item T) interface{} { return ks.Key(item) }
+	}
+
+	page, err := pagination.NewPage(items, req.Limit, cursor)
+	if err != nil {
+		return pagination.PageResponse[T]{}, fmt.Errorf("postgres - Paginate - pagination.NewPage: %w", err)
+	}
+
+	return page, nil
+}
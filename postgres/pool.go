@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolI is the part of the connection pool repositories run statements with, so that
+// they can be unit tested with a fake such as the one of the postgres/mocks package.
+// Transactions begun with it satisfy pgx.Tx, whose Exec, Query and QueryRow match PoolI.
+//
+//nolint:revive // exported: PoolI is named after logger.LoggerI
+type PoolI interface {
+	// Exec runs a statement and returns its command tag.
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	// Query runs a statement and returns its rows, which must be closed.
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	// QueryRow runs a statement returning at most one row, errors being deferred to Scan.
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	// Begin starts a transaction.
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var _ PoolI = (*pgxpool.Pool)(nil)
+
+// DB returns the pool as a PoolI, or nil when New was told not to connect.
+//
+// Example:
+//
+//	type UserRepository struct {
+//	    db postgres.PoolI
+//	}
+//
+//	repo := &UserRepository{db: pg.DB()}
+func (p *Postgres) DB() PoolI {
+	if p.Pool == nil {
+		return nil
+	}
+
+	return p.Pool
+}
@@ -0,0 +1,101 @@
+// Package postgrestest starts a disposable Postgres in Docker for tests, applies schema SQL
+// and returns a ready postgres client. The container is terminated when the test ends, and
+// tests are skipped when Docker is not available or when running with -short.
+package postgrestest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/containers"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// Option configures the database.
+type Option func(*config)
+
+type config struct {
+	schema     []string
+	files      []string
+	containers []containers.Option
+	postgres   []postgres.Option
+}
+
+// Schema adds SQL scripts run in order once the database is up, e.g. CREATE TABLE
+// statements. A script may hold several statements separated by semicolons.
+func Schema(sql ...string) Option {
+	return func(c *config) {
+		c.schema = append(c.schema, sql...)
+	}
+}
+
+// SchemaFiles adds files of SQL scripts run in order after those of Schema.
+func SchemaFiles(paths ...string) Option {
+	return func(c *config) {
+		c.files = append(c.files, paths...)
+	}
+}
+
+// ContainerOptions passes options to the container, e.g. containers.Image.
+func ContainerOptions(opts ...containers.Option) Option {
+	return func(c *config) {
+		c.containers = append(c.containers, opts...)
+	}
+}
+
+// PostgresOptions passes options to the client, e.g. postgres.MaxPoolSize.
+func PostgresOptions(opts ...postgres.Option) Option {
+	return func(c *config) {
+		c.postgres = append(c.postgres, opts...)
+	}
+}
+
+// New starts a Postgres container, applies the schema and returns a connected client,
+// closed when the test ends.
+//
+// Example:
+//
+//	func TestRepository(t *testing.T) {
+//	    pg := postgrestest.New(t, postgrestest.SchemaFiles("../migrations/schema.sql"))
+//	    repo := NewRepository(pg)
+//	    ...
+//	}
+func New(tb testing.TB, opts ...Option) *postgres.Postgres {
+	tb.Helper()
+
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	scripts := cfg.schema
+
+	for _, path := range cfg.files {
+		b, err := os.ReadFile(path) // #nosec G304 -- paths are provided by the test
+		if err != nil {
+			tb.Fatalf("postgrestest - New - os.ReadFile: %v", err)
+		}
+
+		scripts = append(scripts, string(b))
+	}
+
+	pg, err := postgres.New(containers.PostgresURL(tb, cfg.containers...), cfg.postgres...)
+	if err != nil {
+		tb.Fatalf("postgrestest - New - postgres.New: %v", err)
+	}
+
+	tb.Cleanup(pg.Close)
+
+	ctx := context.Background()
+
+	for i, sql := range scripts {
+		// Statements without arguments use the simple protocol, which runs several at once.
+		if _, err := pg.Pool.Exec(ctx, sql); err != nil {
+			tb.Fatalf("postgrestest - New - schema script %d: %v", i, err)
+		}
+	}
+
+	return pg
+}
@@ -0,0 +1,27 @@
+package postgrestest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/postgres/postgrestest"
+)
+
+func TestNew(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(file, []byte("INSERT INTO users (name) VALUES ('alice'); INSERT INTO users (name) VALUES ('bob');"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pg := postgrestest.New(t,
+		postgrestest.Schema("CREATE TABLE users (id BIGSERIAL PRIMARY KEY, name TEXT NOT NULL)"),
+		postgrestest.SchemaFiles(file),
+	)
+
+	var count int
+	if err := pg.Pool.QueryRow(context.Background(), "SELECT count(*) FROM users").Scan(&count); err != nil || count != 2 {
+		t.Errorf("count = %d, %v, want 2", count, err)
+	}
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// WithQueryLogger logs every statement with its duration at level ("debug", "info"
+// or "warn"; anything else is "debug"), and failed statements with their error at
+// the error level. Argument values are not logged unless LogQueryArgs is set.
+// A nil logger is ignored.
+// Default is no query logging.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.WithQueryLogger(l, "debug"))
+func WithQueryLogger(l logger.LoggerI, level string) Option {
+	return func(c *Postgres) {
+		if l != nil {
+			c.queryLogger = l
+			c.queryLogLevel = level
+		}
+	}
+}
+
+// LogQueryArgs makes WithQueryLogger log argument values, each passed through redact
+// first, e.g. to mask the arguments of a password column. A nil redact logs values
+// unchanged.
+// Default is logging only the number of arguments.
+//
+// Example:
+//
+//	postgres.LogQueryArgs(func(sql string, i int, arg any) any {
+//	    if strings.Contains(sql, "password") {
+//	        return "***"
+//	    }
+//
+//	    return arg
+//	})
+func LogQueryArgs(redact func(sql string, i int, arg any) any) Option {
+	return func(c *Postgres) {
+		c.logQueryArgs = true
+		c.redactQueryArg = redact
+	}
+}
+
+type queryLogKey struct{}
+
+type queryLogStart struct {
+	start time.Time
+	sql   string
+	args  string
+}
+
+type queryLogger struct {
+	l      logger.LoggerI
+	log    func(message string, args ...interface{})
+	args   bool
+	redact func(sql string, i int, arg any) any
+}
+
+func newQueryLogger(l logger.LoggerI, level string, args bool, redact func(sql string, i int, arg any) any) queryLogger {
+	q := queryLogger{l: l, args: args, redact: redact}
+
+	switch strings.ToLower(level) {
+	case "info":
+		q.log = l.Info
+	case "warn":
+		q.log = l.Warn
+	default:
+		q.log = func(message string, args ...interface{}) { l.Debug(message, args...) }
+	}
+
+	return q
+}
+
+func (q queryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryLogKey{}, queryLogStart{
+		start: time.Now(),
+		sql:   data.SQL,
+		args:  q.formatArgs(data.SQL, data.Args),
+	})
+}
+
+func (q queryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	s, ok := ctx.Value(queryLogKey{}).(queryLogStart)
+	if !ok {
+		return
+	}
+
+	elapsed := time.Since(s.start)
+
+	if data.Err != nil {
+		q.l.Error("postgres - query failed in %s: %s %s: %v", elapsed, s.sql, s.args, data.Err)
+
+		return
+	}
+
+	q.log("postgres - query in %s: %s %s: %s", elapsed, s.sql, s.args, data.CommandTag.String())
+}
+
+func (q queryLogger) formatArgs(sql string, args []any) string {
+	if !q.args {
+		return fmt.Sprintf("[%d args]", len(args))
+	}
+
+	values := make([]any, len(args))
+
+	for i, arg := range args {
+		if q.redact != nil {
+			arg = q.redact(sql, i, arg)
+		}
+
+		values[i] = arg
+	}
+
+	return fmt.Sprint(values)
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) record(level string, message interface{}, args ...interface{}) {
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(fmt.Sprint(message), args...))
+}
+
+func (l *recordingLogger) Debug(message interface{}, args ...interface{}) {
+	l.record("debug", message, args...)
+}
+
+func (l *recordingLogger) Info(message string, args ...interface{}) {
+	l.record("info", message, args...)
+}
+func (l *recordingLogger) Warn(message string, args ...interface{}) {
+	l.record("warn", message, args...)
+}
+
+func (l *recordingLogger) Error(message interface{}, args ...interface{}) {
+	l.record("error", message, args...)
+}
+
+func (l *recordingLogger) Fatal(message interface{}, args ...interface{}) {
+	l.record("fatal", message, args...)
+}
+
+func TestQueryLogger(t *testing.T) {
+	const sql = "UPDATE users SET password = $1 WHERE id = $2"
+
+	redact := func(_ string, i int, arg any) any {
+		if i == 0 {
+			return "***"
+		}
+
+		return arg
+	}
+
+	tests := []struct {
+		name  string
+		pg    *Postgres
+		err   error
+		want  string
+		avoid string
+	}{
+		{
+			name:  "args hidden",
+			pg:    &Postgres{},
+			want:  "debug postgres - query in",
+			avoid: "secret",
+		},
+		{
+			name:  "args redacted",
+			pg:    &Postgres{logQueryArgs: true, redactQueryArg: redact},
+			want:  "[*** 42]",
+			avoid: "secret",
+		},
+		{
+			name: "error",
+			pg:   &Postgres{},
+			err:  errors.New("deadlock"),
+			want: "error postgres - query failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &recordingLogger{}
+			WithQueryLogger(l, "debug")(tt.pg)
+
+			q := newQueryLogger(tt.pg.queryLogger, tt.pg.queryLogLevel, tt.pg.logQueryArgs, tt.pg.redactQueryArg)
+
+			ctx := q.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{"secret", 42}})
+			q.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1"), Err: tt.err})
+
+			if len(l.lines) != 1 {
+				t.Fatalf("logged %v, want one line", l.lines)
+			}
+
+			if !strings.Contains(l.lines[0], tt.want) || (tt.avoid != "" && strings.Contains(l.lines[0], tt.avoid)) {
+				t.Errorf("logged %q, want %q without %q", l.lines[0], tt.want, tt.avoid)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+const _defaultPrimaryKey = "id"
+
+// ErrInvalidEntity is returned by NewRepository when the entity type is not a struct
+// with a field for the primary key.
+var ErrInvalidEntity = errors.New("postgres - invalid repository entity")
+
+// RepositoryOption configures a Repository.
+type RepositoryOption func(*repositoryConfig)
+
+type repositoryConfig struct {
+	key string
+}
+
+// PrimaryKey sets the column identifying the rows of the table.
+// Default is "id".
+func PrimaryKey(column string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.key = column
+	}
+}
+
+// Repository implements the CRUD statements of a table whose rows map to T.
+// Columns are the exported fields of T named by their "db" tag or, without a tag,
+// their name in snake case, e.g. CreatedAt is created_at; fields tagged "db:\"-\""
+// are ignored and embedded structs are flattened, like for Select. It is safe for
+// concurrent use.
+type Repository[T any] struct {
+	pg      *Postgres
+	table   string
+	key     column
+	columns []column
+}
+
+type column struct {
+	name  string
+	index []int
+}
+
+// NewRepository creates a Repository of table for the entity T. It returns an error
+// wrapping ErrInvalidEntity when T is not a struct or has no primary key field.
+//
+// Example:
+//
+//	type User struct {
+//	    ID        int64  `db:"id"`
+//	    Email     string `db:"email"`
+//	    CreatedAt time.Time
+//	}
+//
+//	users, err := postgres.NewRepository[User](pg, "users")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	u := User{Email: "alice@example.com", CreatedAt: time.Now()}
+//	err = users.Insert(ctx, &u) // sets u.ID
+func NewRepository[T any](pg *Postgres, table string, opts ...RepositoryOption) (*Repository[T], error) {
+	cfg := repositoryConfig{key: _defaultPrimaryKey}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t := reflect.TypeFor[T]()
+	if !isStruct(t) {
+		return nil, fmt.Errorf("postgres - NewRepository - %s: %w", t, ErrInvalidEntity)
+	}
+
+	r := &Repository[T]{pg: pg, table: table, columns: structColumns(t)}
+
+	for _, c := range r.columns {
+		if normalize(c.name) == normalize(cfg.key) {
+			r.key = c
+		}
+	}
+
+	if r.key.index == nil {
+		return nil, fmt.Errorf("postgres - NewRepository - %s has no field for %q: %w", t, cfg.key, ErrInvalidEntity)
+	}
+
+	return r, nil
+}
+
+// Select returns a query of the columns of the table, e.g. to add conditions and
+// ordering for SelectBuilder.
+func (r *Repository[T]) Select() squirrel.SelectBuilder {
+	names := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		names[i] = c.name
+	}
+
+	return r.pg.Builder.Select(names...).From(r.table)
+}
+
+// Insert inserts item. A zero primary key is left to the default of the column, e.g.
+// a sequence, and set on item from the inserted row.
+func (r *Repository[T]) Insert(ctx context.Context, item *T) error {
+	b, generated := r.insert(item)
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return fmt.Errorf("postgres - Repository - Insert - b.ToSql: %w", err)
+	}
+
+	ctx, cancel := r.pg.queryContext(ctx)
+	defer cancel()
+
+	if !generated {
+		if _, err := r.pg.Pool.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("postgres - Repository - Insert - p.Pool.Exec: %w", err)
+		}
+
+		return nil
+	}
+
+	key := fieldByIndex(reflect.ValueOf(item).Elem(), r.key.index).Addr().Interface()
+
+	if err := r.pg.Pool.QueryRow(ctx, query, args...).Scan(key); err != nil {
+		return fmt.Errorf("postgres - Repository - Insert - p.Pool.QueryRow: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns the row with the primary key id, or an error wrapping pgx.ErrNoRows.
+func (r *Repository[T]) GetByID(ctx context.Context, id any) (T, error) {
+	var item T
+
+	if err := r.pg.GetBuilder(ctx, &item, r.Select().Where(squirrel.Eq{r.key.name: id})); err != nil {
+		return item, fmt.Errorf("postgres - Repository - GetByID: %w", err)
+	}
+
+	return item, nil
+}
+
+// Update sets every column of the row with the primary key of item, or returns an
+// error wrapping pgx.ErrNoRows when there is none.
+func (r *Repository[T]) Update(ctx context.Context, item T) error {
+	query, args, err := r.update(item).ToSql()
+	if err != nil {
+		return fmt.Errorf("postgres - Repository - Update - b.ToSql: %w", err)
+	}
+
+	return r.exec(ctx, "Update", query, args)
+}
+
+// Delete deletes the row with the primary key id, or returns an error wrapping
+// pgx.ErrNoRows when there is none.
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	query, args, err := r.pg.Builder.Delete(r.table).Where(squirrel.Eq{r.key.name: id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("postgres - Repository - Delete - b.ToSql: %w", err)
+	}
+
+	return r.exec(ctx, "Delete", query, args)
+}
+
+// List returns the rows matching every condition, ordered by primary key, e.g.
+// squirrel.Eq{"active": true}. Without conditions it returns the whole table.
+func (r *Repository[T]) List(ctx context.Context, where ...squirrel.Sqlizer) ([]T, error) {
+	b := r.Select().OrderBy(r.key.name)
+
+	for _, w := range where {
+		b = b.Where(w)
+	}
+
+	var items []T
+	if err := r.pg.SelectBuilder(ctx, &items, b); err != nil {
+		return nil, fmt.Errorf("postgres - Repository - List: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *Repository[T]) insert(item *T) (squirrel.InsertBuilder, bool) {
+	v := reflect.ValueOf(item).Elem()
+	generated := fieldValue(v, r.key.index).IsZero()
+
+	var (
+		names  []string
+		values []any
+	)
+
+	for _, c := range r.columns {
+		if generated && c.name == r.key.name {
+			continue
+		}
+
+		names = append(names, c.name)
+		values = append(values, fieldValue(v, c.index).Interface())
+	}
+
+	b := r.pg.Builder.Insert(r.table).Columns(names...).Values(values...)
+	if generated {
+		b = b.Suffix("RETURNING " + r.key.name)
+	}
+
+	return b, generated
+}
+
+func (r *Repository[T]) update(item T) squirrel.UpdateBuilder {
+	v := reflect.ValueOf(item)
+	b := r.pg.Builder.Update(r.table)
+
+	for _, c := range r.columns {
+		if c.name != r.key.name {
+			b = b.Set(c.name, fieldValue(v, c.index).Interface())
+		}
+	}
+
+	return b.Where(squirrel.Eq{r.key.name: fieldValue(v, r.key.index).Interface()})
+}
+
+func (r *Repository[T]) exec(ctx context.Context, op, query string, args []any) error {
+	ctx, cancel := r.pg.queryContext(ctx)
+	defer cancel()
+
+	tag, err := r.pg.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("postgres - Repository - %s - p.Pool.Exec: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("postgres - Repository - %s: %w", op, pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// structColumns lists the columns of a struct in field order, with the names and
+// precedence rules of collectFields.
+func structColumns(t reflect.Type) []column {
+	var all []column
+	walkColumns(t, nil, &all)
+
+	var columns []column
+
+	seen := map[string]int{}
+
+	for _, c := range all {
+		key := normalize(c.name)
+
+		i, ok := seen[key]
+		if !ok {
+			seen[key] = len(columns)
+			columns = append(columns, c)
+		} else if len(c.index) < len(columns[i].index) {
+			columns[i] = c
+		}
+	}
+
+	return columns
+}
+
+func walkColumns(t reflect.Type, parent []int, columns *[]column) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				if !f.IsExported() {
+					continue
+				}
+
+				ft = ft.Elem()
+			}
+
+			if isStruct(ft) {
+				walkColumns(ft, index, columns)
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = snakeCase(f.Name)
+		}
+
+		*columns = append(*columns, column{name: name, index: index})
+	}
+}
+
+// fieldValue is like reflect.Value.FieldByIndex, returning the zero value of the field
+// behind a nil embedded pointer.
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Zero(v.Type().Elem().FieldByIndex(index[i:]).Type)
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// snakeCase converts a field name to a column name, e.g. CreatedAt to created_at
+// and UserID to user_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
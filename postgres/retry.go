@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_retryInitialDelay = 50 * time.Millisecond
+	_retryMaxDelay     = 2 * time.Second
+
+	// _adminShutdown is sent when the server terminates a connection, e.g. on restart.
+	_adminShutdown = "57P01"
+	// _connectionExceptionClass covers connection failures reported by the server.
+	_connectionExceptionClass = "08"
+)
+
+// RetryPolicy makes ExecRetry and QueryRetry run a statement up to maxAttempts times
+// in total while it fails with a transient error, as reported by IsTransient, waiting
+// backoff between attempts. A nil backoff doubles the delay from 50ms up to 2 seconds.
+// Default is a single attempt.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.RetryPolicy(5, nil))
+func RetryPolicy(maxAttempts int, backoff retry.Backoff) Option {
+	return func(c *Postgres) {
+		c.retryAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// IsTransient reports whether err may not happen again when the statement is retried:
+// serialization failures, deadlocks, connections closed by the server, and failures
+// pgx reports as safe to retry because nothing was sent.
+func IsTransient(err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch {
+	case pgErr.Code == _serializationFailure, pgErr.Code == _deadlockDetected, pgErr.Code == _adminShutdown:
+		return true
+	default:
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == _connectionExceptionClass
+	}
+}
+
+// ExecRetry is like Pool.Exec, retried according to the RetryPolicy option.
+// The statement must be safe to run more than once.
+func (p *Postgres) ExecRetry(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+
+	err := p.retry(ctx, func(ctx context.Context) error {
+		ctx, cancel := p.queryContext(ctx)
+		defer cancel()
+
+		var err error
+
+		tag, err = p.Pool.Exec(ctx, sql, args...)
+
+		return err
+	})
+	if err != nil {
+		return tag, fmt.Errorf("postgres - ExecRetry - p.Pool.Exec: %w", err)
+	}
+
+	return tag, nil
+}
+
+// QueryRetry runs sql and passes the rows to fn, which must consume them, retrying
+// the query and fn according to the RetryPolicy option. Rows are closed after fn
+// returns. As fn may be called several times, it must reset what it collects.
+//
+// Example:
+//
+//	var names []string
+//	err := pg.QueryRetry(ctx, func(rows pgx.Rows) error {
+//	    names = names[:0]
+//
+//	    var err error
+//	    names, err = pgx.AppendRows(names, rows, pgx.RowTo[string])
+//
+//	    return err
+//	}, "SELECT name FROM users WHERE active")
+func (p *Postgres) QueryRetry(ctx context.Context, fn func(rows pgx.Rows) error, sql string, args ...any) error {
+	err := p.retry(ctx, func(ctx context.Context) error {
+		ctx, cancel := p.queryContext(ctx)
+		defer cancel()
+
+		rows, err := p.Pool.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		if err := fn(rows); err != nil {
+			return err
+		}
+
+		rows.Close()
+
+		return rows.Err()
+	})
+	if err != nil {
+		return fmt.Errorf("postgres - QueryRetry: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) retry(ctx context.Context, fn retry.Func) error {
+	backoff := retry.ExponentialBackoff(_retryInitialDelay, _retryMaxDelay)
+	if p.retryBackoff != nil {
+		backoff = retry.WithBackoff(p.retryBackoff)
+	}
+
+	return retry.Do(ctx, fn,
+		retry.Attempts(p.retryAttempts),
+		backoff,
+		retry.RetryIf(IsTransient),
+	)
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidDest is returned by Select and Get when dest is not a non-nil pointer,
+// to a slice for Select.
+var ErrInvalidDest = errors.New("postgres - invalid scan destination")
+
+// Select runs query and scans every row into dest, a pointer to a slice of structs,
+// of pointers to structs or of scalars. Columns map to the struct field with the
+// same "db" tag or, without a tag, the same name ignoring case and underscores;
+// fields tagged "db:\"-\"" are ignored and embedded structs are flattened. A column
+// without a field is an error. An empty result leaves an empty slice.
+//
+// Example:
+//
+//	type User struct {
+//	    ID        int64     `db:"id"`
+//	    Email     string    `db:"email"`
+//	    CreatedAt time.Time // matches created_at
+//	}
+//
+//	var users []User
+//	err := pg.Select(ctx, &users, "SELECT id, email, created_at FROM users WHERE active = $1", true)
+func (p *Postgres) Select(ctx context.Context, dest any, query string, args ...any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("postgres - Select - %T: %w", dest, ErrInvalidDest)
+	}
+
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+
+	rows, err := p.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("postgres - Select - p.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	slice := v.Elem()
+	elem := slice.Type().Elem()
+	base := elem
+
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, 0)
+
+	for rows.Next() {
+		item := reflect.New(base)
+
+		if err := scanRow(rows, item); err != nil {
+			return fmt.Errorf("postgres - Select - scanRow: %w", err)
+		}
+
+		if elem.Kind() != reflect.Pointer {
+			item = item.Elem()
+		}
+
+		result = reflect.Append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("postgres - Select - rows.Err: %w", err)
+	}
+
+	slice.Set(result)
+
+	return nil
+}
+
+// Get runs query and scans its first row into dest, a pointer to a struct or a
+// scalar, mapping columns like Select. It returns an error wrapping pgx.ErrNoRows
+// when there is no row.
+//
+// Example:
+//
+//	var user User
+//	err := pg.Get(ctx, &user, "SELECT id, email, created_at FROM users WHERE id = $1", id)
+//	if errors.Is(err, pgx.ErrNoRows) {
+//	    return ErrUserNotFound
+//	}
+func (p *Postgres) Get(ctx context.Context, dest any, query string, args ...any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("postgres - Get - %T: %w", dest, ErrInvalidDest)
+	}
+
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+
+	rows, err := p.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("postgres - Get - p.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("postgres - Get - rows.Err: %w", err)
+		}
+
+		return fmt.Errorf("postgres - Get: %w", pgx.ErrNoRows)
+	}
+
+	if err := scanRow(rows, v); err != nil {
+		return fmt.Errorf("postgres - Get - scanRow: %w", err)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("postgres - Get - rows.Err: %w", err)
+	}
+
+	return nil
+}
+
+// SelectBuilder is Select for a query built with Squirrel. Dollar placeholders are
+// used whatever the placeholder format of b.
+//
+// Example:
+//
+//	var users []User
+//	err := pg.SelectBuilder(ctx, &users, pg.Builder.
+//	    Select("id", "email", "created_at").
+//	    From("users").
+//	    Where(squirrel.Eq{"active": true}),
+//	)
+func (p *Postgres) SelectBuilder(ctx context.Context, dest any, b squirrel.SelectBuilder) error {
+	query, args, err := b.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("postgres - SelectBuilder - b.ToSql: %w", err)
+	}
+
+	return p.Select(ctx, dest, query, args...)
+}
+
+// GetBuilder is Get for a query built with Squirrel. Dollar placeholders are used
+// whatever the placeholder format of b.
+func (p *Postgres) GetBuilder(ctx context.Context, dest any, b squirrel.SelectBuilder) error {
+	query, args, err := b.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("postgres - GetBuilder - b.ToSql: %w", err)
+	}
+
+	return p.Get(ctx, dest, query, args...)
+}
+
+var (
+	_scannerType = reflect.TypeFor[sql.Scanner]()
+	_timeType    = reflect.TypeFor[time.Time]()
+)
+
+// scanRow scans the current row into ptr, a pointer to a struct or a scalar.
+func scanRow(rows pgx.Rows, ptr reflect.Value) error {
+	t := ptr.Elem().Type()
+
+	if !isStruct(t) {
+		return rows.Scan(ptr.Interface())
+	}
+
+	fields := structFields(t)
+	columns := rows.FieldDescriptions()
+	targets := make([]any, len(columns))
+
+	for i, column := range columns {
+		index, ok := fields[normalize(column.Name)]
+		if !ok {
+			return fmt.Errorf("no field of %s for column %q", t, column.Name)
+		}
+
+		targets[i] = fieldByIndex(ptr.Elem(), index).Addr().Interface()
+	}
+
+	return rows.Scan(targets...)
+}
+
+// isStruct reports whether t is scanned field by field rather than as one value.
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != _timeType && !reflect.PointerTo(t).Implements(_scannerType)
+}
+
+// _structFields caches the normalized column name to field index map of struct types.
+var _structFields sync.Map
+
+func structFields(t reflect.Type) map[string][]int {
+	if fields, ok := _structFields.Load(t); ok {
+		return fields.(map[string][]int)
+	}
+
+	fields := map[string][]int{}
+	collectFields(t, nil, fields)
+
+	_structFields.Store(t, fields)
+
+	return fields
+}
+
+func collectFields(t reflect.Type, parent []int, fields map[string][]int) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				// A nil pointer to an unexported struct cannot be allocated.
+				if !f.IsExported() {
+					continue
+				}
+
+				ft = ft.Elem()
+			}
+
+			if isStruct(ft) {
+				collectFields(ft, index, fields)
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+
+		// Fields of the outer struct win over embedded ones.
+		key := normalize(name)
+		if existing, ok := fields[key]; !ok || len(existing) > len(index) {
+			fields[key] = index
+		}
+	}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, allocating nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeRows returns one row of values, assigning them to the scan targets by reflection.
+type fakeRows struct {
+	pgx.Rows
+	columns []string
+	values  []any
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fields[i].Name = c
+	}
+
+	return fields
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if len(dest) != len(r.values) {
+		return errors.New("wrong number of targets")
+	}
+
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[i]))
+	}
+
+	return nil
+}
+
+type base struct {
+	ID int64 `db:"id"`
+}
+
+type Audit struct {
+	CreatedAt time.Time
+}
+
+type user struct {
+	base
+	*Audit
+	Email    string `db:"email"`
+	Password string `db:"-"`
+	internal string
+}
+
+func TestScanRow(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		rows    *fakeRows
+		dest    any
+		want    any
+		wantErr string
+	}{
+		{
+			name: "struct",
+			rows: &fakeRows{columns: []string{"id", "email", "created_at"}, values: []any{int64(7), "a@example.com", now}},
+			dest: &user{},
+			want: &user{base: base{ID: 7}, Audit: &Audit{CreatedAt: now}, Email: "a@example.com"},
+		},
+		{
+			name: "scalar",
+			rows: &fakeRows{columns: []string{"count"}, values: []any{int64(3)}},
+			dest: new(int64),
+			want: func() *int64 { n := int64(3); return &n }(),
+		},
+		{
+			name: "time",
+			rows: &fakeRows{columns: []string{"now"}, values: []any{now}},
+			dest: new(time.Time),
+			want: &now,
+		},
+		{
+			name:    "unknown column",
+			rows:    &fakeRows{columns: []string{"id", "password"}, values: []any{int64(1), "secret"}},
+			dest:    &user{},
+			wantErr: `column "password"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanRow(tt.rows, reflect.ValueOf(tt.dest))
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("scanRow() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("scanRow() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Errorf("scanRow() = %+v, want %+v", tt.dest, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect_InvalidDest(t *testing.T) {
+	pg := &Postgres{}
+
+	for _, dest := range []any{nil, []user{}, &user{}, (*[]user)(nil)} {
+		if err := pg.Select(t.Context(), dest, "SELECT 1"); !errors.Is(err, ErrInvalidDest) {
+			t.Errorf("Select(%T) error = %v, want ErrInvalidDest", dest, err)
+		}
+	}
+
+	if err := pg.Get(t.Context(), user{}, "SELECT 1"); !errors.Is(err, ErrInvalidDest) {
+		t.Errorf("Get(user) error = %v, want ErrInvalidDest", err)
+	}
+}
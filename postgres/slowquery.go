@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	// _slowQueryMaxSQL is the number of characters of a slow statement logged.
+	_slowQueryMaxSQL = 500
+	// _slowQueryMaxDepth is the number of frames searched for the caller of a statement.
+	_slowQueryMaxDepth = 32
+)
+
+// _internalFrames prefix the functions skipped when looking for the caller of a statement.
+var _internalFrames = []string{
+	"github.com/jackc/pgx/",
+	"github.com/rdashevsky/go-pkgs/postgres.",
+	"github.com/rdashevsky/go-pkgs/retry.",
+	"runtime.",
+}
+
+// SlowQueryThreshold logs statements taking at least d at the warn level, with their
+// duration, their SQL with whitespace collapsed and truncated to 500 characters, and
+// the file and line of the first caller outside of pgx and this package. Arguments are
+// never logged. It costs a stack capture per statement, far less than WithQueryLogger.
+// Thresholds below or equal to zero and a nil logger are ignored.
+// Default is no slow query logging.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.SlowQueryThreshold(200*time.Millisecond, l))
+func SlowQueryThreshold(d time.Duration, l logger.LoggerI) Option {
+	return func(c *Postgres) {
+		if d > 0 && l != nil {
+			c.slowQueryThreshold = d
+			c.slowQueryLogger = l
+		}
+	}
+}
+
+type slowQueryKey struct{}
+
+type slowQueryStart struct {
+	start time.Time
+	sql   string
+	pcs   []uintptr
+}
+
+type slowQueryLogger struct {
+	l         logger.LoggerI
+	threshold time.Duration
+}
+
+func (s slowQueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	pcs := make([]uintptr, _slowQueryMaxDepth)
+	// Frames are resolved only for slow statements.
+	n := runtime.Callers(2, pcs)
+
+	return context.WithValue(ctx, slowQueryKey{}, slowQueryStart{
+		start: time.Now(),
+		sql:   data.SQL,
+		pcs:   pcs[:n],
+	})
+}
+
+func (s slowQueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(slowQueryKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+
+	elapsed := time.Since(q.start)
+	if elapsed < s.threshold {
+		return
+	}
+
+	status := data.CommandTag.String()
+	if data.Err != nil {
+		status = data.Err.Error()
+	}
+
+	s.l.Warn("postgres - slow query in %s at %s: %s: %s", elapsed, caller(q.pcs), truncateSQL(q.sql), status)
+}
+
+// caller returns the file and line of the first frame outside of internal packages.
+func caller(pcs []uintptr) string {
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame.Function) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+func isInternalFrame(function string) bool {
+	for _, prefix := range _internalFrames {
+		// Tests of this package are callers too.
+		if strings.HasPrefix(function, prefix) && !strings.Contains(function, ".Test") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func truncateSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+
+	if runes := []rune(sql); len(runes) > _slowQueryMaxSQL {
+		return string(runes[:_slowQueryMaxSQL]) + "..."
+	}
+
+	return sql
+}
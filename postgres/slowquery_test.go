@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		sql       string
+		err       error
+		want      []string
+	}{
+		{
+			name:      "fast",
+			threshold: time.Hour,
+			sql:       "SELECT 1",
+		},
+		{
+			name:      "slow",
+			threshold: time.Nanosecond,
+			sql:       "SELECT *\n\tFROM users\n\tWHERE id = $1",
+			want:      []string{"warn postgres - slow query in", "slowquery_test.go:", "SELECT * FROM users WHERE id = $1: SELECT 1"},
+		},
+		{
+			name:      "truncated",
+			threshold: time.Nanosecond,
+			sql:       "SELECT '" + strings.Repeat("x", 1000) + "'",
+			want:      []string{strings.Repeat("x", _slowQueryMaxSQL-8) + "...: SELECT 1"},
+		},
+		{
+			name:      "error",
+			threshold: time.Nanosecond,
+			sql:       "SELECT pg_sleep(10)",
+			err:       errors.New("canceling statement due to statement timeout"),
+			want:      []string{"SELECT pg_sleep(10): canceling statement"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &recordingLogger{}
+			pg := &Postgres{}
+			SlowQueryThreshold(tt.threshold, l)(pg)
+
+			s := slowQueryLogger{l: pg.slowQueryLogger, threshold: pg.slowQueryThreshold}
+
+			ctx := s.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: tt.sql, Args: []any{"secret"}})
+			s.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: tt.err})
+
+			if len(tt.want) == 0 {
+				if len(l.lines) != 0 {
+					t.Errorf("logged %v, want nothing", l.lines)
+				}
+
+				return
+			}
+
+			if len(l.lines) != 1 {
+				t.Fatalf("logged %v, want one line", l.lines)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(l.lines[0], want) {
+					t.Errorf("logged %q, want %q", l.lines[0], want)
+				}
+			}
+
+			if strings.Contains(l.lines[0], "secret") {
+				t.Errorf("logged %q with arguments", l.lines[0])
+			}
+		})
+	}
+}
+
+func TestSlowQueryThreshold_Ignored(t *testing.T) {
+	pg := &Postgres{}
+	SlowQueryThreshold(0, &recordingLogger{})(pg)
+	SlowQueryThreshold(time.Second, nil)(pg)
+
+	if pg.slowQueryLogger != nil || pg.slowQueryThreshold != 0 {
+		t.Errorf("SlowQueryThreshold set %v, %v, want nothing", pg.slowQueryThreshold, pg.slowQueryLogger)
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import "time"
+
+// PoolStats is a snapshot of the connection pool, for dashboards and logs.
+// Counters and durations are cumulative since the pool was created.
+type PoolStats struct {
+	// AcquiredConns, IdleConns and ConstructingConns count the connections of the pool
+	// by state; TotalConns is their sum and MaxConns the size of the pool.
+	AcquiredConns     int32
+	IdleConns         int32
+	ConstructingConns int32
+	TotalConns        int32
+	MaxConns          int32
+
+	// AcquireCount counts connections acquired from the pool, of which EmptyAcquireCount
+	// waited because none was idle and CanceledAcquireCount gave up with their context.
+	AcquireCount         int64
+	EmptyAcquireCount    int64
+	CanceledAcquireCount int64
+	// AcquireDuration is the time spent acquiring connections, of which EmptyAcquireWait
+	// is the time spent waiting for one to be released or established.
+	AcquireDuration  time.Duration
+	EmptyAcquireWait time.Duration
+
+	// NewConns counts connections established, and MaxLifetimeDestroys and
+	// MaxIdleDestroys those closed for exceeding their lifetime or idle time.
+	NewConns            int64
+	MaxLifetimeDestroys int64
+	MaxIdleDestroys     int64
+}
+
+// Stats returns the statistics of the pool, zero when New was told not to connect.
+//
+// Example:
+//
+//	s := pg.Stats()
+//	if s.AcquiredConns == s.MaxConns {
+//	    l.Warn("postgres pool saturated, %d acquires waited so far", s.EmptyAcquireCount)
+//	}
+func (p *Postgres) Stats() PoolStats {
+	if p.Pool == nil {
+		return PoolStats{}
+	}
+
+	s := p.Pool.Stat()
+
+	return PoolStats{
+		AcquiredConns:        s.AcquiredConns(),
+		IdleConns:            s.IdleConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		TotalConns:           s.TotalConns(),
+		MaxConns:             s.MaxConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		AcquireDuration:      s.AcquireDuration(),
+		EmptyAcquireWait:     s.EmptyAcquireWaitTime(),
+		NewConns:             s.NewConnsCount(),
+		MaxLifetimeDestroys:  s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroys:      s.MaxIdleDestroyCount(),
+	}
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_defaultStreamFetchSize = 1000
+	// _streamCursor names the cursor of QueryStream, unique within its transaction.
+	_streamCursor = "go_pkgs_stream"
+)
+
+// StreamFetchSize sets the number of rows QueryStream fetches from the server at once,
+// trading memory for round trips. Sizes below 1 are ignored.
+// Default is 1000.
+func StreamFetchSize(size int) Option {
+	return func(c *Postgres) {
+		if size > 0 {
+			c.streamFetchSize = size
+		}
+	}
+}
+
+// Iterator yields the rows of a QueryStream one at a time. It is not safe for
+// concurrent use and must be closed.
+type Iterator[T any] struct {
+	ctx       context.Context
+	fetch     func(ctx context.Context) (pgx.Rows, error)
+	close     func(ctx context.Context) error
+	fetchSize int
+
+	rows    pgx.Rows
+	fetched int
+	value   T
+	err     error
+	done    bool
+}
+
+// QueryStream runs query in a cursor and returns an iterator over its rows, scanned
+// into T like Select, fetching them in batches of StreamFetchSize as the iterator
+// advances. At most one batch is held in memory, so a slow consumer holds back the
+// query instead of buffering its result. The cursor lives in a transaction holding a
+// pooled connection until the iterator is closed.
+//
+// Example:
+//
+//	it, err := postgres.QueryStream[Event](ctx, pg, "SELECT id, payload FROM events WHERE created_at < $1", cutoff)
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//
+//	for it.Next() {
+//	    if err := enc.Encode(it.Value()); err != nil {
+//	        return err
+//	    }
+//	}
+//
+//	return it.Err()
+func QueryStream[T any](ctx context.Context, p *Postgres, query string, args ...any) (*Iterator[T], error) {
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("postgres - QueryStream - p.Pool.Begin: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DECLARE "+_streamCursor+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+
+		return nil, fmt.Errorf("postgres - QueryStream - DECLARE: %w", err)
+	}
+
+	fetchSize := p.streamFetchSize
+	if fetchSize <= 0 {
+		fetchSize = _defaultStreamFetchSize
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM %s", fetchSize, _streamCursor)
+
+	return &Iterator[T]{
+		ctx: ctx,
+		fetch: func(ctx context.Context) (pgx.Rows, error) {
+			return tx.Query(ctx, fetch)
+		},
+		// The cursor only reads, so there is nothing to commit.
+		close:     tx.Rollback,
+		fetchSize: fetchSize,
+	}, nil
+}
+
+// Next advances to the next row, fetching another batch when the current one is
+// consumed. It returns false at the end of the rows or on error, see Err.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+
+	for {
+		if it.rows == nil {
+			rows, err := it.fetch(it.ctx)
+			if err != nil {
+				return it.fail(fmt.Errorf("postgres - Iterator - FETCH: %w", err))
+			}
+
+			it.rows = rows
+			it.fetched = 0
+		}
+
+		if it.rows.Next() {
+			it.fetched++
+
+			var value T
+			if err := scanRow(it.rows, reflect.ValueOf(&value)); err != nil {
+				return it.fail(fmt.Errorf("postgres - Iterator - scanRow: %w", err))
+			}
+
+			it.value = value
+
+			return true
+		}
+
+		if err := it.rows.Err(); err != nil {
+			return it.fail(fmt.Errorf("postgres - Iterator - rows.Err: %w", err))
+		}
+
+		it.rows = nil
+
+		// A short batch is the last one.
+		if it.fetched < it.fetchSize {
+			it.done = true
+
+			return false
+		}
+	}
+}
+
+func (it *Iterator[T]) fail(err error) bool {
+	it.err = err
+	it.done = true
+
+	return false
+}
+
+// Value returns the current row.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped Next, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the cursor and releases its connection. It is safe to call Close
+// more than once.
+func (it *Iterator[T]) Close() error {
+	if it.close == nil {
+		return nil
+	}
+
+	if it.rows != nil {
+		it.rows.Close()
+		it.rows = nil
+	}
+
+	it.done = true
+
+	closeFn := it.close
+	it.close = nil
+
+	if err := closeFn(context.WithoutCancel(it.ctx)); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return fmt.Errorf("postgres - Iterator - Close: %w", err)
+	}
+
+	return nil
+}
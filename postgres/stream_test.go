@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/postgres"
+	"github.com/rdashevsky/go-pkgs/postgres/postgrestest"
+)
+
+type event struct {
+	ID      int64  `db:"id"`
+	Payload string `db:"payload"`
+}
+
+func TestQueryStream_Integration(t *testing.T) {
+	// A single connection checks the iterator releases it when closed.
+	pg := postgrestest.New(t, postgrestest.PostgresOptions(postgres.MaxPoolSize(1), postgres.StreamFetchSize(100)))
+	ctx := context.Background()
+
+	it, err := postgres.QueryStream[event](ctx, pg,
+		"SELECT n AS id, 'event ' || n AS payload FROM generate_series(1, $1::int) n", 250)
+	if err != nil {
+		t.Fatalf("QueryStream() error = %v", err)
+	}
+
+	var count int64
+
+	for it.Next() {
+		count++
+
+		if e := it.Value(); e.ID != count || e.Payload == "" {
+			t.Fatalf("row %d = %+v", count, e)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if count != 250 {
+		t.Errorf("streamed %d rows, want 250 over 3 fetches", count)
+	}
+
+	if err := it.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := pg.Pool.QueryRow(ctx, "SELECT 1").Scan(new(int)); err != nil {
+		t.Errorf("query after Close() error = %v, want the connection released", err)
+	}
+}
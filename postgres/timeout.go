@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// DefaultQueryTimeout bounds the statements of Select, Get, ExecRetry, QueryRetry,
+// SendBatch, CopyFrom and their variants with a context deadline, each attempt of a
+// retried statement getting its own. The server also cancels any statement of the pool
+// running longer with statement_timeout, which the option overrides in the URL. Deadlines
+// of the caller that expire earlier still apply.
+// Default is no timeout.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.DefaultQueryTimeout(5*time.Second))
+func DefaultQueryTimeout(d time.Duration) Option {
+	return func(c *Postgres) {
+		c.queryTimeout = d
+	}
+}
+
+// queryContext derives the context of a statement run by a helper.
+func (p *Postgres) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, p.queryTimeout)
+}
+
+// statementTimeout formats the timeout in milliseconds, rounded up as zero disables it.
+func statementTimeout(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Millisecond-1)/time.Millisecond), 10)
+}
@@ -0,0 +1,39 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/postgres"
+	"github.com/rdashevsky/go-pkgs/postgres/postgrestest"
+)
+
+func TestDefaultQueryTimeout_Integration(t *testing.T) {
+	pg := postgrestest.New(t, postgrestest.PostgresOptions(postgres.DefaultQueryTimeout(200*time.Millisecond)))
+	ctx := context.Background()
+
+	start := time.Now()
+
+	if _, err := pg.ExecRetry(ctx, "SELECT pg_sleep(5)"); err == nil {
+		t.Fatal("ExecRetry() error = nil, want the statement cancelled")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ExecRetry() took %v, want it cancelled after 200ms", elapsed)
+	}
+
+	// Statements outside the helpers are cancelled by the server with statement_timeout.
+	_, err := pg.Pool.Exec(ctx, "SELECT pg_sleep(5)")
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "57014" {
+		t.Errorf("Pool.Exec() error = %v, want query_canceled", err)
+	}
+
+	if _, err := pg.ExecRetry(ctx, "SELECT pg_sleep(0.01)"); err != nil {
+		t.Errorf("ExecRetry() of a quick statement error = %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const _instrumentationName = "github.com/rdashevsky/go-pkgs/postgres"
+
+// WithTracer traces every query, batch and COPY in an OpenTelemetry client span, a child of
+// the span in the context of the caller. Spans record the statement, the number of
+// arguments and the number of rows affected; argument values are not recorded.
+// A nil provider is ignored.
+// Default is no tracing.
+//
+// Example:
+//
+//	pg, err := postgres.New(url, postgres.WithTracer(otel.GetTracerProvider()))
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(c *Postgres) {
+		if tp != nil {
+			QueryTracer(&otelTracer{tracer: tp.Tracer(_instrumentationName)})(c)
+		}
+	}
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+// spanKey holds the span a tracer started, as the context may carry spans of other tracers.
+type spanKey struct {
+	t *otelTracer
+}
+
+var (
+	_ pgx.CopyFromTracer = (*otelTracer)(nil)
+	_ pgx.BatchTracer    = (*otelTracer)(nil)
+)
+
+func (t *otelTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	op := Operation(data.SQL)
+
+	ctx, span := t.tracer.Start(ctx, "postgres "+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", op),
+			attribute.String("db.query.text", data.SQL),
+			attribute.Int("db.query.args_count", len(data.Args)),
+		),
+	)
+
+	return context.WithValue(ctx, spanKey{t}, span)
+}
+
+func (t *otelTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.CommandTag, data.Err)
+}
+
+func (t *otelTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	table := data.TableName.Sanitize()
+
+	ctx, span := t.tracer.Start(ctx, "postgres COPY",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", "COPY"),
+			attribute.String("db.collection.name", table),
+		),
+	)
+
+	return context.WithValue(ctx, spanKey{t}, span)
+}
+
+func (t *otelTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.end(ctx, data.CommandTag, data.Err)
+}
+
+func (t *otelTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	ctx, span := t.tracer.Start(ctx, "postgres BATCH",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", "BATCH"),
+			attribute.Int("db.operation.batch.size", data.Batch.Len()),
+		),
+	)
+
+	return context.WithValue(ctx, spanKey{t}, span)
+}
+
+// TraceBatchQuery records every statement of a batch as an event of the batch span.
+func (t *otelTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	span, ok := ctx.Value(spanKey{t}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("db.query.text", data.SQL)}
+	if data.Err != nil {
+		attrs = append(attrs, attribute.String("error.message", data.Err.Error()))
+	} else {
+		attrs = append(attrs, attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+
+	span.AddEvent("query", trace.WithAttributes(attrs...))
+}
+
+func (t *otelTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	span, ok := ctx.Value(spanKey{t}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+
+	span.End()
+}
+
+func (t *otelTracer) end(ctx context.Context, tag pgconn.CommandTag, err error) {
+	span, ok := ctx.Value(spanKey{t}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.rows_affected", tag.RowsAffected()))
+	}
+
+	span.End()
+}
+
+// Operation returns the upper-cased first keyword of a statement, e.g. SELECT, a low
+// cardinality name for spans and metrics.
+func Operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "UNKNOWN"
+	}
+
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	pg := &Postgres{}
+	WithTracer(tp)(pg)
+	WithTracer(nil)(pg)
+	WithTracer(tp)(pg)
+
+	if len(pg.tracers) != 2 {
+		t.Fatalf("got %d tracers, want 2", len(pg.tracers))
+	}
+
+	tracer := multiTracer(pg.tracers)
+	ctx := context.Background()
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "update users set name = $1 where id = $2", Args: []any{"a", 1}})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 3")})
+
+	cctx := tracer.TraceCopyFromStart(ctx, nil, pgx.TraceCopyFromStartData{TableName: pgx.Identifier{"audit", "events"}})
+	tracer.TraceCopyFromEnd(cctx, nil, pgx.TraceCopyFromEndData{Err: errors.New("copy failed")})
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d ended spans, want 4", len(spans))
+	}
+
+	update, copySpan := spans[1], spans[3]
+
+	if update.Name() != "postgres UPDATE" {
+		t.Errorf("span name = %q, want %q", update.Name(), "postgres UPDATE")
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		"db.operation.name":   attribute.StringValue("UPDATE"),
+		"db.query.args_count": attribute.IntValue(2),
+		"db.rows_affected":    attribute.Int64Value(3),
+	}
+
+	for _, kv := range update.Attributes() {
+		if v, ok := want[kv.Key]; ok && v != kv.Value {
+			t.Errorf("attribute %s = %v, want %v", kv.Key, kv.Value.Emit(), v.Emit())
+		}
+
+		delete(want, kv.Key)
+	}
+
+	if len(want) > 0 {
+		t.Errorf("missing attributes %v", want)
+	}
+
+	if copySpan.Name() != "postgres COPY" || copySpan.Status().Code != codes.Error {
+		t.Errorf("copy span = %q with status %v, want postgres COPY with error", copySpan.Name(), copySpan.Status().Code)
+	}
+}
+
+func TestWithTracer_Batch(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	pg := &Postgres{}
+	WithTracer(tp)(pg)
+	QueryTracer(queryMetrics{})(pg)
+
+	tracer := multiTracer(pg.tracers)
+
+	var batch pgx.Batch
+	batch.Queue("INSERT INTO events VALUES ($1)", 1)
+	batch.Queue("INSERT INTO events VALUES ($1)", 1)
+
+	ctx := tracer.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{Batch: &batch})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: batch.QueuedQueries[0].SQL, CommandTag: pgconn.NewCommandTag("INSERT 0 1")})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: batch.QueuedQueries[1].SQL, Err: errors.New("duplicate key")})
+	tracer.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{Err: errors.New("duplicate key")})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d ended spans, want 1", len(spans))
+	}
+
+	if spans[0].Name() != "postgres BATCH" || len(spans[0].Events()) != 3 || spans[0].Status().Code != codes.Error {
+		t.Errorf("batch span = %q with %d events and status %v, want postgres BATCH with 2 queries and an error",
+			spans[0].Name(), len(spans[0].Events()), spans[0].Status().Code)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := map[string]string{
+		"select 1":                   "SELECT",
+		"  INSERT INTO users VALUES": "INSERT",
+		"":                           "UNKNOWN",
+	}
+
+	for sql, want := range tests {
+		if got := Operation(sql); got != want {
+			t.Errorf("Operation(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
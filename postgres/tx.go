@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// SQLSTATE codes of transactions that may succeed when retried.
+const (
+	_serializationFailure = "40001"
+	_deadlockDetected     = "40P01"
+)
+
+const (
+	_txRetryInitialDelay = 10 * time.Millisecond
+	_txRetryMaxDelay     = time.Second
+)
+
+// TxOption configures a transaction started by WithTx.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	options  pgx.TxOptions
+	attempts int
+}
+
+// Isolation sets the isolation level of the transaction.
+// Default is the server default, usually read committed.
+func Isolation(level pgx.TxIsoLevel) TxOption {
+	return func(c *txConfig) {
+		c.options.IsoLevel = level
+	}
+}
+
+// ReadOnly makes the transaction read only.
+// Default is read write.
+func ReadOnly() TxOption {
+	return func(c *txConfig) {
+		c.options.AccessMode = pgx.ReadOnly
+	}
+}
+
+// RetrySerialization runs the transaction up to attempts times in total while it fails
+// with a serialization failure or a deadlock, which serializable and repeatable read
+// transactions must expect. The callback must then be safe to run more than once.
+// Default is 1, which disables retries.
+func RetrySerialization(attempts int) TxOption {
+	return func(c *txConfig) {
+		c.attempts = attempts
+	}
+}
+
+// WithTx runs fn in a transaction, committing it when fn returns nil and rolling it
+// back when fn returns an error or panics. The error of fn is returned unchanged.
+// Default configuration: server default isolation level, read write, no retries.
+//
+// Example:
+//
+//	err := pg.WithTx(ctx, func(tx pgx.Tx) error {
+//	    if _, err := tx.Exec(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from); err != nil {
+//	        return err
+//	    }
+//
+//	    _, err := tx.Exec(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", amount, to)
+//
+//	    return err
+//	}, postgres.Isolation(pgx.Serializable), postgres.RetrySerialization(5))
+func (p *Postgres) WithTx(ctx context.Context, fn func(tx pgx.Tx) error, opts ...TxOption) error {
+	cfg := txConfig{attempts: 1}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return retry.Do(ctx, func(ctx context.Context) error {
+		return p.runTx(ctx, fn, cfg.options)
+	},
+		retry.Attempts(cfg.attempts),
+		retry.ExponentialBackoff(_txRetryInitialDelay, _txRetryMaxDelay),
+		retry.Jitter(0.5),
+		retry.RetryIf(isSerializationError),
+	)
+}
+
+func (p *Postgres) runTx(ctx context.Context, fn func(tx pgx.Tx) error, options pgx.TxOptions) error {
+	tx, err := p.Pool.BeginTx(ctx, options)
+	if err != nil {
+		return fmt.Errorf("postgres - WithTx - p.Pool.BeginTx: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("postgres - WithTx - tx.Rollback: %w", rbErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("postgres - WithTx - tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// isSerializationError reports whether err is a serialization failure or a deadlock.
+func isSerializationError(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && (pgErr.Code == _serializationFailure || pgErr.Code == _deadlockDetected)
+}
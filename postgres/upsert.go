@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ErrInvalidUpsert is returned by UpsertBuilder.ToSql for statements missing columns,
+// rows, a conflict target or an action.
+var ErrInvalidUpsert = errors.New("postgres - invalid upsert")
+
+// UpsertBuilder builds an INSERT ... ON CONFLICT statement with dollar placeholders.
+// Like Squirrel builders it is immutable, each method returning a copy, and it can be
+// passed to ExecBuilders and Batch.QueueBuilder.
+type UpsertBuilder struct {
+	table      string
+	columns    []string
+	rows       [][]any
+	conflict   []string
+	constraint string
+	doNothing  bool
+	updateAll  bool
+	update     []string
+	set        []setClause
+	where      squirrel.Sqlizer
+	returning  []string
+}
+
+type setClause struct {
+	column string
+	value  any
+}
+
+// Upsert starts an upsert into table.
+//
+// Example:
+//
+//	query, args, err := postgres.Upsert("users").
+//	    Columns("email", "name", "updated_at").
+//	    Values("john@example.com", "John Doe", now).
+//	    OnConflict("email").
+//	    UpdateAll().
+//	    ToSql()
+//	// INSERT INTO users (email,name,updated_at) VALUES ($1,$2,$3)
+//	// ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+func Upsert(table string) UpsertBuilder {
+	return UpsertBuilder{table: table}
+}
+
+// Columns sets the inserted columns.
+func (b UpsertBuilder) Columns(columns ...string) UpsertBuilder {
+	b.columns = slices.Clone(columns)
+
+	return b
+}
+
+// Values adds a row of values, one per column.
+func (b UpsertBuilder) Values(values ...any) UpsertBuilder {
+	b.rows = append(slices.Clip(b.rows), values)
+
+	return b
+}
+
+// Rows adds rows of values, one per column each.
+func (b UpsertBuilder) Rows(rows ...[]any) UpsertBuilder {
+	b.rows = append(slices.Clip(b.rows), rows...)
+
+	return b
+}
+
+// OnConflict sets the columns of the unique index whose violation triggers the action.
+func (b UpsertBuilder) OnConflict(columns ...string) UpsertBuilder {
+	b.conflict = slices.Clone(columns)
+	b.constraint = ""
+
+	return b
+}
+
+// OnConstraint sets the unique constraint whose violation triggers the action, instead
+// of OnConflict columns.
+func (b UpsertBuilder) OnConstraint(name string) UpsertBuilder {
+	b.constraint = name
+	b.conflict = nil
+
+	return b
+}
+
+// DoNothing skips conflicting rows. A conflict target is then optional.
+func (b UpsertBuilder) DoNothing() UpsertBuilder {
+	b.doNothing = true
+
+	return b
+}
+
+// UpdateAll updates every inserted column but those of the conflict target with the
+// value of the conflicting row, as col = EXCLUDED.col.
+func (b UpsertBuilder) UpdateAll() UpsertBuilder {
+	b.updateAll = true
+
+	return b
+}
+
+// Update updates the columns with the value of the conflicting row, as col = EXCLUDED.col.
+func (b UpsertBuilder) Update(columns ...string) UpsertBuilder {
+	b.update = append(slices.Clip(b.update), columns...)
+
+	return b
+}
+
+// Set updates column with value, a Squirrel expression such as squirrel.Expr("NOW()")
+// or a plain value, after the columns of UpdateAll and Update.
+func (b UpsertBuilder) Set(column string, value any) UpsertBuilder {
+	b.set = append(slices.Clip(b.set), setClause{column: column, value: value})
+
+	return b
+}
+
+// Where only updates conflicting rows matching pred, e.g.
+// squirrel.Expr("users.updated_at < EXCLUDED.updated_at").
+func (b UpsertBuilder) Where(pred squirrel.Sqlizer) UpsertBuilder {
+	b.where = pred
+
+	return b
+}
+
+// Returning adds a RETURNING clause with the columns.
+func (b UpsertBuilder) Returning(columns ...string) UpsertBuilder {
+	b.returning = slices.Clone(columns)
+
+	return b
+}
+
+// ToSql builds the statement. It returns an error wrapping ErrInvalidUpsert when
+// there are no columns or rows, a row does not match the columns, or an update has
+// no conflict target or no column to set.
+func (b UpsertBuilder) ToSql() (string, []any, error) { //nolint:revive // ToSql implements squirrel.Sqlizer
+	if len(b.columns) == 0 || len(b.rows) == 0 {
+		return "", nil, fmt.Errorf("%w: no columns or rows", ErrInvalidUpsert)
+	}
+
+	insert := squirrel.Insert(b.table).Columns(b.columns...).PlaceholderFormat(squirrel.Dollar)
+
+	for i, row := range b.rows {
+		if len(row) != len(b.columns) {
+			return "", nil, fmt.Errorf("%w: row %d has %d values for %d columns", ErrInvalidUpsert, i, len(row), len(b.columns))
+		}
+
+		insert = insert.Values(row...)
+	}
+
+	conflict, err := b.conflictClause()
+	if err != nil {
+		return "", nil, err
+	}
+
+	insert = insert.SuffixExpr(conflict)
+
+	if len(b.returning) > 0 {
+		insert = insert.Suffix("RETURNING " + strings.Join(b.returning, ", "))
+	}
+
+	return insert.ToSql()
+}
+
+func (b UpsertBuilder) conflictClause() (squirrel.Sqlizer, error) {
+	var target string
+
+	switch {
+	case b.constraint != "":
+		target = " ON CONSTRAINT " + b.constraint
+	case len(b.conflict) > 0:
+		target = " (" + strings.Join(b.conflict, ", ") + ")"
+	}
+
+	if b.doNothing {
+		return squirrel.Expr("ON CONFLICT" + target + " DO NOTHING"), nil
+	}
+
+	if target == "" {
+		return nil, fmt.Errorf("%w: update without conflict target", ErrInvalidUpsert)
+	}
+
+	var (
+		sets []string
+		args []any
+	)
+
+	excluded := b.update
+	if b.updateAll {
+		excluded = nil
+
+		for _, c := range b.columns {
+			if !slices.Contains(b.conflict, c) {
+				excluded = append(excluded, c)
+			}
+		}
+
+		for _, c := range b.update {
+			if !slices.Contains(excluded, c) {
+				excluded = append(excluded, c)
+			}
+		}
+	}
+
+	for _, c := range excluded {
+		sets = append(sets, c+" = EXCLUDED."+c)
+	}
+
+	for _, s := range b.set {
+		if expr, ok := s.value.(squirrel.Sqlizer); ok {
+			sql, exprArgs, err := expr.ToSql()
+			if err != nil {
+				return nil, fmt.Errorf("postgres - Upsert - Set %s: %w", s.column, err)
+			}
+
+			sets = append(sets, s.column+" = "+sql)
+			args = append(args, exprArgs...)
+
+			continue
+		}
+
+		sets = append(sets, s.column+" = ?")
+		args = append(args, s.value)
+	}
+
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("%w: no column to update", ErrInvalidUpsert)
+	}
+
+	sql := "ON CONFLICT" + target + " DO UPDATE SET " + strings.Join(sets, ", ")
+
+	if b.where != nil {
+		whereSQL, whereArgs, err := b.where.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("postgres - Upsert - Where: %w", err)
+		}
+
+		sql += " WHERE " + whereSQL
+		args = append(args, whereArgs...)
+	}
+
+	return squirrel.Expr(sql, args...), nil
+}
@@ -0,0 +1,91 @@
+package postgres_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+func TestUpsert(t *testing.T) {
+	base := postgres.Upsert("users").Columns("email", "name", "updated_at").Values("john@example.com", "John", "now")
+
+	tests := []struct {
+		name string
+		b    postgres.UpsertBuilder
+		want string
+		args int
+	}{
+		{
+			name: "update all",
+			b:    base.OnConflict("email").UpdateAll(),
+			want: "INSERT INTO users (email,name,updated_at) VALUES ($1,$2,$3) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at",
+			args: 3,
+		},
+		{
+			name: "rows, update and set",
+			b: base.Rows([]any{"jane@example.com", "Jane", "now"}).
+				OnConflict("email").
+				Update("name").
+				Set("updated_at", squirrel.Expr("NOW()")).
+				Set("version", 2).
+				Returning("id"),
+			want: "INSERT INTO users (email,name,updated_at) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW(), version = $7 RETURNING id",
+			args: 7,
+		},
+		{
+			name: "constraint and where",
+			b: base.OnConstraint("users_email_key").
+				Update("name").
+				Where(squirrel.Expr("users.updated_at < ?", "then")),
+			want: "INSERT INTO users (email,name,updated_at) VALUES ($1,$2,$3) ON CONFLICT ON CONSTRAINT users_email_key DO UPDATE SET name = EXCLUDED.name WHERE users.updated_at < $4",
+			args: 4,
+		},
+		{
+			name: "do nothing",
+			b:    base.DoNothing(),
+			want: "INSERT INTO users (email,name,updated_at) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING",
+			args: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.b.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+
+			if sql != tt.want || len(args) != tt.args {
+				t.Errorf("ToSql() = %q with %d args, want %q with %d", sql, len(args), tt.want, tt.args)
+			}
+		})
+	}
+
+	// Builders are immutable.
+	if sql, _, _ := base.DoNothing().ToSql(); sql != tests[3].want {
+		t.Errorf("base modified: %q", sql)
+	}
+}
+
+func TestUpsert_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		b    postgres.UpsertBuilder
+	}{
+		{name: "no rows", b: postgres.Upsert("users").Columns("email").OnConflict("email").DoNothing()},
+		{name: "row length", b: postgres.Upsert("users").Columns("email", "name").Values("a").DoNothing()},
+		{name: "no target", b: postgres.Upsert("users").Columns("email", "name").Values("a", "b").UpdateAll()},
+		{name: "no action", b: postgres.Upsert("users").Columns("email", "name").Values("a", "b").OnConflict("email")},
+		{name: "nothing to update", b: postgres.Upsert("users").Columns("email").Values("a").OnConflict("email").UpdateAll()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.b.ToSql(); !errors.Is(err, postgres.ErrInvalidUpsert) {
+				t.Errorf("ToSql() error = %v, want ErrInvalidUpsert", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package profiling_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/profiling"
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+func ExampleNew() {
+	s3, err := storage.New("localhost:9000", "minioadmin", "minioadmin", "profiles", storage.Secure(false))
+	if err != nil {
+		return
+	}
+
+	p := profiling.New(
+		profiling.Addr(":6060"),
+		profiling.Storage(s3, "orders/"),
+		profiling.Pyroscope("http://pyroscope:4040", "orders",
+			profiling.Labels(map[string]string{"version": "1.4.2"}),
+		),
+	)
+	p.Start()
+	defer p.Shutdown() //nolint:errcheck // example
+
+	key, err := p.Capture(context.Background(), "heap")
+	if err != nil {
+		return
+	}
+
+	fmt.Println("heap profile stored at", key)
+}
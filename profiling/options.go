@@ -0,0 +1,92 @@
+package profiling
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+// Option configures a Profiler.
+type Option func(*Profiler)
+
+// Addr sets the listen address of the pprof server. It should not be publicly reachable.
+// Default is "127.0.0.1:6060".
+func Addr(addr string) Option {
+	return func(p *Profiler) {
+		p.addr = addr
+	}
+}
+
+// ShutdownTimeout sets the graceful shutdown timeout of the pprof server.
+// Default is 3 seconds.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(p *Profiler) {
+		p.shutdownTimeout = timeout
+	}
+}
+
+// Storage enables on-demand captures, storing profiles in s under keys starting with prefix.
+// Default is no storage.
+func Storage(s storage.Storage, prefix string) Option {
+	return func(p *Profiler) {
+		p.storage = s
+		p.prefix = prefix
+	}
+}
+
+// PyroscopeOption configures the Pyroscope push.
+type PyroscopeOption func(*pyroscope)
+
+// Pyroscope enables continuous profiling: CPU and heap profiles are pushed to the
+// Pyroscope server at serverURL under the application name.
+// Default is no push.
+func Pyroscope(serverURL, application string, opts ...PyroscopeOption) Option {
+	return func(p *Profiler) {
+		p.pyroscope = &pyroscope{
+			url:         strings.TrimRight(serverURL, "/"),
+			application: application,
+			interval:    _defaultPushInterval,
+			client:      &http.Client{Timeout: _defaultPushTimeout},
+		}
+
+		for _, opt := range opts {
+			opt(p.pyroscope)
+		}
+	}
+}
+
+// Labels sets static labels of the pushed profiles, e.g. version or region.
+// Default is no labels.
+func Labels(labels map[string]string) PyroscopeOption {
+	return func(c *pyroscope) {
+		c.labels = labels
+	}
+}
+
+// PushInterval sets the duration of each pushed CPU profile.
+// Default is 10 seconds.
+func PushInterval(interval time.Duration) PyroscopeOption {
+	return func(c *pyroscope) {
+		c.interval = interval
+	}
+}
+
+// BasicAuth sets the credentials of the Pyroscope server, e.g. for Grafana Cloud.
+// Default is no authentication.
+func BasicAuth(user, password string) PyroscopeOption {
+	return func(c *pyroscope) {
+		c.user = user
+		c.password = password
+	}
+}
+
+// WithLogger logs capture and push errors.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(p *Profiler) {
+		p.logger = l
+	}
+}
@@ -0,0 +1,303 @@
+// Package profiling serves pprof on a private port, captures CPU and heap profiles on
+// demand to the storage package, and pushes continuous profiles to Pyroscope.
+// Parca and Grafana Alloy can scrape the served pprof endpoints directly.
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	runtimepprof "runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+const (
+	_defaultAddr            = "127.0.0.1:6060"
+	_defaultReadTimeout     = 5 * time.Second
+	_defaultShutdownTimeout = 3 * time.Second
+	_defaultCPUDuration     = 30 * time.Second
+	_maxCPUDuration         = 5 * time.Minute
+	_contentType            = "application/octet-stream"
+)
+
+var (
+	// ErrBusy is returned when a CPU profile is requested while another one is running.
+	ErrBusy = errors.New("profiling - CPU profiling already in progress")
+	// ErrNoStorage is returned by captures when no storage is configured.
+	ErrNoStorage = errors.New("profiling - no storage configured")
+	// ErrUnknownProfile is returned when capturing a profile that does not exist.
+	ErrUnknownProfile = errors.New("profiling - unknown profile")
+)
+
+// Profiler serves pprof endpoints and captures profiles.
+type Profiler struct {
+	server *http.Server
+	notify chan error
+
+	addr            string
+	shutdownTimeout time.Duration
+
+	storage storage.Storage
+	prefix  string
+
+	pyroscope *pyroscope
+
+	// cpu serializes CPU profiling, of which the runtime supports one at a time.
+	cpu sync.Mutex
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger logger.LoggerI
+}
+
+// New creates a new Profiler. The pprof endpoints expose internals of the process
+// and must only be reachable from a private network.
+// Default configuration: listens on 127.0.0.1:6060, no storage, no Pyroscope push.
+//
+// Example:
+//
+//	p := profiling.New(
+//	    profiling.Addr(":6060"),
+//	    profiling.Storage(s3, "profiles/orders/"),
+//	    profiling.Pyroscope("http://pyroscope:4040", "orders"),
+//	)
+//	p.Start()
+func New(opts ...Option) *Profiler {
+	p := &Profiler{
+		notify:          make(chan error, 1),
+		addr:            _defaultAddr,
+		shutdownTimeout: _defaultShutdownTimeout,
+		cancel:          func() {},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.server = &http.Server{
+		Addr:              p.addr,
+		Handler:           p.Handler(),
+		ReadHeaderTimeout: _defaultReadTimeout,
+	}
+
+	return p
+}
+
+// Handler returns the pprof endpoints under /debug/pprof/, plus
+// POST /debug/pprof/capture?profile=<name>&seconds=<n> storing a profile with the
+// configured storage and responding with its key.
+func (p *Profiler) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", p.serveCPU)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("POST /debug/pprof/capture", p.serveCapture)
+
+	return mux
+}
+
+// Start begins serving the pprof endpoints and pushing profiles to Pyroscope
+// when configured. Use Notify() to receive server errors.
+func (p *Profiler) Start() {
+	if p.pyroscope != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+
+		p.wg.Add(1)
+
+		go p.push(ctx)
+	}
+
+	go func() {
+		if err := p.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			p.notify <- fmt.Errorf("profiling - Start - p.server.ListenAndServe: %w", err)
+		}
+
+		close(p.notify)
+	}()
+}
+
+// Notify returns a channel that receives an error if the server fails
+// and is closed when the server stops.
+func (p *Profiler) Notify() <-chan error {
+	return p.notify
+}
+
+// Shutdown stops pushing profiles and gracefully shuts down the server
+// within the configured timeout.
+func (p *Profiler) Shutdown() error {
+	p.cancel()
+	p.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.shutdownTimeout)
+	defer cancel()
+
+	if err := p.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("profiling - Shutdown - p.server.Shutdown: %w", err)
+	}
+
+	return nil
+}
+
+// CPUProfile records a CPU profile for duration or until ctx is done.
+// It returns ErrBusy if a CPU profile is already being recorded.
+func (p *Profiler) CPUProfile(ctx context.Context, duration time.Duration) ([]byte, error) {
+	if !p.cpu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer p.cpu.Unlock()
+
+	var buf bytes.Buffer
+
+	// Fails when profiling was started outside of the Profiler.
+	if err := runtimepprof.StartCPUProfile(&buf); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBusy, err)
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	runtimepprof.StopCPUProfile()
+
+	return buf.Bytes(), nil
+}
+
+// Profile returns a snapshot of the named runtime profile
+// ("heap", "allocs", "goroutine", "block", "mutex", "threadcreate").
+func (p *Profiler) Profile(name string) ([]byte, error) {
+	profile := runtimepprof.Lookup(name)
+	if profile == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+
+	var buf bytes.Buffer
+	if err := profile.WriteTo(&buf, 0); err != nil {
+		return nil, fmt.Errorf("profiling - Profile - WriteTo: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CaptureCPU records a CPU profile for duration and stores it with the configured
+// storage under "<prefix><hostname>/cpu-<timestamp>.pprof", returning the key.
+func (p *Profiler) CaptureCPU(ctx context.Context, duration time.Duration) (string, error) {
+	if p.storage == nil {
+		return "", ErrNoStorage
+	}
+
+	data, err := p.CPUProfile(ctx, duration)
+	if err != nil {
+		return "", err
+	}
+
+	return p.store(ctx, "cpu", data)
+}
+
+// Capture stores a snapshot of the named runtime profile with the configured storage
+// under "<prefix><hostname>/<name>-<timestamp>.pprof", returning the key.
+//
+// Example:
+//
+//	key, err := p.Capture(ctx, "heap")
+func (p *Profiler) Capture(ctx context.Context, name string) (string, error) {
+	if p.storage == nil {
+		return "", ErrNoStorage
+	}
+
+	data, err := p.Profile(name)
+	if err != nil {
+		return "", err
+	}
+
+	return p.store(ctx, name, data)
+}
+
+func (p *Profiler) store(ctx context.Context, name string, data []byte) (string, error) {
+	host, _ := os.Hostname()
+	key := fmt.Sprintf("%s%s/%s-%s.pprof", p.prefix, host, name, time.Now().UTC().Format("20060102T150405.000Z"))
+
+	if _, err := p.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), _contentType); err != nil {
+		return "", fmt.Errorf("profiling - store - p.storage.Put: %w", err)
+	}
+
+	return key, nil
+}
+
+// serveCPU replaces pprof.Profile so that HTTP and on-demand CPU profiles do not collide.
+func (p *Profiler) serveCPU(w http.ResponseWriter, r *http.Request) {
+	data, err := p.CPUProfile(r.Context(), seconds(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", _contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+	_, _ = w.Write(data)
+}
+
+func (p *Profiler) serveCapture(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("profile")
+	if name == "" {
+		name = "cpu"
+	}
+
+	var (
+		key string
+		err error
+	)
+
+	if name == "cpu" {
+		key, err = p.CaptureCPU(r.Context(), seconds(r))
+	} else {
+		key, err = p.Capture(r.Context(), name)
+	}
+
+	switch {
+	case errors.Is(err, ErrNoStorage):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrUnknownProfile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrBusy):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		if p.logger != nil {
+			p.logger.Error(err, "profiling - serveCapture")
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": key})
+	}
+}
+
+// seconds returns the CPU profile duration of the "seconds" query parameter.
+func seconds(r *http.Request) time.Duration {
+	s, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+	if err != nil || s <= 0 {
+		return _defaultCPUDuration
+	}
+
+	return min(time.Duration(s)*time.Second, _maxCPUDuration)
+}
@@ -0,0 +1,256 @@
+package profiling_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/profiling"
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *memoryStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) (storage.ObjectInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return storage.ObjectInfo{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.objects == nil {
+		s.objects = make(map[string][]byte)
+	}
+
+	s.objects[key] = data
+
+	return storage.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (s *memoryStorage) Get(context.Context, string) (io.ReadCloser, storage.ObjectInfo, error) {
+	return nil, storage.ObjectInfo{}, storage.ErrNotFound
+}
+
+func (s *memoryStorage) Delete(context.Context, string) error { return nil }
+
+func (s *memoryStorage) List(context.Context, string) ([]storage.ObjectInfo, error) { return nil, nil }
+
+func (s *memoryStorage) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+func (s *memoryStorage) PresignPut(context.Context, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+func (s *memoryStorage) object(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.objects[key]
+}
+
+func TestCapture(t *testing.T) {
+	store := &memoryStorage{}
+	p := profiling.New(profiling.Storage(store, "profiles/"))
+
+	key, err := p.Capture(context.Background(), "heap")
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+
+	if !strings.HasPrefix(key, "profiles/") || !strings.Contains(key, "/heap-") {
+		t.Errorf("key = %q, want profiles/<host>/heap-<timestamp>.pprof", key)
+	}
+
+	if len(store.object(key)) == 0 {
+		t.Error("stored profile is empty")
+	}
+
+	if _, err := p.Capture(context.Background(), "unknown"); !errors.Is(err, profiling.ErrUnknownProfile) {
+		t.Errorf("Capture(unknown) error = %v, want ErrUnknownProfile", err)
+	}
+
+	key, err = p.CaptureCPU(context.Background(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("CaptureCPU() error = %v", err)
+	}
+
+	if !strings.Contains(key, "/cpu-") || len(store.object(key)) == 0 {
+		t.Errorf("CPU profile %q not stored", key)
+	}
+}
+
+func TestCaptureWithoutStorage(t *testing.T) {
+	p := profiling.New()
+
+	if _, err := p.Capture(context.Background(), "heap"); !errors.Is(err, profiling.ErrNoStorage) {
+		t.Errorf("Capture() error = %v, want ErrNoStorage", err)
+	}
+}
+
+func TestCPUProfileBusy(t *testing.T) {
+	p := profiling.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		_, err := p.CPUProfile(ctx, time.Minute)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err := p.CPUProfile(context.Background(), time.Millisecond); !errors.Is(err, profiling.ErrBusy) {
+		t.Errorf("concurrent CPUProfile() error = %v, want ErrBusy", err)
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("CPUProfile() error = %v", err)
+	}
+}
+
+func TestCPUProfileStartedElsewhere(t *testing.T) {
+	if err := runtimepprof.StartCPUProfile(io.Discard); err != nil {
+		t.Fatalf("StartCPUProfile() error = %v", err)
+	}
+	defer runtimepprof.StopCPUProfile()
+
+	if _, err := profiling.New().CPUProfile(context.Background(), time.Millisecond); !errors.Is(err, profiling.ErrBusy) {
+		t.Errorf("CPUProfile() error = %v, want ErrBusy", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	p := profiling.New(profiling.Storage(&memoryStorage{}, ""))
+	srv := httptest.NewServer(p.Handler())
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "index", method: http.MethodGet, path: "/debug/pprof/", status: http.StatusOK},
+		{name: "heap", method: http.MethodGet, path: "/debug/pprof/heap", status: http.StatusOK},
+		{name: "cpu", method: http.MethodGet, path: "/debug/pprof/profile?seconds=1", status: http.StatusOK},
+		{name: "capture heap", method: http.MethodPost, path: "/debug/pprof/capture?profile=heap", status: http.StatusOK},
+		{name: "capture unknown", method: http.MethodPost, path: "/debug/pprof/capture?profile=unknown", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, http.NoBody)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+
+	resp, err := http.Post(srv.URL+"/debug/pprof/capture?profile=goroutine", "", http.NoBody)
+	if err != nil {
+		t.Fatalf("capture error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || !strings.Contains(body.Key, "/goroutine-") {
+		t.Errorf("capture response key = %q, %v", body.Key, err)
+	}
+}
+
+func TestPyroscope(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		names []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if r.URL.Path != "/ingest" || user != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		file, _, err := r.FormFile("profile")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		data, _ := io.ReadAll(file)
+		if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		mu.Lock()
+		names = append(names, r.URL.Query().Get("name"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	p := profiling.New(
+		profiling.Addr("127.0.0.1:0"),
+		profiling.Pyroscope(srv.URL, "orders",
+			profiling.PushInterval(50*time.Millisecond),
+			profiling.Labels(map[string]string{"region": "eu", "env": "test"}),
+			profiling.BasicAuth("user", "pass"),
+		),
+	)
+	p.Start()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := map[string]bool{
+		"orders.cpu{env=test,region=eu}":  false,
+		"orders.heap{env=test,region=eu}": false,
+	}
+
+	for _, name := range names {
+		want[name] = true
+	}
+
+	for name, pushed := range want {
+		if !pushed {
+			t.Errorf("profile %q not pushed, got %v", name, names)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_defaultPushInterval = 10 * time.Second
+	_defaultPushTimeout  = 10 * time.Second
+)
+
+type pyroscope struct {
+	url         string
+	application string
+	labels      map[string]string
+	interval    time.Duration
+	user        string
+	password    string
+	client      *http.Client
+}
+
+// push records back to back CPU profiles of the push interval and sends them,
+// together with a heap snapshot, to the Pyroscope ingest API.
+func (p *Profiler) push(ctx context.Context) {
+	defer p.wg.Done()
+
+	for ctx.Err() == nil {
+		from := time.Now()
+
+		cpu, err := p.CPUProfile(ctx, p.pyroscope.interval)
+		if err != nil {
+			// An on-demand CPU profile is running; retry on the next interval.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.pyroscope.interval):
+			}
+
+			continue
+		}
+
+		until := time.Now()
+
+		// The last profile is still sent when shutting down, with a fresh context.
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _defaultPushTimeout)
+
+		err = p.pyroscope.ingest(sendCtx, "cpu", cpu, from, until)
+		if err == nil {
+			var heap []byte
+
+			heap, err = p.Profile("heap")
+			if err == nil {
+				err = p.pyroscope.ingest(sendCtx, "heap", heap, from, until)
+			}
+		}
+
+		cancel()
+
+		if err != nil && p.logger != nil {
+			p.logger.Error(err, "profiling - push")
+		}
+	}
+}
+
+func (c *pyroscope) ingest(ctx context.Context, profile string, data []byte, from, until time.Time) error {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return fmt.Errorf("profiling - pyroscope - ingest - mw.CreateFormFile: %w", err)
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("profiling - pyroscope - ingest - part.Write: %w", err)
+	}
+
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("profiling - pyroscope - ingest - mw.Close: %w", err)
+	}
+
+	query := url.Values{
+		"name":    {c.name(profile)},
+		"from":    {strconv.FormatInt(from.Unix(), 10)},
+		"until":   {strconv.FormatInt(until.Unix(), 10)},
+		"format":  {"pprof"},
+		"spyName": {"gospy"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/ingest?"+query.Encode(), &body)
+	if err != nil {
+		return fmt.Errorf("profiling - pyroscope - ingest - http.NewRequestWithContext: %w", err)
+	}
+
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	if c.user != "" || c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("profiling - pyroscope - ingest - c.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+		return fmt.Errorf("profiling - pyroscope - ingest - unexpected status %d: %s", resp.StatusCode, msg)
+	}
+
+	return nil
+}
+
+// name returns the Pyroscope series name, "<application>.<profile>{label=value,...}".
+func (c *pyroscope) name(profile string) string {
+	keys := make([]string, 0, len(c.labels))
+	for k := range c.labels {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, k+"="+c.labels[k])
+	}
+
+	return c.application + "." + profile + "{" + strings.Join(labels, ",") + "}"
+}
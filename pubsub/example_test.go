@@ -0,0 +1,27 @@
+package pubsub_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/pubsub"
+)
+
+type OrderCreated struct {
+	ID    string
+	Total float64
+}
+
+func ExampleNewTopic() {
+	orders := pubsub.NewTopic[OrderCreated]("orders.created")
+
+	// The notifications module reacts to orders without depending on the orders module.
+	sub := orders.SubscribeFunc(func(o OrderCreated) {
+		fmt.Printf("notify order %s: %.2f\n", o.ID, o.Total)
+	})
+
+	_ = orders.Publish(context.Background(), OrderCreated{ID: "o-1", Total: 42})
+
+	sub.Unsubscribe()
+	// Output: notify order o-1: 42.00
+}
@@ -0,0 +1,40 @@
+package pubsub
+
+type topicConfig struct {
+	metrics Metrics
+}
+
+// Option configures a Topic.
+type Option func(*topicConfig)
+
+// WithMetrics sets the Metrics receiving topic measurements.
+// Default is no metrics.
+func WithMetrics(m Metrics) Option {
+	return func(c *topicConfig) {
+		c.metrics = m
+	}
+}
+
+type subscribeConfig struct {
+	size   int
+	policy Policy
+}
+
+// SubscribeOption configures a Subscription.
+type SubscribeOption func(*subscribeConfig)
+
+// BufferSize sets the number of values buffered for the subscriber.
+// Default is 64.
+func BufferSize(size int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.size = size
+	}
+}
+
+// OverflowPolicy sets what Publish does when the subscriber buffer is full.
+// Default is Block.
+func OverflowPolicy(p Policy) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.policy = p
+	}
+}
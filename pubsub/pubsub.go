@@ -0,0 +1,287 @@
+// Package pubsub provides in-process typed topics fanning out published values to
+// buffered subscribers, with per subscriber overflow policies and graceful unsubscribe.
+// It decouples modules inside one service; use the eventbus package to cross
+// service boundaries through Kafka or RabbitMQ.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const _defaultBufferSize = 64
+
+// ErrClosed is returned when publishing on a closed topic.
+var ErrClosed = errors.New("pubsub - topic is closed")
+
+// Policy decides what Publish does when a subscriber buffer is full.
+type Policy int
+
+const (
+	// Block waits for buffer space, applying backpressure to publishers.
+	Block Policy = iota
+	// DropNewest discards the published value for the slow subscriber.
+	DropNewest
+	// DropOldest discards the oldest buffered value to make room.
+	DropOldest
+)
+
+// Metrics receives topic measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObservePublish is called for every published value with the number of subscribers.
+	ObservePublish(topic string, subscribers int)
+	// ObserveDrop is called when a value is dropped for a full subscriber.
+	ObserveDrop(topic string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObservePublish(string, int) {}
+func (noopMetrics) ObserveDrop(string)         {}
+
+// Topic fans out values of type T to its subscribers. It is safe for concurrent use.
+type Topic[T any] struct {
+	name    string
+	metrics Metrics
+
+	// mu is held for reading while sending to subscribers and for writing while
+	// closing their channels, so values are never sent on a closed channel.
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+
+	// closing is closed first on Close to release publishers blocked on a subscriber.
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTopic creates a new Topic. The name identifies it in metrics.
+//
+// Example:
+//
+//	var OrderCreated = pubsub.NewTopic[Order]("orders.created")
+//
+//	sub := OrderCreated.Subscribe(pubsub.BufferSize(128))
+//	defer sub.Unsubscribe()
+//
+//	_ = OrderCreated.Publish(ctx, order)
+func NewTopic[T any](name string, opts ...Option) *Topic[T] {
+	cfg := topicConfig{metrics: noopMetrics{}}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Topic[T]{
+		name:    name,
+		metrics: cfg.metrics,
+		subs:    make(map[*Subscription[T]]struct{}),
+		closing: make(chan struct{}),
+	}
+}
+
+// Name returns the topic name.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish sends v to every subscriber, applying each subscriber Policy when its buffer
+// is full. With the Block policy it waits until there is room or ctx is done.
+func (t *Topic[T]) Publish(ctx context.Context, v T) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+
+	t.metrics.ObservePublish(t.name, len(t.subs))
+
+	for s := range t.subs {
+		if err := t.deliver(ctx, s, v); err != nil {
+			return fmt.Errorf("pubsub - Publish - %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Topic[T]) deliver(ctx context.Context, s *Subscription[T], v T) error {
+	switch s.policy {
+	case DropNewest:
+		return t.deliverOrDrop(s, v)
+	case DropOldest:
+		if cap(s.ch) == 0 {
+			return t.deliverOrDrop(s, v)
+		}
+
+		for {
+			select {
+			case s.ch <- v:
+				return nil
+			default:
+			}
+
+			select {
+			case <-s.ch:
+				t.metrics.ObserveDrop(t.name)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- v:
+		case <-s.done:
+		case <-t.closing:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (t *Topic[T]) deliverOrDrop(s *Subscription[T], v T) error {
+	select {
+	case s.ch <- v:
+	default:
+		t.metrics.ObserveDrop(t.name)
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber receiving values published from now on through
+// Subscription.C. Subscribing to a closed topic returns a closed subscription.
+func (t *Topic[T]) Subscribe(opts ...SubscribeOption) *Subscription[T] {
+	cfg := subscribeConfig{size: _defaultBufferSize, policy: Block}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	s := &Subscription[T]{
+		topic:  t,
+		ch:     make(chan T, max(cfg.size, 0)),
+		policy: cfg.policy,
+		done:   make(chan struct{}),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		s.closeOnce.Do(func() {
+			close(s.done)
+			close(s.ch)
+		})
+
+		return s
+	}
+
+	t.subs[s] = struct{}{}
+
+	return s
+}
+
+// SubscribeFunc subscribes fn, called sequentially with every value in its own
+// goroutine. Unsubscribe waits for fn to process the values already buffered,
+// so it must not be called from fn.
+func (t *Topic[T]) SubscribeFunc(fn func(v T), opts ...SubscribeOption) *Subscription[T] {
+	s := t.Subscribe(opts...)
+	s.handled = make(chan struct{})
+
+	go func() {
+		defer close(s.handled)
+
+		for v := range s.ch {
+			fn(v)
+		}
+	}()
+
+	return s
+}
+
+// Subscribers returns the number of active subscribers.
+func (t *Topic[T]) Subscribers() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.subs)
+}
+
+// Close unsubscribes every subscriber and rejects further publishing.
+// Values already buffered can still be received.
+func (t *Topic[T]) Close() {
+	first := false
+
+	t.closeOnce.Do(func() {
+		first = true
+		close(t.closing)
+	})
+
+	if !first {
+		return
+	}
+
+	t.mu.Lock()
+	t.closed = true
+	subs := t.subs
+	t.subs = make(map[*Subscription[T]]struct{})
+
+	for s := range subs {
+		s.closeOnce.Do(func() {
+			close(s.done)
+			close(s.ch)
+		})
+	}
+
+	t.mu.Unlock()
+
+	for s := range subs {
+		s.wait()
+	}
+}
+
+// Subscription receives the values published on a topic.
+type Subscription[T any] struct {
+	topic  *Topic[T]
+	ch     chan T
+	policy Policy
+
+	// done is closed first on unsubscribe to release publishers blocked on ch.
+	done      chan struct{}
+	closeOnce sync.Once
+	handled   chan struct{}
+}
+
+// C returns the channel receiving published values.
+// It is closed after Unsubscribe, once buffered values have been received.
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// Unsubscribe stops receiving new values and closes C. Values already buffered can
+// still be received; for SubscribeFunc subscriptions, Unsubscribe waits until they
+// are processed. Calling Unsubscribe more than once has no effect.
+func (s *Subscription[T]) Unsubscribe() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.topic.mu.Lock()
+		delete(s.topic.subs, s)
+		close(s.ch)
+		s.topic.mu.Unlock()
+	})
+
+	s.wait()
+}
+
+func (s *Subscription[T]) wait() {
+	if s.handled != nil {
+		<-s.handled
+	}
+}
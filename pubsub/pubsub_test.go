@@ -0,0 +1,195 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/pubsub"
+)
+
+func receive[T any](t *testing.T, sub *pubsub.Subscription[T]) []T {
+	t.Helper()
+
+	var got []T
+	for v := range sub.C() {
+		got = append(got, v)
+	}
+
+	return got
+}
+
+func TestTopic_FanOut(t *testing.T) {
+	ctx := context.Background()
+	topic := pubsub.NewTopic[int]("numbers")
+
+	a, b := topic.Subscribe(), topic.Subscribe()
+
+	if n := topic.Subscribers(); n != 2 {
+		t.Fatalf("Subscribers() = %d, want 2", n)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := topic.Publish(ctx, i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	a.Unsubscribe()
+	b.Unsubscribe()
+
+	for name, sub := range map[string]*pubsub.Subscription[int]{"a": a, "b": b} {
+		if got := receive(t, sub); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+			t.Errorf("subscriber %s received %v, want [1 2 3]", name, got)
+		}
+	}
+
+	if n := topic.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() after Unsubscribe() = %d, want 0", n)
+	}
+}
+
+func TestTopic_OverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy pubsub.Policy
+		want   []int
+	}{
+		{policy: pubsub.DropNewest, want: []int{1, 2}},
+		{policy: pubsub.DropOldest, want: []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		m := &countingMetrics{}
+		topic := pubsub.NewTopic[int]("numbers", pubsub.WithMetrics(m))
+		sub := topic.Subscribe(pubsub.BufferSize(2), pubsub.OverflowPolicy(tt.policy))
+
+		for i := 1; i <= 5; i++ {
+			if err := topic.Publish(context.Background(), i); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+		}
+
+		sub.Unsubscribe()
+
+		got := receive(t, sub)
+		if len(got) != 2 || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("policy %d: received %v, want %v", tt.policy, got, tt.want)
+		}
+
+		if m.drops.Load() != 3 || m.publishes.Load() != 5 {
+			t.Errorf("policy %d: metrics = %d drops, %d publishes; want 3, 5", tt.policy, m.drops.Load(), m.publishes.Load())
+		}
+	}
+}
+
+func TestTopic_BlockPolicy(t *testing.T) {
+	topic := pubsub.NewTopic[int]("numbers")
+	sub := topic.Subscribe(pubsub.BufferSize(1))
+
+	_ = topic.Publish(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := topic.Publish(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Publish() on full subscriber error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Unsubscribing releases a blocked publisher.
+	published := make(chan error)
+
+	go func() {
+		published <- topic.Publish(context.Background(), 3)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sub.Unsubscribe()
+
+	select {
+	case err := <-published:
+		if err != nil {
+			t.Errorf("Publish() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Publish() still blocked after Unsubscribe()")
+	}
+}
+
+func TestTopic_SubscribeFunc(t *testing.T) {
+	topic := pubsub.NewTopic[string]("words")
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+
+	sub := topic.SubscribeFunc(func(v string) {
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		got = append(got, v)
+		mu.Unlock()
+	})
+
+	for _, w := range []string{"a", "b", "c"} {
+		_ = topic.Publish(context.Background(), w)
+	}
+
+	// Unsubscribe waits for buffered values to be handled.
+	sub.Unsubscribe()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(got) != 3 {
+		t.Errorf("handled %v, want [a b c]", got)
+	}
+}
+
+func TestTopic_Close(t *testing.T) {
+	topic := pubsub.NewTopic[int]("numbers")
+	sub := topic.Subscribe(pubsub.BufferSize(1))
+
+	_ = topic.Publish(context.Background(), 1)
+
+	blocked := make(chan error)
+
+	go func() {
+		blocked <- topic.Publish(context.Background(), 2)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	topic.Close()
+	topic.Close()
+
+	if err := <-blocked; err != nil {
+		t.Errorf("blocked Publish() error = %v", err)
+	}
+
+	if got := receive(t, sub); len(got) != 1 {
+		t.Errorf("received %v after Close(), want [1]", got)
+	}
+
+	if err := topic.Publish(context.Background(), 3); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("Publish() after Close() error = %v, want %v", err, pubsub.ErrClosed)
+	}
+
+	late := topic.Subscribe()
+	if _, ok := <-late.C(); ok {
+		t.Error("subscription to a closed topic is open")
+	}
+
+	late.Unsubscribe()
+	sub.Unsubscribe()
+}
+
+type countingMetrics struct {
+	publishes atomic.Int32
+	drops     atomic.Int32
+}
+
+func (m *countingMetrics) ObservePublish(string, int) { m.publishes.Add(1) }
+func (m *countingMetrics) ObserveDrop(string)         { m.drops.Add(1) }
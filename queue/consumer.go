@@ -0,0 +1,315 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	_ackInterval  = 500 * time.Millisecond
+	_errorBackoff = time.Second
+)
+
+// Consumer receives messages from a queue and handles them concurrently. Handled
+// messages are acknowledged in batches, failed ones are released for redelivery,
+// and messages still being handled have their visibility timeout extended.
+type Consumer struct {
+	q   Queue
+	h   Handler
+	cfg consumerConfig
+
+	notify chan error
+	stop   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	once   sync.Once
+}
+
+// NewConsumer creates a new Consumer handling messages of q with h.
+// Default configuration: 10 concurrent handlers, batches of 10 messages, 30 second
+// visibility timeout, immediate retries without dead-lettering.
+//
+// Example:
+//
+//	c := queue.NewConsumer(q, func(ctx context.Context, m *queue.Message) error {
+//	    return svc.Handle(ctx, m.Body)
+//	}, queue.Concurrency(20), queue.MaxReceives(5), queue.DeadLetter(dlq), queue.WithLogger(l))
+//	c.Start()
+//	defer c.Shutdown()
+func NewConsumer(q Queue, h Handler, opts ...ConsumerOption) *Consumer {
+	return &Consumer{
+		q:      q,
+		h:      h,
+		cfg:    newConsumerConfig(opts),
+		notify: make(chan error, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start begins consuming in a separate goroutine.
+// Use Notify() to receive queue errors.
+func (c *Consumer) Start() {
+	c.start.Do(func() {
+		go c.run()
+	})
+}
+
+// Notify returns a channel that receives queue errors, such as failed receives or
+// acknowledgements. Handler errors are not reported. Errors are dropped while a
+// previous one has not been received.
+func (c *Consumer) Notify() <-chan error {
+	return c.notify
+}
+
+// Shutdown stops receiving, waits for in-flight handlers and acknowledges the
+// messages they handled.
+func (c *Consumer) Shutdown() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+
+	// Start may never have been called.
+	c.start.Do(func() { close(c.done) })
+
+	<-c.done
+
+	return nil
+}
+
+func (c *Consumer) run() {
+	defer close(c.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	acks := make(chan *Message, c.cfg.batchSize)
+	ackerDone := make(chan struct{})
+
+	go func() {
+		defer close(ackerDone)
+
+		c.acker(acks)
+	}()
+
+	slots := make(chan struct{}, c.cfg.concurrency)
+
+	var wg sync.WaitGroup
+
+	for c.acquire(ctx, slots) {
+		n := 1
+		for n < c.cfg.batchSize && tryAcquire(slots) {
+			n++
+		}
+
+		msgs, err := c.q.Receive(ctx, n, c.cfg.visibility)
+
+		for range n - len(msgs) {
+			<-slots
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+
+			c.report(fmt.Errorf("queue - Consumer - c.q.Receive: %w", err))
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(_errorBackoff):
+			}
+
+			continue
+		}
+
+		wg.Add(len(msgs))
+
+		for _, m := range msgs {
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				if c.handle(m) {
+					acks <- m
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+	close(acks)
+	<-ackerDone
+}
+
+// acquire waits for a free handler slot and reports false once stopped.
+func (c *Consumer) acquire(ctx context.Context, slots chan struct{}) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case slots <- struct{}{}:
+		if ctx.Err() != nil {
+			<-slots
+
+			return false
+		}
+
+		return true
+	}
+}
+
+func tryAcquire(slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// handle runs the handler and reports whether m must be acknowledged.
+// Handlers are not cancelled by Shutdown so that they can complete.
+func (c *Consumer) handle(m *Message) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go c.extend(ctx, m)
+
+	err := c.h(ctx, m)
+	if err == nil {
+		return true
+	}
+
+	if c.cfg.maxReceives > 0 && m.ReceiveCount >= c.cfg.maxReceives {
+		return c.exhausted(ctx, m, err)
+	}
+
+	c.logError("queue - Consumer - handler %s: %v", m.ID, err)
+
+	if err := c.q.Nack(ctx, m, c.cfg.retryDelay); err != nil {
+		c.report(fmt.Errorf("queue - Consumer - c.q.Nack: %w", err))
+	}
+
+	return false
+}
+
+// exhausted dead-letters a message that failed for the last time.
+func (c *Consumer) exhausted(ctx context.Context, m *Message, cause error) bool {
+	if c.cfg.deadLetter == nil {
+		c.logError("queue - Consumer - handler %s failed after %d receives, leaving it to the broker dead letter queue: %v",
+			m.ID, m.ReceiveCount, cause)
+
+		if err := c.q.Nack(ctx, m, c.cfg.retryDelay); err != nil {
+			c.report(fmt.Errorf("queue - Consumer - c.q.Nack: %w", err))
+		}
+
+		return false
+	}
+
+	c.logError("queue - Consumer - handler %s failed after %d receives, moving it to the dead letter queue: %v",
+		m.ID, m.ReceiveCount, cause)
+
+	dead := &Message{
+		Body:       m.Body,
+		Attributes: maps.Clone(m.Attributes),
+		GroupID:    m.GroupID,
+	}
+
+	if dead.Attributes == nil {
+		dead.Attributes = make(map[string]string, 2)
+	}
+
+	dead.Attributes[AttributeError] = cause.Error()
+	dead.Attributes[AttributeSourceID] = m.ID
+
+	if err := c.cfg.deadLetter.Send(ctx, dead); err != nil {
+		c.report(fmt.Errorf("queue - Consumer - c.cfg.deadLetter.Send: %w", err))
+
+		return false
+	}
+
+	return true
+}
+
+// extend keeps m invisible until ctx is done.
+func (c *Consumer) extend(ctx context.Context, m *Message) {
+	ticker := time.NewTicker(c.cfg.visibility / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.q.Extend(ctx, m, c.cfg.visibility)
+			if err != nil && ctx.Err() == nil {
+				c.report(fmt.Errorf("queue - Consumer - c.q.Extend: %w", err))
+			}
+		}
+	}
+}
+
+// acker acknowledges handled messages in batches until acks is closed. Batches are
+// flushed before handled messages, no longer extended, become visible again.
+func (c *Consumer) acker(acks <-chan *Message) {
+	ticker := time.NewTicker(min(_ackInterval, c.cfg.visibility/2))
+	defer ticker.Stop()
+
+	batch := make([]*Message, 0, c.cfg.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := c.q.Ack(context.Background(), batch...); err != nil {
+			c.report(fmt.Errorf("queue - Consumer - c.q.Ack: %w", err))
+		}
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case m, ok := <-acks:
+			if !ok {
+				flush()
+
+				return
+			}
+
+			batch = append(batch, m)
+			if len(batch) == c.cfg.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (c *Consumer) report(err error) {
+	c.logError(err)
+
+	select {
+	case c.notify <- err:
+	default:
+	}
+}
+
+func (c *Consumer) logError(message interface{}, args ...interface{}) {
+	if c.cfg.logger != nil {
+		c.cfg.logger.Error(message, args...)
+	}
+}
@@ -0,0 +1,39 @@
+package queue_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/queue"
+)
+
+func ExampleNewConsumer() {
+	// In production the queue is an SQS or RabbitMQ queue.
+	q := queue.NewMemory(queue.WaitTime(10 * time.Millisecond))
+	defer q.Close()
+
+	done := make(chan struct{})
+
+	c := queue.NewConsumer(q, func(_ context.Context, m *queue.Message) error {
+		fmt.Printf("handled %s for %s\n", m.Body, m.Attributes["tenant"])
+		close(done)
+
+		return nil
+	})
+	c.Start()
+
+	_ = q.Send(context.Background(), &queue.Message{
+		Body:       []byte("invoice-42"),
+		Attributes: map[string]string{"tenant": "acme"},
+	})
+
+	<-done
+	_ = c.Shutdown()
+
+	fmt.Println("pending:", q.Len())
+
+	// Output:
+	// handled invoice-42 for acme
+	// pending: 0
+}
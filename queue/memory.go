@@ -0,0 +1,242 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryMessage is a message stored by a Memory queue.
+type memoryMessage struct {
+	msg       Message
+	visibleAt time.Time
+	receipt   string
+}
+
+// Memory is an in-memory Queue for tests. It honours delays, visibility timeouts
+// and receive counts like SQS.
+type Memory struct {
+	cfg config
+
+	mu       sync.Mutex
+	messages []*memoryMessage
+	wake     chan struct{}
+	closed   bool
+}
+
+var _ Queue = (*Memory)(nil)
+
+// NewMemory creates a new in-memory queue. Only the WaitTime option applies.
+func NewMemory(opts ...Option) *Memory {
+	return &Memory{
+		cfg:  newConfig(opts),
+		wake: make(chan struct{}),
+	}
+}
+
+// Send enqueues m.
+func (q *Memory) Send(_ context.Context, m *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+
+	m.ID = uuid.NewString()
+
+	stored := *m
+	stored.Body = append([]byte(nil), m.Body...)
+	stored.Attributes = maps.Clone(m.Attributes)
+	stored.SentAt = time.Now()
+
+	q.messages = append(q.messages, &memoryMessage{
+		msg:       stored,
+		visibleAt: stored.SentAt.Add(m.Delay),
+	})
+	q.signal()
+
+	return nil
+}
+
+// SendBatch enqueues msgs.
+func (q *Memory) SendBatch(ctx context.Context, msgs []*Message) error {
+	for _, m := range msgs {
+		if err := q.Send(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Receive returns up to maxMessages visible messages, waiting up to the configured WaitTime
+// for one.
+func (q *Memory) Receive(ctx context.Context, maxMessages int, visibility time.Duration) ([]*Message, error) {
+	if visibility <= 0 {
+		visibility = _defaultVisibility
+	}
+
+	deadline := time.Now().Add(q.cfg.waitTime)
+
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+
+			return nil, ErrClosed
+		}
+
+		now := time.Now()
+		msgs, next := q.take(now, maxMessages, visibility)
+		wake := q.wake
+		q.mu.Unlock()
+
+		if len(msgs) > 0 || !now.Before(deadline) {
+			return msgs, nil
+		}
+
+		wait := deadline.Sub(now)
+		if !next.IsZero() && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		case <-wake:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+}
+
+// take receives up to maxMessages visible messages and returns the time the next invisible
+// message becomes visible. It must be called with q.mu held.
+func (q *Memory) take(now time.Time, maxMessages int, visibility time.Duration) ([]*Message, time.Time) {
+	var (
+		msgs []*Message
+		next time.Time
+	)
+
+	for _, stored := range q.messages {
+		if stored.visibleAt.After(now) {
+			if next.IsZero() || stored.visibleAt.Before(next) {
+				next = stored.visibleAt
+			}
+
+			continue
+		}
+
+		if len(msgs) == maxMessages {
+			break
+		}
+
+		stored.msg.ReceiveCount++
+		stored.visibleAt = now.Add(visibility)
+		stored.receipt = uuid.NewString()
+
+		m := stored.msg
+		m.Body = append([]byte(nil), stored.msg.Body...)
+		m.Attributes = maps.Clone(stored.msg.Attributes)
+		m.receipt = stored.receipt
+		msgs = append(msgs, &m)
+	}
+
+	return msgs, next
+}
+
+// Ack deletes received messages. Messages received again since are kept.
+func (q *Memory) Ack(_ context.Context, msgs ...*Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+
+	receipts := make(map[any]bool, len(msgs))
+	for _, m := range msgs {
+		receipts[m.receipt] = true
+	}
+
+	kept := q.messages[:0]
+
+	for _, stored := range q.messages {
+		if !receipts[stored.receipt] {
+			kept = append(kept, stored)
+		}
+	}
+
+	clear(q.messages[len(kept):])
+	q.messages = kept
+
+	return nil
+}
+
+// Extend keeps m invisible for visibility from now on.
+func (q *Memory) Extend(_ context.Context, m *Message, visibility time.Duration) error {
+	return q.setVisibility(m, visibility)
+}
+
+// Nack makes m visible again after delay.
+func (q *Memory) Nack(_ context.Context, m *Message, delay time.Duration) error {
+	return q.setVisibility(m, delay)
+}
+
+func (q *Memory) setVisibility(m *Message, d time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+
+	for _, stored := range q.messages {
+		if stored.receipt == m.receipt {
+			stored.visibleAt = time.Now().Add(d)
+			q.signal()
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("queue - Memory - message %s: receipt is no longer valid", m.ID)
+}
+
+// Len returns the number of messages in the queue, visible or not.
+func (q *Memory) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.messages)
+}
+
+// Close discards the messages and wakes up pending receives.
+func (q *Memory) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		q.messages = nil
+		q.signal()
+	}
+
+	return nil
+}
+
+// signal wakes up pending receives. It must be called with q.mu held.
+func (q *Memory) signal() {
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
@@ -0,0 +1,153 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultWaitTime    = 20 * time.Second
+	_defaultPrefetch    = 32
+	_defaultConcurrency = 10
+	_defaultBatchSize   = 10
+	_defaultVisibility  = 30 * time.Second
+)
+
+// Option configures a Queue. Options a queue does not use are ignored.
+type Option func(*config)
+
+type config struct {
+	waitTime time.Duration
+	prefetch int
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		waitTime: _defaultWaitTime,
+		prefetch: _defaultPrefetch,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WaitTime sets how long Receive waits for a message when the queue is empty.
+// SQS long polling supports at most 20 seconds.
+// Default is 20 seconds.
+func WaitTime(d time.Duration) Option {
+	return func(c *config) {
+		c.waitTime = d
+	}
+}
+
+// Prefetch sets the RabbitMQ prefetch count: the number of unacknowledged messages
+// delivered to the queue before it stops receiving. It should be at least the
+// consumer concurrency.
+// Default is 32.
+func Prefetch(n int) Option {
+	return func(c *config) {
+		c.prefetch = n
+	}
+}
+
+// ConsumerOption configures a Consumer.
+type ConsumerOption func(*consumerConfig)
+
+type consumerConfig struct {
+	concurrency int
+	batchSize   int
+	visibility  time.Duration
+	retryDelay  time.Duration
+	maxReceives int
+	deadLetter  Sender
+	logger      logger.LoggerI
+}
+
+func newConsumerConfig(opts []ConsumerOption) consumerConfig {
+	cfg := consumerConfig{
+		concurrency: _defaultConcurrency,
+		batchSize:   _defaultBatchSize,
+		visibility:  _defaultVisibility,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Concurrency sets the maximum number of messages handled at the same time.
+// Default is 10.
+func Concurrency(n int) ConsumerOption {
+	return func(c *consumerConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// BatchSize sets the maximum number of messages received and acknowledged per
+// request. SQS accepts at most 10.
+// Default is 10.
+func BatchSize(n int) ConsumerOption {
+	return func(c *consumerConfig) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// VisibilityTimeout sets how long received messages stay invisible to other
+// consumers. The consumer extends the timeout of messages still being handled at
+// half of it, so it only bounds how soon messages of a crashed consumer are
+// redelivered.
+// Default is 30 seconds.
+func VisibilityTimeout(d time.Duration) ConsumerOption {
+	return func(c *consumerConfig) {
+		if d > 0 {
+			c.visibility = d
+		}
+	}
+}
+
+// RetryDelay sets how long a failed message stays invisible before redelivery.
+// RabbitMQ requeues failed messages immediately.
+// Default is 0: failed messages are redelivered immediately.
+func RetryDelay(d time.Duration) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.retryDelay = d
+	}
+}
+
+// MaxReceives sets the receive count after which a failing message is dead-lettered:
+// moved to the DeadLetter queue if set, or left to the broker otherwise, e.g. an SQS
+// redrive policy with the same maxReceiveCount (see SQS.RedrivePolicy).
+// Default is 0: messages are retried forever.
+func MaxReceives(n int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.maxReceives = n
+	}
+}
+
+// DeadLetter sets the queue failing messages are moved to once they reach MaxReceives.
+// Moved messages keep their body and attributes, plus the AttributeError and
+// AttributeSourceID attributes.
+// Default is nil: exhausted messages are logged and left to the broker.
+func DeadLetter(s Sender) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.deadLetter = s
+	}
+}
+
+// WithLogger sets the logger reporting handler and queue errors.
+// Default is nil: queue errors are only reported through Notify.
+func WithLogger(l logger.LoggerI) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,84 @@
+// Package queue provides a broker-agnostic work queue abstraction: producers send
+// messages, and a Consumer receives them in batches, runs a handler with bounded
+// concurrency, keeps in-flight messages invisible while they are processed and
+// acknowledges them in batches. Queues are backed by AWS SQS (or any SQS-compatible
+// service such as ElasticMQ or LocalStack), RabbitMQ, or memory for tests.
+//
+// Delivery is at least once: a message whose handler fails, or whose consumer stops
+// before acknowledging it, is delivered again, so handlers must be idempotent.
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Attribute names set on messages moved to a dead letter queue by a Consumer.
+const (
+	AttributeError    = "queue-error"
+	AttributeSourceID = "queue-source-id"
+)
+
+var (
+	// ErrClosed is returned when using a closed queue.
+	ErrClosed = errors.New("queue - queue is closed")
+	// ErrNotSupported is returned when a queue does not support a message feature,
+	// e.g. delayed delivery on RabbitMQ.
+	ErrNotSupported = errors.New("queue - not supported")
+)
+
+// Message is a queued message. Fields above ReceiveCount are set by producers;
+// the others are filled on receive.
+type Message struct {
+	// ID identifies the message; Send sets it to the broker assigned ID.
+	ID string
+	// Body is the message payload. SQS only accepts valid UTF-8 text bodies.
+	Body []byte
+	// Attributes carry metadata such as trace context.
+	Attributes map[string]string
+	// Delay postpones the first delivery of the message.
+	Delay time.Duration
+	// GroupID orders messages of a FIFO queue. Ignored by other queues.
+	GroupID string
+	// DeduplicationID deduplicates messages of a FIFO queue. Ignored by other queues.
+	DeduplicationID string
+
+	// ReceiveCount is the number of times the message has been received, including
+	// this one. Brokers not tracking it report 1, or 2 for redeliveries.
+	ReceiveCount int
+	// SentAt is the time the message was sent, when the broker reports it.
+	SentAt time.Time
+
+	// receipt identifies this delivery to Ack, Extend and Nack.
+	receipt any
+}
+
+// Sender sends messages.
+type Sender interface {
+	// Send sends m and sets its ID.
+	Send(ctx context.Context, m *Message) error
+}
+
+// Queue is a message queue.
+type Queue interface {
+	Sender
+	// SendBatch sends msgs, in several requests when the broker limits batch sizes.
+	SendBatch(ctx context.Context, msgs []*Message) error
+	// Receive returns up to maxMessages messages, waiting for the first one as configured by
+	// WaitTime. It may return no messages. Received messages stay invisible to other
+	// consumers for visibility, or the queue default when zero, unless acknowledged,
+	// extended or released before.
+	Receive(ctx context.Context, maxMessages int, visibility time.Duration) ([]*Message, error)
+	// Ack deletes received messages.
+	Ack(ctx context.Context, msgs ...*Message) error
+	// Extend keeps a received message invisible for visibility from now on.
+	Extend(ctx context.Context, m *Message, visibility time.Duration) error
+	// Nack releases a received message for redelivery after delay.
+	Nack(ctx context.Context, m *Message, delay time.Duration) error
+	// Close releases the queue resources.
+	Close() error
+}
+
+// Handler processes a message. A returned error marks the delivery as failed.
+type Handler func(ctx context.Context, m *Message) error
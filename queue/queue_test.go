@@ -0,0 +1,327 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/containers"
+	"github.com/rdashevsky/go-pkgs/queue"
+)
+
+func receive(t *testing.T, q queue.Queue, n int, visibility time.Duration) []*queue.Message {
+	t.Helper()
+
+	msgs, err := q.Receive(context.Background(), n, visibility)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+
+	return msgs
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+	defer q.Close()
+
+	m := &queue.Message{Body: []byte("a"), Attributes: map[string]string{"trace": "t-1"}}
+	if err := q.Send(ctx, m); err != nil || m.ID == "" {
+		t.Fatalf("Send() = %v, ID %q", err, m.ID)
+	}
+
+	if err := q.Send(ctx, &queue.Message{Body: []byte("delayed"), Delay: time.Hour}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	msgs := receive(t, q, 10, time.Hour)
+	if len(msgs) != 1 {
+		t.Fatalf("Receive() = %d messages, want 1", len(msgs))
+	}
+
+	got := msgs[0]
+	if got.ID != m.ID || string(got.Body) != "a" || got.Attributes["trace"] != "t-1" || got.ReceiveCount != 1 || got.SentAt.IsZero() {
+		t.Errorf("Receive() = %+v", got)
+	}
+
+	if msgs := receive(t, q, 10, time.Hour); len(msgs) != 0 {
+		t.Errorf("Receive() of invisible messages = %d messages, want 0", len(msgs))
+	}
+
+	if err := q.Nack(ctx, got, 0); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+
+	msgs = receive(t, q, 10, time.Hour)
+	if len(msgs) != 1 || msgs[0].ReceiveCount != 2 {
+		t.Fatalf("Receive() after Nack = %+v, want one message received twice", msgs)
+	}
+
+	// The receipt of the first delivery is no longer valid.
+	if err := q.Extend(ctx, got, time.Hour); err == nil {
+		t.Error("Extend() with a stale receipt: expected error")
+	}
+
+	if err := q.Ack(ctx, msgs...); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+
+	if q.Len() != 1 {
+		t.Errorf("Len() = %d, want the delayed message only", q.Len())
+	}
+}
+
+func TestMemoryReceiveWaits(t *testing.T) {
+	q := queue.NewMemory(queue.WaitTime(time.Second))
+	defer q.Close()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+
+		_ = q.Send(context.Background(), &queue.Message{Body: []byte("late")})
+	}()
+
+	start := time.Now()
+
+	msgs := receive(t, q, 1, 0)
+	if len(msgs) != 1 || string(msgs[0].Body) != "late" {
+		t.Fatalf("Receive() = %+v, want the late message", msgs)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Receive() took %v, want it to return on send", elapsed)
+	}
+
+	_ = q.Close()
+
+	if _, err := q.Receive(context.Background(), 1, 0); !errors.Is(err, queue.ErrClosed) {
+		t.Errorf("Receive() on closed queue error = %v, want ErrClosed", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+	defer q.Close()
+
+	var (
+		mu      sync.Mutex
+		handled = map[string]int{}
+	)
+
+	c := queue.NewConsumer(q, func(_ context.Context, m *queue.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled[string(m.Body)]++
+
+		// "flaky" fails on its first delivery.
+		if string(m.Body) == "flaky" && m.ReceiveCount == 1 {
+			return errors.New("temporary failure")
+		}
+
+		return nil
+	}, queue.Concurrency(3), queue.BatchSize(2))
+
+	c.Start()
+	defer c.Shutdown()
+
+	msgs := []*queue.Message{{Body: []byte("a")}, {Body: []byte("b")}, {Body: []byte("c")}, {Body: []byte("flaky")}}
+	if err := q.SendBatch(ctx, msgs); err != nil {
+		t.Fatalf("SendBatch() error = %v", err)
+	}
+
+	waitFor(t, func() bool { return q.Len() == 0 })
+
+	if err := c.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := map[string]int{"a": 1, "b": 1, "c": 1, "flaky": 2}
+	for body, n := range want {
+		if handled[body] != n {
+			t.Errorf("message %q handled %d times, want %d", body, handled[body], n)
+		}
+	}
+}
+
+func TestConsumerLifecycle(t *testing.T) {
+	q := queue.NewMemory(queue.WaitTime(10 * time.Millisecond))
+	defer q.Close()
+
+	h := func(context.Context, *queue.Message) error { return nil }
+
+	// Shutdown returns when Start was never called, and Start then does nothing.
+	idle := queue.NewConsumer(q, h)
+	if err := idle.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() without Start error = %v", err)
+	}
+
+	idle.Start()
+
+	c := queue.NewConsumer(q, h)
+	c.Start()
+	c.Start()
+
+	if err := c.Shutdown(); err != nil {
+		t.Errorf("Shutdown() after a double Start error = %v", err)
+	}
+}
+
+func TestConsumerDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+	dlq := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+
+	var calls atomic.Int32
+
+	c := queue.NewConsumer(q, func(context.Context, *queue.Message) error {
+		calls.Add(1)
+
+		return errors.New("boom")
+	}, queue.MaxReceives(3), queue.DeadLetter(dlq))
+
+	c.Start()
+	defer c.Shutdown()
+
+	m := &queue.Message{Body: []byte("poison"), Attributes: map[string]string{"trace": "t-1"}}
+	if err := q.Send(ctx, m); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	waitFor(t, func() bool { return q.Len() == 0 })
+
+	if calls.Load() != 3 {
+		t.Errorf("handler calls = %d, want 3", calls.Load())
+	}
+
+	msgs := receive(t, dlq, 10, 0)
+	if len(msgs) != 1 {
+		t.Fatalf("dead letter queue has %d messages, want 1", len(msgs))
+	}
+
+	dead := msgs[0]
+	if string(dead.Body) != "poison" || dead.Attributes["trace"] != "t-1" ||
+		dead.Attributes[queue.AttributeError] != "boom" || dead.Attributes[queue.AttributeSourceID] != m.ID {
+		t.Errorf("dead letter = %+v", dead)
+	}
+}
+
+func TestConsumerExtendsVisibility(t *testing.T) {
+	q := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+	defer q.Close()
+
+	var calls atomic.Int32
+
+	c := queue.NewConsumer(q, func(context.Context, *queue.Message) error {
+		calls.Add(1)
+		time.Sleep(300 * time.Millisecond)
+
+		return nil
+	}, queue.VisibilityTimeout(100*time.Millisecond))
+
+	c.Start()
+	defer c.Shutdown()
+
+	if err := q.Send(context.Background(), &queue.Message{Body: []byte("slow")}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	waitFor(t, func() bool { return q.Len() == 0 })
+
+	if calls.Load() != 1 {
+		t.Errorf("handler calls = %d, want 1: the message was redelivered while handled", calls.Load())
+	}
+}
+
+func TestConsumerShutdownWaitsForHandlers(t *testing.T) {
+	q := queue.NewMemory(queue.WaitTime(20 * time.Millisecond))
+	defer q.Close()
+
+	started := make(chan struct{})
+
+	var finished atomic.Bool
+
+	c := queue.NewConsumer(q, func(context.Context, *queue.Message) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+
+		return nil
+	})
+
+	c.Start()
+
+	if err := q.Send(context.Background(), &queue.Message{Body: []byte("x")}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	<-started
+
+	if err := c.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if !finished.Load() {
+		t.Error("Shutdown() returned before the handler finished")
+	}
+
+	if q.Len() != 0 {
+		t.Errorf("Len() = %d, want the handled message acknowledged", q.Len())
+	}
+}
+
+func TestRabbitMQ(t *testing.T) {
+	ctx := context.Background()
+
+	q, err := queue.NewRabbitMQ(containers.RabbitMQ(t), "queue-test", queue.WaitTime(time.Second))
+	if err != nil {
+		t.Fatalf("NewRabbitMQ() error = %v", err)
+	}
+	defer q.Close()
+
+	if err := q.Send(ctx, &queue.Message{Body: []byte("x"), Delay: time.Second}); !errors.Is(err, queue.ErrNotSupported) {
+		t.Errorf("Send() with delay error = %v, want ErrNotSupported", err)
+	}
+
+	if err := q.Send(ctx, &queue.Message{Body: []byte("x"), Attributes: map[string]string{"trace": "t-1"}}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	msgs := receive(t, q, 10, 0)
+	if len(msgs) != 1 || string(msgs[0].Body) != "x" || msgs[0].Attributes["trace"] != "t-1" {
+		t.Fatalf("Receive() = %+v", msgs)
+	}
+
+	if err := q.Nack(ctx, msgs[0], 0); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+
+	msgs = receive(t, q, 10, 0)
+	if len(msgs) != 1 || msgs[0].ReceiveCount < 2 {
+		t.Fatalf("Receive() after Nack = %+v, want a redelivery", msgs)
+	}
+
+	if err := q.Ack(ctx, msgs...); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/goccy/go-json"
+)
+
+// _sqsBatchSize is the maximum number of entries of an SQS batch request.
+const _sqsBatchSize = 10
+
+// SQSClient is the subset of *sqs.Client used by the SQS queue.
+type SQSClient interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	SendMessageBatch(
+		ctx context.Context,
+		params *sqs.SendMessageBatchInput,
+		optFns ...func(*sqs.Options),
+	) (*sqs.SendMessageBatchOutput, error)
+	ReceiveMessage(
+		ctx context.Context,
+		params *sqs.ReceiveMessageInput,
+		optFns ...func(*sqs.Options),
+	) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessageBatch(
+		ctx context.Context,
+		params *sqs.DeleteMessageBatchInput,
+		optFns ...func(*sqs.Options),
+	) (*sqs.DeleteMessageBatchOutput, error)
+	ChangeMessageVisibility(
+		ctx context.Context,
+		params *sqs.ChangeMessageVisibilityInput,
+		optFns ...func(*sqs.Options),
+	) (*sqs.ChangeMessageVisibilityOutput, error)
+	GetQueueAttributes(
+		ctx context.Context,
+		params *sqs.GetQueueAttributesInput,
+		optFns ...func(*sqs.Options),
+	) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// SQS is a Queue backed by an AWS SQS queue. Receive long polls for up to WaitTime,
+// batches are split into requests of 10 entries and attributes are sent as string
+// message attributes.
+type SQS struct {
+	client SQSClient
+	url    string
+	cfg    config
+}
+
+var _ Queue = (*SQS)(nil)
+
+// NewSQS creates a new queue sending to and receiving from the queue at queueURL.
+// Only the WaitTime option applies.
+//
+// Example:
+//
+//	awsCfg, err := config.LoadDefaultConfig(ctx)
+//	q := queue.NewSQS(sqs.NewFromConfig(awsCfg), "https://sqs.eu-west-1.amazonaws.com/123456789012/orders")
+func NewSQS(client SQSClient, queueURL string, opts ...Option) *SQS {
+	return &SQS{
+		client: client,
+		url:    queueURL,
+		cfg:    newConfig(opts),
+	}
+}
+
+// Send sends m. Delays are limited to 15 minutes by SQS.
+func (q *SQS) Send(ctx context.Context, m *Message) error {
+	out, err := q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:               aws.String(q.url),
+		MessageBody:            aws.String(string(m.Body)),
+		DelaySeconds:           seconds(m.Delay),
+		MessageAttributes:      sqsAttributes(m.Attributes),
+		MessageGroupId:         optional(m.GroupID),
+		MessageDeduplicationId: optional(m.DeduplicationID),
+	})
+	if err != nil {
+		return fmt.Errorf("queue - SQS - Send - q.client.SendMessage: %w", err)
+	}
+
+	m.ID = aws.ToString(out.MessageId)
+
+	return nil
+}
+
+// SendBatch sends msgs in requests of 10 messages. Messages failing individually are
+// reported together after all requests are made; the others have their ID set.
+func (q *SQS) SendBatch(ctx context.Context, msgs []*Message) error {
+	var errs []error
+
+	for chunk := range chunks(msgs) {
+		entries := make([]types.SendMessageBatchRequestEntry, len(chunk))
+		for i, m := range chunk {
+			entries[i] = types.SendMessageBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(i)),
+				MessageBody:            aws.String(string(m.Body)),
+				DelaySeconds:           seconds(m.Delay),
+				MessageAttributes:      sqsAttributes(m.Attributes),
+				MessageGroupId:         optional(m.GroupID),
+				MessageDeduplicationId: optional(m.DeduplicationID),
+			}
+		}
+
+		out, err := q.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(q.url),
+			Entries:  entries,
+		})
+		if err != nil {
+			return fmt.Errorf("queue - SQS - SendBatch - q.client.SendMessageBatch: %w", err)
+		}
+
+		for _, entry := range out.Successful {
+			i, _ := strconv.Atoi(aws.ToString(entry.Id))
+			chunk[i].ID = aws.ToString(entry.MessageId)
+		}
+
+		errs = append(errs, batchErrors(out.Failed)...)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("queue - SQS - SendBatch: %w", err)
+	}
+
+	return nil
+}
+
+// Receive long polls for up to maxMessages messages, at most 10.
+func (q *SQS) Receive(ctx context.Context, maxMessages int, visibility time.Duration) ([]*Message, error) {
+	out, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(q.url),
+		MaxNumberOfMessages:   int32(min(max(maxMessages, 1), _sqsBatchSize)), //nolint:gosec // bounded
+		WaitTimeSeconds:       min(seconds(q.cfg.waitTime), 20),
+		VisibilityTimeout:     seconds(visibility),
+		MessageAttributeNames: []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameApproximateReceiveCount,
+			types.MessageSystemAttributeNameSentTimestamp,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("queue - SQS - Receive - q.client.ReceiveMessage: %w", err)
+	}
+
+	msgs := make([]*Message, len(out.Messages))
+	for i := range out.Messages {
+		msgs[i] = messageFromSQS(&out.Messages[i])
+	}
+
+	return msgs, nil
+}
+
+// Ack deletes msgs in requests of 10 messages.
+func (q *SQS) Ack(ctx context.Context, msgs ...*Message) error {
+	var errs []error
+
+	for chunk := range chunks(msgs) {
+		entries := make([]types.DeleteMessageBatchRequestEntry, len(chunk))
+		for i, m := range chunk {
+			entries[i] = types.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(i)),
+				ReceiptHandle: aws.String(receiptHandle(m)),
+			}
+		}
+
+		out, err := q.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(q.url),
+			Entries:  entries,
+		})
+		if err != nil {
+			return fmt.Errorf("queue - SQS - Ack - q.client.DeleteMessageBatch: %w", err)
+		}
+
+		errs = append(errs, batchErrors(out.Failed)...)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("queue - SQS - Ack: %w", err)
+	}
+
+	return nil
+}
+
+// Extend changes the visibility timeout of m to visibility from now on.
+func (q *SQS) Extend(ctx context.Context, m *Message, visibility time.Duration) error {
+	if err := q.changeVisibility(ctx, m, visibility); err != nil {
+		return fmt.Errorf("queue - SQS - Extend - %w", err)
+	}
+
+	return nil
+}
+
+// Nack changes the visibility timeout of m to delay, making it visible again then.
+func (q *SQS) Nack(ctx context.Context, m *Message, delay time.Duration) error {
+	if err := q.changeVisibility(ctx, m, delay); err != nil {
+		return fmt.Errorf("queue - SQS - Nack - %w", err)
+	}
+
+	return nil
+}
+
+func (q *SQS) changeVisibility(ctx context.Context, m *Message, d time.Duration) error {
+	_, err := q.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(q.url),
+		ReceiptHandle:     aws.String(receiptHandle(m)),
+		VisibilityTimeout: seconds(d),
+	})
+	if err != nil {
+		return fmt.Errorf("q.client.ChangeMessageVisibility: %w", err)
+	}
+
+	return nil
+}
+
+// RedrivePolicy is the dead letter configuration of an SQS queue.
+type RedrivePolicy struct {
+	// DeadLetterTargetARN is the ARN of the dead letter queue.
+	DeadLetterTargetARN string
+	// MaxReceiveCount is the receive count after which messages are moved.
+	MaxReceiveCount int
+}
+
+// RedrivePolicy returns the dead letter configuration of the queue, or nil when the
+// queue has none. Its MaxReceiveCount is usually passed to the MaxReceives consumer
+// option, so that exhausted messages are reported.
+func (q *SQS) RedrivePolicy(ctx context.Context) (*RedrivePolicy, error) {
+	out, err := q.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.url),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameRedrivePolicy},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("queue - SQS - RedrivePolicy - q.client.GetQueueAttributes: %w", err)
+	}
+
+	raw := out.Attributes[string(types.QueueAttributeNameRedrivePolicy)]
+	if raw == "" {
+		return nil, nil //nolint:nilnil // no redrive policy
+	}
+
+	// maxReceiveCount is a number or a string depending on how the policy was set.
+	var policy struct {
+		DeadLetterTargetARN string      `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.Number `json:"maxReceiveCount"`
+	}
+
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		return nil, fmt.Errorf("queue - SQS - RedrivePolicy - json.Unmarshal: %w", err)
+	}
+
+	n, err := strconv.Atoi(policy.MaxReceiveCount.String())
+	if err != nil {
+		return nil, fmt.Errorf("queue - SQS - RedrivePolicy - strconv.Atoi: %w", err)
+	}
+
+	return &RedrivePolicy{DeadLetterTargetARN: policy.DeadLetterTargetARN, MaxReceiveCount: n}, nil
+}
+
+// Close does nothing: the client is owned by the caller.
+func (q *SQS) Close() error {
+	return nil
+}
+
+func messageFromSQS(sm *types.Message) *Message {
+	m := &Message{
+		ID:           aws.ToString(sm.MessageId),
+		Body:         []byte(aws.ToString(sm.Body)),
+		ReceiveCount: 1,
+		receipt:      aws.ToString(sm.ReceiptHandle),
+	}
+
+	if n, err := strconv.Atoi(sm.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]); err == nil {
+		m.ReceiveCount = n
+	}
+
+	if ms, err := strconv.ParseInt(sm.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		m.SentAt = time.UnixMilli(ms)
+	}
+
+	if len(sm.MessageAttributes) > 0 {
+		m.Attributes = make(map[string]string, len(sm.MessageAttributes))
+		for key, value := range sm.MessageAttributes {
+			if value.StringValue != nil {
+				m.Attributes[key] = *value.StringValue
+			}
+		}
+	}
+
+	return m
+}
+
+func sqsAttributes(attrs map[string]string) map[string]types.MessageAttributeValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]types.MessageAttributeValue, len(attrs))
+	for key, value := range attrs {
+		values[key] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+
+	return values
+}
+
+func batchErrors(failed []types.BatchResultErrorEntry) []error {
+	errs := make([]error, 0, len(failed))
+	for _, entry := range failed {
+		errs = append(errs, fmt.Errorf("entry %s: %s: %s",
+			aws.ToString(entry.Id), aws.ToString(entry.Code), aws.ToString(entry.Message)))
+	}
+
+	return errs
+}
+
+// chunks splits msgs into SQS batches.
+func chunks(msgs []*Message) func(yield func([]*Message) bool) {
+	return func(yield func([]*Message) bool) {
+		for start := 0; start < len(msgs); start += _sqsBatchSize {
+			if !yield(msgs[start:min(start+_sqsBatchSize, len(msgs))]) {
+				return
+			}
+		}
+	}
+}
+
+func receiptHandle(m *Message) string {
+	s, _ := m.receipt.(string)
+
+	return s
+}
+
+// seconds rounds d up to whole seconds.
+func seconds(d time.Duration) int32 {
+	if d <= 0 {
+		return 0
+	}
+
+	return int32((d + time.Second - 1) / time.Second) //nolint:gosec // SQS durations are small
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return aws.String(s)
+}
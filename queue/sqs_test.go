@@ -0,0 +1,235 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/rdashevsky/go-pkgs/queue"
+)
+
+const queueURL = "https://sqs.eu-west-1.amazonaws.com/123456789012/orders"
+
+// fakeSQS records requests and answers them from its fields.
+type fakeSQS struct {
+	queue.SQSClient
+
+	sendBatches   []*sqs.SendMessageBatchInput
+	deleteBatches []*sqs.DeleteMessageBatchInput
+	receive       *sqs.ReceiveMessageInput
+	visibility    *sqs.ChangeMessageVisibilityInput
+	messages      []types.Message
+	failID        string
+	attributes    map[string]string
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	if aws.ToString(in.QueueUrl) != queueURL {
+		return nil, errors.New("unexpected queue URL")
+	}
+
+	return &sqs.SendMessageOutput{MessageId: aws.String("id-" + aws.ToString(in.MessageBody))}, nil
+}
+
+func (f *fakeSQS) SendMessageBatch(
+	_ context.Context,
+	in *sqs.SendMessageBatchInput,
+	_ ...func(*sqs.Options),
+) (*sqs.SendMessageBatchOutput, error) {
+	f.sendBatches = append(f.sendBatches, in)
+
+	out := &sqs.SendMessageBatchOutput{}
+
+	for _, entry := range in.Entries {
+		if aws.ToString(entry.MessageBody) == f.failID {
+			out.Failed = append(out.Failed, types.BatchResultErrorEntry{
+				Id: entry.Id, Code: aws.String("InvalidMessageContents"), Message: aws.String("bad body"),
+			})
+
+			continue
+		}
+
+		out.Successful = append(out.Successful, types.SendMessageBatchResultEntry{
+			Id: entry.Id, MessageId: aws.String("id-" + aws.ToString(entry.MessageBody)),
+		})
+	}
+
+	return out, nil
+}
+
+func (f *fakeSQS) ReceiveMessage(
+	_ context.Context,
+	in *sqs.ReceiveMessageInput,
+	_ ...func(*sqs.Options),
+) (*sqs.ReceiveMessageOutput, error) {
+	f.receive = in
+
+	return &sqs.ReceiveMessageOutput{Messages: f.messages}, nil
+}
+
+func (f *fakeSQS) DeleteMessageBatch(
+	_ context.Context,
+	in *sqs.DeleteMessageBatchInput,
+	_ ...func(*sqs.Options),
+) (*sqs.DeleteMessageBatchOutput, error) {
+	f.deleteBatches = append(f.deleteBatches, in)
+
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(
+	_ context.Context,
+	in *sqs.ChangeMessageVisibilityInput,
+	_ ...func(*sqs.Options),
+) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.visibility = in
+
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(
+	_ context.Context,
+	_ *sqs.GetQueueAttributesInput,
+	_ ...func(*sqs.Options),
+) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{Attributes: f.attributes}, nil
+}
+
+func TestSQSSend(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeSQS{failID: "13"}
+	q := queue.NewSQS(client, queueURL)
+
+	m := &queue.Message{Body: []byte("one")}
+	if err := q.Send(ctx, m); err != nil || m.ID != "id-one" {
+		t.Fatalf("Send() = %v, ID %q", err, m.ID)
+	}
+
+	msgs := make([]*queue.Message, 25)
+	for i := range msgs {
+		msgs[i] = &queue.Message{Body: []byte(strconv.Itoa(i)), Delay: 1500 * time.Millisecond}
+	}
+
+	err := q.SendBatch(ctx, msgs)
+	if err == nil {
+		t.Fatal("SendBatch() with a failed entry: expected error")
+	}
+
+	if len(client.sendBatches) != 3 || len(client.sendBatches[2].Entries) != 5 {
+		t.Fatalf("SendBatch() made %d requests, want 3 with 10, 10 and 5 entries", len(client.sendBatches))
+	}
+
+	if delay := client.sendBatches[0].Entries[0].DelaySeconds; delay != 2 {
+		t.Errorf("DelaySeconds = %d, want the delay rounded up to 2", delay)
+	}
+
+	if msgs[24].ID != "id-24" || msgs[13].ID != "" {
+		t.Errorf("IDs = %q, %q, want successful messages only to get an ID", msgs[24].ID, msgs[13].ID)
+	}
+}
+
+func TestSQSReceive(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeSQS{messages: []types.Message{{
+		MessageId:     aws.String("m-1"),
+		ReceiptHandle: aws.String("r-1"),
+		Body:          aws.String("payload"),
+		Attributes: map[string]string{
+			"ApproximateReceiveCount": "3",
+			"SentTimestamp":           "1700000000000",
+		},
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"trace": {DataType: aws.String("String"), StringValue: aws.String("t-1")},
+		},
+	}}}
+	q := queue.NewSQS(client, queueURL, queue.WaitTime(time.Minute))
+
+	msgs, err := q.Receive(ctx, 50, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+
+	if in := client.receive; in.MaxNumberOfMessages != 10 || in.WaitTimeSeconds != 20 || in.VisibilityTimeout != 30 {
+		t.Errorf("ReceiveMessage() input = max %d, wait %d, visibility %d, want 10, 20, 30",
+			in.MaxNumberOfMessages, in.WaitTimeSeconds, in.VisibilityTimeout)
+	}
+
+	if len(msgs) != 1 {
+		t.Fatalf("Receive() = %d messages, want 1", len(msgs))
+	}
+
+	m := msgs[0]
+	if m.ID != "m-1" || string(m.Body) != "payload" || m.ReceiveCount != 3 ||
+		!m.SentAt.Equal(time.UnixMilli(1700000000000)) || m.Attributes["trace"] != "t-1" {
+		t.Errorf("Receive() = %+v", m)
+	}
+
+	if err := q.Extend(ctx, m, time.Minute); err != nil {
+		t.Fatalf("Extend() error = %v", err)
+	}
+
+	if aws.ToString(client.visibility.ReceiptHandle) != "r-1" || client.visibility.VisibilityTimeout != 60 {
+		t.Errorf("ChangeMessageVisibility() input = %+v", client.visibility)
+	}
+
+	if err := q.Ack(ctx, m); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+
+	if len(client.deleteBatches) != 1 || aws.ToString(client.deleteBatches[0].Entries[0].ReceiptHandle) != "r-1" {
+		t.Errorf("DeleteMessageBatch() calls = %+v", client.deleteBatches)
+	}
+}
+
+func TestSQSRedrivePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected *queue.RedrivePolicy
+		wantErr  bool
+	}{
+		{name: "none"},
+		{
+			name:     "number",
+			policy:   `{"deadLetterTargetArn":"arn:aws:sqs:eu-west-1:123456789012:orders-dlq","maxReceiveCount":5}`,
+			expected: &queue.RedrivePolicy{DeadLetterTargetARN: "arn:aws:sqs:eu-west-1:123456789012:orders-dlq", MaxReceiveCount: 5},
+		},
+		{
+			name:     "string",
+			policy:   `{"deadLetterTargetArn":"arn:dlq","maxReceiveCount":"4"}`,
+			expected: &queue.RedrivePolicy{DeadLetterTargetARN: "arn:dlq", MaxReceiveCount: 4},
+		},
+		{name: "invalid", policy: `{"maxReceiveCount":"many"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSQS{attributes: map[string]string{}}
+			if tt.policy != "" {
+				client.attributes["RedrivePolicy"] = tt.policy
+			}
+
+			policy, err := queue.NewSQS(client, queueURL).RedrivePolicy(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RedrivePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.expected == nil {
+				if policy != nil {
+					t.Errorf("RedrivePolicy() = %+v, want nil", policy)
+				}
+
+				return
+			}
+
+			if policy == nil || *policy != *tt.expected {
+				t.Errorf("RedrivePolicy() = %+v, want %+v", policy, tt.expected)
+			}
+		})
+	}
+}
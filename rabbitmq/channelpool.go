@@ -0,0 +1,167 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// channel is a channel in confirm mode lent by a channelPool.
+type channel interface {
+	IsClosed() bool
+	// publish publishes msg, returning the confirmation of the broker to wait for.
+	publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (confirmation, error)
+}
+
+// confirmation is the pending confirmation of a publishing, *amqp.DeferredConfirmation.
+type confirmation interface {
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+// amqpChannel is a channel of an AMQP connection.
+type amqpChannel struct {
+	*amqp.Channel
+}
+
+func (ch amqpChannel) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (confirmation, error) {
+	return ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+}
+
+// channelPool lends channels in confirm mode to publish concurrently, each channel
+// being used by one publisher at a time and tracking the confirmations of its own
+// publishings.
+type channelPool struct {
+	open func() (channel, error)
+
+	// channels holds the idle channels; nil slots are opened on checkout.
+	channels chan channel
+}
+
+func newChannelPool(size int, open func() (channel, error)) *channelPool {
+	size = max(size, 1)
+
+	p := &channelPool{
+		open:     open,
+		channels: make(chan channel, size),
+	}
+
+	for range size {
+		p.channels <- nil
+	}
+
+	return p
+}
+
+// get checks out a channel, waiting until ctx is done for one to be returned when all
+// are in use. Channels closed, e.g. with their connection, are replaced.
+func (p *channelPool) get(ctx context.Context) (channel, error) {
+	var ch channel
+
+	select {
+	case ch = <-p.channels:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if ch != nil && !ch.IsClosed() {
+		return ch, nil
+	}
+
+	ch, err := p.open()
+	if err != nil {
+		p.channels <- nil
+
+		return nil, fmt.Errorf("p.open: %w", err)
+	}
+
+	return ch, nil
+}
+
+// put returns a channel checked out with get.
+func (p *channelPool) put(ch channel) {
+	p.channels <- ch
+}
+
+// publish publishes msg on a channel of the pool and waits until ctx is done for the
+// broker to confirm it.
+func (p *channelPool) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	ch, err := p.get(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.put(ch)
+
+	confirm, err := ch.publish(ctx, exchange, routingKey, msg)
+	if err != nil {
+		return fmt.Errorf("ch.publish: %w", err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("confirm.WaitContext: %w", err)
+	}
+
+	if !acked {
+		return ErrNacked
+	}
+
+	return nil
+}
+
+// Publish publishes msg to exchange with routingKey and waits until ctx is done for the
+// broker to confirm it. Unlike Channel, it is safe for concurrent use: publishings go
+// through a pool of PublishChannels channels in confirm mode, opened on the connection
+// as needed and replaced once closed, e.g. after Reconnect.
+// Returns ErrNacked when the broker could not take the message.
+func (c *Connection) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	c.poolOnce.Do(func() {
+		c.pool = newChannelPool(c.PublishChannels, c.openChannel)
+	})
+
+	err := c.pool.publish(ctx, exchange, routingKey, msg)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - Publish - c.pool.publish: %w", err)
+	}
+
+	return nil
+}
+
+// setConnection sets Connection, guarded against the pool opening channels on it.
+func (c *Connection) setConnection(conn *amqp.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Connection = conn
+}
+
+// Conn returns the current connection, nil before connecting. Unlike the Connection
+// field, it is safe to call while Reconnect replaces the connection.
+func (c *Connection) Conn() *amqp.Connection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Connection
+}
+
+// openChannel opens a channel in confirm mode on the current connection.
+func (c *Connection) openChannel() (channel, error) {
+	conn := c.Conn()
+
+	if conn == nil {
+		return nil, amqp.ErrClosed
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("conn.Channel: %w", err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+
+		return nil, fmt.Errorf("ch.Confirm: %w", err)
+	}
+
+	return amqpChannel{ch}, nil
+}
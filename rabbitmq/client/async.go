@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// Future is the pending result of an AsyncCall.
+type Future struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// Done returns a channel closed once the response, or the error of the call, is
+// available.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the response of the call and unmarshals it into response, as
+// RemoteCall does. It may be called several times.
+func (f *Future) Wait(response interface{}) error {
+	<-f.done
+
+	if f.err != nil {
+		return f.err
+	}
+
+	if f.body == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(f.body, &response)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc client - Client - RemoteCall - json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+// AsyncCall publishes a request to a remote handler and returns without waiting for
+// its response, so that several calls can be in flight at once.
+// It returns an error if the request cannot be sent, like RemoteCall; errors of the
+// response are returned by Future.Wait.
+//
+// Example:
+//
+//	user, err := c.AsyncCall("user.get", userID)
+//	orders, err := c.AsyncCall("orders.list", userID)
+//	err = user.Wait(&u)
+//	err = orders.Wait(&o)
+func (c *Client) AsyncCall(handler string, request interface{}) (*Future, error) {
+	return c.AsyncCallContext(context.Background(), handler, request)
+}
+
+// AsyncCallContext is like AsyncCall, and stops waiting for the response when ctx is
+// done. With WithTracer the call is traced in a child span of the span in ctx.
+func (c *Client) AsyncCallContext(ctx context.Context, handler string, request interface{}) (*Future, error) {
+	start := c.clock.Now()
+
+	ctx, span := c.startSpan(ctx, handler)
+
+	finish := func(err error) {
+		endSpan(span, err)
+		c.metrics.ObserveCall(handler, c.clock.Since(start), err)
+	}
+
+	call, err := c.send(ctx, handler, request)
+	if err != nil {
+		finish(err)
+
+		return nil, err
+	}
+
+	f := &Future{done: make(chan struct{})}
+
+	go func() {
+		f.body, f.err = c.wait(ctx, call)
+
+		finish(f.err)
+		close(f.done)
+	}()
+
+	return f, nil
+}
+
+// Call is a remote call of BatchCall.
+type Call struct {
+	Handler string
+	Request interface{}
+	// Response is the pointer the response is unmarshaled into, as for RemoteCall.
+	Response interface{}
+}
+
+// Result is the outcome of a Call of BatchCall.
+type Result struct {
+	Err error
+}
+
+// BatchCall sends all calls at once, publishing them concurrently on up to
+// PublishChannels channels, and waits for their responses, unmarshaled into their
+// Response. The results are in the order of calls; the returned error joins the
+// errors of the failed calls.
+//
+// Example:
+//
+//	results, err := c.BatchCall([]client.Call{
+//	    {Handler: "price.get", Request: "sku-1", Response: &p1},
+//	    {Handler: "price.get", Request: "sku-2", Response: &p2},
+//	})
+func (c *Client) BatchCall(calls []Call) ([]Result, error) {
+	return c.BatchCallContext(context.Background(), calls)
+}
+
+// BatchCallContext is like BatchCall, and stops waiting for the responses when ctx is
+// done.
+func (c *Client) BatchCallContext(ctx context.Context, calls []Call) ([]Result, error) {
+	results := make([]Result, len(calls))
+	futures := make([]*Future, len(calls))
+
+	// Sent concurrently, as many at once as there are channels to publish them.
+	var (
+		wg      sync.WaitGroup
+		sending = make(chan struct{}, max(c.conn.PublishChannels, 1))
+	)
+
+	for i, call := range calls {
+		sending <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			futures[i], results[i].Err = c.AsyncCallContext(ctx, call.Handler, call.Request)
+
+			<-sending
+		}()
+	}
+
+	wg.Wait()
+
+	errs := make([]error, 0, len(calls))
+
+	for i, f := range futures {
+		if f != nil {
+			results[i].Err = f.Wait(calls[i].Response)
+		}
+
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("rmq_rpc client - Client - BatchCall - %s: %w", calls[i].Handler, results[i].Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
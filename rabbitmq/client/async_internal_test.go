@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newDisconnected returns a client whose connection is lost, failing calls at once.
+func newDisconnected() *Client {
+	return &Client{
+		conn:    rmqrpc.New("rpc", rmqrpc.Config{}),
+		calls:   make(map[string]*pendingCall),
+		metrics: noopMetrics{},
+		tracer:  noop.NewTracerProvider().Tracer(""),
+		clock:   clock.NewFake(time.Now()),
+	}
+}
+
+func TestFuture_Wait(t *testing.T) {
+	f := &Future{done: make(chan struct{}), body: []byte(`{"name":"gopher"}`)}
+	close(f.done)
+
+	var got struct{ Name string }
+	if err := f.Wait(&got); err != nil || got.Name != "gopher" {
+		t.Fatalf("Wait() = %+v, %v", got, err)
+	}
+
+	failed := &Future{done: make(chan struct{}), err: rmqrpc.ErrTimeout}
+	close(failed.done)
+
+	if err := failed.Wait(&got); !errors.Is(err, rmqrpc.ErrTimeout) {
+		t.Errorf("Wait() error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestClient_AsyncCallDisconnected(t *testing.T) {
+	c := newDisconnected()
+
+	f, err := c.AsyncCall("greet", "gopher")
+	if f != nil || !errors.Is(err, ErrConnectionClosed) {
+		t.Errorf("AsyncCall() = %v, %v, want ErrConnectionClosed", f, err)
+	}
+}
+
+func TestClient_BatchCallDisconnected(t *testing.T) {
+	c := newDisconnected()
+
+	var a, b string
+
+	results, err := c.BatchCall([]Call{
+		{Handler: "greet", Request: "a", Response: &a},
+		{Handler: "greet", Request: "b", Response: &b},
+	})
+	if !errors.Is(err, ErrConnectionClosed) {
+		t.Errorf("BatchCall() error = %v, want ErrConnectionClosed", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	for i, r := range results {
+		if !errors.Is(r.Err, ErrConnectionClosed) {
+			t.Errorf("results[%d].Err = %v, want ErrConnectionClosed", i, r.Err)
+		}
+	}
+}
+
+func TestClient_BatchCallConcurrent(t *testing.T) {
+	c := newDisconnected()
+	c.conn.PublishChannels = 3
+	c.timeout = time.Second
+
+	clk := clock.NewFake(time.Now())
+	c.clock = clk
+
+	calls := make([]Call, 4)
+	for i := range calls {
+		calls[i] = Call{Handler: "greet", Request: i}
+	}
+
+	done := make(chan []Result)
+
+	go func() {
+		results, _ := c.BatchCall(calls)
+		done <- results
+	}()
+
+	// Every send waits a timeout for the lost connection: 3 waiting at once overlap.
+	clk.BlockUntil(3)
+	clk.Advance(time.Second)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	for i, r := range <-done {
+		if !errors.Is(r.Err, ErrConnectionClosed) {
+			t.Errorf("results[%d].Err = %v, want ErrConnectionClosed", i, r.Err)
+		}
+	}
+}
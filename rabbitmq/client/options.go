@@ -1,6 +1,12 @@
 package client
 
-import "time"
+import (
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/breaker"
+	"github.com/rdashevsky/go-pkgs/clock"
+)
 
 // Option is a function that configures a Client.
 // Options are applied in the order they are passed to New.
@@ -8,6 +14,7 @@ type Option func(*Client)
 
 // Timeout sets the maximum duration to wait for a response to a remote call.
 // If a response is not received within this duration, RemoteCall returns ErrTimeout.
+// Shutdown also waits up to this duration for pending calls.
 // Default is 2 seconds.
 //
 // Example:
@@ -32,7 +39,7 @@ func ConnWaitTime(timeout time.Duration) Option {
 	}
 }
 
-// ConnAttempts sets the maximum number of connection attempts.
+// ConnAttempts sets the maximum number of attempts of the initial connection.
 // If all attempts fail, New returns an error.
 // Default is 10 attempts.
 //
@@ -44,3 +51,130 @@ func ConnAttempts(attempts int) Option {
 		c.conn.Attempts = attempts
 	}
 }
+
+// Heartbeat sets the interval of the heartbeats detecting dead connections.
+// Default is 10 seconds, unless the URL sets a heartbeat.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.Heartbeat(30*time.Second))
+func Heartbeat(d time.Duration) Option {
+	return func(c *Client) {
+		c.conn.Heartbeat = d
+	}
+}
+
+// DialTimeout bounds establishing the TCP connection of each connection attempt.
+// Default is 30 seconds.
+func DialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.conn.DialTimeout = d
+	}
+}
+
+// VHost sets the virtual host, overriding the one of the URL.
+// Default is the virtual host of the URL.
+func VHost(vhost string) Option {
+	return func(c *Client) {
+		c.conn.VHost = vhost
+	}
+}
+
+// ConnectionName names the connection, e.g. in the management UI.
+// Default is no name.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.ConnectionName("orders-client"))
+func ConnectionName(name string) Option {
+	return func(c *Client) {
+		c.conn.ConnectionName = name
+	}
+}
+
+// AMQPConfig sets the configuration passed to amqp.DialConfig, e.g. for TLS or SASL.
+// Heartbeat, DialTimeout, VHost and ConnectionName take precedence when set.
+// Default is the amqp091-go defaults.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.AMQPConfig(amqp.Config{TLSClientConfig: tlsConfig}))
+func AMQPConfig(cfg amqp.Config) Option {
+	return func(c *Client) {
+		c.conn.AMQP = &cfg
+	}
+}
+
+// ReconnectAttempts sets the maximum number of attempts to reconnect after the
+// connection is lost. Once they are exhausted the error is sent on Notify, which is
+// then closed.
+// Default is 0, retrying until Shutdown.
+func ReconnectAttempts(attempts int) Option {
+	return func(c *Client) {
+		c.conn.ReconnectAttempts = attempts
+	}
+}
+
+// ReconnectMaxWait caps the exponential backoff between reconnection attempts, which
+// starts at ConnWaitTime and is randomized by ±20%.
+// Default is 1 minute.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.ConnWaitTime(time.Second), client.ReconnectMaxWait(30*time.Second))
+func ReconnectMaxWait(d time.Duration) Option {
+	return func(c *Client) {
+		c.conn.ReconnectMaxWait = d
+	}
+}
+
+// PublishChannels sets how many channels publish requests concurrently, each waiting
+// for the broker to confirm its request before publishing the next.
+// Default is 1.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.PublishChannels(4))
+func PublishChannels(n int) Option {
+	return func(c *Client) {
+		c.conn.PublishChannels = n
+	}
+}
+
+// CircuitBreaker guards remote calls with the given circuit breaker.
+// Timeouts, publish failures, closed connections and internal server errors count as
+// failures; while the breaker is open RemoteCall fails fast with breaker.ErrOpen.
+// The breaker may be shared between clients calling the same server.
+// Default is no circuit breaker.
+//
+// Example:
+//
+//	b := breaker.New(breaker.Name("billing"), breaker.OpenDuration(10*time.Second))
+//	client.New(url, serverEx, clientEx, client.CircuitBreaker(b))
+func CircuitBreaker(b *breaker.Breaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+// WithMetrics sets the hook receiving remote call measurements.
+// Default is no metrics.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		if m != nil {
+			c.metrics = m
+		}
+	}
+}
+
+// WithClock sets the clock driving the call timeout and waits between connection attempts,
+// e.g. a *clock.Fake in tests.
+// Default is clock.Real().
+func WithClock(clk clock.Clock) Option {
+	return func(c *Client) {
+		if clk != nil {
+			c.clock = clk
+			c.conn.Clock = clk
+		}
+	}
+}
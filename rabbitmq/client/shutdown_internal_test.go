@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestClient_ShutdownContext(t *testing.T) {
+	c := newDisconnected()
+	c.conn = rmqrpc.New("rpc", rmqrpc.Config{})
+	c.stop = make(chan struct{})
+	c.failed = make(chan struct{})
+	c.connected.Store(true)
+
+	drained := &pendingCall{id: "drained", done: make(chan struct{})}
+	aborted := &pendingCall{id: "aborted", done: make(chan struct{})}
+	c.addCall(drained.id, drained)
+	c.addCall(aborted.id, aborted)
+
+	go func() {
+		c.deleteCall(drained.id)
+		c.end()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stats, err := c.ShutdownContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ShutdownContext() error = %v, want DeadlineExceeded", err)
+	}
+
+	if stats != (rmqrpc.ShutdownStats{Drained: 1, Aborted: 1}) {
+		t.Errorf("ShutdownContext() stats = %+v, want 1 drained and 1 aborted", stats)
+	}
+
+	if err := c.RemoteCall("greet", nil, nil); !errors.Is(err, ErrConnectionClosed) {
+		t.Errorf("RemoteCall() after shutdown error = %v, want ErrConnectionClosed", err)
+	}
+
+	late := &pendingCall{id: "late", done: make(chan struct{})}
+	if c.addCall(late.id, late) || c.inflight.Load() != 1 {
+		t.Errorf("addCall() after shutdown registered the call, %d in flight", c.inflight.Load())
+	}
+
+	select {
+	case <-c.stop:
+	default:
+		t.Error("consumer not stopped")
+	}
+
+	c.deleteCall(aborted.id)
+	c.end()
+}
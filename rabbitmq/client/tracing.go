@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const _instrumentationName = "github.com/rdashevsky/go-pkgs/rabbitmq/client"
+
+// WithTracer traces every remote call in an OpenTelemetry client span, a child of the
+// span in the context given to RemoteCallContext, and propagates it to the server in
+// the W3C trace context headers of the request. A nil provider is ignored.
+// Default is no tracing.
+//
+// Example:
+//
+//	client.New(url, serverEx, clientEx, client.WithTracer(otel.GetTracerProvider()))
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		if tp != nil {
+			c.tracer = tp.Tracer(_instrumentationName)
+		}
+	}
+}
+
+func (c *Client) startSpan(ctx context.Context, handler string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "rabbitmq "+handler,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", c.serverExchange),
+			attribute.String("rpc.method", handler),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestValidationError(t *testing.T) {
+	err := validationError([]byte(`{"errors":[{"field":"age","rule":"type","message":"age must be int"}]}`))
+
+	var validationErr *rmqrpc.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "age" {
+		t.Errorf("validationError() = %#v", err)
+	}
+
+	if err := validationError(nil); err != rmqrpc.ErrValidation { //nolint:errorlint // the sentinel itself is expected
+		t.Errorf("validationError(nil) = %v, want ErrValidation", err)
+	}
+}
+
+func TestBadRequestError(t *testing.T) {
+	err := badRequestError([]byte(`{"message":"unknown schema"}`))
+
+	var badRequestErr *rmqrpc.BadRequestError
+	if !errors.As(err, &badRequestErr) || badRequestErr.Message != "unknown schema" {
+		t.Errorf("badRequestError() = %#v", err)
+	}
+
+	if err := badRequestError([]byte("not json")); err != rmqrpc.ErrBadRequest { //nolint:errorlint // the sentinel itself is expected
+		t.Errorf("badRequestError() = %v, want ErrBadRequest", err)
+	}
+}
@@ -2,19 +2,79 @@
 package rabbitmq
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/retry"
 )
 
 // Config holds the configuration for a RabbitMQ connection.
 // It specifies the connection URL, retry parameters, and timing.
+// A nil Clock waits between attempts with the real clock.
 type Config struct {
 	URL      string
 	WaitTime time.Duration
 	Attempts int
+	Clock    clock.Clock
+
+	// Heartbeat is the interval of the heartbeats detecting dead connections, 10 seconds
+	// when zero. A heartbeat in the URL takes precedence.
+	Heartbeat time.Duration
+	// DialTimeout bounds establishing the TCP connection, 30 seconds when zero.
+	DialTimeout time.Duration
+	// VHost is the virtual host, overriding the one of the URL when set.
+	VHost string
+	// ConnectionName names the connection, e.g. in the management UI.
+	ConnectionName string
+	// AMQP is the configuration passed to amqp.DialConfig, e.g. for TLS or SASL. The
+	// fields above take precedence when set.
+	AMQP *amqp.Config
+
+	// ReconnectAttempts bounds the attempts of Reconnect, unlimited when zero or negative.
+	ReconnectAttempts int
+	// ReconnectMaxWait caps the exponential backoff of Reconnect, which starts at
+	// WaitTime. When not above WaitTime, Reconnect waits WaitTime between attempts.
+	ReconnectMaxWait time.Duration
+
+	// PublishChannels is the size of the channel pool of Publish, 1 when zero or negative.
+	PublishChannels int
+}
+
+// Topology describes the exchange consumed from and the queue bound to it, declared
+// on connect, and the QoS of the consumer. Set it with the options of New.
+type Topology struct {
+	ExchangeType       string
+	ExchangeDurable    bool
+	ExchangeAutoDelete bool
+
+	// Queue is the name of the queue, empty for a name generated by the server.
+	Queue           string
+	QueueDurable    bool
+	QueueAutoDelete bool
+	QueueExclusive  bool
+	QueueArgs       amqp.Table
+
+	// BindingKeys are the routing keys or patterns the queue is bound with, once each.
+	BindingKeys []string
+	BindingArgs amqp.Table
+
+	// DeadLetterExchange receives the messages rejected by consumers, see DeadLetter.
+	DeadLetterExchange   string
+	DeadLetterRoutingKey string
+
+	// DelayedMessageExchange declares the exchange with the delayed message exchange
+	// plugin, see DelayedMessageExchange.
+	DelayedMessageExchange bool
+
+	// PrefetchCount and PrefetchSize bound the unacknowledged deliveries, see QoS.
+	PrefetchCount int
+	PrefetchSize  int
 }
 
 // Connection represents a RabbitMQ connection with a channel and consumer setup.
@@ -22,9 +82,15 @@ type Config struct {
 type Connection struct {
 	ConsumerExchange string
 	Config
+	Topology   Topology
 	Connection *amqp.Connection
 	Channel    *amqp.Channel
 	Delivery   <-chan amqp.Delivery
+
+	// mu guards Connection, replaced on reconnect while the pool opens channels on it.
+	mu       sync.RWMutex
+	poolOnce sync.Once
+	pool     *channelPool
 }
 
 // New creates a new RabbitMQ connection instance with the specified exchange and configuration.
@@ -33,6 +99,8 @@ type Connection struct {
 // Parameters:
 //   - consumerExchange: the name of the exchange to consume from
 //   - cfg: connection configuration including URL, retry attempts, and wait time
+//   - opts: topology options; by default a non-durable fanout exchange and an exclusive
+//     queue named by the server
 //
 // Example:
 //
@@ -43,10 +111,19 @@ type Connection struct {
 //	}
 //	conn := rabbitmq.New("my-exchange", cfg)
 //	err := conn.AttemptConnect()
-func New(consumerExchange string, cfg Config) *Connection {
+func New(consumerExchange string, cfg Config, opts ...Option) *Connection {
 	conn := &Connection{
 		ConsumerExchange: consumerExchange,
 		Config:           cfg,
+		Topology: Topology{
+			ExchangeType:   amqp.ExchangeFanout,
+			QueueExclusive: true,
+			BindingKeys:    []string{""},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(conn)
 	}
 
 	return conn
@@ -55,79 +132,207 @@ func New(consumerExchange string, cfg Config) *Connection {
 // AttemptConnect tries to establish a connection to RabbitMQ.
 // It will retry the connection based on the configured Attempts and WaitTime.
 // If all attempts fail, it returns the last error encountered.
+// With zero or negative Attempts no connection is attempted.
 //
 // The method will:
 //  1. Establish an AMQP connection
 //  2. Create a channel
-//  3. Declare the exchange as fanout
-//  4. Create an exclusive queue
-//  5. Bind the queue to the exchange
-//  6. Start consuming messages
+//  3. Declare the exchange, and the dead-letter exchange and queue if configured
+//  4. Declare the queue
+//  5. Bind the queue to the exchange with each binding key
+//  6. Set the QoS of the channel if configured
+//  7. Start consuming messages
 func (c *Connection) AttemptConnect() error {
-	var err error
-	for i := c.Attempts; i > 0; i-- {
-		if err = c.connect(); err == nil {
-			break
-		}
+	err := c.attempt(c.connect)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - AttemptConnect - c.connect: %w", err)
+	}
+
+	return nil
+}
+
+// attempt calls connect according to Attempts and WaitTime.
+func (c *Connection) attempt(connect func() error) error {
+	if c.Attempts <= 0 {
+		return nil
+	}
+
+	return retry.Do(context.Background(), func(context.Context) error {
+		return connect()
+	},
+		retry.Attempts(c.Attempts),
+		retry.ConstantBackoff(c.WaitTime),
+		retry.WithClock(c.Clock),
+		retry.OnRetry(func(attempt int, _ error, _ time.Duration) {
+			log.Printf("RabbitMQ is trying to connect, attempts left: %d", c.Attempts-attempt)
+		}),
+	)
+}
+
+// Dial connects to RabbitMQ with the settings of cfg, retrying according to Attempts
+// and WaitTime, for packages declaring their own topology and channels. It dials once
+// when Attempts is zero or negative.
+//
+// Example:
+//
+//	conn, err := rabbitmq.Dial(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	ch, err := conn.Channel()
+func Dial(cfg Config) (*amqp.Connection, error) {
+	c := &Connection{Config: cfg}
+	c.Attempts = max(c.Attempts, 1)
+
+	var conn *amqp.Connection
 
-		log.Printf("RabbitMQ is trying to connect, attempts left: %d", i)
-		time.Sleep(c.WaitTime)
+	err := c.attempt(func() error {
+		var err error
+
+		conn, err = c.dial()
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rmq_rpc - Dial - c.dial: %w", err)
 	}
 
+	return conn, nil
+}
+
+// dial connects to the URL with amqp.DialConfig.
+func (c *Config) dial() (*amqp.Connection, error) {
+	conn, err := amqp.DialConfig(c.URL, c.amqpConfig())
 	if err != nil {
-		return fmt.Errorf("rmq_rpc - AttemptConnect - c.connect: %w", err)
+		return nil, fmt.Errorf("amqp.DialConfig: %w", err)
 	}
 
-	return nil
+	return conn, nil
+}
+
+// amqpConfig returns a copy of AMQP with the other fields applied.
+func (c *Config) amqpConfig() amqp.Config {
+	var cfg amqp.Config
+	if c.AMQP != nil {
+		cfg = *c.AMQP
+	}
+
+	if cfg.Locale == "" {
+		cfg.Locale = "en_US"
+	}
+
+	if c.Heartbeat > 0 {
+		cfg.Heartbeat = c.Heartbeat
+	}
+
+	if c.DialTimeout > 0 {
+		cfg.Dial = amqp.DefaultDial(c.DialTimeout)
+	}
+
+	if c.VHost != "" {
+		cfg.Vhost = c.VHost
+	}
+
+	if c.ConnectionName != "" {
+		properties := amqp.NewConnectionProperties()
+		for k, v := range cfg.Properties {
+			properties[k] = v
+		}
+
+		properties.SetClientConnectionName(c.ConnectionName)
+		cfg.Properties = properties
+	}
+
+	return cfg
 }
 
 func (c *Connection) connect() error {
-	var err error
+	conn, err := c.dial()
+	c.setConnection(conn)
 
-	c.Connection, err = amqp.Dial(c.URL)
 	if err != nil {
-		return fmt.Errorf("amqp.Dial: %w", err)
+		return permanent(fmt.Errorf("c.dial: %w", err))
+	}
+
+	if err := c.declare(conn); err != nil {
+		// Closed so that retries don't leak a connection each.
+		_ = conn.Close()
+
+		return permanent(err)
 	}
 
-	c.Channel, err = c.Connection.Channel()
+	return nil
+}
+
+// permanent marks the errors retrying cannot fix with retry.Permanent: hard AMQP
+// errors, e.g. refused credentials, and declarations mismatching the existing topology.
+// The connection closing meanwhile, e.g. as the broker restarts, is retried.
+func permanent(err error) error {
+	var amqpErr *amqp.Error
+	if !errors.As(err, &amqpErr) || errors.Is(err, amqp.ErrClosed) || amqpErr.Code == amqp.ConnectionForced {
+		return err
+	}
+
+	if !amqpErr.Recover || amqpErr.Code == amqp.PreconditionFailed || amqpErr.Code == amqp.AccessRefused {
+		return retry.Permanent(err)
+	}
+
+	return err
+}
+
+// declare opens the channel on conn, declares the topology and starts consuming.
+func (c *Connection) declare(conn *amqp.Connection) error {
+	var err error
+
+	c.Channel, err = conn.Channel()
 	if err != nil {
 		return fmt.Errorf("c.Connection.Channel: %w", err)
 	}
 
-	err = c.Channel.ExchangeDeclare(
-		c.ConsumerExchange,
-		"fanout",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
+	t := c.Topology
+
+	if t.DeadLetterExchange != "" {
+		if err := c.declareDeadLetter(); err != nil {
+			return fmt.Errorf("c.declareDeadLetter: %w", err)
+		}
+	}
+
+	err = t.declareExchange(c.Channel, c.ConsumerExchange)
 	if err != nil {
-		return fmt.Errorf("c.Connection.Channel: %w", err)
+		return fmt.Errorf("t.declareExchange: %w", err)
 	}
 
 	queue, err := c.Channel.QueueDeclare(
-		"",
-		false,
+		t.Queue,
+		t.QueueDurable,
+		t.QueueAutoDelete,
+		t.QueueExclusive,
 		false,
-		true,
-		false,
-		nil,
+		t.QueueArgs,
 	)
 	if err != nil {
 		return fmt.Errorf("c.Channel.QueueDeclare: %w", err)
 	}
 
-	err = c.Channel.QueueBind(
-		queue.Name,
-		"",
-		c.ConsumerExchange,
-		false,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("c.Channel.QueueBind: %w", err)
+	for _, key := range t.BindingKeys {
+		err = c.Channel.QueueBind(
+			queue.Name,
+			key,
+			c.ConsumerExchange,
+			false,
+			t.BindingArgs,
+		)
+		if err != nil {
+			return fmt.Errorf("c.Channel.QueueBind: %w", err)
+		}
+	}
+
+	if t.PrefetchCount > 0 || t.PrefetchSize > 0 {
+		err = c.Channel.Qos(t.PrefetchCount, t.PrefetchSize, false)
+		if err != nil {
+			return fmt.Errorf("c.Channel.Qos: %w", err)
+		}
 	}
 
 	c.Delivery, err = c.Channel.Consume(
@@ -0,0 +1,81 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+func TestConfig_amqpConfig(t *testing.T) {
+	empty := (&Config{}).amqpConfig()
+	if empty.Locale != "en_US" || empty.Heartbeat != 0 || empty.Dial != nil || empty.Vhost != "" || empty.Properties != nil {
+		t.Errorf("amqpConfig() of an empty Config = %+v, want amqp.Dial defaults", empty)
+	}
+
+	base := &amqp.Config{
+		Vhost:      "base",
+		Heartbeat:  time.Minute,
+		ChannelMax: 64,
+		Properties: amqp.Table{"team": "billing"},
+	}
+
+	cfg := Config{
+		Heartbeat:      30 * time.Second,
+		DialTimeout:    time.Second,
+		VHost:          "orders",
+		ConnectionName: "orders-server",
+		AMQP:           base,
+	}
+
+	got := cfg.amqpConfig()
+
+	if got.Heartbeat != 30*time.Second || got.Vhost != "orders" || got.ChannelMax != 64 || got.Dial == nil {
+		t.Errorf("amqpConfig() = %+v", got)
+	}
+
+	if got.Properties["connection_name"] != "orders-server" || got.Properties["team"] != "billing" || got.Properties["product"] == nil {
+		t.Errorf("amqpConfig() properties = %v", got.Properties)
+	}
+
+	if _, ok := base.Properties["connection_name"]; ok || base.Vhost != "base" {
+		t.Errorf("amqpConfig() modified the AMQP config: %+v", base)
+	}
+
+	if kept := (&Config{AMQP: base}).amqpConfig(); kept.Heartbeat != time.Minute || kept.Vhost != "base" {
+		t.Errorf("amqpConfig() = %+v, want the AMQP config kept", kept)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", errors.New("dial tcp: connection refused"), false},
+		{"connection closed", fmt.Errorf("c.Connection.Channel: %w", amqp.ErrClosed), false},
+		{"broker restarting", &amqp.Error{Code: amqp.ConnectionForced, Server: true}, false},
+		{"not found", &amqp.Error{Code: amqp.NotFound, Server: true, Recover: true}, false},
+		{"mismatched declaration", fmt.Errorf("c.Channel.QueueDeclare: %w",
+			&amqp.Error{Code: amqp.PreconditionFailed, Server: true, Recover: true}), true},
+		{"refused credentials", amqp.ErrCredentials, true},
+		{"hard error", &amqp.Error{Code: amqp.FrameError, Server: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := permanent(tt.err)
+			if got := retry.IsPermanent(err); got != tt.want {
+				t.Errorf("permanent(%v) permanent = %v, want %v", tt.err, got, tt.want)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("permanent(%v) = %v, want it wrapped", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const _notifyBuffer = 16
+
+// Handler processes a message received by a Consumer. The context carries the trace
+// context of the message headers and is canceled by Shutdown.
+type Handler func(ctx context.Context, d *amqp.Delivery) error
+
+// Consumer consumes messages from a queue bound to an exchange, outside of the RPC
+// client and server, passing each one to its Handler in turn. Messages are acknowledged
+// once handled, and rejected without requeue when the handler fails, so that they reach
+// the dead-letter exchange of the DeadLetter option if any.
+type Consumer struct {
+	conn    *Connection
+	handler Handler
+
+	error  chan error
+	failed chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewConsumer connects to RabbitMQ, retrying according to cfg.Attempts and
+// cfg.WaitTime, and declares the exchange, the queue and its bindings of opts.
+// Consumption begins with Start.
+//
+// Example:
+//
+//	c, err := rabbitmq.NewConsumer("orders", cfg, handle,
+//	    rabbitmq.ExchangeType(amqp.ExchangeTopic),
+//	    rabbitmq.Queue("billing.orders"),
+//	    rabbitmq.QueueDurable(true),
+//	    rabbitmq.BindingKeys("order.created", "order.paid"),
+//	)
+//	c.Start()
+func NewConsumer(exchange string, cfg Config, h Handler, opts ...Option) (*Consumer, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &Consumer{
+		conn:    New(exchange, cfg, opts...),
+		handler: h,
+		error:   make(chan error, _notifyBuffer),
+		failed:  make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	err := c.conn.AttemptConnect()
+	if err != nil {
+		cancel()
+
+		return nil, fmt.Errorf("rmq_rpc - NewConsumer - c.conn.AttemptConnect: %w", err)
+	}
+
+	return c, nil
+}
+
+// Start begins consuming messages in a separate goroutine.
+// Use Notify() to receive consumer errors.
+func (c *Consumer) Start() {
+	c.startOnce.Do(func() {
+		go c.consume()
+	})
+}
+
+func (c *Consumer) consume() {
+	defer close(c.done)
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case d, opened := <-c.conn.Delivery:
+			if !opened {
+				if !c.reconnect() {
+					return
+				}
+
+				continue
+			}
+
+			if err := c.handler(ExtractTrace(c.ctx, d.Headers), &d); err != nil {
+				_ = d.Nack(false, false) //nolint:errcheck // redelivered if the channel is lost
+
+				continue
+			}
+
+			_ = d.Ack(false) //nolint:errcheck // redelivered if the channel is lost
+		}
+	}
+}
+
+// reconnect re-establishes the lost connection, reporting its progress on Notify.
+// It reports false when the consumer was shut down meanwhile or the reconnect
+// attempts are exhausted, which is fatal.
+func (c *Consumer) reconnect() bool {
+	err := c.conn.Reconnect(c.ctx, func(e *ReconnectEvent) {
+		select {
+		case c.error <- e:
+		default:
+		}
+	})
+	if err == nil {
+		return true
+	}
+
+	if c.ctx.Err() == nil {
+		close(c.failed)
+		c.error <- fmt.Errorf("rmq_rpc - Consumer - reconnect - c.conn.Reconnect: %w", err)
+		close(c.error)
+	}
+
+	return false
+}
+
+// Notify returns a channel that receives consumer errors.
+// While the consumer reconnects after losing the connection it receives
+// *ReconnectEvent values, which are not fatal. The channel is closed after a fatal
+// error, when the ReconnectAttempts are exhausted.
+func (c *Consumer) Notify() <-chan error {
+	return c.error
+}
+
+// Shutdown stops consuming, cancels the context of the running handler and waits for
+// it to return, then closes the connection.
+func (c *Consumer) Shutdown() error {
+	var err error
+
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		c.cancel()
+
+		// Start may never have been called.
+		c.startOnce.Do(func() { close(c.done) })
+		<-c.done
+
+		select {
+		case <-c.failed:
+			return
+		default:
+		}
+
+		conn := c.conn.Conn()
+		if conn == nil {
+			return
+		}
+
+		if cerr := conn.Close(); cerr != nil && !errors.Is(cerr, amqp.ErrClosed) {
+			err = fmt.Errorf("rmq_rpc - Consumer - Shutdown - conn.Close: %w", cerr)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,189 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterQueue returns the name of the queue declared by the DeadLetter option
+// for exchange.
+func DeadLetterQueue(exchange string) string {
+	return exchange + ".dlq"
+}
+
+// DeadLetter routes the messages rejected without requeue, expired or exceeding a
+// queue limit to exchange, with routingKey instead of their own unless it is empty.
+// On connect, exchange is declared as a durable fanout exchange with a durable queue
+// named DeadLetterQueue(exchange) bound to it. Read dead letters with
+// ConsumeDeadLetters and move them back with ReplayDeadLetters.
+// Default is no dead-lettering: rejected messages are dropped.
+//
+// Example:
+//
+//	conn := rabbitmq.New("orders", cfg,
+//	    rabbitmq.Queue("billing.orders"),
+//	    rabbitmq.DeadLetter("orders.dead", ""),
+//	)
+func DeadLetter(exchange, routingKey string) Option {
+	return func(c *Connection) {
+		c.Topology.DeadLetterExchange = exchange
+		c.Topology.DeadLetterRoutingKey = routingKey
+
+		args := amqp.Table{"x-dead-letter-exchange": exchange}
+		if routingKey != "" {
+			args["x-dead-letter-routing-key"] = routingKey
+		}
+
+		QueueArgs(args)(c)
+	}
+}
+
+// declareDeadLetter declares the dead-letter exchange and its queue.
+func (c *Connection) declareDeadLetter() error {
+	exchange := c.Topology.DeadLetterExchange
+
+	err := c.Channel.ExchangeDeclare(exchange, amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("c.Channel.ExchangeDeclare: %w", err)
+	}
+
+	queue, err := c.Channel.QueueDeclare(DeadLetterQueue(exchange), true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("c.Channel.QueueDeclare: %w", err)
+	}
+
+	if err := c.Channel.QueueBind(queue.Name, "", exchange, false, nil); err != nil {
+		return fmt.Errorf("c.Channel.QueueBind: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeDeadLetters consumes the dead-letter queue of the DeadLetter option on the
+// channel of the connection. See the ConsumeDeadLetters function.
+func (c *Connection) ConsumeDeadLetters() (<-chan amqp.Delivery, error) {
+	if c.Topology.DeadLetterExchange == "" {
+		return nil, fmt.Errorf("rmq_rpc - ConsumeDeadLetters: %w", ErrNoDeadLetter)
+	}
+
+	return ConsumeDeadLetters(c.Channel, c.Topology.DeadLetterExchange)
+}
+
+// ReplayDeadLetters replays the dead-letter queue of the DeadLetter option on the
+// channel of the connection. See the ReplayDeadLetters function.
+func (c *Connection) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	if c.Topology.DeadLetterExchange == "" {
+		return 0, fmt.Errorf("rmq_rpc - ReplayDeadLetters: %w", ErrNoDeadLetter)
+	}
+
+	return ReplayDeadLetters(ctx, c.Channel, c.Topology.DeadLetterExchange, limit)
+}
+
+// ConsumeDeadLetters consumes the queue declared by the DeadLetter option for exchange,
+// e.g. from a tool given its own channel. Deliveries must be acknowledged; the
+// "x-death" header tells where and why each was dead-lettered.
+func ConsumeDeadLetters(ch *amqp.Channel, exchange string) (<-chan amqp.Delivery, error) {
+	deliveries, err := ch.Consume(DeadLetterQueue(exchange), "", false, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rmq_rpc - ConsumeDeadLetters - ch.Consume: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDeadLetters republishes up to limit messages of the queue declared by the
+// DeadLetter option for exchange, all those queued when it is called if limit is zero
+// or negative, to the exchange and with the routing key they were originally
+// published with, and removes them from the dead-letter queue.
+// It returns the number of messages replayed.
+//
+// Example:
+//
+//	conn, err := amqp.Dial(url)
+//	ch, err := conn.Channel()
+//	n, err := rabbitmq.ReplayDeadLetters(ctx, ch, "rpc.dead", 0)
+func ReplayDeadLetters(ctx context.Context, ch *amqp.Channel, exchange string, limit int) (int, error) {
+	name := DeadLetterQueue(exchange)
+
+	// Bound the replay to the messages queued now, so that messages dead-lettered
+	// again while replaying are not replayed in a loop.
+	queue, err := ch.QueueDeclarePassive(name, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("rmq_rpc - ReplayDeadLetters - ch.QueueDeclarePassive: %w", err)
+	}
+
+	if limit <= 0 || limit > queue.Messages {
+		limit = queue.Messages
+	}
+
+	for n := 0; n < limit; n++ {
+		d, ok, err := ch.Get(name, false)
+		if err != nil {
+			return n, fmt.Errorf("rmq_rpc - ReplayDeadLetters - ch.Get: %w", err)
+		}
+
+		if !ok {
+			return n, nil
+		}
+
+		exchange, routingKey := origin(&d)
+
+		err = ch.PublishWithContext(ctx, exchange, routingKey, false, false, amqp.Publishing{
+			Headers:         d.Headers,
+			ContentType:     d.ContentType,
+			ContentEncoding: d.ContentEncoding,
+			DeliveryMode:    d.DeliveryMode,
+			Priority:        d.Priority,
+			CorrelationId:   d.CorrelationId,
+			ReplyTo:         d.ReplyTo,
+			MessageId:       d.MessageId,
+			Timestamp:       d.Timestamp,
+			Type:            d.Type,
+			UserId:          d.UserId,
+			AppId:           d.AppId,
+			Body:            d.Body,
+		})
+		if err != nil {
+			_ = d.Nack(false, true) //nolint:errcheck // the publish error is returned
+
+			return n, fmt.Errorf("rmq_rpc - ReplayDeadLetters - ch.PublishWithContext: %w", err)
+		}
+
+		if err := d.Ack(false); err != nil {
+			return n, fmt.Errorf("rmq_rpc - ReplayDeadLetters - d.Ack: %w", err)
+		}
+	}
+
+	return limit, nil
+}
+
+// origin returns the exchange and routing key a dead letter was first published with,
+// from the last entry of its "x-death" header.
+func origin(d *amqp.Delivery) (exchange, routingKey string) {
+	exchange, routingKey = d.Exchange, d.RoutingKey
+
+	deaths, ok := d.Headers["x-death"].([]any)
+	if !ok || len(deaths) == 0 {
+		return exchange, routingKey
+	}
+
+	// Entries are ordered from the most recent death.
+	death, ok := deaths[len(deaths)-1].(amqp.Table)
+	if !ok {
+		return exchange, routingKey
+	}
+
+	if e, ok := death["exchange"].(string); ok {
+		exchange = e
+	}
+
+	if keys, ok := death["routing-keys"].([]any); ok && len(keys) > 0 {
+		if k, ok := keys[0].(string); ok {
+			routingKey = k
+		}
+	}
+
+	return exchange, routingKey
+}
@@ -0,0 +1,44 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestOrigin(t *testing.T) {
+	tests := []struct {
+		name         string
+		d            amqp.Delivery
+		wantExchange string
+		wantKey      string
+	}{
+		{
+			name:         "without x-death",
+			d:            amqp.Delivery{Exchange: "orders.dead", RoutingKey: "order.created"},
+			wantExchange: "orders.dead",
+			wantKey:      "order.created",
+		},
+		{
+			name: "first death",
+			d: amqp.Delivery{
+				Exchange: "orders.dead",
+				Headers: amqp.Table{"x-death": []any{
+					amqp.Table{"exchange": "replayed", "routing-keys": []any{"order.retried"}},
+					amqp.Table{"exchange": "orders", "routing-keys": []any{"order.created"}},
+				}},
+			},
+			wantExchange: "orders",
+			wantKey:      "order.created",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchange, key := origin(&tt.d)
+			if exchange != tt.wantExchange || key != tt.wantKey {
+				t.Errorf("origin() = %q, %q, want %q, %q", exchange, key, tt.wantExchange, tt.wantKey)
+			}
+		})
+	}
+}
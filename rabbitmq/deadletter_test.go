@@ -0,0 +1,56 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestDeadLetter(t *testing.T) {
+	conn := rabbitmq.New("orders", rabbitmq.Config{},
+		rabbitmq.QueueArgs(amqp.Table{"x-queue-type": "quorum"}),
+		rabbitmq.DeadLetter("orders.dead", "failed"),
+	)
+
+	if conn.Topology.DeadLetterExchange != "orders.dead" || conn.Topology.DeadLetterRoutingKey != "failed" {
+		t.Errorf("Topology dead letter = %q %q, want orders.dead failed",
+			conn.Topology.DeadLetterExchange, conn.Topology.DeadLetterRoutingKey)
+	}
+
+	want := amqp.Table{
+		"x-queue-type":              "quorum",
+		"x-dead-letter-exchange":    "orders.dead",
+		"x-dead-letter-routing-key": "failed",
+	}
+	if !reflect.DeepEqual(conn.Topology.QueueArgs, want) {
+		t.Errorf("QueueArgs = %v, want %v", conn.Topology.QueueArgs, want)
+	}
+
+	if got := rabbitmq.DeadLetterQueue("orders.dead"); got != "orders.dead.dlq" {
+		t.Errorf("DeadLetterQueue() = %q, want orders.dead.dlq", got)
+	}
+}
+
+func TestDeadLetter_KeepsRoutingKey(t *testing.T) {
+	conn := rabbitmq.New("orders", rabbitmq.Config{}, rabbitmq.DeadLetter("orders.dead", ""))
+
+	if _, ok := conn.Topology.QueueArgs["x-dead-letter-routing-key"]; ok {
+		t.Error("expected no x-dead-letter-routing-key without a routing key")
+	}
+}
+
+func TestDeadLetter_NotConfigured(t *testing.T) {
+	conn := rabbitmq.New("orders", rabbitmq.Config{})
+
+	if _, err := conn.ConsumeDeadLetters(); !errors.Is(err, rabbitmq.ErrNoDeadLetter) {
+		t.Errorf("ConsumeDeadLetters() error = %v, want ErrNoDeadLetter", err)
+	}
+
+	if _, err := conn.ReplayDeadLetters(context.Background(), 0); !errors.Is(err, rabbitmq.ErrNoDeadLetter) {
+		t.Errorf("ReplayDeadLetters() error = %v, want ErrNoDeadLetter", err)
+	}
+}
@@ -0,0 +1,124 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const _delayedMessageExchange = "x-delayed-message"
+
+// DelayedMessageExchange declares the exchange with the rabbitmq_delayed_message_exchange
+// plugin, routing as ExchangeType once the "x-delay" header of a message has elapsed.
+// Publisher.PublishWithDelay then sets that header instead of going through delay
+// queues. Every connection declaring the exchange must use it.
+// Default is a plain exchange.
+//
+// Example:
+//
+//	p, err := rabbitmq.NewPublisher("tasks", cfg,
+//	    rabbitmq.ExchangeType(amqp.ExchangeDirect),
+//	    rabbitmq.ExchangeDurable(true),
+//	    rabbitmq.DelayedMessageExchange(),
+//	)
+func DelayedMessageExchange() Option {
+	return func(c *Connection) {
+		c.Topology.DelayedMessageExchange = true
+	}
+}
+
+// DelayQueue returns the name of the exchange and queue holding messages published to
+// exchange with delay by Publisher.PublishWithDelay without the delayed message
+// exchange plugin, e.g. "tasks.delay.30000" for 30 seconds.
+func DelayQueue(exchange string, delay time.Duration) string {
+	return fmt.Sprintf("%s.delay.%d", exchange, delay.Milliseconds())
+}
+
+func (t Topology) declareExchange(ch *amqp.Channel, name string) error {
+	kind, args := t.ExchangeType, amqp.Table(nil)
+
+	if t.DelayedMessageExchange {
+		kind, args = _delayedMessageExchange, amqp.Table{"x-delayed-type": t.ExchangeType}
+	}
+
+	return ch.ExchangeDeclare(name, kind, t.ExchangeDurable, t.ExchangeAutoDelete, false, false, args)
+}
+
+// PublishWithDelay publishes msg to the exchange with routingKey once delay has
+// elapsed, with millisecond precision, e.g. to retry later or schedule a task.
+//
+// With DelayedMessageExchange the delay is set in the "x-delay" header. Otherwise the
+// message waits in the durable DelayQueue of the delay, declared on first use, whose
+// message TTL dead-letters it to the exchange with its routing key. Use few distinct
+// delays, as each one has its queue.
+func (p *Publisher) PublishWithDelay(ctx context.Context, routingKey string, delay time.Duration, msg amqp.Publishing) error {
+	delay = delay.Truncate(time.Millisecond)
+	if delay <= 0 {
+		return p.Publish(ctx, routingKey, msg)
+	}
+
+	if p.conn.Topology.DelayedMessageExchange {
+		headers := amqp.Table{}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+
+		headers["x-delay"] = delay.Milliseconds()
+		msg.Headers = headers
+
+		return p.Publish(ctx, routingKey, msg)
+	}
+
+	err := p.declareDelay(delay)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - Publisher - PublishWithDelay - p.declareDelay: %w", err)
+	}
+
+	return p.publish(ctx, DelayQueue(p.exchange, delay), routingKey, msg)
+}
+
+// declareDelay declares the fanout exchange and queue of delay, which keep the routing
+// key of the messages, and dead-letters them to the exchange once expired.
+func (p *Publisher) declareDelay(delay time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	if p.delays[delay] {
+		return nil
+	}
+
+	err := p.reconnect()
+	if err != nil {
+		return err
+	}
+
+	name := DelayQueue(p.exchange, delay)
+
+	err = p.conn.Channel.ExchangeDeclare(name, amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("p.conn.Channel.ExchangeDeclare: %w", err)
+	}
+
+	_, err = p.conn.Channel.QueueDeclare(name, true, false, false, false, amqp.Table{
+		amqp.QueueMessageTTLArg:  delay.Milliseconds(),
+		"x-dead-letter-exchange": p.exchange,
+	})
+	if err != nil {
+		return fmt.Errorf("p.conn.Channel.QueueDeclare: %w", err)
+	}
+
+	err = p.conn.Channel.QueueBind(name, "", name, false, nil)
+	if err != nil {
+		return fmt.Errorf("p.conn.Channel.QueueBind: %w", err)
+	}
+
+	p.delays[delay] = true
+
+	return nil
+}
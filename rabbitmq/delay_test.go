@@ -0,0 +1,45 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestDelayQueue(t *testing.T) {
+	if got := rabbitmq.DelayQueue("tasks", 30*time.Second); got != "tasks.delay.30000" {
+		t.Errorf("DelayQueue() = %q, want tasks.delay.30000", got)
+	}
+}
+
+func TestDelayedMessageExchange(t *testing.T) {
+	conn := rabbitmq.New("tasks", rabbitmq.Config{}, rabbitmq.DelayedMessageExchange())
+
+	if !conn.Topology.DelayedMessageExchange {
+		t.Error("DelayedMessageExchange option not applied")
+	}
+}
+
+func TestPublisher_PublishWithDelayClosed(t *testing.T) {
+	for _, opts := range [][]rabbitmq.Option{nil, {rabbitmq.DelayedMessageExchange()}} {
+		p, err := rabbitmq.NewPublisher("tasks", rabbitmq.Config{}, opts...)
+		if err != nil {
+			t.Fatalf("NewPublisher() error = %v", err)
+		}
+
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		for _, delay := range []time.Duration{0, time.Minute} {
+			err := p.PublishWithDelay(context.Background(), "task.run", delay, amqp.Publishing{Body: []byte("run")})
+			if !errors.Is(err, rabbitmq.ErrPublisherClosed) {
+				t.Errorf("PublishWithDelay(%s) error = %v, want ErrPublisherClosed", delay, err)
+			}
+		}
+	}
+}
@@ -9,6 +9,20 @@ var (
 	ErrInternalServer = errors.New("internal server error")
 	// ErrBadHandler -.
 	ErrBadHandler = errors.New("unregistered handler")
+	// ErrBadRequest is the status of requests rejected as malformed, by a validator or
+	// with a *BadRequestError.
+	ErrBadRequest = errors.New("bad request")
+	// ErrValidation is the status of requests rejected with a *ValidationError.
+	ErrValidation = errors.New("validation failed")
+	// ErrNoDeadLetter is returned by the dead-letter helpers of a connection without
+	// the DeadLetter option.
+	ErrNoDeadLetter = errors.New("no dead-letter exchange")
+	// ErrNacked is returned by Connection.Publish when the broker negatively
+	// acknowledges a message, failing to route or store it.
+	ErrNacked = errors.New("publish nacked")
+	// ErrConnectionLost is the error of the first ReconnectEvent, when the connection
+	// or channel was closed by the broker or the network.
+	ErrConnectionLost = errors.New("connection lost")
 )
 
 // Success -.
@@ -0,0 +1,207 @@
+package rabbitmq
+
+import (
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Option configures the topology and QoS of a Connection.
+type Option func(*Connection)
+
+// ExchangeType sets the type of the exchange: amqp.ExchangeDirect, amqp.ExchangeFanout,
+// amqp.ExchangeTopic or amqp.ExchangeHeaders.
+// Default is amqp.ExchangeFanout.
+func ExchangeType(kind string) Option {
+	return func(c *Connection) {
+		if kind != "" {
+			c.Topology.ExchangeType = kind
+		}
+	}
+}
+
+// ExchangeDurable sets whether the exchange survives broker restarts.
+// Default is false.
+func ExchangeDurable(durable bool) Option {
+	return func(c *Connection) {
+		c.Topology.ExchangeDurable = durable
+	}
+}
+
+// ExchangeAutoDelete sets whether the exchange is deleted once no queue is bound to it.
+// Default is false.
+func ExchangeAutoDelete(autoDelete bool) Option {
+	return func(c *Connection) {
+		c.Topology.ExchangeAutoDelete = autoDelete
+	}
+}
+
+// Queue sets the name of the queue, shared by the connections consuming it, which
+// makes it non-exclusive. Apply QueueExclusive after it to change that.
+// Default is an exclusive queue named by the server.
+func Queue(name string) Option {
+	return func(c *Connection) {
+		c.Topology.Queue = name
+		c.Topology.QueueExclusive = false
+	}
+}
+
+// QueueDurable sets whether the queue survives broker restarts.
+// Default is false.
+func QueueDurable(durable bool) Option {
+	return func(c *Connection) {
+		c.Topology.QueueDurable = durable
+	}
+}
+
+// QueueAutoDelete sets whether the queue is deleted once its last consumer is gone.
+// Default is false.
+func QueueAutoDelete(autoDelete bool) Option {
+	return func(c *Connection) {
+		c.Topology.QueueAutoDelete = autoDelete
+	}
+}
+
+// QueueExclusive sets whether the queue is used by this connection only and deleted
+// when it closes.
+// Default is true, false after Queue.
+func QueueExclusive(exclusive bool) Option {
+	return func(c *Connection) {
+		c.Topology.QueueExclusive = exclusive
+	}
+}
+
+// QueueArgs adds arguments to the queue declaration, such as "x-queue-type",
+// "x-message-ttl" or "x-dead-letter-exchange". Arguments of several calls are merged.
+// Default is no arguments.
+//
+// Example:
+//
+//	conn := rabbitmq.New("orders", cfg,
+//	    rabbitmq.ExchangeType(amqp.ExchangeTopic),
+//	    rabbitmq.ExchangeDurable(true),
+//	    rabbitmq.Queue("billing.orders"),
+//	    rabbitmq.QueueDurable(true),
+//	    rabbitmq.QueueArgs(amqp.Table{"x-single-active-consumer": true}),
+//	    rabbitmq.BindingKeys("order.created", "order.paid"),
+//	)
+func QueueArgs(args amqp.Table) Option {
+	return func(c *Connection) {
+		if c.Topology.QueueArgs == nil {
+			c.Topology.QueueArgs = amqp.Table{}
+		}
+
+		for k, v := range args {
+			c.Topology.QueueArgs[k] = v
+		}
+	}
+}
+
+// QueueType sets the type of the queue: amqp.QueueTypeClassic, amqp.QueueTypeQuorum or
+// amqp.QueueTypeStream. Quorum and stream queues are replicated and must be durable and
+// not exclusive, which this sets; they also need a name, given with Queue.
+// Default is the broker default, usually classic.
+//
+// Example:
+//
+//	conn := rabbitmq.New("orders", cfg,
+//	    rabbitmq.Queue("billing.orders"),
+//	    rabbitmq.QueueType(amqp.QueueTypeQuorum),
+//	    rabbitmq.DeliveryLimit(5),
+//	)
+func QueueType(kind string) Option {
+	return func(c *Connection) {
+		if kind == "" {
+			return
+		}
+
+		QueueArgs(amqp.Table{amqp.QueueTypeArg: kind})(c)
+
+		if kind != amqp.QueueTypeClassic {
+			c.Topology.QueueDurable = true
+			c.Topology.QueueExclusive = false
+			c.Topology.QueueAutoDelete = false
+		}
+	}
+}
+
+// DeliveryLimit sets how many times a message of a quorum queue is delivered before it
+// is dropped or, with DeadLetter, dead-lettered, guarding against messages that crash
+// their consumer.
+// Default is the broker default.
+func DeliveryLimit(n int) Option {
+	return func(c *Connection) {
+		if n > 0 {
+			QueueArgs(amqp.Table{"x-delivery-limit": n})(c)
+		}
+	}
+}
+
+// MaxLength sets the maximum number of ready messages in the queue. Past it, the
+// oldest are dropped or dead-lettered.
+// Default is no limit.
+func MaxLength(n int) Option {
+	return func(c *Connection) {
+		if n > 0 {
+			QueueArgs(amqp.Table{amqp.QueueMaxLenArg: n})(c)
+		}
+	}
+}
+
+// MaxLengthBytes sets the maximum total size in bytes of the ready message bodies in
+// the queue. Past it, the oldest are dropped or dead-lettered.
+// Default is no limit.
+func MaxLengthBytes(n int) Option {
+	return func(c *Connection) {
+		if n > 0 {
+			QueueArgs(amqp.Table{amqp.QueueMaxLenBytesArg: n})(c)
+		}
+	}
+}
+
+// MessageTTL sets how long a message may stay in the queue, with millisecond
+// precision, before it is dropped or dead-lettered.
+// Default is no expiry.
+func MessageTTL(ttl time.Duration) Option {
+	return func(c *Connection) {
+		if ttl > 0 {
+			QueueArgs(amqp.Table{amqp.QueueMessageTTLArg: ttl.Milliseconds()})(c)
+		}
+	}
+}
+
+// QoS sets how many messages, and how many bytes of message bodies, the broker
+// delivers to the consumer ahead of their acknowledgement; zero means no limit.
+// Messages acknowledged only once handled then stay in the queue, available to other
+// consumers, rather than piling up in a slow one.
+// Default is no limit.
+//
+// Example:
+//
+//	conn := rabbitmq.New("orders", cfg, rabbitmq.QoS(10, 0))
+func QoS(count, size int) Option {
+	return func(c *Connection) {
+		c.Topology.PrefetchCount = max(count, 0)
+		c.Topology.PrefetchSize = max(size, 0)
+	}
+}
+
+// BindingKeys sets the routing keys, or patterns for a topic exchange, the queue is
+// bound with, once each.
+// Default is a single empty key, enough for fanout and headers exchanges.
+func BindingKeys(keys ...string) Option {
+	return func(c *Connection) {
+		if len(keys) > 0 {
+			c.Topology.BindingKeys = keys
+		}
+	}
+}
+
+// BindingArgs sets the arguments of the bindings, e.g. the headers to match and
+// "x-match" for a headers exchange.
+// Default is no arguments.
+func BindingArgs(args amqp.Table) Option {
+	return func(c *Connection) {
+		c.Topology.BindingArgs = args
+	}
+}
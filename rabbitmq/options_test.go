@@ -0,0 +1,132 @@
+package rabbitmq_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestTopology_Default(t *testing.T) {
+	conn := rabbitmq.New("events", rabbitmq.Config{})
+
+	want := rabbitmq.Topology{
+		ExchangeType:   amqp.ExchangeFanout,
+		QueueExclusive: true,
+		BindingKeys:    []string{""},
+	}
+
+	if !reflect.DeepEqual(conn.Topology, want) {
+		t.Errorf("Topology = %+v, want %+v", conn.Topology, want)
+	}
+}
+
+func TestTopology_Options(t *testing.T) {
+	conn := rabbitmq.New("orders", rabbitmq.Config{},
+		rabbitmq.ExchangeType(amqp.ExchangeTopic),
+		rabbitmq.ExchangeDurable(true),
+		rabbitmq.ExchangeAutoDelete(true),
+		rabbitmq.Queue("billing.orders"),
+		rabbitmq.QueueDurable(true),
+		rabbitmq.QueueAutoDelete(true),
+		rabbitmq.QueueArgs(amqp.Table{"x-queue-type": "quorum"}),
+		rabbitmq.QueueArgs(amqp.Table{"x-message-ttl": 60000}),
+		rabbitmq.BindingKeys("order.created", "order.*.paid"),
+		rabbitmq.BindingArgs(amqp.Table{"x-match": "all"}),
+	)
+
+	want := rabbitmq.Topology{
+		ExchangeType:       amqp.ExchangeTopic,
+		ExchangeDurable:    true,
+		ExchangeAutoDelete: true,
+		Queue:              "billing.orders",
+		QueueDurable:       true,
+		QueueAutoDelete:    true,
+		QueueArgs:          amqp.Table{"x-queue-type": "quorum", "x-message-ttl": 60000},
+		BindingKeys:        []string{"order.created", "order.*.paid"},
+		BindingArgs:        amqp.Table{"x-match": "all"},
+	}
+
+	if !reflect.DeepEqual(conn.Topology, want) {
+		t.Errorf("Topology = %+v, want %+v", conn.Topology, want)
+	}
+}
+
+func TestTopology_QueueExclusive(t *testing.T) {
+	conn := rabbitmq.New("events", rabbitmq.Config{},
+		rabbitmq.Queue("events.audit"),
+		rabbitmq.QueueExclusive(true),
+		rabbitmq.ExchangeType(""),
+		rabbitmq.BindingKeys(),
+	)
+
+	if !conn.Topology.QueueExclusive {
+		t.Error("QueueExclusive after Queue should make the queue exclusive")
+	}
+
+	if conn.Topology.ExchangeType != amqp.ExchangeFanout || !reflect.DeepEqual(conn.Topology.BindingKeys, []string{""}) {
+		t.Errorf("empty options changed defaults: %+v", conn.Topology)
+	}
+}
+
+func TestTopology_QueueType(t *testing.T) {
+	conn := rabbitmq.New("orders", rabbitmq.Config{},
+		rabbitmq.QueueAutoDelete(true),
+		rabbitmq.Queue("billing.orders"),
+		rabbitmq.QueueType(amqp.QueueTypeQuorum),
+		rabbitmq.DeliveryLimit(5),
+		rabbitmq.MaxLength(1000),
+		rabbitmq.MaxLengthBytes(1<<20),
+		rabbitmq.MessageTTL(90*time.Second),
+	)
+
+	want := amqp.Table{
+		amqp.QueueTypeArg:        amqp.QueueTypeQuorum,
+		"x-delivery-limit":       5,
+		amqp.QueueMaxLenArg:      1000,
+		amqp.QueueMaxLenBytesArg: 1 << 20,
+		amqp.QueueMessageTTLArg:  int64(90000),
+	}
+
+	if !reflect.DeepEqual(conn.Topology.QueueArgs, want) {
+		t.Errorf("QueueArgs = %v, want %v", conn.Topology.QueueArgs, want)
+	}
+
+	if !conn.Topology.QueueDurable || conn.Topology.QueueExclusive || conn.Topology.QueueAutoDelete {
+		t.Errorf("quorum queue should be durable, not exclusive nor auto-deleted: %+v", conn.Topology)
+	}
+}
+
+func TestTopology_QueueTypeClassic(t *testing.T) {
+	conn := rabbitmq.New("events", rabbitmq.Config{},
+		rabbitmq.QueueType(amqp.QueueTypeClassic),
+		rabbitmq.QueueType(""),
+		rabbitmq.DeliveryLimit(0),
+		rabbitmq.MaxLength(-1),
+		rabbitmq.MessageTTL(0),
+	)
+
+	if want := (amqp.Table{amqp.QueueTypeArg: amqp.QueueTypeClassic}); !reflect.DeepEqual(conn.Topology.QueueArgs, want) {
+		t.Errorf("QueueArgs = %v, want %v", conn.Topology.QueueArgs, want)
+	}
+
+	if !conn.Topology.QueueExclusive || conn.Topology.QueueDurable {
+		t.Errorf("classic queue type changed queue defaults: %+v", conn.Topology)
+	}
+}
+
+func TestTopology_QoS(t *testing.T) {
+	conn := rabbitmq.New("events", rabbitmq.Config{}, rabbitmq.QoS(10, 4096))
+
+	if conn.Topology.PrefetchCount != 10 || conn.Topology.PrefetchSize != 4096 {
+		t.Errorf("QoS(10, 4096) = %d, %d", conn.Topology.PrefetchCount, conn.Topology.PrefetchSize)
+	}
+
+	rabbitmq.QoS(-1, -1)(conn)
+
+	if conn.Topology.PrefetchCount != 0 || conn.Topology.PrefetchSize != 0 {
+		t.Errorf("QoS(-1, -1) = %d, %d, want no limit", conn.Topology.PrefetchCount, conn.Topology.PrefetchSize)
+	}
+}
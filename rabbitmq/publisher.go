@@ -0,0 +1,174 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrPublisherClosed is returned by Publish after Close.
+var ErrPublisherClosed = errors.New("rmq_rpc - publisher closed")
+
+// Publisher publishes messages to an exchange with routing keys, outside of the RPC
+// client and server, waiting for the broker to confirm them. A lost connection is
+// re-established by the next Publish. It is safe for concurrent use, publishing on
+// up to cfg.PublishChannels channels at once.
+type Publisher struct {
+	exchange string
+	conn     *Connection
+
+	mu     sync.Mutex
+	delays map[time.Duration]bool
+	closed bool
+}
+
+// NewPublisher connects to RabbitMQ, retrying according to cfg.Attempts and
+// cfg.WaitTime, and declares exchange. Only the exchange options apply.
+//
+// Example:
+//
+//	p, err := rabbitmq.NewPublisher("orders", cfg,
+//	    rabbitmq.ExchangeType(amqp.ExchangeTopic),
+//	    rabbitmq.ExchangeDurable(true),
+//	)
+//	err = p.PublishJSON(ctx, "order.created", order)
+func NewPublisher(exchange string, cfg Config, opts ...Option) (*Publisher, error) {
+	p := &Publisher{
+		exchange: exchange,
+		conn:     New(exchange, cfg, opts...),
+		delays:   make(map[time.Duration]bool),
+	}
+
+	err := p.conn.attempt(func() error {
+		return permanent(p.connect())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rmq_rpc - NewPublisher - p.connect: %w", err)
+	}
+
+	return p, nil
+}
+
+func (p *Publisher) connect() error {
+	conn, err := p.conn.dial()
+	p.conn.setConnection(conn)
+
+	if err != nil {
+		return fmt.Errorf("p.conn.dial: %w", err)
+	}
+
+	p.conn.Channel, err = conn.Channel()
+	if err != nil {
+		_ = p.conn.Connection.Close()
+
+		return fmt.Errorf("p.conn.Connection.Channel: %w", err)
+	}
+
+	t := p.conn.Topology
+
+	err = t.declareExchange(p.conn.Channel, p.exchange)
+	if err != nil {
+		_ = p.conn.Connection.Close()
+
+		return fmt.Errorf("t.declareExchange: %w", err)
+	}
+
+	return nil
+}
+
+// Publish publishes msg to the exchange with routingKey, with the trace context of
+// ctx in its headers. A Timestamp is set when missing.
+func (p *Publisher) Publish(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	return p.publish(ctx, p.exchange, routingKey, msg)
+}
+
+func (p *Publisher) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	if err := p.ready(); err != nil {
+		return err
+	}
+
+	msg.Headers = InjectTrace(ctx, msg.Headers)
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	err := p.conn.Publish(ctx, exchange, routingKey, msg)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - Publisher - Publish - p.conn.Publish: %w", err)
+	}
+
+	return nil
+}
+
+// ready checks the publisher is open and connected, reconnecting if needed.
+func (p *Publisher) ready() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	if err := p.reconnect(); err != nil {
+		return fmt.Errorf("rmq_rpc - Publisher - Publish - p.reconnect: %w", err)
+	}
+
+	return nil
+}
+
+// reconnect connects again when the channel declaring exchanges was closed, e.g. by a
+// lost connection or a failed declaration. It must be called with mu held.
+func (p *Publisher) reconnect() error {
+	if p.conn.Channel != nil && !p.conn.Channel.IsClosed() {
+		return nil
+	}
+
+	if p.conn.Connection != nil {
+		_ = p.conn.Connection.Close()
+	}
+
+	return p.connect()
+}
+
+// PublishJSON publishes v marshaled to JSON as a persistent message.
+func (p *Publisher) PublishJSON(ctx context.Context, routingKey string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - Publisher - PublishJSON - json.Marshal: %w", err)
+	}
+
+	return p.Publish(ctx, routingKey, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+	})
+}
+
+// Close closes the connection. Publish fails with ErrPublisherClosed afterwards.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+
+	if p.conn.Connection == nil {
+		return nil
+	}
+
+	err := p.conn.Connection.Close()
+	if err != nil && !errors.Is(err, amqp.ErrClosed) {
+		return fmt.Errorf("rmq_rpc - Publisher - Close - p.conn.Connection.Close: %w", err)
+	}
+
+	return nil
+}
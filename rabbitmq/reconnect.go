@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const _reconnectJitter = 0.2
+
+// ReconnectEvent reports the progress of Reconnect, and is sent on the Notify channel
+// of the RPC client and server while they reconnect: once with ErrConnectionLost when
+// the connection is lost, after every failed attempt with its error, and with a nil
+// Err once reconnected. It is not fatal; Temporary reports true, so that app.Runner
+// logs it instead of shutting the service down.
+type ReconnectEvent struct {
+	// Attempt is the number of the attempt, 0 when the connection is lost.
+	Attempt int
+	// Err is the error of the attempt, nil once reconnected.
+	Err error
+}
+
+func (e *ReconnectEvent) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("rmq_rpc - reconnected after %d attempts", e.Attempt)
+	}
+
+	return fmt.Sprintf("rmq_rpc - reconnect attempt %d: %v", e.Attempt, e.Err)
+}
+
+func (e *ReconnectEvent) Unwrap() error { return e.Err }
+
+// Temporary reports true: the connection is being re-established.
+func (e *ReconnectEvent) Temporary() bool { return true }
+
+// Reconnected reports whether the event is the last of a successful Reconnect.
+func (e *ReconnectEvent) Reconnected() bool { return e.Err == nil }
+
+// Reconnect re-establishes the connection and its topology after it was lost, with an
+// exponential backoff from WaitTime up to ReconnectMaxWait randomized by ±20%, for up
+// to ReconnectAttempts attempts or until ctx is done. It stops at once on errors
+// retrying cannot fix, such as refused credentials or a declaration mismatching the
+// existing topology. The events are passed to onEvent, which may be nil.
+func (c *Connection) Reconnect(ctx context.Context, onEvent func(*ReconnectEvent)) error {
+	if onEvent == nil {
+		onEvent = func(*ReconnectEvent) {}
+	}
+
+	onEvent(&ReconnectEvent{Err: ErrConnectionLost})
+
+	attempts := c.ReconnectAttempts
+	if attempts <= 0 {
+		attempts = math.MaxInt
+	}
+
+	backoff := retry.ConstantBackoff(c.WaitTime)
+	if c.WaitTime > 0 && c.ReconnectMaxWait > c.WaitTime {
+		backoff = retry.ExponentialBackoff(c.WaitTime, c.ReconnectMaxWait)
+	}
+
+	var attempt int
+
+	err := retry.Do(ctx, func(context.Context) error {
+		attempt++
+
+		return c.connect()
+	},
+		retry.Attempts(attempts),
+		backoff,
+		retry.Jitter(_reconnectJitter),
+		retry.WithClock(c.Clock),
+		retry.OnRetry(func(n int, err error, _ time.Duration) {
+			onEvent(&ReconnectEvent{Attempt: n, Err: err})
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("rmq_rpc - Reconnect - c.connect: %w", err)
+	}
+
+	onEvent(&ReconnectEvent{Attempt: attempt})
+
+	return nil
+}
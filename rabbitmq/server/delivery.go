@@ -0,0 +1,130 @@
+package server
+
+import (
+	"errors"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+const (
+	_defaultMaxDeliveries = 3
+
+	// _deliveryCountHeader counts the failed deliveries of a message of a quorum queue.
+	_deliveryCountHeader = "x-delivery-count"
+)
+
+// Policy decides when requests are acknowledged and what becomes of failed ones,
+// see the Delivery option. Requests fail when their handler is unknown, they are bad
+// requests or their handler fails after Retries.
+type Policy int
+
+const (
+	// AckOnReceipt acknowledges requests on receipt, before serving them, so failed
+	// requests or those in flight when the server crashes are lost.
+	// Default without DeadLetter.
+	AckOnReceipt Policy = iota + 1
+	// AckOnSuccess acknowledges requests once served and rejects failed ones without
+	// requeue, which the broker drops unless the queue has a dead-letter exchange.
+	// Requests in flight when the server crashes are delivered again.
+	AckOnSuccess
+	// Requeue acknowledges requests once served and requeues failed ones, replying to
+	// the client only after their last delivery, for handlers failing transiently.
+	// Requests are delivered up to MaxDeliveries times, then rejected like AckOnSuccess.
+	// Unknown handlers and bad requests are not requeued.
+	Requeue
+	// RejectToDeadLetter acknowledges requests once served and rejects failed ones to
+	// the dead-letter exchange of the DeadLetter option, which it requires.
+	// Default with DeadLetter.
+	RejectToDeadLetter
+)
+
+// deliveries counts the deliveries of requests requeued by the Requeue policy.
+// Quorum queues count them in the x-delivery-count header; for other queues they are
+// counted by correlation ID, by this server only.
+type deliveries struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// next returns the delivery number of d, starting at 1.
+func (c *deliveries) next(d *amqp.Delivery) int {
+	if count, ok := d.Headers[_deliveryCountHeader].(int64); ok {
+		return int(count) + 1
+	}
+
+	if !d.Redelivered {
+		return 1
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[d.CorrelationId] + 1
+}
+
+// requeued records that delivery n of d was requeued.
+func (c *deliveries) requeued(d *amqp.Delivery, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+
+	c.counts[d.CorrelationId] = n
+}
+
+// settled forgets d once acknowledged or rejected.
+func (c *deliveries) settled(d *amqp.Delivery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.counts, d.CorrelationId)
+}
+
+// requeue reports whether d is requeued if it fails, returning its delivery number.
+func (s *Server) requeue(d *amqp.Delivery) (int, bool) {
+	if s.policy != Requeue {
+		return 0, false
+	}
+
+	n := s.deliveries.next(d)
+
+	return n, n < s.maxDeliveries
+}
+
+// settle acknowledges d once served or, when it failed with err, requeues or rejects it
+// according to the policy.
+func (s *Server) settle(d *amqp.Delivery, n int, requeue bool, err error) {
+	if s.policy == AckOnReceipt {
+		return
+	}
+
+	var settleErr error
+
+	switch {
+	case err != nil && requeue && requeuable(err):
+		s.deliveries.requeued(d, n)
+
+		settleErr = d.Nack(false, true)
+	case err == nil:
+		s.deliveries.settled(d)
+
+		settleErr = d.Ack(false)
+	default:
+		s.deliveries.settled(d)
+
+		settleErr = d.Nack(false, false)
+	}
+
+	if settleErr != nil {
+		s.logger.Error(settleErr, "rmq_rpc server - Server - settle")
+	}
+}
+
+// requeuable reports whether a request failing with err may succeed when delivered again.
+func requeuable(err error) bool {
+	return !errors.Is(err, rmqrpc.ErrBadHandler) && !errors.Is(err, rmqrpc.ErrBadRequest)
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+type settlement struct {
+	ack, nack, requeue bool
+}
+
+type mockAcknowledger struct {
+	settlement
+}
+
+func (m *mockAcknowledger) Ack(uint64, bool) error {
+	m.ack = true
+
+	return nil
+}
+
+func (m *mockAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	m.nack, m.requeue = true, requeue
+
+	return nil
+}
+
+func (m *mockAcknowledger) Reject(_ uint64, requeue bool) error {
+	m.nack, m.requeue = true, requeue
+
+	return nil
+}
+
+func TestDelivery(t *testing.T) {
+	s := &Server{policy: AckOnSuccess}
+
+	Delivery(0)(s)
+	Delivery(RejectToDeadLetter + 1)(s)
+
+	if s.policy != AckOnSuccess {
+		t.Errorf("policy = %v after invalid policies, want AckOnSuccess", s.policy)
+	}
+
+	Delivery(Requeue)(s)
+	MaxDeliveries(0)(s)
+	MaxDeliveries(5)(s)
+
+	if s.policy != Requeue || s.maxDeliveries != 5 {
+		t.Errorf("policy = %v, maxDeliveries = %d, want Requeue and 5", s.policy, s.maxDeliveries)
+	}
+}
+
+func TestServer_settle(t *testing.T) {
+	failed := errors.New("database unavailable")
+
+	tests := []struct {
+		name   string
+		policy Policy
+		err    error
+		want   settlement
+	}{
+		{name: "ack on receipt", policy: AckOnReceipt, err: failed},
+		{name: "ack on success served", policy: AckOnSuccess, want: settlement{ack: true}},
+		{name: "ack on success failed", policy: AckOnSuccess, err: failed, want: settlement{nack: true}},
+		{name: "requeue failed", policy: Requeue, err: failed, want: settlement{nack: true, requeue: true}},
+		{name: "requeue bad request", policy: Requeue, err: rmqrpc.ErrBadRequest, want: settlement{nack: true}},
+		{name: "requeue bad handler", policy: Requeue, err: rmqrpc.ErrBadHandler, want: settlement{nack: true}},
+		{name: "dead letter failed", policy: RejectToDeadLetter, err: failed, want: settlement{nack: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{policy: tt.policy, maxDeliveries: 3}
+			ack := &mockAcknowledger{}
+			d := &amqp.Delivery{Acknowledger: ack, CorrelationId: "corr-1"}
+
+			n, requeue := s.requeue(d)
+			s.settle(d, n, requeue, tt.err)
+
+			if ack.settlement != tt.want {
+				t.Errorf("settlement = %+v, want %+v", ack.settlement, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_requeueMaxDeliveries(t *testing.T) {
+	s := &Server{policy: Requeue, maxDeliveries: 3}
+	failed := errors.New("database unavailable")
+
+	var settlements []settlement
+
+	for i := 0; i < 3; i++ {
+		ack := &mockAcknowledger{}
+		d := &amqp.Delivery{Acknowledger: ack, CorrelationId: "corr-1", Redelivered: i > 0}
+
+		n, requeue := s.requeue(d)
+		if n != i+1 {
+			t.Fatalf("delivery %d numbered %d", i+1, n)
+		}
+
+		s.settle(d, n, requeue, failed)
+
+		settlements = append(settlements, ack.settlement)
+	}
+
+	requeued := settlement{nack: true, requeue: true}
+	if settlements[0] != requeued || settlements[1] != requeued || settlements[2] != (settlement{nack: true}) {
+		t.Errorf("settlements = %+v, want requeued twice then rejected", settlements)
+	}
+
+	if len(s.deliveries.counts) != 0 {
+		t.Errorf("deliveries not forgotten: %v", s.deliveries.counts)
+	}
+
+	quorum := &amqp.Delivery{Headers: amqp.Table{_deliveryCountHeader: int64(2)}, Redelivered: true}
+	if n, requeue := s.requeue(quorum); n != 3 || requeue {
+		t.Errorf("requeue() of the quorum delivery = %d, %v, want 3, false", n, requeue)
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+// Handle registers in router a handler named name, which unmarshals the request body
+// into Req and calls h; its response is marshaled as JSON like any other.
+// A body that does not unmarshal into Req is rejected without calling h, replying a
+// *rabbitmq.ValidationError to the client. An empty body leaves Req its zero value.
+// When Req or *Req has a Validate() error method, a request failing it is rejected
+// too, replying its *rabbitmq.ValidationError or a *rabbitmq.BadRequestError.
+// Pass the router to the Handlers option.
+//
+// Example:
+//
+//	router := make(map[string]server.Handler)
+//	server.Handle(router, "user.get", func(ctx context.Context, req GetUser) (*User, error) {
+//	    return users.Get(ctx, req.ID)
+//	})
+//	server.New(url, exchange, nil, logger, server.Handlers(router))
+func Handle[Req, Resp any](router map[string]Handler, name string, h func(ctx context.Context, req Req) (Resp, error)) {
+	router[name] = func(ctx context.Context, r *Request) (interface{}, error) {
+		var req Req
+
+		if err := bind(r.Body, &req); err != nil {
+			return nil, err
+		}
+
+		if err := validate(&req); err != nil {
+			return nil, err
+		}
+
+		return h(ctx, req)
+	}
+}
+
+// bind unmarshals body into v, reporting unmarshaling errors as *rabbitmq.ValidationError.
+// It uses encoding/json, whose type errors carry the JSON path of the field.
+func bind(body []byte, v interface{}) error {
+	if len(body) == 0 {
+		return nil
+	}
+
+	err := json.Unmarshal(body, v)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &rmqrpc.ValidationError{Fields: []rmqrpc.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be %s, not %s", fieldName(typeErr.Field), typeErr.Type, typeErr.Value),
+		}}}
+	}
+
+	return &rmqrpc.ValidationError{Fields: []rmqrpc.FieldError{{
+		Rule:    "json",
+		Message: "request is not valid JSON: " + err.Error(),
+	}}}
+}
+
+// validate calls the Validate method of *req or req, if any, reporting its failure
+// as a bad request.
+func validate(req interface{}) error {
+	v, ok := req.(interface{ Validate() error })
+	if !ok {
+		return nil
+	}
+
+	err := v.Validate()
+	if err == nil || errors.Is(err, rmqrpc.ErrBadRequest) {
+		return err
+	}
+
+	return &rmqrpc.BadRequestError{Message: err.Error()}
+}
+
+func fieldName(field string) string {
+	if field == "" {
+		return "request"
+	}
+
+	return field
+}
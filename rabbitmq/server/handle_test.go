@@ -0,0 +1,114 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+	"github.com/rdashevsky/go-pkgs/rabbitmq/server"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type signupRequest struct {
+	Email string `json:"email"`
+}
+
+func (r *signupRequest) Validate() error {
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+
+	return nil
+}
+
+func TestHandle(t *testing.T) {
+	router := make(map[string]server.Handler)
+
+	called := false
+
+	server.Handle(router, "greet", func(_ context.Context, req greetRequest) (string, error) {
+		called = true
+
+		return "Hello " + req.Name, nil
+	})
+
+	h := router["greet"]
+	if h == nil {
+		t.Fatal("handler not registered")
+	}
+
+	response, err := h(context.Background(), &server.Request{Body: []byte(`{"name":"gopher"}`)})
+	if err != nil || response != "Hello gopher" {
+		t.Errorf("h() = %v, %v, want Hello gopher", response, err)
+	}
+
+	response, err = h(context.Background(), &server.Request{})
+	if err != nil || response != "Hello " {
+		t.Errorf("h() with empty body = %v, %v, want zero request", response, err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want rmqrpc.FieldError
+	}{
+		{
+			name: "wrong type",
+			body: `{"name":"gopher","age":"old"}`,
+			want: rmqrpc.FieldError{Field: "age", Rule: "type", Message: "age must be int, not string"},
+		},
+		{
+			name: "not JSON",
+			body: `{"name":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+
+			_, err := h(context.Background(), &server.Request{Body: []byte(tt.body)})
+			if !errors.Is(err, rmqrpc.ErrValidation) {
+				t.Fatalf("h() error = %v, want ErrValidation", err)
+			}
+
+			if called {
+				t.Error("handler called with an invalid request")
+			}
+
+			var validationErr *rmqrpc.ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 {
+				t.Fatalf("h() error = %#v, want one field error", err)
+			}
+
+			if tt.want.Rule != "" && validationErr.Fields[0] != tt.want {
+				t.Errorf("field error = %+v, want %+v", validationErr.Fields[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleValidate(t *testing.T) {
+	router := make(map[string]server.Handler)
+
+	server.Handle(router, "signup", func(_ context.Context, req signupRequest) (string, error) {
+		return req.Email, nil
+	})
+
+	response, err := router["signup"](context.Background(), &server.Request{Body: []byte(`{"email":"gopher@example.com"}`)})
+	if err != nil || response != "gopher@example.com" {
+		t.Errorf("h() = %v, %v", response, err)
+	}
+
+	_, err = router["signup"](context.Background(), &server.Request{Body: []byte(`{}`)})
+
+	var badRequestErr *rmqrpc.BadRequestError
+	if !errors.As(err, &badRequestErr) || badRequestErr.Message != "email is required" {
+		t.Errorf("h() error = %v, want bad request", err)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Middleware wraps a Handler, e.g. to log, recover, validate or authorize requests.
+// Handlers of the router given to New are wrapped as Handler.
+type Middleware func(Handler) Handler
+
+// Chain wraps h with middleware; the first middleware is the outermost.
+func Chain(h Handler, mw ...Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+
+	return h
+}
+
+// Recover turns handler panics into errors, replied as ErrInternalServer, and logs
+// their stack trace.
+func Recover(l logger.LoggerI) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, r *Request) (response interface{}, err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = fmt.Errorf("rmq_rpc server - handler panicked: %v", v)
+
+					l.Error("rmq_rpc server - handler %s panicked: %v\n%s", r.Handler, v, debug.Stack())
+				}
+			}()
+
+			return next(ctx, r)
+		}
+	}
+}
+
+// Logger logs every request with its handler, correlation id and duration, and
+// failures at error level. Durations are measured with the clock of WithClock.
+func Logger(l logger.LoggerI) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, r *Request) (interface{}, error) {
+			start := r.now()
+
+			response, err := next(ctx, r)
+			if err != nil {
+				l.Error("rmq_rpc server - %s %s - %s: %v", r.Handler, r.CorrelationID, r.now().Sub(start), err)
+
+				return response, err
+			}
+
+			l.Debug("rmq_rpc server - %s %s - %s", r.Handler, r.CorrelationID, r.now().Sub(start))
+
+			return response, nil
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+// durationLogger records the duration logged at debug level by Logger.
+type durationLogger struct {
+	durations []time.Duration
+}
+
+func (l *durationLogger) Debug(_ interface{}, args ...interface{}) {
+	if d, ok := args[len(args)-1].(time.Duration); ok {
+		l.durations = append(l.durations, d)
+	}
+}
+
+func (l *durationLogger) Info(string, ...interface{})       {}
+func (l *durationLogger) Warn(string, ...interface{})       {}
+func (l *durationLogger) Error(interface{}, ...interface{}) {}
+func (l *durationLogger) Fatal(interface{}, ...interface{}) {}
+
+func TestLogger_Clock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := &durationLogger{}
+
+	h := Logger(l)(func(context.Context, *Request) (interface{}, error) {
+		clk.Advance(3 * time.Second)
+
+		return nil, nil
+	})
+
+	if _, err := h(context.Background(), &Request{Handler: "greet", clock: clk}); err != nil {
+		t.Fatalf("h() error = %v", err)
+	}
+
+	if len(l.durations) != 1 || l.durations[0] != 3*time.Second {
+		t.Errorf("logged durations %v, want [3s] on the server clock", l.durations)
+	}
+}
@@ -1,13 +1,21 @@
 package server
 
-import "time"
+import (
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/clock"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+	"github.com/rdashevsky/go-pkgs/retry"
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
 
 // Option is a function that configures a Server.
 // Options are applied in the order they are passed to New.
 type Option func(*Server)
 
-// Timeout sets the duration to wait before closing the connection during shutdown.
-// This allows pending operations to complete gracefully.
+// Timeout sets how long Shutdown waits for in-flight requests to be served before
+// closing the connection.
 // Default is 2 seconds.
 //
 // Example:
@@ -32,7 +40,7 @@ func ConnWaitTime(timeout time.Duration) Option {
 	}
 }
 
-// ConnAttempts sets the maximum number of connection attempts.
+// ConnAttempts sets the maximum number of attempts of the initial connection.
 // If all attempts fail, New returns an error.
 // Default is 10 attempts.
 //
@@ -44,3 +52,299 @@ func ConnAttempts(attempts int) Option {
 		s.conn.Attempts = attempts
 	}
 }
+
+// Heartbeat sets the interval of the heartbeats detecting dead connections.
+// Default is 10 seconds, unless the URL sets a heartbeat.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Heartbeat(30*time.Second))
+func Heartbeat(d time.Duration) Option {
+	return func(s *Server) {
+		s.conn.Heartbeat = d
+	}
+}
+
+// DialTimeout bounds establishing the TCP connection of each connection attempt.
+// Default is 30 seconds.
+func DialTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.conn.DialTimeout = d
+	}
+}
+
+// VHost sets the virtual host, overriding the one of the URL.
+// Default is the virtual host of the URL.
+func VHost(vhost string) Option {
+	return func(s *Server) {
+		s.conn.VHost = vhost
+	}
+}
+
+// ConnectionName names the connection, e.g. in the management UI.
+// Default is no name.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.ConnectionName("orders-server"))
+func ConnectionName(name string) Option {
+	return func(s *Server) {
+		s.conn.ConnectionName = name
+	}
+}
+
+// AMQPConfig sets the configuration passed to amqp.DialConfig, e.g. for TLS or SASL.
+// Heartbeat, DialTimeout, VHost and ConnectionName take precedence when set.
+// Default is the amqp091-go defaults.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.AMQPConfig(amqp.Config{TLSClientConfig: tlsConfig}))
+func AMQPConfig(cfg amqp.Config) Option {
+	return func(s *Server) {
+		s.conn.AMQP = &cfg
+	}
+}
+
+// ReconnectAttempts sets the maximum number of attempts to reconnect after the
+// connection is lost. Once they are exhausted the error is sent on Notify, which is
+// then closed.
+// Default is 0, retrying until Shutdown.
+func ReconnectAttempts(attempts int) Option {
+	return func(s *Server) {
+		s.conn.ReconnectAttempts = attempts
+	}
+}
+
+// ReconnectMaxWait caps the exponential backoff between reconnection attempts, which
+// starts at ConnWaitTime and is randomized by ±20%.
+// Default is 1 minute.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.ConnWaitTime(time.Second), server.ReconnectMaxWait(30*time.Second))
+func ReconnectMaxWait(d time.Duration) Option {
+	return func(s *Server) {
+		s.conn.ReconnectMaxWait = d
+	}
+}
+
+// PublishChannels sets how many channels publish replies concurrently, each waiting
+// for the broker to confirm its reply before publishing the next. Useful with Workers.
+// Default is 1.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Workers(8), server.PublishChannels(4))
+func PublishChannels(n int) Option {
+	return func(s *Server) {
+		s.conn.PublishChannels = n
+	}
+}
+
+// Workers sets the number of requests processed concurrently.
+// With a single worker requests are handled sequentially in the consumer goroutine;
+// with more, they are dispatched to a bounded worker pool and Shutdown drains
+// in-flight requests within the Timeout period.
+// Default is 1.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Workers(8))
+func Workers(n int) Option {
+	return func(s *Server) {
+		s.workers = n
+	}
+}
+
+// Semaphore bounds handler executions with sem, each request holding a weight of 1
+// while its handler runs. Sharing sem between servers, consumers or HTTP handlers
+// bounds their combined concurrency, e.g. against a shared database.
+// Default is no semaphore.
+//
+// Example:
+//
+//	db := semaphore.New(20)
+//	server.New(url, exchange, router, logger, server.Workers(8), server.Semaphore(db))
+func Semaphore(sem *semaphore.Semaphore) Option {
+	return func(s *Server) {
+		s.sem = sem
+	}
+}
+
+// Retries sets how many times a failing handler is called again before the request
+// fails, replying ErrInternalServer and being settled by the Delivery policy, waiting
+// as set by RetryBackoff between calls. Handlers must then be idempotent. Bad requests,
+// matching rabbitmq.ErrBadRequest, are not retried.
+// Default is 0, no retries.
+func Retries(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.retries = n
+		}
+	}
+}
+
+// RetryBackoff sets the wait before the first retry of a failing handler, doubled on
+// every further retry up to maxDelay.
+// Default is 100ms up to 2 seconds.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Retries(3), server.RetryBackoff(time.Second, 5*time.Second))
+func RetryBackoff(initial, maxDelay time.Duration) Option {
+	return func(s *Server) {
+		s.backoff = retry.Exponential(initial, maxDelay)
+	}
+}
+
+// DeadLetter declares the dead-letter exchange and queue of rabbitmq.DeadLetter for the
+// request queue. The delivery policy then defaults to RejectToDeadLetter: requests are
+// acknowledged once served instead of on receipt, and failed ones are rejected to the
+// dead-letter queue rather than dropped, to be inspected or replayed with
+// rabbitmq.Connection.ReplayDeadLetters.
+// Default is no dead-lettering.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Retries(2), server.DeadLetter("rpc.dead", ""))
+func DeadLetter(exchange, routingKey string) Option {
+	return func(s *Server) {
+		rmqrpc.DeadLetter(exchange, routingKey)(s.conn)
+	}
+}
+
+// Topology applies rabbitmq options to the request queue and its exchange, e.g. to
+// serve from a named quorum queue shared by several servers.
+// Default is a fanout exchange and an exclusive queue named by the server.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Topology(
+//	    rabbitmq.Queue("rpc.requests"),
+//	    rabbitmq.QueueType(amqp.QueueTypeQuorum),
+//	    rabbitmq.DeliveryLimit(5),
+//	))
+func Topology(opts ...rmqrpc.Option) Option {
+	return func(s *Server) {
+		for _, opt := range opts {
+			opt(s.conn)
+		}
+	}
+}
+
+// Prefetch sets how many requests the server receives ahead of acknowledging them,
+// with rabbitmq.QoS. Requests are acknowledged on receipt with the AckOnReceipt
+// delivery policy; with the others a slow server holds at most count requests and
+// leaves the rest in the queue for other servers sharing it.
+// Default is 0, no limit.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Workers(8), server.DeadLetter("rpc.dead", ""), server.Prefetch(16))
+func Prefetch(count int) Option {
+	return func(s *Server) {
+		rmqrpc.QoS(count, 0)(s.conn)
+	}
+}
+
+// Delivery sets the delivery policy, deciding when requests are acknowledged and
+// whether failed ones are dropped, requeued or dead-lettered. New fails with
+// rabbitmq.ErrNoDeadLetter for RejectToDeadLetter without DeadLetter.
+// Default is AckOnReceipt, or RejectToDeadLetter with DeadLetter.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Delivery(server.Requeue), server.MaxDeliveries(5))
+func Delivery(p Policy) Option {
+	return func(s *Server) {
+		if p >= AckOnReceipt && p <= RejectToDeadLetter {
+			s.policy = p
+		}
+	}
+}
+
+// MaxDeliveries sets how many times a failing request is delivered with the Requeue
+// delivery policy before it is rejected. Each delivery calls its handler up to Retries
+// more times.
+// Default is 3.
+func MaxDeliveries(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxDeliveries = n
+		}
+	}
+}
+
+// Handlers registers handlers receiving a context and the decoded Request, alongside
+// the router given to New. A handler of the same name replaces the router's.
+//
+// Example:
+//
+//	server.New(url, exchange, nil, logger, server.Handlers(map[string]server.Handler{
+//	    "greet": func(ctx context.Context, r *server.Request) (interface{}, error) {
+//	        if r.Redelivered && seen(ctx, r.CorrelationID) {
+//	            return cached(ctx, r.CorrelationID)
+//	        }
+//	        return "Hello World", nil
+//	    },
+//	}))
+func Handlers(handlers map[string]Handler) Option {
+	return func(s *Server) {
+		for name, h := range handlers {
+			s.router[name] = h
+		}
+	}
+}
+
+// Use adds middleware applied to every handler of the router, the first being the
+// outermost. Retries call the handler through its middleware again.
+// Default is no middleware.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Use(server.Recover(logger), server.Logger(logger)))
+func Use(mw ...Middleware) Option {
+	return func(s *Server) {
+		s.middleware = append(s.middleware, mw...)
+	}
+}
+
+// Validate adds validators run in order on every request before its handler and
+// middleware. A request failing a validator is not handled nor retried; the client
+// receives the *rabbitmq.ValidationError returned by the validator or, for other
+// errors, a *rabbitmq.BadRequestError with the ErrBadRequest status.
+// Default is no validators.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.Validate(func(_ context.Context, r *server.Request) error {
+//	    return schemas[r.Handler].Validate(r.Body)
+//	}))
+func Validate(validators ...Validator) Option {
+	return func(s *Server) {
+		s.validators = append(s.validators, validators...)
+	}
+}
+
+// WithMetrics sets the hook receiving request measurements.
+// Default is no metrics.
+func WithMetrics(m Metrics) Option {
+	return func(s *Server) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
+
+// WithClock sets the clock driving waits between connection attempts and handler durations,
+// e.g. a *clock.Fake in tests.
+// Default is clock.Real().
+func WithClock(clk clock.Clock) Option {
+	return func(s *Server) {
+		if clk != nil {
+			s.clock = clk
+			s.conn.Clock = clk
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+// Request is an RPC request decoded from its AMQP delivery.
+type Request struct {
+	// Handler is the name of the handler called, the type of the delivery.
+	Handler       string
+	CorrelationID string
+	ReplyTo       string
+	MessageID     string
+	Timestamp     time.Time
+	Headers       amqp.Table
+	Body          []byte
+	// Redelivered reports whether the request was delivered before, e.g. to a server
+	// that failed before acknowledging it.
+	Redelivered bool
+
+	delivery *amqp.Delivery
+	// clock is the clock of the server, timing the request in middleware.
+	clock clock.Clock
+}
+
+func newRequest(d *amqp.Delivery) *Request {
+	return &Request{
+		Handler:       d.Type,
+		CorrelationID: d.CorrelationId,
+		ReplyTo:       d.ReplyTo,
+		MessageID:     d.MessageId,
+		Timestamp:     d.Timestamp,
+		Headers:       d.Headers,
+		Body:          d.Body,
+		Redelivered:   d.Redelivered,
+		delivery:      d,
+	}
+}
+
+// Delivery returns the AMQP delivery of the request, for properties Request does not
+// expose.
+func (r *Request) Delivery() *amqp.Delivery {
+	return r.delivery
+}
+
+// now returns the current time on the clock of the server, the real clock for requests
+// built outside of it.
+func (r *Request) now() time.Time {
+	if r.clock == nil {
+		return time.Now()
+	}
+
+	return r.clock.Now()
+}
+
+// Handler processes an incoming RPC request like CallHandler, with a context carrying
+// the trace context of the request. Register handlers with the Handlers option.
+type Handler func(ctx context.Context, r *Request) (interface{}, error)
+
+// Validator checks a request before its handler, see the Validate option.
+type Validator func(ctx context.Context, r *Request) error
+
+// handler adapts h to Handler.
+func (h CallHandler) handler() Handler {
+	return func(_ context.Context, r *Request) (interface{}, error) {
+		return h(r.delivery)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestNewRequest(t *testing.T) {
+	now := time.Now()
+	d := &amqp.Delivery{
+		Type:          "greet",
+		CorrelationId: "corr-1",
+		ReplyTo:       "client-exchange",
+		MessageId:     "msg-1",
+		Timestamp:     now,
+		Headers:       amqp.Table{"traceparent": "00-abc-def-01"},
+		Body:          []byte(`"world"`),
+		Redelivered:   true,
+	}
+
+	want := &Request{
+		Handler:       "greet",
+		CorrelationID: "corr-1",
+		ReplyTo:       "client-exchange",
+		MessageID:     "msg-1",
+		Timestamp:     now,
+		Headers:       amqp.Table{"traceparent": "00-abc-def-01"},
+		Body:          []byte(`"world"`),
+		Redelivered:   true,
+		delivery:      d,
+	}
+
+	if got := newRequest(d); !reflect.DeepEqual(got, want) {
+		t.Errorf("newRequest() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCallHandler_handler(t *testing.T) {
+	var got *amqp.Delivery
+
+	h := CallHandler(func(d *amqp.Delivery) (interface{}, error) {
+		got = d
+
+		return nil, nil
+	}).handler()
+
+	d := &amqp.Delivery{Type: "greet"}
+
+	if _, err := h(context.Background(), newRequest(d)); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+
+	if got != d {
+		t.Error("CallHandler did not receive the request delivery")
+	}
+}
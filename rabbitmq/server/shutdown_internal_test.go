@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func newStopping() *Server {
+	return &Server{
+		conn:   rmqrpc.New("rpc", rmqrpc.Config{}),
+		stop:   make(chan struct{}),
+		failed: make(chan struct{}),
+	}
+}
+
+func TestServer_ShutdownContextDrains(t *testing.T) {
+	s := newStopping()
+	s.begin()
+	s.begin()
+
+	go func() {
+		<-s.stop
+		s.end()
+		s.end()
+	}()
+
+	stats, err := s.ShutdownContext(context.Background())
+	if err != nil {
+		t.Fatalf("ShutdownContext() error = %v", err)
+	}
+
+	if stats != (rmqrpc.ShutdownStats{Drained: 2}) {
+		t.Errorf("ShutdownContext() stats = %+v, want 2 drained", stats)
+	}
+
+	if s.begin() || s.inflight.Load() != 0 {
+		t.Errorf("begin() after shutdown counted the request, %d in flight", s.inflight.Load())
+	}
+
+	if stats, err := s.ShutdownContext(context.Background()); err != nil || stats != (rmqrpc.ShutdownStats{}) {
+		t.Errorf("second ShutdownContext() = %+v, %v, want no effect", stats, err)
+	}
+}
+
+func TestServer_ShutdownContextAborts(t *testing.T) {
+	s := newStopping()
+	s.begin()
+	s.begin()
+
+	go func() {
+		<-s.stop
+		s.end()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stats, err := s.ShutdownContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ShutdownContext() error = %v, want DeadlineExceeded", err)
+	}
+
+	if stats != (rmqrpc.ShutdownStats{Drained: 1, Aborted: 1}) {
+		t.Errorf("ShutdownContext() stats = %+v, want 1 drained and 1 aborted", stats)
+	}
+
+	s.end()
+}
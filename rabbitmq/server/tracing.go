@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const _instrumentationName = "github.com/rdashevsky/go-pkgs/rabbitmq/server"
+
+// WithTracer traces every request in an OpenTelemetry server span, a child of the
+// client span propagated in the W3C trace context headers of the request. Handlers
+// receive the span in their context. A nil provider is ignored.
+// Default is no tracing.
+//
+// Example:
+//
+//	server.New(url, exchange, router, logger, server.WithTracer(otel.GetTracerProvider()))
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(s *Server) {
+		if tp != nil {
+			s.tracer = tp.Tracer(_instrumentationName)
+		}
+	}
+}
+
+func (s *Server) startSpan(d *amqp.Delivery) (context.Context, trace.Span) {
+	ctx := rmqrpc.ExtractTrace(context.Background(), d.Headers)
+
+	return s.tracer.Start(ctx, "rabbitmq "+d.Type,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", s.conn.ConsumerExchange),
+			attribute.String("messaging.message.conversation_id", d.CorrelationId),
+			attribute.String("rpc.method", d.Type),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	s := &Server{conn: rmqrpc.New("rpc", rmqrpc.Config{})}
+	WithTracer(tp)(s)
+	WithTracer(nil)(s)
+
+	clientCtx, clientSpan := tp.Tracer("client").Start(context.Background(), "call")
+	d := &amqp.Delivery{Type: "greet", CorrelationId: "corr-1", Headers: rmqrpc.InjectTrace(clientCtx, nil)}
+	clientSpan.End()
+
+	ctx, span := s.startSpan(d)
+	if trace.SpanFromContext(ctx) != span {
+		t.Error("handler context does not carry the server span")
+	}
+
+	endSpan(span, errors.New("handler failed"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d ended spans, want 2", len(spans))
+	}
+
+	got := spans[1]
+	if got.Name() != "rabbitmq greet" || got.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %s %v, want rabbitmq greet server", got.Name(), got.SpanKind())
+	}
+
+	if got.Parent().SpanID() != clientSpan.SpanContext().SpanID() {
+		t.Error("server span is not a child of the client span")
+	}
+
+	if got.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error", got.Status())
+	}
+}
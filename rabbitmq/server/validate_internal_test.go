@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rmqrpc "github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestServer_validate(t *testing.T) {
+	var calls int
+
+	pass := func(context.Context, *Request) error {
+		calls++
+
+		return nil
+	}
+	invalid := &rmqrpc.ValidationError{Fields: []rmqrpc.FieldError{{Field: "name", Rule: "required", Message: "name is required"}}}
+
+	tests := []struct {
+		name       string
+		validators []Validator
+		want       error
+		calls      int
+	}{
+		{name: "none"},
+		{name: "valid", validators: []Validator{pass, pass}, calls: 2},
+		{
+			name: "plain error",
+			validators: []Validator{pass, func(context.Context, *Request) error {
+				return errors.New("unknown schema")
+			}, pass},
+			want:  &rmqrpc.BadRequestError{Message: "unknown schema"},
+			calls: 1,
+		},
+		{
+			name: "validation error",
+			validators: []Validator{func(context.Context, *Request) error {
+				return invalid
+			}},
+			want: invalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+
+			s := &Server{}
+			Validate(tt.validators...)(s)
+
+			err := s.validate(context.Background(), &Request{Handler: "greet"})
+
+			switch want := tt.want.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("validate() error = %v, want nil", err)
+				}
+			case *rmqrpc.BadRequestError:
+				var got *rmqrpc.BadRequestError
+				if !errors.As(err, &got) || *got != *want {
+					t.Errorf("validate() error = %v, want %v", err, want)
+				}
+			default:
+				if err != want { //nolint:errorlint // the validator error itself is expected
+					t.Errorf("validate() error = %v, want %v", err, want)
+				}
+			}
+
+			if calls != tt.calls {
+				t.Errorf("validators called %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
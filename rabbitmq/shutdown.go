@@ -0,0 +1,11 @@
+package rabbitmq
+
+// ShutdownStats reports the outcome of the graceful shutdown of the RPC client or
+// server for the requests or calls in flight.
+type ShutdownStats struct {
+	// Drained is the number of requests or calls completed during the shutdown.
+	Drained int
+	// Aborted is the number of requests or calls still in flight when the context of
+	// the shutdown expired.
+	Aborted int
+}
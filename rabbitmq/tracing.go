@@ -0,0 +1,60 @@
+package rabbitmq
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// propagator propagates the W3C trace context and baggage through message headers.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// HeaderCarrier adapts AMQP message headers to an OpenTelemetry TextMapCarrier.
+type HeaderCarrier amqp.Table
+
+var _ propagation.TextMapCarrier = HeaderCarrier(nil)
+
+// Get returns the string value of key, empty when missing or not a string.
+func (h HeaderCarrier) Get(key string) string {
+	v, _ := h[key].(string)
+
+	return v
+}
+
+// Set sets key to value.
+func (h HeaderCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+// Keys returns the keys of the headers.
+func (h HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// InjectTrace writes the W3C trace context and baggage of ctx into headers, which is
+// allocated when nil and returned.
+//
+// Example:
+//
+//	msg.Headers = rabbitmq.InjectTrace(ctx, msg.Headers)
+func InjectTrace(ctx context.Context, headers amqp.Table) amqp.Table {
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+
+	propagator.Inject(ctx, HeaderCarrier(headers))
+
+	return headers
+}
+
+// ExtractTrace returns ctx with the W3C trace context and baggage read from headers,
+// so that spans started from it are children of the publisher's span.
+func ExtractTrace(ctx context.Context, headers amqp.Table) context.Context {
+	return propagator.Extract(ctx, HeaderCarrier(headers))
+}
@@ -0,0 +1,36 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtractTrace(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	headers := rabbitmq.InjectTrace(ctx, nil)
+	if _, ok := headers["traceparent"].(string); !ok {
+		t.Fatalf("headers = %v, want a traceparent", headers)
+	}
+
+	got := trace.SpanContextFromContext(rabbitmq.ExtractTrace(context.Background(), headers))
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() || !got.IsRemote() {
+		t.Errorf("extracted %v, want remote %v", got, span.SpanContext())
+	}
+}
+
+func TestExtractTrace_NoHeaders(t *testing.T) {
+	for _, headers := range []amqp.Table{nil, {"traceparent": 42}} {
+		if sc := trace.SpanContextFromContext(rabbitmq.ExtractTrace(context.Background(), headers)); sc.IsValid() {
+			t.Errorf("ExtractTrace(%v) = %v, want no span context", headers, sc)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package rabbitmq
+
+import "strings"
+
+// FieldError describes an invalid field of a request.
+type FieldError struct {
+	// Field is the path of the field, e.g. "address.city"; empty for the whole request.
+	Field string `json:"field,omitempty"`
+	// Rule is the failed rule, e.g. "type" for a JSON value of the wrong type.
+	Rule string `json:"rule"`
+	// Message is the human readable error message.
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by handlers rejecting a request with invalid fields.
+// The server replies it with the ErrValidation status and the client returns it
+// from the call, so errors.Is(err, ErrValidation) reports validation failures.
+// Being a bad request, it also matches ErrBadRequest.
+type ValidationError struct {
+	Fields []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Message)
+	}
+
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrValidation or ErrBadRequest.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation || target == ErrBadRequest
+}
+
+// BadRequestError rejects a malformed request. The server replies it with the
+// ErrBadRequest status and the client returns it from the call.
+type BadRequestError struct {
+	Message string `json:"message"`
+}
+
+func (e *BadRequestError) Error() string {
+	return ErrBadRequest.Error() + ": " + e.Message
+}
+
+// Is reports whether target is ErrBadRequest.
+func (e *BadRequestError) Is(target error) bool {
+	return target == ErrBadRequest
+}
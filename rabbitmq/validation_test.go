@@ -0,0 +1,39 @@
+package rabbitmq_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/rabbitmq"
+)
+
+func TestValidationError(t *testing.T) {
+	err := &rabbitmq.ValidationError{Fields: []rabbitmq.FieldError{
+		{Field: "name", Rule: "required", Message: "name is required"},
+		{Field: "age", Rule: "type", Message: "age must be int"},
+	}}
+
+	if !errors.Is(err, rabbitmq.ErrValidation) || errors.Is(err, rabbitmq.ErrInternalServer) {
+		t.Errorf("errors.Is(%v) mismatch", err)
+	}
+
+	if want := "validation failed: name is required; age must be int"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestBadRequestError(t *testing.T) {
+	err := &rabbitmq.BadRequestError{Message: "unknown schema"}
+
+	if !errors.Is(err, rabbitmq.ErrBadRequest) || errors.Is(err, rabbitmq.ErrValidation) {
+		t.Errorf("errors.Is(%v) mismatch", err)
+	}
+
+	if want := "bad request: unknown schema"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if !errors.Is(&rabbitmq.ValidationError{}, rabbitmq.ErrBadRequest) {
+		t.Error("ValidationError does not match ErrBadRequest")
+	}
+}
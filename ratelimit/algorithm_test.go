@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newFakeLimiter returns an in-memory limiter driven by the returned clock.
+func newFakeLimiter(alg algorithm, limit Limit) (*limiter, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := newMemoryStore()
+	ms.now = func() time.Time { return now }
+
+	return &limiter{alg: alg, limit: limit, store: ms}, &now
+}
+
+func allow(t *testing.T, l *limiter) Result {
+	t.Helper()
+
+	res, err := l.Allow(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+
+	return res
+}
+
+func TestTokenBucket(t *testing.T) {
+	l, now := newFakeLimiter(tokenBucket{}, Limit{Rate: 10, Period: time.Second, Burst: 3})
+
+	for i := 2; i >= 0; i-- {
+		res := allow(t, l)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("burst event: got %+v, want allowed with %d remaining", res, i)
+		}
+	}
+
+	res := allow(t, l)
+	if res.Allowed || res.RetryAfter != 100*time.Millisecond {
+		t.Fatalf("exhausted bucket: got %+v, want rejected with 100ms retry", res)
+	}
+
+	*now = now.Add(100 * time.Millisecond)
+
+	if res := allow(t, l); !res.Allowed {
+		t.Fatalf("after refill: got %+v, want allowed", res)
+	}
+
+	*now = now.Add(time.Hour)
+
+	if res := allow(t, l); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("after idle: got %+v, want bucket capped at burst", res)
+	}
+}
+
+func TestLeakyBucket(t *testing.T) {
+	l, now := newFakeLimiter(leakyBucket{}, Limit{Rate: 10, Period: time.Second, Burst: 3})
+
+	for i, delay := range []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		res := allow(t, l)
+		if !res.Allowed || res.Delay != delay || res.Remaining != 2-i {
+			t.Fatalf("event %d: got %+v, want allowed with %s delay", i, res, delay)
+		}
+	}
+
+	res := allow(t, l)
+	if res.Allowed || res.RetryAfter != 100*time.Millisecond {
+		t.Fatalf("full bucket: got %+v, want rejected with 100ms retry", res)
+	}
+
+	*now = now.Add(100 * time.Millisecond)
+
+	if res := allow(t, l); !res.Allowed || res.Delay != 200*time.Millisecond {
+		t.Fatalf("after leak: got %+v, want allowed with 200ms delay", res)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	l, now := newFakeLimiter(slidingWindow{}, PerSecond(4))
+
+	for i := 3; i >= 0; i-- {
+		if res := allow(t, l); !res.Allowed || res.Remaining != i {
+			t.Fatalf("got %+v, want allowed with %d remaining", res, i)
+		}
+	}
+
+	res := allow(t, l)
+	if res.Allowed || res.RetryAfter != 1250*time.Millisecond {
+		t.Fatalf("full window: got %+v, want rejected with 1.25s retry", res)
+	}
+
+	// Half way through the next window, the previous one still weighs 2 events.
+	*now = now.Add(1500 * time.Millisecond)
+
+	for i := 1; i >= 0; i-- {
+		if res := allow(t, l); !res.Allowed || res.Remaining != i {
+			t.Fatalf("next window: got %+v, want allowed with %d remaining", res, i)
+		}
+	}
+
+	res = allow(t, l)
+	if res.Allowed || res.RetryAfter != 250*time.Millisecond {
+		t.Fatalf("next window full: got %+v, want rejected with 250ms retry", res)
+	}
+
+	// Windows that are not adjacent do not count.
+	*now = now.Add(2 * time.Second)
+
+	if res := allow(t, l); !res.Allowed || res.Remaining != 3 {
+		t.Fatalf("after idle: got %+v, want allowed with 3 remaining", res)
+	}
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	l, now := newFakeLimiter(tokenBucket{}, PerSecond(1))
+	ms := l.store.(*memoryStore)
+
+	allow(t, l)
+
+	*now = now.Add(2 * _sweepInterval)
+
+	if _, err := l.Allow(context.Background(), "other"); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+
+	if _, ok := ms.entries["key"]; ok {
+		t.Error("idle key was not swept")
+	}
+
+	if len(ms.entries) != 1 {
+		t.Errorf("got %d entries, want 1", len(ms.entries))
+	}
+}
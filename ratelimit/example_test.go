@@ -0,0 +1,56 @@
+package ratelimit_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+)
+
+func ExampleNewTokenBucket() {
+	limiter := ratelimit.NewTokenBucket(ratelimit.Limit{Rate: 1, Period: time.Minute, Burst: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, _ := limiter.Allow(ctx, "user:42")
+		fmt.Println(res.Allowed, res.Remaining)
+	}
+	// Output:
+	// true 1
+	// true 0
+	// false 0
+}
+
+func ExampleNewLeakyBucket() {
+	limiter := ratelimit.NewLeakyBucket(ratelimit.Limit{Rate: 10, Period: time.Second, Burst: 3})
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		res, _ := limiter.Allow(ctx, "smtp")
+		fmt.Println(res.Allowed, res.Delay.Round(10*time.Millisecond))
+	}
+	// Output:
+	// true 0s
+	// true 100ms
+	// true 200ms
+	// false 0s
+}
+
+func ExampleWait() {
+	limiter := ratelimit.NewSlidingWindow(ratelimit.PerSecond(100))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 10; i++ {
+		if err := ratelimit.Wait(ctx, limiter, "worker"); err != nil {
+			fmt.Println(err)
+
+			return
+		}
+	}
+
+	fmt.Println("done")
+	// Output: done
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type leakyBucket struct{}
+
+// NewLeakyBucket returns a Limiter shaping events to a constant rate of Rate per Period:
+// events are queued in a bucket of Burst slots leaking one event every Period/Rate.
+// Allowed events carry the Delay to wait before proceeding; events finding the bucket
+// full are rejected. Unlike the token bucket, it never lets bursts through.
+//
+// Example:
+//
+//	limiter := ratelimit.NewLeakyBucket(ratelimit.Limit{Rate: 10, Period: time.Second, Burst: 50})
+//	res, err := limiter.Allow(ctx, "smtp")
+//	if err == nil && res.Allowed {
+//	    time.Sleep(res.Delay)
+//	    send()
+//	}
+func NewLeakyBucket(limit Limit, opts ...Option) Limiter {
+	return newLimiter(leakyBucket{}, limit, opts)
+}
+
+func (leakyBucket) allow(st *state, now time.Time, limit Limit) Result {
+	interval := limit.interval()
+	// maxDelay is the delay of the last slot of the bucket.
+	maxDelay := time.Duration(limit.burst()-1) * interval
+
+	slot := st.next
+	if slot.Before(now) {
+		slot = now
+	}
+
+	delay := slot.Sub(now)
+	if delay > maxDelay {
+		return Result{Limit: limit.Rate, RetryAfter: delay - maxDelay}
+	}
+
+	st.next = slot.Add(interval)
+
+	return Result{
+		Allowed:   true,
+		Limit:     limit.Rate,
+		Remaining: int((maxDelay - delay) / interval),
+		Delay:     delay,
+	}
+}
+
+func (leakyBucket) ttl(limit Limit) time.Duration {
+	return time.Duration(limit.burst()) * limit.interval()
+}
+
+var leakyBucketScript = goredis.NewScript(`
+local t = redis.call("TIME")
+local now = t[1] * 1000 + t[2] / 1000
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+
+local interval = period / rate
+local max_delay = (burst - 1) * interval
+
+local slot = tonumber(redis.call("GET", KEYS[1])) or now
+if slot < now then
+	slot = now
+end
+
+local delay = slot - now
+if delay > max_delay then
+	return {0, 0, math.ceil(delay - max_delay), 0}
+end
+
+redis.call("SET", KEYS[1], tostring(slot + interval), "PX", math.ceil(delay + interval) + 1000)
+
+return {1, math.floor((max_delay - delay) / interval), 0, math.floor(delay)}
+`)
+
+func (leakyBucket) script() scriptRunner {
+	return leakyBucketScript
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import "github.com/rdashevsky/go-pkgs/redis"
+
+// Option configures a Limiter.
+type Option func(*config)
+
+type config struct {
+	redis  *redis.Redis
+	prefix string
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{prefix: _defaultPrefix}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Redis stores the limiter state in Redis, sharing limits across instances.
+// Decisions are made atomically by Lua scripts using the Redis server clock.
+// Default is in-memory state, local to the process.
+func Redis(r *redis.Redis) Option {
+	return func(c *config) {
+		c.redis = r
+	}
+}
+
+// Prefix sets the prefix of the Redis keys.
+// Default is "ratelimit:".
+func Prefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
@@ -0,0 +1,162 @@
+// Package ratelimit implements token bucket, leaky bucket and sliding window rate
+// limiting behind a single Limiter interface, with in-memory and Redis backends.
+// It powers the httpserver RateLimit middleware and the grpcserver RateLimit
+// interceptors, and Wait throttles background workers.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const _defaultPrefix = "ratelimit:"
+
+// Limit is an allowed rate of Rate events per Period.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	// Burst is the number of events allowed at once by the token bucket and the number
+	// of events queued by the leaky bucket. Zero means Rate. The sliding window ignores it.
+	Burst int
+}
+
+// PerSecond returns a limit of n events per second.
+func PerSecond(n int) Limit {
+	return Limit{Rate: n, Period: time.Second}
+}
+
+// PerMinute returns a limit of n events per minute.
+func PerMinute(n int) Limit {
+	return Limit{Rate: n, Period: time.Minute}
+}
+
+// PerHour returns a limit of n events per hour.
+func PerHour(n int) Limit {
+	return Limit{Rate: n, Period: time.Hour}
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Rate
+}
+
+// interval returns the time between two events at the limit rate.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result is the outcome of an Allow call.
+type Result struct {
+	// Allowed reports whether the event may proceed.
+	Allowed bool
+	// Limit is the configured rate.
+	Limit int
+	// Remaining is the number of events still allowed right now.
+	Remaining int
+	// RetryAfter is the time to wait before retrying a rejected event.
+	RetryAfter time.Duration
+	// Delay is the time an allowed event must wait before proceeding.
+	// Only the leaky bucket, which shapes traffic to a constant rate, sets it.
+	Delay time.Duration
+}
+
+// Limiter decides whether events identified by a key are within their rate limit.
+type Limiter interface {
+	// Allow records an event for key and reports whether it is allowed.
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Wait blocks until an event for key is allowed, including its leaky bucket delay,
+// or ctx is done. It throttles background workers to the limiter rate.
+//
+// Example:
+//
+//	limiter := ratelimit.NewTokenBucket(ratelimit.PerSecond(50))
+//	for _, job := range jobs {
+//	    if err := ratelimit.Wait(ctx, limiter, "partner-api"); err != nil {
+//	        return err
+//	    }
+//	    call(job)
+//	}
+func Wait(ctx context.Context, l Limiter, key string) error {
+	for {
+		res, err := l.Allow(ctx, key)
+		if err != nil {
+			return fmt.Errorf("ratelimit - Wait - l.Allow: %w", err)
+		}
+
+		wait := res.RetryAfter
+		if res.Allowed {
+			wait = res.Delay
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return fmt.Errorf("ratelimit - Wait - %w", ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		if res.Allowed {
+			return nil
+		}
+	}
+}
+
+// state is the per key state of every algorithm.
+type state struct {
+	// Token bucket.
+	tokens float64
+	last   time.Time
+	// Leaky bucket: the time the next event may proceed.
+	next time.Time
+	// Sliding window.
+	windowStart time.Time
+	current     int
+	previous    int
+}
+
+// algorithm computes a decision in memory and provides the equivalent Redis script.
+type algorithm interface {
+	allow(st *state, now time.Time, limit Limit) Result
+	// ttl is how long an idle key must be kept for its state to matter.
+	ttl(limit Limit) time.Duration
+	script() scriptRunner
+}
+
+type limiter struct {
+	alg   algorithm
+	limit Limit
+	store store
+}
+
+func newLimiter(alg algorithm, limit Limit, opts []Option) Limiter {
+	cfg := newConfig(opts)
+
+	l := &limiter{alg: alg, limit: limit}
+
+	if cfg.redis != nil {
+		l.store = newRedisStore(cfg.redis, cfg.prefix, alg.script())
+	} else {
+		l.store = newMemoryStore()
+	}
+
+	return l
+}
+
+func (l *limiter) Allow(ctx context.Context, key string) (Result, error) {
+	if l.limit.Rate <= 0 || l.limit.Period <= 0 {
+		return Result{}, fmt.Errorf("ratelimit - Allow - invalid limit %d per %s", l.limit.Rate, l.limit.Period)
+	}
+
+	return l.store.allow(ctx, key, l.alg, l.limit)
+}
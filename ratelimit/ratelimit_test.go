@@ -0,0 +1,135 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/ratelimit"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+func TestLimiters_Concurrent(t *testing.T) {
+	limiters := map[string]ratelimit.Limiter{
+		"token bucket":   ratelimit.NewTokenBucket(ratelimit.PerMinute(50)),
+		"leaky bucket":   ratelimit.NewLeakyBucket(ratelimit.Limit{Rate: 1, Period: time.Minute, Burst: 50}),
+		"sliding window": ratelimit.NewSlidingWindow(ratelimit.PerMinute(50)),
+	}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				allowed int
+			)
+
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					res, err := l.Allow(context.Background(), "key")
+					if err != nil {
+						t.Errorf("Allow() error = %v", err)
+
+						return
+					}
+
+					if res.Allowed {
+						mu.Lock()
+						allowed++
+						mu.Unlock()
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			if allowed != 50 {
+				t.Errorf("got %d allowed events, want 50", allowed)
+			}
+		})
+	}
+}
+
+func TestLimiter_KeysAreIndependent(t *testing.T) {
+	l := ratelimit.NewTokenBucket(ratelimit.PerHour(1))
+
+	for _, key := range []string{"a", "b"} {
+		res, err := l.Allow(context.Background(), key)
+		if err != nil || !res.Allowed {
+			t.Fatalf("Allow(%q) = %+v, %v; want allowed", key, res, err)
+		}
+	}
+
+	res, _ := l.Allow(context.Background(), "a")
+	if res.Allowed {
+		t.Error("second event for key a was allowed")
+	}
+}
+
+func TestLimiter_InvalidLimit(t *testing.T) {
+	l := ratelimit.NewSlidingWindow(ratelimit.Limit{})
+
+	if _, err := l.Allow(context.Background(), "key"); err == nil {
+		t.Error("expected error for zero limit")
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := ratelimit.NewTokenBucket(ratelimit.Limit{Rate: 20, Period: time.Second, Burst: 1})
+	ctx := context.Background()
+
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := ratelimit.Wait(ctx, l, "key"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 events at 20/s took %s, want at least 100ms", elapsed)
+	}
+}
+
+func TestWait_ContextCanceled(t *testing.T) {
+	l := ratelimit.NewTokenBucket(ratelimit.PerHour(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_ = ratelimit.Wait(ctx, l, "key")
+
+	if err := ratelimit.Wait(ctx, l, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRedis_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("redis.New() error = %v", err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	limiters := map[string]ratelimit.Limiter{
+		"token bucket":   ratelimit.NewTokenBucket(ratelimit.PerSecond(1), ratelimit.Redis(r)),
+		"leaky bucket":   ratelimit.NewLeakyBucket(ratelimit.PerSecond(1), ratelimit.Redis(r)),
+		"sliding window": ratelimit.NewSlidingWindow(ratelimit.PerSecond(1), ratelimit.Redis(r)),
+	}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			if _, err := l.Allow(ctx, "key"); err == nil {
+				t.Skip("unexpected successful connection to Redis")
+			}
+		})
+	}
+}
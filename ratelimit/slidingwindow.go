@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type slidingWindow struct{}
+
+// NewSlidingWindow returns a Limiter allowing Rate events in any window of Period,
+// approximated from the counts of the current and previous fixed windows, the latter
+// weighted by its overlap with the sliding window. It avoids the double bursts of fixed
+// windows at their boundaries with two counters per key.
+//
+// Example:
+//
+//	limiter := ratelimit.NewSlidingWindow(ratelimit.PerHour(1000), ratelimit.Redis(r))
+func NewSlidingWindow(limit Limit, opts ...Option) Limiter {
+	return newLimiter(slidingWindow{}, limit, opts)
+}
+
+func (slidingWindow) allow(st *state, now time.Time, limit Limit) Result {
+	start := now.Truncate(limit.Period)
+
+	if !start.Equal(st.windowStart) {
+		if start.Sub(st.windowStart) == limit.Period {
+			st.previous = st.current
+		} else {
+			st.previous = 0
+		}
+
+		st.current = 0
+		st.windowStart = start
+	}
+
+	elapsed := now.Sub(start)
+	rate := float64(limit.Rate)
+	period := float64(limit.Period)
+	estimate := float64(st.previous)*(period-float64(elapsed))/period + float64(st.current)
+
+	if estimate+1 > rate {
+		return Result{
+			Limit:      limit.Rate,
+			RetryAfter: slidingWindowRetry(st.previous, st.current, rate, period, float64(elapsed)),
+		}
+	}
+
+	st.current++
+
+	return Result{Allowed: true, Limit: limit.Rate, Remaining: int(rate - estimate - 1)}
+}
+
+// slidingWindowRetry returns the time until the estimate leaves room for one event.
+func slidingWindowRetry(previous, current int, rate, period, elapsed float64) time.Duration {
+	if float64(current)+1 <= rate {
+		// The previous window weight decays enough within the current window.
+		return time.Duration(math.Ceil(period - elapsed - (rate-float64(current)-1)*period/float64(previous)))
+	}
+
+	// The current window becomes the previous one and must decay in the next window.
+	return time.Duration(math.Ceil(period - elapsed + period*(1-(rate-1)/float64(current))))
+}
+
+func (slidingWindow) ttl(limit Limit) time.Duration {
+	return 2 * limit.Period
+}
+
+var slidingWindowScript = goredis.NewScript(`
+local t = redis.call("TIME")
+local now = t[1] * 1000 + t[2] / 1000
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+
+local start = math.floor(now / period) * period
+local st = redis.call("HMGET", KEYS[1], "start", "current", "previous")
+local current = tonumber(st[2]) or 0
+local previous = tonumber(st[3]) or 0
+local last_start = tonumber(st[1])
+
+if last_start ~= start then
+	if last_start ~= nil and start - last_start == period then
+		previous = current
+	else
+		previous = 0
+	end
+	current = 0
+end
+
+local elapsed = now - start
+local estimate = previous * (period - elapsed) / period + current
+
+if estimate + 1 > rate then
+	local retry
+	if current + 1 <= rate then
+		retry = period - elapsed - (rate - current - 1) * period / previous
+	else
+		retry = period - elapsed + period * (1 - (rate - 1) / current)
+	end
+	return {0, 0, math.ceil(retry), 0}
+end
+
+current = current + 1
+redis.call("HSET", KEYS[1], "start", tostring(start), "current", current, "previous", previous)
+redis.call("PEXPIRE", KEYS[1], 2 * period)
+
+return {1, math.floor(rate - estimate - 1), 0, 0}
+`)
+
+func (slidingWindow) script() scriptRunner {
+	return slidingWindowScript
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// _sweepInterval is how often idle keys are evicted from memory.
+const _sweepInterval = time.Minute
+
+type store interface {
+	allow(ctx context.Context, key string, alg algorithm, limit Limit) (Result, error)
+}
+
+type memoryEntry struct {
+	state
+	expiresAt time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *memoryStore) allow(_ context.Context, key string, alg algorithm, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now.Sub(s.lastSweep) >= _sweepInterval {
+		for k, e := range s.entries {
+			if now.After(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+
+		s.lastSweep = now
+	}
+
+	e, ok := s.entries[key]
+	if !ok {
+		e = &memoryEntry{}
+		s.entries[key] = e
+	}
+
+	res := alg.allow(&e.state, now, limit)
+	e.expiresAt = now.Add(alg.ttl(limit) + res.Delay)
+
+	return res, nil
+}
+
+type scriptRunner interface {
+	Run(ctx context.Context, c goredis.Scripter, keys []string, args ...interface{}) *goredis.Cmd
+}
+
+type redisStore struct {
+	client goredis.UniversalClient
+	prefix string
+	script scriptRunner
+}
+
+func newRedisStore(r *redis.Redis, prefix string, script scriptRunner) *redisStore {
+	return &redisStore{client: r.Client(), prefix: prefix, script: script}
+}
+
+func (s *redisStore) allow(ctx context.Context, key string, _ algorithm, limit Limit) (Result, error) {
+	values, err := s.script.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Rate, limit.Period.Milliseconds(), limit.burst()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit - Redis - allow - script.Run: %w", err)
+	}
+
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("ratelimit - Redis - allow - unexpected script result %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Rate,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Delay:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type tokenBucket struct{}
+
+// NewTokenBucket returns a Limiter refilling Rate tokens per Period into a bucket of
+// Burst tokens; each event takes a token. It allows bursts of up to Burst events
+// while enforcing the average rate.
+//
+// Example:
+//
+//	limiter := ratelimit.NewTokenBucket(ratelimit.Limit{Rate: 100, Period: time.Minute, Burst: 20},
+//	    ratelimit.Redis(r),
+//	)
+func NewTokenBucket(limit Limit, opts ...Option) Limiter {
+	return newLimiter(tokenBucket{}, limit, opts)
+}
+
+func (tokenBucket) allow(st *state, now time.Time, limit Limit) Result {
+	burst := float64(limit.burst())
+	perNanosecond := float64(limit.Rate) / float64(limit.Period)
+
+	if st.last.IsZero() {
+		st.tokens = burst
+	} else {
+		st.tokens = math.Min(burst, st.tokens+float64(now.Sub(st.last))*perNanosecond)
+	}
+
+	st.last = now
+
+	if st.tokens < 1 {
+		return Result{
+			Limit:      limit.Rate,
+			RetryAfter: time.Duration(math.Ceil((1 - st.tokens) / perNanosecond)),
+		}
+	}
+
+	st.tokens--
+
+	return Result{Allowed: true, Limit: limit.Rate, Remaining: int(st.tokens)}
+}
+
+func (tokenBucket) ttl(limit Limit) time.Duration {
+	return time.Duration(limit.burst()) * limit.interval()
+}
+
+var tokenBucketScript = goredis.NewScript(`
+local t = redis.call("TIME")
+local now = t[1] * 1000 + t[2] / 1000
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+
+local st = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(st[1])
+local last = tonumber(st[2])
+if tokens == nil or last == nil then
+	tokens = burst
+else
+	tokens = math.min(burst, tokens + math.max(0, now - last) * rate / period)
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * period / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * period / rate) + 1000)
+
+return {allowed, math.floor(tokens), retry, 0}
+`)
+
+func (tokenBucket) script() scriptRunner {
+	return tokenBucketScript
+}
@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Codec encodes cached values to bytes and back.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type codec struct {
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+func (c codec) Marshal(v any) ([]byte, error) { return c.marshal(v) }
+
+func (c codec) Unmarshal(data []byte, v any) error { return c.unmarshal(data, v) }
+
+// JSONCodec encodes values with encoding/json.
+var JSONCodec Codec = codec{marshal: json.Marshal, unmarshal: json.Unmarshal}
+
+// NewCodec returns a Codec from a pair of marshal and unmarshal functions, e.g. to
+// store values as MessagePack without this package depending on it:
+//
+//	redis.NewCodec(msgpack.Marshal, msgpack.Unmarshal)
+func NewCodec(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) Codec {
+	return codec{marshal: marshal, unmarshal: unmarshal}
+}
+
+// CacheOption configures a Cache.
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	codec  Codec
+	prefix string
+	ttl    time.Duration
+	hasTTL bool
+}
+
+// CacheCodec sets the codec encoding values.
+// Default is JSONCodec.
+func CacheCodec(c Codec) CacheOption {
+	return func(cfg *cacheConfig) {
+		if c != nil {
+			cfg.codec = c
+		}
+	}
+}
+
+// CachePrefix sets the prefix added to every key, to keep the keys of different
+// types apart, e.g. "user:".
+// Default is no prefix.
+func CachePrefix(prefix string) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.prefix = prefix
+	}
+}
+
+// CacheTTL sets the TTL of values stored with Set.
+// Default is the TTL of the Redis client.
+func CacheTTL(ttl time.Duration) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.ttl = ttl
+		cfg.hasTTL = true
+	}
+}
+
+// Cache stores values of type T in Redis, encoded by a Codec.
+// It is safe for concurrent use. For stampede protection, stale-while-revalidate
+// or layered stores, use the cache package.
+type Cache[T any] struct {
+	r      *Redis
+	codec  Codec
+	prefix string
+	ttl    time.Duration
+}
+
+// NewCache creates a Cache of values of type T stored with r.
+//
+// Example:
+//
+//	users := redis.NewCache[User](r, redis.CachePrefix("user:"), redis.CacheTTL(10*time.Minute))
+//
+//	if err := users.Set(ctx, id, user); err != nil {
+//	    return err
+//	}
+//
+//	user, ok, err := users.Get(ctx, id)
+func NewCache[T any](r *Redis, opts ...CacheOption) *Cache[T] {
+	cfg := cacheConfig{codec: JSONCodec}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if !cfg.hasTTL {
+		cfg.ttl = r.ttl
+	}
+
+	return &Cache[T]{
+		r:      r,
+		codec:  cfg.codec,
+		prefix: cfg.prefix,
+		ttl:    cfg.ttl,
+	}
+}
+
+// Get returns the value stored under key.
+// Returns the zero value and false if the key doesn't exist.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var v T
+
+	data, err := c.r.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return v, false, nil
+	} else if err != nil {
+		return v, false, fmt.Errorf("redis - Cache - Get: %w", err)
+	}
+
+	if err := c.codec.Unmarshal(data, &v); err != nil {
+		return v, false, fmt.Errorf("redis - Cache - Get - c.codec.Unmarshal: %w", err)
+	}
+
+	return v, true, nil
+}
+
+// Set stores value under key with the cache TTL.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	return c.SetWithTTL(ctx, key, value, c.ttl)
+}
+
+// SetWithTTL stores value under key with a custom TTL.
+func (c *Cache[T]) SetWithTTL(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("redis - Cache - SetWithTTL - c.codec.Marshal: %w", err)
+	}
+
+	if err := c.r.client.Set(ctx, c.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis - Cache - SetWithTTL: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the key. Deleting a missing key is not an error.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	if err := c.r.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		return fmt.Errorf("redis - Cache - Delete: %w", err)
+	}
+
+	return nil
+}
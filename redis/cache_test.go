@@ -0,0 +1,89 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+type cachedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCache_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	users := redis.NewCache[cachedUser](client)
+
+	if err := users.Set(ctx, "1", cachedUser{ID: 1}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, _, err := users.Get(ctx, "1"); err == nil {
+		t.Error("expected Get to fail without connection")
+	}
+
+	if err := users.Delete(ctx, "1"); err == nil {
+		t.Error("expected Delete to fail without connection")
+	}
+}
+
+func TestCache_MarshalError(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	errCodec := errors.New("codec")
+	codec := redis.NewCodec(func(any) ([]byte, error) { return nil, errCodec }, json.Unmarshal)
+
+	users := redis.NewCache[cachedUser](client, redis.CacheCodec(codec))
+	if err := users.Set(context.Background(), "1", cachedUser{}); !errors.Is(err, errCodec) {
+		t.Errorf("Set() error = %v, want %v", err, errCodec)
+	}
+}
+
+func TestCache_Integration(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+
+	users := redis.NewCache[cachedUser](client, redis.CachePrefix("test-cache:"), redis.CacheTTL(time.Minute))
+
+	if _, ok, err := users.Get(ctx, "1"); err != nil || ok {
+		t.Errorf("Get() missing = %v, %v, want false", ok, err)
+	}
+
+	want := cachedUser{ID: 1, Name: "Ann"}
+	if err := users.Set(ctx, "1", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if raw, err := client.Get(ctx, "test-cache:1"); err != nil || raw != `{"id":1,"name":"Ann"}` {
+		t.Errorf("raw value = %q, %v", raw, err)
+	}
+
+	if got, ok, err := users.Get(ctx, "1"); err != nil || !ok || got != want {
+		t.Errorf("Get() = %+v, %v, %v, want %+v", got, ok, err, want)
+	}
+
+	if err := users.Delete(ctx, "1"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	if _, ok, err := users.Get(ctx, "1"); err != nil || ok {
+		t.Errorf("Get() after Delete = %v, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewCluster creates a Redis client for a Redis Cluster reachable through the given seed
+// addresses, with the same API and options as New. The cluster topology is discovered
+// from the seeds and commands are routed to the node owning their key, following MOVED
+// and ASK redirections during resharding and failover.
+//
+// Commands with several keys, such as Del with several keys, BRPop on several lists,
+// require all keys to map to the same hash slot. Use hash
+// tags, e.g. "{user:42}:profile" and "{user:42}:sessions", to group keys.
+//
+// Example:
+//
+//	client, err := redis.NewCluster(
+//	    []string{"redis-0:6379", "redis-1:6379", "redis-2:6379"}, "", "",
+//	    redis.TTL(5 * time.Minute),
+//	)
+func NewCluster(addresses []string, user string, password string, opts ...Options) (*Redis, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("redis - NewCluster: no addresses")
+	}
+
+	r := newRedis(opts)
+
+	r.conn.Addrs = addresses
+	r.conn.Username = user
+	r.conn.Password = password
+
+	err := r.setClient(redis.NewClusterClient(r.conn.Cluster()))
+	if err != nil {
+		return nil, fmt.Errorf("redis - NewCluster - %w", err)
+	}
+
+	return r, nil
+}
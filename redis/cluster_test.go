@@ -0,0 +1,29 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestNewCluster(t *testing.T) {
+	if _, err := redis.NewCluster(nil, "", ""); err == nil {
+		t.Error("expected NewCluster to fail without addresses")
+	}
+
+	client, err := redis.NewCluster([]string{"127.0.0.1:65432", "127.0.0.1:65433"}, "", "")
+	if err != nil {
+		t.Fatalf("NewCluster() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, ok := client.Client().(*goredis.ClusterClient); !ok {
+		t.Errorf("Client() = %T, want *redis.ClusterClient", client.Client())
+	}
+
+	if err := client.Set(context.Background(), "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to Redis Cluster")
+	}
+}
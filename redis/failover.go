@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewFailover creates a Redis client for a master monitored by Redis Sentinel, with the
+// same API and options as New. The current master of masterName is discovered through
+// the sentinels and the client reconnects to the new master after a failover.
+// The credentials are those of the Redis nodes, not of the sentinels.
+//
+// Example:
+//
+//	client, err := redis.NewFailover("mymaster",
+//	    []string{"sentinel-0:26379", "sentinel-1:26379", "sentinel-2:26379"}, "", "secret",
+//	    redis.TTL(5 * time.Minute),
+//	)
+func NewFailover(masterName string, sentinelAddrs []string, user string, password string, opts ...Options) (*Redis, error) {
+	if masterName == "" {
+		return nil, errors.New("redis - NewFailover: no master name")
+	}
+
+	if len(sentinelAddrs) == 0 {
+		return nil, errors.New("redis - NewFailover: no sentinel addresses")
+	}
+
+	r := newRedis(opts)
+
+	r.conn.MasterName = masterName
+	r.conn.Addrs = sentinelAddrs
+	r.conn.Username = user
+	r.conn.Password = password
+
+	err := r.setClient(redis.NewFailoverClient(r.conn.Failover()))
+	if err != nil {
+		return nil, fmt.Errorf("redis - NewFailover - %w", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,28 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+func TestNewFailover(t *testing.T) {
+	if _, err := redis.NewFailover("", []string{"127.0.0.1:65432"}, "", ""); err == nil {
+		t.Error("expected NewFailover to fail without master name")
+	}
+
+	if _, err := redis.NewFailover("mymaster", nil, "", ""); err == nil {
+		t.Error("expected NewFailover to fail without sentinel addresses")
+	}
+
+	client, err := redis.NewFailover("mymaster", []string{"127.0.0.1:65432"}, "", "")
+	if err != nil {
+		t.Fatalf("NewFailover() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Set(context.Background(), "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to Redis Sentinel")
+	}
+}
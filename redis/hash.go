@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// HSet sets fields of the hash at key, creating it if needed, and returns the number
+// of fields added rather than updated. Values are strings, numbers, booleans or types
+// implementing encoding.BinaryMarshaler.
+func (r *Redis) HSet(ctx context.Context, key string, fields map[string]any) (int64, error) {
+	n, err := r.client.HSet(ctx, key, fields).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - HSet: %w", err)
+	}
+
+	return n, nil
+}
+
+// HGet returns the value of field in the hash at key.
+// Returns empty string and nil error if the key or the field doesn't exist.
+func (r *Redis) HGet(ctx context.Context, key, field string) (string, error) {
+	val, err := r.client.HGet(ctx, key, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("redis - HGet: %w", err)
+	}
+
+	return val, nil
+}
+
+// HGetAll returns every field of the hash at key, an empty map if it doesn't exist.
+func (r *Redis) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	fields, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis - HGetAll: %w", err)
+	}
+
+	return fields, nil
+}
+
+// HDel deletes fields of the hash at key and returns how many existed.
+func (r *Redis) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	n, err := r.client.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - HDel: %w", err)
+	}
+
+	return n, nil
+}
+
+// HIncrBy adds incr to the integer in field of the hash at key, starting from zero,
+// and returns the new value.
+func (r *Redis) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	n, err := r.client.HIncrBy(ctx, key, field, incr).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - HIncrBy: %w", err)
+	}
+
+	return n, nil
+}
+
+// HSetStruct stores the fields of v, a struct or a pointer to one, in the hash at key.
+// Fields are named by their "redis" tag, e.g. `redis:"email"`; untagged fields are
+// skipped, as are empty ones tagged with omitempty.
+//
+// Example:
+//
+//	type Session struct {
+//	    UserID    int64  `redis:"user_id"`
+//	    Role      string `redis:"role"`
+//	    UserAgent string `redis:"user_agent,omitempty"`
+//	}
+//
+//	err := r.HSetStruct(ctx, "session:"+id, Session{UserID: 42, Role: "admin"})
+func (r *Redis) HSetStruct(ctx context.Context, key string, v any) error {
+	if err := r.client.HSet(ctx, key, v).Err(); err != nil {
+		return fmt.Errorf("redis - HSetStruct: %w", err)
+	}
+
+	return nil
+}
+
+// HGetStruct reads the hash at key into dest, a pointer to a struct with fields named
+// like for HSetStruct. It reports false, leaving dest unchanged, when the key doesn't exist.
+//
+// Example:
+//
+//	var s Session
+//	found, err := r.HGetStruct(ctx, "session:"+id, &s)
+func (r *Redis) HGetStruct(ctx context.Context, key string, dest any) (bool, error) {
+	cmd := r.client.HGetAll(ctx, key)
+	if err := cmd.Err(); err != nil {
+		return false, fmt.Errorf("redis - HGetStruct: %w", err)
+	}
+
+	if len(cmd.Val()) == 0 {
+		return false, nil
+	}
+
+	if err := cmd.Scan(dest); err != nil {
+		return false, fmt.Errorf("redis - HGetStruct - Scan: %w", err)
+	}
+
+	return true, nil
+}
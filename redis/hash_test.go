@@ -0,0 +1,81 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+type session struct {
+	UserID    int64  `redis:"user_id"`
+	Role      string `redis:"role"`
+	UserAgent string `redis:"user_agent,omitempty"`
+}
+
+func TestRedis_Hash_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if _, err := client.HSet(ctx, "hash", map[string]any{"field": "value"}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.HGet(ctx, "hash", "field"); err == nil {
+		t.Error("expected HGet to fail without connection")
+	}
+
+	if err := client.HSetStruct(ctx, "hash", session{UserID: 1}); err == nil {
+		t.Error("expected HSetStruct to fail without connection")
+	}
+
+	if _, err := client.HGetStruct(ctx, "hash", &session{}); err == nil {
+		t.Error("expected HGetStruct to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationHash(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	key := "test-hash-key"
+
+	if n, err := client.HSet(ctx, key, map[string]any{"name": "alice", "visits": 1}); err != nil || n != 2 {
+		t.Errorf("HSet() = %d, %v, want 2", n, err)
+	}
+
+	if n, err := client.HIncrBy(ctx, key, "visits", 2); err != nil || n != 3 {
+		t.Errorf("HIncrBy() = %d, %v, want 3", n, err)
+	}
+
+	if v, err := client.HGet(ctx, key, "missing"); err != nil || v != "" {
+		t.Errorf("HGet() = %q, %v, want empty", v, err)
+	}
+
+	if n, err := client.HDel(ctx, key, "visits"); err != nil || n != 1 {
+		t.Errorf("HDel() = %d, %v, want 1", n, err)
+	}
+
+	if all, err := client.HGetAll(ctx, key); err != nil || len(all) != 1 || all["name"] != "alice" {
+		t.Errorf("HGetAll() = %v, %v, want name only", all, err)
+	}
+
+	if err := client.HSetStruct(ctx, key, session{UserID: 42, Role: "admin"}); err != nil {
+		t.Fatalf("HSetStruct() error = %v", err)
+	}
+
+	var s session
+	if found, err := client.HGetStruct(ctx, key, &s); err != nil || !found || s.UserID != 42 || s.Role != "admin" {
+		t.Errorf("HGetStruct() = %+v, %v, %v", s, found, err)
+	}
+
+	if found, err := client.HGetStruct(ctx, "test-hash-missing", &s); err != nil || found {
+		t.Errorf("HGetStruct() = %v, %v, want not found", found, err)
+	}
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Status describes the client at the time of a health check.
+type Status struct {
+	// Latency is the duration of the ping.
+	Latency time.Duration
+	// Connected reports whether the server answered the ping.
+	Connected bool
+	// TotalConns and IdleConns count the connections of the pool; Timeouts counts the
+	// times waiting for a connection timed out since the client was created.
+	TotalConns uint32
+	IdleConns  uint32
+	Timeouts   uint32
+}
+
+// Ping checks that the server responds. With a cluster, every master node is pinged.
+func (r *Redis) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis - Ping: %w", err)
+	}
+
+	return nil
+}
+
+// HealthCheck pings the server and reports the latency and the pool statistics.
+// Statistics are returned even when the check fails, for readiness probes to report
+// saturation.
+//
+// Example:
+//
+//	app.Get("/ready", func(c *fiber.Ctx) error {
+//	    status, err := r.HealthCheck(c.UserContext())
+//	    if err != nil {
+//	        return c.Status(fiber.StatusServiceUnavailable).JSON(status)
+//	    }
+//
+//	    return c.JSON(status)
+//	})
+func (r *Redis) HealthCheck(ctx context.Context) (Status, error) {
+	start := time.Now()
+
+	err := r.Ping(ctx)
+
+	stats := r.client.PoolStats()
+
+	return Status{
+		Latency:    time.Since(start),
+		Connected:  err == nil,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		Timeouts:   stats.Timeouts,
+	}, err
+}
@@ -0,0 +1,47 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_HealthCheck_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	status, err := client.HealthCheck(ctx)
+	if err == nil {
+		t.Error("expected HealthCheck to fail without connection")
+	}
+
+	if status.Connected || status.Latency <= 0 {
+		t.Errorf("HealthCheck() = %+v, want disconnected with latency", status)
+	}
+}
+
+func TestRedis_IntegrationHealthCheck(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	status, err := client.HealthCheck(ctx)
+	if err != nil || !status.Connected || status.TotalConns == 0 {
+		t.Errorf("HealthCheck() = %+v, %v, want connected", status, err)
+	}
+}
@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NoExpiry is returned by TTL for a key that exists without an expiry.
+const NoExpiry time.Duration = -1
+
+// _scanCount is the number of keys Scan asks the server to look at per call.
+const _scanCount = 100
+
+// Key returns key as stored on the server, with the KeyPrefix option prefix, e.g. to
+// inspect it with another client. Commands sent through the client add the prefix
+// themselves.
+func (r *Redis) Key(key string) string {
+	return r.prefix + key
+}
+
+// Del deletes the keys and returns how many existed.
+//
+// Example:
+//
+//	// Invalidate the cached profile after an update.
+//	_, err := r.Del(ctx, "user:123", "user:123:permissions")
+func (r *Redis) Del(ctx context.Context, keys ...string) (int64, error) {
+	n, err := r.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - Del: %w", err)
+	}
+
+	return n, nil
+}
+
+// Exists reports whether key exists.
+func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis - Exists: %w", err)
+	}
+
+	return n == 1, nil
+}
+
+// Expire sets the time to live of key. It reports false if the key does not exist.
+func (r *Redis) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := r.client.PExpire(ctx, key, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis - Expire: %w", err)
+	}
+
+	return ok, nil
+}
+
+// Persist removes the expiry of key. It reports false if the key does not exist or
+// has no expiry.
+func (r *Redis) Persist(ctx context.Context, key string) (bool, error) {
+	ok, err := r.client.Persist(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis - Persist: %w", err)
+	}
+
+	return ok, nil
+}
+
+// TTL returns the remaining time to live of key, NoExpiry for a key without one, and
+// zero for a key that does not exist.
+func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - TTL: %w", err)
+	}
+
+	// PTTL replies -2 for a missing key and -1 for a key without expiry.
+	switch {
+	case ttl == -2:
+		return 0, nil
+	case ttl < 0:
+		return NoExpiry, nil
+	default:
+		return ttl, nil
+	}
+}
+
+// Scan calls fn with each key matching the glob-style pattern match, e.g. "session:*",
+// without blocking the server like KEYS. With the KeyPrefix option, only keys with the
+// prefix are scanned and fn receives them without it. Keys added or removed during the
+// scan may be missed, and a key may be passed more than once. Scan stops at the first
+// error returned by fn.
+func (r *Redis) Scan(ctx context.Context, match string, fn func(key string) error) error {
+	var err error
+
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		// Masters are scanned concurrently, calls to fn are not.
+		var mu sync.Mutex
+
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			// Node clients don't have the prefix hook of the cluster client.
+			return r.scan(ctx, node, match, r.prefix, func(key string) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				return fn(key)
+			})
+		})
+	} else {
+		err = r.scan(ctx, r.client, match, "", fn)
+	}
+
+	if err != nil {
+		return fmt.Errorf("redis - Scan: %w", err)
+	}
+
+	return nil
+}
+
+// scan scans the keys of c matching match with prefix, passing them to fn without it.
+func (r *Redis) scan(ctx context.Context, c redis.Cmdable, match, prefix string, fn func(key string) error) error {
+	iter := c.Scan(ctx, 0, prefix+match, _scanCount).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(strings.TrimPrefix(iter.Val(), prefix)); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
@@ -0,0 +1,79 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_Keys_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if _, err := client.Del(ctx, "key"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.Exists(ctx, "key"); err == nil {
+		t.Error("expected Exists to fail without connection")
+	}
+
+	if _, err := client.Expire(ctx, "key", time.Minute); err == nil {
+		t.Error("expected Expire to fail without connection")
+	}
+
+	if _, err := client.Persist(ctx, "key"); err == nil {
+		t.Error("expected Persist to fail without connection")
+	}
+
+	if _, err := client.TTL(ctx, "key"); err == nil {
+		t.Error("expected TTL to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationKeys(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	key := "test-keys-key"
+
+	if err := client.SetWithTTL(ctx, key, "value", time.Minute); err != nil {
+		t.Fatalf("SetWithTTL() error = %v", err)
+	}
+
+	if exists, err := client.Exists(ctx, key); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+
+	if ttl, err := client.TTL(ctx, key); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL() = %v, %v, want up to a minute", ttl, err)
+	}
+
+	if ok, err := client.Persist(ctx, key); err != nil || !ok {
+		t.Errorf("Persist() = %v, %v, want true", ok, err)
+	}
+
+	if ttl, err := client.TTL(ctx, key); err != nil || ttl != redis.NoExpiry {
+		t.Errorf("TTL() = %v, %v, want NoExpiry", ttl, err)
+	}
+
+	if ok, err := client.Expire(ctx, key, time.Hour); err != nil || !ok {
+		t.Errorf("Expire() = %v, %v, want true", ok, err)
+	}
+
+	if n, err := client.Del(ctx, key, "test-keys-missing"); err != nil || n != 1 {
+		t.Errorf("Del() = %d, %v, want 1", n, err)
+	}
+
+	if ttl, err := client.TTL(ctx, key); err != nil || ttl != 0 {
+		t.Errorf("TTL() = %v, %v, want 0 for a missing key", ttl, err)
+	}
+}
@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// _blockSlice bounds each blocking call to the server, so that BRPop notices a
+// canceled context without configuring read deadlines on the client.
+const _blockSlice = time.Second
+
+// LPush prepends values to the list at key, creating it if needed, and returns its length.
+// Values are pushed in order, so the last one ends up first.
+func (r *Redis) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	n, err := r.client.LPush(ctx, key, toArgs(values)...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - LPush: %w", err)
+	}
+
+	return n, nil
+}
+
+// RPush appends values to the list at key, creating it if needed, and returns its length.
+func (r *Redis) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	n, err := r.client.RPush(ctx, key, toArgs(values)...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - RPush: %w", err)
+	}
+
+	return n, nil
+}
+
+// LPop removes and returns the first element of the list at key.
+// It reports false if the list is empty or doesn't exist.
+func (r *Redis) LPop(ctx context.Context, key string) (string, bool, error) {
+	val, err := r.client.LPop(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("redis - LPop: %w", err)
+	}
+
+	return val, true, nil
+}
+
+// BRPop removes and returns the last element of the first non-empty list of keys,
+// waiting up to timeout for one to be pushed, or until ctx is done when timeout is
+// zero. It returns the key of the list, and reports false when the timeout expires.
+// The timeout has a precision of one second, and a canceled ctx is noticed within
+// a second and returned as an error.
+//
+// Example:
+//
+//	// Worker consuming jobs pushed with LPush.
+//	for {
+//	    _, job, ok, err := r.BRPop(ctx, 0, "jobs:high", "jobs:low")
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    if ok {
+//	        process(job)
+//	    }
+//	}
+func (r *Redis) BRPop(ctx context.Context, timeout time.Duration, keys ...string) (key, value string, ok bool, err error) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", "", false, fmt.Errorf("redis - BRPop: %w", err)
+		}
+
+		wait := _blockSlice
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return "", "", false, nil
+			}
+
+			wait = min(wait, remaining)
+		}
+
+		res, err := r.client.BRPop(ctx, wait, keys...).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		} else if err != nil {
+			return "", "", false, fmt.Errorf("redis - BRPop: %w", err)
+		}
+
+		return res[0], res[1], true, nil
+	}
+}
+
+// LRange returns the elements of the list at key from start to stop, both included.
+// Negative indexes count from the end, so LRange(ctx, key, 0, -1) returns the whole list.
+//
+// Example:
+//
+//	// Recent items feed capped at 50 entries.
+//	_, err := r.LPush(ctx, "feed:42", itemID)
+//	items, err := r.LRange(ctx, "feed:42", 0, 49)
+func (r *Redis) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	vals, err := r.client.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis - LRange: %w", err)
+	}
+
+	return vals, nil
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+
+	return args
+}
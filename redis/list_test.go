@@ -0,0 +1,80 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_List_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if _, err := client.RPush(ctx, "list", "a"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, _, err := client.LPop(ctx, "list"); err == nil {
+		t.Error("expected LPop to fail without connection")
+	}
+
+	if _, _, _, err := client.BRPop(ctx, time.Second, "list"); err == nil {
+		t.Error("expected BRPop to fail without connection")
+	}
+}
+
+func TestRedis_BRPop_Canceled(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, _, err := client.BRPop(ctx, 0, "list"); !errors.Is(err, context.Canceled) {
+		t.Errorf("BRPop() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRedis_IntegrationList(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	key := "test-list-key"
+
+	if n, err := client.RPush(ctx, key, "b", "c"); err != nil || n != 2 {
+		t.Errorf("RPush() = %d, %v, want 2", n, err)
+	}
+
+	if n, err := client.LPush(ctx, key, "a"); err != nil || n != 3 {
+		t.Errorf("LPush() = %d, %v, want 3", n, err)
+	}
+
+	if items, err := client.LRange(ctx, key, 0, -1); err != nil || !reflect.DeepEqual(items, []string{"a", "b", "c"}) {
+		t.Errorf("LRange() = %v, %v, want [a b c]", items, err)
+	}
+
+	if v, ok, err := client.LPop(ctx, key); err != nil || !ok || v != "a" {
+		t.Errorf("LPop() = %q, %v, %v, want a", v, ok, err)
+	}
+
+	if k, v, ok, err := client.BRPop(ctx, time.Second, "test-list-missing", key); err != nil || !ok || k != key || v != "c" {
+		t.Errorf("BRPop() = %q, %q, %v, %v, want c", k, v, ok, err)
+	}
+
+	if _, _, ok, err := client.BRPop(ctx, 100*time.Millisecond, "test-list-missing"); err != nil || ok {
+		t.Errorf("BRPop() = %v, %v, want timeout", ok, err)
+	}
+}
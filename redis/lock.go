@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript deletes the lock key only if it still holds the caller's token,
+// so a lock that expired and was taken by another owner is never released by mistake.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// TryLock attempts to acquire a lock on key that expires after ttl, without blocking.
+// On success it returns a random token identifying the owner, which must be passed to Unlock.
+//
+// Example:
+//
+//	token, acquired, err := r.TryLock(ctx, "lock:reports", 30*time.Second)
+//	if err != nil || !acquired {
+//	    return err
+//	}
+//	defer r.Unlock(context.Background(), "lock:reports", token)
+func (r *Redis) TryLock(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error) {
+	token = uuid.NewString()
+
+	acquired, err = r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("redis - TryLock - SetNX: %w", err)
+	}
+
+	if !acquired {
+		return "", false, nil
+	}
+
+	return token, true, nil
+}
+
+// Unlock releases a lock acquired with TryLock.
+// It reports false if the lock had already expired or is held by another owner.
+func (r *Redis) Unlock(ctx context.Context, key, token string) (bool, error) {
+	n, err := unlockScript.Run(ctx, r.client, []string{key}, token).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis - Unlock - unlockScript.Run: %w", err)
+	}
+
+	return n == 1, nil
+}
@@ -0,0 +1,56 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_TryLock_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	_, _, err = client.TryLock(context.Background(), "lock:test", time.Second)
+	if err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.Unlock(context.Background(), "lock:test", "token"); err == nil {
+		t.Error("expected Unlock to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationTryLock(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	key := "test-lock-key"
+
+	token, acquired, err := client.TryLock(ctx, key, time.Second)
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+
+	if !acquired || token == "" {
+		t.Fatal("expected lock to be acquired with a token")
+	}
+
+	if _, again, _ := client.TryLock(ctx, key, time.Second); again {
+		t.Error("expected second TryLock to fail while the lock is held")
+	}
+
+	if released, _ := client.Unlock(ctx, key, "foreign-token"); released {
+		t.Error("expected Unlock with a foreign token to be rejected")
+	}
+
+	released, err := client.Unlock(ctx, key, token)
+	if err != nil || !released {
+		t.Errorf("expected lock to be released, got released=%v err=%v", released, err)
+	}
+}
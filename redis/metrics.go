@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// WithMetrics registers Prometheus metrics of the client on r: the duration of commands
+// by command and status, errors by command, hits and misses of key reads, and pool
+// statistics. Register one client per registerer, or tell clients apart with
+// prometheus.WrapRegistererWith. A nil registerer is ignored.
+// Default is no metrics.
+//
+// Example:
+//
+//	client, err := redis.New("localhost:6379", "", "", redis.WithMetrics(prometheus.DefaultRegisterer))
+func WithMetrics(r prometheus.Registerer) Options {
+	return func(c *Redis) {
+		if r != nil {
+			c.registerer = r
+		}
+	}
+}
+
+// _readCommands are the commands whose nil replies count as cache misses.
+var _readCommands = map[string]bool{
+	"get":    true,
+	"getex":  true,
+	"getdel": true,
+	"hget":   true,
+}
+
+// registerMetrics registers the command metrics and the pool collector on reg and
+// returns the hook observing commands.
+func (r *Redis) registerMetrics(reg prometheus.Registerer) (redis.Hook, error) {
+	m := commandMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "redis_command_duration_seconds",
+			Help:    "Duration of Redis commands and pipelines.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command", "status"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_command_errors_total",
+			Help: "Redis commands that failed, excluding nil replies.",
+		}, []string{"command"}),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_cache_hits_total",
+			Help: "Key reads that found the key.",
+		}, []string{"command"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_cache_misses_total",
+			Help: "Key reads that didn't find the key.",
+		}, []string{"command"}),
+	}
+
+	collectors := []prometheus.Collector{m.duration, m.errors, m.hits, m.misses, newPoolCollector(r)}
+
+	for i, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			for _, registered := range collectors[:i] {
+				reg.Unregister(registered)
+			}
+
+			return nil, fmt.Errorf("redis - registerMetrics - reg.Register: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+type poolCollector struct {
+	r *Redis
+
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+	timeouts *prometheus.Desc
+	total    *prometheus.Desc
+	idle     *prometheus.Desc
+	stale    *prometheus.Desc
+}
+
+func newPoolCollector(r *Redis) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("redis_pool_"+name, help, nil, nil)
+	}
+
+	return &poolCollector{
+		r:        r,
+		hits:     desc("hits_total", "Times a free connection was found in the pool."),
+		misses:   desc("misses_total", "Times a free connection was not found in the pool."),
+		timeouts: desc("timeouts_total", "Times waiting for a connection timed out."),
+		total:    desc("total_connections", "Connections in the pool."),
+		idle:     desc("idle_connections", "Idle connections in the pool."),
+		stale:    desc("stale_connections_total", "Stale connections removed from the pool."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.hits, c.misses, c.timeouts, c.total, c.idle, c.stale} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.r.client == nil {
+		return
+	}
+
+	s := c.r.client.PoolStats()
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	counter(c.hits, float64(s.Hits))
+	counter(c.misses, float64(s.Misses))
+	counter(c.timeouts, float64(s.Timeouts))
+	gauge(c.total, float64(s.TotalConns))
+	gauge(c.idle, float64(s.IdleConns))
+	counter(c.stale, float64(s.StaleConns))
+}
+
+type commandMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	hits     *prometheus.CounterVec
+	misses   *prometheus.CounterVec
+}
+
+func (m commandMetrics) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (m commandMetrics) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmd)
+
+		m.observe(strings.ToLower(cmd.Name()), time.Since(start), err)
+		m.count(cmd, err)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook observes the duration of the whole pipeline, and the errors,
+// hits and misses of each of its commands.
+func (m commandMetrics) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmds)
+
+		m.observe("pipeline", time.Since(start), err)
+
+		for _, cmd := range cmds {
+			m.count(cmd, cmd.Err())
+		}
+
+		return err
+	}
+}
+
+func (m commandMetrics) observe(command string, d time.Duration, err error) {
+	status := "ok"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		status = "error"
+	}
+
+	m.duration.WithLabelValues(command, status).Observe(d.Seconds())
+}
+
+// count counts the error of cmd, or its hit or miss when it reads a key.
+func (m commandMetrics) count(cmd redis.Cmder, err error) {
+	command := strings.ToLower(cmd.Name())
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		m.errors.WithLabelValues(command).Inc()
+
+		return
+	}
+
+	if !_readCommands[command] {
+		return
+	}
+
+	if errors.Is(err, redis.Nil) {
+		m.misses.WithLabelValues(command).Inc()
+	} else {
+		m.hits.WithLabelValues(command).Inc()
+	}
+}
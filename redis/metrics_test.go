@@ -0,0 +1,83 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestWithMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	client, err := redis.New("127.0.0.1:65432", "", "", redis.WithMetrics(reg))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Set(context.Background(), "key", "value"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	want := `
+# HELP redis_command_errors_total Redis commands that failed, excluding nil replies.
+# TYPE redis_command_errors_total counter
+redis_command_errors_total{command="set"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "redis_command_errors_total"); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(reg, "redis_command_duration_seconds"); n != 1 {
+		t.Errorf("redis_command_duration_seconds series = %d, want 1", n)
+	}
+
+	if n := testutil.CollectAndCount(reg, "redis_pool_total_connections"); n != 1 {
+		t.Errorf("redis_pool_total_connections series = %d, want 1", n)
+	}
+
+	_, err = redis.New("127.0.0.1:65432", "", "", redis.WithMetrics(reg))
+
+	var already prometheus.AlreadyRegisteredError
+	if !errors.As(err, &already) {
+		t.Errorf("New() with the same registerer error = %v, want AlreadyRegisteredError", err)
+	}
+}
+
+func TestWithMetrics_Integration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	client, _ := redistest.New(t, redis.WithMetrics(reg))
+
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "test-metrics-key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if _, err := client.Get(ctx, "test-metrics-key"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if _, err := client.Get(ctx, "test-metrics-missing"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	want := `
+# HELP redis_cache_hits_total Key reads that found the key.
+# TYPE redis_cache_hits_total counter
+redis_cache_hits_total{command="get"} 1
+# HELP redis_cache_misses_total Key reads that didn't find the key.
+# TYPE redis_cache_misses_total counter
+redis_cache_misses_total{command="get"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "redis_cache_hits_total", "redis_cache_misses_total"); err != nil {
+		t.Error(err)
+	}
+}
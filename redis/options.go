@@ -1,6 +1,10 @@
 package redis
 
-import "time"
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
 
 // Options defines a function type for configuring Redis instances.
 type Options func(*Redis)
@@ -11,3 +15,107 @@ func TTL(ttl time.Duration) Options {
 		c.ttl = ttl
 	}
 }
+
+// Hooks adds go-redis hooks wrapping dials, commands and pipelines, e.g. for metrics or tracing.
+// Hooks of several calls run in order.
+func Hooks(hooks ...redis.Hook) Options {
+	return func(c *Redis) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// KeyPrefix adds prefix to the keys of every command, and strips it from the keys they
+// return, so that services can share a Redis instance, e.g. "billing:". It applies to
+// the methods of Redis and Cache as well as to commands sent through Client, Pipeline,
+// TxPipeline or a Watch transaction, and to Lua scripts through their KEYS. Pub/Sub
+// channels are not keys: use Key to prefix them. With a cluster, the clients of the
+// nodes returned by Client are not prefixed.
+// Default is no prefix.
+func KeyPrefix(prefix string) Options {
+	return func(c *Redis) {
+		c.prefix = prefix
+	}
+}
+
+// PoolSize sets the maximum number of connections, per node with a cluster.
+// Default is 10 connections per CPU.
+func PoolSize(n int) Options {
+	return func(c *Redis) {
+		if n > 0 {
+			c.conn.PoolSize = n
+		}
+	}
+}
+
+// MinIdleConns sets the number of idle connections kept open, to absorb bursts
+// without dialing.
+// Default is 0.
+func MinIdleConns(n int) Options {
+	return func(c *Redis) {
+		if n > 0 {
+			c.conn.MinIdleConns = n
+		}
+	}
+}
+
+// PoolTimeout sets how long a command waits for a connection when all of them are busy.
+// Default is ReadTimeout plus 1 second.
+func PoolTimeout(d time.Duration) Options {
+	return func(c *Redis) {
+		if d > 0 {
+			c.conn.PoolTimeout = d
+		}
+	}
+}
+
+// DialTimeout sets the timeout for establishing connections.
+// Default is 5 seconds.
+func DialTimeout(d time.Duration) Options {
+	return func(c *Redis) {
+		if d > 0 {
+			c.conn.DialTimeout = d
+		}
+	}
+}
+
+// ReadTimeout sets the timeout for reading replies; -1 disables it. Blocking commands
+// such as BRPop extend it by their own timeout.
+// Default is 3 seconds.
+func ReadTimeout(d time.Duration) Options {
+	return func(c *Redis) {
+		c.conn.ReadTimeout = d
+	}
+}
+
+// WriteTimeout sets the timeout for sending commands; -1 disables it.
+// Default is ReadTimeout.
+func WriteTimeout(d time.Duration) Options {
+	return func(c *Redis) {
+		c.conn.WriteTimeout = d
+	}
+}
+
+// MaxRetries sets how many times a command failing with a network error, or a
+// retriable server error such as LOADING, is retried; -1 disables retries.
+// Default is 3.
+func MaxRetries(n int) Options {
+	return func(c *Redis) {
+		c.conn.MaxRetries = n
+	}
+}
+
+// RetryBackoff sets the bounds of the exponential backoff between retries.
+// Default is 8 milliseconds to 512 milliseconds.
+//
+// Example:
+//
+//	client, err := redis.New("localhost:6379", "", "",
+//	    redis.MaxRetries(5),
+//	    redis.RetryBackoff(10*time.Millisecond, time.Second),
+//	)
+func RetryBackoff(minBackoff, maxBackoff time.Duration) Options {
+	return func(c *Redis) {
+		c.conn.MinRetryBackoff = minBackoff
+		c.conn.MaxRetryBackoff = maxBackoff
+	}
+}
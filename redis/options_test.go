@@ -0,0 +1,57 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestPoolOptions(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "user", "pass",
+		redis.PoolSize(20),
+		redis.MinIdleConns(2),
+		redis.PoolTimeout(2*time.Second),
+		redis.DialTimeout(time.Second),
+		redis.ReadTimeout(500*time.Millisecond),
+		redis.WriteTimeout(250*time.Millisecond),
+		redis.MaxRetries(-1),
+		redis.RetryBackoff(time.Millisecond, time.Second),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	opts := client.Client().(*goredis.Client).Options()
+
+	if opts.Addr != "127.0.0.1:65432" || opts.Username != "user" || opts.Password != "pass" {
+		t.Errorf("Options() address = %s %s %s, want the New arguments", opts.Addr, opts.Username, opts.Password)
+	}
+
+	if opts.PoolSize != 20 || opts.MinIdleConns != 2 || opts.PoolTimeout != 2*time.Second {
+		t.Errorf("Options() pool = %d %d %v, want 20 2 2s", opts.PoolSize, opts.MinIdleConns, opts.PoolTimeout)
+	}
+
+	if opts.DialTimeout != time.Second || opts.ReadTimeout != 500*time.Millisecond || opts.WriteTimeout != 250*time.Millisecond {
+		t.Errorf("Options() timeouts = %v %v %v, want 1s 500ms 250ms", opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout)
+	}
+
+	// go-redis stores disabled retries as 0.
+	if opts.MaxRetries != 0 || opts.MinRetryBackoff != time.Millisecond || opts.MaxRetryBackoff != time.Second {
+		t.Errorf("Options() retries = %d %v %v, want 0 1ms 1s", opts.MaxRetries, opts.MinRetryBackoff, opts.MaxRetryBackoff)
+	}
+}
+
+func TestPoolOptions_Cluster(t *testing.T) {
+	client, err := redis.NewCluster([]string{"127.0.0.1:65432"}, "", "", redis.PoolSize(7))
+	if err != nil {
+		t.Fatalf("NewCluster() error = %v", err)
+	}
+	defer client.Close()
+
+	if opts := client.Client().(*goredis.ClusterClient).Options(); opts.PoolSize != 7 {
+		t.Errorf("Options().PoolSize = %d, want 7", opts.PoolSize)
+	}
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// _watchAttempts bounds how many times Watch runs its function when the watched keys
+// keep changing.
+const _watchAttempts = 10
+
+// Nil is the error of a pipelined command reading a key that doesn't exist.
+const Nil = redis.Nil
+
+// ErrTxFailed is returned by Watch when the watched keys kept changing.
+const ErrTxFailed = redis.TxFailedErr
+
+// Pipe queues commands to send in one round trip. Each queued command returns its
+// result holder, filled in when the pipeline is executed.
+type Pipe = redis.Pipeliner
+
+// Tx is a connection with watched keys, used in a Watch function to read the keys and
+// update them with TxPipeline.
+type Tx = redis.Tx
+
+// Pipeline sends the commands queued by fn in one round trip and returns them in order
+// with their results. Commands are not atomic: use TxPipeline for that. Nothing is sent
+// when fn returns an error.
+//
+// The error is that of the first failed command. A key that doesn't exist is not an
+// error, but the command's Err() returns Nil.
+//
+// Example:
+//
+//	var views *goredis.IntCmd
+//
+//	_, err := r.Pipeline(ctx, func(p redis.Pipe) error {
+//	    views = p.Incr(ctx, "views:"+id)
+//	    p.Expire(ctx, "views:"+id, time.Hour)
+//	    return nil
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	log.Println(views.Val())
+func (r *Redis) Pipeline(ctx context.Context, fn func(p Pipe) error) ([]redis.Cmder, error) {
+	cmds, err := r.client.Pipelined(ctx, fn)
+	if err = firstError(cmds, err); err != nil {
+		return cmds, fmt.Errorf("redis - Pipeline: %w", err)
+	}
+
+	return cmds, nil
+}
+
+// TxPipeline is like Pipeline but wraps the commands in MULTI/EXEC, so that they are
+// executed atomically, without commands of other clients in between.
+func (r *Redis) TxPipeline(ctx context.Context, fn func(p Pipe) error) ([]redis.Cmder, error) {
+	cmds, err := r.client.TxPipelined(ctx, fn)
+	if err = firstError(cmds, err); err != nil {
+		return cmds, fmt.Errorf("redis - TxPipeline: %w", err)
+	}
+
+	return cmds, nil
+}
+
+// Watch runs fn with keys watched, for optimistic locking: fn reads the keys with tx and
+// updates them with tx.TxPipelined, which fails if any of them was changed meanwhile.
+// fn is then run again, up to 10 times in total, after which ErrTxFailed is returned.
+// In a cluster all keys must map to the same hash slot.
+//
+// Example:
+//
+//	// Decrement the stock only if enough is left.
+//	err := r.Watch(ctx, func(tx *redis.Tx) error {
+//	    stock, err := tx.Get(ctx, key).Int()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if stock < n {
+//	        return ErrOutOfStock
+//	    }
+//	    _, err = tx.TxPipelined(ctx, func(p redis.Pipe) error {
+//	        p.Set(ctx, key, stock-n, 0)
+//	        return nil
+//	    })
+//	    return err
+//	}, key)
+func (r *Redis) Watch(ctx context.Context, fn func(tx *Tx) error, keys ...string) error {
+	var err error
+
+	for range _watchAttempts {
+		err = r.client.Watch(ctx, fn, keys...)
+		if !errors.Is(err, ErrTxFailed) {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("redis - Watch: %w", ctx.Err())
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("redis - Watch: %w", err)
+	}
+
+	return nil
+}
+
+// firstError returns err unless it is Nil, in which case it returns the error of the
+// first command that failed otherwise, if any.
+func firstError(cmds []redis.Cmder, err error) error {
+	if !errors.Is(err, Nil) {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && !errors.Is(err, Nil) {
+			return err
+		}
+	}
+
+	return nil
+}
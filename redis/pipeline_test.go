@@ -0,0 +1,108 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestRedis_Pipeline_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	errAbort := errors.New("abort")
+	if _, err := client.Pipeline(ctx, func(redis.Pipe) error { return errAbort }); !errors.Is(err, errAbort) {
+		t.Errorf("Pipeline() error = %v, want %v", err, errAbort)
+	}
+
+	if _, err := client.Pipeline(ctx, func(p redis.Pipe) error {
+		p.Get(ctx, "key")
+		return nil
+	}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.TxPipeline(ctx, func(p redis.Pipe) error {
+		p.Get(ctx, "key")
+		return nil
+	}); err == nil {
+		t.Error("expected TxPipeline to fail without connection")
+	}
+
+	if err := client.Watch(ctx, func(*redis.Tx) error { return nil }, "key"); err == nil {
+		t.Error("expected Watch to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationPipeline(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	key := "test-pipeline-key"
+
+	var (
+		incr    *goredis.IntCmd
+		missing *goredis.StringCmd
+	)
+
+	cmds, err := client.Pipeline(ctx, func(p redis.Pipe) error {
+		p.Set(ctx, key, 1, 0)
+		incr = p.Incr(ctx, key)
+		missing = p.Get(ctx, "test-pipeline-missing")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Pipeline() error = %v", err)
+	}
+
+	if len(cmds) != 3 || incr.Val() != 2 || !errors.Is(missing.Err(), redis.Nil) {
+		t.Errorf("Pipeline() = %d commands, incr %d, missing %v", len(cmds), incr.Val(), missing.Err())
+	}
+
+	if _, err := client.TxPipeline(ctx, func(p redis.Pipe) error {
+		p.IncrBy(ctx, key, 10)
+		return nil
+	}); err != nil {
+		t.Errorf("TxPipeline() error = %v", err)
+	}
+
+	// Change the watched key once behind the first attempt's back to force a retry.
+	attempts := 0
+	err = client.Watch(ctx, func(tx *redis.Tx) error {
+		attempts++
+
+		n, err := tx.Get(ctx, key).Int()
+		if err != nil {
+			return err
+		}
+
+		if attempts == 1 {
+			if err := client.Set(ctx, key, "100"); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipe) error {
+			p.Set(ctx, key, n*2, 0)
+			return nil
+		})
+
+		return err
+	}, key)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if v, err := client.Get(ctx, key); err != nil || v != "200" || attempts != 2 {
+		t.Errorf("Get() = %q, %v after %d attempts, want 200 after 2", v, err, attempts)
+	}
+}
@@ -0,0 +1,296 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keySpec locates the keys in the arguments of a command like COMMAND INFO does: from
+// the first to the last argument, counted from the end when negative, every step.
+type keySpec struct {
+	first, last, step int
+}
+
+var (
+	_oneKey      = keySpec{1, 1, 1}
+	_allKeys     = keySpec{1, -1, 1}
+	_pairKeys    = keySpec{1, -1, 2}
+	_twoKeys     = keySpec{1, 2, 1}
+	_blockKeys   = keySpec{1, -2, 1}
+	_subcmdKey   = keySpec{2, 2, 1}
+	_numKeysAt1  = keySpec{first: 1}
+	_numKeysAt2  = keySpec{first: 2}
+	_destNumKeys = keySpec{first: -2}
+)
+
+// _keySpecs maps the commands taking keys to their keys. Commands taking a number of
+// keys before them are marked with a zero step and the position of that number, or -2
+// for a destination key followed by the number at 2.
+var _keySpecs = map[string]keySpec{
+	// Keys.
+	"del": _allKeys, "exists": _allKeys, "unlink": _allKeys, "touch": _allKeys, "watch": _allKeys,
+	"expire": _oneKey, "pexpire": _oneKey, "expireat": _oneKey, "pexpireat": _oneKey,
+	"expiretime": _oneKey, "pexpiretime": _oneKey, "persist": _oneKey, "ttl": _oneKey,
+	"pttl": _oneKey, "type": _oneKey, "dump": _oneKey, "restore": _oneKey, "sort": _oneKey,
+	"sort_ro": _oneKey, "rename": _twoKeys, "renamenx": _twoKeys, "copy": _twoKeys,
+	"object": _subcmdKey, "memory": _subcmdKey,
+
+	// Strings.
+	"get": _oneKey, "set": _oneKey, "setnx": _oneKey, "setex": _oneKey, "psetex": _oneKey,
+	"getset": _oneKey, "getdel": _oneKey, "getex": _oneKey, "append": _oneKey,
+	"strlen": _oneKey, "incr": _oneKey, "incrby": _oneKey, "incrbyfloat": _oneKey,
+	"decr": _oneKey, "decrby": _oneKey, "getrange": _oneKey, "setrange": _oneKey,
+	"getbit": _oneKey, "setbit": _oneKey, "bitcount": _oneKey, "bitpos": _oneKey,
+	"bitfield": _oneKey, "mget": _allKeys, "mset": _pairKeys, "msetnx": _pairKeys,
+	"pfadd": _oneKey, "pfcount": _allKeys, "pfmerge": _allKeys,
+
+	// Hashes.
+	"hset": _oneKey, "hsetnx": _oneKey, "hget": _oneKey, "hmset": _oneKey, "hmget": _oneKey,
+	"hgetall": _oneKey, "hdel": _oneKey, "hexists": _oneKey, "hincrby": _oneKey,
+	"hincrbyfloat": _oneKey, "hkeys": _oneKey, "hvals": _oneKey, "hlen": _oneKey,
+	"hscan": _oneKey, "hstrlen": _oneKey, "hrandfield": _oneKey, "hexpire": _oneKey,
+	"hpexpire": _oneKey, "httl": _oneKey, "hpttl": _oneKey, "hpersist": _oneKey,
+
+	// Lists.
+	"lpush": _oneKey, "rpush": _oneKey, "lpushx": _oneKey, "rpushx": _oneKey,
+	"lpop": _oneKey, "rpop": _oneKey, "llen": _oneKey, "lrange": _oneKey, "lindex": _oneKey,
+	"lset": _oneKey, "linsert": _oneKey, "lrem": _oneKey, "ltrim": _oneKey, "lpos": _oneKey,
+	"rpoplpush": _twoKeys, "lmove": _twoKeys, "brpoplpush": _twoKeys, "blmove": _twoKeys,
+	"blpop": _blockKeys, "brpop": _blockKeys, "lmpop": _numKeysAt1, "blmpop": _numKeysAt2,
+
+	// Sets.
+	"sadd": _oneKey, "srem": _oneKey, "smembers": _oneKey, "sismember": _oneKey,
+	"smismember": _oneKey, "scard": _oneKey, "spop": _oneKey, "srandmember": _oneKey,
+	"sscan": _oneKey, "smove": _twoKeys, "sinter": _allKeys, "sunion": _allKeys,
+	"sdiff": _allKeys, "sinterstore": _allKeys, "sunionstore": _allKeys,
+	"sdiffstore": _allKeys, "sintercard": _numKeysAt1,
+
+	// Sorted sets.
+	"zadd": _oneKey, "zrem": _oneKey, "zscore": _oneKey, "zmscore": _oneKey,
+	"zincrby": _oneKey, "zcard": _oneKey, "zcount": _oneKey, "zlexcount": _oneKey,
+	"zrange": _oneKey, "zrangebyscore": _oneKey, "zrevrange": _oneKey,
+	"zrevrangebyscore": _oneKey, "zrangebylex": _oneKey, "zrevrangebylex": _oneKey,
+	"zrank": _oneKey, "zrevrank": _oneKey, "zremrangebyrank": _oneKey,
+	"zremrangebyscore": _oneKey, "zremrangebylex": _oneKey, "zscan": _oneKey,
+	"zpopmin": _oneKey, "zpopmax": _oneKey, "zrandmember": _oneKey,
+	"zrangestore": _twoKeys, "bzpopmin": _blockKeys, "bzpopmax": _blockKeys,
+	"zunion": _numKeysAt1, "zinter": _numKeysAt1, "zdiff": _numKeysAt1,
+	"zintercard": _numKeysAt1, "zmpop": _numKeysAt1, "bzmpop": _numKeysAt2,
+	"zunionstore": _destNumKeys, "zinterstore": _destNumKeys, "zdiffstore": _destNumKeys,
+
+	// Streams.
+	"xadd": _oneKey, "xlen": _oneKey, "xrange": _oneKey, "xrevrange": _oneKey,
+	"xdel": _oneKey, "xtrim": _oneKey, "xack": _oneKey, "xpending": _oneKey,
+	"xclaim": _oneKey, "xautoclaim": _oneKey, "xsetid": _oneKey,
+	"xgroup": _subcmdKey, "xinfo": _subcmdKey,
+
+	// Geo.
+	"geoadd": _oneKey, "geopos": _oneKey, "geodist": _oneKey, "geohash": _oneKey,
+	"georadius": _oneKey, "georadius_ro": _oneKey, "georadiusbymember": _oneKey,
+	"georadiusbymember_ro": _oneKey, "geosearch": _oneKey, "geosearchstore": _twoKeys,
+
+	// Scripts and functions.
+	"eval": _numKeysAt2, "evalsha": _numKeysAt2, "eval_ro": _numKeysAt2,
+	"evalsha_ro": _numKeysAt2, "fcall": _numKeysAt2, "fcall_ro": _numKeysAt2,
+}
+
+// prefixHook adds the KeyPrefix option prefix to the keys of every command sent
+// through the client, pipelines and transactions included, and strips it from the
+// keys in the replies of SCAN, KEYS, RANDOMKEY, the blocking pops and XREAD.
+type prefixHook struct {
+	prefix string
+}
+
+func (h prefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h prefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.prefixArgs(cmd)
+
+		err := next(ctx, cmd)
+
+		h.unprefixReply(cmd)
+
+		return err
+	}
+}
+
+func (h prefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.prefixArgs(cmd)
+		}
+
+		err := next(ctx, cmds)
+
+		for _, cmd := range cmds {
+			h.unprefixReply(cmd)
+		}
+
+		return err
+	}
+}
+
+// prefixArgs prefixes the keys, and the patterns of SCAN and KEYS, in the arguments
+// of cmd, in place.
+func (h prefixHook) prefixArgs(cmd redis.Cmder) {
+	args := cmd.Args()
+	name := cmd.Name()
+
+	switch name {
+	case "scan":
+		for i := 2; i < len(args)-1; i++ {
+			if s, ok := args[i].(string); ok && strings.EqualFold(s, "match") {
+				h.prefixArg(args, i+1)
+			}
+		}
+
+		return
+	case "keys":
+		h.prefixArg(args, 1)
+
+		return
+	case "xread", "xreadgroup":
+		h.prefixStreams(args)
+
+		return
+	}
+
+	spec, ok := _keySpecs[name]
+	if !ok {
+		return
+	}
+
+	if spec.step == 0 {
+		h.prefixNumKeys(args, spec.first)
+
+		return
+	}
+
+	last := spec.last
+	if last < 0 {
+		last += len(args)
+	}
+
+	for i := spec.first; i <= last && i < len(args); i += spec.step {
+		h.prefixArg(args, i)
+	}
+}
+
+// prefixNumKeys prefixes the keys following their number at pos, after a destination
+// key when pos is -2.
+func (h prefixHook) prefixNumKeys(args []interface{}, pos int) {
+	if pos == _destNumKeys.first {
+		h.prefixArg(args, 1)
+
+		pos = 2
+	}
+
+	if pos >= len(args) {
+		return
+	}
+
+	n, err := strconv.Atoi(toString(args[pos]))
+	if err != nil {
+		return
+	}
+
+	for i := pos + 1; i <= pos+n && i < len(args); i++ {
+		h.prefixArg(args, i)
+	}
+}
+
+// prefixStreams prefixes the stream keys of XREAD and XREADGROUP, the first half of
+// the arguments after STREAMS, the others being IDs.
+func (h prefixHook) prefixStreams(args []interface{}) {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "streams") {
+			n := (len(args) - i - 1) / 2
+			for j := i + 1; j <= i+n; j++ {
+				h.prefixArg(args, j)
+			}
+
+			return
+		}
+	}
+}
+
+func (h prefixHook) prefixArg(args []interface{}, i int) {
+	if i >= len(args) {
+		return
+	}
+
+	switch key := args[i].(type) {
+	case string:
+		args[i] = h.prefix + key
+	case []byte:
+		args[i] = h.prefix + string(key)
+	}
+}
+
+// unprefixReply strips the prefix from the keys in the reply of cmd.
+func (h prefixHook) unprefixReply(cmd redis.Cmder) {
+	if cmd.Err() != nil {
+		return
+	}
+
+	switch cmd := cmd.(type) {
+	case *redis.ScanCmd:
+		keys, cursor := cmd.Val()
+		if cmd.Name() == "scan" {
+			cmd.SetVal(h.unprefixAll(keys), cursor)
+		}
+	case *redis.StringSliceCmd:
+		// BLPOP and BRPOP reply the key and the value.
+		switch cmd.Name() {
+		case "keys":
+			cmd.SetVal(h.unprefixAll(cmd.Val()))
+		case "blpop", "brpop":
+			if val := cmd.Val(); len(val) == 2 {
+				val[0] = h.unprefix(val[0])
+			}
+		}
+	case *redis.StringCmd:
+		if cmd.Name() == "randomkey" {
+			cmd.SetVal(h.unprefix(cmd.Val()))
+		}
+	case *redis.ZWithKeyCmd:
+		if z := cmd.Val(); z != nil {
+			z.Key = h.unprefix(z.Key)
+		}
+	case *redis.XStreamSliceCmd:
+		for i := range cmd.Val() {
+			cmd.Val()[i].Stream = h.unprefix(cmd.Val()[i].Stream)
+		}
+	}
+}
+
+func (h prefixHook) unprefixAll(keys []string) []string {
+	for i, key := range keys {
+		keys[i] = h.unprefix(key)
+	}
+
+	return keys
+}
+
+func (h prefixHook) unprefix(key string) string {
+	return strings.TrimPrefix(key, h.prefix)
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,161 @@
+package redis_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestRedis_Key(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "", redis.KeyPrefix("app:"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if got := client.Key("user:1"); got != "app:user:1" {
+		t.Errorf("Key() = %q, want app:user:1", got)
+	}
+}
+
+func TestRedis_IntegrationKeyPrefix(t *testing.T) {
+	client, srv := redistest.New(t, redis.KeyPrefix("test-prefix:"))
+
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "a", "1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if v, err := srv.Miniredis().Get("test-prefix:a"); err != nil || v != "1" {
+		t.Errorf("server value = %q, %v, want 1", v, err)
+	}
+
+	if v, err := client.Get(ctx, "a"); err != nil || v != "1" {
+		t.Errorf("Get() = %q, %v, want 1", v, err)
+	}
+
+	if err := client.Set(ctx, "b", "2"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	var keys []string
+	if err := client.Scan(ctx, "*", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	if !slices.Equal(keys, []string{"a", "b"}) {
+		t.Errorf("Scan() keys = %v, want [a b]", keys)
+	}
+
+	if _, err := client.RPush(ctx, "list", "x"); err != nil {
+		t.Fatalf("RPush() error = %v", err)
+	}
+
+	if k, v, ok, err := client.BRPop(ctx, time.Second, "list"); err != nil || !ok || k != "list" || v != "x" {
+		t.Errorf("BRPop() = %q, %q, %v, %v, want list x", k, v, ok, err)
+	}
+}
+
+func TestRedis_IntegrationKeyPrefixClient(t *testing.T) {
+	client, srv := redistest.New(t, redis.KeyPrefix("app:"))
+
+	ctx := context.Background()
+	mr := srv.Miniredis()
+
+	_, err := client.Pipeline(ctx, func(p redis.Pipe) error {
+		p.Set(ctx, "piped", "1", 0)
+		p.MSet(ctx, "m1", "a", "m2", "b")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Pipeline() error = %v", err)
+	}
+
+	_, err = client.TxPipeline(ctx, func(p redis.Pipe) error {
+		p.RPush(ctx, "queue", "job")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TxPipeline() error = %v", err)
+	}
+
+	err = client.Watch(ctx, func(tx *redis.Tx) error {
+		n, err := tx.Get(ctx, "piped").Int()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipe) error {
+			p.Set(ctx, "watched", n+1, 0)
+
+			return nil
+		})
+
+		return err
+	}, "piped")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	raw := client.Client()
+
+	if err := raw.Eval(ctx, `return redis.call("SET", KEYS[1], ARGV[1])`, []string{"scripted"}, "x").Err(); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+
+	if err := raw.ZAdd(ctx, "scores", goredis.Z{Score: 1, Member: "a"}).Err(); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+
+	if err := raw.ZUnionStore(ctx, "union", &goredis.ZStore{Keys: []string{"scores"}}).Err(); err != nil {
+		t.Fatalf("ZUnionStore() error = %v", err)
+	}
+
+	for _, key := range []string{"piped", "m1", "m2", "queue", "watched", "scripted", "scores", "union"} {
+		if !mr.Exists("app:" + key) {
+			t.Errorf("key %q not prefixed, server keys = %v", key, mr.Keys())
+		}
+	}
+
+	if vals, err := raw.MGet(ctx, "m1", "m2").Result(); err != nil || vals[0] != "a" || vals[1] != "b" {
+		t.Errorf("MGet() = %v, %v, want [a b]", vals, err)
+	}
+
+	if kv, err := raw.BLPop(ctx, time.Second, "queue").Result(); err != nil || kv[0] != "queue" || kv[1] != "job" {
+		t.Errorf("BLPop() = %v, %v, want [queue job]", kv, err)
+	}
+
+	keys, err := raw.Keys(ctx, "m*").Result()
+	slices.Sort(keys)
+
+	if err != nil || !slices.Equal(keys, []string{"m1", "m2"}) {
+		t.Errorf("Keys() = %v, %v, want [m1 m2]", keys, err)
+	}
+
+	if err := raw.XAdd(ctx, &goredis.XAddArgs{Stream: "events", Values: []string{"k", "v"}}).Err(); err != nil {
+		t.Fatalf("XAdd() error = %v", err)
+	}
+
+	streams, err := raw.XRead(ctx, &goredis.XReadArgs{Streams: []string{"events", "0"}, Count: 1}).Result()
+	if err != nil || len(streams) != 1 || streams[0].Stream != "events" || len(streams[0].Messages) != 1 {
+		t.Errorf("XRead() = %+v, %v, want one message of events", streams, err)
+	}
+
+	if mr.Exists("events") || !mr.Exists("app:events") {
+		t.Errorf("stream not prefixed, server keys = %v", mr.Keys())
+	}
+}
@@ -4,9 +4,11 @@ package redis
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -14,8 +16,15 @@ const defaultTTL = 2 * time.Minute
 
 // Redis represents a Redis client with configurable default TTL.
 type Redis struct {
-	client *redis.Client
+	client redis.UniversalClient
 	ttl    time.Duration
+	hooks  []redis.Hook
+	prefix string
+	// conn holds the connection and pool settings of the options, mirrored onto the
+	// options of the go-redis client by the constructors.
+	conn redis.UniversalOptions
+
+	registerer prometheus.Registerer
 }
 
 // New creates a new Redis client with the given connection parameters and options.
@@ -27,6 +36,21 @@ type Redis struct {
 //	    redis.TTL(5 * time.Minute),
 //	)
 func New(address string, user string, password string, opts ...Options) (*Redis, error) {
+	r := newRedis(opts)
+
+	r.conn.Addrs = []string{address}
+	r.conn.Username = user
+	r.conn.Password = password
+
+	err := r.setClient(redis.NewClient(r.conn.Simple()))
+	if err != nil {
+		return nil, fmt.Errorf("redis - New - %w", err)
+	}
+
+	return r, nil
+}
+
+func newRedis(opts []Options) *Redis {
 	r := &Redis{
 		ttl: defaultTTL,
 	}
@@ -35,13 +59,35 @@ func New(address string, user string, password string, opts ...Options) (*Redis,
 		opt(r)
 	}
 
-	r.client = redis.NewClient(&redis.Options{
-		Addr:     address,
-		Username: user,
-		Password: password,
-	})
+	return r
+}
+
+// setClient sets the go-redis client, adds the configured hooks to it and registers
+// its metrics. The client is closed if the metrics can't be registered.
+func (r *Redis) setClient(client redis.UniversalClient) error {
+	r.client = client
 
-	return r, nil
+	for _, h := range r.hooks {
+		r.client.AddHook(h)
+	}
+
+	if r.registerer != nil {
+		hook, err := r.registerMetrics(r.registerer)
+		if err != nil {
+			r.Close()
+
+			return fmt.Errorf("setClient - r.registerMetrics: %w", err)
+		}
+
+		r.client.AddHook(hook)
+	}
+
+	// Added last, the other hooks see the keys without the prefix.
+	if r.prefix != "" {
+		r.client.AddHook(prefixHook{prefix: r.prefix})
+	}
+
+	return nil
 }
 
 // Set stores a key-value pair with the default TTL.
@@ -77,3 +123,8 @@ func (r *Redis) Close() {
 		}
 	}
 }
+
+// Client returns the underlying go-redis client for commands not covered by Redis.
+func (r *Redis) Client() redis.UniversalClient {
+	return r.client
+}
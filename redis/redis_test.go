@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
 )
 
 func TestNew(t *testing.T) {
@@ -187,24 +188,18 @@ func TestRedis_MultipleOptions(t *testing.T) {
 	}
 }
 
-// TestRedis_IntegrationSetGet would test actual Redis operations
-// This would require a running Redis instance
+// TestRedis_IntegrationSetGet tests actual Redis operations against an embedded server
 func TestRedis_IntegrationSetGet(t *testing.T) {
-	// Skip this test if we don't have a Redis server available
-	client, err := redis.New("localhost:6379", "", "")
-	if err != nil {
-		t.Fatalf("failed to create client: %v", err)
-	}
-	defer client.Close()
+	client, _ := redistest.New(t)
 
 	ctx := context.Background()
 	testKey := "test-integration-key"
 	testValue := "test-integration-value"
 
-	// Try to set a value
-	err = client.Set(ctx, testKey, testValue)
+	// Set a value
+	err := client.Set(ctx, testKey, testValue)
 	if err != nil {
-		t.Skip("Redis server not available for integration test")
+		t.Fatalf("failed to set value: %v", err)
 	}
 
 	// Try to get the value
@@ -228,22 +223,18 @@ func TestRedis_IntegrationSetGet(t *testing.T) {
 	}
 }
 
-// TestRedis_IntegrationSetWithTTL would test TTL functionality
+// TestRedis_IntegrationSetWithTTL tests TTL functionality
 func TestRedis_IntegrationSetWithTTL(t *testing.T) {
-	client, err := redis.New("localhost:6379", "", "")
-	if err != nil {
-		t.Fatalf("failed to create client: %v", err)
-	}
-	defer client.Close()
+	client, srv := redistest.New(t)
 
 	ctx := context.Background()
 	testKey := "test-ttl-key"
 	testValue := "test-ttl-value"
 
-	// Try to set a value with short TTL
-	err = client.SetWithTTL(ctx, testKey, testValue, 100*time.Millisecond)
+	// Set a value with short TTL
+	err := client.SetWithTTL(ctx, testKey, testValue, 100*time.Millisecond)
 	if err != nil {
-		t.Skip("Redis server not available for integration test")
+		t.Fatalf("failed to set value: %v", err)
 	}
 
 	// Should be able to get it immediately
@@ -256,8 +247,8 @@ func TestRedis_IntegrationSetWithTTL(t *testing.T) {
 		t.Errorf("expected %q, got %q", testValue, retrievedValue)
 	}
 
-	// Wait for TTL to expire
-	time.Sleep(150 * time.Millisecond)
+	// Let the TTL expire
+	srv.FastForward(150 * time.Millisecond)
 
 	// Should be expired now
 	expiredValue, err := client.Get(ctx, testKey)
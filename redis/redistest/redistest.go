@@ -0,0 +1,73 @@
+// Package redistest starts an embedded, in-memory Redis server for tests and returns a
+// redis client connected to it, so that tests run without a real server. Time on the
+// server only moves when told to, which makes TTL tests fast and deterministic.
+package redistest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+// Server is the embedded server behind a client returned by New.
+type Server struct {
+	mr *miniredis.Miniredis
+}
+
+// New starts an embedded server and returns a client connected to it, configured
+// with opts, and the server. Both are closed when the test ends.
+//
+// Example:
+//
+//	func TestSessions(t *testing.T) {
+//	    r, srv := redistest.New(t, redis.KeyPrefix("app:"))
+//	    store := NewSessionStore(r)
+//
+//	    _ = store.Save(ctx, session, time.Minute)
+//	    srv.FastForward(2 * time.Minute)
+//	    ...
+//	}
+func New(tb testing.TB, opts ...redis.Options) (*redis.Redis, *Server) {
+	tb.Helper()
+
+	mr := miniredis.RunT(tb)
+
+	r, err := redis.New(mr.Addr(), "", "", opts...)
+	if err != nil {
+		tb.Fatalf("redistest - New - redis.New: %v", err)
+	}
+
+	tb.Cleanup(r.Close)
+
+	return r, &Server{mr: mr}
+}
+
+// Addr returns the address of the server, e.g. for clients created with redis.New.
+func (s *Server) Addr() string {
+	return s.mr.Addr()
+}
+
+// FastForward moves the time of the server forward by d, expiring the keys whose TTL
+// elapses.
+func (s *Server) FastForward(d time.Duration) {
+	s.mr.FastForward(d)
+}
+
+// SetTime sets the time returned by the TIME command and used by commands taking
+// absolute expiry times, such as EXPIREAT. It does not expire keys: use FastForward.
+func (s *Server) SetTime(t time.Time) {
+	s.mr.SetTime(t)
+}
+
+// FlushAll removes every key, e.g. between subtests.
+func (s *Server) FlushAll() {
+	s.mr.FlushAll()
+}
+
+// Miniredis returns the underlying miniredis server, to inspect keys directly or
+// simulate errors with SetError.
+func (s *Server) Miniredis() *miniredis.Miniredis {
+	return s.mr
+}
@@ -0,0 +1,50 @@
+package redistest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestNew(t *testing.T) {
+	r, srv := redistest.New(t, redis.TTL(time.Minute), redis.KeyPrefix("app:"))
+
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if got, err := srv.Miniredis().Get("app:key"); err != nil || got != "value" {
+		t.Errorf("server value = %q, %v, want value", got, err)
+	}
+
+	if ttl, err := r.TTL(ctx, "key"); err != nil || ttl != time.Minute {
+		t.Errorf("TTL() = %v, %v, want 1m", ttl, err)
+	}
+
+	srv.FastForward(30 * time.Second)
+
+	if v, err := r.Get(ctx, "key"); err != nil || v != "value" {
+		t.Errorf("Get() after 30s = %q, %v, want value", v, err)
+	}
+
+	srv.FastForward(30 * time.Second)
+
+	if v, err := r.Get(ctx, "key"); err != nil || v != "" {
+		t.Errorf("Get() after expiry = %q, %v, want empty", v, err)
+	}
+
+	if err := r.Set(ctx, "other", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	srv.FlushAll()
+
+	if ok, err := r.Exists(ctx, "other"); err != nil || ok {
+		t.Errorf("Exists() after FlushAll = %v, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Script is a Lua script run atomically on the server. Scripts are sent by SHA1 with
+// EVALSHA, and in full with EVAL only when the server doesn't have them cached yet,
+// e.g. after a restart or a failover.
+type Script struct {
+	r      *Redis
+	script *redis.Script
+}
+
+// NewScript creates a Script running src with r. Create scripts once, e.g. as fields
+// of a repository, rather than per call.
+//
+// Example:
+//
+//	// Move a job from the pending set to the processing hash atomically.
+//	claim := r.NewScript(`
+//	local job = redis.call("ZPOPMIN", KEYS[1])
+//	if #job == 0 then return false end
+//	redis.call("HSET", KEYS[2], job[1], ARGV[1])
+//	return job[1]
+//	`)
+//
+//	job, err := claim.Run(ctx, []string{"jobs:pending", "jobs:processing"}, workerID)
+func (r *Redis) NewScript(src string) *Script {
+	return &Script{r: r, script: redis.NewScript(src)}
+}
+
+// Load caches the script on the server, or on every master of a cluster, so that the
+// first Run doesn't send it. Loading is optional.
+func (s *Script) Load(ctx context.Context) error {
+	if err := s.script.Load(ctx, s.r.client).Err(); err != nil {
+		return fmt.Errorf("redis - Script - Load: %w", err)
+	}
+
+	return nil
+}
+
+// Run runs the script with KEYS set to keys, prefixed by the KeyPrefix option, and ARGV
+// set to args, and returns its reply: nil for a Lua false or nil, int64, string, or
+// []any for a table. In a cluster all keys must map to the same hash slot.
+func (s *Script) Run(ctx context.Context, keys []string, args ...any) (any, error) {
+	val, err := s.script.Run(ctx, s.r.client, keys, args...).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("redis - Script - Run: %w", err)
+	}
+
+	return val, nil
+}
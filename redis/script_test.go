@@ -0,0 +1,57 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestScript_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	script := client.NewScript(`return 1`)
+
+	if err := script.Load(ctx); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := script.Run(ctx, nil); err == nil {
+		t.Error("expected Run to fail without connection")
+	}
+}
+
+func TestScript_Integration(t *testing.T) {
+	client, _ := redistest.New(t, redis.KeyPrefix("test-script:"))
+
+	ctx := context.Background()
+
+	// Flush the script cache so that the first Run falls back to EVAL.
+	if err := client.Client().ScriptFlush(ctx).Err(); err != nil {
+		t.Fatalf("ScriptFlush() error = %v", err)
+	}
+
+	incr := client.NewScript(`return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+
+	if v, err := incr.Run(ctx, []string{"counter"}, 2); err != nil || v != int64(2) {
+		t.Errorf("Run() = %v, %v, want 2", v, err)
+	}
+
+	if err := incr.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if v, err := incr.Run(ctx, []string{"counter"}, 3); err != nil || v != int64(5) {
+		t.Errorf("Run() = %v, %v, want 5", v, err)
+	}
+
+	if v, err := client.NewScript(`return false`).Run(ctx, nil); err != nil || v != nil {
+		t.Errorf("Run() = %v, %v, want nil", v, err)
+	}
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Z is a member of a sorted set with its score.
+type Z struct {
+	Member string
+	Score  float64
+}
+
+// SAdd adds members to the set at key, creating it if needed, and returns the number
+// of members that were not already in it.
+func (r *Redis) SAdd(ctx context.Context, key string, members ...string) (int64, error) {
+	n, err := r.client.SAdd(ctx, key, toArgs(members)...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - SAdd: %w", err)
+	}
+
+	return n, nil
+}
+
+// SMembers returns the members of the set at key in no particular order, none if it
+// doesn't exist.
+func (r *Redis) SMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis - SMembers: %w", err)
+	}
+
+	return members, nil
+}
+
+// SIsMember reports whether member is in the set at key.
+func (r *Redis) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	ok, err := r.client.SIsMember(ctx, key, member).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis - SIsMember: %w", err)
+	}
+
+	return ok, nil
+}
+
+// ZAdd adds members to the sorted set at key, or updates their score, and returns the
+// number of members added.
+//
+// Example:
+//
+//	// Index events by time to query a window with ZRangeByScore.
+//	_, err := r.ZAdd(ctx, "events:by_time", redis.Z{Member: eventID, Score: float64(at.Unix())})
+func (r *Redis) ZAdd(ctx context.Context, key string, members ...Z) (int64, error) {
+	zs := make([]redis.Z, len(members))
+	for i, m := range members {
+		zs[i] = redis.Z{Member: m.Member, Score: m.Score}
+	}
+
+	n, err := r.client.ZAdd(ctx, key, zs...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - ZAdd: %w", err)
+	}
+
+	return n, nil
+}
+
+// ZRangeByScore returns the members of the sorted set at key with a score between
+// minScore and maxScore, both included, by ascending score. Infinite bounds leave the
+// range open, e.g. math.Inf(1) for no maximum.
+func (r *Redis) ZRangeByScore(ctx context.Context, key string, minScore, maxScore float64) ([]Z, error) {
+	zs, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: formatScore(minScore),
+		Max: formatScore(maxScore),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis - ZRangeByScore: %w", err)
+	}
+
+	members := make([]Z, len(zs))
+	for i, z := range zs {
+		members[i] = Z{Member: fmt.Sprint(z.Member), Score: z.Score}
+	}
+
+	return members, nil
+}
+
+// ZIncrBy adds incr to the score of member in the sorted set at key, starting from
+// zero, and returns the new score.
+//
+// Example:
+//
+//	// Leaderboard.
+//	score, err := r.ZIncrBy(ctx, "leaderboard", playerID, points)
+func (r *Redis) ZIncrBy(ctx context.Context, key, member string, incr float64) (float64, error) {
+	score, err := r.client.ZIncrBy(ctx, key, incr, member).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - ZIncrBy: %w", err)
+	}
+
+	return score, nil
+}
+
+func formatScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+}
@@ -0,0 +1,69 @@
+package redis_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_Set_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if _, err := client.SAdd(ctx, "set", "a"); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.ZAdd(ctx, "zset", redis.Z{Member: "a", Score: 1}); err == nil {
+		t.Error("expected ZAdd to fail without connection")
+	}
+
+	if _, err := client.ZRangeByScore(ctx, "zset", math.Inf(-1), math.Inf(1)); err == nil {
+		t.Error("expected ZRangeByScore to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationSet(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	set, zset := "test-set-key", "test-zset-key"
+
+	if n, err := client.SAdd(ctx, set, "go", "redis", "go"); err != nil || n != 2 {
+		t.Errorf("SAdd() = %d, %v, want 2", n, err)
+	}
+
+	members, err := client.SMembers(ctx, set)
+	sort.Strings(members)
+
+	if err != nil || !reflect.DeepEqual(members, []string{"go", "redis"}) {
+		t.Errorf("SMembers() = %v, %v, want [go redis]", members, err)
+	}
+
+	if ok, err := client.SIsMember(ctx, set, "rust"); err != nil || ok {
+		t.Errorf("SIsMember() = %v, %v, want false", ok, err)
+	}
+
+	if n, err := client.ZAdd(ctx, zset, redis.Z{Member: "alice", Score: 10}, redis.Z{Member: "bob", Score: 20}); err != nil || n != 2 {
+		t.Errorf("ZAdd() = %d, %v, want 2", n, err)
+	}
+
+	if score, err := client.ZIncrBy(ctx, zset, "alice", 15); err != nil || score != 25 {
+		t.Errorf("ZIncrBy() = %v, %v, want 25", score, err)
+	}
+
+	want := []redis.Z{{Member: "bob", Score: 20}, {Member: "alice", Score: 25}}
+	if zs, err := client.ZRangeByScore(ctx, zset, 15, math.Inf(1)); err != nil || !reflect.DeepEqual(zs, want) {
+		t.Errorf("ZRangeByScore() = %v, %v, want %v", zs, err, want)
+	}
+}
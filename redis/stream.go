@@ -0,0 +1,404 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	_defaultStreamBatchSize   = 10
+	_defaultStreamConcurrency = 10
+	_defaultStreamBlock       = time.Second
+	_defaultStreamClaimIdle   = time.Minute
+	_defaultStreamStartID     = "$"
+	_streamErrorBackoff       = time.Second
+)
+
+// StreamMessage is an entry of a stream delivered to a consumer group.
+type StreamMessage struct {
+	ID     string
+	Stream string
+	Values map[string]string
+	// Claimed reports an entry taken over from the pending entries of a consumer that
+	// did not acknowledge it in time, including this one after a failed handler.
+	Claimed bool
+}
+
+// StreamHandler handles a stream entry. Returning nil acknowledges it; on error it
+// stays pending and is delivered again once claimed.
+type StreamHandler func(ctx context.Context, m StreamMessage) error
+
+// XAdd appends an entry with values to stream, creating it if needed, and returns
+// the ID assigned by the server.
+func (r *Redis) XAdd(ctx context.Context, stream string, values map[string]any) (string, error) {
+	id, err := r.client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: values}).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis - XAdd: %w", err)
+	}
+
+	return id, nil
+}
+
+// XAck acknowledges entries of stream delivered to group, removing them from its
+// pending entries, and returns how many were pending.
+func (r *Redis) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	n, err := r.client.XAck(ctx, stream, group, ids...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis - XAck: %w", err)
+	}
+
+	return n, nil
+}
+
+// StreamOption configures a StreamConsumer.
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	consumer    string
+	batchSize   int
+	concurrency int
+	block       time.Duration
+	claimIdle   time.Duration
+	startID     string
+	logger      logger.LoggerI
+}
+
+func newStreamConfig(opts []StreamOption) streamConfig {
+	cfg := streamConfig{
+		consumer:    uuid.NewString(),
+		batchSize:   _defaultStreamBatchSize,
+		concurrency: _defaultStreamConcurrency,
+		block:       _defaultStreamBlock,
+		claimIdle:   _defaultStreamClaimIdle,
+		startID:     _defaultStreamStartID,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// StreamConsumerName sets the name of the consumer within its group. A stable name,
+// e.g. the pod name, lets a restarted consumer resume its own pending entries.
+// Default is a random UUID.
+func StreamConsumerName(name string) StreamOption {
+	return func(c *streamConfig) {
+		if name != "" {
+			c.consumer = name
+		}
+	}
+}
+
+// StreamBatchSize sets the maximum number of entries read or claimed per request.
+// Default is 10.
+func StreamBatchSize(n int) StreamOption {
+	return func(c *streamConfig) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// StreamConcurrency sets the maximum number of entries handled at the same time.
+// Default is 10.
+func StreamConcurrency(n int) StreamOption {
+	return func(c *streamConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// StreamBlock sets how long a read waits for new entries, which also bounds how long
+// Shutdown waits for the current read.
+// Default is 1 second.
+func StreamBlock(d time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		if d > 0 {
+			c.block = d
+		}
+	}
+}
+
+// StreamClaimIdle sets how long an entry stays pending, unacknowledged by the consumer
+// it was delivered to, before this consumer claims it. Pending entries are claimed on
+// Start and then every half of d.
+// Default is 1 minute.
+func StreamClaimIdle(d time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		if d > 0 {
+			c.claimIdle = d
+		}
+	}
+}
+
+// StreamStartID sets the ID after which a group created by NewStreamConsumer starts
+// reading, e.g. "0" to handle the entries already in the stream. It is ignored when
+// the group exists.
+// Default is "$", only new entries.
+func StreamStartID(id string) StreamOption {
+	return func(c *streamConfig) {
+		c.startID = id
+	}
+}
+
+// StreamLogger sets the logger reporting handler and stream errors.
+// Default is nil: stream errors are only reported through Notify.
+func StreamLogger(l logger.LoggerI) StreamOption {
+	return func(c *streamConfig) {
+		c.logger = l
+	}
+}
+
+// StreamConsumer reads a stream as a member of a consumer group and handles its
+// entries concurrently, acknowledging those handled and claiming entries left pending
+// by failed handlers or crashed consumers. Delivery is at least once, so handlers must
+// be idempotent. Claiming requires Redis 6.2 or later.
+type StreamConsumer struct {
+	client redis.UniversalClient
+	stream string
+	key    string
+	group  string
+	h      StreamHandler
+	cfg    streamConfig
+
+	notify chan error
+	stop   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	once   sync.Once
+}
+
+// NewStreamConsumer creates a consumer of stream in group handling entries with h.
+// The group, and the stream, are created if they don't exist.
+//
+// Example:
+//
+//	c, err := redis.NewStreamConsumer(r, "orders", "billing", func(ctx context.Context, m redis.StreamMessage) error {
+//	    return svc.Bill(ctx, m.Values["order_id"])
+//	}, redis.StreamConsumerName(hostname), redis.StreamLogger(l))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	c.Start()
+//	defer c.Shutdown()
+func NewStreamConsumer(r *Redis, stream, group string, h StreamHandler, opts ...StreamOption) (*StreamConsumer, error) {
+	c := &StreamConsumer{
+		client: r.client,
+		stream: stream,
+		key:    stream,
+		group:  group,
+		h:      h,
+		cfg:    newStreamConfig(opts),
+		notify: make(chan error, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	err := c.client.XGroupCreateMkStream(context.Background(), c.key, group, c.cfg.startID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("redis - NewStreamConsumer - XGroupCreateMkStream: %w", err)
+	}
+
+	return c, nil
+}
+
+// Start begins consuming in a separate goroutine.
+// Use Notify() to receive stream errors.
+func (c *StreamConsumer) Start() {
+	c.start.Do(func() {
+		go c.run()
+	})
+}
+
+// Notify returns a channel that receives stream errors, such as failed reads or
+// acknowledgements. Handler errors are not reported. Errors are dropped while a
+// previous one has not been received.
+func (c *StreamConsumer) Notify() <-chan error {
+	return c.notify
+}
+
+// Shutdown stops reading, waits for in-flight handlers and acknowledges the entries
+// they handled.
+func (c *StreamConsumer) Shutdown() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+
+	// Start may never have been called.
+	c.start.Do(func() { close(c.done) })
+
+	<-c.done
+
+	return nil
+}
+
+func (c *StreamConsumer) run() {
+	defer close(c.done)
+
+	var nextClaim time.Time
+
+	for !c.stopped() {
+		if now := time.Now(); !now.Before(nextClaim) {
+			c.claim()
+
+			nextClaim = now.Add(c.cfg.claimIdle / 2)
+		}
+
+		// Reads are not bound to Shutdown, which waits for them up to the block time.
+		streams, err := c.client.XReadGroup(context.Background(), &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.cfg.consumer,
+			Streams:  []string{c.key, ">"},
+			Count:    int64(c.cfg.batchSize),
+			Block:    c.cfg.block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			c.fail(fmt.Errorf("redis - StreamConsumer - XReadGroup: %w", err))
+
+			continue
+		}
+
+		for _, s := range streams {
+			c.process(s.Messages, false)
+		}
+	}
+}
+
+// claim takes over and handles the entries pending for longer than the claim idle time.
+func (c *StreamConsumer) claim() {
+	start := "0-0"
+
+	for !c.stopped() {
+		msgs, next, err := c.client.XAutoClaim(context.Background(), &redis.XAutoClaimArgs{
+			Stream:   c.key,
+			Group:    c.group,
+			Consumer: c.cfg.consumer,
+			MinIdle:  c.cfg.claimIdle,
+			Start:    start,
+			Count:    int64(c.cfg.batchSize),
+		}).Result()
+		if err != nil {
+			c.fail(fmt.Errorf("redis - StreamConsumer - XAutoClaim: %w", err))
+
+			return
+		}
+
+		c.process(msgs, true)
+
+		if next == "0-0" || next == "" {
+			return
+		}
+
+		start = next
+	}
+}
+
+// process handles msgs with bounded concurrency and acknowledges those handled.
+func (c *StreamConsumer) process(msgs []redis.XMessage, claimed bool) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	slots := make(chan struct{}, c.cfg.concurrency)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids []string
+	)
+
+	for _, msg := range msgs {
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if c.handle(msg, claimed) {
+				mu.Lock()
+				ids = append(ids, msg.ID)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(ids) == 0 {
+		return
+	}
+
+	if err := c.client.XAck(context.Background(), c.key, c.group, ids...).Err(); err != nil {
+		c.report(fmt.Errorf("redis - StreamConsumer - XAck: %w", err))
+	}
+}
+
+// handle runs the handler and reports whether the entry must be acknowledged.
+// Handlers are not cancelled by Shutdown so that they can complete.
+func (c *StreamConsumer) handle(msg redis.XMessage, claimed bool) bool {
+	values := make(map[string]string, len(msg.Values))
+	for k, v := range msg.Values {
+		values[k] = fmt.Sprint(v)
+	}
+
+	err := c.h(context.Background(), StreamMessage{ID: msg.ID, Stream: c.stream, Values: values, Claimed: claimed})
+	if err != nil {
+		c.logError("redis - StreamConsumer - handler %s: %v", msg.ID, err)
+
+		return false
+	}
+
+	return true
+}
+
+func (c *StreamConsumer) stopped() bool {
+	select {
+	case <-c.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// fail reports err and waits before the next request, unless stopped.
+func (c *StreamConsumer) fail(err error) {
+	c.report(err)
+
+	select {
+	case <-c.stop:
+	case <-time.After(_streamErrorBackoff):
+	}
+}
+
+func (c *StreamConsumer) report(err error) {
+	c.logError(err)
+
+	select {
+	case c.notify <- err:
+	default:
+	}
+}
+
+func (c *StreamConsumer) logError(message interface{}, args ...interface{}) {
+	if c.cfg.logger != nil {
+		c.cfg.logger.Error(message, args...)
+	}
+}
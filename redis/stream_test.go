@@ -0,0 +1,121 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_Stream_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if _, err := client.XAdd(ctx, "stream", map[string]any{"k": "v"}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.XAck(ctx, "stream", "group", "0-1"); err == nil {
+		t.Error("expected XAck to fail without connection")
+	}
+
+	h := func(context.Context, redis.StreamMessage) error { return nil }
+	if _, err := redis.NewStreamConsumer(client, "stream", "group", h); err == nil {
+		t.Error("expected NewStreamConsumer to fail without connection")
+	}
+}
+
+func TestRedis_IntegrationStreamConsumer(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	ctx := context.Background()
+	stream := "test-stream-key"
+
+	var failed atomic.Bool
+
+	got := make(chan redis.StreamMessage, 2)
+	h := func(_ context.Context, m redis.StreamMessage) error {
+		// Fail the first delivery to leave the entry pending until it is claimed.
+		if !failed.Swap(true) {
+			return errors.New("first delivery")
+		}
+
+		got <- m
+
+		return nil
+	}
+
+	c, err := redis.NewStreamConsumer(client, stream, "test-group", h,
+		redis.StreamStartID("0"),
+		redis.StreamBlock(100*time.Millisecond),
+		redis.StreamClaimIdle(200*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	id, err := client.XAdd(ctx, stream, map[string]any{"order": "42"})
+	if err != nil {
+		t.Fatalf("XAdd() error = %v", err)
+	}
+
+	c.Start()
+
+	select {
+	case m := <-got:
+		if m.ID != id || m.Values["order"] != "42" || !m.Claimed {
+			t.Errorf("message = %+v, want claimed %s with order 42", m, id)
+		}
+	case err := <-c.Notify():
+		t.Fatalf("consumer error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not redelivered")
+	}
+
+	if err := c.Shutdown(); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+
+	if n, err := client.XAck(ctx, stream, "test-group", id); err != nil || n != 0 {
+		t.Errorf("XAck() = %d, %v, want already acknowledged", n, err)
+	}
+}
+
+func TestRedis_StreamConsumerLifecycle(t *testing.T) {
+	client, _ := redistest.New(t)
+
+	h := func(context.Context, redis.StreamMessage) error { return nil }
+
+	idle, err := redis.NewStreamConsumer(client, "lifecycle", "group", h)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	// Shutdown returns when Start was never called, and Start then does nothing.
+	if err := idle.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() without Start error = %v", err)
+	}
+
+	idle.Start()
+
+	c, err := redis.NewStreamConsumer(client, "lifecycle", "group", h, redis.StreamBlock(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	c.Start()
+	c.Start()
+
+	if err := c.Shutdown(); err != nil {
+		t.Errorf("Shutdown() after a double Start error = %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Content types commonly passed to SetTyped.
+const (
+	ContentTypeJSON   = "application/json"
+	ContentTypeBytes  = "application/octet-stream"
+	ContentTypeString = "text/plain"
+)
+
+// _typedMagic starts the values written by SetTyped, followed by the length of the
+// content type on one byte, the content type and the data.
+const _typedMagic = "\x00ct"
+
+// SetBytes stores a binary value under key with the default TTL.
+func (r *Redis) SetBytes(ctx context.Context, key string, value []byte) error {
+	if err := r.client.Set(ctx, key, value, r.ttl).Err(); err != nil {
+		return fmt.Errorf("redis - SetBytes: %w", err)
+	}
+
+	return nil
+}
+
+// GetBytes retrieves the binary value of key.
+// Returns nil and nil error if key doesn't exist.
+func (r *Redis) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	val, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("redis - GetBytes: %w", err)
+	}
+
+	return val, nil
+}
+
+// SetJSON stores v encoded as JSON under key with the default TTL.
+//
+// Example:
+//
+//	err := r.SetJSON(ctx, "session:"+id, Session{UserID: 42, Roles: []string{"admin"}})
+//
+//	var s Session
+//	found, err := r.GetJSON(ctx, "session:"+id, &s)
+func (r *Redis) SetJSON(ctx context.Context, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("redis - SetJSON - json.Marshal: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		return fmt.Errorf("redis - SetJSON: %w", err)
+	}
+
+	return nil
+}
+
+// GetJSON decodes the JSON value of key into dest. It reports false, leaving dest
+// unchanged, when the key doesn't exist.
+func (r *Redis) GetJSON(ctx context.Context, key string, dest any) (bool, error) {
+	data, err := r.GetBytes(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("redis - GetJSON - %w", err)
+	}
+
+	if data == nil {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, fmt.Errorf("redis - GetJSON - json.Unmarshal: %w", err)
+	}
+
+	return true, nil
+}
+
+// SetTyped stores data tagged with its content type under key with the default TTL,
+// for keyspaces holding values of several encodings. Read them back with GetTyped;
+// other readers see the tag in front of the data.
+func (r *Redis) SetTyped(ctx context.Context, key, contentType string, data []byte) error {
+	if len(contentType) > 255 {
+		return fmt.Errorf("redis - SetTyped: content type longer than 255 bytes: %q", contentType)
+	}
+
+	value := make([]byte, 0, len(_typedMagic)+1+len(contentType)+len(data))
+	value = append(value, _typedMagic...)
+	value = append(value, byte(len(contentType)))
+	value = append(value, contentType...)
+	value = append(value, data...)
+
+	if err := r.client.Set(ctx, key, value, r.ttl).Err(); err != nil {
+		return fmt.Errorf("redis - SetTyped: %w", err)
+	}
+
+	return nil
+}
+
+// GetTyped retrieves the value of key with its content type. A value not written by
+// SetTyped is returned whole with an empty content type.
+// Returns an empty content type and nil data if key doesn't exist.
+//
+// Example:
+//
+//	contentType, data, err := r.GetTyped(ctx, key)
+//	switch contentType {
+//	case redis.ContentTypeJSON:
+//	    err = json.Unmarshal(data, &v)
+//	case "application/x-protobuf":
+//	    err = proto.Unmarshal(data, &msg)
+//	}
+func (r *Redis) GetTyped(ctx context.Context, key string) (contentType string, data []byte, err error) {
+	value, err := r.GetBytes(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("redis - GetTyped - %w", err)
+	}
+
+	contentType, data = splitTyped(value)
+
+	return contentType, data, nil
+}
+
+// splitTyped splits a value written by SetTyped into its content type and data.
+func splitTyped(value []byte) (contentType string, data []byte) {
+	rest, ok := bytes.CutPrefix(value, []byte(_typedMagic))
+	if !ok || len(rest) == 0 || len(rest) < 1+int(rest[0]) {
+		return "", value
+	}
+
+	n := int(rest[0])
+
+	return string(rest[1 : 1+n]), rest[1+n:]
+}
@@ -0,0 +1,111 @@
+package redis_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/redis/redistest"
+)
+
+func TestRedis_Values_NoConnection(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.SetBytes(ctx, "key", []byte{1}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	if _, err := client.GetBytes(ctx, "key"); err == nil {
+		t.Error("expected GetBytes to fail without connection")
+	}
+
+	if err := client.SetJSON(ctx, "key", map[string]int{"a": 1}); err == nil {
+		t.Error("expected SetJSON to fail without connection")
+	}
+
+	var v map[string]int
+	if _, err := client.GetJSON(ctx, "key", &v); err == nil {
+		t.Error("expected GetJSON to fail without connection")
+	}
+
+	if _, _, err := client.GetTyped(ctx, "key"); err == nil {
+		t.Error("expected GetTyped to fail without connection")
+	}
+}
+
+func TestRedis_SetJSON_MarshalError(t *testing.T) {
+	client, err := redis.New("127.0.0.1:65432", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.SetJSON(context.Background(), "key", make(chan int)); err == nil || !strings.Contains(err.Error(), "json.Marshal") {
+		t.Errorf("SetJSON() error = %v, want json.Marshal error", err)
+	}
+
+	if err := client.SetTyped(context.Background(), "key", strings.Repeat("x", 256), nil); err == nil {
+		t.Error("expected SetTyped to reject a content type longer than 255 bytes")
+	}
+}
+
+func TestRedis_IntegrationValues(t *testing.T) {
+	client, _ := redistest.New(t, redis.KeyPrefix("test-values:"))
+
+	ctx := context.Background()
+
+	blob := []byte{0, 1, 2, 0xff}
+	if err := client.SetBytes(ctx, "bytes", blob); err != nil {
+		t.Fatalf("SetBytes() error = %v", err)
+	}
+
+	if got, err := client.GetBytes(ctx, "bytes"); err != nil || !bytes.Equal(got, blob) {
+		t.Errorf("GetBytes() = %v, %v, want %v", got, err, blob)
+	}
+
+	if got, err := client.GetBytes(ctx, "missing"); err != nil || got != nil {
+		t.Errorf("GetBytes() missing = %v, %v, want nil", got, err)
+	}
+
+	type session struct {
+		UserID int      `json:"user_id"`
+		Roles  []string `json:"roles"`
+	}
+
+	if err := client.SetJSON(ctx, "json", session{UserID: 42, Roles: []string{"admin"}}); err != nil {
+		t.Fatalf("SetJSON() error = %v", err)
+	}
+
+	var s session
+	if found, err := client.GetJSON(ctx, "json", &s); err != nil || !found || s.UserID != 42 || len(s.Roles) != 1 {
+		t.Errorf("GetJSON() = %+v, %v, %v", s, found, err)
+	}
+
+	if found, err := client.GetJSON(ctx, "missing", &s); err != nil || found {
+		t.Errorf("GetJSON() missing = %v, %v, want false", found, err)
+	}
+
+	if err := client.SetTyped(ctx, "typed", redis.ContentTypeJSON, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("SetTyped() error = %v", err)
+	}
+
+	if ct, data, err := client.GetTyped(ctx, "typed"); err != nil || ct != redis.ContentTypeJSON || string(data) != `{"a":1}` {
+		t.Errorf("GetTyped() = %q, %q, %v", ct, data, err)
+	}
+
+	if err := client.Set(ctx, "plain", "hello"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if ct, data, err := client.GetTyped(ctx, "plain"); err != nil || ct != "" || string(data) != "hello" {
+		t.Errorf("GetTyped() untagged = %q, %q, %v, want hello", ct, data, err)
+	}
+}
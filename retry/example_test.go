@@ -0,0 +1,43 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+// Example demonstrates retrying a flaky operation with exponential backoff
+func Example() {
+	calls := 0
+
+	err := retry.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("service unavailable")
+		}
+
+		return nil
+	},
+		retry.Attempts(5),
+		retry.ExponentialBackoff(10*time.Millisecond, time.Second),
+		retry.Jitter(0.2),
+	)
+
+	fmt.Println(calls, err)
+
+	// Output: 3 <nil>
+}
+
+// ExamplePermanent demonstrates stopping retries on a non-recoverable error
+func ExamplePermanent() {
+	err := retry.Do(context.Background(), func(context.Context) error {
+		return retry.Permanent(errors.New("invalid credentials"))
+	}, retry.Attempts(5))
+
+	fmt.Println(err)
+
+	// Output: invalid credentials
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+// Option configures a retry.
+type Option func(*config)
+
+// Attempts sets the maximum number of calls, including the first one.
+// Values below 1 are treated as 1.
+// Default is 3.
+func Attempts(attempts int) Option {
+	return func(c *config) {
+		c.attempts = attempts
+	}
+}
+
+// ConstantBackoff waits the same delay between attempts.
+func ConstantBackoff(delay time.Duration) Option {
+	return func(c *config) {
+		c.backoff = Constant(delay)
+	}
+}
+
+// ExponentialBackoff doubles the delay after every retry, starting at initial
+// and capped at maxDelay. A zero maxDelay means no cap.
+// Default is 100ms up to 10 seconds.
+func ExponentialBackoff(initial, maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.backoff = Exponential(initial, maxDelay)
+	}
+}
+
+// WithBackoff sets a custom backoff strategy.
+func WithBackoff(b Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
+
+// Jitter randomizes every delay by up to ±factor of its value (e.g. 0.2 for ±20%),
+// to avoid synchronized retries from many clients. Factor is clamped to [0, 1].
+// Default is no jitter.
+func Jitter(factor float64) Option {
+	return func(c *config) {
+		c.jitter = min(max(factor, 0), 1)
+	}
+}
+
+// RetryIf sets a predicate deciding whether an error is retried.
+// Errors for which it returns false are returned immediately.
+// Default retries every error.
+func RetryIf(fn func(error) bool) Option {
+	return func(c *config) {
+		c.retryIf = fn
+	}
+}
+
+// OnRetry registers a callback invoked after every failed attempt that will be retried,
+// with the attempt number (starting at 1), its error and the delay before the next attempt.
+func OnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *config) {
+		c.onRetry = fn
+	}
+}
+
+// WithClock sets the clock used to wait between attempts, e.g. a *clock.Fake in tests.
+// Default is clock.Real().
+func WithClock(c clock.Clock) Option {
+	return func(cfg *config) {
+		if c != nil {
+			cfg.clock = c
+		}
+	}
+}
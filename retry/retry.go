@@ -0,0 +1,214 @@
+// Package retry provides a reusable retry helper with constant or exponential backoff,
+// jitter, retry predicates and context cancellation.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+const (
+	_defaultAttempts     = 3
+	_defaultInitialDelay = 100 * time.Millisecond
+	_defaultMaxDelay     = 10 * time.Second
+)
+
+// Func is an operation retried by Do.
+type Func func(ctx context.Context) error
+
+// Backoff returns the delay before the given retry. Retry numbers start at 1.
+type Backoff func(retry int) time.Duration
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do stops retrying and returns it immediately.
+// The wrapped error remains reachable with errors.Is and errors.As.
+// Permanent(nil) returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+
+	return errors.As(err, &p)
+}
+
+type config struct {
+	attempts int
+	backoff  Backoff
+	jitter   float64
+	retryIf  func(error) bool
+	onRetry  func(attempt int, err error, delay time.Duration)
+	clock    clock.Clock
+}
+
+// Do calls fn until it succeeds, the attempts are exhausted, fn returns an error
+// rejected by RetryIf or wrapped with Permanent, or ctx is done.
+// It returns nil on success and the last error of fn otherwise. When ctx is done while
+// waiting between attempts, the returned error wraps both ctx.Err() and the last error.
+// Default configuration: 3 attempts, exponential backoff from 100ms up to 10 seconds,
+// every error is retried.
+//
+// Example:
+//
+//	err := retry.Do(ctx, func(ctx context.Context) error {
+//	    return client.Ping(ctx)
+//	},
+//	    retry.Attempts(5),
+//	    retry.ExponentialBackoff(200*time.Millisecond, 5*time.Second),
+//	    retry.RetryIf(isTemporary),
+//	)
+func Do(ctx context.Context, fn Func, opts ...Option) error {
+	cfg := config{
+		attempts: _defaultAttempts,
+		backoff:  Exponential(_defaultInitialDelay, _defaultMaxDelay),
+		clock:    clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.attempts < 1 {
+		cfg.attempts = 1
+	}
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				return ctxErr
+			}
+
+			return fmt.Errorf("retry - Do - %w: %w", ctxErr, err)
+		}
+
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= cfg.attempts || IsPermanent(err) || (cfg.retryIf != nil && !cfg.retryIf(err)) {
+			return err
+		}
+
+		delay := cfg.delay(attempt)
+
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt, err, delay)
+		}
+
+		timer := cfg.clock.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("retry - Do - %w: %w", ctx.Err(), err)
+		case <-timer.C():
+		}
+	}
+}
+
+// DoValue is like Do for operations returning a value.
+// The value of the last successful call is returned.
+//
+// Example:
+//
+//	user, err := retry.DoValue(ctx, func(ctx context.Context) (User, error) {
+//	    return repo.Get(ctx, id)
+//	}, retry.Attempts(3))
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	var value T
+
+	err := Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		value, err = fn(ctx)
+
+		return err
+	}, opts...)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return value, nil
+}
+
+func (c *config) delay(retry int) time.Duration {
+	d := c.backoff(retry)
+
+	if c.jitter > 0 && d > 0 {
+		// Spread the delay uniformly over [d*(1-jitter), d*(1+jitter)].
+		spread := float64(d) * c.jitter
+		jittered := float64(d) - spread + rand.Float64()*2*spread //nolint:gosec // jitter does not need a secure source
+
+		d = time.Duration(math.MaxInt64)
+		if jittered < float64(math.MaxInt64) {
+			d = time.Duration(jittered)
+		}
+	}
+
+	if d < 0 {
+		d = 0
+	}
+
+	return d
+}
+
+// Constant returns a Backoff waiting the same delay before every retry.
+func Constant(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential returns a Backoff doubling the delay after every retry, starting at
+// initial and capped at maxDelay. A zero maxDelay means no cap, delays saturating at
+// the largest Duration. It also computes the delays of retries scheduled outside of
+// Do, e.g. in a database column.
+func Exponential(initial, maxDelay time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		d := initial
+		for i := 1; i < retry; i++ {
+			// Saturate instead of overflowing to a negative delay.
+			if d > math.MaxInt64/2 {
+				d = math.MaxInt64
+
+				break
+			}
+
+			d *= 2
+			if maxDelay > 0 && d >= maxDelay {
+				return maxDelay
+			}
+		}
+
+		if maxDelay > 0 && d > maxDelay {
+			return maxDelay
+		}
+
+		return d
+	}
+}
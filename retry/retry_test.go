@@ -0,0 +1,248 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+var errTemporary = errors.New("temporary")
+
+func TestDo(t *testing.T) {
+	errFatal := errors.New("fatal")
+
+	testCases := []struct {
+		name      string
+		failures  int
+		err       error
+		opts      []retry.Option
+		wantCalls int
+		wantErr   error
+	}{
+		{"succeeds first time", 0, errTemporary, nil, 1, nil},
+		{"succeeds after retries", 2, errTemporary, nil, 3, nil},
+		{"exhausts attempts", 10, errTemporary, []retry.Option{retry.Attempts(4)}, 4, errTemporary},
+		{"zero attempts calls once", 10, errTemporary, []retry.Option{retry.Attempts(0)}, 1, errTemporary},
+		{"permanent error stops", 10, retry.Permanent(errFatal), nil, 1, errFatal},
+		{
+			"RetryIf rejects error", 10, errFatal,
+			[]retry.Option{retry.RetryIf(func(err error) bool { return errors.Is(err, errTemporary) })},
+			1, errFatal,
+		},
+		{
+			"RetryIf accepts error", 1, errTemporary,
+			[]retry.Option{retry.RetryIf(func(err error) bool { return errors.Is(err, errTemporary) })},
+			2, nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			opts := append([]retry.Option{retry.ConstantBackoff(time.Millisecond)}, tc.opts...)
+
+			err := retry.Do(context.Background(), func(context.Context) error {
+				calls++
+				if calls <= tc.failures {
+					return tc.err
+				}
+
+				return nil
+			}, opts...)
+
+			if calls != tc.wantCalls {
+				t.Errorf("expected %d calls, got %d", tc.wantCalls, calls)
+			}
+
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Errorf("expected %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDo_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	err := retry.Do(ctx, func(context.Context) error {
+		return errTemporary
+	}, retry.Attempts(100), retry.ConstantBackoff(time.Hour))
+
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errTemporary) {
+		t.Errorf("expected deadline and last error, got: %v", err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Error("expected Do to stop waiting when ctx is done")
+	}
+
+	calls := 0
+	_ = retry.Do(ctx, func(context.Context) error {
+		calls++
+
+		return nil
+	})
+
+	if calls != 0 {
+		t.Error("expected fn not to be called with a done context")
+	}
+}
+
+func TestDo_WithClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewFake(start)
+
+	var calls atomic.Int32
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- retry.Do(context.Background(), func(context.Context) error {
+			calls.Add(1)
+
+			return errTemporary
+		}, retry.Attempts(3), retry.ConstantBackoff(time.Hour), retry.WithClock(c))
+	}()
+
+	for range 2 {
+		c.BlockUntil(1)
+		c.Advance(time.Hour)
+	}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errTemporary) {
+			t.Errorf("expected %v, got: %v", errTemporary, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Do to return after the fake clock advanced")
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+
+	if got := c.Since(start); got != 2*time.Hour {
+		t.Errorf("expected 2h of backoff, got %v", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var delays []time.Duration
+
+	_ = retry.Do(context.Background(), func(context.Context) error {
+		return errTemporary
+	},
+		retry.Attempts(6),
+		retry.WithBackoff(func(int) time.Duration { return 0 }),
+		retry.ExponentialBackoff(time.Microsecond, 8*time.Microsecond),
+		retry.OnRetry(func(attempt int, err error, delay time.Duration) {
+			if attempt != len(delays)+1 || !errors.Is(err, errTemporary) {
+				t.Errorf("unexpected OnRetry arguments: %d, %v", attempt, err)
+			}
+
+			delays = append(delays, delay)
+		}),
+	)
+
+	want := []time.Duration{1, 2, 4, 8, 8}
+	if len(delays) != len(want) {
+		t.Fatalf("expected %d retries, got %d", len(want), len(delays))
+	}
+
+	for i, d := range delays {
+		if d != want[i]*time.Microsecond {
+			t.Errorf("retry %d: expected %v, got %v", i+1, want[i]*time.Microsecond, d)
+		}
+	}
+}
+
+func TestExponential(t *testing.T) {
+	backoff := retry.Exponential(time.Second, 5*time.Second)
+
+	for retryNum, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := backoff(retryNum); got != want {
+			t.Errorf("Exponential()(%d) = %v, want %v", retryNum, got, want)
+		}
+	}
+
+	uncapped := retry.Exponential(time.Second, 0)
+
+	for _, retryNum := range []int{35, 64, 100, 1000} {
+		if got := uncapped(retryNum); got != math.MaxInt64 {
+			t.Errorf("Exponential() without cap (%d) = %v, want saturated at %v", retryNum, got, time.Duration(math.MaxInt64))
+		}
+	}
+
+	if got := retry.Constant(time.Second)(7); got != time.Second {
+		t.Errorf("Constant()(7) = %v, want %v", got, time.Second)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	base := 100 * time.Microsecond
+
+	_ = retry.Do(context.Background(), func(context.Context) error {
+		return errTemporary
+	},
+		retry.Attempts(20),
+		retry.ConstantBackoff(base),
+		retry.Jitter(0.5),
+		retry.OnRetry(func(_ int, _ error, delay time.Duration) {
+			if delay < base/2 || delay > base*3/2 {
+				t.Errorf("delay %v outside jitter bounds", delay)
+			}
+		}),
+	)
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+
+	v, err := retry.DoValue(context.Background(), func(context.Context) (int, error) {
+		calls++
+		if calls < 2 {
+			return -1, errTemporary
+		}
+
+		return 42, nil
+	}, retry.ConstantBackoff(0))
+	if err != nil || v != 42 {
+		t.Errorf("expected 42, got %d (%v)", v, err)
+	}
+
+	v, err = retry.DoValue(context.Background(), func(context.Context) (int, error) {
+		return 7, errTemporary
+	}, retry.Attempts(1))
+	if err == nil || v != 0 {
+		t.Errorf("expected zero value and error, got %d (%v)", v, err)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if retry.Permanent(nil) != nil {
+		t.Error("expected Permanent(nil) to be nil")
+	}
+
+	err := retry.Permanent(errTemporary)
+	if !retry.IsPermanent(err) || !errors.Is(err, errTemporary) {
+		t.Error("expected permanent error wrapping the original")
+	}
+
+	if retry.IsPermanent(errTemporary) {
+		t.Error("expected plain error not to be permanent")
+	}
+}
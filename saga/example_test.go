@@ -0,0 +1,55 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/eventbus"
+	"github.com/rdashevsky/go-pkgs/saga"
+)
+
+func ExampleNew() {
+	type transfer struct {
+		Amount int `json:"amount"`
+	}
+
+	step := func(name string, err error) saga.Step[transfer] {
+		return saga.Step[transfer]{
+			Name: name,
+			Action: func(context.Context, *transfer) error {
+				fmt.Println(name)
+				return err
+			},
+			Compensate: func(context.Context, *transfer) error {
+				fmt.Println("undo", name)
+				return nil
+			},
+		}
+	}
+
+	ctx := context.Background()
+
+	transfers := saga.New("transfer", saga.NewMemoryStore(), eventbus.NewMemory(), []saga.Step[transfer]{
+		step("debit", nil),
+		step("credit", errors.New("account closed")),
+	})
+
+	if err := transfers.Register(ctx); err != nil {
+		panic(err)
+	}
+
+	id, err := transfers.Start(ctx, transfer{Amount: 100})
+	if err != nil {
+		panic(err)
+	}
+
+	inst, _, _ := transfers.Get(ctx, id)
+	fmt.Println(inst.Status, "-", inst.Error)
+
+	// Output:
+	// debit
+	// credit
+	// undo debit
+	// compensated - step credit: account closed
+}
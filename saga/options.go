@@ -0,0 +1,81 @@
+package saga
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_defaultStaleAfter  = 5 * time.Minute
+	_defaultResumeBatch = 100
+)
+
+// Option configures a Saga.
+type Option func(*config)
+
+type config struct {
+	topic       string
+	retry       []retry.Option
+	staleAfter  time.Duration
+	resumeBatch int
+	logger      logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		retry:       []retry.Option{retry.Attempts(1)},
+		staleAfter:  _defaultStaleAfter,
+		resumeBatch: _defaultResumeBatch,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Topic sets the bus topic steps are dispatched on. Sagas must not share a topic.
+// Default is "saga." + name.
+func Topic(topic string) Option {
+	return func(c *config) {
+		c.topic = topic
+	}
+}
+
+// StepRetry sets the retry policy of actions and compensations before an
+// action failure starts compensation or a compensation failure fails the
+// instance. Errors wrapped with retry.Permanent are not retried.
+// Default is a single attempt.
+func StepRetry(opts ...retry.Option) Option {
+	return func(c *config) {
+		c.retry = append([]retry.Option{retry.Attempts(1)}, opts...)
+	}
+}
+
+// StaleAfter sets how long an active instance may go without progress before
+// Resume dispatches its current step again. It must exceed the longest step.
+// Default is 5 minutes.
+func StaleAfter(d time.Duration) Option {
+	return func(c *config) {
+		c.staleAfter = d
+	}
+}
+
+// ResumeBatch sets the maximum number of instances resumed per Resume call.
+// Default is 100.
+func ResumeBatch(n int) Option {
+	return func(c *config) {
+		c.resumeBatch = n
+	}
+}
+
+// WithLogger sets the logger used to report dropped commands.
+// Default is nil, which disables logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,163 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// DefaultTable is the table used by PostgresStore when no table name is given.
+const DefaultTable = "saga_instances"
+
+// Schema returns the DDL creating the saga table and its index of active
+// instances, for use in migrations (e.g. with the goose package).
+func Schema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    id         UUID PRIMARY KEY,
+    saga       TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    step       INT NOT NULL,
+    data       JSONB NOT NULL,
+    error      TEXT NOT NULL DEFAULT '',
+    version    INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (saga, updated_at) WHERE status IN ('running', 'compensating');`,
+		postgres.TableIdentifier(table).Sanitize(), postgres.IndexIdentifier(table, "active").Sanitize())
+}
+
+// PostgresStore is a Store backed by a Postgres table.
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	name  string
+	table string
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a new PostgresStore using table, or DefaultTable when empty.
+//
+// Example:
+//
+//	store := saga.NewPostgresStore(pg, "")
+//	err := store.CreateTable(ctx)
+func NewPostgresStore(pg *postgres.Postgres, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	return &PostgresStore{pool: pg.Pool, name: table, table: postgres.TableIdentifier(table).Sanitize()}
+}
+
+// CreateTable creates the saga table if it does not exist.
+func (s *PostgresStore) CreateTable(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, Schema(s.name)); err != nil {
+		return fmt.Errorf("saga - PostgresStore - CreateTable - s.pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Create implements Store.
+func (s *PostgresStore) Create(ctx context.Context, inst *Instance) error {
+	query := fmt.Sprintf(`INSERT INTO %s (id, saga, status, step, data, error, version)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING created_at, updated_at`, s.table)
+
+	err := s.pool.QueryRow(ctx, query,
+		inst.ID, inst.Saga, inst.Status, inst.Step, inst.Data, inst.Error, inst.Version,
+	).Scan(&inst.CreatedAt, &inst.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("saga - PostgresStore - Create - s.pool.QueryRow: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements Store.
+func (s *PostgresStore) Get(ctx context.Context, id string) (*Instance, error) {
+	query := fmt.Sprintf(`SELECT id, saga, status, step, data, error, version, created_at, updated_at
+FROM %s WHERE id = $1`, s.table)
+
+	inst, err := scanInstance(s.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("saga - PostgresStore - Get - s.pool.QueryRow: %w", err)
+	}
+
+	return inst, nil
+}
+
+// Update implements Store.
+func (s *PostgresStore) Update(ctx context.Context, inst *Instance) error {
+	query := fmt.Sprintf(`UPDATE %s
+SET status = $3, step = $4, data = $5, error = $6, version = version + 1, updated_at = now()
+WHERE id = $1 AND version = $2
+RETURNING version, updated_at`, s.table)
+
+	err := s.pool.QueryRow(ctx, query,
+		inst.ID, inst.Version, inst.Status, inst.Step, inst.Data, inst.Error,
+	).Scan(&inst.Version, &inst.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrConflict
+	}
+
+	if err != nil {
+		return fmt.Errorf("saga - PostgresStore - Update - s.pool.QueryRow: %w", err)
+	}
+
+	return nil
+}
+
+// Stale implements Store.
+func (s *PostgresStore) Stale(ctx context.Context, saga string, before time.Time, limit int) ([]*Instance, error) {
+	query := fmt.Sprintf(`SELECT id, saga, status, step, data, error, version, created_at, updated_at
+FROM %s
+WHERE saga = $1 AND status IN ('running', 'compensating') AND updated_at < $2
+ORDER BY updated_at
+LIMIT $3`, s.table)
+
+	rows, err := s.pool.Query(ctx, query, saga, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("saga - PostgresStore - Stale - s.pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var stale []*Instance
+
+	for rows.Next() {
+		inst, err := scanInstance(rows)
+		if err != nil {
+			return nil, fmt.Errorf("saga - PostgresStore - Stale - rows.Scan: %w", err)
+		}
+
+		stale = append(stale, inst)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("saga - PostgresStore - Stale - rows.Err: %w", err)
+	}
+
+	return stale, nil
+}
+
+func scanInstance(row pgx.Row) (*Instance, error) {
+	var inst Instance
+
+	err := row.Scan(&inst.ID, &inst.Saga, &inst.Status, &inst.Step, &inst.Data,
+		&inst.Error, &inst.Version, &inst.CreatedAt, &inst.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inst, nil
+}
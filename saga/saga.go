@@ -0,0 +1,363 @@
+// Package saga orchestrates multi-step workflows with compensating actions.
+// Instance state is persisted after every step, in Postgres or in memory, and
+// steps are dispatched as commands over an eventbus.Bus (Kafka, RabbitMQ or
+// in-memory), so a saga survives restarts and resumes where it stopped with
+// at-least-once execution of each step.
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/rdashevsky/go-pkgs/eventbus"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+var (
+	// ErrNotFound is returned when a saga instance does not exist.
+	ErrNotFound = errors.New("saga - instance not found")
+	// ErrConflict is returned by Store.Update when the instance was modified concurrently.
+	ErrConflict = errors.New("saga - instance modified concurrently")
+)
+
+// Status is the state of a saga instance.
+type Status string
+
+// Saga instance statuses.
+const (
+	// StatusRunning means steps are being executed.
+	StatusRunning Status = "running"
+	// StatusCompensating means a step failed and completed steps are being compensated.
+	StatusCompensating Status = "compensating"
+	// StatusCompleted means every step succeeded.
+	StatusCompleted Status = "completed"
+	// StatusCompensated means a step failed and every completed step was compensated.
+	StatusCompensated Status = "compensated"
+	// StatusFailed means a compensation failed; the instance needs manual attention.
+	StatusFailed Status = "failed"
+)
+
+// Terminal reports whether no further steps run for the status.
+func (s Status) Terminal() bool {
+	return s == StatusCompleted || s == StatusCompensated || s == StatusFailed
+}
+
+// Step is a unit of work of a saga. Action and Compensate may run more than
+// once and must be idempotent. Changes they make to the data are persisted
+// and visible to the following steps.
+type Step[T any] struct {
+	// Name identifies the step in errors.
+	Name string
+	// Action performs the step. An error, once retries are exhausted, starts
+	// compensation of the previous steps.
+	Action func(ctx context.Context, data *T) error
+	// Compensate undoes a completed Action; nil means nothing to undo.
+	Compensate func(ctx context.Context, data *T) error
+}
+
+// Instance is the persisted state of a saga execution.
+type Instance struct {
+	ID     string
+	Saga   string
+	Status Status
+	// Step is the index of the next step to execute or compensate.
+	Step int
+	// Data is the JSON encoded saga data.
+	Data []byte
+	// Error describes the failure of a compensated or failed instance.
+	Error     string
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// command is the eventbus payload dispatching a step of an instance.
+type command struct {
+	ID      string `json:"id"`
+	Status  Status `json:"status"`
+	Step    int    `json:"step"`
+	Version int    `json:"version"`
+}
+
+// Saga executes instances of a workflow over a store and a bus.
+type Saga[T any] struct {
+	name  string
+	steps []Step[T]
+	store Store
+	bus   eventbus.Bus
+	cfg   config
+}
+
+// New creates a Saga named name running steps in order. Instances are
+// persisted in store and steps are dispatched on bus; call Register on every
+// instance of the service to consume them.
+//
+// Default configuration:
+//   - Topic: "saga." + name
+//   - Step attempts: 1, no retries
+//   - Stale after: 5 minutes
+//   - Resume batch: 100
+//
+// Example:
+//
+//	orders := saga.New("order", store, bus, []saga.Step[Order]{
+//	    {Name: "reserve", Action: inventory.Reserve, Compensate: inventory.Release},
+//	    {Name: "charge", Action: payments.Charge, Compensate: payments.Refund},
+//	    {Name: "ship", Action: shipping.Schedule},
+//	}, saga.StepRetry(retry.Attempts(3)))
+//
+//	err := orders.Register(ctx)
+//	id, err := orders.Start(ctx, Order{ID: orderID, Amount: 42})
+func New[T any](name string, store Store, bus eventbus.Bus, steps []Step[T], opts ...Option) *Saga[T] {
+	cfg := newConfig(opts)
+	if cfg.topic == "" {
+		cfg.topic = "saga." + name
+	}
+
+	return &Saga[T]{
+		name:  name,
+		steps: steps,
+		store: store,
+		bus:   bus,
+		cfg:   cfg,
+	}
+}
+
+// Name returns the saga name.
+func (s *Saga[T]) Name() string {
+	return s.name
+}
+
+// Register subscribes to the saga topic so that this process executes steps.
+func (s *Saga[T]) Register(ctx context.Context) error {
+	if err := s.bus.Subscribe(ctx, s.cfg.topic, s.handle); err != nil {
+		return fmt.Errorf("saga - Saga - Register - bus.Subscribe: %w", err)
+	}
+
+	return nil
+}
+
+// Start persists a new instance with data and dispatches its first step. The
+// instance ID is returned even when dispatching fails, in which case Resume
+// picks the instance up later.
+func (s *Saga[T]) Start(ctx context.Context, data T) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("saga - Saga - Start - json.Marshal: %w", err)
+	}
+
+	inst := &Instance{
+		ID:     uuid.NewString(),
+		Saga:   s.name,
+		Status: StatusRunning,
+		Data:   raw,
+	}
+
+	if len(s.steps) == 0 {
+		inst.Status = StatusCompleted
+	}
+
+	if err = s.store.Create(ctx, inst); err != nil {
+		return "", fmt.Errorf("saga - Saga - Start - store.Create: %w", err)
+	}
+
+	if inst.Status.Terminal() {
+		return inst.ID, nil
+	}
+
+	if err = s.dispatch(ctx, inst); err != nil {
+		return inst.ID, fmt.Errorf("saga - Saga - Start: %w", err)
+	}
+
+	return inst.ID, nil
+}
+
+// Get returns the instance with the given ID and its decoded data.
+func (s *Saga[T]) Get(ctx context.Context, id string) (*Instance, T, error) {
+	var data T
+
+	inst, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, data, fmt.Errorf("saga - Saga - Get - store.Get: %w", err)
+	}
+
+	if err = json.Unmarshal(inst.Data, &data); err != nil {
+		return nil, data, fmt.Errorf("saga - Saga - Get - json.Unmarshal: %w", err)
+	}
+
+	return inst, data, nil
+}
+
+// Resume dispatches again the current step of instances that made no progress
+// for the stale duration, e.g. because the process crashed or a dispatch
+// failed. It returns the number of instances resumed and is meant to be called
+// periodically, for instance with the scheduler package.
+func (s *Saga[T]) Resume(ctx context.Context) (int, error) {
+	stale, err := s.store.Stale(ctx, s.name, time.Now().Add(-s.cfg.staleAfter), s.cfg.resumeBatch)
+	if err != nil {
+		return 0, fmt.Errorf("saga - Saga - Resume - store.Stale: %w", err)
+	}
+
+	var errs []error
+
+	resumed := 0
+
+	for _, inst := range stale {
+		if err := s.dispatch(ctx, inst); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		resumed++
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return resumed, fmt.Errorf("saga - Saga - Resume: %w", err)
+	}
+
+	return resumed, nil
+}
+
+func (s *Saga[T]) dispatch(ctx context.Context, inst *Instance) error {
+	payload, err := json.Marshal(command{
+		ID:      inst.ID,
+		Status:  inst.Status,
+		Step:    inst.Step,
+		Version: inst.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("dispatch - json.Marshal: %w", err)
+	}
+
+	err = s.bus.Publish(ctx, &eventbus.Event{Topic: s.cfg.topic, Key: inst.ID, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("dispatch - bus.Publish: %w", err)
+	}
+
+	return nil
+}
+
+// handle executes the step a command refers to. Errors are returned only for
+// store and bus failures so that the bus redelivers the command.
+func (s *Saga[T]) handle(ctx context.Context, e *eventbus.Event) error {
+	var cmd command
+	if err := json.Unmarshal(e.Payload, &cmd); err != nil {
+		s.logError("saga - %s - malformed command: %v", s.name, err)
+
+		return nil
+	}
+
+	inst, err := s.store.Get(ctx, cmd.ID)
+	if errors.Is(err, ErrNotFound) {
+		s.logError("saga - %s - command for unknown instance %s", s.name, cmd.ID)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("saga - Saga - handle - store.Get: %w", err)
+	}
+
+	// Duplicate or outdated deliveries are acknowledged without effect.
+	if inst.Status.Terminal() || inst.Status != cmd.Status || inst.Step != cmd.Step || inst.Version != cmd.Version {
+		return nil
+	}
+
+	s.execute(ctx, inst)
+
+	if err = s.store.Update(ctx, inst); errors.Is(err, ErrConflict) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("saga - Saga - handle - store.Update: %w", err)
+	}
+
+	if inst.Status.Terminal() {
+		return nil
+	}
+
+	if err = s.dispatch(ctx, inst); err != nil {
+		return fmt.Errorf("saga - Saga - handle: %w", err)
+	}
+
+	return nil
+}
+
+// execute runs the current step of inst and advances its state.
+func (s *Saga[T]) execute(ctx context.Context, inst *Instance) {
+	var data T
+	if err := json.Unmarshal(inst.Data, &data); err != nil {
+		inst.Status = StatusFailed
+		inst.Error = fmt.Sprintf("decode data: %v", err)
+
+		return
+	}
+
+	if inst.Step < 0 || inst.Step >= len(s.steps) {
+		inst.Status = StatusFailed
+		inst.Error = fmt.Sprintf("step %d out of range", inst.Step)
+
+		return
+	}
+
+	step := s.steps[inst.Step]
+
+	switch inst.Status {
+	case StatusRunning:
+		if err := s.run(ctx, step.Action, &data); err != nil {
+			inst.Status = StatusCompensating
+			inst.Error = fmt.Sprintf("step %s: %v", step.Name, err)
+			inst.Step--
+
+			break
+		}
+
+		inst.Step++
+
+		if inst.Step == len(s.steps) {
+			inst.Status = StatusCompleted
+		}
+	case StatusCompensating:
+		if err := s.run(ctx, step.Compensate, &data); err != nil {
+			inst.Status = StatusFailed
+			inst.Error += fmt.Sprintf("; compensate %s: %v", step.Name, err)
+
+			return
+		}
+
+		inst.Step--
+	}
+
+	if inst.Status == StatusCompensating && inst.Step < 0 {
+		inst.Status = StatusCompensated
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		inst.Status = StatusFailed
+		inst.Error = fmt.Sprintf("encode data: %v", err)
+
+		return
+	}
+
+	inst.Data = raw
+}
+
+func (s *Saga[T]) run(ctx context.Context, fn func(context.Context, *T) error, data *T) error {
+	if fn == nil {
+		return nil
+	}
+
+	return retry.Do(ctx, func(ctx context.Context) error {
+		return fn(ctx, data)
+	}, s.cfg.retry...)
+}
+
+func (s *Saga[T]) logError(format string, args ...interface{}) {
+	if s.cfg.logger != nil {
+		s.cfg.logger.Error(format, args...)
+	}
+}
@@ -0,0 +1,114 @@
+package saga
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists saga instances.
+type Store interface {
+	// Create inserts inst, setting its timestamps.
+	Create(ctx context.Context, inst *Instance) error
+	// Get returns the instance with the given ID or ErrNotFound.
+	Get(ctx context.Context, id string) (*Instance, error)
+	// Update saves inst when the stored version equals inst.Version, then
+	// increments inst.Version; otherwise it returns ErrConflict.
+	Update(ctx context.Context, inst *Instance) error
+	// Stale returns up to limit running or compensating instances of saga last
+	// updated before the given time, oldest first.
+	Stale(ctx context.Context, saga string, before time.Time, limit int) ([]*Instance, error)
+}
+
+// MemoryStore is a Store keeping instances in process, for tests and single
+// instance services that can afford to lose running sagas on restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	instances map[string]Instance
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{instances: make(map[string]Instance)}
+}
+
+// Create implements Store.
+func (m *MemoryStore) Create(_ context.Context, inst *Instance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	inst.CreatedAt, inst.UpdatedAt = now, now
+	m.instances[inst.ID] = clone(inst)
+
+	return nil
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(_ context.Context, id string) (*Instance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inst, ok := m.instances[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	c := clone(&inst)
+
+	return &c, nil
+}
+
+// Update implements Store.
+func (m *MemoryStore) Update(_ context.Context, inst *Instance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.instances[inst.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if stored.Version != inst.Version {
+		return ErrConflict
+	}
+
+	inst.Version++
+	inst.UpdatedAt = time.Now()
+	m.instances[inst.ID] = clone(inst)
+
+	return nil
+}
+
+// Stale implements Store.
+func (m *MemoryStore) Stale(_ context.Context, saga string, before time.Time, limit int) ([]*Instance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stale []*Instance
+
+	for _, inst := range m.instances {
+		if inst.Saga == saga && !inst.Status.Terminal() && inst.UpdatedAt.Before(before) {
+			c := clone(&inst)
+			stale = append(stale, &c)
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+
+	return stale, nil
+}
+
+func clone(inst *Instance) Instance {
+	c := *inst
+	c.Data = append([]byte(nil), inst.Data...)
+
+	return c
+}
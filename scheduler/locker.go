@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/postgres"
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+type postgresLocker struct {
+	pg *postgres.Postgres
+}
+
+// PostgresLocker returns a Locker backed by PostgreSQL session advisory locks.
+// Each held lock occupies one pool connection for the duration of the run.
+func PostgresLocker(pg *postgres.Postgres) Locker {
+	return &postgresLocker{pg: pg}
+}
+
+func (l *postgresLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	unlock, acquired, err := l.pg.TryAdvisoryLock(ctx, key)
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	return func() { _ = unlock(context.Background()) }, true, nil
+}
+
+type redisLocker struct {
+	r   *redis.Redis
+	ttl time.Duration
+}
+
+// RedisLocker returns a Locker backed by Redis locks expiring after ttl.
+// The ttl must exceed the longest job run, otherwise the lock may expire while the job runs.
+func RedisLocker(r *redis.Redis, ttl time.Duration) Locker {
+	return &redisLocker{r: r, ttl: ttl}
+}
+
+func (l *redisLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	token, acquired, err := l.r.TryLock(ctx, key, l.ttl)
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	return func() { _, _ = l.r.Unlock(context.Background(), key, token) }, true, nil
+}
+
+type lockLocker struct {
+	l lock.Locker
+}
+
+// FromLocker returns a Locker backed by a lock.Locker, so jobs share the lease renewal
+// and backends of the lock package. Unlike RedisLocker, leases are renewed while the job runs.
+//
+// Example:
+//
+//	s := scheduler.New(l, scheduler.WithLocker(scheduler.FromLocker(lock.NewRedis(r))))
+func FromLocker(l lock.Locker) Locker {
+	return &lockLocker{l: l}
+}
+
+func (l *lockLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	held, err := l.l.TryAcquire(ctx, key)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return func() { _ = held.Release(context.Background()) }, true, nil
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+)
+
+// Option is a function that configures a Scheduler.
+// Options are applied in the order they are passed to New.
+type Option func(*Scheduler)
+
+// Location sets the time zone in which schedules are evaluated.
+// Default is time.Local.
+func Location(loc *time.Location) Option {
+	return func(s *Scheduler) {
+		s.location = loc
+	}
+}
+
+// WithLocker sets the Locker used by jobs registered with the Distributed option.
+//
+// Example:
+//
+//	scheduler.New(l, scheduler.WithLocker(scheduler.RedisLocker(r, time.Minute)))
+func WithLocker(locker Locker) Option {
+	return func(s *Scheduler) {
+		s.locker = locker
+	}
+}
+
+// WithLeader sets the Leadership consulted by jobs registered with the LeaderOnly option.
+//
+// Example:
+//
+//	e := leader.NewPostgres(pg, "scheduler")
+//	roles, err := e.Campaign(ctx)
+//	s := scheduler.New(l, scheduler.WithLeader(e))
+func WithLeader(l Leadership) Option {
+	return func(s *Scheduler) {
+		s.leadership = l
+	}
+}
+
+// LockPrefix sets the prefix of lock keys derived from job names.
+// Default is "scheduler:".
+func LockPrefix(prefix string) Option {
+	return func(s *Scheduler) {
+		s.lockPrefix = prefix
+	}
+}
+
+// WithMetrics sets the hook receiving job run and skip measurements.
+func WithMetrics(m Metrics) Option {
+	return func(s *Scheduler) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
+
+// ShutdownTimeout sets the maximum duration Shutdown waits for running jobs.
+// Default is 10 seconds.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithClock sets the clock driving schedules, run durations and the shutdown timeout,
+// e.g. a *clock.Fake to trigger activations deterministically in tests.
+// Default is clock.Real().
+func WithClock(c clock.Clock) Option {
+	return func(s *Scheduler) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
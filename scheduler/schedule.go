@@ -0,0 +1,274 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the activation times of a job.
+type Schedule interface {
+	// Next returns the first activation time strictly after t,
+	// or the zero time if the schedule never activates again.
+	Next(t time.Time) time.Time
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a Schedule activating at a fixed interval.
+// Intervals below one second are rounded up to one second.
+func Every(interval time.Duration) Schedule {
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule holds the allowed values of each cron field as bit sets.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record unrestricted fields, needed for the day matching rule.
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{0, 6, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron parses a standard five-field cron expression
+// (minute, hour, day of month, month, day of week).
+// Fields support lists (1,15), ranges (1-5), steps (*/10, 0-30/5) and
+// month/weekday names (JAN, MON). Day of week 7 is accepted as Sunday.
+// The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly
+// and "@every <duration>" are supported as well.
+// When both day of month and day of week are restricted, a day matches if either does.
+//
+// Example:
+//
+//	s, err := scheduler.Cron("*/15 9-17 * * MON-FRI")
+func Cron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler - Cron - invalid @every duration %q: %w", rest, err)
+		}
+
+		return Every(d), nil
+	}
+
+	if std, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = std
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("scheduler - Cron - expected 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	// Day of week 7 is an alias for Sunday.
+	dow := bounds{0, 7, dowBounds.names}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+
+	if s.minute, _, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+
+	if s.hour, _, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+
+	if s.dom, s.domStar, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+
+	if s.month, _, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+
+	if s.dow, s.dowStar, err = parseField(fields[4], dow); err != nil {
+		return nil, err
+	}
+
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	return s, nil
+}
+
+// MustCron is like Cron but panics if the expression cannot be parsed.
+func MustCron(expr string) Schedule {
+	s, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func parseField(field string, b bounds) (bits uint64, star bool, err error) {
+	for _, part := range strings.Split(field, ",") {
+		partBits, partStar, err := parsePart(part, b)
+		if err != nil {
+			return 0, false, fmt.Errorf("scheduler - Cron - field %q: %w", field, err)
+		}
+
+		bits |= partBits
+		star = star || partStar
+	}
+
+	return bits, star, nil
+}
+
+func parsePart(part string, b bounds) (bits uint64, star bool, err error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, false, fmt.Errorf("invalid step %q", stepPart)
+		}
+	}
+
+	var lo, hi int
+
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		lo, hi = b.min, b.max
+		star = !hasStep
+	case strings.Contains(rangePart, "-"):
+		from, to, _ := strings.Cut(rangePart, "-")
+
+		if lo, err = parseValue(from, b); err != nil {
+			return 0, false, err
+		}
+
+		if hi, err = parseValue(to, b); err != nil {
+			return 0, false, err
+		}
+	default:
+		if lo, err = parseValue(rangePart, b); err != nil {
+			return 0, false, err
+		}
+
+		hi = lo
+		if hasStep {
+			hi = b.max
+		}
+	}
+
+	if lo > hi {
+		return 0, false, fmt.Errorf("invalid range %q", rangePart)
+	}
+
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+
+	return bits, star, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, b.min, b.max)
+	}
+
+	return v, nil
+}
+
+// maxSearchYears bounds the search for schedules that can never match (e.g. "0 0 30 2 *").
+const maxSearchYears = 5
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.Year() + maxSearchYears
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,101 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/scheduler"
+)
+
+func TestCron_Next(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2024, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", time.Date(2024, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"daily at 03:00", "0 3 * * *", time.Date(2024, 3, 16, 3, 0, 0, 0, time.UTC)},
+		{"list of minutes", "5,35 * * * *", time.Date(2024, 3, 15, 10, 35, 0, 0, time.UTC)},
+		{"weekdays business hours", "0 9-17 * * MON-FRI", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"next monday", "0 9 * * mon", time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"first of month", "@monthly", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"yearly", "@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 FEB *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"range with step", "0-30/10 * * * *", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 1 * FRI", time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{"every descriptor", "@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scheduler.Cron(tt.expr)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tt.expr, err)
+			}
+
+			if got := s.Next(base); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCron_NeverMatches(t *testing.T) {
+	s := scheduler.MustCron("0 0 30 2 *")
+
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("expected zero time for impossible schedule, got %v", next)
+	}
+}
+
+func TestCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@every soon",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := scheduler.Cron(expr); err == nil {
+				t.Errorf("expected error for %q", expr)
+			}
+		})
+	}
+}
+
+func TestMustCron_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid expression")
+		}
+	}()
+
+	scheduler.MustCron("invalid")
+}
+
+func TestEvery(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := scheduler.Every(5 * time.Minute).Next(base); !got.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("expected 5 minutes later, got %v", got)
+	}
+
+	if got := scheduler.Every(time.Millisecond).Next(base); !got.Equal(base.Add(time.Second)) {
+		t.Errorf("expected interval to be rounded up to 1s, got %v", got)
+	}
+}
@@ -0,0 +1,420 @@
+// Package scheduler provides a cron-style job scheduler supporting cron expressions
+// and fixed intervals, with per-job timeouts, panic recovery, overlap prevention and
+// optional distributed single-run guarantees through a Locker or a leader election.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultShutdownTimeout = 10 * time.Second
+	_defaultNotifySize      = 16
+)
+
+var (
+	// ErrDuplicateJob is returned by Add when a job with the same name is already registered.
+	ErrDuplicateJob = errors.New("scheduler - job already registered")
+	// ErrStarted is returned by Add after Start has been called.
+	ErrStarted = errors.New("scheduler - scheduler already started")
+	// ErrStopped is returned by RunNow after Shutdown has been called.
+	ErrStopped = errors.New("scheduler - scheduler stopped")
+	// ErrPanic wraps a panic recovered from a job.
+	ErrPanic = errors.New("scheduler - job panicked")
+)
+
+// Skip reasons reported to Metrics.ObserveSkip.
+const (
+	SkipOverlap  = "overlap"
+	SkipLocked   = "locked"
+	SkipFollower = "follower"
+)
+
+// Job is a unit of scheduled work. The context is cancelled when the job
+// times out or the scheduler is shut down.
+type Job func(ctx context.Context) error
+
+// Locker provides mutual exclusion between scheduler instances.
+// TryLock must not block; when the lock is held elsewhere it returns acquired == false.
+type Locker interface {
+	TryLock(ctx context.Context, key string) (unlock func(), acquired bool, err error)
+}
+
+// Leadership tells whether this instance is the elected leader, e.g. a *leader.Elector.
+type Leadership interface {
+	IsLeader() bool
+}
+
+// Metrics receives job execution measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveRun(job string, duration time.Duration, err error)
+	ObserveSkip(job string, reason string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRun(string, time.Duration, error) {}
+func (noopMetrics) ObserveSkip(string, string)              {}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       Job
+
+	timeout      time.Duration
+	allowOverlap bool
+	distributed  bool
+	leaderOnly   bool
+
+	mu      sync.Mutex
+	running bool
+}
+
+// JobOption configures a single job.
+type JobOption func(*job)
+
+// Timeout cancels the job context after the given duration. Zero means no timeout.
+func Timeout(timeout time.Duration) JobOption {
+	return func(j *job) {
+		j.timeout = timeout
+	}
+}
+
+// AllowOverlap lets a new run start while the previous one is still running.
+// By default overlapping runs are skipped.
+func AllowOverlap() JobOption {
+	return func(j *job) {
+		j.allowOverlap = true
+	}
+}
+
+// Distributed runs the job on a single instance per activation, using the scheduler Locker.
+// Instances should have synchronized clocks. Add fails if no Locker is configured.
+func Distributed() JobOption {
+	return func(j *job) {
+		j.distributed = true
+	}
+}
+
+// LeaderOnly runs the job only on the instance elected by the scheduler Leadership;
+// activations on other instances are skipped. Unlike Distributed, no lock is taken per
+// run. Add fails if no Leadership is configured.
+func LeaderOnly() JobOption {
+	return func(j *job) {
+		j.leaderOnly = true
+	}
+}
+
+// Scheduler runs registered jobs according to their schedules.
+type Scheduler struct {
+	jobs map[string]*job
+
+	location        *time.Location
+	locker          Locker
+	leadership      Leadership
+	lockPrefix      string
+	metrics         Metrics
+	shutdownTimeout time.Duration
+	clock           clock.Clock
+
+	notify chan error
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	loops  sync.WaitGroup
+	runs   sync.WaitGroup
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+
+	logger logger.LoggerI
+}
+
+// New creates a new Scheduler. Jobs are added with Add and run after Start.
+// Default configuration: local time zone, no distributed locking, 10 seconds shutdown timeout.
+//
+// Example:
+//
+//	s := scheduler.New(l, scheduler.WithLocker(scheduler.PostgresLocker(pg)))
+//	_ = s.Add("cleanup", scheduler.MustCron("0 3 * * *"), cleanup, scheduler.Distributed())
+//	s.Start()
+func New(l logger.LoggerI, opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		jobs:            make(map[string]*job),
+		location:        time.Local,
+		lockPrefix:      "scheduler:",
+		metrics:         noopMetrics{},
+		shutdownTimeout: _defaultShutdownTimeout,
+		clock:           clock.Real(),
+		notify:          make(chan error, _defaultNotifySize),
+		stop:            make(chan struct{}),
+		ctx:             ctx,
+		cancel:          cancel,
+		logger:          l,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add registers a job under a unique name.
+//
+// Example:
+//
+//	err := s.Add("sync", scheduler.Every(time.Minute), syncFn, scheduler.Timeout(30*time.Second))
+func (s *Scheduler) Add(name string, schedule Schedule, fn Job, opts ...JobOption) error {
+	j := &job{
+		name:     name,
+		schedule: schedule,
+		fn:       fn,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	if j.distributed && s.locker == nil {
+		return fmt.Errorf("scheduler - Add - job %q is distributed but no Locker is configured", name)
+	}
+
+	if j.leaderOnly && s.leadership == nil {
+		return fmt.Errorf("scheduler - Add - job %q is leader only but no Leadership is configured", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrStarted
+	}
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateJob, name)
+	}
+
+	s.jobs[name] = j
+
+	return nil
+}
+
+// Start begins scheduling all registered jobs, each in its own goroutine.
+// Use Notify() to receive job failures.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started || s.stopped {
+		return
+	}
+
+	s.started = true
+
+	for _, j := range s.jobs {
+		s.loops.Add(1)
+
+		go s.loop(j)
+	}
+}
+
+// RunNow triggers an immediate run of the named job outside of its schedule,
+// honouring its overlap, timeout and locking settings. It blocks until the run completes.
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	if !ok || s.stopped {
+		s.mu.Unlock()
+
+		if !ok {
+			return fmt.Errorf("scheduler - RunNow - unknown job %q", name)
+		}
+
+		return ErrStopped
+	}
+
+	s.runs.Add(1)
+	s.mu.Unlock()
+
+	return s.run(j)
+}
+
+func (s *Scheduler) loop(j *job) {
+	defer s.loops.Done()
+
+	for {
+		now := s.clock.Now().In(s.location)
+
+		next := j.schedule.Next(now)
+		if next.IsZero() {
+			s.logger.Warn("scheduler - job %s has no further activations", j.name)
+
+			return
+		}
+
+		timer := s.clock.NewTimer(next.Sub(now))
+
+		select {
+		case <-s.stop:
+			timer.Stop()
+
+			return
+		case <-timer.C():
+			s.runs.Add(1)
+
+			go func() {
+				_ = s.run(j)
+			}()
+		}
+	}
+}
+
+// run executes one activation of j. The caller must have called s.runs.Add(1).
+func (s *Scheduler) run(j *job) (err error) {
+	defer s.runs.Done()
+
+	if !j.allowOverlap {
+		j.mu.Lock()
+		if j.running {
+			j.mu.Unlock()
+			s.metrics.ObserveSkip(j.name, SkipOverlap)
+			s.logger.Debug("scheduler - job %s skipped: previous run still in progress", j.name)
+
+			return nil
+		}
+
+		j.running = true
+		j.mu.Unlock()
+
+		defer func() {
+			j.mu.Lock()
+			j.running = false
+			j.mu.Unlock()
+		}()
+	}
+
+	if j.leaderOnly && !s.leadership.IsLeader() {
+		s.metrics.ObserveSkip(j.name, SkipFollower)
+
+		return nil
+	}
+
+	ctx := s.ctx
+
+	if j.distributed {
+		unlock, acquired, lockErr := s.locker.TryLock(ctx, s.lockPrefix+j.name)
+		if lockErr != nil {
+			err = fmt.Errorf("scheduler - run - job %s - s.locker.TryLock: %w", j.name, lockErr)
+			s.report(err)
+
+			return err
+		}
+
+		if !acquired {
+			s.metrics.ObserveSkip(j.name, SkipLocked)
+
+			return nil
+		}
+
+		defer unlock()
+	}
+
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	start := s.clock.Now()
+
+	err = s.execute(ctx, j)
+
+	s.metrics.ObserveRun(j.name, s.clock.Since(start), err)
+
+	if err != nil {
+		err = fmt.Errorf("scheduler - job %s: %w", j.name, err)
+		s.report(err)
+	}
+
+	return err
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+
+			s.logger.Error("scheduler - job %s panicked: %v\n%s", j.name, r, debug.Stack())
+		}
+	}()
+
+	return j.fn(ctx)
+}
+
+func (s *Scheduler) report(err error) {
+	s.logger.Error(err)
+
+	select {
+	case s.notify <- err:
+	default:
+	}
+}
+
+// Notify returns a channel that receives job failures (errors, panics, timeouts
+// and locking errors). Failures are dropped when the channel buffer is full.
+// The channel is closed after Shutdown.
+func (s *Scheduler) Notify() <-chan error {
+	return s.notify
+}
+
+// Shutdown stops scheduling new runs and waits up to the configured shutdown timeout
+// for running jobs to finish. Jobs still running after the timeout have their context cancelled.
+func (s *Scheduler) Shutdown() error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	s.stopped = true
+	close(s.stop)
+	s.mu.Unlock()
+
+	s.loops.Wait()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-s.clock.After(s.shutdownTimeout):
+		err = errors.New("scheduler - Shutdown - timed out waiting for running jobs")
+	}
+
+	s.cancel()
+	<-done
+	close(s.notify)
+
+	return err
+}
@@ -0,0 +1,406 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/clock"
+	"github.com/rdashevsky/go-pkgs/lock"
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/scheduler"
+)
+
+type recordingMetrics struct {
+	mu    sync.Mutex
+	runs  map[string]int
+	skips map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{runs: map[string]int{}, skips: map[string]int{}}
+}
+
+func (m *recordingMetrics) ObserveRun(job string, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[job]++
+}
+
+func (m *recordingMetrics) ObserveSkip(_ string, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.skips[reason]++
+}
+
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+	err  error
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, key string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return nil, false, l.err
+	}
+
+	if l.held[key] {
+		return nil, false, nil
+	}
+
+	l.held[key] = true
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, true, nil
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := scheduler.New(logger.New("error"))
+	noop := func(context.Context) error { return nil }
+
+	if err := s.Add("job", scheduler.Every(time.Hour), noop); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if err := s.Add("job", scheduler.Every(time.Hour), noop); !errors.Is(err, scheduler.ErrDuplicateJob) {
+		t.Errorf("expected ErrDuplicateJob, got: %v", err)
+	}
+
+	if err := s.Add("dist", scheduler.Every(time.Hour), noop, scheduler.Distributed()); err == nil {
+		t.Error("expected error for distributed job without locker")
+	}
+
+	s.Start()
+	defer s.Shutdown() //nolint:errcheck // test cleanup
+
+	if err := s.Add("late", scheduler.Every(time.Hour), noop); !errors.Is(err, scheduler.ErrStarted) {
+		t.Errorf("expected ErrStarted, got: %v", err)
+	}
+}
+
+func TestScheduler_RunsOnSchedule(t *testing.T) {
+	var runs atomic.Int32
+
+	s := scheduler.New(logger.New("error"))
+
+	err := s.Add("tick", scheduler.Every(time.Second), func(context.Context) error {
+		runs.Add(1)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	s.Start()
+	time.Sleep(1200 * time.Millisecond)
+
+	if err := s.Shutdown(); err != nil {
+		t.Fatalf("expected no error on shutdown, got: %v", err)
+	}
+
+	if runs.Load() < 1 {
+		t.Errorf("expected job to run at least once, got %d", runs.Load())
+	}
+}
+
+func TestScheduler_WithClock(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 2, 59, 0, 0, time.UTC))
+	runs := make(chan struct{}, 1)
+
+	s := scheduler.New(logger.New("error"), scheduler.Location(time.UTC), scheduler.WithClock(c))
+
+	err := s.Add("nightly", scheduler.MustCron("0 3 * * *"), func(context.Context) error {
+		runs <- struct{}{}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	s.Start()
+
+	steps := []struct {
+		advance time.Duration
+		run     bool
+	}{
+		{time.Minute, true},
+		{23 * time.Hour, false},
+		{time.Hour, true},
+	}
+
+	for i, step := range steps {
+		c.BlockUntil(1)
+		c.Advance(step.advance)
+
+		select {
+		case <-runs:
+			if !step.run {
+				t.Fatalf("step %d: unexpected run", i)
+			}
+		case <-time.After(100 * time.Millisecond):
+			if step.run {
+				t.Fatalf("step %d: expected a run", i)
+			}
+		}
+	}
+
+	if err := s.Shutdown(); err != nil {
+		t.Fatalf("expected no error on shutdown, got: %v", err)
+	}
+}
+
+func TestScheduler_RunNow_RecoversPanic(t *testing.T) {
+	s := scheduler.New(logger.New("error"))
+
+	_ = s.Add("boom", scheduler.Every(time.Hour), func(context.Context) error {
+		panic("kaboom")
+	})
+
+	err := s.RunNow("boom")
+	if !errors.Is(err, scheduler.ErrPanic) {
+		t.Fatalf("expected ErrPanic, got: %v", err)
+	}
+
+	select {
+	case notified := <-s.Notify():
+		if !errors.Is(notified, scheduler.ErrPanic) {
+			t.Errorf("expected ErrPanic on Notify, got: %v", notified)
+		}
+	default:
+		t.Error("expected failure to be reported on Notify")
+	}
+
+	if err := s.RunNow("missing"); err == nil {
+		t.Error("expected error for unknown job")
+	}
+}
+
+func TestScheduler_Timeout(t *testing.T) {
+	s := scheduler.New(logger.New("error"))
+
+	_ = s.Add("slow", scheduler.Every(time.Hour), func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, scheduler.Timeout(10*time.Millisecond))
+
+	if err := s.RunNow("slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestScheduler_PreventsOverlap(t *testing.T) {
+	metrics := newRecordingMetrics()
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	s := scheduler.New(logger.New("error"), scheduler.WithMetrics(metrics))
+
+	_ = s.Add("long", scheduler.Every(time.Hour), func(context.Context) error {
+		close(started)
+		<-release
+
+		return nil
+	})
+
+	done := make(chan error)
+
+	go func() { done <- s.RunNow("long") }()
+
+	<-started
+
+	if err := s.RunNow("long"); err != nil {
+		t.Errorf("expected overlapping run to be skipped silently, got: %v", err)
+	}
+
+	close(release)
+	<-done
+
+	if metrics.skips[scheduler.SkipOverlap] != 1 {
+		t.Errorf("expected 1 overlap skip, got %d", metrics.skips[scheduler.SkipOverlap])
+	}
+
+	if metrics.runs["long"] != 1 {
+		t.Errorf("expected 1 run, got %d", metrics.runs["long"])
+	}
+}
+
+func TestScheduler_Distributed(t *testing.T) {
+	locker := &fakeLocker{held: map[string]bool{"jobs:report": true}}
+	metrics := newRecordingMetrics()
+
+	s := scheduler.New(logger.New("error"),
+		scheduler.WithLocker(locker),
+		scheduler.LockPrefix("jobs:"),
+		scheduler.WithMetrics(metrics),
+	)
+
+	var runs atomic.Int32
+
+	_ = s.Add("report", scheduler.Every(time.Hour), func(context.Context) error {
+		runs.Add(1)
+
+		return nil
+	}, scheduler.Distributed())
+
+	_ = s.RunNow("report")
+
+	if runs.Load() != 0 || metrics.skips[scheduler.SkipLocked] != 1 {
+		t.Errorf("expected run to be skipped while locked elsewhere, runs=%d", runs.Load())
+	}
+
+	locker.held = map[string]bool{}
+	_ = s.RunNow("report")
+
+	if runs.Load() != 1 {
+		t.Errorf("expected run after lock release, got %d", runs.Load())
+	}
+
+	if locker.held["jobs:report"] {
+		t.Error("expected lock to be released after the run")
+	}
+
+	locker.err = errors.New("backend down")
+	if err := s.RunNow("report"); err == nil {
+		t.Error("expected locking error to be returned")
+	}
+}
+
+type fakeLeadership struct {
+	leader atomic.Bool
+}
+
+func (l *fakeLeadership) IsLeader() bool { return l.leader.Load() }
+
+func TestScheduler_LeaderOnly(t *testing.T) {
+	leadership := &fakeLeadership{}
+	metrics := newRecordingMetrics()
+
+	if err := scheduler.New(logger.New("error")).Add("report", scheduler.Every(time.Hour),
+		func(context.Context) error { return nil }, scheduler.LeaderOnly()); err == nil {
+		t.Error("expected Add to fail without Leadership")
+	}
+
+	s := scheduler.New(logger.New("error"), scheduler.WithLeader(leadership), scheduler.WithMetrics(metrics))
+
+	var runs atomic.Int32
+
+	_ = s.Add("report", scheduler.Every(time.Hour), func(context.Context) error {
+		runs.Add(1)
+
+		return nil
+	}, scheduler.LeaderOnly())
+
+	_ = s.RunNow("report")
+
+	if runs.Load() != 0 || metrics.skips[scheduler.SkipFollower] != 1 {
+		t.Errorf("expected run to be skipped on a follower, runs=%d", runs.Load())
+	}
+
+	leadership.leader.Store(true)
+	_ = s.RunNow("report")
+
+	if runs.Load() != 1 {
+		t.Errorf("expected run on the leader, got %d", runs.Load())
+	}
+}
+
+func TestScheduler_Shutdown(t *testing.T) {
+	s := scheduler.New(logger.New("error"), scheduler.ShutdownTimeout(20*time.Millisecond))
+
+	cancelled := make(chan struct{})
+
+	_ = s.Add("stuck", scheduler.Every(time.Hour), func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+
+		return nil
+	})
+
+	go func() { _ = s.RunNow("stuck") }()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := s.Shutdown(); err == nil {
+		t.Error("expected shutdown timeout error")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("expected running job context to be cancelled")
+	}
+
+	if _, ok := <-s.Notify(); ok {
+		t.Error("expected Notify channel to be closed")
+	}
+
+	if err := s.RunNow("stuck"); !errors.Is(err, scheduler.ErrStopped) {
+		t.Errorf("expected ErrStopped, got: %v", err)
+	}
+
+	if err := s.Shutdown(); err != nil {
+		t.Errorf("expected repeated shutdown to be a no-op, got: %v", err)
+	}
+}
+
+type fakeLock struct {
+	released atomic.Bool
+}
+
+func (l *fakeLock) Key() string                   { return "key" }
+func (l *fakeLock) Token() int64                  { return 1 }
+func (l *fakeLock) Lost() <-chan struct{}         { return nil }
+func (l *fakeLock) Release(context.Context) error { l.released.Store(true); return nil }
+
+type fakeLockLocker struct {
+	lock *fakeLock
+	err  error
+}
+
+func (l *fakeLockLocker) TryAcquire(context.Context, string) (lock.Lock, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	return l.lock, nil
+}
+
+func TestFromLocker(t *testing.T) {
+	held := &fakeLock{}
+	locker := &fakeLockLocker{err: lock.ErrNotAcquired}
+	adapter := scheduler.FromLocker(locker)
+
+	if _, acquired, err := adapter.TryLock(context.Background(), "key"); acquired || err != nil {
+		t.Errorf("expected lock held elsewhere to be reported as not acquired, got %v, %v", acquired, err)
+	}
+
+	locker.err = errors.New("backend down")
+	if _, _, err := adapter.TryLock(context.Background(), "key"); err == nil {
+		t.Error("expected backend error to be returned")
+	}
+
+	locker.err = nil
+	locker.lock = held
+
+	unlock, acquired, err := adapter.TryLock(context.Background(), "key")
+	if !acquired || err != nil {
+		t.Fatalf("expected lock to be acquired, got %v, %v", acquired, err)
+	}
+
+	unlock()
+
+	if !held.released.Load() {
+		t.Error("expected unlock to release the lock")
+	}
+}
@@ -0,0 +1,65 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// AWSClient is the subset of *secretsmanager.Client used by the AWS provider.
+type AWSClient interface {
+	GetSecretValue(
+		ctx context.Context,
+		params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options),
+	) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+type awsProvider struct {
+	client AWSClient
+}
+
+// AWSProvider returns a Provider reading the current version of secrets from
+// AWS Secrets Manager. Secret names may be names or ARNs; binary secrets are
+// returned as their raw bytes.
+func AWSProvider(client AWSClient) Provider {
+	return awsProvider{client: client}
+}
+
+// NewAWSProvider returns an AWSProvider with a client built from the default AWS
+// configuration (environment, shared config files, instance roles).
+//
+// Example:
+//
+//	p, err := secrets.NewAWSProvider(ctx, config.WithRegion("eu-west-1"))
+func NewAWSProvider(ctx context.Context, optFns ...func(*config.LoadOptions) error) (Provider, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("secrets - NewAWSProvider - config.LoadDefaultConfig: %w", err)
+	}
+
+	return AWSProvider(secretsmanager.NewFromConfig(cfg)), nil
+}
+
+func (p awsProvider) Get(ctx context.Context, name string) (string, error) {
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return "", fmt.Errorf("secrets - AWSProvider - %s: %w", name, ErrNotFound)
+		}
+
+		return "", fmt.Errorf("secrets - AWSProvider - p.client.GetSecretValue: %w", err)
+	}
+
+	if out.SecretString != nil {
+		return *out.SecretString, nil
+	}
+
+	return string(out.SecretBinary), nil
+}
@@ -0,0 +1,35 @@
+package secrets
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// TTL sets how long fetched values are served from cache.
+// Default is 5 minutes.
+func TTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// RefreshInterval periodically fetches every cached secret again,
+// notifying Watch callbacks of rotated values.
+// Default is 0, no periodic refresh.
+func RefreshInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.refreshInterval = interval
+	}
+}
+
+// WithLogger logs background refresh errors.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(m *Manager) {
+		m.logger = l
+	}
+}
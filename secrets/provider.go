@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type staticProvider map[string]string
+
+// StaticProvider returns a Provider serving fixed values, e.g. in tests.
+func StaticProvider(values map[string]string) Provider {
+	return staticProvider(values)
+}
+
+func (p staticProvider) Get(_ context.Context, name string) (string, error) {
+	value, ok := p[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+type envProvider struct {
+	prefix string
+}
+
+// EnvProvider returns a Provider reading secrets from environment variables, meant as
+// a fallback for local development. The variable name is prefix followed by the secret
+// name in upper case, with characters other than letters and digits replaced by "_":
+// "db/app" is read from DB_APP.
+func EnvProvider(prefix string) Provider {
+	return envProvider{prefix: prefix}
+}
+
+func (p envProvider) Get(_ context.Context, name string) (string, error) {
+	key := p.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("secrets - EnvProvider - %s: %w", key, ErrNotFound)
+	}
+
+	return value, nil
+}
+
+type chainProvider []Provider
+
+// ChainProvider returns a Provider trying providers in order until one has the secret.
+//
+// Example:
+//
+//	p := secrets.ChainProvider(secrets.VaultProvider(addr, token), secrets.EnvProvider(""))
+func ChainProvider(providers ...Provider) Provider {
+	return chainProvider(providers)
+}
+
+func (p chainProvider) Get(ctx context.Context, name string) (string, error) {
+	for _, provider := range p {
+		value, err := provider.Get(ctx, name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		return value, err
+	}
+
+	return "", ErrNotFound
+}
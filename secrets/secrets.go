@@ -0,0 +1,288 @@
+// Package secrets resolves secrets from secret managers (HashiCorp Vault, AWS Secrets
+// Manager) with an environment fallback, caching values with a TTL and notifying
+// rotation callbacks when a value changes. Expand resolves secret references inside
+// configuration values, so DSNs and passwords never live in plain env vars.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const _defaultTTL = 5 * time.Minute
+
+// ErrNotFound is returned when a secret or one of its fields does not exist.
+var ErrNotFound = errors.New("secrets - secret not found")
+
+// Provider fetches secret values from a backend.
+// Get returns ErrNotFound (possibly wrapped) when the secret does not exist.
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Get calls f(ctx, name).
+func (f ProviderFunc) Get(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+type watcher struct {
+	field string
+	fn    func(value string)
+	last  string
+}
+
+// Manager resolves secrets through a Provider and caches them.
+//
+// A secret reference is a secret name optionally followed by "#field", selecting a field
+// of a JSON object secret, e.g. "db/app#password". Vault secrets are always JSON objects.
+type Manager struct {
+	provider        Provider
+	ttl             time.Duration
+	refreshInterval time.Duration
+	logger          logger.LoggerI
+
+	mu       sync.Mutex
+	cache    map[string]entry
+	watchers map[string][]*watcher
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new Manager resolving secrets through provider.
+// Default configuration: values cached for 5 minutes, no background refresh.
+//
+// Example:
+//
+//	vault := secrets.VaultProvider("https://vault:8200", os.Getenv("VAULT_TOKEN"))
+//	m := secrets.New(secrets.ChainProvider(vault, secrets.EnvProvider("")),
+//	    secrets.RefreshInterval(time.Minute),
+//	)
+//	defer m.Close()
+func New(provider Provider, opts ...Option) *Manager {
+	m := &Manager{
+		provider: provider,
+		ttl:      _defaultTTL,
+		cache:    make(map[string]entry),
+		watchers: make(map[string][]*watcher),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	if m.refreshInterval > 0 {
+		m.wg.Add(1)
+
+		go m.refresh(ctx)
+	}
+
+	return m
+}
+
+// Get returns the value of the secret reference ref, from cache when fresh.
+func (m *Manager) Get(ctx context.Context, ref string) (string, error) {
+	name, field, _ := strings.Cut(ref, "#")
+
+	m.mu.Lock()
+	e, ok := m.cache[name]
+	m.mu.Unlock()
+
+	value := e.value
+
+	if !ok || time.Now().After(e.expiresAt) {
+		var err error
+
+		value, err = m.fetch(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("secrets - Manager - Get - %s: %w", name, err)
+		}
+	}
+
+	value, err := extract(value, field)
+	if err != nil {
+		return "", fmt.Errorf("secrets - Manager - Get - %s: %w", ref, err)
+	}
+
+	return value, nil
+}
+
+// Watch returns the current value of ref and calls fn with the new value whenever a
+// refresh observes a rotation. Rotations are detected when a cached value expires and
+// is fetched again, by Refresh, and periodically with the RefreshInterval option.
+//
+// Example:
+//
+//	password, err := m.Watch(ctx, "db/app#password", func(password string) {
+//	    pool.Reset() // reconnect with the rotated credentials
+//	})
+func (m *Manager) Watch(ctx context.Context, ref string, fn func(value string)) (string, error) {
+	value, err := m.Get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	name, field, _ := strings.Cut(ref, "#")
+
+	m.mu.Lock()
+	m.watchers[name] = append(m.watchers[name], &watcher{field: field, fn: fn, last: value})
+	m.mu.Unlock()
+
+	return value, nil
+}
+
+// Refresh fetches every cached secret again, notifying watchers of rotated values.
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.cache))
+	for name := range m.cache {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+
+	var errs []error
+
+	for _, name := range names {
+		if _, err := m.fetch(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("secrets - Manager - Refresh - %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+var referencePattern = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// Expand replaces every "${secret:<ref>}" reference in s with the secret value,
+// resolving configuration values such as DSNs.
+//
+// Example:
+//
+//	// PG_URL=postgres://app:${secret:db/app#password}@db:5432/app
+//	dsn, err := m.Expand(ctx, os.Getenv("PG_URL"))
+func (m *Manager) Expand(ctx context.Context, s string) (string, error) {
+	var err error
+
+	expanded := referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		var value string
+
+		value, err = m.Get(ctx, referencePattern.FindStringSubmatch(match)[1])
+
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
+
+// Close stops the background refresh.
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// fetch loads name from the provider, caches it and notifies watchers of changed values.
+func (m *Manager) fetch(ctx context.Context, name string) (string, error) {
+	value, err := m.provider.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	type notification struct {
+		fn    func(string)
+		value string
+	}
+
+	var notify []notification
+
+	m.mu.Lock()
+	m.cache[name] = entry{value: value, expiresAt: time.Now().Add(m.ttl)}
+
+	for _, w := range m.watchers[name] {
+		v, err := extract(value, w.field)
+		if err != nil || v == w.last {
+			continue
+		}
+
+		w.last = v
+		notify = append(notify, notification{fn: w.fn, value: v})
+	}
+	m.mu.Unlock()
+
+	for _, n := range notify {
+		n.fn(n.value)
+	}
+
+	return value, nil
+}
+
+func (m *Manager) refresh(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Refresh(ctx); err != nil && m.logger != nil {
+				m.logger.Error(err, "secrets - Manager - refresh")
+			}
+		}
+	}
+}
+
+// extract returns field of the JSON object value, or value itself when field is empty.
+func extract(value, field string) (string, error) {
+	if field == "" {
+		return value, nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+
+	v, ok := object[field]
+	if !ok {
+		return "", fmt.Errorf("field %q: %w", field, ErrNotFound)
+	}
+
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return string(b), nil
+}
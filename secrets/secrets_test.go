@@ -0,0 +1,241 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/rdashevsky/go-pkgs/secrets"
+)
+
+func TestManagerGet(t *testing.T) {
+	m := secrets.New(secrets.StaticProvider(map[string]string{
+		"api-key": "k-1",
+		"db/app":  `{"user":"app","password":"s3cret","port":5432}`,
+	}))
+	defer m.Close()
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+		notFound bool
+	}{
+		{name: "plain secret", ref: "api-key", expected: "k-1"},
+		{name: "string field", ref: "db/app#password", expected: "s3cret"},
+		{name: "number field", ref: "db/app#port", expected: "5432"},
+		{name: "missing field", ref: "db/app#host", notFound: true},
+		{name: "missing secret", ref: "unknown", notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := m.Get(context.Background(), tt.ref)
+			if tt.notFound {
+				if !errors.Is(err, secrets.ErrNotFound) {
+					t.Errorf("Get() error = %v, want ErrNotFound", err)
+				}
+
+				return
+			}
+
+			if err != nil || value != tt.expected {
+				t.Errorf("Get() = %q, %v, want %q", value, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestManagerCache(t *testing.T) {
+	var calls atomic.Int32
+
+	m := secrets.New(secrets.ProviderFunc(func(context.Context, string) (string, error) {
+		calls.Add(1)
+
+		return "value", nil
+	}), secrets.TTL(20*time.Millisecond))
+	defer m.Close()
+
+	for range 3 {
+		if _, err := m.Get(context.Background(), "name"); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("provider calls = %d, want 1", calls.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := m.Get(context.Background(), "name"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("provider calls after expiry = %d, want 2", calls.Load())
+	}
+}
+
+func TestManagerWatch(t *testing.T) {
+	var password atomic.Value
+	password.Store("v1")
+
+	m := secrets.New(secrets.ProviderFunc(func(context.Context, string) (string, error) {
+		return `{"password":"` + password.Load().(string) + `","user":"app"}`, nil
+	}))
+	defer m.Close()
+
+	var rotated []string
+
+	value, err := m.Watch(context.Background(), "db#password", func(v string) {
+		rotated = append(rotated, v)
+	})
+	if err != nil || value != "v1" {
+		t.Fatalf("Watch() = %q, %v, want v1", value, err)
+	}
+
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	password.Store("v2")
+
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if len(rotated) != 1 || rotated[0] != "v2" {
+		t.Errorf("rotations = %v, want [v2]", rotated)
+	}
+
+	if value, _ := m.Get(context.Background(), "db#password"); value != "v2" {
+		t.Errorf("Get() after rotation = %q, want v2", value)
+	}
+}
+
+func TestManagerExpand(t *testing.T) {
+	m := secrets.New(secrets.StaticProvider(map[string]string{
+		"db/app": `{"user":"app","password":"s3cret"}`,
+	}))
+	defer m.Close()
+
+	dsn, err := m.Expand(context.Background(), "postgres://${secret:db/app#user}:${secret:db/app#password}@db:5432/app")
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	if dsn != "postgres://app:s3cret@db:5432/app" {
+		t.Errorf("Expand() = %q", dsn)
+	}
+
+	if _, err := m.Expand(context.Background(), "${secret:missing}"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Expand() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "from-env")
+
+	p := secrets.EnvProvider("APP_")
+
+	value, err := p.Get(context.Background(), "db/password")
+	if err != nil || value != "from-env" {
+		t.Errorf("Get() = %q, %v, want from-env", value, err)
+	}
+
+	if _, err := p.Get(context.Background(), "missing"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChainProvider(t *testing.T) {
+	failing := secrets.ProviderFunc(func(context.Context, string) (string, error) {
+		return "", errors.New("unavailable")
+	})
+
+	p := secrets.ChainProvider(
+		secrets.StaticProvider(map[string]string{"a": "primary"}),
+		secrets.StaticProvider(map[string]string{"a": "fallback", "b": "fallback"}),
+	)
+
+	if value, _ := p.Get(context.Background(), "a"); value != "primary" {
+		t.Errorf("Get(a) = %q, want primary", value)
+	}
+
+	if value, _ := p.Get(context.Background(), "b"); value != "fallback" {
+		t.Errorf("Get(b) = %q, want fallback", value)
+	}
+
+	if _, err := secrets.ChainProvider(failing, secrets.EnvProvider("")).Get(context.Background(), "b"); err == nil {
+		t.Error("Get() must not fall back on provider failures")
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if r.URL.Path != "/v1/kv/data/db/app" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"data":{"password":"s3cret"},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	m := secrets.New(secrets.VaultProvider(srv.URL, "token", secrets.VaultMount("kv")))
+	defer m.Close()
+
+	value, err := m.Get(context.Background(), "db/app#password")
+	if err != nil || value != "s3cret" {
+		t.Errorf("Get() = %q, %v, want s3cret", value, err)
+	}
+
+	if _, err := m.Get(context.Background(), "db/other"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := secrets.VaultProvider(srv.URL, "wrong").Get(context.Background(), "db/app"); err == nil {
+		t.Error("Get() with an invalid token must fail")
+	}
+}
+
+type fakeAWS map[string]string
+
+func (f fakeAWS) GetSecretValue(
+	_ context.Context,
+	in *secretsmanager.GetSecretValueInput,
+	_ ...func(*secretsmanager.Options),
+) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f[*in.SecretId]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{}
+	}
+
+	return &secretsmanager.GetSecretValueOutput{SecretString: &value}, nil
+}
+
+func TestAWSProvider(t *testing.T) {
+	p := secrets.AWSProvider(fakeAWS{"prod/api": "key"})
+
+	if value, err := p.Get(context.Background(), "prod/api"); err != nil || value != "key" {
+		t.Errorf("Get() = %q, %v, want key", value, err)
+	}
+
+	if _, err := p.Get(context.Background(), "missing"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
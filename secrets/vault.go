@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	_defaultVaultMount   = "secret"
+	_defaultVaultTimeout = 10 * time.Second
+)
+
+// VaultOption configures a Vault provider.
+type VaultOption func(*vaultProvider)
+
+// VaultMount sets the mount path of the KV version 2 secrets engine.
+// Default is "secret".
+func VaultMount(mount string) VaultOption {
+	return func(p *vaultProvider) {
+		p.mount = strings.Trim(mount, "/")
+	}
+}
+
+// VaultNamespace sets the Vault Enterprise namespace.
+// Default is no namespace.
+func VaultNamespace(namespace string) VaultOption {
+	return func(p *vaultProvider) {
+		p.namespace = namespace
+	}
+}
+
+// VaultHTTPClient sets the HTTP client used to reach Vault, e.g. one configured with
+// the Vault CA certificate.
+// Default is a client with a 10 seconds timeout.
+func VaultHTTPClient(client *http.Client) VaultOption {
+	return func(p *vaultProvider) {
+		p.client = client
+	}
+}
+
+type vaultProvider struct {
+	addr      string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+}
+
+// VaultProvider returns a Provider reading the latest version of secrets from a
+// HashiCorp Vault KV version 2 engine through the Vault HTTP API. Values are returned
+// as a JSON object of the secret data; select a field with a "name#field" reference.
+//
+// Example:
+//
+//	p := secrets.VaultProvider("https://vault:8200", os.Getenv("VAULT_TOKEN"), secrets.VaultMount("kv"))
+//	password, err := secrets.New(p).Get(ctx, "db/app#password")
+func VaultProvider(addr, token string, opts ...VaultOption) Provider {
+	p := &vaultProvider{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		mount:  _defaultVaultMount,
+		client: &http.Client{Timeout: _defaultVaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func (p *vaultProvider) Get(ctx context.Context, name string) (string, error) {
+	u := p.addr + "/v1/" + p.mount + "/data/" + (&url.URL{Path: strings.Trim(name, "/")}).EscapedPath()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("secrets - VaultProvider - http.NewRequestWithContext: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", p.token)
+
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets - VaultProvider - p.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("secrets - VaultProvider - %s: %w", name, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+		return "", fmt.Errorf("secrets - VaultProvider - unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var secret struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("secrets - VaultProvider - json.Decode: %w", err)
+	}
+
+	// A deleted latest version is returned with null data.
+	if len(secret.Data.Data) == 0 || string(secret.Data.Data) == "null" {
+		return "", fmt.Errorf("secrets - VaultProvider - %s: %w", name, ErrNotFound)
+	}
+
+	return string(secret.Data.Data), nil
+}
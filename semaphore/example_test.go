@@ -0,0 +1,38 @@
+package semaphore_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
+
+func ExampleNew() {
+	sem := semaphore.New(10)
+
+	// A heavy export takes 5 slots, leaving 5 for regular queries.
+	if err := sem.Acquire(context.Background(), 5); err != nil {
+		fmt.Println(err)
+
+		return
+	}
+	defer sem.Release(5)
+
+	fmt.Println("in use:", sem.InUse(), "of", sem.Capacity())
+
+	// Output:
+	// in use: 5 of 10
+}
+
+func ExampleNewKeyed() {
+	perTenant := semaphore.NewKeyed(1)
+
+	fmt.Println(perTenant.TryAcquire("acme", 1))
+	fmt.Println(perTenant.TryAcquire("acme", 1))
+	fmt.Println(perTenant.TryAcquire("globex", 1))
+
+	// Output:
+	// true
+	// false
+	// true
+}
@@ -0,0 +1,115 @@
+package semaphore
+
+import (
+	"context"
+	"sync"
+)
+
+type keyedEntry struct {
+	sem  *Semaphore
+	refs int
+}
+
+// Keyed holds one Semaphore of the same capacity per key, e.g. to bound concurrent
+// requests per tenant or per downstream host. Semaphores are created on first use
+// and dropped once no caller holds or waits for them. It is safe for concurrent use.
+type Keyed struct {
+	capacity int64
+	opts     []Option
+
+	mu   sync.Mutex
+	sems map[string]*keyedEntry
+}
+
+// NewKeyed creates a new Keyed semaphore allowing a total weight of capacity per key.
+// The options, including the Metrics, apply to every per-key semaphore.
+//
+// Example:
+//
+//	perTenant := semaphore.NewKeyed(4)
+//	if err := perTenant.Acquire(ctx, tenantID, 1); err != nil {
+//	    return err
+//	}
+//	defer perTenant.Release(tenantID, 1)
+func NewKeyed(capacity int64, opts ...Option) *Keyed {
+	return &Keyed{
+		capacity: capacity,
+		opts:     opts,
+		sems:     make(map[string]*keyedEntry),
+	}
+}
+
+// Acquire blocks until a weight of n is available for key or ctx is done.
+// On success the caller must call Release(key, n).
+func (k *Keyed) Acquire(ctx context.Context, key string, n int64) error {
+	e := k.ref(key)
+
+	if err := e.sem.Acquire(ctx, n); err != nil {
+		k.unref(key, e)
+
+		return err
+	}
+
+	return nil
+}
+
+// TryAcquire acquires a weight of n for key without blocking, reporting whether it succeeded.
+func (k *Keyed) TryAcquire(key string, n int64) bool {
+	e := k.ref(key)
+
+	if !e.sem.TryAcquire(n) {
+		k.unref(key, e)
+
+		return false
+	}
+
+	return true
+}
+
+// Release releases a weight of n acquired for key. Every successful Acquire or
+// TryAcquire must be matched by exactly one Release. It panics when nothing is held for key.
+func (k *Keyed) Release(key string, n int64) {
+	k.mu.Lock()
+	e, ok := k.sems[key]
+	k.mu.Unlock()
+
+	if !ok {
+		panic("semaphore - Keyed - released a key that is not held")
+	}
+
+	e.sem.Release(n)
+	k.unref(key, e)
+}
+
+// Len returns the number of keys currently held or waited for.
+func (k *Keyed) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return len(k.sems)
+}
+
+func (k *Keyed) ref(key string) *keyedEntry {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.sems[key]
+	if !ok {
+		e = &keyedEntry{sem: New(k.capacity, k.opts...)}
+		k.sems[key] = e
+	}
+
+	e.refs++
+
+	return e
+}
+
+func (k *Keyed) unref(key string, e *keyedEntry) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e.refs--
+	if e.refs == 0 {
+		delete(k.sems, key)
+	}
+}
@@ -0,0 +1,55 @@
+package semaphore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
+
+func TestKeyed(t *testing.T) {
+	ctx := context.Background()
+	k := semaphore.NewKeyed(1)
+
+	if err := k.Acquire(ctx, "a", 1); err != nil {
+		t.Fatalf("Acquire(a) error = %v", err)
+	}
+
+	if !k.TryAcquire("b", 1) {
+		t.Error("TryAcquire(b) failed, keys must be independent")
+	}
+
+	if k.TryAcquire("a", 1) {
+		t.Error("TryAcquire(a) succeeded beyond the per-key capacity")
+	}
+
+	if got := k.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := k.Acquire(waitCtx, "a", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire(a) error = %v, want DeadlineExceeded", err)
+	}
+
+	k.Release("a", 1)
+	k.Release("b", 1)
+
+	if got := k.Len(); got != 0 {
+		t.Errorf("Len() after Release = %d, want 0", got)
+	}
+}
+
+func TestKeyed_ReleaseUnknownKeyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+
+	semaphore.NewKeyed(1).Release("missing", 1)
+}
@@ -0,0 +1,30 @@
+package semaphore
+
+// Option configures a Semaphore.
+type Option func(*config)
+
+type config struct {
+	metrics Metrics
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		metrics: noopMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithMetrics sets the hook receiving wait time and queue depth measurements.
+// Default is no metrics.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		if m != nil {
+			c.metrics = m
+		}
+	}
+}
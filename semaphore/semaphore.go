@@ -0,0 +1,203 @@
+// Package semaphore provides weighted, context-aware semaphores bounding concurrent
+// work such as DB-heavy HTTP handlers or message consumers, Keyed semaphores
+// limiting concurrency per key, and metrics hooks on wait time and queue depth.
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTooLarge is returned by Acquire when the requested weight exceeds the capacity.
+var ErrTooLarge = errors.New("semaphore - weight exceeds capacity")
+
+// Metrics receives semaphore measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveWait is called after every Acquire with the time spent waiting and its error.
+	ObserveWait(duration time.Duration, err error)
+	// ObserveQueueDepth is called with the number of waiting Acquire calls whenever one starts waiting.
+	ObserveQueueDepth(depth int)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveWait(time.Duration, error) {}
+func (noopMetrics) ObserveQueueDepth(int)            {}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Semaphore limits the total weight of concurrent holders to its capacity.
+// Waiters are served in FIFO order, so a large request is not starved by smaller ones.
+// It is safe for concurrent use.
+type Semaphore struct {
+	capacity int64
+	metrics  Metrics
+
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List
+}
+
+// New creates a new Semaphore with the given capacity.
+//
+// Example:
+//
+//	sem := semaphore.New(10, semaphore.WithMetrics(m))
+//	if err := sem.Acquire(ctx, 1); err != nil {
+//	    return err
+//	}
+//	defer sem.Release(1)
+func New(capacity int64, opts ...Option) *Semaphore {
+	cfg := newConfig(opts)
+
+	return &Semaphore{
+		capacity: capacity,
+		metrics:  cfg.metrics,
+	}
+}
+
+// Acquire blocks until a weight of n is available or ctx is done.
+// On success the caller must call Release(n). On failure it returns ctx.Err(),
+// or ErrTooLarge if n exceeds the capacity, and holds nothing.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+
+	if n > s.capacity {
+		s.mu.Unlock()
+		s.metrics.ObserveWait(0, ErrTooLarge)
+
+		return fmt.Errorf("%w: %d > %d", ErrTooLarge, n, s.capacity)
+	}
+
+	if s.capacity-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		s.metrics.ObserveWait(0, nil)
+
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.mu.Unlock()
+		s.metrics.ObserveWait(0, err)
+
+		return err
+	}
+
+	w := waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	depth := s.waiters.Len()
+	s.mu.Unlock()
+
+	s.metrics.ObserveQueueDepth(depth)
+
+	start := time.Now()
+
+	var err error
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		s.mu.Lock()
+
+		select {
+		case <-w.ready:
+			// Acquired right after ctx was done: keep the weight rather than fixing up the queue.
+		default:
+			front := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+
+			// Removing the front waiter may unblock the ones behind it.
+			if front {
+				s.notifyWaiters()
+			}
+
+			err = ctx.Err()
+		}
+
+		s.mu.Unlock()
+	}
+
+	s.metrics.ObserveWait(time.Since(start), err)
+
+	return err
+}
+
+// TryAcquire acquires a weight of n without blocking, reporting whether it succeeded.
+// It fails while other callers are waiting, to preserve FIFO order.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.capacity-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+
+		return true
+	}
+
+	return false
+}
+
+// Release releases a weight of n acquired with Acquire or TryAcquire.
+// It panics when releasing more than is held.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore - released more than held")
+	}
+
+	s.notifyWaiters()
+}
+
+// Capacity returns the maximum total weight of concurrent holders.
+func (s *Semaphore) Capacity() int64 {
+	return s.capacity
+}
+
+// InUse returns the weight currently held.
+func (s *Semaphore) InUse() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cur
+}
+
+// Waiting returns the number of Acquire calls waiting for weight.
+func (s *Semaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.waiters.Len()
+}
+
+// notifyWaiters wakes waiters in FIFO order while their weight fits.
+// It stops at the first waiter that does not fit, so large requests are not starved.
+// The caller must hold s.mu.
+func (s *Semaphore) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+
+		w := next.Value.(waiter) //nolint:forcetypeassert // only waiters are queued
+		if s.capacity-s.cur < w.n {
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,184 @@
+package semaphore_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/semaphore"
+)
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	waits    int
+	errs     int
+	maxDepth int
+}
+
+func (m *recordingMetrics) ObserveWait(_ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.waits++
+	if err != nil {
+		m.errs++
+	}
+}
+
+func (m *recordingMetrics) ObserveQueueDepth(depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maxDepth = max(m.maxDepth, depth)
+}
+
+func TestSemaphore_AcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	sem := semaphore.New(3)
+
+	if err := sem.Acquire(ctx, 2); err != nil {
+		t.Fatalf("Acquire(2) error = %v", err)
+	}
+
+	if sem.TryAcquire(2) {
+		t.Error("TryAcquire(2) succeeded beyond capacity")
+	}
+
+	if !sem.TryAcquire(1) {
+		t.Error("TryAcquire(1) failed with 1 available")
+	}
+
+	if got := sem.InUse(); got != 3 {
+		t.Errorf("InUse() = %d, want 3", got)
+	}
+
+	sem.Release(3)
+
+	if got := sem.InUse(); got != 0 {
+		t.Errorf("InUse() after Release = %d, want 0", got)
+	}
+
+	if err := sem.Acquire(ctx, 4); !errors.Is(err, semaphore.ErrTooLarge) {
+		t.Errorf("Acquire(4) error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestSemaphore_ContextDone(t *testing.T) {
+	sem := semaphore.New(1)
+	sem.TryAcquire(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := sem.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() error = %v, want DeadlineExceeded", err)
+	}
+
+	if got := sem.Waiting(); got != 0 {
+		t.Errorf("Waiting() after cancellation = %d, want 0", got)
+	}
+
+	sem.Release(1)
+
+	if !sem.TryAcquire(1) {
+		t.Error("cancelled Acquire leaked weight")
+	}
+}
+
+func TestSemaphore_FIFO(t *testing.T) {
+	sem := semaphore.New(2)
+	sem.TryAcquire(2)
+
+	// A large waiter queued first must not be overtaken by smaller ones.
+	large := make(chan struct{})
+
+	go func() {
+		_ = sem.Acquire(context.Background(), 2)
+		close(large)
+	}()
+
+	for sem.Waiting() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	sem.Release(1)
+
+	if sem.TryAcquire(1) {
+		t.Fatal("TryAcquire(1) overtook a queued waiter")
+	}
+
+	sem.Release(1)
+
+	select {
+	case <-large:
+	case <-time.After(time.Second):
+		t.Fatal("large waiter was not woken")
+	}
+}
+
+func TestSemaphore_BoundsConcurrency(t *testing.T) {
+	m := &recordingMetrics{}
+	sem := semaphore.New(3, semaphore.WithMetrics(m))
+
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int32
+		peak    atomic.Int32
+	)
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := sem.Acquire(context.Background(), 1); err != nil {
+				t.Errorf("Acquire() error = %v", err)
+
+				return
+			}
+			defer sem.Release(1)
+
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(2 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+
+	wg.Wait()
+
+	if peak.Load() > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak.Load())
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.waits != 20 || m.errs != 0 {
+		t.Errorf("metrics waits = %d, errs = %d; want 20, 0", m.waits, m.errs)
+	}
+
+	if m.maxDepth == 0 {
+		t.Error("expected queue depth to be observed")
+	}
+}
+
+func TestSemaphore_ReleaseTooMuchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+
+	semaphore.New(1).Release(1)
+}
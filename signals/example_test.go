@@ -0,0 +1,22 @@
+package signals_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/signals"
+)
+
+func ExampleHooks() {
+	hooks := signals.NewHooks()
+	hooks.Add("postgres", func(context.Context) error { fmt.Println("close postgres"); return nil }, signals.Order(10))
+	hooks.Add("server", func(context.Context) error { fmt.Println("stop server"); return nil })
+	hooks.Add("consumers", func(context.Context) error { fmt.Println("stop consumers"); return nil })
+
+	_ = hooks.Run(context.Background())
+
+	// Output:
+	// stop consumers
+	// stop server
+	// close postgres
+}
@@ -0,0 +1,118 @@
+package signals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Hooks runs cleanup functions at shutdown: hooks run by increasing order, and hooks
+// of the same order in reverse registration order, like deferred calls. Hooks
+// implements the app.Component interface, so it can be added to an app runner,
+// which runs it when it stops.
+//
+// A Hooks is safe for concurrent use.
+type Hooks struct {
+	mu    sync.Mutex
+	hooks []hook
+	ran   bool
+}
+
+type hook struct {
+	name  string
+	fn    func(ctx context.Context) error
+	order int
+	seq   int
+}
+
+// HookOption configures a hook.
+type HookOption func(*hook)
+
+// Order sets the order of a hook; lower orders run first. Default is 0.
+func Order(n int) HookOption {
+	return func(h *hook) {
+		h.order = n
+	}
+}
+
+// NewHooks creates an empty set of hooks.
+//
+// Example:
+//
+//	hooks := signals.NewHooks()
+//	hooks.Add("postgres", func(context.Context) error { pg.Close(); return nil }, signals.Order(10))
+//	hooks.Add("flush metrics", exporter.Shutdown)
+//
+//	ctx, stop := signals.NotifyContext(context.Background())
+//	defer stop()
+//	<-ctx.Done()
+//
+//	err := hooks.Run(shutdownCtx)
+func NewHooks() *Hooks {
+	return &Hooks{}
+}
+
+// Add registers a hook.
+func (h *Hooks) Add(name string, fn func(ctx context.Context) error, opts ...HookOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hk := hook{name: name, fn: fn, seq: len(h.hooks)}
+
+	for _, opt := range opts {
+		opt(&hk)
+	}
+
+	h.hooks = append(h.hooks, hk)
+}
+
+// Run runs the hooks once; later calls return nil. Every hook runs even when a
+// previous one failed, unless ctx is done: the remaining hooks are then skipped and
+// reported. The returned error joins the hook errors.
+func (h *Hooks) Run(ctx context.Context) error {
+	h.mu.Lock()
+	if h.ran {
+		h.mu.Unlock()
+
+		return nil
+	}
+
+	h.ran = true
+	hooks := slices.Clone(h.hooks)
+	h.mu.Unlock()
+
+	slices.SortFunc(hooks, func(a, b hook) int {
+		if a.order != b.order {
+			return a.order - b.order
+		}
+
+		return b.seq - a.seq
+	})
+
+	var errs []error
+
+	for _, hk := range hooks {
+		if ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("signals - hook %s: skipped: %w", hk.name, ctx.Err()))
+
+			continue
+		}
+
+		if err := hk.fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("signals - hook %s: %w", hk.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start implements app.Component; hooks only act at shutdown.
+func (h *Hooks) Start() {}
+
+// Shutdown implements app.Component by running the hooks. The app runner enforces
+// its shutdown deadline.
+func (h *Hooks) Shutdown() error {
+	return h.Run(context.Background())
+}
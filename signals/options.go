@@ -0,0 +1,73 @@
+package signals
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// _forceExitCode is the exit code of a forced shutdown.
+const _forceExitCode = 1
+
+// Option is a function that configures NotifyContext.
+type Option func(*config)
+
+type config struct {
+	signals []os.Signal
+	timeout time.Duration
+	force   func()
+	logger  logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		force:   func() { os.Exit(_forceExitCode) },
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+func (c *config) info(message string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Info(message, args...)
+	}
+}
+
+// Signals sets the OS signals that start and force the shutdown.
+// Default is SIGINT and SIGTERM.
+func Signals(signals ...os.Signal) Option {
+	return func(c *config) {
+		c.signals = signals
+	}
+}
+
+// Timeout sets the drain deadline: the shutdown is forced when it is not complete
+// this long after it started. Default is no deadline, only a second signal forces it.
+func Timeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// OnForce sets the function forcing the shutdown. Default is exiting the process
+// with status 1.
+func OnForce(fn func()) Option {
+	return func(c *config) {
+		c.force = fn
+	}
+}
+
+// WithLogger sets a logger for received signals and forced exits.
+// Default is no logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
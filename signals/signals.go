@@ -0,0 +1,79 @@
+// Package signals replaces ad-hoc signal handling in main functions with a two-phase
+// shutdown: the first SIGINT or SIGTERM cancels the context returned by NotifyContext
+// to start a graceful drain, and a second signal, or the drain deadline, forces the
+// process to exit. Hooks run cleanup functions in a defined order at shutdown, on
+// their own or as a component of the app runner, which uses NotifyContext itself.
+package signals
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// NotifyContext returns a copy of parent cancelled by the first configured signal,
+// which starts the graceful shutdown. A second signal, or the drain deadline set with
+// Timeout, then calls the force function, which exits the process by default. Calling
+// stop marks the shutdown as complete: it releases the signals and disarms the force
+// exit. Cancelling parent also starts the shutdown phase.
+//
+// Example:
+//
+//	ctx, stop := signals.NotifyContext(context.Background(), signals.Timeout(30*time.Second))
+//	defer stop()
+//
+//	go server.Start()
+//	<-ctx.Done()
+//	err := server.Shutdown()
+func NotifyContext(parent context.Context, opts ...Option) (ctx context.Context, stop context.CancelFunc) {
+	cfg := newConfig(opts)
+
+	ctx, cancel := context.WithCancel(parent)
+
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, cfg.signals...)
+
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-received:
+			cfg.info("signals - received %s, shutting down", sig)
+			cancel()
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		var deadline <-chan time.Time
+
+		if cfg.timeout > 0 {
+			timer := time.NewTimer(cfg.timeout)
+			defer timer.Stop()
+
+			deadline = timer.C
+		}
+
+		select {
+		case sig := <-received:
+			cfg.info("signals - received %s during shutdown, forcing exit", sig)
+			cfg.force()
+		case <-deadline:
+			cfg.info("signals - shutdown not complete after %s, forcing exit", cfg.timeout)
+			cfg.force()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+			cancel()
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package signals_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/signals"
+)
+
+func TestNotifyContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []signals.Option
+		second    bool
+		wantForce bool
+	}{
+		{name: "graceful", opts: nil},
+		{name: "second signal forces", second: true, wantForce: true},
+		{name: "deadline forces", opts: []signals.Option{signals.Timeout(20 * time.Millisecond)}, wantForce: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forced := make(chan struct{}, 1)
+			opts := append([]signals.Option{
+				signals.Signals(syscall.SIGUSR1),
+				signals.OnForce(func() { forced <- struct{}{} }),
+			}, tt.opts...)
+
+			ctx, stop := signals.NotifyContext(context.Background(), opts...)
+			defer stop()
+
+			if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+				t.Fatalf("syscall.Kill() error = %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Fatal("context not cancelled by the first signal")
+			}
+
+			if tt.second {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+			}
+
+			select {
+			case <-forced:
+				if !tt.wantForce {
+					t.Error("shutdown forced, want graceful")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantForce {
+					t.Error("shutdown not forced")
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyContext_Stop(t *testing.T) {
+	ctx, stop := signals.NotifyContext(context.Background(), signals.Signals(syscall.SIGUSR2))
+	stop()
+	stop()
+
+	if ctx.Err() == nil {
+		t.Error("context not cancelled by stop")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var order []string
+
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+
+			return err
+		}
+	}
+
+	h := signals.NewHooks()
+	h.Add("cache", record("cache", nil))
+	h.Add("postgres", record("postgres", nil), signals.Order(10))
+	h.Add("server", record("server", nil), signals.Order(-1))
+	h.Add("metrics", record("metrics", errors.New("exporter down")))
+
+	err := h.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "signals - hook metrics: exporter down") {
+		t.Errorf("Run() error = %v, want the metrics error", err)
+	}
+
+	if got := strings.Join(order, ","); got != "server,metrics,cache,postgres" {
+		t.Errorf("run order = %s, want server,metrics,cache,postgres", got)
+	}
+
+	if err := h.Shutdown(); err != nil || len(order) != 4 {
+		t.Errorf("second run = %v, ran %d hooks, want nil and no hook run again", err, len(order))
+	}
+}
+
+func TestHooks_ContextDone(t *testing.T) {
+	ran := false
+
+	h := signals.NewHooks()
+	h.Add("postgres", func(context.Context) error { ran = true; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := h.Run(ctx)
+	if ran || !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() with a done context = %v, ran %v, want skipped hooks", err, ran)
+	}
+}
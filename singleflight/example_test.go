@@ -0,0 +1,43 @@
+package singleflight_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/singleflight"
+)
+
+func ExampleGroup_Do() {
+	rates := singleflight.New[float64](singleflight.Timeout(time.Second))
+
+	var upstreamCalls atomic.Int32
+
+	fetch := func(context.Context) (float64, error) {
+		upstreamCalls.Add(1)
+		time.Sleep(50 * time.Millisecond) // an expensive upstream request
+
+		return 1.08, nil
+	}
+
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _, _ = rates.Do(context.Background(), "EUR/USD", fetch)
+		}()
+	}
+
+	wg.Wait()
+
+	fmt.Println("upstream calls:", upstreamCalls.Load())
+
+	// Output:
+	// upstream calls: 1
+}
@@ -0,0 +1,29 @@
+package singleflight
+
+import "time"
+
+// Option configures a Group.
+type Option func(*config)
+
+type config struct {
+	timeout time.Duration
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Timeout bounds each call: the context passed to fn is cancelled after timeout,
+// whatever the callers' deadlines.
+// Default is 0: calls are only cancelled when every caller gave up.
+func Timeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
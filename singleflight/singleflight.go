@@ -0,0 +1,177 @@
+// Package singleflight coalesces concurrent calls for the same key into a single
+// execution whose result is shared by every caller, protecting expensive upstreams
+// from thundering herds. Unlike golang.org/x/sync/singleflight it is typed and
+// context-aware: the shared call runs detached from the caller that started it, so a
+// cancelled caller neither fails the others nor leaks its cancellation into the call,
+// and the call is cancelled once every caller has given up.
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanic is wrapped by the error returned to every caller of a call that panicked.
+var ErrPanic = errors.New("singleflight - call panicked")
+
+type call[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// waiters is the number of callers waiting for the call and shared reports
+	// whether more than one caller joined it. Both are guarded by Group.mu.
+	waiters int
+	shared  bool
+
+	value T
+	err   error
+}
+
+// Group coalesces calls returning values of type T.
+// The zero value is not usable; create groups with New. A Group is safe for concurrent use.
+type Group[T any] struct {
+	cfg config
+
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// New creates a new Group.
+// Default configuration: no timeout.
+//
+// Example:
+//
+//	rates := singleflight.New[Rates](singleflight.Timeout(5 * time.Second))
+//
+//	r, shared, err := rates.Do(ctx, currency, func(ctx context.Context) (Rates, error) {
+//	    return upstream.Rates(ctx, currency)
+//	})
+func New[T any](opts ...Option) *Group[T] {
+	return &Group[T]{
+		cfg:   newConfig(opts),
+		calls: make(map[string]*call[T]),
+	}
+}
+
+// Do runs fn once for all concurrent callers of key and returns its result to each
+// of them, along with whether the result was shared with other callers. Errors are
+// shared like values and not remembered: the next call after completion runs fn again.
+//
+// fn receives a context carrying the values of the first caller's ctx but not its
+// cancellation, bounded by the configured Timeout. When ctx is done, Do returns
+// ctx.Err() without waiting; fn's context is cancelled once no caller is waiting.
+// A panic in fn is returned to every caller as an error wrapping ErrPanic.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, bool, error) {
+	g.mu.Lock()
+
+	c, ok := g.calls[key]
+	if ok {
+		c.waiters++
+		c.shared = true
+	} else {
+		c = g.start(ctx, key, fn)
+	}
+
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared := c.shared
+		g.mu.Unlock()
+
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+
+		var zero T
+
+		return zero, false, ctx.Err()
+	}
+}
+
+// start runs fn for key in a new goroutine. It must be called with g.mu held.
+func (g *Group[T]) start(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) *call[T] {
+	var (
+		callCtx context.Context
+		cancel  context.CancelFunc
+	)
+
+	if g.cfg.timeout > 0 {
+		callCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), g.cfg.timeout)
+	} else {
+		callCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+
+	c := &call[T]{
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		waiters: 1,
+	}
+	g.calls[key] = c
+
+	go func() {
+		defer cancel()
+
+		c.value, c.err = run(callCtx, key, fn)
+
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	return c
+}
+
+// leave unregisters a caller that stopped waiting and cancels the call when it was
+// the last one.
+func (g *Group[T]) leave(key string, c *call[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+
+	c.cancel()
+
+	// New callers must not join a cancelled call.
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
+
+func run[T any](ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrPanic, key, r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// Forget makes the next call for key run fn again instead of joining the one in
+// flight, e.g. after invalidating the data it loads. Callers already waiting still
+// get the result of the call in flight.
+func (g *Group[T]) Forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.calls, key)
+}
+
+// InFlight returns the number of calls in flight.
+func (g *Group[T]) InFlight() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.calls)
+}
@@ -0,0 +1,226 @@
+package singleflight_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/singleflight"
+)
+
+func TestGroup_Coalesces(t *testing.T) {
+	g := singleflight.New[int]()
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	fn := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+
+		return 42, nil
+	}
+
+	const callers = 10
+
+	var (
+		wg     sync.WaitGroup
+		shared atomic.Int32
+	)
+
+	for range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, s, err := g.Do(context.Background(), "k", fn)
+			if err != nil || v != 42 {
+				t.Errorf("Do() = %d, %v; want 42", v, err)
+			}
+
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+
+	for g.InFlight() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Give the other callers time to join the call.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn calls = %d, want 1", calls.Load())
+	}
+
+	if shared.Load() != callers {
+		t.Errorf("shared results = %d, want %d", shared.Load(), callers)
+	}
+
+	if g.InFlight() != 0 {
+		t.Errorf("InFlight() = %d after completion, want 0", g.InFlight())
+	}
+
+	// Results are not remembered.
+	v, s, _ := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 7, nil })
+	if v != 7 || s {
+		t.Errorf("Do() after completion = %d, shared %v; want 7 not shared", v, s)
+	}
+}
+
+func TestGroup_SharedError(t *testing.T) {
+	g := singleflight.New[string]()
+	errUpstream := errors.New("upstream down")
+
+	tests := []struct {
+		name    string
+		fn      func(context.Context) (string, error)
+		wantErr error
+	}{
+		{name: "error", fn: func(context.Context) (string, error) { return "", errUpstream }, wantErr: errUpstream},
+		{name: "panic", fn: func(context.Context) (string, error) { panic("boom") }, wantErr: singleflight.ErrPanic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := g.Do(context.Background(), tt.name, tt.fn); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroup_CallerCancellation(t *testing.T) {
+	g := singleflight.New[int]()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var callCtx context.Context
+
+	fn := func(ctx context.Context) (int, error) {
+		callCtx = ctx
+		close(started)
+		<-release
+
+		return 1, ctx.Err()
+	}
+
+	// The first caller gives up; the second one still gets the result.
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, _, err := g.Do(firstCtx, "k", fn)
+		firstErr <- err
+	}()
+
+	<-started
+
+	secondDone := make(chan int, 1)
+
+	go func() {
+		v, _, _ := g.Do(context.Background(), "k", fn)
+		secondDone <- v
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancelFirst()
+
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first Do() error = %v, want context.Canceled", err)
+	}
+
+	if callCtx.Err() != nil {
+		t.Fatal("call cancelled while a caller is still waiting")
+	}
+
+	close(release)
+
+	if v := <-secondDone; v != 1 {
+		t.Errorf("second Do() = %d, want 1", v)
+	}
+}
+
+func TestGroup_CancelledWhenAbandoned(t *testing.T) {
+	g := singleflight.New[int]()
+
+	cancelled := make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err := g.Do(ctx, "k", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("call not cancelled after every caller gave up")
+	}
+}
+
+func TestGroup_Timeout(t *testing.T) {
+	g := singleflight.New[int](singleflight.Timeout(10 * time.Millisecond))
+
+	_, _, err := g.Do(context.Background(), "k", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestGroup_Forget(t *testing.T) {
+	g := singleflight.New[int]()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go func() {
+		_, _, _ = g.Do(context.Background(), "k", func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 1, nil
+		})
+	}()
+
+	<-started
+	g.Forget("k")
+
+	v, shared, err := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 2, nil })
+	if v != 2 || shared || err != nil {
+		t.Errorf("Do() after Forget() = %d, %v, %v; want a new call returning 2", v, shared, err)
+	}
+
+	close(release)
+}
+
+func BenchmarkGroup_Do(b *testing.B) {
+	g := singleflight.New[int]()
+	fn := func(context.Context) (int, error) { return 1, nil }
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _, _ = g.Do(context.Background(), "k", fn)
+		}
+	})
+}
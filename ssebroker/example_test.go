@@ -0,0 +1,28 @@
+package ssebroker_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/ssebroker"
+)
+
+func ExampleNew() {
+	broker := ssebroker.New(ssebroker.NewMemory(ssebroker.MaxLen(100)))
+	defer broker.Shutdown(context.Background())
+
+	app := fiber.New()
+	app.Get("/events/:user", broker.Handler(func(c *fiber.Ctx) string {
+		return "user:" + c.Params("user")
+	}))
+
+	id, err := broker.Publish(context.Background(), "user:42", ssebroker.Event{
+		Type: "notification",
+		Data: []byte(`{"text":"hello"}`),
+	})
+	fmt.Println(id != "", err)
+
+	// Output:
+	// true <nil>
+}
@@ -0,0 +1,140 @@
+package ssebroker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Memory is a Backend keeping the last events of each topic in process. It
+// suits single instance services and tests.
+type Memory struct {
+	cfg backendConfig
+
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+	lastMS uint64
+	seq    uint64
+}
+
+type memoryTopic struct {
+	events []Event
+	// changed is closed and replaced on every append to wake up listeners.
+	changed chan struct{}
+}
+
+var _ Backend = (*Memory)(nil)
+
+// NewMemory creates a Memory backend.
+func NewMemory(opts ...BackendOption) *Memory {
+	return &Memory{
+		cfg:    newBackendConfig(opts),
+		topics: make(map[string]*memoryTopic),
+	}
+}
+
+// Append implements Backend.
+func (m *Memory) Append(_ context.Context, topic string, e Event) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// IDs follow the Redis stream "<milliseconds>-<sequence>" format.
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= m.lastMS {
+		ms = m.lastMS
+		m.seq++
+	} else {
+		m.lastMS, m.seq = ms, 0
+	}
+
+	e.ID = strconv.FormatUint(ms, 10) + "-" + strconv.FormatUint(m.seq, 10)
+
+	t := m.topic(topic)
+	t.events = append(t.events, e)
+
+	if over := len(t.events) - m.cfg.maxLen; over > 0 {
+		t.events = append(t.events[:0:0], t.events[over:]...)
+	}
+
+	close(t.changed)
+	t.changed = make(chan struct{})
+
+	return e.ID, nil
+}
+
+// Since implements Backend.
+func (m *Memory) Since(_ context.Context, topic, lastID string) ([]Event, error) {
+	if lastID == "" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return after(m.topic(topic).events, lastID), nil
+}
+
+// Last implements Backend.
+func (m *Memory) Last(_ context.Context, topic string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := m.topic(topic).events
+	if len(events) == 0 {
+		return "", nil
+	}
+
+	return events[len(events)-1].ID, nil
+}
+
+// Listen implements Backend.
+func (m *Memory) Listen(ctx context.Context, topic, lastID string, fn func(Event)) error {
+	for {
+		m.mu.Lock()
+		t := m.topic(topic)
+		events := t.events
+
+		if lastID != "" {
+			events = after(events, lastID)
+		} else {
+			events = append([]Event(nil), events...)
+		}
+
+		changed := t.changed
+		m.mu.Unlock()
+
+		for _, e := range events {
+			fn(e)
+			lastID = e.ID
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// topic returns the topic state, creating it. The caller must hold m.mu.
+func (m *Memory) topic(name string) *memoryTopic {
+	t, ok := m.topics[name]
+	if !ok {
+		t = &memoryTopic{changed: make(chan struct{})}
+		m.topics[name] = t
+	}
+
+	return t
+}
+
+// after returns the events following lastID; events are sorted by ID.
+func after(events []Event, lastID string) []Event {
+	for i, e := range events {
+		if compareIDs(e.ID, lastID) > 0 {
+			return append([]Event(nil), events[i:]...)
+		}
+	}
+
+	return nil
+}
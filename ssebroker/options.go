@@ -0,0 +1,109 @@
+package ssebroker
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultHeartbeat  = 15 * time.Second
+	_defaultBufferSize = 64
+	_defaultMaxLen     = 1000
+	_defaultPrefix     = "sse:"
+)
+
+// Option configures a Broker.
+type Option func(*config)
+
+type config struct {
+	heartbeat  time.Duration
+	bufferSize int
+	retry      time.Duration
+	logger     logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		heartbeat:  _defaultHeartbeat,
+		bufferSize: _defaultBufferSize,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// HeartbeatInterval sets how often a comment is sent to idle streams, keeping
+// proxies from timing them out and detecting disconnected clients.
+// Default is 15s.
+func HeartbeatInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.heartbeat = d
+	}
+}
+
+// BufferSize sets the number of events buffered per subscriber before it is
+// considered too slow and disconnected.
+// Default is 64.
+func BufferSize(size int) Option {
+	return func(c *config) {
+		c.bufferSize = size
+	}
+}
+
+// Retry sets the reconnection delay advertised to clients.
+// Default is 0, which leaves the client default.
+func Retry(d time.Duration) Option {
+	return func(c *config) {
+		c.retry = d
+	}
+}
+
+// WithLogger sets the logger used to report backend failures.
+// Default is nil, which disables logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// BackendOption configures the built-in backends.
+type BackendOption func(*backendConfig)
+
+type backendConfig struct {
+	maxLen int
+	prefix string
+}
+
+func newBackendConfig(opts []BackendOption) backendConfig {
+	cfg := backendConfig{
+		maxLen: _defaultMaxLen,
+		prefix: _defaultPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// MaxLen sets the number of events kept per topic for replay. Redis trims
+// streams approximately, so slightly more may be kept.
+// Default is 1000.
+func MaxLen(n int) BackendOption {
+	return func(c *backendConfig) {
+		c.maxLen = n
+	}
+}
+
+// Prefix sets the prefix of the Redis stream keys; the memory backend ignores it.
+// Default is "sse:".
+func Prefix(prefix string) BackendOption {
+	return func(c *backendConfig) {
+		c.prefix = prefix
+	}
+}
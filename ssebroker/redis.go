@@ -0,0 +1,127 @@
+package ssebroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const _listenBlock = 5 * time.Second
+
+// Redis is a Backend storing each topic in a Redis stream. Every instance
+// reads the streams of the topics it serves, so events fan out to all of them.
+type Redis struct {
+	r   *redis.Redis
+	cfg backendConfig
+}
+
+var _ Backend = (*Redis)(nil)
+
+// NewRedis creates a Redis backend.
+func NewRedis(r *redis.Redis, opts ...BackendOption) *Redis {
+	return &Redis{r: r, cfg: newBackendConfig(opts)}
+}
+
+// Append implements Backend.
+func (s *Redis) Append(ctx context.Context, topic string, e Event) (string, error) {
+	id, err := s.r.Client().XAdd(ctx, &goredis.XAddArgs{
+		Stream: s.cfg.prefix + topic,
+		MaxLen: int64(s.cfg.maxLen),
+		Approx: true,
+		Values: []any{"type", e.Type, "data", e.Data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("ssebroker - Redis - Append - Client.XAdd: %w", err)
+	}
+
+	return id, nil
+}
+
+// Since implements Backend.
+func (s *Redis) Since(ctx context.Context, topic, lastID string) ([]Event, error) {
+	if lastID == "" {
+		return nil, nil
+	}
+
+	msgs, err := s.r.Client().XRange(ctx, s.cfg.prefix+topic, lastID, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("ssebroker - Redis - Since - Client.XRange: %w", err)
+	}
+
+	events := make([]Event, 0, len(msgs))
+
+	for _, msg := range msgs {
+		// The range is inclusive.
+		if msg.ID == lastID {
+			continue
+		}
+
+		events = append(events, toEvent(msg))
+	}
+
+	return events, nil
+}
+
+// Last implements Backend.
+func (s *Redis) Last(ctx context.Context, topic string) (string, error) {
+	msgs, err := s.r.Client().XRevRangeN(ctx, s.cfg.prefix+topic, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("ssebroker - Redis - Last - Client.XRevRangeN: %w", err)
+	}
+
+	if len(msgs) == 0 {
+		return "", nil
+	}
+
+	return msgs[0].ID, nil
+}
+
+// Listen implements Backend.
+func (s *Redis) Listen(ctx context.Context, topic, lastID string, fn func(Event)) error {
+	stream := s.cfg.prefix + topic
+
+	if lastID == "" {
+		lastID = "0-0"
+	}
+
+	for {
+		res, err := s.r.Client().XRead(ctx, &goredis.XReadArgs{
+			Streams: []string{stream, lastID},
+			Block:   _listenBlock,
+		}).Result()
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, goredis.Nil):
+			continue
+		case err != nil:
+			return fmt.Errorf("ssebroker - Redis - Listen - Client.XRead: %w", err)
+		}
+
+		for _, str := range res {
+			for _, msg := range str.Messages {
+				fn(toEvent(msg))
+				lastID = msg.ID
+			}
+		}
+	}
+}
+
+func toEvent(msg goredis.XMessage) Event {
+	e := Event{ID: msg.ID}
+
+	if v, ok := msg.Values["type"].(string); ok {
+		e.Type = v
+	}
+
+	if v, ok := msg.Values["data"].(string); ok {
+		e.Data = []byte(v)
+	}
+
+	return e
+}
@@ -0,0 +1,399 @@
+// Package ssebroker streams server-sent events to Fiber clients subscribed to
+// topics. Events are appended to a backend, an in-memory ring for a single
+// instance or a Redis stream for horizontally scaled services, which fans them
+// out to every instance and lets reconnecting clients replay what they missed
+// through the Last-Event-ID header.
+package ssebroker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrClosed is returned when publishing through a broker that has been shut down.
+var ErrClosed = errors.New("ssebroker - broker closed")
+
+// Event is a server-sent event.
+type Event struct {
+	// ID is assigned by the backend on publish and sent as the SSE event id.
+	ID string
+	// Type is the SSE event name; empty means the default "message" event.
+	Type string
+	// Data is the event payload, split into one data line per line.
+	Data []byte
+}
+
+// Backend stores events per topic and delivers them to every broker instance.
+type Backend interface {
+	// Append stores e on topic and returns the ID assigned to it.
+	Append(ctx context.Context, topic string, e Event) (string, error)
+	// Since returns the stored events of topic published after lastID, oldest first.
+	Since(ctx context.Context, topic, lastID string) ([]Event, error)
+	// Last returns the ID of the newest stored event of topic, or "" when there is none.
+	Last(ctx context.Context, topic string) (string, error)
+	// Listen calls fn for every event appended to topic by any instance after
+	// lastID, or from the start when lastID is empty, until ctx is done.
+	Listen(ctx context.Context, topic, lastID string, fn func(Event)) error
+}
+
+// Broker tracks SSE subscribers per topic and streams events to them.
+type Broker struct {
+	backend Backend
+	cfg     config
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+type topic struct {
+	subs   map[*subscriber]struct{}
+	cancel context.CancelFunc
+	// ready is closed once the listener knows where to start from, so that
+	// events published after a client subscribed are never missed.
+	ready chan struct{}
+}
+
+type subscriber struct {
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// New creates a Broker on top of backend.
+//
+// Default configuration:
+//   - Heartbeat interval: 15s
+//   - Buffer size: 64 events per subscriber
+//   - Retry: not sent, clients use their default reconnection delay
+//
+// Example:
+//
+//	broker := ssebroker.New(ssebroker.NewRedis(r, ssebroker.MaxLen(10000)))
+//	server.App.Get("/events/:user", broker.Handler(func(c *fiber.Ctx) string {
+//	    return "user:" + c.Params("user")
+//	}))
+//	_, err := broker.Publish(ctx, "user:42", ssebroker.Event{Type: "notification", Data: payload})
+func New(backend Backend, opts ...Option) *Broker {
+	return &Broker{
+		backend: backend,
+		cfg:     newConfig(opts),
+		topics:  make(map[string]*topic),
+	}
+}
+
+// Publish appends e to topic and returns its ID. The event reaches subscribers
+// of every instance sharing the backend.
+func (b *Broker) Publish(ctx context.Context, topic string, e Event) (string, error) {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+
+	if closed {
+		return "", ErrClosed
+	}
+
+	id, err := b.backend.Append(ctx, topic, e)
+	if err != nil {
+		return "", fmt.Errorf("ssebroker - Broker - Publish - backend.Append: %w", err)
+	}
+
+	return id, nil
+}
+
+// Subscribers returns the number of clients of this instance streaming topic.
+func (b *Broker) Subscribers(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if t, ok := b.topics[topic]; ok {
+		return len(t.subs)
+	}
+
+	return 0
+}
+
+// Handler returns a Fiber handler streaming the topic returned by topicOf to the
+// client. Events missed since the Last-Event-ID header, or lastEventId query
+// parameter for clients that cannot set headers, are replayed first. Clients too
+// slow to keep up are disconnected and catch up on reconnect.
+func (b *Broker) Handler(topicOf func(c *fiber.Ctx) string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		name := topicOf(ctx)
+		if name == "" {
+			return fiber.ErrNotFound
+		}
+
+		lastID := ctx.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = ctx.Query("lastEventId")
+		}
+
+		sub, err := b.subscribe(name)
+		if err != nil {
+			return fiber.ErrServiceUnavailable
+		}
+
+		// Replay before streaming so that the response can still fail cleanly.
+		replay, err := b.backend.Since(ctx.UserContext(), name, lastID)
+		if err != nil {
+			b.unsubscribe(name, sub)
+
+			return fmt.Errorf("ssebroker - Broker - Handler - backend.Since: %w", err)
+		}
+
+		ctx.Set(fiber.HeaderContentType, "text/event-stream")
+		ctx.Set(fiber.HeaderCacheControl, "no-cache")
+		ctx.Set(fiber.HeaderConnection, "keep-alive")
+		ctx.Set("X-Accel-Buffering", "no")
+
+		ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer b.unsubscribe(name, sub)
+
+			b.stream(w, sub, replay, lastID)
+		})
+
+		return nil
+	}
+}
+
+// Shutdown disconnects every subscriber and waits for the topic listeners to
+// stop until ctx is done.
+func (b *Broker) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+
+	for _, t := range b.topics {
+		t.cancel()
+
+		for sub := range t.subs {
+			sub.close()
+		}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("ssebroker - Broker - Shutdown: %w", ctx.Err())
+	}
+}
+
+func (b *Broker) stream(w *bufio.Writer, sub *subscriber, replay []Event, lastID string) {
+	if b.cfg.retry > 0 {
+		fmt.Fprintf(w, "retry: %d\n\n", b.cfg.retry.Milliseconds())
+	}
+
+	for _, e := range replay {
+		writeEvent(w, e)
+		lastID = e.ID
+	}
+
+	// An empty comment flushes the headers right away.
+	if _, err := w.WriteString(":\n\n"); err != nil || w.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(b.cfg.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-sub.done:
+			return
+		case e := <-sub.events:
+			// Events published while replaying arrive twice.
+			if lastID != "" && compareIDs(e.ID, lastID) <= 0 {
+				continue
+			}
+
+			writeEvent(w, e)
+			lastID = e.ID
+		case <-heartbeat.C:
+			_, _ = w.WriteString(": ping\n\n")
+		}
+
+		// A failed flush means the client went away.
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (b *Broker) subscribe(name string) (*subscriber, error) {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+
+		return nil, ErrClosed
+	}
+
+	sub := &subscriber{
+		events: make(chan Event, b.cfg.bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	t, ok := b.topics[name]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		t = &topic{subs: make(map[*subscriber]struct{}), cancel: cancel, ready: make(chan struct{})}
+		b.topics[name] = t
+
+		b.wg.Add(1)
+
+		go b.listen(ctx, name, t)
+	}
+
+	t.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	<-t.ready
+
+	return sub, nil
+}
+
+func (b *Broker) unsubscribe(name string, sub *subscriber) {
+	sub.close()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[name]
+	if !ok {
+		return
+	}
+
+	delete(t.subs, sub)
+
+	if len(t.subs) == 0 {
+		t.cancel()
+		delete(b.topics, name)
+	}
+}
+
+// listen fans out the events of a topic to the local subscribers while the
+// topic has any. After a backend failure it resumes from the last event seen.
+func (b *Broker) listen(ctx context.Context, name string, t *topic) {
+	defer b.wg.Done()
+
+	ready := sync.OnceFunc(func() { close(t.ready) })
+	defer ready()
+
+	var (
+		lastID   string
+		resolved bool
+	)
+
+	for {
+		var err error
+
+		if !resolved {
+			lastID, err = b.backend.Last(ctx, name)
+			resolved = err == nil
+
+			ready()
+		}
+
+		if err == nil {
+			err = b.backend.Listen(ctx, name, lastID, func(e Event) {
+				lastID = e.ID
+
+				b.mu.Lock()
+				defer b.mu.Unlock()
+
+				for sub := range t.subs {
+					select {
+					case sub.events <- e:
+					default:
+						sub.close()
+					}
+				}
+			})
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if b.cfg.logger != nil {
+			b.cfg.logger.Error(err, "ssebroker - Broker - listen")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, e Event) {
+	if e.ID != "" {
+		w.WriteString("id: " + e.ID + "\n")
+	}
+
+	if e.Type != "" {
+		w.WriteString("event: " + e.Type + "\n")
+	}
+
+	for _, line := range strings.Split(string(e.Data), "\n") {
+		w.WriteString("data: " + line + "\n")
+	}
+
+	w.WriteString("\n")
+}
+
+// compareIDs orders event IDs of the "<milliseconds>-<sequence>" form used by
+// the backends.
+func compareIDs(a, b string) int {
+	am, as := splitID(a)
+	bm, bs := splitID(b)
+
+	switch {
+	case am != bm:
+		if am < bm {
+			return -1
+		}
+
+		return 1
+	case as != bs:
+		if as < bs {
+			return -1
+		}
+
+		return 1
+	default:
+		return 0
+	}
+}
+
+func splitID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+
+	return ms, seq
+}
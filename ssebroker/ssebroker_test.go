@@ -0,0 +1,264 @@
+package ssebroker_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/ssebroker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve starts broker on a random port and returns the base URL of its
+// "/events/:topic" endpoint.
+func serve(t *testing.T, broker *ssebroker.Broker) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/events/:topic", broker.Handler(func(c *fiber.Ctx) string { return c.Params("topic") }))
+
+	go func() { _ = app.Listener(ln) }()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_ = broker.Shutdown(ctx)
+		_ = app.ShutdownWithTimeout(time.Second)
+	})
+
+	return "http://" + ln.Addr().String() + "/events/"
+}
+
+type stream struct {
+	resp *http.Response
+	r    *bufio.Reader
+}
+
+func connect(t *testing.T, url, lastID string) *stream {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return &stream{resp: resp, r: bufio.NewReader(resp.Body)}
+}
+
+// next returns the next event block, skipping comments.
+func (s *stream) next(t *testing.T) string {
+	t.Helper()
+
+	lines := make(chan string, 1)
+
+	go func() {
+		var block []string
+
+		for {
+			line, err := s.r.ReadString('\n')
+			if err != nil {
+				lines <- "EOF"
+				return
+			}
+
+			line = strings.TrimSuffix(line, "\n")
+
+			switch {
+			case line == "" && len(block) > 0:
+				lines <- strings.Join(block, "\n")
+				return
+			case line == "", strings.HasPrefix(line, ":"):
+			default:
+				block = append(block, line)
+			}
+		}
+	}()
+
+	select {
+	case block := <-lines:
+		return block
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return ""
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	require.Eventually(t, cond, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestBroker_Stream(t *testing.T) {
+	broker := ssebroker.New(ssebroker.NewMemory())
+	url := serve(t, broker)
+
+	s := connect(t, url+"news", "")
+	waitFor(t, func() bool { return broker.Subscribers("news") == 1 })
+
+	ctx := context.Background()
+
+	id, err := broker.Publish(ctx, "news", ssebroker.Event{Type: "headline", Data: []byte("line one\nline two")})
+	require.NoError(t, err)
+
+	_, err = broker.Publish(ctx, "sports", ssebroker.Event{Data: []byte("other topic")})
+	require.NoError(t, err)
+
+	_, err = broker.Publish(ctx, "news", ssebroker.Event{Data: []byte("second")})
+	require.NoError(t, err)
+
+	assert.Equal(t, "id: "+id+"\nevent: headline\ndata: line one\ndata: line two", s.next(t))
+	assert.Contains(t, s.next(t), "data: second")
+}
+
+func TestBroker_Replay(t *testing.T) {
+	backend := ssebroker.NewMemory()
+	broker := ssebroker.New(backend)
+	url := serve(t, broker)
+
+	ctx := context.Background()
+
+	var ids []string
+
+	for _, data := range []string{"one", "two", "three"} {
+		id, err := broker.Publish(ctx, "news", ssebroker.Event{Data: []byte(data)})
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	s := connect(t, url+"news", ids[0])
+	assert.Equal(t, "id: "+ids[1]+"\ndata: two", s.next(t))
+	assert.Equal(t, "id: "+ids[2]+"\ndata: three", s.next(t))
+
+	waitFor(t, func() bool { return broker.Subscribers("news") == 1 })
+
+	_, err := broker.Publish(ctx, "news", ssebroker.Event{Data: []byte("four")})
+	require.NoError(t, err)
+	assert.Contains(t, s.next(t), "data: four")
+}
+
+func TestBroker_FanOut(t *testing.T) {
+	backend := ssebroker.NewMemory()
+	first := ssebroker.New(backend)
+	second := ssebroker.New(backend)
+
+	a := connect(t, serve(t, first)+"news", "")
+	b := connect(t, serve(t, second)+"news", "")
+
+	waitFor(t, func() bool { return first.Subscribers("news") == 1 && second.Subscribers("news") == 1 })
+
+	_, err := first.Publish(context.Background(), "news", ssebroker.Event{Data: []byte("hello")})
+	require.NoError(t, err)
+
+	assert.Contains(t, a.next(t), "data: hello")
+	assert.Contains(t, b.next(t), "data: hello")
+}
+
+func TestBroker_Disconnect(t *testing.T) {
+	broker := ssebroker.New(ssebroker.NewMemory(), ssebroker.HeartbeatInterval(10*time.Millisecond))
+	url := serve(t, broker)
+
+	s := connect(t, url+"news", "")
+	waitFor(t, func() bool { return broker.Subscribers("news") == 1 })
+
+	s.resp.Body.Close()
+	waitFor(t, func() bool { return broker.Subscribers("news") == 0 })
+}
+
+func TestBroker_Shutdown(t *testing.T) {
+	broker := ssebroker.New(ssebroker.NewMemory())
+	url := serve(t, broker)
+
+	s := connect(t, url+"news", "")
+	waitFor(t, func() bool { return broker.Subscribers("news") == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, broker.Shutdown(ctx))
+	assert.Equal(t, "EOF", s.next(t))
+
+	_, err := broker.Publish(context.Background(), "news", ssebroker.Event{})
+	assert.ErrorIs(t, err, ssebroker.ErrClosed)
+}
+
+func TestMemory(t *testing.T) {
+	m := ssebroker.NewMemory(ssebroker.MaxLen(2))
+	ctx := context.Background()
+
+	var ids []string
+
+	for _, data := range []string{"one", "two", "three"} {
+		id, err := m.Append(ctx, "t", ssebroker.Event{Data: []byte(data)})
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	tests := []struct {
+		name   string
+		lastID string
+		want   []string
+	}{
+		{name: "no last id", lastID: "", want: nil},
+		{name: "trimmed", lastID: "0-0", want: []string{"two", "three"}},
+		{name: "middle", lastID: ids[1], want: []string{"three"}},
+		{name: "latest", lastID: ids[2], want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := m.Since(ctx, "t", tt.lastID)
+			require.NoError(t, err)
+
+			var got []string
+			for _, e := range events {
+				got = append(got, string(e.Data))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedis_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	require.NoError(t, err)
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	backend := ssebroker.NewRedis(r)
+
+	if _, err := backend.Append(ctx, "t", ssebroker.Event{Data: []byte("x")}); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	_, err = backend.Since(ctx, "t", "0-0")
+	assert.Error(t, err)
+	_, err = backend.Last(ctx, "t")
+	assert.Error(t, err)
+	assert.Error(t, backend.Listen(ctx, "t", "", func(ssebroker.Event) {}))
+}
@@ -0,0 +1,59 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+// Example demonstrates uploading and sharing a file via a MinIO server
+func Example() {
+	s, err := storage.New("localhost:9000", "minioadmin", "minioadmin", "uploads",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+	)
+	if err != nil {
+		fmt.Printf("Failed to create storage: %v\n", err)
+		return
+	}
+
+	ctx := context.Background()
+
+	body := strings.NewReader("hello world")
+
+	_, err = s.Put(ctx, "docs/hello.txt", body, body.Size(), "text/plain")
+	if err != nil {
+		fmt.Printf("Storage not available for example: %v\n", err)
+		return
+	}
+
+	link, err := s.PresignGet(ctx, "docs/hello.txt", time.Hour)
+	if err != nil {
+		fmt.Printf("Failed to presign URL: %v\n", err)
+		return
+	}
+
+	_ = link // Share the link with the client
+}
+
+// ExampleNew demonstrates configuring clients for different providers
+func ExampleNew() {
+	// AWS S3
+	_, _ = storage.New("s3.amazonaws.com", "AKIA...", "secret", "my-bucket",
+		storage.Region("eu-west-1"),
+	)
+
+	// Google Cloud Storage using HMAC interoperability keys
+	_, _ = storage.New("storage.googleapis.com", "GOOG...", "secret", "my-bucket")
+
+	// Large uploads: 64 MiB parts, 8 in parallel, 5 attempts
+	_, _ = storage.New("localhost:9000", "minioadmin", "minioadmin", "backups",
+		storage.Secure(false),
+		storage.PartSize(64<<20),
+		storage.Concurrency(8),
+		storage.Retries(5, time.Second),
+	)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import "time"
+
+// Option is a function that configures an S3 storage.
+// Options are applied in the order they are passed to New.
+type Option func(*S3)
+
+// Secure enables or disables TLS for the endpoint connection.
+// Default is true.
+func Secure(secure bool) Option {
+	return func(s *S3) {
+		s.secure = secure
+	}
+}
+
+// Region sets the bucket region. When empty, the region is discovered automatically.
+func Region(region string) Option {
+	return func(s *S3) {
+		s.region = region
+	}
+}
+
+// PartSize sets the size of each part in multipart uploads.
+// Default is 16 MiB.
+func PartSize(size uint64) Option {
+	return func(s *S3) {
+		s.partSize = size
+	}
+}
+
+// Concurrency sets the number of parts uploaded in parallel during multipart uploads.
+// Default is 4.
+func Concurrency(n uint) Option {
+	return func(s *S3) {
+		s.concurrency = n
+	}
+}
+
+// Retries sets the number of attempts for each operation and the wait time between them.
+// Only transient failures (network errors, 5xx responses, throttling) are retried.
+// Default is 3 attempts with 200ms wait time.
+//
+// Example:
+//
+//	storage.New(endpoint, key, secret, bucket, storage.Retries(5, time.Second))
+func Retries(attempts int, waitTime time.Duration) Option {
+	return func(s *S3) {
+		s.attempts = attempts
+		s.waitTime = waitTime
+	}
+}
+
+// WithMetrics sets the hook receiving a measurement for every operation.
+func WithMetrics(m Metrics) Option {
+	return func(s *S3) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
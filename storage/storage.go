@@ -0,0 +1,339 @@
+// Package storage provides S3-compatible object storage with a common interface
+// for Put/Get/Delete/List operations, presigned URLs and concurrent multipart uploads.
+// It works with AWS S3, MinIO and Google Cloud Storage (through its S3 interoperability API)
+// using minio-go.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_defaultRegion      = ""
+	_defaultPartSize    = 16 << 20 // 16 MiB
+	_defaultConcurrency = 4
+	_defaultAttempts    = 3
+	_defaultWaitTime    = 200 * time.Millisecond
+)
+
+// ErrNotFound is returned when the requested object does not exist.
+var ErrNotFound = errors.New("storage - object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Storage defines the common object storage operations.
+type Storage interface {
+	// Put uploads an object. Size may be -1 when unknown, in which case the upload is multipart.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error)
+	// Get returns a reader for the object content. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns all objects whose keys start with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// PresignGet returns a URL allowing anonymous download of the object until expiry.
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// PresignPut returns a URL allowing anonymous upload of the object until expiry.
+	PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// Metrics receives a measurement for every storage operation.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveOperation(operation string, duration time.Duration, err error)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveOperation(string, time.Duration, error) {}
+
+// S3 implements Storage on top of any S3-compatible endpoint.
+type S3 struct {
+	client *minio.Client
+	bucket string
+
+	secure      bool
+	region      string
+	partSize    uint64
+	concurrency uint
+	attempts    int
+	waitTime    time.Duration
+	metrics     Metrics
+}
+
+var _ Storage = (*S3)(nil)
+
+// New creates a new S3-compatible storage client for the given bucket.
+// The endpoint is a host with optional port, without scheme (e.g. "s3.amazonaws.com",
+// "localhost:9000" or "storage.googleapis.com").
+// Default configuration: TLS enabled, 16 MiB parts, 4 concurrent part uploads,
+// 3 attempts with 200ms between retries.
+//
+// Example:
+//
+//	s, err := storage.New("localhost:9000", "minioadmin", "minioadmin", "uploads",
+//	    storage.Secure(false),
+//	    storage.Concurrency(8),
+//	)
+func New(endpoint, accessKey, secretKey, bucket string, opts ...Option) (*S3, error) {
+	s := &S3{
+		bucket:      bucket,
+		secure:      true,
+		region:      _defaultRegion,
+		partSize:    _defaultPartSize,
+		concurrency: _defaultConcurrency,
+		attempts:    _defaultAttempts,
+		waitTime:    _defaultWaitTime,
+		metrics:     noopMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: s.secure,
+		Region: s.region,
+		// Retries are handled by S3.do so the configured policy is the only one applied.
+		MaxRetries: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage - New - minio.New: %w", err)
+	}
+
+	s.client = client
+
+	return s, nil
+}
+
+// Client returns the underlying minio client for operations not covered by Storage.
+func (s *S3) Client() *minio.Client {
+	return s.client
+}
+
+// Put uploads an object. Uploads larger than the configured part size, or of unknown
+// size, are split into parts that are uploaded concurrently.
+// The upload is retried only when r implements io.Seeker, so it can be rewound.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	var info ObjectInfo
+
+	seeker, rewindable := r.(io.Seeker)
+
+	err := s.do(ctx, "put", func() error {
+		if rewindable {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+
+		res, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+			ContentType:           contentType,
+			PartSize:              s.partSize,
+			NumThreads:            s.concurrency,
+			ConcurrentStreamParts: s.concurrency > 1,
+		})
+		if err != nil {
+			if !rewindable {
+				return retry.Permanent(err)
+			}
+
+			return err
+		}
+
+		info = ObjectInfo{
+			Key:          res.Key,
+			Size:         res.Size,
+			ContentType:  contentType,
+			ETag:         res.ETag,
+			LastModified: res.LastModified,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("storage - S3 - Put - s.client.PutObject: %w", err)
+	}
+
+	return info, nil
+}
+
+// Get returns a reader for the object content along with its metadata.
+// Returns ErrNotFound if the object does not exist.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	var (
+		obj  *minio.Object
+		stat minio.ObjectInfo
+	)
+
+	err := s.do(ctx, "get", func() error {
+		var err error
+
+		obj, err = s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+		if err != nil {
+			return err
+		}
+
+		stat, err = obj.Stat()
+		if err != nil {
+			_ = obj.Close()
+
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("storage - S3 - Get - s.client.GetObject: %w", err)
+	}
+
+	return obj, toObjectInfo(stat), nil
+}
+
+// Delete removes an object from the bucket.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	err := s.do(ctx, "delete", func() error {
+		return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+	})
+	if err != nil {
+		return fmt.Errorf("storage - S3 - Delete - s.client.RemoveObject: %w", err)
+	}
+
+	return nil
+}
+
+// List returns metadata of all objects with the given key prefix, recursively.
+func (s *S3) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := s.do(ctx, "list", func() error {
+		objects = objects[:0]
+
+		// Cancelling the listing context stops minio's producer goroutine on early return.
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		for obj := range s.client.ListObjects(listCtx, s.bucket, minio.ListObjectsOptions{
+			Prefix:    prefix,
+			Recursive: true,
+		}) {
+			if obj.Err != nil {
+				return obj.Err
+			}
+
+			objects = append(objects, toObjectInfo(obj))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage - S3 - List - s.client.ListObjects: %w", err)
+	}
+
+	return objects, nil
+}
+
+// PresignGet returns a presigned download URL valid for the given duration.
+func (s *S3) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	start := time.Now()
+
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, nil)
+	s.metrics.ObserveOperation("presign_get", time.Since(start), err)
+
+	if err != nil {
+		return "", fmt.Errorf("storage - S3 - PresignGet - s.client.PresignedGetObject: %w", err)
+	}
+
+	return u.String(), nil
+}
+
+// PresignPut returns a presigned upload URL valid for the given duration.
+func (s *S3) PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	start := time.Now()
+
+	u, err := s.client.PresignedPutObject(ctx, s.bucket, key, expiry)
+	s.metrics.ObserveOperation("presign_put", time.Since(start), err)
+
+	if err != nil {
+		return "", fmt.Errorf("storage - S3 - PresignPut - s.client.PresignedPutObject: %w", err)
+	}
+
+	return u.String(), nil
+}
+
+// do runs fn with the configured retry policy and reports the outcome to metrics.
+func (s *S3) do(ctx context.Context, operation string, fn func() error) error {
+	start := time.Now()
+
+	err := retry.Do(ctx, func(context.Context) error {
+		return mapError(fn())
+	},
+		retry.Attempts(s.attempts),
+		retry.ConstantBackoff(s.waitTime),
+		retry.RetryIf(retryable),
+		retry.OnRetry(func(attempt int, _ error, _ time.Duration) {
+			log.Printf("Storage %s failed, attempts left: %d", operation, s.attempts-attempt)
+		}),
+	)
+
+	s.metrics.ObserveOperation(operation, time.Since(start), err)
+
+	return err
+}
+
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	resp := minio.ToErrorResponse(err)
+
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.Code == "SlowDown"
+}
+
+func toObjectInfo(o minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          o.Key,
+		Size:         o.Size,
+		ContentType:  o.ContentType,
+		ETag:         o.ETag,
+		LastModified: o.LastModified,
+	}
+}
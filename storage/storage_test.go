@@ -0,0 +1,251 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/storage"
+)
+
+// unreachableEndpoint refuses connections immediately.
+const unreachableEndpoint = "127.0.0.1:1"
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	operations []string
+	errs       []error
+}
+
+func (m *recordingMetrics) ObserveOperation(operation string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.operations = append(m.operations, operation)
+	m.errs = append(m.errs, err)
+}
+
+type countingSeeker struct {
+	*bytes.Reader
+	seeks int
+}
+
+func (c *countingSeeker) Seek(offset int64, whence int) (int64, error) {
+	c.seeks++
+
+	return c.Reader.Seek(offset, whence)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		opts     []storage.Option
+		wantErr  bool
+	}{
+		{
+			name:     "default configuration",
+			endpoint: "s3.amazonaws.com",
+		},
+		{
+			name:     "minio with options",
+			endpoint: "localhost:9000",
+			opts: []storage.Option{
+				storage.Secure(false),
+				storage.Region("us-east-1"),
+				storage.PartSize(5 << 20),
+				storage.Concurrency(8),
+				storage.Retries(5, time.Second),
+				storage.WithMetrics(&recordingMetrics{}),
+			},
+		},
+		{
+			name:     "endpoint with scheme is rejected",
+			endpoint: "http://localhost:9000",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := storage.New(tt.endpoint, "access", "secret", "bucket", tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if s.Client() == nil {
+				t.Error("expected underlying client to be set")
+			}
+		})
+	}
+}
+
+func TestS3_PresignGet(t *testing.T) {
+	s, err := storage.New("localhost:9000", "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	raw, err := s.PresignGet(context.Background(), "reports/2024.csv", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid presigned URL %q: %v", raw, err)
+	}
+
+	if u.Path != "/bucket/reports/2024.csv" {
+		t.Errorf("expected path /bucket/reports/2024.csv, got %q", u.Path)
+	}
+
+	if u.Query().Get("X-Amz-Signature") == "" {
+		t.Error("expected presigned URL to contain a signature")
+	}
+
+	if u.Query().Get("X-Amz-Expires") != "900" {
+		t.Errorf("expected expiry 900, got %q", u.Query().Get("X-Amz-Expires"))
+	}
+}
+
+func TestS3_PresignPut(t *testing.T) {
+	s, err := storage.New("localhost:9000", "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	raw, err := s.PresignPut(context.Background(), "avatar.png", time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(raw, "/bucket/avatar.png") {
+		t.Errorf("expected URL to reference the object, got %q", raw)
+	}
+}
+
+func TestS3_Put_RetriesRewindableReader(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	s, err := storage.New(unreachableEndpoint, "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+		storage.Retries(3, time.Millisecond),
+		storage.WithMetrics(metrics),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	body := &countingSeeker{Reader: bytes.NewReader([]byte("hello"))}
+
+	_, err = s.Put(context.Background(), "greeting.txt", body, int64(body.Len()), "text/plain")
+	if err == nil {
+		t.Skip("unexpected storage server listening on " + unreachableEndpoint)
+	}
+
+	if body.seeks < 3 {
+		t.Errorf("expected reader to be rewound for each of 3 attempts, got %d seeks", body.seeks)
+	}
+
+	if len(metrics.operations) != 1 || metrics.operations[0] != "put" {
+		t.Errorf("expected a single put observation, got %v", metrics.operations)
+	}
+
+	if metrics.errs[0] == nil {
+		t.Error("expected observed error to be reported")
+	}
+}
+
+func TestS3_Put_NonRewindableReaderNotRetried(t *testing.T) {
+	s, err := storage.New(unreachableEndpoint, "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+		storage.Retries(3, time.Second),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	start := time.Now()
+
+	_, err = s.Put(context.Background(), "stream.bin", io.LimitReader(strings.NewReader("data"), 4), 4, "")
+	if err == nil {
+		t.Skip("unexpected storage server listening on " + unreachableEndpoint)
+	}
+
+	if time.Since(start) >= time.Second {
+		t.Error("expected non-rewindable upload to fail without waiting for retries")
+	}
+}
+
+func TestS3_Operations_NoConnection(t *testing.T) {
+	s, err := storage.New(unreachableEndpoint, "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+		storage.Retries(1, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if _, _, err := s.Get(ctx, "missing"); err == nil {
+		t.Skip("unexpected storage server listening on " + unreachableEndpoint)
+	} else if errors.Is(err, storage.ErrNotFound) {
+		t.Error("expected connection error, got ErrNotFound")
+	}
+
+	if err := s.Delete(ctx, "missing"); err == nil {
+		t.Error("expected Delete to fail without connection")
+	}
+
+	if _, err := s.List(ctx, "prefix/"); err == nil {
+		t.Error("expected List to fail without connection")
+	}
+}
+
+func TestS3_Retries_StopOnContextCancel(t *testing.T) {
+	s, err := storage.New(unreachableEndpoint, "access", "secret", "bucket",
+		storage.Secure(false),
+		storage.Region("us-east-1"),
+		storage.Retries(10, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = s.Delete(ctx, "key")
+	if err == nil {
+		t.Skip("unexpected storage server listening on " + unreachableEndpoint)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context deadline error, got: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package tenant_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/tenant"
+)
+
+func ExampleKey() {
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	key, _ := tenant.Key(ctx, "cart:42")
+	fmt.Println(key)
+
+	// Output: tenant:acme:cart:42
+}
@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Logger returns l tagging messages with the tenant stored in ctx, e.g.
+// "[tenant acme] order created". l is returned as is when ctx carries no tenant.
+//
+// Example:
+//
+//	tenant.Logger(ctx, l).Info("order %s created", order.ID)
+func Logger(ctx context.Context, l logger.LoggerI) logger.LoggerI {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return l
+	}
+
+	return &tenantLogger{LoggerI: l, prefix: "[tenant " + id + "] "}
+}
+
+type tenantLogger struct {
+	logger.LoggerI
+	prefix string
+}
+
+func (l *tenantLogger) Debug(message interface{}, args ...interface{}) {
+	l.LoggerI.Debug(l.tag(message), args...)
+}
+
+func (l *tenantLogger) Info(message string, args ...interface{}) {
+	l.LoggerI.Info(l.prefix+message, args...)
+}
+
+func (l *tenantLogger) Warn(message string, args ...interface{}) {
+	l.LoggerI.Warn(l.prefix+message, args...)
+}
+
+func (l *tenantLogger) Error(message interface{}, args ...interface{}) {
+	l.LoggerI.Error(l.tag(message), args...)
+}
+
+func (l *tenantLogger) Fatal(message interface{}, args ...interface{}) {
+	l.LoggerI.Fatal(l.tag(message), args...)
+}
+
+// tag prefixes string and error messages, keeping errors unwrappable.
+func (l *tenantLogger) tag(message interface{}) interface{} {
+	switch m := message.(type) {
+	case string:
+		return l.prefix + m
+	case error:
+		return &taggedError{prefix: l.prefix, err: m}
+	default:
+		return message
+	}
+}
+
+type taggedError struct {
+	prefix string
+	err    error
+}
+
+func (e *taggedError) Error() string { return e.prefix + e.err.Error() }
+func (e *taggedError) Unwrap() error { return e.err }
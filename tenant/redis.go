@@ -0,0 +1,23 @@
+package tenant
+
+import "context"
+
+// KeyPrefix is the prefix of tenant scoped keys, followed by the tenant ID and a colon.
+const KeyPrefix = "tenant:"
+
+// Key returns key scoped to the tenant stored in ctx, e.g. "tenant:acme:cart:42",
+// so tenants sharing a Redis instance or a cache never read each other's keys.
+// It returns ErrMissing when ctx carries no tenant.
+//
+// Example:
+//
+//	key, err := tenant.Key(ctx, "cart:"+userID)
+//	err = r.Set(ctx, key, cart, time.Hour)
+func Key(ctx context.Context, key string) (string, error) {
+	id, err := Require(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return KeyPrefix + id + ":" + key, nil
+}
@@ -0,0 +1,71 @@
+// Package tenant makes tenant isolation consistent across the stack. The tenant of
+// a request is resolved by the Fiber middleware (httpserver/middleware.Tenant) or
+// the gRPC interceptors (grpcserver.UnaryTenant) from a header, metadata or JWT
+// claims, and stored in the request context. Integrations then read it from the
+// context: Postgres sessions get the app.tenant_id setting for row-level security,
+// Redis keys get a tenant prefix and log messages a tenant tag.
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+)
+
+const (
+	// Header is the HTTP header carrying the tenant ID by default.
+	Header = "X-Tenant-ID"
+	// MetadataKey is the gRPC metadata key carrying the tenant ID by default.
+	MetadataKey = "x-tenant-id"
+)
+
+// ErrMissing is returned when the context carries no tenant.
+var ErrMissing = errors.New("tenant - missing tenant")
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant stored in ctx and whether there is one.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+
+	return id, ok && id != ""
+}
+
+// Require returns the tenant stored in ctx, or ErrMissing.
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrMissing
+	}
+
+	return id, nil
+}
+
+// FromJWT returns a resolver reading the tenant from the JWT claims stored in the
+// context by the JWT middleware or interceptors, for use with the tenant middleware.
+// field extracts the tenant from the claims; contexts without claims of type T
+// resolve to no tenant.
+//
+// Example:
+//
+//	resolve := tenant.FromJWT(func(c *UserClaims) string { return c.TenantID })
+//
+//	app.Use(middleware.JWT(j, newClaims), middleware.Tenant(func(c *fiber.Ctx) string {
+//	    return resolve(c.UserContext())
+//	}))
+func FromJWT[T jwt.Claims](field func(claims T) string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		claims, ok := jwt.FromContext[T](ctx)
+		if !ok {
+			return ""
+		}
+
+		return field(claims)
+	}
+}
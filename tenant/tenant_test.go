@@ -0,0 +1,109 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rdashevsky/go-pkgs/jwt"
+	"github.com/rdashevsky/go-pkgs/tenant"
+)
+
+func TestContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    string
+		wantErr error
+	}{
+		{name: "tenant", ctx: tenant.NewContext(context.Background(), "acme"), want: "acme"},
+		{name: "no tenant", ctx: context.Background(), wantErr: tenant.ErrMissing},
+		{name: "empty tenant", ctx: tenant.NewContext(context.Background(), ""), wantErr: tenant.ErrMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tenant.Require(tt.ctx)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Require() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+
+			key, err := tenant.Key(tt.ctx, "cart:42")
+			if tt.wantErr == nil && key != "tenant:"+tt.want+":cart:42" {
+				t.Errorf("Key() = %q, want %q", key, "tenant:"+tt.want+":cart:42")
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Key() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type tenantClaims struct {
+	jwt.RegisteredClaims
+	TenantID string `json:"tid"`
+}
+
+func TestFromJWT(t *testing.T) {
+	resolve := tenant.FromJWT(func(c *tenantClaims) string { return c.TenantID })
+
+	ctx := jwt.NewContext(context.Background(), &tenantClaims{TenantID: "acme"})
+	if got := resolve(ctx); got != "acme" {
+		t.Errorf("resolve() = %q, want %q", got, "acme")
+	}
+
+	ctx = jwt.NewContext(context.Background(), &jwt.RegisteredClaims{Subject: "user-1"})
+	if got := resolve(ctx); got != "" {
+		t.Errorf("resolve() of other claims = %q, want empty", got)
+	}
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) record(message interface{}, args ...interface{}) {
+	switch m := message.(type) {
+	case error:
+		l.messages = append(l.messages, fmt.Sprintf(m.Error(), args...))
+	default:
+		l.messages = append(l.messages, fmt.Sprintf(fmt.Sprint(m), args...))
+	}
+}
+
+func (l *recordingLogger) Debug(message interface{}, args ...interface{}) { l.record(message, args...) }
+func (l *recordingLogger) Info(message string, args ...interface{})       { l.record(message, args...) }
+func (l *recordingLogger) Warn(message string, args ...interface{})       { l.record(message, args...) }
+func (l *recordingLogger) Error(message interface{}, args ...interface{}) { l.record(message, args...) }
+func (l *recordingLogger) Fatal(message interface{}, args ...interface{}) { l.record(message, args...) }
+
+func TestLogger(t *testing.T) {
+	l := &recordingLogger{}
+	errBoom := errors.New("boom")
+
+	tenant.Logger(tenant.NewContext(context.Background(), "acme"), l).Info("order %s created", "42")
+	tenant.Logger(tenant.NewContext(context.Background(), "acme"), l).Error(errBoom)
+	tenant.Logger(context.Background(), l).Warn("no tenant")
+
+	want := []string{"[tenant acme] order 42 created", "[tenant acme] boom", "no tenant"}
+	if strings.Join(l.messages, "|") != strings.Join(want, "|") {
+		t.Errorf("messages = %q, want %q", l.messages, want)
+	}
+}
+
+func TestRLSPolicy(t *testing.T) {
+	ddl := tenant.RLSPolicy("billing.invoices", "tenant_id")
+
+	for _, want := range []string{
+		`ALTER TABLE "billing"."invoices" ENABLE ROW LEVEL SECURITY;`,
+		`ALTER TABLE "billing"."invoices" FORCE ROW LEVEL SECURITY;`,
+		`CREATE POLICY "invoices_tenant_isolation" ON "billing"."invoices" USING ("tenant_id" = current_setting('app.tenant_id', true))`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("RLSPolicy() does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FieldError describes a single failed rule.
+type FieldError struct {
+	// Field is the path of the field, e.g. "address.city" or "items[0].sku".
+	Field string `json:"field"`
+	// Rule is the failed validation tag, e.g. "required".
+	Rule string `json:"rule"`
+	// Param is the rule parameter, e.g. "3" for "min=3".
+	Param string `json:"param,omitempty"`
+	// Message is the human readable error message.
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a value fails validation.
+// It implements the gRPC status interface, so returning it from a gRPC handler
+// produces an INVALID_ARGUMENT status with BadRequest field violation details.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Message)
+	}
+
+	return "validator - validation failed: " + strings.Join(messages, "; ")
+}
+
+// ProblemDetails is an RFC 9457 problem details document.
+type ProblemDetails struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Problem returns the error as a 422 Unprocessable Entity problem details document.
+func (e *ValidationError) Problem() ProblemDetails {
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Detail: "The request contains invalid fields.",
+		Errors: e.Fields,
+	}
+}
+
+// GRPCStatus returns an INVALID_ARGUMENT status carrying a BadRequest detail
+// with one field violation per failed rule.
+func (e *ValidationError) GRPCStatus() *status.Status {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, "validation failed")
+
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
@@ -0,0 +1,28 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/validator"
+)
+
+func ExampleValidator_Struct() {
+	type CreateUser struct {
+		Email string `json:"email" validate:"required,email"`
+		Phone string `json:"phone" validate:"omitempty,phone"`
+	}
+
+	v, err := validator.New()
+	if err != nil {
+		panic(err)
+	}
+
+	err = v.Struct(context.Background(), CreateUser{Email: "john@example.com", Phone: "12345"})
+	if validationErr, ok := err.(*validator.ValidationError); ok {
+		for _, f := range validationErr.Fields {
+			fmt.Printf("%s: %s\n", f.Field, f.Message)
+		}
+	}
+	// Output: phone: phone must be a valid phone number in E.164 format
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON is the content type of problem details responses.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// HTTPError writes a 422 problem details response when err is a *ValidationError
+// and returns any other error unchanged, to be handled by the Fiber error handler.
+//
+// Example:
+//
+//	if err := v.Struct(c.UserContext(), &req); err != nil {
+//	    return validator.HTTPError(c, err)
+//	}
+func HTTPError(ctx *fiber.Ctx, err error) error {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusUnprocessableEntity).JSON(validationErr.Problem(), MIMEApplicationProblemJSON)
+}
+
+// ParseBody decodes the request body into out and validates it.
+// A malformed body is reported as a 400 *fiber.Error and an invalid one as *ValidationError,
+// so the result can be passed to HTTPError.
+//
+// Example:
+//
+//	var req CreateUser
+//	if err := validator.ParseBody(c, v, &req); err != nil {
+//	    return validator.HTTPError(c, err)
+//	}
+func ParseBody(ctx *fiber.Ctx, v *Validator, out interface{}) error {
+	if err := ctx.BodyParser(out); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return v.Struct(ctx.UserContext(), out)
+}
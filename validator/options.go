@@ -0,0 +1,38 @@
+package validator
+
+// Option is a function that configures a Validator.
+type Option func(*Validator)
+
+// Rule registers a custom validation rule under tag, with the error message template
+// used when it fails. In the template {0} is the field name and {1} the rule parameter.
+//
+// Example:
+//
+//	validator.Rule("sku", func(fl validator.FieldLevel) bool {
+//	    return skuPattern.MatchString(fl.Field().String())
+//	}, "{0} must be a valid SKU")
+func Rule(tag string, fn Func, message string) Option {
+	return func(v *Validator) {
+		v.rules = append(v.rules, rule{tag: tag, fn: fn, message: message})
+	}
+}
+
+// Message overrides the error message template of a rule, built-in or custom.
+// In the template {0} is the field name and {1} the rule parameter.
+//
+// Example:
+//
+//	validator.Message("min", "{0} needs at least {1} characters")
+func Message(tag, message string) Option {
+	return func(v *Validator) {
+		v.messages[tag] = message
+	}
+}
+
+// FieldNameTag sets the struct tag used to name fields in errors.
+// Default is "json"; fields without the tag use their Go name.
+func FieldNameTag(tag string) Option {
+	return func(v *Validator) {
+		v.fieldNameTag = tag
+	}
+}
@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// Enum is implemented by enumeration types validated with the "enum" rule.
+//
+// Example:
+//
+//	type Status string
+//
+//	func (s Status) IsValid() bool { return s == "active" || s == "blocked" }
+//
+//	type Request struct {
+//	    Status Status `json:"status" validate:"required,enum"`
+//	}
+type Enum interface {
+	IsValid() bool
+}
+
+// phonePattern matches E.164 numbers: a leading "+" and up to 15 digits.
+var phonePattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+func isPhone(fl FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+
+	return phonePattern.MatchString(field.String())
+}
+
+func isEnum(fl FieldLevel) bool {
+	field := fl.Field()
+	if !field.CanInterface() {
+		return false
+	}
+
+	if e, ok := field.Interface().(Enum); ok {
+		return e.IsValid()
+	}
+
+	if field.CanAddr() {
+		if e, ok := field.Addr().Interface().(Enum); ok {
+			return e.IsValid()
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,195 @@
+// Package validator provides request and struct validation on top of
+// go-playground/validator, with custom rules (phone, enum), English error messages
+// keyed by JSON field names, and adapters producing HTTP 422 problem details and
+// gRPC INVALID_ARGUMENT statuses.
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+)
+
+const _defaultFieldNameTag = "json"
+
+// FieldLevel gives a custom rule access to the validated field.
+type FieldLevel = validator.FieldLevel
+
+// Func is a custom validation rule reporting whether the field is valid.
+type Func = validator.Func
+
+type rule struct {
+	tag     string
+	fn      Func
+	message string
+}
+
+// Validator validates structs and single values. It is safe for concurrent use.
+type Validator struct {
+	validate   *validator.Validate
+	translator ut.Translator
+
+	fieldNameTag string
+	rules        []rule
+	messages     map[string]string
+}
+
+// New creates a new Validator with the built-in go-playground rules
+// plus phone and enum, and English error messages.
+// Field names in errors come from the json tag.
+//
+// Example:
+//
+//	v, err := validator.New(
+//	    validator.Rule("sku", isSKU, "{0} must be a valid SKU"),
+//	    validator.Message("required", "{0} is mandatory"),
+//	)
+func New(opts ...Option) (*Validator, error) {
+	v := &Validator{
+		validate:     validator.New(validator.WithRequiredStructEnabled()),
+		fieldNameTag: _defaultFieldNameTag,
+		messages:     make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	locale := en.New()
+
+	translator, _ := ut.New(locale, locale).GetTranslator("en")
+	v.translator = translator
+
+	if err := entranslations.RegisterDefaultTranslations(v.validate, translator); err != nil {
+		return nil, fmt.Errorf("validator - New - RegisterDefaultTranslations: %w", err)
+	}
+
+	v.validate.RegisterTagNameFunc(v.fieldName)
+
+	rules := append([]rule{
+		{tag: "phone", fn: isPhone, message: "{0} must be a valid phone number in E.164 format"},
+		{tag: "enum", fn: isEnum, message: "{0} must be one of the allowed values"},
+	}, v.rules...)
+
+	for _, r := range rules {
+		if err := v.validate.RegisterValidation(r.tag, r.fn); err != nil {
+			return nil, fmt.Errorf("validator - New - RegisterValidation %q: %w", r.tag, err)
+		}
+
+		if err := v.registerMessage(r.tag, r.message); err != nil {
+			return nil, err
+		}
+	}
+
+	for tag, message := range v.messages {
+		if err := v.registerMessage(tag, message); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// Struct validates the exported fields of s according to their validate tags.
+// Validation failures are returned as *ValidationError.
+//
+// Example:
+//
+//	type CreateUser struct {
+//	    Email string `json:"email" validate:"required,email"`
+//	    Phone string `json:"phone" validate:"omitempty,phone"`
+//	}
+//
+//	if err := v.Struct(ctx, req); err != nil {
+//	    return validator.HTTPError(c, err)
+//	}
+func (v *Validator) Struct(ctx context.Context, s interface{}) error {
+	return v.convert(v.validate.StructCtx(ctx, s))
+}
+
+// Var validates a single value against tag, e.g. v.Var(ctx, email, "required,email").
+// Validation failures are returned as *ValidationError.
+func (v *Validator) Var(ctx context.Context, field interface{}, tag string) error {
+	return v.convert(v.validate.VarCtx(ctx, field, tag))
+}
+
+// Engine returns the underlying go-playground validator for advanced configuration,
+// such as struct level validations.
+func (v *Validator) Engine() *validator.Validate {
+	return v.validate
+}
+
+func (v *Validator) convert(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return fmt.Errorf("validator - validate: %w", err)
+	}
+
+	fields := make([]FieldError, 0, len(fieldErrors))
+
+	for _, fe := range fieldErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(v.translator),
+		})
+	}
+
+	return &ValidationError{Fields: fields}
+}
+
+func (v *Validator) registerMessage(tag, message string) error {
+	err := v.validate.RegisterTranslation(tag, v.translator,
+		func(t ut.Translator) error {
+			return t.Add(tag, message, true)
+		},
+		func(t ut.Translator, fe validator.FieldError) string {
+			msg, err := t.T(fe.Tag(), fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+
+			return msg
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("validator - New - RegisterTranslation %q: %w", tag, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get(v.fieldNameTag), ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	default:
+		return name
+	}
+}
+
+// fieldPath strips the top level struct name from a namespace such as "CreateUser.address.city".
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return namespace
+	}
+
+	return path
+}
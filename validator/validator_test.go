@@ -0,0 +1,269 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type role string
+
+func (r role) IsValid() bool {
+	return r == "admin" || r == "user"
+}
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type createUser struct {
+	Email   string  `json:"email" validate:"required,email"`
+	Phone   string  `json:"phone" validate:"omitempty,phone"`
+	ID      string  `json:"id" validate:"omitempty,uuid"`
+	Role    role    `json:"role" validate:"required,enum"`
+	Name    string  `json:"name" validate:"min=3"`
+	Address address `json:"address"`
+}
+
+func mustNew(t *testing.T, opts ...validator.Option) *validator.Validator {
+	t.Helper()
+
+	v, err := validator.New(opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return v
+}
+
+func valid() createUser {
+	return createUser{
+		Email:   "john@example.com",
+		Phone:   "+14155552671",
+		ID:      "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		Role:    "admin",
+		Name:    "John",
+		Address: address{City: "Berlin"},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	v := mustNew(t)
+
+	tests := []struct {
+		name        string
+		mutate      func(*createUser)
+		wantField   string
+		wantRule    string
+		wantMessage string
+	}{
+		{name: "valid", mutate: func(*createUser) {}},
+		{
+			name:        "missing email",
+			mutate:      func(u *createUser) { u.Email = "" },
+			wantField:   "email",
+			wantRule:    "required",
+			wantMessage: "email is a required field",
+		},
+		{
+			name:        "invalid phone",
+			mutate:      func(u *createUser) { u.Phone = "555-1234" },
+			wantField:   "phone",
+			wantRule:    "phone",
+			wantMessage: "phone must be a valid phone number in E.164 format",
+		},
+		{
+			name:      "invalid uuid",
+			mutate:    func(u *createUser) { u.ID = "not-a-uuid" },
+			wantField: "id",
+			wantRule:  "uuid",
+		},
+		{
+			name:        "invalid enum",
+			mutate:      func(u *createUser) { u.Role = "root" },
+			wantField:   "role",
+			wantRule:    "enum",
+			wantMessage: "role must be one of the allowed values",
+		},
+		{
+			name:        "nested field",
+			mutate:      func(u *createUser) { u.Address.City = "" },
+			wantField:   "address.city",
+			wantRule:    "required",
+			wantMessage: "city is a required field",
+		},
+		{
+			name:        "rule parameter",
+			mutate:      func(u *createUser) { u.Name = "Jo" },
+			wantField:   "name",
+			wantRule:    "min",
+			wantMessage: "name must be at least 3 characters in length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := valid()
+			tt.mutate(&u)
+
+			err := v.Struct(context.Background(), u)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Struct() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var validationErr *validator.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Struct() error = %v, want *ValidationError", err)
+			}
+
+			if len(validationErr.Fields) != 1 {
+				t.Fatalf("Struct() fields = %+v, want 1", validationErr.Fields)
+			}
+
+			f := validationErr.Fields[0]
+			if f.Field != tt.wantField || f.Rule != tt.wantRule {
+				t.Errorf("Struct() field = %+v, want field %q rule %q", f, tt.wantField, tt.wantRule)
+			}
+
+			if tt.wantMessage != "" && f.Message != tt.wantMessage {
+				t.Errorf("Struct() message = %q, want %q", f.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	v := mustNew(t,
+		validator.Rule("even", func(fl validator.FieldLevel) bool { return fl.Field().Int()%2 == 0 }, "{0} must be even"),
+		validator.Message("min", "{0} needs {1}+ characters"),
+	)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		tag   string
+		want  string
+	}{
+		{name: "custom rule", value: 3, tag: "even", want: "must be even"},
+		{name: "overridden message", value: "ab", tag: "min=3", want: "needs 3+ characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Var(context.Background(), tt.value, tt.tag)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Var() error = %v, want message containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := v.Var(context.Background(), 4, "even"); err != nil {
+		t.Errorf("Var() error = %v, want nil", err)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	v := mustNew(t)
+
+	u := valid()
+	u.Email = ""
+
+	err := v.Struct(context.Background(), u)
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("status.FromError() = %v, %v, want InvalidArgument", st.Code(), ok)
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("status details = %v, want 1", details)
+	}
+
+	badRequest, ok := details[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.GetFieldViolations()) != 1 || badRequest.GetFieldViolations()[0].GetField() != "email" {
+		t.Errorf("status details = %v, want email violation", details[0])
+	}
+}
+
+func TestParseBody(t *testing.T) {
+	v := mustNew(t)
+
+	app := fiber.New()
+	app.Post("/users", func(c *fiber.Ctx) error {
+		var req createUser
+		if err := validator.ParseBody(c, v, &req); err != nil {
+			return validator.HTTPError(c, err)
+		}
+
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	body, _ := json.Marshal(valid())
+
+	tests := []struct {
+		name            string
+		body            string
+		wantStatus      int
+		wantContentType string
+	}{
+		{name: "valid", body: string(body), wantStatus: fiber.StatusCreated},
+		{name: "malformed", body: "{", wantStatus: fiber.StatusBadRequest},
+		{
+			name:            "invalid",
+			body:            `{"email":"bad"}`,
+			wantStatus:      fiber.StatusUnprocessableEntity,
+			wantContentType: validator.MIMEApplicationProblemJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/users", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantContentType == "" {
+				return
+			}
+
+			if ct := resp.Header.Get(fiber.HeaderContentType); ct != tt.wantContentType {
+				t.Errorf("content type = %q, want %q", ct, tt.wantContentType)
+			}
+
+			raw, _ := io.ReadAll(resp.Body)
+
+			var problem validator.ProblemDetails
+			if err := json.Unmarshal(raw, &problem); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if problem.Status != fiber.StatusUnprocessableEntity || len(problem.Errors) == 0 {
+				t.Errorf("problem = %+v", problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/httpclient"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+const (
+	_defaultTimeout = 10 * time.Second
+	// _maxErrorBody is the number of response body bytes kept in last_error.
+	_maxErrorBody = 1024
+)
+
+// record is a due delivery.
+type record struct {
+	Delivery
+	secret  string
+	headers map[string]string
+}
+
+// result is the outcome of a delivery attempt.
+type result struct {
+	statusCode int
+	retryAfter time.Duration
+	err        error
+}
+
+// Dispatcher delivers pending webhooks. Instances can run on every replica: due
+// deliveries are locked with FOR UPDATE SKIP LOCKED, so each is attempted by one
+// instance at a time. Delivery is at least once: a webhook may be sent again if the
+// dispatcher stops between sending and committing, so receivers should deduplicate
+// on the Webhook-Id header.
+type Dispatcher struct {
+	pg     *postgres.Postgres
+	client *httpclient.Client
+	cfg    config
+
+	notify chan error
+	stop   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	once   sync.Once
+}
+
+// NewDispatcher creates a new Dispatcher sending the deliveries of the table through
+// client, or a client with a 10 seconds timeout when nil. Requests are POSTs without
+// an Idempotency-Key header, so client does not retry them itself.
+// Default configuration: "webhook_deliveries" table, batches of 100 deliveries sent
+// 10 at a time, 1 second poll interval, 8 attempts with exponential backoff from 30
+// seconds up to 6 hours.
+//
+// Example:
+//
+//	d := webhooks.NewDispatcher(pg, nil, webhooks.WithLogger(l))
+//	d.Start()
+//	defer d.Shutdown()
+func NewDispatcher(pg *postgres.Postgres, client *httpclient.Client, opts ...Option) *Dispatcher {
+	if client == nil {
+		client = httpclient.New(httpclient.Timeout(_defaultTimeout))
+	}
+
+	return &Dispatcher{
+		pg:     pg,
+		client: client,
+		cfg:    newConfig(opts),
+		notify: make(chan error, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start begins delivering webhooks in a separate goroutine.
+// Use Notify() to receive dispatcher errors.
+func (d *Dispatcher) Start() {
+	d.start.Do(func() {
+		go d.run()
+	})
+}
+
+// Notify returns a channel that receives dispatcher errors, such as database failures.
+// Failed deliveries are recorded on the deliveries, not reported here.
+// Errors are dropped while a previous one has not been received.
+func (d *Dispatcher) Notify() <-chan error {
+	return d.notify
+}
+
+// Shutdown stops the dispatcher and waits for it to return. The current batch is
+// aborted: its requests are cancelled and its transaction rolls back, so its deliveries,
+// including those already sent, are attempted again by the next dispatcher.
+func (d *Dispatcher) Shutdown() error {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+
+	// Start may never have been called.
+	d.start.Do(func() { close(d.done) })
+
+	<-d.done
+
+	return nil
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-d.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			if d.cfg.logger != nil {
+				d.cfg.logger.Error(err)
+			}
+
+			select {
+			case d.notify <- err:
+			default:
+			}
+		}
+
+		// A full batch likely means more deliveries are due.
+		if n == d.cfg.batchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.cfg.pollInterval):
+		}
+	}
+}
+
+// DispatchOnce attempts one batch of due deliveries and returns the number of
+// deliveries attempted.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	tx, err := d.pg.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("webhooks - Dispatcher - DispatchOnce - d.pg.Pool.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	records, err := d.due(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	results := d.deliverAll(ctx, records)
+
+	var dead []Delivery
+
+	for i, rec := range records {
+		status, err := d.update(ctx, tx, rec, results[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if status == Dead {
+			rec.Status = Dead
+			rec.Attempts++
+			rec.LastStatusCode = results[i].statusCode
+			rec.LastError = results[i].err.Error()
+			dead = append(dead, rec.Delivery)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("webhooks - Dispatcher - DispatchOnce - tx.Commit: %w", err)
+	}
+
+	for _, delivery := range dead {
+		d.deadLetter(delivery)
+	}
+
+	return len(records), nil
+}
+
+func (d *Dispatcher) due(ctx context.Context, tx pgx.Tx) ([]*record, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf(
+		`SELECT id::text, url, event, payload, headers, secret, attempts, created_at
+		FROM %s WHERE status = 'pending' AND next_attempt_at <= now()
+		ORDER BY next_attempt_at LIMIT $1 FOR UPDATE SKIP LOCKED`, postgres.TableIdentifier(d.cfg.table).Sanitize()), d.cfg.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks - Dispatcher - due - tx.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*record
+
+	for rows.Next() {
+		var (
+			rec     record
+			headers []byte
+		)
+
+		err := rows.Scan(&rec.ID, &rec.URL, &rec.Event, &rec.Payload, &headers, &rec.secret, &rec.Attempts, &rec.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("webhooks - Dispatcher - due - rows.Scan: %w", err)
+		}
+
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &rec.headers); err != nil {
+				return nil, fmt.Errorf("webhooks - Dispatcher - due - json.Unmarshal: %w", err)
+			}
+		}
+
+		rec.Status = Pending
+		records = append(records, &rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("webhooks - Dispatcher - due - rows.Err: %w", err)
+	}
+
+	return records, nil
+}
+
+// deliverAll sends records with at most cfg.concurrency requests at once.
+func (d *Dispatcher) deliverAll(ctx context.Context, records []*record) []result {
+	results := make([]result, len(records))
+	slots := make(chan struct{}, d.cfg.concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, rec := range records {
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			results[i] = d.deliver(ctx, rec)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, rec *record) result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rec.URL, bytes.NewReader(rec.Payload))
+	if err != nil {
+		return result{err: fmt.Errorf("invalid request: %w", err)}
+	}
+
+	now := time.Now()
+
+	for key, value := range rec.headers {
+		req.Header.Set(key, value)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, rec.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderEvent, rec.Event)
+
+	if rec.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(rec.secret, rec.ID, now, rec.Payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return result{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, _maxErrorBody))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return result{statusCode: resp.StatusCode}
+	}
+
+	res := result{
+		statusCode: resp.StatusCode,
+		err:        fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body)),
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		res.retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return res
+}
+
+// update records the result of an attempt and returns the new status of the delivery.
+func (d *Dispatcher) update(ctx context.Context, tx pgx.Tx, rec *record, res result) (Status, error) {
+	table := postgres.TableIdentifier(d.cfg.table).Sanitize()
+	attempts := rec.Attempts + 1
+
+	var err error
+
+	switch {
+	case res.err == nil:
+		_, err = tx.Exec(ctx, fmt.Sprintf(
+			`UPDATE %s SET status = 'delivered', attempts = $2, last_status = $3, last_error = '', delivered_at = now()
+			WHERE id = $1`, table), rec.ID, attempts, res.statusCode)
+
+		if err == nil {
+			return Delivered, nil
+		}
+	case attempts >= d.cfg.maxAttempts:
+		_, err = tx.Exec(ctx, fmt.Sprintf(
+			"UPDATE %s SET status = 'dead', attempts = $2, last_status = $3, last_error = $4 WHERE id = $1",
+			table), rec.ID, attempts, res.statusCode, res.err.Error())
+
+		if err == nil {
+			return Dead, nil
+		}
+	default:
+		delay := max(d.cfg.backoff(attempts), res.retryAfter)
+
+		_, err = tx.Exec(ctx, fmt.Sprintf(
+			`UPDATE %s SET attempts = $2, last_status = $3, last_error = $4, next_attempt_at = now() + $5::interval
+			WHERE id = $1`, table), rec.ID, attempts, res.statusCode, res.err.Error(), delay)
+
+		if err == nil {
+			return Pending, nil
+		}
+	}
+
+	return "", fmt.Errorf("webhooks - Dispatcher - update - tx.Exec: %w", err)
+}
+
+func (d *Dispatcher) deadLetter(delivery Delivery) {
+	if d.cfg.onDead != nil {
+		d.cfg.onDead(delivery)
+
+		return
+	}
+
+	if d.cfg.logger != nil {
+		d.cfg.logger.Error("webhooks - Dispatcher - delivery %s of %s to %s dead after %d attempts: %s",
+			delivery.ID, delivery.Event, delivery.URL, delivery.Attempts, delivery.LastError)
+	}
+}
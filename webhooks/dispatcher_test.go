@@ -0,0 +1,112 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDispatcherDeliver(t *testing.T) {
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if err := Verify("secret", r.Header, body, time.Minute); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+
+		received = r.Header.Clone()
+
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusNoContent)
+		case "/busy":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("try later"))
+		}
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(nil, nil)
+
+	tests := []struct {
+		name           string
+		path           string
+		wantStatusCode int
+		wantErr        string
+		wantRetryAfter time.Duration
+	}{
+		{name: "delivered", path: "/ok", wantStatusCode: http.StatusNoContent},
+		{name: "unavailable", path: "/busy", wantStatusCode: http.StatusServiceUnavailable,
+			wantErr: "unexpected status 503: try later", wantRetryAfter: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &record{
+				Delivery: Delivery{ID: "6c1b0b8e-4f5e-4c53-9f5b-8d7b5f0e6d11", URL: server.URL + tt.path, Event: "invoice.paid",
+					Payload: []byte(`{"id":"42"}`)},
+				secret:  "secret",
+				headers: map[string]string{"X-Tenant-ID": "acme"},
+			}
+
+			res := d.deliver(context.Background(), rec)
+
+			if res.statusCode != tt.wantStatusCode || res.retryAfter != tt.wantRetryAfter {
+				t.Errorf("deliver() = status %d, retry after %v, want %d, %v",
+					res.statusCode, res.retryAfter, tt.wantStatusCode, tt.wantRetryAfter)
+			}
+
+			if (res.err == nil) != (tt.wantErr == "") || (res.err != nil && !strings.Contains(res.err.Error(), tt.wantErr)) {
+				t.Errorf("deliver() error = %v, want %q", res.err, tt.wantErr)
+			}
+
+			if received.Get(HeaderEvent) != "invoice.paid" || received.Get("X-Tenant-ID") != "acme" ||
+				received.Get(HeaderID) != rec.ID {
+				t.Errorf("received headers = %v", received)
+			}
+		})
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, Backoff(time.Second, 10*time.Second))
+
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 50: 10 * time.Second} {
+		if got := d.cfg.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestDispatcherShutdownWithoutStart(t *testing.T) {
+	d := NewDispatcher(nil, nil)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- d.Shutdown()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() without Start blocked")
+	}
+
+	// Start after Shutdown does nothing, so the dispatcher needs no database.
+	d.Start()
+
+	if err := d.Shutdown(); err != nil {
+		t.Errorf("second Shutdown() error = %v", err)
+	}
+}
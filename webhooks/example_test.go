@@ -0,0 +1,16 @@
+package webhooks_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/webhooks"
+)
+
+func ExampleSign() {
+	ts := time.Unix(1700000000, 0)
+
+	fmt.Println(webhooks.Sign("secret", "msg_1", ts, []byte(`{"id":"42"}`)))
+
+	// Output: v1,1b2bVbQqGMf9paKXNawRYU/YZCE+hTc4e7kOSQ+fXQ0=
+}
@@ -0,0 +1,125 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+	"github.com/rdashevsky/go-pkgs/retry"
+)
+
+const (
+	_defaultTable        = "webhook_deliveries"
+	_defaultBatchSize    = 100
+	_defaultConcurrency  = 10
+	_defaultPollInterval = time.Second
+	_defaultMaxAttempts  = 8
+	_defaultMinBackoff   = 30 * time.Second
+	_defaultMaxBackoff   = 6 * time.Hour
+)
+
+// Option is a function that configures a Store or a Dispatcher.
+type Option func(*config)
+
+type config struct {
+	table        string
+	batchSize    int
+	concurrency  int
+	pollInterval time.Duration
+	maxAttempts  int
+	backoff      retry.Backoff
+	onDead       func(d Delivery)
+	logger       logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		table:        _defaultTable,
+		batchSize:    _defaultBatchSize,
+		concurrency:  _defaultConcurrency,
+		pollInterval: _defaultPollInterval,
+		maxAttempts:  _defaultMaxAttempts,
+		backoff:      retry.Exponential(_defaultMinBackoff, _defaultMaxBackoff),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.batchSize < 1 {
+		cfg.batchSize = 1
+	}
+
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+
+	if cfg.maxAttempts < 1 {
+		cfg.maxAttempts = 1
+	}
+
+	return cfg
+}
+
+// Table sets the deliveries table name, optionally schema qualified ("schema.table").
+// Default is "webhook_deliveries".
+func Table(name string) Option {
+	return func(c *config) {
+		c.table = name
+	}
+}
+
+// BatchSize sets the maximum number of deliveries the dispatcher attempts per
+// transaction. Default is 100.
+func BatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// Concurrency sets how many deliveries of a batch the dispatcher sends at once.
+// Default is 10.
+func Concurrency(n int) Option {
+	return func(c *config) {
+		c.concurrency = n
+	}
+}
+
+// PollInterval sets how often the dispatcher checks for due deliveries when none
+// are pending. Default is 1 second.
+func PollInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.pollInterval = interval
+	}
+}
+
+// MaxAttempts sets the number of attempts before a delivery is dead-lettered.
+// Default is 8.
+func MaxAttempts(n int) Option {
+	return func(c *config) {
+		c.maxAttempts = n
+	}
+}
+
+// Backoff sets the delay before the first retry, doubled on every further retry up
+// to maxDelay. Default is 30 seconds up to 6 hours, so the default 8 attempts span
+// about an hour.
+func Backoff(initial, maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.backoff = retry.Exponential(initial, maxDelay)
+	}
+}
+
+// OnDeadLetter sets a function called with deliveries that failed their last
+// attempt, e.g. to alert or disable the endpoint. Default is logging them.
+func OnDeadLetter(fn func(d Delivery)) Option {
+	return func(c *config) {
+		c.onDead = fn
+	}
+}
+
+// WithLogger sets the dispatcher logger.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,212 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+const _deliveryColumns = `id::text, url, event, payload, status, attempts, last_status, last_error,
+	next_attempt_at, created_at, delivered_at`
+
+// Store writes and reads deliveries.
+type Store struct {
+	table   string
+	builder squirrel.StatementBuilderType
+}
+
+// Filter selects deliveries listed by Store.List. Empty fields match all deliveries.
+type Filter struct {
+	Status Status
+	URL    string
+	Event  string
+	// Limit is the maximum number of deliveries returned, 100 when zero.
+	Limit uint64
+}
+
+// NewStore creates a new Store.
+// Default configuration: "webhook_deliveries" table.
+//
+// Example:
+//
+//	store := webhooks.NewStore()
+//
+//	tx, err := pg.Pool.Begin(ctx)
+//	// ... business changes in tx ...
+//	msg, err := webhooks.JSONMessage(endpoint.URL, "invoice.paid", endpoint.Secret, invoice)
+//	ids, err := store.Enqueue(ctx, tx, msg)
+//	err = tx.Commit(ctx)
+func NewStore(opts ...Option) *Store {
+	cfg := newConfig(opts)
+
+	return &Store{
+		table:   cfg.table,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// CreateTable creates the deliveries table if it does not exist.
+func (s *Store) CreateTable(ctx context.Context, db DBTX) error {
+	if _, err := db.Exec(ctx, Schema(s.table)); err != nil {
+		return fmt.Errorf("webhooks - Store - CreateTable - db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Enqueue stores messages for delivery using db, which should be the transaction of
+// the business change so that webhooks are sent if and only if it commits. It
+// returns the delivery IDs, also sent in the Webhook-Id header.
+func (s *Store) Enqueue(ctx context.Context, db DBTX, msgs ...Message) ([]string, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	q := s.builder.Insert(postgres.TableIdentifier(s.table).Sanitize()).
+		Columns("id", "url", "event", "payload", "headers", "secret")
+
+	ids := make([]string, 0, len(msgs))
+
+	for _, m := range msgs {
+		var headers []byte
+
+		if len(m.Headers) > 0 {
+			var err error
+
+			headers, err = json.Marshal(m.Headers)
+			if err != nil {
+				return nil, fmt.Errorf("webhooks - Store - Enqueue - json.Marshal: %w", err)
+			}
+		}
+
+		id := uuid.NewString()
+		ids = append(ids, id)
+		q = q.Values(id, m.URL, m.Event, m.Payload, headers, m.Secret)
+	}
+
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("webhooks - Store - Enqueue - q.ToSql: %w", err)
+	}
+
+	if _, err := db.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("webhooks - Store - Enqueue - db.Exec: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Get returns the delivery with id, or ErrNotFound.
+func (s *Store) Get(ctx context.Context, db DBTX, id string) (*Delivery, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("webhooks - Store - Get: %w", ErrNotFound)
+	}
+
+	row := db.QueryRow(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", _deliveryColumns, postgres.TableIdentifier(s.table).Sanitize()), id)
+
+	d, err := scanDelivery(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("webhooks - Store - Get: %w", ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("webhooks - Store - Get - row.Scan: %w", err)
+	}
+
+	return d, nil
+}
+
+// List returns the deliveries matching f, most recent first.
+func (s *Store) List(ctx context.Context, db DBTX, f Filter) ([]*Delivery, error) {
+	limit := f.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	q := s.builder.Select(_deliveryColumns).From(postgres.TableIdentifier(s.table).Sanitize()).OrderBy("created_at DESC").Limit(limit)
+
+	if f.Status != "" {
+		q = q.Where(squirrel.Eq{"status": string(f.Status)})
+	}
+
+	if f.URL != "" {
+		q = q.Where(squirrel.Eq{"url": f.URL})
+	}
+
+	if f.Event != "" {
+		q = q.Where(squirrel.Eq{"event": f.Event})
+	}
+
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("webhooks - Store - List - q.ToSql: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks - Store - List - db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*Delivery
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("webhooks - Store - List - rows.Scan: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("webhooks - Store - List - rows.Err: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver schedules a dead or delivered delivery for immediate delivery with a
+// fresh set of attempts, e.g. once its endpoint is fixed. It returns ErrNotFound when
+// the delivery does not exist.
+func (s *Store) Redeliver(ctx context.Context, db DBTX, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("webhooks - Store - Redeliver: %w", ErrNotFound)
+	}
+
+	tag, err := db.Exec(ctx, fmt.Sprintf(
+		`UPDATE %s SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+		WHERE id = $1`, postgres.TableIdentifier(s.table).Sanitize()), id)
+	if err != nil {
+		return fmt.Errorf("webhooks - Store - Redeliver - db.Exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("webhooks - Store - Redeliver: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanDelivery(row pgx.Row) (*Delivery, error) {
+	var (
+		d      Delivery
+		status string
+	)
+
+	err := row.Scan(&d.ID, &d.URL, &d.Event, &d.Payload, &status, &d.Attempts, &d.LastStatusCode, &d.LastError,
+		&d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Status = Status(status)
+
+	return &d, nil
+}
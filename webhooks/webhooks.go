@@ -0,0 +1,114 @@
+// Package webhooks delivers outbound webhooks reliably, the outbound counterpart of
+// the HTTP server. Messages are stored in a Postgres table, ideally in the
+// transaction of the business change, and a Dispatcher POSTs them to their URL with
+// an HMAC signature through the httpclient package, retrying failed deliveries with
+// exponential backoff and dead-lettering them after the last attempt. The Store
+// reports the status of deliveries and redelivers dead ones.
+//
+// Signatures follow the Standard Webhooks scheme: the "Webhook-Signature" header
+// carries "v1,<base64 HMAC-SHA256 of id.timestamp.payload>", so receivers can use
+// Verify or any Standard Webhooks library.
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rdashevsky/go-pkgs/postgres"
+)
+
+// ErrNotFound is returned when a delivery does not exist.
+var ErrNotFound = errors.New("webhooks - delivery not found")
+
+// DBTX is implemented by pgx.Tx, *pgxpool.Pool and *pgx.Conn.
+// Enqueue is meant to be called with the transaction of the business change.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Status is the state of a delivery.
+type Status string
+
+// Delivery statuses.
+const (
+	// Pending deliveries wait for their first or next attempt.
+	Pending Status = "pending"
+	// Delivered deliveries got a 2xx response.
+	Delivered Status = "delivered"
+	// Dead deliveries failed all their attempts.
+	Dead Status = "dead"
+)
+
+// Message is a webhook to deliver.
+type Message struct {
+	// URL is the endpoint receiving the webhook.
+	URL string
+	// Event is the event type, e.g. "invoice.paid", sent in the Webhook-Event header.
+	Event string
+	// Payload is the JSON request body.
+	Payload []byte
+	// Secret is the signing secret of the endpoint. It is stored with the delivery;
+	// no signature is sent when empty.
+	Secret string
+	// Headers are additional request headers.
+	Headers map[string]string
+}
+
+// JSONMessage returns a Message with v encoded as JSON payload.
+func JSONMessage(url, event, secret string, v interface{}) (Message, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return Message{}, fmt.Errorf("webhooks - JSONMessage - json.Marshal: %w", err)
+	}
+
+	return Message{URL: url, Event: event, Payload: payload, Secret: secret}, nil
+}
+
+// Delivery is the state of a stored webhook. The secret is not exposed.
+type Delivery struct {
+	ID      string
+	URL     string
+	Event   string
+	Payload []byte
+	Status  Status
+	// Attempts is the number of delivery attempts made.
+	Attempts int
+	// LastStatusCode is the response status of the last attempt, 0 when it got none.
+	LastStatusCode int
+	// LastError describes the failure of the last attempt.
+	LastError string
+	// NextAttemptAt is when a pending delivery is attempted next.
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	// DeliveredAt is set once the delivery succeeded.
+	DeliveredAt *time.Time
+}
+
+// Schema returns the DDL creating the deliveries table and its pending index,
+// for use in migrations (e.g. with the goose package).
+func Schema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    id              UUID PRIMARY KEY,
+    url             TEXT NOT NULL,
+    event           TEXT NOT NULL,
+    payload         BYTEA NOT NULL,
+    headers         JSONB,
+    secret          TEXT NOT NULL DEFAULT '',
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INT NOT NULL DEFAULT 0,
+    last_status     INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (next_attempt_at) WHERE status = 'pending';`,
+		postgres.TableIdentifier(table).Sanitize(), postgres.IndexIdentifier(table, "pending").Sanitize())
+}
@@ -0,0 +1,85 @@
+package webhooks_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/webhooks"
+)
+
+func signedHeader(secret, id string, ts time.Time, payload []byte) http.Header {
+	h := make(http.Header)
+	h.Set(webhooks.HeaderID, id)
+	h.Set(webhooks.HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+	h.Set(webhooks.HeaderSignature, webhooks.Sign(secret, id, ts, payload))
+
+	return h
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"invoice":"42"}`)
+	now := time.Now()
+
+	rotated := signedHeader("new", "msg_1", now, payload)
+	rotated.Set(webhooks.HeaderSignature, webhooks.Sign("old", "msg_1", now, payload)+" "+rotated.Get(webhooks.HeaderSignature))
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		payload []byte
+		secret  string
+		wantErr bool
+	}{
+		{name: "valid", header: signedHeader("secret", "msg_1", now, payload), payload: payload, secret: "secret"},
+		{name: "other secret", header: signedHeader("secret", "msg_1", now, payload), payload: payload, secret: "other", wantErr: true},
+		{name: "tampered payload", header: signedHeader("secret", "msg_1", now, payload), payload: []byte(`{}`), secret: "secret", wantErr: true},
+		{name: "too old", header: signedHeader("secret", "msg_1", now.Add(-time.Hour), payload), payload: payload, secret: "secret", wantErr: true},
+		{name: "missing headers", header: http.Header{}, payload: payload, secret: "secret", wantErr: true},
+		{name: "one of several signatures", header: rotated, payload: payload, secret: "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhooks.Verify(tt.secret, tt.header, tt.payload, 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, webhooks.ErrInvalidSignature) {
+				t.Errorf("Verify() error = %v, want %v", err, webhooks.ErrInvalidSignature)
+			}
+		})
+	}
+}
+
+func TestJSONMessage(t *testing.T) {
+	msg, err := webhooks.JSONMessage("https://example.com/hook", "invoice.paid", "secret", map[string]string{"id": "42"})
+	if err != nil {
+		t.Fatalf("JSONMessage() error = %v", err)
+	}
+
+	if string(msg.Payload) != `{"id":"42"}` || msg.Event != "invoice.paid" {
+		t.Errorf("JSONMessage() = %+v", msg)
+	}
+
+	if _, err := webhooks.JSONMessage("", "", "", func() {}); err == nil {
+		t.Error("JSONMessage() of a func error = nil, want error")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := webhooks.Schema("app.webhook_deliveries")
+
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "app"."webhook_deliveries"`,
+		`"webhook_deliveries_pending_idx" ON "app"."webhook_deliveries" (next_attempt_at) WHERE status = 'pending'`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("Schema() does not contain %q", want)
+		}
+	}
+}
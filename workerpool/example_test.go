@@ -0,0 +1,32 @@
+package workerpool_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rdashevsky/go-pkgs/workerpool"
+)
+
+// Example demonstrates processing tasks concurrently and collecting results
+func Example() {
+	p := workerpool.New(func(_ context.Context, word string) (string, error) {
+		return strings.ToUpper(word), nil
+	}, workerpool.Workers(1))
+	p.Start()
+
+	for _, w := range []string{"alpha", "beta"} {
+		_ = p.Submit(context.Background(), w)
+	}
+
+	for i := 0; i < 2; i++ {
+		r := <-p.Results()
+		fmt.Println(r.Task, "->", r.Value)
+	}
+
+	_ = p.Shutdown()
+
+	// Output:
+	// alpha -> ALPHA
+	// beta -> BETA
+}
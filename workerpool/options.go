@@ -0,0 +1,58 @@
+package workerpool
+
+import (
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+// Option is a function that configures a Pool.
+// Options are applied in the order they are passed to New.
+type Option func(*config)
+
+// Workers sets the number of concurrent workers. Values below 1 are treated as 1.
+// Default is 4.
+func Workers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// QueueSize sets the capacity of the task queue and of the results buffer.
+// Default is 64.
+func QueueSize(size int) Option {
+	return func(c *config) {
+		c.queueSize = size
+	}
+}
+
+// DiscardResults disables the Results channel, for pools used only for side effects.
+func DiscardResults() Option {
+	return func(c *config) {
+		c.discardResults = true
+	}
+}
+
+// ShutdownTimeout sets the maximum duration Shutdown waits for tasks to drain.
+// Default is 10 seconds.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.shutdownTimeout = timeout
+	}
+}
+
+// WithMetrics sets the hook receiving task and queue measurements.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		if m != nil {
+			c.metrics = m
+		}
+	}
+}
+
+// WithLogger sets the logger used to report recovered panics with their stack trace.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,291 @@
+// Package workerpool provides a bounded concurrency worker pool with a generic
+// Submit/Results API, a bounded queue, graceful drain on shutdown, panic isolation
+// and metrics hooks.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultWorkers         = 4
+	_defaultQueueSize       = 64
+	_defaultShutdownTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned by TrySubmit when the queue is at capacity.
+	ErrQueueFull = errors.New("workerpool - queue is full")
+	// ErrClosed is returned when submitting to a pool that is shutting down.
+	ErrClosed = errors.New("workerpool - pool is closed")
+	// ErrPanic wraps a panic recovered from a task handler.
+	ErrPanic = errors.New("workerpool - task panicked")
+)
+
+// Handler processes a single task.
+// The context is cancelled when the pool shutdown timeout expires.
+type Handler[T, R any] func(ctx context.Context, task T) (R, error)
+
+// Result is the outcome of a processed task.
+type Result[T, R any] struct {
+	Task  T
+	Value R
+	Err   error
+}
+
+// Metrics receives pool measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveTask is called after every task with its processing duration and error.
+	ObserveTask(duration time.Duration, err error)
+	// ObserveQueueDepth is called with the number of queued tasks whenever a task is enqueued.
+	ObserveQueueDepth(depth int)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveTask(time.Duration, error) {}
+func (noopMetrics) ObserveQueueDepth(int)            {}
+
+type config struct {
+	workers         int
+	queueSize       int
+	discardResults  bool
+	shutdownTimeout time.Duration
+	metrics         Metrics
+	logger          logger.LoggerI
+}
+
+// Pool runs tasks of type T on a fixed number of workers, producing results of type R.
+//
+// Unless DiscardResults is set, every task produces a Result on Results(),
+// which must be drained by the caller; workers block while the results buffer is full.
+type Pool[T, R any] struct {
+	handler Handler[T, R]
+	cfg     config
+
+	queue   chan T
+	results chan Result[T, R]
+	closing chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.RWMutex
+	closeOnce sync.Once
+	started   bool
+	closed    bool
+}
+
+// New creates a new Pool processing tasks with handler.
+// Default configuration: 4 workers, queue of 64 tasks, 10 seconds shutdown timeout.
+//
+// Example:
+//
+//	p := workerpool.New(func(ctx context.Context, url string) (int, error) {
+//	    return fetchStatus(ctx, url)
+//	}, workerpool.Workers(8))
+//	p.Start()
+func New[T, R any](handler Handler[T, R], opts ...Option) *Pool[T, R] {
+	cfg := config{
+		workers:         _defaultWorkers,
+		queueSize:       _defaultQueueSize,
+		shutdownTimeout: _defaultShutdownTimeout,
+		metrics:         noopMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	if cfg.queueSize < 0 {
+		cfg.queueSize = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Pool[T, R]{
+		handler: handler,
+		cfg:     cfg,
+		queue:   make(chan T, cfg.queueSize),
+		closing: make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	if !cfg.discardResults {
+		p.results = make(chan Result[T, R], cfg.queueSize)
+	}
+
+	return p
+}
+
+// Start launches the workers. Calling Start more than once has no effect.
+func (p *Pool[T, R]) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started || p.closed {
+		return
+	}
+
+	p.started = true
+
+	for i := 0; i < p.cfg.workers; i++ {
+		p.wg.Add(1)
+
+		go p.worker()
+	}
+}
+
+// Submit enqueues a task, blocking while the queue is full until ctx is done.
+// Returns ErrClosed if the pool is shutting down.
+func (p *Pool[T, R]) Submit(ctx context.Context, task T) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- task:
+		p.cfg.metrics.ObserveQueueDepth(len(p.queue))
+
+		return nil
+	case <-p.closing:
+		return ErrClosed
+	case <-ctx.Done():
+		return fmt.Errorf("workerpool - Submit - %w", ctx.Err())
+	}
+}
+
+// TrySubmit enqueues a task without blocking.
+// Returns ErrQueueFull if the queue is at capacity and ErrClosed if the pool is shutting down.
+func (p *Pool[T, R]) TrySubmit(task T) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- task:
+		p.cfg.metrics.ObserveQueueDepth(len(p.queue))
+
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Results returns the channel receiving task results.
+// It is nil when the pool was created with DiscardResults,
+// and is closed after Shutdown once all workers have exited.
+func (p *Pool[T, R]) Results() <-chan Result[T, R] {
+	return p.results
+}
+
+// Len returns the number of tasks waiting in the queue.
+func (p *Pool[T, R]) Len() int {
+	return len(p.queue)
+}
+
+func (p *Pool[T, R]) worker() {
+	defer p.wg.Done()
+
+	for task := range p.queue {
+		start := time.Now()
+
+		value, err := p.process(task)
+
+		p.cfg.metrics.ObserveTask(time.Since(start), err)
+
+		if p.results != nil {
+			// Results are dropped once the shutdown timeout expired, so an
+			// abandoned Results channel cannot block Shutdown forever.
+			select {
+			case p.results <- Result[T, R]{Task: task, Value: value, Err: err}:
+			case <-p.ctx.Done():
+			}
+		}
+	}
+}
+
+func (p *Pool[T, R]) process(task T) (value R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+
+			if p.cfg.logger != nil {
+				p.cfg.logger.Error("workerpool - task panicked: %v\n%s", r, debug.Stack())
+			}
+		}
+	}()
+
+	return p.handler(p.ctx, task)
+}
+
+// Shutdown stops accepting tasks and waits up to the configured shutdown timeout
+// for queued and running tasks to complete. After the timeout the handler context
+// is cancelled: remaining queued tasks are still passed to the handler with the
+// cancelled context so they can fail fast, and their results are dropped.
+// If the pool was never started, queued tasks are discarded.
+func (p *Pool[T, R]) Shutdown() error {
+	first := false
+
+	// closing is closed before taking the write lock to release Submit calls
+	// blocked on a full queue while holding the read lock.
+	p.closeOnce.Do(func() {
+		first = true
+		close(p.closing)
+	})
+
+	if !first {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-time.After(p.cfg.shutdownTimeout):
+		err = fmt.Errorf("workerpool - Shutdown - timed out with %d tasks pending", len(p.queue))
+
+		p.cancel()
+		<-done
+	}
+
+	p.cancel()
+
+	if p.results != nil {
+		close(p.results)
+	}
+
+	return err
+}
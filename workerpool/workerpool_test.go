@@ -0,0 +1,229 @@
+package workerpool_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdashevsky/go-pkgs/workerpool"
+)
+
+type recordingMetrics struct {
+	tasks    atomic.Int32
+	failures atomic.Int32
+	maxDepth atomic.Int32
+}
+
+func (m *recordingMetrics) ObserveTask(_ time.Duration, err error) {
+	m.tasks.Add(1)
+
+	if err != nil {
+		m.failures.Add(1)
+	}
+}
+
+func (m *recordingMetrics) ObserveQueueDepth(depth int) {
+	for {
+		current := m.maxDepth.Load()
+		if int32(depth) <= current || m.maxDepth.CompareAndSwap(current, int32(depth)) { //nolint:gosec // test values are small
+			return
+		}
+	}
+}
+
+func TestPool_SubmitAndResults(t *testing.T) {
+	p := workerpool.New(func(_ context.Context, n int) (int, error) {
+		return n * n, nil
+	}, workerpool.Workers(3), workerpool.QueueSize(10))
+	p.Start()
+
+	for i := 1; i <= 5; i++ {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	var squares []int
+
+	for i := 0; i < 5; i++ {
+		r := <-p.Results()
+		if r.Err != nil {
+			t.Fatalf("unexpected task error: %v", r.Err)
+		}
+
+		if r.Value != r.Task*r.Task {
+			t.Errorf("expected %d, got %d", r.Task*r.Task, r.Value)
+		}
+
+		squares = append(squares, r.Value)
+	}
+
+	sort.Ints(squares)
+
+	if len(squares) != 5 || squares[0] != 1 || squares[4] != 25 {
+		t.Errorf("unexpected results: %v", squares)
+	}
+
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("expected no error on shutdown, got: %v", err)
+	}
+
+	if _, ok := <-p.Results(); ok {
+		t.Error("expected Results channel to be closed after shutdown")
+	}
+}
+
+func TestPool_BoundedConcurrency(t *testing.T) {
+	var (
+		running atomic.Int32
+		peak    atomic.Int32
+	)
+
+	p := workerpool.New(func(_ context.Context, _ int) (struct{}, error) {
+		n := running.Add(1)
+		for {
+			current := peak.Load()
+			if n <= current || peak.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+
+		return struct{}{}, nil
+	}, workerpool.Workers(2), workerpool.DiscardResults())
+	p.Start()
+
+	for i := 0; i < 10; i++ {
+		_ = p.Submit(context.Background(), i)
+	}
+
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("expected graceful drain, got: %v", err)
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent tasks, got %d", peak.Load())
+	}
+
+	if p.Results() != nil {
+		t.Error("expected nil Results channel with DiscardResults")
+	}
+}
+
+func TestPool_PanicIsolation(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	p := workerpool.New(func(_ context.Context, n int) (int, error) {
+		if n == 0 {
+			panic("division by zero")
+		}
+
+		return 10 / n, nil
+	}, workerpool.Workers(1), workerpool.WithMetrics(metrics))
+	p.Start()
+
+	_ = p.Submit(context.Background(), 0)
+	_ = p.Submit(context.Background(), 5)
+
+	first, second := <-p.Results(), <-p.Results()
+
+	if !errors.Is(first.Err, workerpool.ErrPanic) {
+		t.Errorf("expected ErrPanic, got: %v", first.Err)
+	}
+
+	if second.Err != nil || second.Value != 2 {
+		t.Errorf("expected worker to survive the panic, got %v (%v)", second.Value, second.Err)
+	}
+
+	_ = p.Shutdown()
+
+	if metrics.tasks.Load() != 2 || metrics.failures.Load() != 1 {
+		t.Errorf("expected 2 tasks and 1 failure, got %d and %d", metrics.tasks.Load(), metrics.failures.Load())
+	}
+
+	if metrics.maxDepth.Load() < 1 {
+		t.Error("expected queue depth to be observed")
+	}
+}
+
+func TestPool_TrySubmit_QueueFull(t *testing.T) {
+	p := workerpool.New(func(_ context.Context, _ int) (int, error) {
+		return 0, nil
+	}, workerpool.QueueSize(1))
+
+	if err := p.TrySubmit(1); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if err := p.TrySubmit(2); !errors.Is(err, workerpool.ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got: %v", err)
+	}
+
+	if p.Len() != 1 {
+		t.Errorf("expected 1 queued task, got %d", p.Len())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := p.Submit(ctx, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Submit to give up when ctx expires, got: %v", err)
+	}
+}
+
+func TestPool_ShutdownReleasesBlockedSubmit(t *testing.T) {
+	p := workerpool.New(func(_ context.Context, _ int) (int, error) {
+		return 0, nil
+	}, workerpool.QueueSize(0), workerpool.DiscardResults())
+
+	errs := make(chan error, 1)
+
+	go func() { errs <- p.Submit(context.Background(), 1) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, workerpool.ErrClosed) {
+			t.Errorf("expected ErrClosed, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected blocked Submit to be released by Shutdown")
+	}
+
+	if err := p.TrySubmit(1); !errors.Is(err, workerpool.ErrClosed) {
+		t.Errorf("expected ErrClosed after shutdown, got: %v", err)
+	}
+}
+
+func TestPool_ShutdownTimeout(t *testing.T) {
+	var cancelled sync.WaitGroup
+	cancelled.Add(1)
+
+	p := workerpool.New(func(ctx context.Context, _ int) (int, error) {
+		<-ctx.Done()
+		cancelled.Done()
+
+		return 0, ctx.Err()
+	}, workerpool.Workers(1), workerpool.ShutdownTimeout(20*time.Millisecond))
+	p.Start()
+
+	_ = p.Submit(context.Background(), 1)
+
+	// Nobody drains Results, Shutdown must still return.
+	if err := p.Shutdown(); err == nil {
+		t.Error("expected shutdown timeout error")
+	}
+
+	cancelled.Wait()
+}
@@ -0,0 +1,72 @@
+package wshub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdashevsky/go-pkgs/redis"
+)
+
+const _defaultChannel = "wshub"
+
+// Bridge relays hub messages between instances.
+type Bridge interface {
+	// Publish sends data to every instance, including the publishing one.
+	Publish(ctx context.Context, data []byte) error
+	// Subscribe calls fn for every published message until ctx is done.
+	Subscribe(ctx context.Context, fn func(data []byte)) error
+}
+
+// RedisBridge returns a Bridge built on Redis pub/sub. Instances sharing a
+// channel share broadcasts; an empty channel defaults to "wshub".
+//
+// Example:
+//
+//	hub := wshub.New(wshub.WithBridge(wshub.RedisBridge(r, "chat")))
+func RedisBridge(r *redis.Redis, channel string) Bridge {
+	if channel == "" {
+		channel = _defaultChannel
+	}
+
+	// Channels are not keys: the KeyPrefix of r is added here.
+	return &redisBridge{r: r, channel: r.Key(channel)}
+}
+
+type redisBridge struct {
+	r       *redis.Redis
+	channel string
+}
+
+func (b *redisBridge) Publish(ctx context.Context, data []byte) error {
+	if err := b.r.Client().Publish(ctx, b.channel, data).Err(); err != nil {
+		return fmt.Errorf("wshub - redisBridge - Publish - Client.Publish: %w", err)
+	}
+
+	return nil
+}
+
+func (b *redisBridge) Subscribe(ctx context.Context, fn func(data []byte)) error {
+	ps := b.r.Client().Subscribe(ctx, b.channel)
+	defer ps.Close()
+
+	// Wait for the subscription to be confirmed so that errors surface here;
+	// later connection drops are handled by the client reconnecting.
+	if _, err := ps.Receive(ctx); err != nil {
+		return fmt.Errorf("wshub - redisBridge - Subscribe - PubSub.Receive: %w", err)
+	}
+
+	ch := ps.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			fn([]byte(msg.Payload))
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package wshub
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/rdashevsky/go-pkgs/ids"
+)
+
+// Client is a WebSocket connection registered with a Hub.
+type Client struct {
+	id    string
+	hub   *Hub
+	conn  *websocket.Conn
+	send  chan []byte
+	rooms map[string]struct{} // guarded by hub.mu
+
+	done       chan struct{}
+	closeOnce  sync.Once
+	closeCode  int
+	closeText  string
+	localsMu   sync.RWMutex
+	locals     map[string]any
+	remoteAddr string
+}
+
+// ID returns the client ID.
+func (c *Client) ID() string {
+	return c.id
+}
+
+// Hub returns the hub the client is registered with.
+func (c *Client) Hub() *Hub {
+	return c.hub
+}
+
+// IP returns the client IP address.
+func (c *Client) IP() string {
+	return c.remoteAddr
+}
+
+// Get returns the value stored under key with Set, or the request local of the
+// upgraded request when none was set.
+func (c *Client) Get(key string) any {
+	c.localsMu.RLock()
+	v, ok := c.locals[key]
+	c.localsMu.RUnlock()
+
+	if ok {
+		return v
+	}
+
+	select {
+	case <-c.done:
+		// The connection is released once the client is closed.
+		return nil
+	default:
+		return c.conn.Locals(key)
+	}
+}
+
+// Set stores a value on the client, e.g. the authenticated user.
+func (c *Client) Set(key string, value any) {
+	c.localsMu.Lock()
+	defer c.localsMu.Unlock()
+
+	c.locals[key] = value
+}
+
+// Join adds the client to room.
+func (c *Client) Join(room string) {
+	c.hub.join(c, room)
+}
+
+// Leave removes the client from room.
+func (c *Client) Leave(room string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+
+	c.hub.leave(c, room)
+}
+
+// Rooms returns the rooms the client is in.
+func (c *Client) Rooms() []string {
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+
+	return rooms
+}
+
+// Send queues msg for the client, applying the hub overflow policy when its
+// buffer is full. It reports whether msg was queued.
+func (c *Client) Send(msg []byte) bool {
+	return c.deliver(msg)
+}
+
+// Close closes the connection with a normal closure status.
+func (c *Client) Close() {
+	c.close(websocket.CloseNormalClosure, "")
+}
+
+func (c *Client) deliver(msg []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+	}
+
+	if c.hub.cfg.policy == Disconnect {
+		c.close(websocket.ClosePolicyViolation, "client too slow")
+	}
+
+	return false
+}
+
+func (c *Client) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+	})
+}
+
+func (h *Hub) serve(conn *websocket.Conn) {
+	id := ""
+	if h.cfg.clientID != nil {
+		id = h.cfg.clientID(conn)
+	}
+
+	if id == "" {
+		id = ids.NewUUID().String()
+	}
+
+	c := &Client{
+		id:         id,
+		hub:        h,
+		conn:       conn,
+		send:       make(chan []byte, h.cfg.sendBuffer),
+		rooms:      make(map[string]struct{}),
+		done:       make(chan struct{}),
+		locals:     make(map[string]any),
+		remoteAddr: conn.IP(),
+	}
+
+	h.wg.Add(1)
+	defer h.wg.Done()
+
+	if !h.register(c) {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(h.cfg.writeTimeout))
+
+		return
+	}
+
+	writerDone := make(chan struct{})
+
+	go func() {
+		defer close(writerDone)
+		c.writeLoop()
+	}()
+
+	if h.cfg.onConnect != nil {
+		h.safely(func() { h.cfg.onConnect(c) })
+	}
+
+	c.readLoop()
+	c.close(websocket.CloseNormalClosure, "")
+	<-writerDone
+
+	h.unregister(c)
+
+	if h.cfg.onDisconnect != nil {
+		h.safely(func() { h.cfg.onDisconnect(c) })
+	}
+}
+
+func (c *Client) readLoop() {
+	cfg := &c.hub.cfg
+
+	c.conn.SetReadLimit(cfg.maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(cfg.pongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(cfg.pongTimeout))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		_ = c.conn.SetReadDeadline(time.Now().Add(cfg.pongTimeout))
+
+		if cfg.onMessage != nil {
+			c.hub.safely(func() { cfg.onMessage(c, msg) })
+		}
+	}
+}
+
+func (c *Client) writeLoop() {
+	cfg := &c.hub.cfg
+
+	ticker := time.NewTicker(cfg.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(cfg.writeTimeout))
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				_ = c.conn.Close()
+
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(cfg.writeTimeout)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				_ = c.conn.Close()
+
+				return
+			}
+		case <-c.done:
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(c.closeCode, c.closeText),
+				time.Now().Add(cfg.writeTimeout))
+			// Closing the connection unblocks the read loop.
+			_ = c.conn.Close()
+
+			return
+		}
+	}
+}
+
+// safely runs a user callback, logging instead of crashing the connection on panic.
+func (h *Hub) safely(fn func()) {
+	defer func() {
+		if r := recover(); r != nil && h.cfg.logger != nil {
+			h.cfg.logger.Error("wshub - callback panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	fn()
+}
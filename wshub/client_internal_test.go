@@ -0,0 +1,46 @@
+package wshub
+
+import (
+	"testing"
+)
+
+func TestClient_Overflow(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     Policy
+		wantClosed bool
+	}{
+		{name: "disconnect", policy: Disconnect, wantClosed: true},
+		{name: "drop newest", policy: DropNewest, wantClosed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(SendBuffer(1), OverflowPolicy(tt.policy))
+			c := &Client{hub: h, send: make(chan []byte, h.cfg.sendBuffer), done: make(chan struct{})}
+
+			if !c.deliver([]byte("first")) {
+				t.Fatal("deliver() = false on empty buffer")
+			}
+
+			if c.deliver([]byte("second")) {
+				t.Fatal("deliver() = true on full buffer")
+			}
+
+			select {
+			case <-c.done:
+				if !tt.wantClosed {
+					t.Fatal("client closed, want open")
+				}
+			default:
+				if tt.wantClosed {
+					t.Fatal("client open, want closed")
+				}
+			}
+
+			if got := string(<-c.send); got != "first" {
+				t.Fatalf("buffered message = %q, want %q", got, "first")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package wshub_test
+
+import (
+	"context"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/wshub"
+)
+
+func ExampleNew() {
+	hub := wshub.New(
+		wshub.ClientID(func(conn *websocket.Conn) string { return conn.Query("user") }),
+		wshub.OnConnect(func(c *wshub.Client) { c.Join("lobby") }),
+		wshub.OnMessage(func(c *wshub.Client, msg []byte) {
+			_ = c.Hub().BroadcastRoom(context.Background(), "lobby", msg)
+		}),
+	)
+	defer hub.Shutdown(context.Background())
+
+	app := fiber.New()
+	app.Get("/ws", hub.Handler())
+
+	// Push a notification to a single user from anywhere in the service.
+	_ = hub.Send(context.Background(), "alice", []byte(`{"type":"notification"}`))
+}
@@ -0,0 +1,154 @@
+package wshub
+
+import (
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/rdashevsky/go-pkgs/logger"
+)
+
+const (
+	_defaultSendBuffer     = 256
+	_defaultPingInterval   = 30 * time.Second
+	_defaultPongTimeout    = 60 * time.Second
+	_defaultWriteTimeout   = 10 * time.Second
+	_defaultMaxMessageSize = 64 << 10
+)
+
+// Option configures a Hub.
+type Option func(*config)
+
+type config struct {
+	onConnect    func(*Client)
+	onMessage    func(*Client, []byte)
+	onDisconnect func(*Client)
+	clientID     func(*websocket.Conn) string
+
+	sendBuffer     int
+	policy         Policy
+	pingInterval   time.Duration
+	pongTimeout    time.Duration
+	writeTimeout   time.Duration
+	maxMessageSize int64
+
+	bridge Bridge
+	logger logger.LoggerI
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		sendBuffer:     _defaultSendBuffer,
+		policy:         Disconnect,
+		pingInterval:   _defaultPingInterval,
+		pongTimeout:    _defaultPongTimeout,
+		writeTimeout:   _defaultWriteTimeout,
+		maxMessageSize: _defaultMaxMessageSize,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// OnConnect sets the callback run once a client is registered, typically to
+// join it to rooms.
+// Default is none.
+func OnConnect(fn func(c *Client)) Option {
+	return func(c *config) {
+		c.onConnect = fn
+	}
+}
+
+// OnMessage sets the callback run for every message received from a client.
+// It runs on the connection read loop, so a slow callback delays further reads.
+// Default is none, messages are discarded.
+func OnMessage(fn func(c *Client, msg []byte)) Option {
+	return func(c *config) {
+		c.onMessage = fn
+	}
+}
+
+// OnDisconnect sets the callback run after a client is unregistered.
+// Default is none.
+func OnDisconnect(fn func(c *Client)) Option {
+	return func(c *config) {
+		c.onDisconnect = fn
+	}
+}
+
+// ClientID sets the function deriving the client ID from the connection, e.g.
+// from the authenticated user stored in its locals. IDs must be unique per hub;
+// a new connection with a taken ID replaces the old one.
+// Default is a random UUIDv7.
+func ClientID(fn func(conn *websocket.Conn) string) Option {
+	return func(c *config) {
+		c.clientID = fn
+	}
+}
+
+// SendBuffer sets the number of outgoing messages buffered per client.
+// Default is 256.
+func SendBuffer(size int) Option {
+	return func(c *config) {
+		c.sendBuffer = size
+	}
+}
+
+// OverflowPolicy sets what happens when a client send buffer is full.
+// Default is Disconnect.
+func OverflowPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// PingInterval sets how often clients are pinged. It must be shorter than PongTimeout.
+// Default is 30s.
+func PingInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.pingInterval = d
+	}
+}
+
+// PongTimeout sets how long a client may stay silent before it is disconnected.
+// Default is 60s.
+func PongTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.pongTimeout = d
+	}
+}
+
+// WriteTimeout sets the deadline for writing a single message to a client.
+// Default is 10s.
+func WriteTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.writeTimeout = d
+	}
+}
+
+// MaxMessageSize sets the maximum size in bytes of a message read from a client.
+// Default is 64KiB.
+func MaxMessageSize(size int64) Option {
+	return func(c *config) {
+		c.maxMessageSize = size
+	}
+}
+
+// WithBridge relays broadcasts and targeted sends through b so that they reach
+// clients connected to other instances.
+// Default is none, messages only reach local clients.
+func WithBridge(b Bridge) Option {
+	return func(c *config) {
+		c.bridge = b
+	}
+}
+
+// WithLogger sets the logger used to report bridge and callback failures.
+// Default is nil, which disables logging.
+func WithLogger(l logger.LoggerI) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
@@ -0,0 +1,339 @@
+// Package wshub manages WebSocket clients for Fiber servers: a registry of
+// connections grouped into rooms, broadcast and targeted sends with per client
+// backpressure, heartbeats, and an optional bridge, such as Redis pub/sub,
+// so that messages reach clients connected to other instances.
+package wshub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/ids"
+)
+
+// ErrClosed is returned when sending through a hub that has been shut down.
+var ErrClosed = errors.New("wshub - hub closed")
+
+// Policy decides what happens when a client send buffer is full.
+type Policy int
+
+const (
+	// Disconnect closes slow clients so that they reconnect and resync.
+	Disconnect Policy = iota
+	// DropNewest discards the message for the slow client and keeps it connected.
+	DropNewest
+)
+
+// Hub tracks connected clients and routes messages to them.
+type Hub struct {
+	cfg    config
+	origin string
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+	rooms   map[string]map[*Client]struct{}
+	closed  bool
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// envelope is the bridge wire format.
+type envelope struct {
+	Origin string `json:"origin"`
+	Room   string `json:"room,omitempty"`
+	Client string `json:"client,omitempty"`
+	Data   []byte `json:"data"`
+}
+
+// New creates a Hub. When a bridge is configured it is subscribed to right away.
+//
+// Default configuration:
+//   - Send buffer: 256 messages per client
+//   - Overflow policy: Disconnect
+//   - Ping interval: 30s, pong timeout: 60s
+//   - Write timeout: 10s
+//   - Max message size: 64KiB
+//   - Bridge: none
+//
+// Example:
+//
+//	hub := wshub.New(
+//	    wshub.OnConnect(func(c *wshub.Client) { c.Join("lobby") }),
+//	    wshub.OnMessage(func(c *wshub.Client, msg []byte) {
+//	        _ = c.Hub().BroadcastRoom(context.Background(), "lobby", msg)
+//	    }),
+//	    wshub.WithBridge(wshub.RedisBridge(r, "chat")),
+//	)
+//	server.App.Get("/ws", hub.Handler())
+//	defer hub.Shutdown(ctx)
+func New(opts ...Option) *Hub {
+	cfg := newConfig(opts)
+
+	h := &Hub{
+		cfg:     cfg,
+		origin:  ids.NewUUID().String(),
+		clients: make(map[string]*Client),
+		rooms:   make(map[string]map[*Client]struct{}),
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+
+	if cfg.bridge != nil {
+		h.wg.Add(1)
+
+		go func() {
+			defer h.wg.Done()
+
+			if err := cfg.bridge.Subscribe(h.ctx, h.receive); err != nil && h.cfg.logger != nil {
+				h.cfg.logger.Error(err, "wshub - Hub - bridge.Subscribe")
+			}
+		}()
+	}
+
+	return h
+}
+
+// Handler returns the Fiber handler upgrading requests to WebSocket connections
+// and serving them until they close. Requests that are not WebSocket upgrades
+// get a 426 response.
+func (h *Hub) Handler(config ...websocket.Config) fiber.Handler {
+	ws := websocket.New(h.serve, config...)
+
+	return func(ctx *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(ctx) {
+			return fiber.ErrUpgradeRequired
+		}
+
+		return ws(ctx)
+	}
+}
+
+// Client returns the connected client with the given ID.
+func (h *Hub) Client(id string) (*Client, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	c, ok := h.clients[id]
+
+	return c, ok
+}
+
+// Count returns the number of clients connected to this instance.
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients)
+}
+
+// RoomSize returns the number of clients of this instance in room.
+func (h *Hub) RoomSize(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.rooms[room])
+}
+
+// Broadcast sends msg to every client.
+func (h *Hub) Broadcast(ctx context.Context, msg []byte) error {
+	return h.route(ctx, envelope{Data: msg})
+}
+
+// BroadcastRoom sends msg to every client in room.
+func (h *Hub) BroadcastRoom(ctx context.Context, room string, msg []byte) error {
+	return h.route(ctx, envelope{Room: room, Data: msg})
+}
+
+// Send sends msg to the client with the given ID, wherever it is connected.
+func (h *Hub) Send(ctx context.Context, clientID string, msg []byte) error {
+	return h.route(ctx, envelope{Client: clientID, Data: msg})
+}
+
+// Shutdown closes every client with a going away status, stops the bridge and
+// waits for connections to finish until ctx is done.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*Client, 0, len(h.clients))
+
+	for _, c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	h.cancel()
+
+	for _, c := range clients {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wshub - Hub - Shutdown: %w", ctx.Err())
+	}
+}
+
+func (h *Hub) route(ctx context.Context, e envelope) error {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	// A targeted send to a local client never needs the bridge.
+	if e.Client != "" {
+		if c, ok := h.Client(e.Client); ok {
+			c.deliver(e.Data)
+			return nil
+		}
+	} else {
+		h.deliver(e)
+	}
+
+	if h.cfg.bridge == nil {
+		return nil
+	}
+
+	e.Origin = h.origin
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("wshub - Hub - route - json.Marshal: %w", err)
+	}
+
+	if err = h.cfg.bridge.Publish(ctx, data); err != nil {
+		return fmt.Errorf("wshub - Hub - route - bridge.Publish: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Hub) receive(data []byte) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		if h.cfg.logger != nil {
+			h.cfg.logger.Error(err, "wshub - Hub - receive - json.Unmarshal")
+		}
+
+		return
+	}
+
+	if e.Origin == h.origin {
+		return
+	}
+
+	h.deliver(e)
+}
+
+// deliver hands e to the matching local clients.
+func (h *Hub) deliver(e envelope) {
+	h.mu.RLock()
+
+	var targets []*Client
+
+	switch {
+	case e.Client != "":
+		if c, ok := h.clients[e.Client]; ok {
+			targets = []*Client{c}
+		}
+	case e.Room != "":
+		targets = make([]*Client, 0, len(h.rooms[e.Room]))
+		for c := range h.rooms[e.Room] {
+			targets = append(targets, c)
+		}
+	default:
+		targets = make([]*Client, 0, len(h.clients))
+		for _, c := range h.clients {
+			targets = append(targets, c)
+		}
+	}
+
+	h.mu.RUnlock()
+
+	for _, c := range targets {
+		c.deliver(e.Data)
+	}
+}
+
+func (h *Hub) register(c *Client) bool {
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		return false
+	}
+
+	old := h.clients[c.id]
+	h.clients[c.id] = c
+	h.mu.Unlock()
+
+	if old != nil {
+		old.close(websocket.ClosePolicyViolation, "replaced by a new connection")
+	}
+
+	return true
+}
+
+func (h *Hub) unregister(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[c.id] == c {
+		delete(h.clients, c.id)
+	}
+
+	for room := range c.rooms {
+		h.leave(c, room)
+	}
+}
+
+func (h *Hub) join(c *Client, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[c.id] != c {
+		return
+	}
+
+	members, ok := h.rooms[room]
+	if !ok {
+		members = make(map[*Client]struct{})
+		h.rooms[room] = members
+	}
+
+	members[c] = struct{}{}
+	c.rooms[room] = struct{}{}
+}
+
+// leave removes c from room. The caller must hold h.mu.
+func (h *Hub) leave(c *Client, room string) {
+	delete(c.rooms, room)
+
+	members, ok := h.rooms[room]
+	if !ok {
+		return
+	}
+
+	delete(members, c)
+
+	if len(members) == 0 {
+		delete(h.rooms, room)
+	}
+}
@@ -0,0 +1,325 @@
+package wshub_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	fws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rdashevsky/go-pkgs/redis"
+	"github.com/rdashevsky/go-pkgs/wshub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve starts hub on a random port and returns the WebSocket URL.
+func serve(t *testing.T, hub *wshub.Hub) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", hub.Handler())
+
+	go func() { _ = app.Listener(ln) }()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_ = hub.Shutdown(ctx)
+		_ = app.Shutdown()
+	})
+
+	return "ws://" + ln.Addr().String() + "/ws"
+}
+
+func dial(t *testing.T, url string) *fws.Conn {
+	t.Helper()
+
+	conn, resp, err := fws.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func read(t *testing.T, conn *fws.Conn) string {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	return string(msg)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	require.Eventually(t, cond, 2*time.Second, 5*time.Millisecond)
+}
+
+// idHub returns a hub taking client IDs from the "id" query parameter.
+func idHub(opts ...wshub.Option) *wshub.Hub {
+	opts = append([]wshub.Option{
+		wshub.ClientID(func(conn *websocket.Conn) string { return conn.Query("id") }),
+	}, opts...)
+
+	return wshub.New(opts...)
+}
+
+func TestHub_Routing(t *testing.T) {
+	hub := idHub(wshub.OnConnect(func(c *wshub.Client) {
+		if c.ID() != "carol" {
+			c.Join("team")
+		}
+	}))
+	url := serve(t, hub)
+
+	alice := dial(t, url+"?id=alice")
+	bob := dial(t, url+"?id=bob")
+	carol := dial(t, url+"?id=carol")
+
+	waitFor(t, func() bool { return hub.Count() == 3 && hub.RoomSize("team") == 2 })
+
+	ctx := context.Background()
+
+	require.NoError(t, hub.Broadcast(ctx, []byte("all")))
+	assert.Equal(t, "all", read(t, alice))
+	assert.Equal(t, "all", read(t, bob))
+	assert.Equal(t, "all", read(t, carol))
+
+	require.NoError(t, hub.BroadcastRoom(ctx, "team", []byte("team")))
+	require.NoError(t, hub.Send(ctx, "carol", []byte("direct")))
+	assert.Equal(t, "team", read(t, alice))
+	assert.Equal(t, "team", read(t, bob))
+	assert.Equal(t, "direct", read(t, carol))
+
+	c, ok := hub.Client("alice")
+	require.True(t, ok)
+	assert.Equal(t, []string{"team"}, c.Rooms())
+
+	c.Leave("team")
+	assert.Equal(t, 1, hub.RoomSize("team"))
+
+	bob.Close()
+	waitFor(t, func() bool { return hub.Count() == 2 && hub.RoomSize("team") == 0 })
+}
+
+func TestHub_Callbacks(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		disconnected []string
+	)
+
+	hub := idHub(
+		wshub.OnMessage(func(c *wshub.Client, msg []byte) {
+			c.Send(append([]byte(c.ID()+": "), msg...))
+		}),
+		wshub.OnDisconnect(func(c *wshub.Client) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			disconnected = append(disconnected, c.ID())
+		}),
+	)
+	url := serve(t, hub)
+
+	conn := dial(t, url+"?id=alice")
+	require.NoError(t, conn.WriteMessage(fws.TextMessage, []byte("hi")))
+	assert.Equal(t, "alice: hi", read(t, conn))
+
+	conn.Close()
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(disconnected) == 1 && disconnected[0] == "alice"
+	})
+}
+
+func TestHub_ReplacesDuplicateID(t *testing.T) {
+	hub := idHub()
+	url := serve(t, hub)
+
+	first := dial(t, url+"?id=alice")
+	waitFor(t, func() bool { return hub.Count() == 1 })
+
+	second := dial(t, url+"?id=alice")
+
+	require.NoError(t, first.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err := first.ReadMessage()
+	assert.True(t, fws.IsCloseError(err, fws.ClosePolicyViolation), "got %v", err)
+
+	require.NoError(t, hub.Send(context.Background(), "alice", []byte("hello")))
+	assert.Equal(t, "hello", read(t, second))
+	assert.Equal(t, 1, hub.Count())
+}
+
+func TestHub_Heartbeat(t *testing.T) {
+	hub := wshub.New(wshub.PingInterval(10 * time.Millisecond))
+	url := serve(t, hub)
+
+	conn := dial(t, url)
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+
+		return nil
+	})
+
+	// Pings are only processed while reading.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no ping received")
+	}
+}
+
+func TestHub_PongTimeout(t *testing.T) {
+	hub := wshub.New(wshub.PingInterval(time.Hour), wshub.PongTimeout(50*time.Millisecond))
+	url := serve(t, hub)
+
+	dial(t, url)
+	waitFor(t, func() bool { return hub.Count() == 1 })
+	waitFor(t, func() bool { return hub.Count() == 0 })
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := wshub.New()
+	url := serve(t, hub)
+
+	conn := dial(t, url)
+	waitFor(t, func() bool { return hub.Count() == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- hub.Shutdown(ctx) }()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, fws.IsCloseError(err, fws.CloseGoingAway), "got %v", err)
+
+	require.NoError(t, <-done)
+	assert.ErrorIs(t, hub.Broadcast(context.Background(), []byte("late")), wshub.ErrClosed)
+}
+
+func TestHub_RequiresUpgrade(t *testing.T) {
+	hub := wshub.New()
+
+	app := fiber.New()
+	app.Get("/ws", hub.Handler())
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/ws", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+}
+
+// memBridge connects hubs in the same process.
+type memBridge struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+func (b *memBridge) Publish(_ context.Context, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subs {
+		ch <- data
+	}
+
+	return nil
+}
+
+func (b *memBridge) Subscribe(ctx context.Context, fn func([]byte)) error {
+	ch := make(chan []byte, 16)
+
+	b.mu.Lock()
+	b.subs = append(b.subs, ch)
+	b.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-ch:
+			fn(data)
+		}
+	}
+}
+
+func TestHub_Bridge(t *testing.T) {
+	bridge := &memBridge{}
+
+	first := idHub(wshub.WithBridge(bridge))
+	second := idHub(wshub.WithBridge(bridge))
+
+	alice := dial(t, serve(t, first)+"?id=alice")
+	bob := dial(t, serve(t, second)+"?id=bob")
+
+	waitFor(t, func() bool { return first.Count() == 1 && second.Count() == 1 })
+
+	ctx := context.Background()
+
+	require.NoError(t, first.Broadcast(ctx, []byte("everyone")))
+	assert.Equal(t, "everyone", read(t, alice))
+	assert.Equal(t, "everyone", read(t, bob))
+
+	require.NoError(t, first.Send(ctx, "bob", []byte("to bob")))
+	assert.Equal(t, "to bob", read(t, bob))
+
+	require.NoError(t, second.Send(ctx, "alice", []byte("to alice")))
+	assert.Equal(t, "to alice", read(t, alice))
+}
+
+func TestRedisBridge_NoConnection(t *testing.T) {
+	r, err := redis.New("127.0.0.1:65432", "", "")
+	require.NoError(t, err)
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bridge := wshub.RedisBridge(r, "")
+
+	if err := bridge.Publish(ctx, []byte("x")); err == nil {
+		t.Skip("unexpected successful connection to Redis")
+	}
+
+	assert.Error(t, bridge.Subscribe(ctx, func([]byte) {}))
+
+	// Broadcasts still reach local clients and report the bridge failure.
+	hub := wshub.New(wshub.WithBridge(bridge))
+	assert.Error(t, hub.Broadcast(ctx, []byte("x")))
+}